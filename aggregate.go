@@ -0,0 +1,191 @@
+package kallax
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Aggregate is an aggregate function of a column, such as the sum of its
+// values, computed for every group of rows of a query. It is a SchemaField,
+// so it can be used in the conditions given to Having and in the orders of
+// the query.
+type Aggregate struct {
+	fn  string
+	col SchemaField
+}
+
+// Count returns an aggregate that counts the rows of a group in which the
+// given column is not null.
+func Count(col SchemaField) *Aggregate {
+	return &Aggregate{"COUNT", col}
+}
+
+// CountAll returns an aggregate that counts all the rows of a group.
+func CountAll() *Aggregate {
+	return &Aggregate{"COUNT", nil}
+}
+
+// Sum returns an aggregate with the sum of the values of the given column in
+// a group.
+func Sum(col SchemaField) *Aggregate {
+	return &Aggregate{"SUM", col}
+}
+
+// Avg returns an aggregate with the average of the values of the given column
+// in a group.
+func Avg(col SchemaField) *Aggregate {
+	return &Aggregate{"AVG", col}
+}
+
+// Min returns an aggregate with the minimum value of the given column in a
+// group.
+func Min(col SchemaField) *Aggregate {
+	return &Aggregate{"MIN", col}
+}
+
+// Max returns an aggregate with the maximum value of the given column in a
+// group.
+func Max(col SchemaField) *Aggregate {
+	return &Aggregate{"MAX", col}
+}
+
+func (*Aggregate) isSchemaField() {}
+
+// String returns the aggregate function with the name of the column.
+func (a *Aggregate) String() string {
+	if a.col == nil {
+		return fmt.Sprintf("%s(*)", a.fn)
+	}
+	return fmt.Sprintf("%s(%s)", a.fn, a.col)
+}
+
+// QualifiedName returns the aggregate function with the name of the column
+// qualified by the alias of the given schema.
+func (a *Aggregate) QualifiedName(schema Schema) string {
+	if a.col == nil {
+		return a.String()
+	}
+	return fmt.Sprintf("%s(%s)", a.fn, a.col.QualifiedName(schema))
+}
+
+// AggregateValues are the values of the aggregates computed for a group.
+type AggregateValues struct {
+	aggregates []*Aggregate
+	values     []interface{}
+}
+
+// Value returns the value of the given aggregate, as it's retrieved from the
+// database, or nil if the aggregate was not computed or its value is null.
+func (v AggregateValues) Value(a *Aggregate) interface{} {
+	for i, agg := range v.aggregates {
+		if agg == a {
+			if b, ok := v.values[i].([]byte); ok {
+				return string(b)
+			}
+			return v.values[i]
+		}
+	}
+	return nil
+}
+
+// Int64 returns the value of the given aggregate as an int64. It returns 0 if
+// the value is null or it's not a number.
+func (v AggregateValues) Int64(a *Aggregate) int64 {
+	switch val := v.Value(a).(type) {
+	case int64:
+		return val
+	case float64:
+		return int64(val)
+	case string:
+		if n, err := strconv.ParseInt(val, 10, 64); err == nil {
+			return n
+		}
+		n, _ := strconv.ParseFloat(val, 64)
+		return int64(n)
+	}
+	return 0
+}
+
+// Float64 returns the value of the given aggregate as a float64. It returns
+// 0 if the value is null or it's not a number.
+func (v AggregateValues) Float64(a *Aggregate) float64 {
+	switch val := v.Value(a).(type) {
+	case int64:
+		return float64(val)
+	case float64:
+		return val
+	case string:
+		n, _ := strconv.ParseFloat(val, 64)
+		return n
+	}
+	return 0
+}
+
+// AggregateRow is a group of the rows retrieved by an aggregation query, with
+// the values of the columns the rows are grouped by set in the record and the
+// values of the aggregates of the group.
+type AggregateRow struct {
+	Record Record
+	AggregateValues
+}
+
+// Aggregate performs the given query computing the given aggregates for every
+// group of the rows selected by it, which are grouped by the columns given to
+// the GroupBy method of the query. Only the values of those columns are set
+// in the records of the returned rows.
+func (s *Store) Aggregate(q Query, aggregates ...*Aggregate) ([]*AggregateRow, error) {
+	schema := q.Schema()
+	groupBy := q.getGroupBy()
+	_, queryBuilder := s.queryScopes().compile(q)
+	builder := aggregateBuilder(queryBuilder)
+	for _, col := range groupBy {
+		builder = builder.Column(col.QualifiedName(schema))
+	}
+	for _, a := range aggregates {
+		builder = builder.Column(a.QualifiedName(schema))
+	}
+
+	if offset := q.GetOffset(); offset > 0 {
+		builder = builder.Offset(offset)
+	}
+
+	if limit := q.GetLimit(); limit > 0 {
+		builder = builder.Limit(limit)
+	}
+
+	rows, err := s.query(q, builder)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*AggregateRow
+	for rows.Next() {
+		row := &AggregateRow{
+			Record: schema.New(),
+			AggregateValues: AggregateValues{
+				aggregates: aggregates,
+				values:     make([]interface{}, len(aggregates)),
+			},
+		}
+
+		var pointers = make([]interface{}, 0, len(groupBy)+len(aggregates))
+		for _, col := range groupBy {
+			ptr, err := row.Record.ColumnAddress(col.String())
+			if err != nil {
+				return nil, err
+			}
+			pointers = append(pointers, ptr)
+		}
+		for i := range aggregates {
+			pointers = append(pointers, &row.values[i])
+		}
+
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		result = append(result, row)
+	}
+
+	return result, rows.Err()
+}
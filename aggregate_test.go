@@ -0,0 +1,56 @@
+package kallax
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregate(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	count, sum := CountAll(), Sum(f("age"))
+	q := NewBaseQuery(ModelSchema)
+	q.Where(Gt(f("age"), 18))
+	q.GroupBy(f("name"))
+	q.Having(Gt(sum, 100))
+	q.Order(Desc(count))
+	q.Limit(10)
+
+	recordedQueries = nil
+	rows, err := NewStore(db).Aggregate(q, count, sum)
+	r.NoError(err)
+	r.Len(rows, 0)
+	r.Equal([]string{
+		"SELECT __model.name, COUNT(*), SUM(__model.age) FROM model __model WHERE __model.age > $1 GROUP BY __model.name HAVING SUM(__model.age) > $2 ORDER BY COUNT(*) DESC LIMIT 10",
+	}, recordedQueries)
+
+	r.Equal([]SchemaField{f("name")}, q.Copy().getGroupBy())
+}
+
+func TestAggregateValues(t *testing.T) {
+	r := require.New(t)
+	count, avg, max, min := CountAll(), Avg(f("age")), Max(f("name")), Min(f("age"))
+	values := AggregateValues{
+		[]*Aggregate{count, avg, max, min},
+		[]interface{}{int64(3), []byte("20.5"), []byte("foo"), nil},
+	}
+
+	r.Equal(int64(3), values.Int64(count))
+	r.Equal(float64(3), values.Float64(count))
+	r.Equal(20.5, values.Float64(avg))
+	r.Equal(int64(20), values.Int64(avg))
+	r.Equal("foo", values.Value(max))
+	r.Equal(int64(0), values.Int64(max))
+	r.Nil(values.Value(min))
+	r.Equal(int64(0), values.Int64(min))
+	r.Nil(values.Value(Sum(f("age"))))
+
+	r.Equal("COUNT(*)", count.String())
+	r.Equal("AVG(age)", avg.String())
+	r.Equal("MAX(__model.name)", max.QualifiedName(ModelSchema))
+}
@@ -0,0 +1,195 @@
+package kallax
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// AuditActorSetting is the setting of PostgreSQL the audit triggers read the
+// actor of the changes from. It's set for the transactions run with AuditAs.
+const AuditActorSetting = "kallax.actor"
+
+// AuditOperation is the kind of change recorded in an audit table.
+type AuditOperation string
+
+const (
+	// AuditInsert is the operation of the inserted records.
+	AuditInsert AuditOperation = "INSERT"
+	// AuditUpdate is the operation of the updated records.
+	AuditUpdate AuditOperation = "UPDATE"
+	// AuditDelete is the operation of the deleted records.
+	AuditDelete AuditOperation = "DELETE"
+)
+
+// AuditTable returns the name of the audit table of the given schema, which
+// is the name of its table followed by "_audit".
+func AuditTable(schema Schema) string {
+	return schema.Table() + "_audit"
+}
+
+// AuditEntry is a change of a record of a model with the `audit:"true"` tag
+// in its kallax.Model field, written to its audit table by the triggers
+// generated with its migrations.
+type AuditEntry struct {
+	// ID is the identifier of the entry, which grows with each change.
+	ID int64
+	// RecordID is the text representation of the primary key of the changed
+	// record.
+	RecordID string
+	// Operation is the kind of change.
+	Operation AuditOperation
+	// Old is the row before the change, as a JSON object with its columns.
+	// It's empty for inserts.
+	Old json.RawMessage
+	// New is the row after the change, as a JSON object with its columns.
+	// It's empty for deletes.
+	New json.RawMessage
+	// Actor is the actor the change was made by, set with AuditAs. It's
+	// empty if it was not set.
+	Actor string
+	// ChangedAt is the time of the change.
+	ChangedAt time.Time
+}
+
+// AuditQuery is a query over the entries of an audit table. By default, it
+// returns all the entries, with the latest first.
+type AuditQuery struct {
+	table  string
+	where  squirrel.And
+	limit  uint64
+	oldest bool
+}
+
+// NewAuditQuery returns a query over the audit table of the given schema.
+func NewAuditQuery(schema Schema) *AuditQuery {
+	return &AuditQuery{table: AuditTable(schema)}
+}
+
+// ForRecord restricts the query to the changes of the record with the given
+// primary key.
+func (q *AuditQuery) ForRecord(id interface{}) *AuditQuery {
+	q.where = append(q.where, squirrel.Expr("record_id = ?", id))
+	return q
+}
+
+// ByActor restricts the query to the changes made by the given actor.
+func (q *AuditQuery) ByActor(actor string) *AuditQuery {
+	q.where = append(q.where, squirrel.Eq{"actor": actor})
+	return q
+}
+
+// Operation restricts the query to the changes of the given kinds.
+func (q *AuditQuery) Operation(ops ...AuditOperation) *AuditQuery {
+	values := make([]string, len(ops))
+	for i, op := range ops {
+		values[i] = string(op)
+	}
+	q.where = append(q.where, squirrel.Eq{"operation": values})
+	return q
+}
+
+// Since restricts the query to the changes made at or after the given time.
+func (q *AuditQuery) Since(t time.Time) *AuditQuery {
+	q.where = append(q.where, squirrel.GtOrEq{"changed_at": t})
+	return q
+}
+
+// Until restricts the query to the changes made before the given time.
+func (q *AuditQuery) Until(t time.Time) *AuditQuery {
+	q.where = append(q.where, squirrel.Lt{"changed_at": t})
+	return q
+}
+
+// Oldest makes the query return the oldest entries first.
+func (q *AuditQuery) Oldest() *AuditQuery {
+	q.oldest = true
+	return q
+}
+
+// Limit sets the maximum number of entries returned by the query. A limit
+// of 0 returns all of them.
+func (q *AuditQuery) Limit(n uint64) *AuditQuery {
+	q.limit = n
+	return q
+}
+
+// ToSql returns the statement of the query and its arguments.
+func (q *AuditQuery) ToSql() (string, []interface{}, error) {
+	b := squirrel.StatementBuilder.
+		PlaceholderFormat(squirrel.Dollar).
+		Select("id", "record_id::text", "operation", "old_row", "new_row", "actor", "changed_at").
+		From(q.table)
+	if len(q.where) > 0 {
+		b = b.Where(q.where)
+	}
+
+	if q.oldest {
+		b = b.OrderBy("id ASC")
+	} else {
+		b = b.OrderBy("id DESC")
+	}
+
+	if q.limit > 0 {
+		b = b.Limit(q.limit)
+	}
+	return b.ToSql()
+}
+
+// FindAudits returns the entries of the audit table matched by the given
+// query.
+func (s *Store) FindAudits(q *AuditQuery) ([]*AuditEntry, error) {
+	query, args, err := q.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.runner.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*AuditEntry
+	for rows.Next() {
+		var (
+			e              AuditEntry
+			op             string
+			oldRow, newRow []byte
+			actor          *string
+		)
+		if err := rows.Scan(&e.ID, &e.RecordID, &op, &oldRow, &newRow, &actor, &e.ChangedAt); err != nil {
+			return nil, err
+		}
+
+		e.Operation = AuditOperation(op)
+		e.Old, e.New = oldRow, newRow
+		if actor != nil {
+			e.Actor = *actor
+		}
+
+		if s.loc != nil {
+			e.ChangedAt = e.ChangedAt.In(s.loc)
+		}
+		entries = append(entries, &e)
+	}
+	return entries, rows.Err()
+}
+
+// AuditAs runs the given callback in a transaction whose changes are recorded
+// in the audit tables as made by the given actor. If the store is already
+// holding a transaction, the actor is set for the rest of it.
+func (s *Store) AuditAs(actor string, callback func(*Store) error) error {
+	if callback == nil {
+		return ErrInvalidTxCallback
+	}
+
+	return s.Transaction(func(s *Store) error {
+		if _, err := s.runner.Exec("SELECT set_config($1, $2, true)", AuditActorSetting, actor); err != nil {
+			return fmt.Errorf("kallax: unable to set the audit actor: %s", err)
+		}
+		return callback(s)
+	})
+}
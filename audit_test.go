@@ -0,0 +1,52 @@
+package kallax
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditQuery(t *testing.T) {
+	require := require.New(t)
+	require.Equal("model_audit", AuditTable(ModelSchema))
+
+	query, args, err := NewAuditQuery(ModelSchema).ToSql()
+	require.NoError(err)
+	require.Equal("SELECT id, record_id::text, operation, old_row, new_row, actor, changed_at FROM model_audit ORDER BY id DESC", query)
+	require.Empty(args)
+
+	since := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	query, args, err = NewAuditQuery(ModelSchema).
+		ForRecord(int64(1)).
+		ByActor("alice").
+		Operation(AuditUpdate, AuditDelete).
+		Since(since).
+		Oldest().
+		Limit(10).
+		ToSql()
+	require.NoError(err)
+	require.Equal("SELECT id, record_id::text, operation, old_row, new_row, actor, changed_at FROM model_audit WHERE (record_id = $1 AND actor = $2 AND operation IN ($3,$4) AND changed_at >= $5) ORDER BY id ASC LIMIT 10", query)
+	require.Equal([]interface{}{int64(1), "alice", "UPDATE", "DELETE", since}, args)
+}
+
+func TestAuditAs(t *testing.T) {
+	db, err := sql.Open("kallax_recording", "")
+	require.NoError(t, err)
+	defer db.Close()
+
+	recordedQueries = nil
+	var called bool
+	err = NewStore(db).AuditAs("alice", func(s *Store) error {
+		_, ok := s.db.(*txRunner)
+		require.True(t, ok)
+		called = true
+		return nil
+	})
+	require.NoError(t, err)
+	require.True(t, called)
+	require.Equal(t, []string{"SELECT set_config($1, $2, true)"}, recordedQueries)
+
+	require.Equal(t, ErrInvalidTxCallback, NewStore(db).AuditAs("alice", nil))
+}
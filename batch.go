@@ -0,0 +1,607 @@
+package kallax
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrEmptyBatch is returned when a batch without statements is flushed.
+var ErrEmptyBatch = errors.New("kallax: batch has no statements to flush")
+
+// Batch is a queue of inserts, updates and deletes of records of any model,
+// which are sent to the database in a single round trip when the batch is
+// flushed. All of them are run as a single statement, with data-modifying
+// WITH queries, so either all of them are applied or none is. As every
+// statement of a batch sees the database as it was before the batch, a
+// batch can not update or delete the records it inserts, nor change the
+// same record twice. Batches can only be flushed with the dialects
+// supporting data-modifying WITH queries.
+//
+// The values of the records are taken when their statements are queued,
+// and no events are fired for them.
+type Batch struct {
+	store *Store
+	ops   []*batchOp
+}
+
+type batchOpKind int
+
+const (
+	batchInsert batchOpKind = iota
+	batchUpdate
+	batchDelete
+)
+
+// batchOp is a statement queued in a batch.
+type batchOp struct {
+	kind   batchOpKind
+	schema Schema
+	record Record
+	cols   []string
+	query  string
+	args   []interface{}
+	// deletedAt is the time a soft deleted record is deleted at.
+	deletedAt time.Time
+	// version is the version an optimistically locked record had when its
+	// update was queued.
+	version int64
+	// returning are the columns returned by the statement, which are scanned
+	// into the record.
+	returning []string
+	// returned are the values of the returned columns of an update, which
+	// are only scanned into the record if it was updated.
+	returned []interface{}
+}
+
+// NewBatch returns a new empty batch that is flushed with the store.
+func (s *Store) NewBatch() *Batch {
+	return &Batch{store: s}
+}
+
+// Len returns the number of queued statements in the batch.
+func (b *Batch) Len() int {
+	return len(b.ops)
+}
+
+// Insert queues the insert of the given record in the table of the given
+// schema. The auto-incrementable primary key of the record is set when the
+// batch is flushed.
+func (b *Batch) Insert(schema Schema, record Record) error {
+	if record.IsPersisted() {
+		return ErrNonNewDocument
+	}
+
+	if err := b.store.bindTenant(schema, record); err != nil {
+		return err
+	}
+
+	returnedCols, err := b.store.insertReturningColumns(schema, record)
+	if err != nil {
+		return err
+	}
+
+	query, args, err := insertStatement(schema, record, true)
+	if err != nil {
+		return err
+	}
+
+	b.queue(&batchOp{
+		kind:      batchInsert,
+		schema:    schema,
+		record:    record,
+		cols:      ColumnNames(schema.Columns()),
+		query:     appendReturning(query, schema.isPrimaryKeyAutoIncrementable(), returnedCols),
+		args:      args,
+		returning: returnedCols,
+	})
+	return nil
+}
+
+// Update queues the update of the given columns of the given record, or all
+// of them if no columns are given. Flush returns ErrNoRowUpdate if the
+// record does not exist, or ErrStaleObject if its updates are optimistically
+// locked and its version is not the one in the database.
+func (b *Batch) Update(schema Schema, record Record, cols ...SchemaField) error {
+	if !record.IsWritable() {
+		return ErrNotWritable
+	}
+
+	if !record.IsPersisted() {
+		return ErrNewDocument
+	}
+
+	if record.GetID().IsEmpty() {
+		return ErrEmptyID
+	}
+
+	if err := b.store.bindTenant(schema, record); err != nil {
+		return err
+	}
+
+	cols, err := updatedColumns(schema, record, cols)
+	if err != nil {
+		return err
+	}
+
+	returnedCols, err := b.store.returningColumns(schema)
+	if err != nil {
+		return err
+	}
+
+	query, args, err := UpdateStatement(schema, record, cols...)
+	if err != nil {
+		return err
+	}
+	query, args = b.store.tenantStatement(schema, query, args)
+
+	op := &batchOp{
+		kind:      batchUpdate,
+		schema:    schema,
+		record:    record,
+		cols:      append(ColumnNames(cols), returnedCols...),
+		query:     appendReturning(query+" RETURNING 1", true, returnedCols),
+		args:      args,
+		returning: returnedCols,
+	}
+	if lock := schema.LockField(); lock != nil {
+		if op.version, err = lockVersion(schema, record, lock); err != nil {
+			return err
+		}
+		op.cols = append(op.cols, lock.String())
+	}
+
+	b.queue(op)
+	return nil
+}
+
+// Delete queues the delete of the given record. If the records of the schema
+// are soft deleted, its soft delete column is set instead, as with
+// Store.Delete, and it is set in the record when the batch is flushed.
+func (b *Batch) Delete(schema Schema, record Record) error {
+	if record.GetID().IsEmpty() {
+		return ErrEmptyID
+	}
+
+	op := &batchOp{
+		kind:   batchDelete,
+		schema: schema,
+		record: record,
+	}
+	if col := schema.SoftDeleteField(); col != nil {
+		op.deletedAt = b.store.deletionTime()
+		op.query, op.args = softDeleteStatement(schema, record, col, op.deletedAt)
+	} else {
+		op.query, op.args = DeleteStatement(schema, record)
+	}
+	op.query, op.args = b.store.tenantStatement(schema, op.query, op.args)
+
+	b.queue(op)
+	return nil
+}
+
+func (b *Batch) queue(op *batchOp) {
+	if b.store.loc != nil {
+		valuesInLocation(op.args, b.store.loc)
+	}
+	b.ops = append(b.ops, op)
+}
+
+// placeholderRegex matches the placeholders of the statements of a batch,
+// which contain no literals.
+var placeholderRegex = regexp.MustCompile(`\$(\d+)`)
+
+// ToSql returns the statement that flushes the batch and its arguments.
+func (b *Batch) ToSql() (string, []interface{}, error) {
+	if len(b.ops) == 0 {
+		return "", nil, ErrEmptyBatch
+	}
+
+	var (
+		query   bytes.Buffer
+		args    []interface{}
+		results []string
+	)
+
+	query.WriteString("WITH ")
+	for i, op := range b.ops {
+		if i > 0 {
+			query.WriteString(", ")
+		}
+
+		offset := len(args)
+		stmt := placeholderRegex.ReplaceAllStringFunc(op.query, func(p string) string {
+			n, _ := strconv.Atoi(p[1:])
+			return fmt.Sprintf("$%d", n+offset)
+		})
+		fmt.Fprintf(&query, "q%d AS (%s)", i+1, stmt)
+		args = append(args, op.args...)
+
+		switch {
+		case op.kind == batchUpdate:
+			results = append(results, fmt.Sprintf("(SELECT COUNT(*) FROM q%d)", i+1))
+		case op.kind == batchInsert && op.schema.isPrimaryKeyAutoIncrementable():
+			results = append(results, fmt.Sprintf("(SELECT %s FROM q%d)", op.schema.ID(), i+1))
+		}
+
+		for _, col := range op.returning {
+			results = append(results, fmt.Sprintf("(SELECT %s FROM q%d)", col, i+1))
+		}
+	}
+
+	if len(results) == 0 {
+		results = append(results, "1")
+	}
+
+	query.WriteString(" SELECT ")
+	for i, r := range results {
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		query.WriteString(r)
+	}
+	return query.String(), args, nil
+}
+
+// Flush runs all the queued statements of the batch in a single round trip
+// and empties it. The primary keys of the inserted records are set, and the
+// inserted and updated records are marked as persisted. If any of the
+// updated records does not exist, ErrNoRowUpdate is returned after the rest
+// of the statements have been applied, and so is ErrStaleObject if any of
+// the optimistically locked ones is stale. The versions of the updated
+// optimistically locked records are incremented.
+func (b *Batch) Flush() error {
+	if d := b.store.Dialect(); !d.Supports(FeatureWritableCTEs) {
+		return &UnsupportedError{Dialect: d.Name(), Feature: FeatureWritableCTEs}
+	}
+
+	query, args, err := b.ToSql()
+	if err != nil {
+		return err
+	}
+
+	var (
+		pointers []interface{}
+		counts   = make(map[*batchOp]*int64)
+	)
+	for _, op := range b.ops {
+		switch op.kind {
+		case batchUpdate:
+			counts[op] = new(int64)
+			pointers = append(pointers, counts[op])
+			op.returned = make([]interface{}, len(op.returning))
+			for i := range op.returned {
+				op.returned[i] = new(interface{})
+			}
+			pointers = append(pointers, op.returned...)
+		case batchInsert:
+			addrs, err := returnedAddresses(op.schema, op.record, op.schema.isPrimaryKeyAutoIncrementable(), op.returning)
+			if err != nil {
+				return err
+			}
+			pointers = append(pointers, addrs...)
+		}
+	}
+
+	if len(pointers) == 0 {
+		pointers = append(pointers, new(int64))
+	}
+
+	rows, err := b.store.runner.Query(query, args...)
+	if err != nil {
+		return err
+	}
+
+	if rows.Next() {
+		err = rows.Scan(pointers...)
+	} else {
+		err = rows.Err()
+	}
+	rows.Close()
+	if err != nil {
+		return err
+	}
+
+	ops := b.ops
+	b.ops = nil
+
+	var tables []string
+	for _, op := range ops {
+		tables = append(tables, op.schema.Table())
+		switch op.kind {
+		case batchInsert:
+			op.record.setWritable(true)
+			op.record.setPersisted()
+			snapshot(op.record, op.cols, true)
+		case batchUpdate:
+			lock := op.schema.LockField()
+			if *counts[op] == 0 {
+				if lock != nil {
+					err = ErrStaleObject
+				} else {
+					err = ErrNoRowUpdate
+				}
+				continue
+			}
+
+			if lock != nil {
+				if e := setLockVersion(op.schema, op.record, lock, op.version+1); e != nil {
+					err = e
+				}
+			}
+
+			for i, col := range op.returning {
+				if e := scanColumnValue(op.record, col, *op.returned[i].(*interface{})); e != nil {
+					err = e
+				}
+			}
+			snapshot(op.record, op.cols, false)
+		case batchDelete:
+			if col := op.schema.SoftDeleteField(); col != nil {
+				if e := setDeletionTime(op.schema, op.record, col, op.deletedAt); e != nil {
+					err = e
+				}
+			}
+		}
+	}
+	b.store.invalidate(tables...)
+	return err
+}
+
+// ErrBatchInsertColumns is returned by BatchInsert when the records to
+// insert do not have the same columns, such as when only some of them have
+// a virtual column.
+var ErrBatchInsertColumns = errors.New("kallax: all the records inserted in a batch must have the same columns")
+
+// BatchInsertOptions are the options to insert records with BatchInsert.
+type BatchInsertOptions struct {
+	// BatchSize is the maximum number of records inserted by every INSERT
+	// statement. If it is zero, every statement inserts as many records as
+	// fit in the maximum number of parameters of a statement.
+	BatchSize int
+	// CopyThreshold is the number of records from which they are inserted
+	// with a COPY statement instead, which is faster for many records. It is
+	// only used with the dialects supporting COPY statements and if the
+	// primary key is not auto-incrementable, as COPY statements can not
+	// return the generated keys. If it is zero, COPY statements are never
+	// used.
+	CopyThreshold int
+}
+
+// maxStatementParams is the maximum number of parameters of a statement in
+// PostgreSQL and MySQL.
+const maxStatementParams = 65535
+
+// BatchInsert inserts the given records in the table of the given schema with
+// multi-row INSERT statements, so a single round trip is made for every
+// batch of records instead of one for every record. If more than one
+// statement is needed, all of them are run in a transaction. The
+// auto-incrementable primary keys of the records are set to the ones of
+// their rows. All the records must be new and have the same columns, which
+// include the columns with a default value even if they are zero. No events
+// are fired for them, and their relationships are not inserted.
+func (s *Store) BatchInsert(schema Schema, records []Record, opts BatchInsertOptions) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	for _, r := range records {
+		if err := s.bindTenant(schema, r); err != nil {
+			return err
+		}
+	}
+
+	returnedCols, err := s.returningColumns(schema)
+	if err != nil {
+		return err
+	}
+
+	cols, values, err := batchInsertValues(schema, records)
+	if err != nil {
+		return err
+	}
+
+	if s.loc != nil {
+		for _, v := range values {
+			valuesInLocation(v, s.loc)
+		}
+	}
+
+	size := opts.BatchSize
+	if max := maxStatementParams / len(cols); size <= 0 || size > max {
+		size = max
+	}
+
+	useCopy := opts.CopyThreshold > 0 && len(records) >= opts.CopyThreshold &&
+		s.Dialect().Supports(FeatureCopy) && s.Driver().Supports(FeatureCopy) &&
+		!schema.isPrimaryKeyAutoIncrementable() && len(returnedCols) == 0
+	insert := func(s *Store) error {
+		if useCopy {
+			return s.copyValues(schema.Table(), cols, values)
+		}
+
+		for i := 0; i < len(records); i += size {
+			end := i + size
+			if end > len(records) {
+				end = len(records)
+			}
+
+			if err := s.insertValues(schema, records[i:end], cols, values[i:end], returnedCols); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if useCopy || len(records) > size {
+		err = s.Transaction(insert)
+	} else {
+		err = insert(s)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		record.setWritable(true)
+		record.setPersisted()
+		snapshot(record, ColumnNames(schema.Columns()), true)
+	}
+	s.invalidate(schema.Table())
+	s.emit(ChangeInsert, schema, records...)
+	return nil
+}
+
+// batchInsertValues returns the columns inserted for the given records and
+// their values, which are the ones of the first record.
+func batchInsertValues(schema Schema, records []Record) ([]string, [][]interface{}, error) {
+	cols := ColumnNames(schema.Columns())
+	if schema.isPrimaryKeyAutoIncrementable() {
+		// the pk is always the first column
+		cols = cols[1:]
+	}
+
+	values := make([][]interface{}, len(records))
+	for i, record := range records {
+		if record.IsPersisted() {
+			return nil, nil, ErrNonNewDocument
+		}
+
+		vals, recordCols, err := RecordValues(record, cols...)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		virtualCols, virtualVals := virtualColumns(record, recordCols)
+		if i == 0 {
+			cols = append(recordCols, virtualCols...)
+			vals = append(vals, virtualVals...)
+			if len(cols) == 0 {
+				return nil, nil, ErrNoColumns
+			}
+		} else if len(recordCols) != len(cols) || len(virtualCols) > 0 {
+			return nil, nil, ErrBatchInsertColumns
+		}
+		values[i] = vals
+	}
+	return cols, values, nil
+}
+
+// insertValues inserts the given values of the given records with a single
+// INSERT statement and sets their auto-incrementable primary keys and the
+// given returned columns.
+func (s *Store) insertValues(schema Schema, records []Record, cols []string, values [][]interface{}, returnedCols []string) error {
+	var (
+		query bytes.Buffer
+		args  []interface{}
+	)
+	fmt.Fprintf(&query, "INSERT INTO %s (%s) VALUES ", schema.Table(), strings.Join(cols, ","))
+	for i, vals := range values {
+		if i > 0 {
+			query.WriteRune(',')
+		}
+
+		query.WriteRune('(')
+		for j, v := range vals {
+			if j > 0 {
+				query.WriteRune(',')
+			}
+			args = append(args, v)
+			fmt.Fprintf(&query, "$%d", len(args))
+		}
+		query.WriteRune(')')
+	}
+
+	autoincr := schema.isPrimaryKeyAutoIncrementable()
+	if !autoincr && len(returnedCols) == 0 {
+		_, err := s.runner.Exec(query.String(), args...)
+		return err
+	}
+
+	addrs := make([][]interface{}, len(records))
+	for i, record := range records {
+		var err error
+		if addrs[i], err = returnedAddresses(schema, record, autoincr, returnedCols); err != nil {
+			return err
+		}
+	}
+
+	if !s.Dialect().Supports(FeatureReturning) {
+		pks := make([]interface{}, len(records))
+		for i := range addrs {
+			pks[i] = addrs[i][0]
+		}
+		return s.insertLastIDs(query.String(), args, pks)
+	}
+
+	if autoincr {
+		fmt.Fprintf(&query, " RETURNING %s", schema.ID())
+	}
+	rows, err := s.runner.Query(appendReturning(query.String(), autoincr, returnedCols), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for _, dests := range addrs {
+		if !rows.Next() {
+			if err := rows.Err(); err != nil {
+				return err
+			}
+			return fmt.Errorf("kallax: the batch insert did not return the ids of all the %d records", len(records))
+		}
+
+		if err := rows.Scan(dests...); err != nil {
+			return err
+		}
+	}
+	return rows.Close()
+}
+
+// insertLastIDs runs the given multi-row insert statement and sets the given
+// primary keys to the consecutive IDs starting at the ID of the first
+// inserted row reported by the database, for the dialects that do not
+// support returning them from the statement.
+func (s *Store) insertLastIDs(query string, args []interface{}, pks []interface{}) error {
+	result, err := s.runner.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	for i, pk := range pks {
+		if err := setLastInsertID(pk, id+int64(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyValues inserts the given values in the given table with a COPY
+// statement. It must be run in a transaction.
+func (s *Store) copyValues(table string, cols []string, values [][]interface{}) error {
+	stmt, err := s.db.PrepareContext(s.Context(), copyStatement(table, cols))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, vals := range values {
+		if _, err := stmt.ExecContext(s.Context(), vals...); err != nil {
+			return err
+		}
+	}
+
+	if _, err := stmt.ExecContext(s.Context()); err != nil {
+		return err
+	}
+	return stmt.Close()
+}
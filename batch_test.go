@@ -0,0 +1,142 @@
+package kallax
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatch_ToSql(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	b := NewStore(db).NewBatch()
+	_, _, err = b.ToSql()
+	r.Equal(ErrEmptyBatch, err)
+
+	inserted := newModel("foo", "foo@bar.baz", 1)
+	r.NoError(b.Insert(ModelSchema, inserted))
+
+	updated := newModel("bar", "bar@bar.baz", 2)
+	updated.ID = 2
+	updated.setPersisted()
+	updated.setWritable(true)
+	r.NoError(b.Update(ModelSchema, updated, f("name")))
+
+	deleted := newModel("baz", "baz@bar.baz", 3)
+	deleted.ID = 3
+	r.NoError(b.Delete(ModelSchema, deleted))
+	r.Equal(3, b.Len())
+
+	query, args, err := b.ToSql()
+	r.NoError(err)
+	r.Equal("WITH q1 AS (INSERT INTO model (name,email,age) VALUES ($1,$2,$3) RETURNING id), "+
+		"q2 AS (UPDATE model SET name=$4 WHERE id=$5 RETURNING 1), "+
+		"q3 AS (DELETE FROM model WHERE id=$6) "+
+		"SELECT (SELECT id FROM q1), (SELECT COUNT(*) FROM q2)", query)
+	r.Len(args, 6)
+	r.Equal([]interface{}{"foo", "foo@bar.baz", 1, "bar"}, args[:4])
+}
+
+func TestBatch_Errors(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	b := NewStore(db).NewBatch()
+	m := newModel("foo", "foo@bar.baz", 1)
+	r.Equal(ErrNewDocument, b.Update(ModelSchema, m))
+	r.Equal(ErrEmptyID, b.Delete(ModelSchema, m))
+
+	m.setPersisted()
+	r.Equal(ErrNonNewDocument, b.Insert(ModelSchema, m))
+	r.Equal(ErrEmptyBatch, b.Flush())
+
+	m = newModel("foo", "foo@bar.baz", 1)
+	b = NewStore(db).WithDialect(SQLite).NewBatch()
+	r.NoError(b.Insert(ModelSchema, m))
+	r.EqualError(b.Flush(), "kallax: data-modifying WITH queries are not supported by the sqlite dialect")
+}
+
+func TestBatch_Flush(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	recordedQueries = nil
+	b := NewStore(db).NewBatch()
+	inserted := newModel("foo", "foo@bar.baz", 1)
+	r.NoError(b.Insert(ModelSchema, inserted))
+
+	updated := newModel("bar", "bar@bar.baz", 2)
+	updated.ID = 2
+	updated.setPersisted()
+	updated.setWritable(true)
+	r.NoError(b.Update(ModelSchema, updated))
+
+	// the recording driver reports no updated rows
+	r.Equal(ErrNoRowUpdate, b.Flush())
+	r.Len(recordedQueries, 1)
+	r.Equal(0, b.Len())
+	r.True(inserted.IsPersisted())
+	r.True(inserted.IsWritable())
+}
+
+func TestStore_BatchInsert(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	recordedQueries = nil
+	lastInsertID = 10
+	defer func() { lastInsertID = 0 }()
+	records := []Record{
+		newModel("foo", "foo@bar.baz", 1),
+		newModel("bar", "bar@bar.baz", 2),
+		newModel("baz", "baz@bar.baz", 3),
+	}
+	r.NoError(NewStore(db).WithDialect(MySQL).BatchInsert(ModelSchema, records, BatchInsertOptions{BatchSize: 2}))
+	// the ids of every statement start at the last insert id
+	for i, id := range []int64{10, 11, 10} {
+		r.True(records[i].IsPersisted())
+		r.Equal(id, records[i].(*model).ID)
+	}
+	r.Equal([]string{
+		"INSERT INTO model (name,email,age) VALUES (?,?,?),(?,?,?)",
+		"INSERT INTO model (name,email,age) VALUES (?,?,?)",
+	}, recordedQueries)
+	r.Equal(ErrNonNewDocument, NewStore(db).BatchInsert(ModelSchema, records, BatchInsertOptions{}))
+
+	recordedQueries = nil
+	err = NewStore(db).BatchInsert(ModelSchema, []Record{newModel("foo", "foo@bar.baz", 1)}, BatchInsertOptions{})
+	r.EqualError(err, "kallax: the batch insert did not return the ids of all the 1 records")
+	r.Equal([]string{"INSERT INTO model (name,email,age) VALUES ($1,$2,$3) RETURNING id"}, recordedQueries)
+
+	schema := NewDynamicSchema("orders", "id", false, "name")
+	orders := make([]Record, 2)
+	for i := range orders {
+		record := NewDynamicRecord(schema)
+		r.NoError(record.Set("id", i+1))
+		r.NoError(record.Set("name", "foo"))
+		orders[i] = record
+	}
+
+	recordedQueries = nil
+	r.NoError(NewStore(db).BatchInsert(schema, orders, BatchInsertOptions{CopyThreshold: 3}))
+	r.Equal([]string{"INSERT INTO orders (id,name) VALUES ($1,$2),($3,$4)"}, recordedQueries)
+
+	for i := range orders {
+		record := NewDynamicRecord(schema)
+		r.NoError(record.Set("id", i+3))
+		orders[i] = record
+	}
+	recordedQueries = nil
+	r.NoError(NewStore(db).BatchInsert(schema, orders, BatchInsertOptions{CopyThreshold: 2}))
+	r.Equal([]string{`COPY "orders" ("id", "name") FROM STDIN`}, recordedQueries)
+}
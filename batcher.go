@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 )
@@ -14,21 +16,29 @@ type batchQueryRunner struct {
 	q             Query
 	oneToOneRels  []Relationship
 	oneToManyRels []Relationship
-	db            squirrel.BaseRunner
-	builder       squirrel.SelectBuilder
-	total         int
-	eof           bool
+	// manyToManyRels are retrieved like the 1:N relationships, but through
+	// their join tables.
+	manyToManyRels []Relationship
+	db             squirrel.BaseRunner
+	builder        squirrel.SelectBuilder
+	total          int
+	eof            bool
 	// records is the cache of the records in the last batch.
 	records []Record
+	// loc is the location scanned times are normalized to, if any.
+	loc *time.Location
+	// scopes are the default conditions of the queries of the store.
+	scopes scopes
 }
 
 var errNoMoreRows = errors.New("kallax: there are no more rows in the result set")
 
-func newBatchQueryRunner(schema Schema, db squirrel.BaseRunner, q Query) *batchQueryRunner {
-	cols, builder := q.compile()
+func newBatchQueryRunner(schema Schema, db squirrel.BaseRunner, q Query, scopes scopes) *batchQueryRunner {
+	cols, builder := scopes.compile(q)
 	var (
-		oneToOneRels  []Relationship
-		oneToManyRels []Relationship
+		oneToOneRels   []Relationship
+		oneToManyRels  []Relationship
+		manyToManyRels []Relationship
 	)
 
 	for _, rel := range q.getRelationships() {
@@ -37,17 +47,21 @@ func newBatchQueryRunner(schema Schema, db squirrel.BaseRunner, q Query) *batchQ
 			oneToOneRels = append(oneToOneRels, rel)
 		case OneToMany:
 			oneToManyRels = append(oneToManyRels, rel)
+		case ManyToMany:
+			manyToManyRels = append(manyToManyRels, rel)
 		}
 	}
 
 	return &batchQueryRunner{
-		schema:        schema,
-		cols:          cols,
-		q:             q,
-		oneToOneRels:  oneToOneRels,
-		oneToManyRels: oneToManyRels,
-		db:            db,
-		builder:       builder,
+		schema:         schema,
+		cols:           cols,
+		q:              q,
+		oneToOneRels:   oneToOneRels,
+		oneToManyRels:  oneToManyRels,
+		manyToManyRels: manyToManyRels,
+		db:             db,
+		builder:        builder,
+		scopes:         scopes,
 	}
 }
 
@@ -118,10 +132,12 @@ func (r *batchQueryRunner) loadNextBatch() ([]Record, error) {
 func (r *batchQueryRunner) processBatch(rows *sql.Rows) ([]Record, error) {
 	batchRs := NewResultSet(
 		rows,
-		r.q.isReadOnly(),
+		false,
 		r.oneToOneRels,
 		r.cols...,
 	)
+	batchRs.partial = r.q.isPartial()
+	batchRs.loc = r.loc
 
 	var records []Record
 	for batchRs.Next() {
@@ -141,8 +157,16 @@ func (r *batchQueryRunner) processBatch(rows *sql.Rows) ([]Record, error) {
 		ids[i] = r.GetID().Raw()
 	}
 
-	for _, rel := range r.oneToManyRels {
-		indexedResults, err := r.getRecordRelationships(ids, rel)
+	for _, rel := range append(r.oneToManyRels, r.manyToManyRels...) {
+		var (
+			indexedResults indexedRecords
+			err            error
+		)
+		if rel.Type == ManyToMany {
+			indexedResults, err = r.getJoinedRecords(r.schema, ids, rel)
+		} else {
+			indexedResults, err = r.getRecordRelationships(r.schema, ids, rel)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -160,56 +184,358 @@ func (r *batchQueryRunner) processBatch(rows *sql.Rows) ([]Record, error) {
 				r.setWritable(false)
 			}
 		}
+
+		if err := r.preload(rel.Schema, indexedResults.records(), rel.Preload); err != nil {
+			return nil, err
+		}
 	}
 
 	return records, nil
 }
 
-type indexedRecords map[interface{}][]Record
+// preload loads the relationships at the given dot-separated paths of the
+// given records of the given schema, with a query for each relationship of
+// each level of the paths. The relationships of the records are found in the
+// registered description of their schema.
+func (r *batchQueryRunner) preload(schema Schema, records []Record, paths []string) error {
+	if len(records) == 0 || len(paths) == 0 {
+		return nil
+	}
 
-func (r *batchQueryRunner) getRecordRelationships(ids []interface{}, rel Relationship) (indexedRecords, error) {
-	fk, ok := r.schema.ForeignKey(rel.Field)
+	info, ok := SchemaByTable(schema.Table())
 	if !ok {
-		return nil, fmt.Errorf("kallax: cannot find foreign key on field %s for table %s", rel.Field, r.schema.Table())
+		return fmt.Errorf("kallax: cannot find the schema of table %s to preload its relationships", schema.Table())
 	}
 
-	filter := In(fk, ids...)
-	if rel.Filter != nil {
-		rel.Filter = And(rel.Filter, filter)
-	} else {
-		rel.Filter = filter
+	fields, nested := splitPreloadPaths(paths)
+	for _, field := range fields {
+		relInfo, ok := info.relationship(field)
+		if !ok {
+			return fmt.Errorf("kallax: cannot preload relationship %s, model %s has no such relationship", field, info.Model)
+		}
+
+		rel := Relationship{Type: relInfo.Type, Field: field, Schema: relInfo.Schema.WithAlias(field)}
+		related, err := r.loadRelationship(schema, records, rel)
+		if err != nil {
+			return err
+		}
+
+		if err := r.preload(rel.Schema, related, nested[field]); err != nil {
+			return err
+		}
 	}
 
-	q := NewBaseQuery(rel.Schema)
-	q.Where(rel.Filter)
-	cols, builder := q.compile()
-	rows, err := builder.RunWith(r.db).Query()
+	return nil
+}
+
+// splitPreloadPaths returns the fields of the first level of the given
+// dot-separated paths of relationships, in the order they are found, and the
+// rest of the paths under each field.
+func splitPreloadPaths(paths []string) ([]string, map[string][]string) {
+	var (
+		fields []string
+		nested = make(map[string][]string)
+	)
+
+	for _, path := range paths {
+		parts := strings.SplitN(path, ".", 2)
+		if _, ok := nested[parts[0]]; !ok {
+			fields = append(fields, parts[0])
+			nested[parts[0]] = nil
+		}
+
+		if len(parts) > 1 {
+			nested[parts[0]] = append(nested[parts[0]], parts[1])
+		}
+	}
+
+	return fields, nested
+}
+
+// loadRelationship loads the given relationship of the given records of the
+// given schema, and returns the related records.
+func (r *batchQueryRunner) loadRelationship(schema Schema, records []Record, rel Relationship) ([]Record, error) {
+	if rel.Type == OneToOne {
+		return r.loadOneToOne(schema, records, rel)
+	}
+
+	var ids = make([]interface{}, len(records))
+	for i, rec := range records {
+		ids[i] = rec.GetID().Raw()
+	}
+
+	var (
+		indexedResults indexedRecords
+		err            error
+	)
+	if rel.Type == ManyToMany {
+		indexedResults, err = r.getJoinedRecords(schema, ids, rel)
+	} else {
+		indexedResults, err = r.getRecordRelationships(schema, ids, rel)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	relRs := NewResultSet(rows, false, nil, cols...)
-	var indexedResults = make(indexedRecords)
-	for relRs.Next() {
-		rec, err := relRs.Get(rel.Schema)
+	for _, rec := range records {
+		if err := rec.SetRelationship(rel.Field, indexedResults[rec.GetID().Raw()]); err != nil {
+			return nil, err
+		}
+	}
+
+	return indexedResults.records(), nil
+}
+
+// loadOneToOne loads the given one to one relationship of the given records
+// of the given schema, and returns the related records. The foreign key can
+// be in the records, if the relationship is inverse, or in the related ones.
+func (r *batchQueryRunner) loadOneToOne(schema Schema, records []Record, rel Relationship) ([]Record, error) {
+	fk, ok := schema.ForeignKey(rel.Field)
+	if !ok {
+		return nil, fmt.Errorf("kallax: cannot find foreign key on field %s for table %s", rel.Field, schema.Table())
+	}
+
+	if !fk.Inverse {
+		var ids = make([]interface{}, len(records))
+		for i, rec := range records {
+			ids[i] = rec.GetID().Raw()
+		}
+
+		indexedResults, err := r.getRecordRelationships(schema, ids, rel)
 		if err != nil {
 			return nil, err
 		}
 
+		for _, rec := range records {
+			if related := indexedResults[rec.GetID().Raw()]; len(related) > 0 {
+				if err := rec.SetRelationship(rel.Field, related[0]); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		return indexedResults.records(), nil
+	}
+
+	var (
+		ids  []interface{}
+		seen = make(map[interface{}]struct{})
+		refs = make(map[Record]interface{})
+	)
+	for _, rec := range records {
 		val, err := rec.Value(fk.String())
+		if err == ErrEmptyVirtualColumn {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		id := val.(Identifier).Raw()
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			ids = append(ids, id)
+		}
+		refs[rec] = id
+	}
+
+	var related = make(indexedRecords)
+	for _, chunk := range chunkIDs(ids) {
+		q := NewBaseQuery(rel.Schema)
+		q.Where(In(rel.Schema.ID(), chunk...))
+		err := r.queryRecords(q, rel.Schema, related, func(rec Record) (interface{}, error) {
+			return rec.GetID().Raw(), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, rec := range records {
+		id, ok := refs[rec]
+		if !ok || len(related[id]) == 0 {
+			continue
+		}
+
+		if err := rec.SetRelationship(rel.Field, related[id][0]); err != nil {
+			return nil, err
+		}
+	}
+
+	return related.records(), nil
+}
+
+type indexedRecords map[interface{}][]Record
+
+// records returns all the indexed records, without repeating the ones
+// indexed more than once.
+func (ir indexedRecords) records() []Record {
+	var (
+		result []Record
+		seen   = make(map[Record]bool)
+	)
+	for _, records := range ir {
+		for _, rec := range records {
+			if !seen[rec] {
+				seen[rec] = true
+				result = append(result, rec)
+			}
+		}
+	}
+	return result
+}
+
+func (r *batchQueryRunner) getRecordRelationships(schema Schema, ids []interface{}, rel Relationship) (indexedRecords, error) {
+	fk, ok := schema.ForeignKey(rel.Field)
+	if !ok {
+		return nil, fmt.Errorf("kallax: cannot find foreign key on field %s for table %s", rel.Field, schema.Table())
+	}
+
+	var indexedResults = make(indexedRecords)
+	for _, chunk := range chunkIDs(ids) {
+		filter := In(fk, chunk...)
+		if rel.Filter != nil {
+			filter = And(rel.Filter, filter)
+		}
+
+		q := NewBaseQuery(rel.Schema)
+		q.Where(filter)
+		err := r.queryRecords(q, rel.Schema, indexedResults, func(rec Record) (interface{}, error) {
+			val, err := rec.Value(fk.String())
+			if err != nil {
+				return nil, err
+			}
+			return val.(Identifier).Raw(), nil
+		})
 		if err != nil {
 			return nil, err
 		}
+	}
+
+	return indexedResults, nil
+}
+
+// relationshipChunkSize is the maximum number of identifiers of the records
+// whose relationships are retrieved in a single query. The records of bigger
+// batches are split in several queries, so they never exceed the maximum
+// number of parameters of a statement.
+var relationshipChunkSize = 1000
+
+// chunkIDs splits the given identifiers in chunks of relationshipChunkSize
+// identifiers at most.
+func chunkIDs(ids []interface{}) [][]interface{} {
+	var chunks [][]interface{}
+	for len(ids) > relationshipChunkSize {
+		chunks = append(chunks, ids[:relationshipChunkSize])
+		ids = ids[relationshipChunkSize:]
+	}
+
+	if len(ids) > 0 {
+		chunks = append(chunks, ids)
+	}
+	return chunks
+}
+
+// queryRecords runs the given query of records of the given schema, and adds
+// them to the given indexed records with the key returned for each of them.
+func (r *batchQueryRunner) queryRecords(q *BaseQuery, schema Schema, indexedResults indexedRecords, key func(Record) (interface{}, error)) error {
+	cols, builder := r.scopes.compile(q)
+	rows, err := builder.RunWith(r.db).Query()
+	if err != nil {
+		return err
+	}
+
+	relRs := NewResultSet(rows, false, nil, cols...)
+	relRs.loc = r.loc
+	for relRs.Next() {
+		rec, err := relRs.Get(schema)
+		if err != nil {
+			return err
+		}
+
+		id, err := key(rec)
+		if err != nil {
+			return err
+		}
 
 		rec.setPersisted()
 		rec.setWritable(true)
-		id := val.(Identifier).Raw()
 		indexedResults[id] = append(indexedResults[id], rec)
 	}
 
-	if err := relRs.Close(); err != nil {
+	return relRs.Close()
+}
+
+// getJoinedRecords retrieves the records of the given many to many
+// relationship of the records with the given ids, indexed by the id of the
+// record they are linked to in the join table.
+func (r *batchQueryRunner) getJoinedRecords(schema Schema, ids []interface{}, rel Relationship) (indexedRecords, error) {
+	fk, err := joinTableForeignKey(schema, rel.Field)
+	if err != nil {
 		return nil, err
 	}
 
-	return indexedResults, nil
+	var (
+		related []interface{}
+		links   = make(map[interface{}][]interface{})
+	)
+	for _, chunk := range chunkIDs(ids) {
+		rows, err := squirrel.Select(fk.String(), fk.Through.References.String()).
+			From(fk.Through.Table).
+			Where(squirrel.Eq{fk.String(): chunk}).
+			PlaceholderFormat(squirrel.Dollar).
+			RunWith(r.db).
+			Query()
+		if err != nil {
+			return nil, err
+		}
+
+		for rows.Next() {
+			id, relID := schema.New().GetID(), rel.Schema.New().GetID()
+			if err := rows.Scan(id, relID); err != nil {
+				rows.Close()
+				return nil, err
+			}
+
+			if _, ok := links[relID.Raw()]; !ok {
+				related = append(related, relID.Raw())
+			}
+			links[relID.Raw()] = append(links[relID.Raw()], id.Raw())
+		}
+
+		if err := rows.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		indexedResults = make(indexedRecords)
+		scanned        []Record
+	)
+	for _, chunk := range chunkIDs(related) {
+		filter := In(rel.Schema.ID(), chunk...)
+		if rel.Filter != nil {
+			filter = And(rel.Filter, filter)
+		}
+
+		q := NewBaseQuery(rel.Schema)
+		q.Where(filter)
+		err := r.queryRecords(q, rel.Schema, indexedResults, func(rec Record) (interface{}, error) {
+			scanned = append(scanned, rec)
+			return rec.GetID().Raw(), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the related records are indexed by the identifiers of the records
+	// they are linked to, in the order they are read
+	var linkedResults = make(indexedRecords)
+	for _, rec := range scanned {
+		for _, id := range links[rec.GetID().Raw()] {
+			linkedResults[id] = append(linkedResults[id], rec)
+		}
+	}
+
+	return linkedResults, nil
 }
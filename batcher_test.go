@@ -1,7 +1,10 @@
 package kallax
 
 import (
+	"database/sql"
+	"database/sql/driver"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/Masterminds/squirrel"
@@ -26,12 +29,21 @@ func TestOneToManyWithFilterNotWritable(t *testing.T) {
 
 	q := NewBaseQuery(ModelSchema)
 	r.NoError(q.AddRelation(RelSchema, "rels", OneToMany, Eq(f("foo"), "1")))
-	runner := newBatchQueryRunner(ModelSchema, squirrel.NewStmtCacher(db), q)
+	runner := newBatchQueryRunner(ModelSchema, squirrel.NewStmtCacher(db), q, nil)
 	record, err := runner.next()
 	r.NoError(err)
 	r.False(record.IsWritable())
 }
 
+func TestSplitPreloadPaths(t *testing.T) {
+	fields, nested := splitPreloadPaths([]string{"Items.Product", "Owner", "Items.Discounts.Code", "Items"})
+	require.Equal(t, []string{"Items", "Owner"}, fields)
+	require.Equal(t, map[string][]string{
+		"Items": {"Product", "Discounts.Code"},
+		"Owner": nil,
+	}, nested)
+}
+
 func TestBatcherLimit(t *testing.T) {
 	r := require.New(t)
 	db, err := openTestDB()
@@ -54,7 +66,7 @@ func TestBatcherLimit(t *testing.T) {
 	q.BatchSize(2)
 	q.Limit(5)
 	r.NoError(q.AddRelation(RelSchema, "rels", OneToMany, Eq(f("foo"), "1")))
-	runner := newBatchQueryRunner(ModelSchema, store.runner, q)
+	runner := newBatchQueryRunner(ModelSchema, store.runner, q, nil)
 	rs := NewBatchingResultSet(runner)
 
 	var count int
@@ -92,7 +104,7 @@ func TestBatcherNoExtraQueryIfLessThanLimit(t *testing.T) {
 	proxy := store.DebugWith(func(_ string, _ ...interface{}) {
 		queries++
 	}).runner
-	runner := newBatchQueryRunner(ModelSchema, proxy, q)
+	runner := newBatchQueryRunner(ModelSchema, proxy, q, nil)
 	rs := NewBatchingResultSet(runner)
 
 	var count int
@@ -131,7 +143,7 @@ func TestBatcherNoExtraQueryIfLessThanBatchSize(t *testing.T) {
 	proxy := store.DebugWith(func(_ string, _ ...interface{}) {
 		queries++
 	}).runner
-	runner := newBatchQueryRunner(ModelSchema, proxy, q)
+	runner := newBatchQueryRunner(ModelSchema, proxy, q, nil)
 	rs := NewBatchingResultSet(runner)
 
 	var count int
@@ -144,3 +156,98 @@ func TestBatcherNoExtraQueryIfLessThanBatchSize(t *testing.T) {
 	r.Equal(4, count)
 	r.Equal(4, queries)
 }
+
+func TestBatcherManyToMany(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	q := NewBaseQuery(ModelSchema)
+	r.NoError(q.AddRelation(RelSchema, "joined", ManyToMany, nil))
+	runner := newBatchQueryRunner(ModelSchema, NewStore(db).runner, q, nil)
+	r.Len(runner.manyToManyRels, 1)
+
+	recordedQueries = nil
+	indexed, err := runner.getJoinedRecords(ModelSchema, []interface{}{int64(1), int64(2)}, runner.manyToManyRels[0])
+	r.NoError(err)
+	r.Len(indexed, 0)
+	r.Equal([]string{"SELECT model_id, rel_id FROM model_rel WHERE model_id IN ($1,$2)"}, recordedQueries)
+
+	rows := &tableRowsRunner{entries: map[string]*cacheEntry{
+		"model_rel": {
+			columns: []string{"model_id", "rel_id"},
+			types:   []string{"INT8", "INT8"},
+			values:  [][]driver.Value{{int64(1), int64(30)}, {int64(2), int64(10)}, {int64(1), int64(20)}, {int64(1), int64(10)}},
+		},
+		"rel": {
+			columns: []string{"id", "model_id", "foo"},
+			types:   []string{"INT8", "INT8", "TEXT"},
+			values:  [][]driver.Value{{int64(20), int64(1), "b"}, {int64(10), int64(1), "a"}, {int64(30), int64(1), "c"}},
+		},
+	}}
+	runner = newBatchQueryRunner(ModelSchema, rows, q, nil)
+	for i := 0; i < 10; i++ {
+		indexed, err = runner.getJoinedRecords(ModelSchema, []interface{}{int64(1), int64(2)}, runner.manyToManyRels[0])
+		r.NoError(err)
+		r.Len(indexed, 2)
+
+		// the related records are in the order they are read
+		var foos = make(map[interface{}][]string)
+		for id, records := range indexed {
+			for _, rec := range records {
+				foos[id] = append(foos[id], rec.(*rel).Foo)
+			}
+		}
+		r.Equal(map[interface{}][]string{NumericID(1): {"b", "a", "c"}, NumericID(2): {"a"}}, foos)
+	}
+}
+
+// tableRowsRunner returns from every query the rows of the table it selects
+// from.
+type tableRowsRunner struct {
+	entries map[string]*cacheEntry
+}
+
+func (r *tableRowsRunner) Exec(string, ...interface{}) (sql.Result, error) {
+	return nil, nil
+}
+
+func (r *tableRowsRunner) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	for table, entry := range r.entries {
+		if strings.Contains(query, " FROM "+table+" ") {
+			return entry.replay()
+		}
+	}
+	return nil, fmt.Errorf("unexpected query: %s", query)
+}
+
+func TestBatcherRelationshipChunks(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	defer func(size int) {
+		relationshipChunkSize = size
+	}(relationshipChunkSize)
+	relationshipChunkSize = 2
+
+	q := NewBaseQuery(ModelSchema)
+	r.NoError(q.AddRelation(RelSchema, "rels", OneToMany, nil))
+	runner := newBatchQueryRunner(ModelSchema, db, q, nil)
+
+	recordedQueries = nil
+	ids := []interface{}{int64(1), int64(2), int64(3), int64(4), int64(5)}
+	indexed, err := runner.getRecordRelationships(ModelSchema, ids, runner.oneToManyRels[0])
+	r.NoError(err)
+	r.Len(indexed, 0)
+	r.Equal([]string{
+		"SELECT __rel_rels.id, __rel_rels.model_id, __rel_rels.foo FROM rel __rel_rels WHERE __rel_rels.model_id IN ($1,$2)",
+		"SELECT __rel_rels.id, __rel_rels.model_id, __rel_rels.foo FROM rel __rel_rels WHERE __rel_rels.model_id IN ($1,$2)",
+		"SELECT __rel_rels.id, __rel_rels.model_id, __rel_rels.foo FROM rel __rel_rels WHERE __rel_rels.model_id IN ($1)",
+	}, recordedQueries)
+
+	r.Equal([][]interface{}{{int64(1), int64(2)}, {int64(3), int64(4)}, {int64(5)}}, chunkIDs(ids))
+	r.Nil(chunkIDs(nil))
+}
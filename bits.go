@@ -0,0 +1,111 @@
+package kallax
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// BitString is a fixed or variable length string of bits stored in a bit or
+// bit varying column, which is a compact way to store sets of flags. The
+// length of the column can be set with the struct tag `bits`, e.g. `bits:"64"`
+// for a bit(64) column; otherwise the column is bit varying.
+type BitString struct {
+	bytes []byte
+	len   int
+}
+
+// NewBitString returns a new bit string of the given length with all its
+// bits unset.
+func NewBitString(len int) BitString {
+	return BitString{make([]byte, (len+7)/8), len}
+}
+
+// ParseBitString creates a new bit string from its representation as a
+// string of ones and zeros, e.g. "10110".
+func ParseBitString(s string) (BitString, error) {
+	b := NewBitString(len(s))
+	for i, c := range s {
+		switch c {
+		case '1':
+			b.SetBit(i, true)
+		case '0':
+		default:
+			return BitString{}, fmt.Errorf("kallax: invalid bit string %q", s)
+		}
+	}
+	return b, nil
+}
+
+// Len returns the number of bits in the bit string.
+func (b BitString) Len() int {
+	return b.len
+}
+
+// Bit reports whether the bit at the given position is set. Positions start
+// at 0, the leftmost bit. Bits out of the bit string are not set.
+func (b BitString) Bit(i int) bool {
+	if i < 0 || i >= b.len {
+		return false
+	}
+	return b.bytes[i/8]&(0x80>>uint(i%8)) != 0
+}
+
+// SetBit sets or unsets the bit at the given position. If the position is
+// out of the bit string, it grows to include it.
+func (b *BitString) SetBit(i int, v bool) {
+	if i < 0 {
+		return
+	}
+
+	if i >= b.len {
+		b.len = i + 1
+		for len(b.bytes) < (b.len+7)/8 {
+			b.bytes = append(b.bytes, 0)
+		}
+	}
+
+	if v {
+		b.bytes[i/8] |= 0x80 >> uint(i%8)
+	} else {
+		b.bytes[i/8] &^= 0x80 >> uint(i%8)
+	}
+}
+
+// String returns the bit string as a string of ones and zeros.
+func (b BitString) String() string {
+	var buf strings.Builder
+	for i := 0; i < b.len; i++ {
+		if b.Bit(i) {
+			buf.WriteRune('1')
+		} else {
+			buf.WriteRune('0')
+		}
+	}
+	return buf.String()
+}
+
+// Scan implements the sql.Scanner interface.
+func (b *BitString) Scan(src interface{}) error {
+	switch t := src.(type) {
+	case []byte:
+		return b.Scan(string(t))
+	case string:
+		bits, err := ParseBitString(t)
+		if err != nil {
+			return err
+		}
+		*b = bits
+		return nil
+	case nil:
+		*b = BitString{}
+		return nil
+	}
+	return fmt.Errorf("kallax: cannot scan type %s into BitString type", reflect.TypeOf(src))
+}
+
+// Value implements the driver.Valuer interface.
+func (b BitString) Value() (driver.Value, error) {
+	return b.String(), nil
+}
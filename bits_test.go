@@ -0,0 +1,91 @@
+package kallax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBitString(t *testing.T) {
+	r := require.New(t)
+	b := NewBitString(10)
+	r.Equal(10, b.Len())
+	r.Equal("0000000000", b.String())
+
+	b.SetBit(0, true)
+	b.SetBit(9, true)
+	r.True(b.Bit(0))
+	r.False(b.Bit(1))
+	r.True(b.Bit(9))
+	r.False(b.Bit(10))
+	r.Equal("1000000001", b.String())
+
+	b.SetBit(0, false)
+	b.SetBit(12, true)
+	r.Equal(13, b.Len())
+	r.Equal("0000000001001", b.String())
+}
+
+func TestParseBitString(t *testing.T) {
+	r := require.New(t)
+	b, err := ParseBitString("101100001")
+	r.NoError(err)
+	r.Equal(9, b.Len())
+	r.Equal("101100001", b.String())
+
+	b, err = ParseBitString("")
+	r.NoError(err)
+	r.Equal(0, b.Len())
+
+	_, err = ParseBitString("1012")
+	r.Error(err)
+}
+
+func TestBitString_Scan(t *testing.T) {
+	r := require.New(t)
+	var b BitString
+	r.NoError(b.Scan([]byte("0110")))
+	r.Equal("0110", b.String())
+
+	r.NoError(b.Scan("1"))
+	r.Equal("1", b.String())
+
+	r.NoError(b.Scan(nil))
+	r.Equal(0, b.Len())
+
+	r.Error(b.Scan("0x1"))
+	r.Error(b.Scan(int64(1)))
+}
+
+func TestBitString_Value(t *testing.T) {
+	b, _ := ParseBitString("0110")
+	v, err := b.Value()
+	require.NoError(t, err)
+	require.Equal(t, "0110", v)
+}
+
+func TestBitsOperators(t *testing.T) {
+	r := require.New(t)
+	col := f("flags")
+	schema := NewBaseSchema("users", "__users", f("id"), nil, nil, false, f("id"), col)
+	mask, _ := ParseBitString("0101")
+	zero := NewBitString(4)
+
+	cases := []struct {
+		cond Condition
+		sql  string
+		args []interface{}
+	}{
+		{BitsAll(col, mask), "(__users.flags & CAST(? AS varbit)) = CAST(? AS varbit)", []interface{}{mask, mask}},
+		{BitsAny(col, mask), "(__users.flags & CAST(? AS varbit)) <> CAST(? AS varbit)", []interface{}{mask, zero}},
+		{BitsNone(col, mask), "(__users.flags & CAST(? AS varbit)) = CAST(? AS varbit)", []interface{}{mask, zero}},
+		{BitSet(col, 3), "get_bit(__users.flags, ?) = 1", []interface{}{3}},
+	}
+
+	for _, c := range cases {
+		sql, args, err := c.cond(schema).ToSql()
+		r.NoError(err)
+		r.Equal(c.sql, sql)
+		r.Equal(c.args, args)
+	}
+}
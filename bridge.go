@@ -0,0 +1,135 @@
+package kallax
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// ConstraintKind is the kind of a constraint of the database.
+type ConstraintKind string
+
+const (
+	// UniqueConstraint is a unique index or primary key.
+	UniqueConstraint ConstraintKind = "unique"
+	// ForeignKeyConstraint is a foreign key.
+	ForeignKeyConstraint ConstraintKind = "foreign key"
+	// NotNullConstraint is a NOT NULL column.
+	NotNullConstraint ConstraintKind = "not null"
+	// CheckConstraint is a CHECK constraint.
+	CheckConstraint ConstraintKind = "check"
+)
+
+// pqConstraintKinds are the constraint kinds by the code of the errors of
+// PostgreSQL that violate them.
+var pqConstraintKinds = map[string]ConstraintKind{
+	"23505": UniqueConstraint,
+	"23503": ForeignKeyConstraint,
+	"23502": NotNullConstraint,
+	"23514": CheckConstraint,
+}
+
+// ConstraintError is the error returned by TranslateError when a statement
+// violates a constraint of the database.
+type ConstraintError struct {
+	// Kind is the kind of the violated constraint.
+	Kind ConstraintKind
+	// Table is the table of the constraint, if it is reported by the
+	// database.
+	Table string
+	// Constraint is the name of the constraint, if it is reported by the
+	// database. It is empty for NOT NULL constraints.
+	Constraint string
+	// Err is the error returned by the database.
+	Err error
+}
+
+func (e *ConstraintError) Error() string {
+	return fmt.Sprintf("kallax: %s constraint violated: %s", e.Kind, e.Err)
+}
+
+// Unwrap returns the error returned by the database.
+func (e *ConstraintError) Unwrap() error {
+	return e.Err
+}
+
+// TranslateError translates the given error returned by the database driver
+// to an error of kallax, so it can be handled without depending on the
+// driver. The violations of constraints are translated to ConstraintError,
+// which wraps the error of the driver. Any other error is returned as is.
+// The errors of all the registered drivers are translated.
+func TranslateError(err error) error {
+	if pqErr, ok := postgresError(err); ok {
+		if kind, ok := pqConstraintKinds[pqErr.Code]; ok {
+			return &ConstraintError{
+				Kind:       kind,
+				Table:      pqErr.Table,
+				Constraint: pqErr.Constraint,
+				Err:        err,
+			}
+		}
+	}
+	return err
+}
+
+// RawRows performs a raw SQL query with the given parameters and returns its
+// rows as they are, so they can be read by code that works with the
+// database/sql package. Errors are translated with TranslateError. The rows
+// must be closed once read.
+func (s *Store) RawRows(query string, params ...interface{}) (*sql.Rows, error) {
+	rows, err := s.runner.Query(query, params...)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	return rows, nil
+}
+
+// NewRowsResultSet returns a result set that scans the given rows into
+// records of the given schema, so the rows returned by RawRows or by another
+// data layer can be read as records. The columns of the rows are matched to
+// the columns of the records by name. If the rows do not have all the
+// columns of the schema, the records are not writable.
+func NewRowsResultSet(schema Schema, rows *sql.Rows) (*BaseResultSet, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	readOnly := false
+	for _, col := range ColumnNames(schema.Columns()) {
+		if !containsString(columns, col) {
+			readOnly = true
+			break
+		}
+	}
+	return NewResultSet(rows, readOnly, nil, columns...), nil
+}
+
+// FindBySQL performs a raw SQL query with the given parameters and returns a
+// result set that scans its rows into records of the given schema, as
+// RowsResultSet, so they can be read with Scan or Get instead of RawScan.
+func (s *Store) FindBySQL(schema Schema, query string, params ...interface{}) (*BaseResultSet, error) {
+	rows, err := s.RawRows(query, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	rs, err := s.RowsResultSet(schema, rows)
+	if err != nil {
+		rows.Close()
+		return nil, err
+	}
+	return rs, nil
+}
+
+// RowsResultSet returns a result set that scans the given rows into records
+// of the given schema, as NewRowsResultSet, with times normalized to the
+// location of the store, if any.
+func (s *Store) RowsResultSet(schema Schema, rows *sql.Rows) (*BaseResultSet, error) {
+	rs, err := NewRowsResultSet(schema, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	rs.loc = s.loc
+	return rs, nil
+}
@@ -0,0 +1,71 @@
+package kallax
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslateError(t *testing.T) {
+	cases := []struct {
+		err  error
+		kind ConstraintKind
+	}{
+		{&pq.Error{Code: "23505", Table: "model", Constraint: "model_email_key"}, UniqueConstraint},
+		{&pq.Error{Code: "23503", Table: "rel", Constraint: "rel_model_id_fkey"}, ForeignKeyConstraint},
+		{&pq.Error{Code: "23502", Table: "model"}, NotNullConstraint},
+		{&pq.Error{Code: "23514", Table: "model", Constraint: "model_age_check"}, CheckConstraint},
+	}
+
+	for _, c := range cases {
+		err := TranslateError(c.err)
+		var cerr *ConstraintError
+		require.True(t, errors.As(err, &cerr), c.kind)
+		require.Equal(t, c.kind, cerr.Kind)
+		require.Equal(t, c.err.(*pq.Error).Table, cerr.Table)
+		require.Equal(t, c.err.(*pq.Error).Constraint, cerr.Constraint)
+		require.Equal(t, c.err, errors.Unwrap(err))
+	}
+
+	require.EqualError(t, TranslateError(&pq.Error{Code: "23505", Message: "duplicate key"}), "kallax: unique constraint violated: pq: duplicate key (23505)")
+	retryable := &pq.Error{Code: "40001"}
+	require.Equal(t, retryable, TranslateError(retryable))
+	require.Equal(t, sql.ErrNoRows, TranslateError(sql.ErrNoRows))
+	require.NoError(t, TranslateError(nil))
+}
+
+func TestRawRows(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	recordedQueries = nil
+	store := NewStore(db)
+	rows, err := store.RawRows("SELECT id, name FROM model WHERE age > $1", 1)
+	r.NoError(err)
+
+	rs, err := store.RowsResultSet(ModelSchema, rows)
+	r.NoError(err)
+	r.True(rs.readOnly)
+	r.False(rs.Next())
+	r.NoError(rs.Close())
+	r.Equal([]string{"SELECT id, name FROM model WHERE age > $1"}, recordedQueries)
+}
+
+func TestFindBySQL(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	recordedQueries = nil
+	rs, err := NewStore(db).FindBySQL(ModelSchema, "SELECT m.* FROM model m JOIN rel r ON r.model_id = m.id WHERE r.foo = $1", "bar")
+	r.NoError(err)
+	r.False(rs.Next())
+	r.NoError(rs.Close())
+	r.Equal([]string{"SELECT m.* FROM model m JOIN rel r ON r.model_id = m.id WHERE r.foo = $1"}, recordedQueries)
+}
@@ -0,0 +1,175 @@
+package kallax
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/lann/builder"
+)
+
+// ErrNoValues is returned by UpdateWhere when no values are given.
+var ErrNoValues = errors.New("kallax: an update needs at least one value")
+
+// UpdateWhere sets the given columns to the given values in all the rows
+// retrieved with the given query, including its limit and offset, and
+// returns the number of rows updated. The records are not loaded, so their
+// events are not run. If the updates of the records of the schema are
+// optimistically locked, their versions are incremented, so the records
+// already loaded become stale.
+func (s *Store) UpdateWhere(q Query, values map[SchemaField]interface{}) (int64, error) {
+	query, args, err := s.updateWhereStatement(q, values)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.bulkExec(q.Schema(), query, args)
+}
+
+// DeleteWhere removes all the rows retrieved with the given query, including
+// its limit and offset, and returns the number of rows removed. If the
+// records of the schema are soft deleted, the rows are not removed, but their
+// soft delete column is set to the current time; HardDeleteWhere removes
+// them anyway. The records are not loaded, so their events are not run.
+func (s *Store) DeleteWhere(q Query) (int64, error) {
+	if col := q.Schema().SoftDeleteField(); col != nil {
+		return s.UpdateWhere(q, map[SchemaField]interface{}{
+			col: s.deletionTime(),
+		})
+	}
+	return s.HardDeleteWhere(q)
+}
+
+// HardDeleteWhere removes all the rows retrieved with the given query, even
+// if the records of the schema are soft deleted, and returns the number of
+// rows removed.
+func (s *Store) HardDeleteWhere(q Query) (int64, error) {
+	schema := q.Schema()
+	cond, args, err := s.bulkCond(q)
+	if err != nil {
+		return 0, err
+	}
+
+	query, err := squirrel.Dollar.ReplacePlaceholders("DELETE FROM " + schema.Table() + " WHERE " + cond)
+	if err != nil {
+		return 0, err
+	}
+	return s.bulkExec(schema, query, args)
+}
+
+// updateWhereStatement returns the SQL statement, and its arguments, run by
+// UpdateWhere. The columns are sorted, so the same statement is prepared for
+// the same columns.
+func (s *Store) updateWhereStatement(q Query, values map[SchemaField]interface{}) (string, []interface{}, error) {
+	if len(values) == 0 {
+		return "", nil, ErrNoValues
+	}
+
+	cols := make([]SchemaField, 0, len(values))
+	for col := range values {
+		cols = append(cols, col)
+	}
+	sort.Slice(cols, func(i, j int) bool {
+		return cols[i].String() < cols[j].String()
+	})
+
+	schema := q.Schema()
+	if col := schema.TenantField(); s.tenant != nil && col != nil {
+		for c, v := range values {
+			if c.String() == col.String() && !sameTenant(v, s.tenant) {
+				return "", nil, ErrCrossTenant
+			}
+		}
+	}
+
+	var (
+		query  bytes.Buffer
+		args   []interface{}
+		locked bool
+	)
+	lock := schema.LockField()
+	query.WriteString("UPDATE ")
+	query.WriteString(schema.Table())
+	query.WriteString(" SET ")
+	for i, col := range cols {
+		if i != 0 {
+			query.WriteString(",")
+		}
+		query.WriteString(col.String())
+		query.WriteString("=?")
+		args = append(args, values[col])
+		locked = locked || (lock != nil && col.String() == lock.String())
+	}
+
+	if lock != nil && !locked {
+		query.WriteString(",")
+		query.WriteString(lock.String())
+		query.WriteString("=")
+		query.WriteString(lock.String())
+		query.WriteString("+1")
+	}
+
+	cond, condArgs, err := s.bulkCond(q)
+	if err != nil {
+		return "", nil, err
+	}
+	query.WriteString(" WHERE ")
+	query.WriteString(cond)
+
+	sql, err := squirrel.Dollar.ReplacePlaceholders(query.String())
+	if err != nil {
+		return "", nil, err
+	}
+	return sql, append(args, condArgs...), nil
+}
+
+// bulkCond returns the condition, with question mark placeholders, that
+// matches the primary keys of the rows retrieved with the given query, along
+// with the default conditions of the store.
+func (s *Store) bulkCond(q Query) (string, []interface{}, error) {
+	schema := q.Schema()
+	pk := schema.PrimaryKey()
+	cols := make([]string, len(pk))
+	qualified := make([]string, len(pk))
+	for i, col := range pk {
+		cols[i] = col.String()
+		qualified[i] = col.QualifiedName(schema)
+	}
+
+	_, queryBuilder := s.queryScopes().compile(q)
+	builder := builder.Set(queryBuilder, "Columns", nil).(squirrel.SelectBuilder).
+		Columns(qualified...).
+		PlaceholderFormat(squirrel.Question)
+	if offset := q.GetOffset(); offset > 0 {
+		builder = builder.Offset(offset)
+	}
+
+	if limit := q.GetLimit(); limit > 0 {
+		builder = builder.Limit(limit)
+	}
+
+	sub, args, err := builder.ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+
+	key := cols[0]
+	if len(cols) > 1 {
+		key = "(" + strings.Join(cols, ",") + ")"
+	}
+	return key + " IN (" + sub + ")", args, nil
+}
+
+// bulkExec runs the given statement of the table of the given schema and
+// returns the number of rows affected by it.
+func (s *Store) bulkExec(schema Schema, query string, args []interface{}) (int64, error) {
+	result, err := s.runner.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	s.invalidate(schema.Table())
+	return result.RowsAffected()
+}
@@ -0,0 +1,73 @@
+package kallax
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreUpdateWhere(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	store := NewStore(db)
+	q := NewBaseQuery(ModelSchema)
+	q.Where(Eq(f("name"), "foo"))
+	q.Limit(10)
+
+	recordedQueries, recordedArgs = nil, nil
+	_, err = store.UpdateWhere(q, map[SchemaField]interface{}{
+		f("email"): "foo@bar.baz",
+		f("age"):   2,
+	})
+	r.NoError(err)
+	_, err = store.UpdateWhere(q, nil)
+	r.Equal(ErrNoValues, err)
+
+	schema := NewDynamicSchema("post", "id", true, "title", "version").
+		WithLock(f("version"))
+	_, err = store.UpdateWhere(NewBaseQuery(schema), map[SchemaField]interface{}{
+		f("title"): "foo",
+	})
+	r.NoError(err)
+
+	r.Equal([]string{
+		"UPDATE model SET age=$1,email=$2 WHERE id IN (SELECT __model.id FROM model __model WHERE __model.name = $3 LIMIT 10)",
+		"UPDATE post SET title=$1,version=version+1 WHERE id IN (SELECT __post.id FROM post __post)",
+	}, recordedQueries)
+	r.Equal([][]driver.Value{{int64(2), "foo@bar.baz", "foo"}, {"foo"}}, recordedArgs)
+}
+
+func TestStoreDeleteWhere(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	store := NewStore(db)
+	q := NewBaseQuery(ModelSchema)
+	q.Where(Gt(f("age"), 2))
+
+	recordedQueries, recordedArgs = nil, nil
+	_, err = store.DeleteWhere(q)
+	r.NoError(err)
+
+	schema := NewDynamicSchema("post", "foo_id", false, "foo_id", "position", "deleted_at").
+		WithPrimaryKey(f("foo_id"), f("position")).
+		WithSoftDelete(f("deleted_at"))
+	_, err = store.DeleteWhere(NewBaseQuery(schema))
+	r.NoError(err)
+	_, err = store.HardDeleteWhere(NewBaseQuery(schema))
+	r.NoError(err)
+
+	r.Equal([]string{
+		"DELETE FROM model WHERE id IN (SELECT __model.id FROM model __model WHERE __model.age > $1)",
+		"UPDATE post SET deleted_at=$1 WHERE (foo_id,position) IN (SELECT __post.foo_id, __post.position FROM post __post WHERE __post.deleted_at IS NULL)",
+		"DELETE FROM post WHERE (foo_id,position) IN (SELECT __post.foo_id, __post.position FROM post __post WHERE __post.deleted_at IS NULL)",
+	}, recordedQueries)
+	r.Equal(int64(2), recordedArgs[0][0])
+}
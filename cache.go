@@ -0,0 +1,318 @@
+package kallax
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// QueryCache is a read-through cache of the rows retrieved by the queries of
+// the stores using it, see Store.WithCache. The rows are cached by the
+// fingerprint of the SQL of the queries and their arguments, and are tagged
+// with the tables of the queries, so they can be invalidated when the tables
+// change. A QueryCache is safe for concurrent use and can be shared by the
+// stores of several models.
+type QueryCache struct {
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	// tags are the keys of the entries of every table.
+	tags map[string]map[string]struct{}
+	// generations are increased every time a table is invalidated, so rows
+	// retrieved while their tables are invalidated are not cached.
+	generations map[string]uint64
+	now         func() time.Time
+}
+
+// NewQueryCache returns a new empty query cache.
+func NewQueryCache() *QueryCache {
+	return &QueryCache{
+		entries:     make(map[string]*cacheEntry),
+		tags:        make(map[string]map[string]struct{}),
+		generations: make(map[string]uint64),
+		now:         time.Now,
+	}
+}
+
+// Invalidate removes the cached rows of the queries of the given tables.
+// The stores using the cache invalidate the tables they insert, update or
+// delete records in, but the tables changed in any other way, such as with
+// raw statements or by other processes, must be invalidated explicitly.
+func (c *QueryCache) Invalidate(tables ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, table := range tables {
+		c.generations[table]++
+		for key := range c.tags[table] {
+			c.remove(key)
+		}
+	}
+}
+
+// Purge removes all the cached rows.
+func (c *QueryCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for table := range c.tags {
+		c.generations[table]++
+	}
+	c.entries = make(map[string]*cacheEntry)
+	c.tags = make(map[string]map[string]struct{})
+}
+
+// Len returns the number of queries with cached rows, including the ones
+// that have expired but have not been retrieved again yet.
+func (c *QueryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// query returns the rows of the given query, which are retrieved with the
+// given runner and cached for the given time if they are not in the cache.
+// If ttl is zero, the rows are cached until their tables are invalidated.
+// The rows retrieved from a replica are cached with a key of their own.
+func (c *QueryCache) query(runner squirrel.BaseRunner, replica bool, tables []string, ttl time.Duration, query string, args ...interface{}) (*sql.Rows, error) {
+	key, ok := fingerprint(query, args)
+	if !ok {
+		return runner.Query(query, args...)
+	}
+
+	if replica {
+		key = "replica:" + key
+	}
+
+	entry, generations := c.get(key, tables)
+	if entry != nil {
+		return entry.replay()
+	}
+
+	rows, err := runner.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err = newCacheEntry(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.tables = tables
+	if ttl > 0 {
+		entry.expires = c.now().Add(ttl)
+	}
+	c.set(key, entry, generations)
+	return entry.replay()
+}
+
+// get returns the entry with the given key, if it is cached and has not
+// expired. Otherwise, it returns the generations of the given tables.
+func (c *QueryCache) get(key string, tables []string) (*cacheEntry, []uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.entries[key]; ok {
+		if entry.expires.IsZero() || c.now().Before(entry.expires) {
+			return entry, nil
+		}
+		c.remove(key)
+	}
+
+	generations := make([]uint64, len(tables))
+	for i, table := range tables {
+		generations[i] = c.generations[table]
+	}
+	return nil, generations
+}
+
+// set caches the given entry with the given key, unless any of its tables
+// has been invalidated since it had the given generations.
+func (c *QueryCache) set(key string, entry *cacheEntry, generations []uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, table := range entry.tables {
+		if c.generations[table] != generations[i] {
+			return
+		}
+	}
+
+	c.entries[key] = entry
+	for _, table := range entry.tables {
+		if c.tags[table] == nil {
+			c.tags[table] = make(map[string]struct{})
+		}
+		c.tags[table][key] = struct{}{}
+	}
+}
+
+func (c *QueryCache) remove(key string) {
+	entry, ok := c.entries[key]
+	if !ok {
+		return
+	}
+
+	delete(c.entries, key)
+	for _, table := range entry.tables {
+		delete(c.tags[table], key)
+	}
+}
+
+// fingerprint returns the key of the rows of the given query in the cache.
+// It returns false if any of the arguments can not be converted to a value
+// sent to the database, in which case the rows are not cached.
+func fingerprint(query string, args []interface{}) (string, bool) {
+	h := sha256.New()
+	io.WriteString(h, query)
+	for _, arg := range args {
+		v, err := driver.DefaultParameterConverter.ConvertValue(arg)
+		if err != nil {
+			return "", false
+		}
+
+		if t, ok := v.(time.Time); ok {
+			v = t.UTC().Format(time.RFC3339Nano)
+		}
+		fmt.Fprintf(h, "\x00%T:%v", v, v)
+	}
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+// queryTables returns the tables retrieved by the given query, which are
+// the tags of its rows in the cache.
+func queryTables(q Query) []string {
+	tables := []string{q.Schema().Table()}
+	for _, r := range q.getRelationships() {
+		tables = append(tables, r.Schema.Table())
+	}
+	return tables
+}
+
+// cacheEntry are the cached rows of a query.
+type cacheEntry struct {
+	columns []string
+	types   []string
+	values  [][]driver.Value
+	tables  []string
+	expires time.Time
+}
+
+// newCacheEntry reads all the given rows and closes them.
+func newCacheEntry(rows *sql.Rows) (*cacheEntry, error) {
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &cacheEntry{columns: columns, types: make([]string, len(columnTypes))}
+	for i, t := range columnTypes {
+		entry.types[i] = t.DatabaseTypeName()
+	}
+
+	for rows.Next() {
+		row := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range row {
+			pointers[i] = &row[i]
+		}
+
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+
+		values := make([]driver.Value, len(row))
+		for i, v := range row {
+			values[i] = v
+		}
+		entry.values = append(entry.values, values)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// replay returns the cached rows, which are read by the result sets as the
+// ones retrieved from the database.
+func (e *cacheEntry) replay() (*sql.Rows, error) {
+	return cacheDB.Query("", e)
+}
+
+// cacheDB is the database of the cache driver, which returns the rows of
+// the cache entry given as the only argument of its queries.
+var cacheDB *sql.DB
+
+func init() {
+	sql.Register("kallax_cache", cacheDriver{})
+	cacheDB, _ = sql.Open("kallax_cache", "")
+}
+
+type cacheDriver struct{}
+
+func (cacheDriver) Open(string) (driver.Conn, error) { return cacheConn{}, nil }
+
+type cacheConn struct{}
+
+func (cacheConn) Prepare(string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("kallax: the cache driver does not prepare statements")
+}
+
+func (cacheConn) Close() error { return nil }
+
+func (cacheConn) Begin() (driver.Tx, error) {
+	return nil, fmt.Errorf("kallax: the cache driver does not run transactions")
+}
+
+// CheckNamedValue accepts the cache entries as arguments.
+func (cacheConn) CheckNamedValue(*driver.NamedValue) error { return nil }
+
+func (cacheConn) Query(_ string, args []driver.Value) (driver.Rows, error) {
+	entry, ok := args[0].(*cacheEntry)
+	if !ok {
+		return nil, fmt.Errorf("kallax: the cache driver can only query cache entries")
+	}
+	return &cacheRows{entry: entry}, nil
+}
+
+type cacheRows struct {
+	entry *cacheEntry
+	next  int
+}
+
+func (r *cacheRows) Columns() []string { return r.entry.columns }
+func (r *cacheRows) Close() error      { return nil }
+
+func (r *cacheRows) ColumnTypeDatabaseTypeName(i int) string {
+	return r.entry.types[i]
+}
+
+func (r *cacheRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.entry.values) {
+		return io.EOF
+	}
+
+	for i, v := range r.entry.values[r.next] {
+		if b, ok := v.([]byte); ok {
+			v = append([]byte(nil), b...)
+		}
+		dest[i] = v
+	}
+	r.next++
+	return nil
+}
@@ -0,0 +1,173 @@
+package kallax
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryCache(t *testing.T) {
+	r := require.New(t)
+	runner := &countingRunner{values: [][]driver.Value{{int64(1), []byte("foo")}, {int64(2), nil}}}
+	cache := NewQueryCache()
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	query := func(args ...interface{}) [][]interface{} {
+		rows, err := cache.query(runner, false, []string{"model", "rel"}, time.Minute, "SELECT id, name FROM model WHERE age > $1", args...)
+		r.NoError(err)
+		defer rows.Close()
+
+		var result [][]interface{}
+		for rows.Next() {
+			var id int64
+			var name *string
+			r.NoError(rows.Scan(&id, &name))
+			if name == nil {
+				result = append(result, []interface{}{id, nil})
+			} else {
+				result = append(result, []interface{}{id, *name})
+			}
+		}
+		r.NoError(rows.Err())
+		return result
+	}
+
+	expected := [][]interface{}{{int64(1), "foo"}, {int64(2), nil}}
+	r.Equal(expected, query(1))
+	r.Equal(expected, query(1))
+	r.Equal(1, runner.calls)
+	r.Equal(1, cache.Len())
+
+	query(2)
+	r.Equal(2, runner.calls)
+	r.Equal(2, cache.Len())
+
+	cache.Invalidate("rel")
+	r.Equal(0, cache.Len())
+	r.Equal(expected, query(1))
+	r.Equal(3, runner.calls)
+
+	now = now.Add(time.Minute)
+	query(1)
+	r.Equal(4, runner.calls)
+
+	cache.Purge()
+	r.Equal(0, cache.Len())
+	query(1)
+	r.Equal(5, runner.calls)
+}
+
+func TestQueryCache_InvalidatedWhileQuerying(t *testing.T) {
+	cache := NewQueryCache()
+	runner := &countingRunner{onQuery: func() { cache.Invalidate("model") }}
+
+	rows, err := cache.query(runner, false, []string{"model"}, 0, "SELECT 1")
+	require.NoError(t, err)
+	require.NoError(t, rows.Close())
+	require.Equal(t, 0, cache.Len())
+}
+
+func TestFingerprint(t *testing.T) {
+	r := require.New(t)
+	ts := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	a, ok := fingerprint("SELECT $1", []interface{}{1, ts})
+	r.True(ok)
+	b, ok := fingerprint("SELECT $1", []interface{}{int64(1), ts.In(time.FixedZone("CET", 3600))})
+	r.True(ok)
+	r.Equal(a, b)
+
+	c, ok := fingerprint("SELECT $1", []interface{}{"1", ts})
+	r.True(ok)
+	r.NotEqual(a, c)
+
+	_, ok = fingerprint("SELECT $1", []interface{}{struct{}{}})
+	r.False(ok)
+}
+
+func TestStore_WithCache(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	recordedQueries = nil
+	cache := NewQueryCache()
+	store := NewStore(db).DisableCacher().WithCache(cache, 0)
+	find := func(s *Store) {
+		rs, err := s.Find(NewBaseQuery(ModelSchema))
+		r.NoError(err)
+		r.NoError(rs.Close())
+	}
+
+	find(store)
+	find(store)
+	r.Len(recordedQueries, 1)
+	r.Equal(1, cache.Len())
+
+	m := newModel("foo", "foo@bar.baz", 1)
+	m.ID = 1
+	r.NoError(store.Delete(ModelSchema, m))
+	r.Equal(0, cache.Len())
+
+	find(store)
+	r.NoError(store.Transaction(func(s *Store) error {
+		find(s)
+		return s.Delete(ModelSchema, m)
+	}))
+	r.Len(recordedQueries, 5)
+	r.Equal(0, cache.Len())
+}
+
+func TestStore_WithCache_DerivedTransactionStore(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	recordedQueries = nil
+	cache := NewQueryCache()
+	store := NewStore(db).DisableCacher().WithDialect(MySQL).WithCache(cache, 0)
+	find := func(s *Store) {
+		rs, err := s.Find(NewBaseQuery(ModelSchema))
+		r.NoError(err)
+		r.NoError(rs.Close())
+	}
+
+	r.NoError(store.Transaction(func(tx *Store) error {
+		if err := tx.WithScope(ModelSchema, Eq(f("age"), 1)).Insert(ModelSchema, newModel("foo", "foo@bar.baz", 1)); err != nil {
+			return err
+		}
+
+		// a concurrent reader caches the rows before the commit
+		find(store)
+		r.Equal(1, cache.Len())
+		return nil
+	}))
+	r.Equal(0, cache.Len())
+}
+
+// countingRunner returns the given rows from every query and counts them.
+type countingRunner struct {
+	values  [][]driver.Value
+	calls   int
+	onQuery func()
+}
+
+func (r *countingRunner) Exec(string, ...interface{}) (sql.Result, error) {
+	return nil, nil
+}
+
+func (r *countingRunner) Query(string, ...interface{}) (*sql.Rows, error) {
+	r.calls++
+	if r.onQuery != nil {
+		r.onQuery()
+	}
+
+	entry := &cacheEntry{columns: []string{"id", "name"}, types: []string{"INT8", "TEXT"}, values: r.values}
+	return entry.replay()
+}
@@ -0,0 +1,151 @@
+package kallax
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+	"unicode/utf8"
+)
+
+// FieldChange is the change of the value of a column of a record.
+type FieldChange struct {
+	// Old is the value of the column when the record was loaded or saved.
+	Old interface{} `json:"old"`
+	// New is the current value of the column.
+	New interface{} `json:"new"`
+}
+
+// Changeset are the changes of the columns of a record since it was loaded
+// from the database or saved, indexed by column name. The values are the ones
+// sent to the database: JSON columns are reported as JSON documents, text
+// columns and the rest of the types encoded as text, such as arrays, as
+// strings, and binary columns as bytes. A changeset can be encoded to JSON.
+type Changeset map[string]FieldChange
+
+// Has reports whether the given columns have changed. If no columns are
+// given, it reports whether any column has changed.
+func (c Changeset) Has(cols ...SchemaField) bool {
+	if len(cols) == 0 {
+		return len(c) > 0
+	}
+
+	for _, col := range cols {
+		if _, ok := c[col.String()]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Get returns the change of the given column, if it has changed.
+func (c Changeset) Get(col SchemaField) (FieldChange, bool) {
+	change, ok := c[col.String()]
+	return change, ok
+}
+
+// Columns returns the names of the changed columns, sorted alphabetically.
+func (c Changeset) Columns() []string {
+	cols := make([]string, 0, len(c))
+	for col := range c {
+		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+	return cols
+}
+
+// ChangesOf returns the changes of the columns of the given record since it
+// was loaded from the database or saved. Only the columns that were
+// retrieved or saved are compared, so the changeset of a new record is
+// always empty. Generated models expose it as their Changes method.
+func ChangesOf(record Record) Changeset {
+	changes := make(Changeset)
+	t, ok := record.(changeTracker)
+	if !ok {
+		return changes
+	}
+
+	for col, old := range t.loadedValues() {
+		current, ok := columnValue(record, col)
+		if !ok || sameValue(old, current) {
+			continue
+		}
+
+		changes[col] = FieldChange{Old: changeValue(old), New: changeValue(current)}
+	}
+	return changes
+}
+
+// changeTracker is implemented by the records that keep the values their
+// columns had when they were loaded or saved, which all the records
+// embedding Model do.
+type changeTracker interface {
+	loadedValues() map[string]driver.Value
+	setLoadedValues(values map[string]driver.Value, reset bool)
+}
+
+// snapshot keeps the current values of the given columns of the record, so
+// its changes can be reported by ChangesOf. If reset is true, the values of
+// the rest of the columns are discarded.
+func snapshot(record Record, cols []string, reset bool) {
+	t, ok := record.(changeTracker)
+	if !ok {
+		return
+	}
+
+	values := make(map[string]driver.Value, len(cols))
+	for _, col := range cols {
+		if v, ok := columnValue(record, col); ok {
+			values[col] = v
+		}
+	}
+	t.setLoadedValues(values, reset)
+}
+
+// columnValue returns the value sent to the database for the given column
+// of the record. Values are copied, so they don't change with the record.
+func columnValue(record Record, col string) (driver.Value, bool) {
+	v, err := record.Value(col)
+	if err != nil {
+		return nil, false
+	}
+
+	dv, err := driver.DefaultParameterConverter.ConvertValue(v)
+	if err != nil {
+		return nil, false
+	}
+
+	if b, ok := dv.([]byte); ok {
+		dv = append([]byte(nil), b...)
+	}
+	return dv, true
+}
+
+func sameValue(a, b driver.Value) bool {
+	switch a := a.(type) {
+	case []byte:
+		b, ok := b.([]byte)
+		return ok && bytes.Equal(a, b)
+	case time.Time:
+		b, ok := b.(time.Time)
+		return ok && a.Equal(b)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// changeValue returns the value reported in a changeset for the given value
+// sent to the database, which can be encoded to JSON.
+func changeValue(v driver.Value) interface{} {
+	b, ok := v.([]byte)
+	switch {
+	case !ok:
+		return v
+	case json.Valid(b):
+		return json.RawMessage(b)
+	case utf8.Valid(b):
+		return string(b)
+	}
+	return b
+}
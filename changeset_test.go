@@ -0,0 +1,73 @@
+package kallax
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangesOf(t *testing.T) {
+	require := require.New(t)
+	m := newModel("foo", "foo@bar.baz", 1)
+	require.Empty(ChangesOf(m))
+
+	snapshot(m, []string{"name", "email", "age"}, true)
+	require.Empty(ChangesOf(m))
+	require.False(ChangesOf(m).Has())
+
+	m.Name = "bar"
+	m.Age = 2
+	changes := ChangesOf(m)
+	require.Equal(Changeset{
+		"name": {Old: "foo", New: "bar"},
+		"age":  {Old: int64(1), New: int64(2)},
+	}, changes)
+	require.True(changes.Has())
+	require.True(changes.Has(f("name"), f("age")))
+	require.False(changes.Has(f("name"), f("email")))
+	require.Equal([]string{"age", "name"}, changes.Columns())
+
+	change, ok := changes.Get(f("age"))
+	require.True(ok)
+	require.Equal(int64(1), change.Old)
+	_, ok = changes.Get(f("email"))
+	require.False(ok)
+
+	data, err := json.Marshal(changes)
+	require.NoError(err)
+	require.JSONEq(`{"name":{"old":"foo","new":"bar"},"age":{"old":1,"new":2}}`, string(data))
+
+	snapshot(m, []string{"name"}, false)
+	require.Equal(Changeset{"age": {Old: int64(1), New: int64(2)}}, ChangesOf(m))
+}
+
+func TestChangeValue(t *testing.T) {
+	require := require.New(t)
+	require.Equal(json.RawMessage(`{"a":1}`), changeValue([]byte(`{"a":1}`)))
+	require.Equal("{a,b}", changeValue([]byte("{a,b}")))
+	require.Equal([]byte{0xff, 0xfe}, changeValue([]byte{0xff, 0xfe}))
+	require.Equal(int64(1), changeValue(int64(1)))
+
+	now := time.Now()
+	require.True(sameValue(now, now.UTC()))
+	require.True(sameValue([]byte("a"), []byte("a")))
+	require.False(sameValue([]byte("a"), "a"))
+}
+
+func TestStoreSnapshot(t *testing.T) {
+	require := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	require.NoError(err)
+	defer db.Close()
+
+	store := NewStore(db)
+	m := newModel("foo", "foo@bar.baz", 1)
+	require.NoError(store.Insert(ModelSchema, m))
+	require.Empty(ChangesOf(m))
+
+	m.Name = "bar"
+	require.True(ChangesOf(m).Has(f("name")))
+}
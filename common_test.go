@@ -229,6 +229,7 @@ var ModelSchema = NewBaseSchema(
 		"rel":     NewForeignKey("model_id", false),
 		"rels":    NewForeignKey("model_id", false),
 		"rel_inv": NewForeignKey("model_id", true),
+		"joined":  NewJoinTableForeignKey("model_rel", "model_id", "rel_id"),
 	},
 	func() Record {
 		return new(model)
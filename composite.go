@@ -0,0 +1,51 @@
+package kallax
+
+import "fmt"
+
+// Composite needs to be embedded in a struct to declare it as a Postgres
+// composite type, which can be used as the type of model fields. The exported
+// fields of the struct are the attributes of the composite type. The name of
+// the type can be set with the struct tag `type`, e.g.
+//
+//	type Address struct {
+//		kallax.Composite `type:"address"`
+//		Street string
+//		City   string
+//	}
+//
+// If no name is given, the name of the type is the name of the struct in
+// lower snake case. The type is created in the migrations of the models using
+// it.
+type Composite struct{}
+
+// CompositeSchemaField is a SchemaField that represents an attribute of a
+// column of a composite type.
+type CompositeSchemaField struct {
+	field string
+	attrs []string
+}
+
+// NewCompositeSchemaField creates a new SchemaField that is the attribute of
+// a composite type column. If more than one attribute is given, the rest are
+// attributes of nested composite types.
+func NewCompositeSchemaField(field string, attrs ...string) *CompositeSchemaField {
+	return &CompositeSchemaField{field, attrs}
+}
+
+func (f *CompositeSchemaField) QualifiedName(schema Schema) string {
+	name := f.field
+	if schema != nil && schema.Alias() != "" {
+		name = schema.Alias() + "." + name
+	}
+
+	for _, attr := range f.attrs {
+		name = fmt.Sprintf("(%s).%s", name, attr)
+	}
+	return name
+}
+
+func (f *CompositeSchemaField) String() string {
+	return f.QualifiedName(nil)
+}
+
+func (*CompositeSchemaField) isSchemaField() {}
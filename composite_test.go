@@ -0,0 +1,24 @@
+package kallax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompositeSchemaField(t *testing.T) {
+	r := require.New(t)
+	schema := NewBaseSchema("users", "__users", f("id"), nil, nil, false, f("id"))
+
+	field := NewCompositeSchemaField("address", "city")
+	r.Equal("(address).city", field.String())
+	r.Equal("(__users.address).city", field.QualifiedName(schema))
+
+	nested := NewCompositeSchemaField("address", "location", "lat")
+	r.Equal("((__users.address).location).lat", nested.QualifiedName(schema))
+
+	sql, args, err := Eq(field, "Madrid")(schema).ToSql()
+	r.NoError(err)
+	r.Equal("(__users.address).city = ?", sql)
+	r.Equal([]interface{}{"Madrid"}, args)
+}
@@ -0,0 +1,88 @@
+package kallax
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// WithContext returns a copy of the store that runs all its statements with
+// the given context, so they are cancelled along with it and carry its
+// deadline and values. The context is also used by the result sets of the
+// queries, the loading of their relationships and the transactions of the
+// store, whose stores have the same context. The copy shares the prepared
+// statements of the store, so a copy can be made for every request.
+func (s *Store) WithContext(ctx context.Context) *Store {
+	store := *s
+	store.ctx = ctx
+	store.runner = s.chain
+	if ctx != nil {
+		store.runner = &contextBoundRunner{DBProxyContext: s.chain, ctx: ctx}
+	}
+	return &store
+}
+
+// Context returns the context of the store, which is the background context
+// unless it is set with WithContext.
+func (s *Store) Context() context.Context {
+	if s.ctx == nil {
+		return context.Background()
+	}
+	return s.ctx
+}
+
+// contextRunner is a runner that runs its statements with a context.
+type contextRunner interface {
+	squirrel.ExecerContext
+	squirrel.QueryerContext
+	squirrel.QueryRowerContext
+}
+
+// runnerContext returns the given runner as a contextRunner. The statements
+// of the runners that do not support contexts are run without them.
+func runnerContext(runner squirrel.DBProxyContext) contextRunner {
+	if r, ok := runner.(contextRunner); ok {
+		return r
+	}
+	return noContextRunner{runner}
+}
+
+type noContextRunner struct {
+	squirrel.DBProxyContext
+}
+
+func (r noContextRunner) ExecContext(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return r.Exec(query, args...)
+}
+
+func (r noContextRunner) QueryContext(_ context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return r.Query(query, args...)
+}
+
+func (r noContextRunner) QueryRowContext(_ context.Context, query string, args ...interface{}) squirrel.RowScanner {
+	return r.QueryRow(query, args...)
+}
+
+// contextBoundRunner runs the statements of its wrapped runner with the given
+// context.
+type contextBoundRunner struct {
+	squirrel.DBProxyContext
+	ctx context.Context
+}
+
+func (r *contextBoundRunner) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return runnerContext(r.DBProxyContext).ExecContext(r.ctx, query, args...)
+}
+
+func (r *contextBoundRunner) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return runnerContext(r.DBProxyContext).QueryContext(r.ctx, query, args...)
+}
+
+func (r *contextBoundRunner) QueryRow(query string, args ...interface{}) squirrel.RowScanner {
+	return runnerContext(r.DBProxyContext).QueryRowContext(r.ctx, query, args...)
+}
+
+func (r *contextBoundRunner) Prepare(query string) (*sql.Stmt, error) {
+	return r.DBProxyContext.PrepareContext(r.ctx, query)
+}
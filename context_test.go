@@ -0,0 +1,52 @@
+package kallax
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type contextKey struct{}
+
+func TestStore_WithContext(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	var logged []string
+	store := NewStore(db).DebugWith(func(msg string, _ ...interface{}) {
+		logged = append(logged, msg)
+	})
+	r.Equal(context.Background(), store.Context())
+
+	ctx := context.WithValue(context.Background(), contextKey{}, "foo")
+	recordedQueries = nil
+	r.NoError(store.WithContext(ctx).Transaction(func(s *Store) error {
+		r.Equal("foo", s.Context().Value(contextKey{}))
+		rs, err := s.Find(NewBaseQuery(ModelSchema))
+		if err != nil {
+			return err
+		}
+		return rs.Close()
+	}))
+	r.Equal([]string{"SELECT __model.id, __model.name, __model.email, __model.age FROM model __model"}, recordedQueries)
+	r.Equal([]string{"kallax: Query: SELECT __model.id, __model.name, __model.email, __model.age FROM model __model"}, logged)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	ctxStore := store.WithContext(cancelled).WithDialect(Postgres)
+	r.Equal(cancelled, ctxStore.Context())
+	_, err = ctxStore.Find(NewBaseQuery(ModelSchema))
+	r.Equal(context.Canceled, err)
+	_, err = ctxStore.RawExec("DELETE FROM model WHERE id = $1", 1)
+	r.Equal(context.Canceled, err)
+	r.EqualError(ctxStore.Transaction(func(*Store) error {
+		return nil
+	}), "kallax: can't open transaction: context canceled")
+
+	_, err = ctxStore.WithContext(context.Background()).Find(NewBaseQuery(ModelSchema))
+	r.NoError(err)
+}
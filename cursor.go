@@ -0,0 +1,306 @@
+package kallax
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrInvalidCursor is returned when a query is paginated with a cursor
+	// that was not returned by a page of the same query.
+	ErrInvalidCursor = errors.New("kallax: invalid cursor")
+	// ErrPageLimit is returned by FindPage when the query has no limit.
+	ErrPageLimit = errors.New("kallax: keyset pagination requires a limit")
+	// ErrPageOrder is returned by FindPage when the query is not ordered by
+	// columns.
+	ErrPageOrder = errors.New("kallax: keyset pagination requires an order by columns")
+)
+
+// Cursor is an opaque position in the results of a query with keyset
+// pagination, which contains the values of the sort keys of a record. The
+// cursors of a page are returned by Page.NextCursor and Page.PrevCursor.
+// The empty cursor is no position.
+type Cursor string
+
+// newCursor returns the cursor of the given record for the given sort keys.
+func newCursor(record Record, keys []*colOrder) (Cursor, error) {
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		v, err := record.Value(key.col.String())
+		if err != nil {
+			return "", err
+		}
+
+		dv, err := driver.DefaultParameterConverter.ConvertValue(v)
+		if err != nil {
+			return "", err
+		}
+
+		switch dv := dv.(type) {
+		case time.Time:
+			values[i] = dv.Format(time.RFC3339Nano)
+		case []byte:
+			values[i] = string(dv)
+		default:
+			values[i] = dv
+		}
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return Cursor(base64.RawURLEncoding.EncodeToString(data)), nil
+}
+
+// values returns the values of the sort keys of the cursor. Numbers are
+// returned as strings, so their precision is kept, and the database converts
+// them, as it does with times, to the types of the columns they are
+// compared to.
+func (c Cursor) values() ([]interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(string(c))
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var values []interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&values); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	for i, v := range values {
+		if n, ok := v.(json.Number); ok {
+			values[i] = n.String()
+		}
+	}
+	return values, nil
+}
+
+// cursorBound is the cursor a query is paginated from.
+type cursorBound struct {
+	cursor Cursor
+	before bool
+}
+
+// AfterCursor makes the query retrieve the records after the given cursor in
+// the order of the query. An empty cursor retrieves the first records. Use it
+// with FindPage to paginate the query by keyset.
+func (q *BaseQuery) AfterCursor(cursor Cursor) {
+	q.paginate(cursor, false)
+}
+
+// BeforeCursor makes the query retrieve the records before the given cursor
+// in the order of the query, which are the last ones of the query if the
+// cursor is empty. Use it with FindPage to paginate the query by keyset.
+func (q *BaseQuery) BeforeCursor(cursor Cursor) {
+	q.paginate(cursor, true)
+}
+
+func (q *BaseQuery) paginate(cursor Cursor, before bool) {
+	q.cursor = &cursorBound{cursor, before}
+}
+
+// keys returns the sort keys of the query, which are the columns of its
+// order.
+func (q *BaseQuery) keys() ([]*colOrder, error) {
+	if len(q.orders) == 0 {
+		return nil, ErrPageOrder
+	}
+
+	keys := make([]*colOrder, len(q.orders))
+	for i, o := range q.orders {
+		key, ok := o.(*colOrder)
+		if !ok {
+			return nil, ErrPageOrder
+		}
+		keys[i] = key
+	}
+	return keys, nil
+}
+
+// cursorCond returns the condition that selects the records after, or
+// before, the non-empty cursor of the query. With the sort keys a ASC and
+// b DESC, it is `a > $1 OR (a = $1 AND b < $2)` after the cursor.
+func (q *BaseQuery) cursorCond() Condition {
+	keys, err := q.keys()
+	if err != nil {
+		return errCond(err)
+	}
+
+	values, err := q.cursor.cursor.values()
+	if err != nil {
+		return errCond(err)
+	}
+
+	if len(values) != len(keys) {
+		return errCond(ErrInvalidCursor)
+	}
+
+	conds := make([]Condition, len(keys))
+	for i, key := range keys {
+		var cond Condition
+		if (key.order == asc) != q.cursor.before {
+			cond = Gt(key.col, values[i])
+		} else {
+			cond = Lt(key.col, values[i])
+		}
+
+		if i == 0 {
+			conds[i] = cond
+			continue
+		}
+
+		eqs := make([]Condition, 0, i+1)
+		for j := 0; j < i; j++ {
+			eqs = append(eqs, Eq(keys[j].col, values[j]))
+		}
+		conds[i] = And(append(eqs, cond)...)
+	}
+	return Or(conds...)
+}
+
+// errCond returns a condition that fails to compile with the given error.
+func errCond(err error) Condition {
+	return func(Schema) ToSqler {
+		return &errOp{err.Error()}
+	}
+}
+
+// reverseOrder returns the given order reversed, if it is the order of a
+// column.
+func reverseOrder(o ColumnOrder) ColumnOrder {
+	key, ok := o.(*colOrder)
+	if !ok {
+		return o
+	}
+
+	if key.order == asc {
+		return Desc(key.col)
+	}
+	return Asc(key.col)
+}
+
+// pageQuery returns a copy of the query that retrieves one more record than
+// a page, so FindPage knows whether there are more.
+func (q *BaseQuery) pageQuery() (*BaseQuery, error) {
+	if q.limit == 0 {
+		return nil, ErrPageLimit
+	}
+
+	keys, err := q.keys()
+	if err != nil {
+		return nil, err
+	}
+
+	if q.cursor != nil && q.cursor.cursor != "" {
+		values, err := q.cursor.cursor.values()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(values) != len(keys) {
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	pq := q.Copy()
+	pq.limit++
+	if pq.cursor == nil {
+		pq.cursor = &cursorBound{}
+	}
+	return pq, nil
+}
+
+// Page is a page of records of a query paginated by keyset.
+type Page struct {
+	// Records are the records of the page, in the order of the query.
+	Records []Record
+	next    Cursor
+	prev    Cursor
+}
+
+// NextCursor returns the cursor to retrieve the next page with AfterCursor,
+// or an empty cursor if this is the last page.
+func (p *Page) NextCursor() Cursor {
+	return p.next
+}
+
+// PrevCursor returns the cursor to retrieve the previous page with
+// BeforeCursor, or an empty cursor if this is the first page.
+func (p *Page) PrevCursor() Cursor {
+	return p.prev
+}
+
+// FindPage retrieves a page of the records of the given query, which is
+// paginated by keyset instead of by offset, so retrieving a page does not
+// get slower the further it is. The query must be ordered by columns whose
+// values are unique and not null, so include the primary key as the last of
+// them, and its limit is the size of the page. The page is the first one,
+// unless the query is paginated with AfterCursor or BeforeCursor.
+func (s *Store) FindPage(q Query) (*Page, error) {
+	return findPage(q, s.Find)
+}
+
+// findPage retrieves a page of the records of the given query with the given
+// function to find the records of a query.
+func findPage(q Query, find func(Query) (ResultSet, error)) (*Page, error) {
+	pq, err := q.pageQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	rs, err := find(pq)
+	if err != nil {
+		return nil, err
+	}
+	defer rs.Close()
+
+	var records []Record
+	for rs.Next() {
+		record, err := rs.Get(q.Schema())
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	limit := int(q.GetLimit())
+	more := len(records) > limit
+	if more {
+		records = records[:limit]
+	}
+
+	before := pq.cursor.before
+	if before {
+		for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+			records[i], records[j] = records[j], records[i]
+		}
+	}
+
+	page := &Page{Records: records}
+	if len(records) == 0 {
+		return page, nil
+	}
+
+	keys, _ := pq.keys()
+	paginated := pq.cursor.cursor != ""
+	if more && !before || paginated && before {
+		if page.next, err = newCursor(records[len(records)-1], keys); err != nil {
+			return nil, err
+		}
+	}
+
+	if more && before || paginated && !before {
+		if page.prev, err = newCursor(records[0], keys); err != nil {
+			return nil, err
+		}
+	}
+
+	return page, nil
+}
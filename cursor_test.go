@@ -0,0 +1,116 @@
+package kallax
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor(t *testing.T) {
+	r := require.New(t)
+	m := newModel("foo", "foo@bar.baz", 20)
+	m.ID = 3
+
+	cursor, err := newCursor(m, []*colOrder{
+		Desc(f("age")).(*colOrder),
+		Asc(f("name")).(*colOrder),
+		Asc(f("id")).(*colOrder),
+	})
+	r.NoError(err)
+	r.NotEmpty(cursor)
+
+	values, err := cursor.values()
+	r.NoError(err)
+	r.Equal([]interface{}{"20", "foo", "3"}, values)
+
+	_, err = Cursor("foo!").values()
+	r.Equal(ErrInvalidCursor, err)
+	_, err = Cursor("Zm9v").values()
+	r.Equal(ErrInvalidCursor, err)
+}
+
+func TestCursor_Time(t *testing.T) {
+	r := require.New(t)
+	schema := NewDynamicSchema("post", "id", true, "created_at")
+	record := NewDynamicRecord(schema)
+	date := time.Date(2017, time.March, 1, 10, 30, 0, 500, time.UTC)
+	r.NoError(record.Set("created_at", date))
+
+	cursor, err := newCursor(record, []*colOrder{Asc(f("created_at")).(*colOrder)})
+	r.NoError(err)
+	values, err := cursor.values()
+	r.NoError(err)
+	r.Equal([]interface{}{"2017-03-01T10:30:00.0000005Z"}, values)
+}
+
+func TestBaseQuery_AfterCursor(t *testing.T) {
+	r := require.New(t)
+	m := newModel("foo", "foo@bar.baz", 20)
+	m.ID = 3
+	cursor, err := newCursor(m, []*colOrder{
+		Desc(f("age")).(*colOrder),
+		Asc(f("id")).(*colOrder),
+	})
+	r.NoError(err)
+
+	q := NewBaseQuery(ModelSchema)
+	q.Select(f("id"))
+	q.Order(Desc(f("age")), Asc(f("id")))
+	q.AfterCursor(cursor)
+
+	sql, args, err := q.ToSql()
+	r.NoError(err)
+	r.Equal("SELECT __model.id FROM model __model WHERE (__model.age < $1 OR (__model.age = $2 AND __model.id > $3)) ORDER BY __model.age DESC, __model.id ASC", sql)
+	r.Equal([]interface{}{"20", "20", "3"}, args)
+
+	q.BeforeCursor(cursor)
+	sql, args, err = q.ToSql()
+	r.NoError(err)
+	r.Equal("SELECT __model.id FROM model __model WHERE (__model.age > $1 OR (__model.age = $2 AND __model.id < $3)) ORDER BY __model.age ASC, __model.id DESC", sql)
+	r.Equal([]interface{}{"20", "20", "3"}, args)
+
+	q.AfterCursor("")
+	sql, _, err = q.ToSql()
+	r.NoError(err)
+	r.Equal("SELECT __model.id FROM model __model ORDER BY __model.age DESC, __model.id ASC", sql)
+
+	q.AfterCursor("foo!")
+	_, _, err = q.ToSql()
+	r.EqualError(err, ErrInvalidCursor.Error())
+}
+
+func TestStore_FindPage(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+	store := NewStore(db)
+
+	q := NewBaseQuery(ModelSchema)
+	q.Order(Asc(f("id")))
+	_, err = store.FindPage(q)
+	r.Equal(ErrPageLimit, err)
+
+	q = NewBaseQuery(ModelSchema)
+	q.Limit(10)
+	_, err = store.FindPage(q)
+	r.Equal(ErrPageOrder, err)
+
+	recordedQueries = nil
+	q.Order(Asc(f("id")))
+	page, err := store.FindPage(q)
+	r.NoError(err)
+	r.Len(page.Records, 0)
+	r.Empty(page.NextCursor())
+	r.Empty(page.PrevCursor())
+	r.Equal([]string{
+		"SELECT __model.id, __model.name, __model.email, __model.age FROM model __model ORDER BY __model.id ASC LIMIT 11",
+	}, recordedQueries)
+	r.Equal(uint64(10), q.GetLimit())
+
+	q.AfterCursor("foo!")
+	_, err = store.FindPage(q)
+	r.Equal(ErrInvalidCursor, err)
+}
@@ -0,0 +1,428 @@
+package kallax
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// Dialect is the SQL dialect of a database backend. Kallax builds all its
+// statements for PostgreSQL, and stores with a different dialect rewrite them
+// before running them: the placeholders are replaced with the ones of the
+// dialect and statements using features the dialect does not support fail
+// with an UnsupportedError instead of being sent to the database.
+type Dialect interface {
+	// Name returns the name of the dialect.
+	Name() string
+	// Placeholder returns the placeholder of the argument in the given
+	// position of a statement, starting at 1.
+	Placeholder(n int) string
+	// Supports reports whether the dialect supports the given feature.
+	Supports(Feature) bool
+	// OnConflictUpdate returns the clause of an insert that updates the
+	// given columns of the row that conflicts with the inserted one in the
+	// given columns, with the inserted values. If there are no columns to
+	// update, the conflicting row is left as is.
+	OnConflictUpdate(conflict, update []string) string
+}
+
+// Feature is a feature of PostgreSQL that other dialects may not support.
+type Feature string
+
+const (
+	// FeatureReturning is returning values from inserts, which is used to
+	// retrieve auto-incrementable primary keys.
+	FeatureReturning Feature = "RETURNING clauses"
+	// FeatureCasts are the type casts with `::`.
+	FeatureCasts Feature = "type casts"
+	// FeatureArrays are the array expressions and comparisons, such as
+	// `= ANY(...)`.
+	FeatureArrays Feature = "array expressions"
+	// FeatureContainment are the containment and overlap operators of
+	// arrays, JSON documents, ranges and hstores: `@>`, `<@` and `&&`.
+	FeatureContainment Feature = "containment and overlap operators"
+	// FeatureJSONKeys are the operators checking the keys of JSON documents
+	// and hstores: `?|` and `?&`.
+	FeatureJSONKeys Feature = "key existence operators"
+	// FeatureFullTextSearch is the full text search match operator `@@`.
+	FeatureFullTextSearch Feature = "full text search"
+	// FeatureRegex are the regular expression operators, such as `~`.
+	FeatureRegex Feature = "regular expression operators"
+	// FeatureILike is the case insensitive ILIKE operator.
+	FeatureILike Feature = "ILIKE"
+	// FeatureSimilarTo is the SIMILAR TO operator.
+	FeatureSimilarTo Feature = "SIMILAR TO"
+	// FeatureCopy are the COPY statements, with which Import loads rows.
+	FeatureCopy Feature = "COPY statements"
+	// FeatureWritableCTEs are the data-modifying WITH queries, with which
+	// batches are flushed.
+	FeatureWritableCTEs Feature = "data-modifying WITH queries"
+	// FeatureMaintenance are the ANALYZE, VACUUM and REINDEX statements run
+	// by the maintenance helpers of the stores.
+	FeatureMaintenance Feature = "maintenance statements"
+	// FeatureExplain are the cost estimates of the query plans returned by
+	// EXPLAIN, with which query guards check the cost of statements.
+	FeatureExplain Feature = "EXPLAIN cost estimates"
+	// FeatureRowLocks are the row-level locks taken by the queries with
+	// LockForUpdate and LockForShare.
+	FeatureRowLocks Feature = "row-level locks"
+	// FeatureNotifications are the notifications sent to channels with
+	// Store.Notify and received with a Listener.
+	FeatureNotifications Feature = "notifications"
+)
+
+// UnsupportedError is returned when a statement uses a feature that the
+// dialect of the store does not support.
+type UnsupportedError struct {
+	// Dialect is the name of the dialect.
+	Dialect string
+	// Feature is the unsupported feature.
+	Feature Feature
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("kallax: %s are not supported by the %s dialect", e.Feature, e.Dialect)
+}
+
+var (
+	// Postgres is the dialect of PostgreSQL, which is the default one.
+	Postgres Dialect = postgresDialect{}
+	// SQLite is the dialect of SQLite 3.35 or later. It's meant to run fast
+	// local tests of the stores, so the database driver is not a dependency
+	// of kallax and it must be imported by the tests. The tables can be
+	// created with the statements printed by `kallax schema --dialect sqlite`.
+	// Only the statements using features of PostgreSQL that SQLite lacks,
+	// such as arrays and JSON operators, fail.
+	SQLite Dialect = sqliteDialect{}
+	// CockroachDB is the dialect of CockroachDB. Statements are run as they
+	// are built for PostgreSQL, but the transactions of the stores are
+	// retried when CockroachDB aborts them because of contention. As it uses
+	// the same driver as PostgreSQL, it must always be set with WithDialect.
+	CockroachDB Dialect = cockroachDialect{}
+	// MySQL is the dialect of MySQL and MariaDB. As they can not return
+	// values from inserts, the auto-incrementable primary keys are set with
+	// the ID of the last inserted row. The tables can be created with the
+	// statements printed by `kallax schema --dialect mysql`.
+	MySQL Dialect = mysqlDialect{}
+)
+
+// Retrier is implemented by the dialects of databases that abort
+// transactions that must be retried by the client, such as CockroachDB.
+type Retrier interface {
+	// Retryable reports whether a transaction that failed with the given
+	// error must be retried.
+	Retryable(err error) bool
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string             { return "postgres" }
+func (postgresDialect) Placeholder(n int) string { return fmt.Sprintf("$%d", n) }
+func (postgresDialect) Supports(Feature) bool    { return true }
+
+func (postgresDialect) OnConflictUpdate(conflict, update []string) string {
+	return onConflictUpdate(conflict, update)
+}
+
+// onConflictUpdate returns the ON CONFLICT clause of PostgreSQL, which is
+// also supported by SQLite.
+func onConflictUpdate(conflict, update []string) string {
+	clause := fmt.Sprintf(" ON CONFLICT (%s) DO ", strings.Join(conflict, ", "))
+	if len(update) == 0 {
+		return clause + "NOTHING"
+	}
+
+	sets := make([]string, len(update))
+	for i, col := range update {
+		sets[i] = fmt.Sprintf("%s = EXCLUDED.%s", col, col)
+	}
+	return clause + "UPDATE SET " + strings.Join(sets, ", ")
+}
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string             { return "sqlite" }
+func (sqliteDialect) Placeholder(n int) string { return fmt.Sprintf("?%d", n) }
+func (sqliteDialect) Supports(f Feature) bool  { return f == FeatureReturning }
+
+func (sqliteDialect) OnConflictUpdate(conflict, update []string) string {
+	return onConflictUpdate(conflict, update)
+}
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) Name() string            { return "mysql" }
+func (mysqlDialect) Placeholder(int) string  { return "?" }
+func (mysqlDialect) Supports(f Feature) bool { return f == FeatureRowLocks }
+
+// OnConflictUpdate returns an ON DUPLICATE KEY UPDATE clause. MySQL checks
+// all the unique keys of the table, so the conflict columns are only used to
+// leave the row as is if there are no columns to update.
+func (mysqlDialect) OnConflictUpdate(conflict, update []string) string {
+	if len(update) == 0 {
+		return fmt.Sprintf(" ON DUPLICATE KEY UPDATE %s = %s", conflict[0], conflict[0])
+	}
+
+	sets := make([]string, len(update))
+	for i, col := range update {
+		sets[i] = fmt.Sprintf("%s = VALUES(%s)", col, col)
+	}
+	return " ON DUPLICATE KEY UPDATE " + strings.Join(sets, ", ")
+}
+
+type cockroachDialect struct {
+	postgresDialect
+}
+
+func (cockroachDialect) Name() string { return "cockroachdb" }
+
+// Supports reports whether CockroachDB supports the given feature, which are
+// all of them but the maintenance statements, the EXPLAIN output and the
+// notifications of PostgreSQL.
+func (cockroachDialect) Supports(f Feature) bool {
+	return f != FeatureMaintenance && f != FeatureExplain && f != FeatureNotifications
+}
+
+// Retryable reports whether the given error is a serialization failure,
+// which CockroachDB returns for transactions that must be retried.
+func (cockroachDialect) Retryable(err error) bool {
+	pgErr, ok := postgresError(err)
+	return ok && pgErr.Code == "40001"
+}
+
+// rewrites reports whether the statements must be rewritten for the given
+// dialect, because it does not support all the statements built for
+// PostgreSQL.
+func rewrites(d Dialect) bool {
+	switch d.(type) {
+	case nil, postgresDialect, cockroachDialect:
+		return false
+	}
+	return true
+}
+
+// DialectOf returns the dialect of the given database, guessed from the
+// package of its driver. SQLite and MySQL drivers are detected and any other
+// driver is considered to be PostgreSQL.
+func DialectOf(db *sql.DB) Dialect {
+	if db == nil {
+		return Postgres
+	}
+
+	t := reflect.TypeOf(db.Driver())
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch pkg := strings.ToLower(t.PkgPath()); {
+	case strings.Contains(pkg, "sqlite"):
+		return SQLite
+	case strings.Contains(pkg, "mysql"):
+		return MySQL
+	}
+	return Postgres
+}
+
+// postgresOperators are the operators of PostgreSQL that are checked in the
+// statements, with the feature they belong to.
+var postgresOperators = []struct {
+	token   string
+	feature Feature
+}{
+	{"::", FeatureCasts},
+	{"@>", FeatureContainment},
+	{"<@", FeatureContainment},
+	{"&&", FeatureContainment},
+	{"?|", FeatureJSONKeys},
+	{"?&", FeatureJSONKeys},
+	{"@@", FeatureFullTextSearch},
+	{"!~", FeatureRegex},
+	{"~*", FeatureRegex},
+	{" ~ ", FeatureRegex},
+}
+
+// postgresKeywords are the keywords of PostgreSQL that are checked in the
+// statements, with the feature they belong to.
+var postgresKeywords = map[string]Feature{
+	"RETURNING": FeatureReturning,
+	"ANY":       FeatureArrays,
+	"ARRAY":     FeatureArrays,
+	"ILIKE":     FeatureILike,
+	"SIMILAR":   FeatureSimilarTo,
+	"FOR":       FeatureRowLocks,
+}
+
+// rewrite returns the given PostgreSQL statement with the placeholders of
+// the given dialect, along with the given arguments of the statement in the
+// order of its placeholders, for the dialects whose placeholders are not
+// numbered, such as the question marks of MySQL. The arguments are repeated
+// if a placeholder is used more than once. An UnsupportedError is returned if
+// the statement uses a feature of PostgreSQL that the dialect does not
+// support. String literals and quoted identifiers are left untouched.
+func rewrite(d Dialect, query string, args []interface{}) (string, []interface{}, error) {
+	var (
+		buf          bytes.Buffer
+		placeholders []int
+	)
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(query[i+1:], c)
+			if end < 0 {
+				end = len(query) - i - 1
+			}
+			buf.WriteString(query[i : i+end+2])
+			i += end + 1
+			continue
+		case c == '$' && i+1 < len(query) && isDigit(query[i+1]):
+			j := i + 1
+			for j < len(query) && isDigit(query[j]) {
+				j++
+			}
+			n, _ := strconv.Atoi(query[i+1 : j])
+			buf.WriteString(d.Placeholder(n))
+			placeholders = append(placeholders, n)
+			i = j - 1
+			continue
+		case isIdentifier(c) && (i == 0 || !isIdentifier(query[i-1])):
+			j := i
+			for j < len(query) && isIdentifier(query[j]) {
+				j++
+			}
+			word := query[i:j]
+			if f, ok := postgresKeywords[strings.ToUpper(word)]; ok && !d.Supports(f) {
+				return "", nil, &UnsupportedError{d.Name(), f}
+			}
+			buf.WriteString(word)
+			i = j - 1
+			continue
+		}
+
+		for _, op := range postgresOperators {
+			if strings.HasPrefix(query[i:], op.token) && !d.Supports(op.feature) {
+				return "", nil, &UnsupportedError{d.Name(), op.feature}
+			}
+		}
+		buf.WriteByte(c)
+	}
+
+	if d.Placeholder(1) != d.Placeholder(2) || inOrder(placeholders) {
+		return buf.String(), args, nil
+	}
+
+	result := make([]interface{}, len(placeholders))
+	for i, n := range placeholders {
+		if n < 1 || n > len(args) {
+			return "", nil, fmt.Errorf("kallax: there is no argument for the placeholder $%d of the statement for %s", n, d.Name())
+		}
+		result[i] = args[n-1]
+	}
+	return buf.String(), result, nil
+}
+
+// inOrder reports whether the given numbers of the placeholders of a
+// statement are the ones from 1 in order, so its arguments are already in the
+// order of its placeholders.
+func inOrder(placeholders []int) bool {
+	for i, n := range placeholders {
+		if n != i+1 {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentifier(c byte) bool {
+	return c == '_' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// dialectRunner is a database runner that rewrites all the statements for
+// the dialect of the store before running them.
+type dialectRunner struct {
+	squirrel.DBProxyContext
+	dialect Dialect
+}
+
+func (r *dialectRunner) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return r.ExecContext(context.Background(), query, args...)
+}
+
+func (r *dialectRunner) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	query, args, err := rewrite(r.dialect, query, args)
+	if err != nil {
+		return nil, err
+	}
+	return runnerContext(r.DBProxyContext).ExecContext(ctx, query, args...)
+}
+
+func (r *dialectRunner) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return r.QueryContext(context.Background(), query, args...)
+}
+
+func (r *dialectRunner) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	query, args, err := rewrite(r.dialect, query, args)
+	if err != nil {
+		return nil, err
+	}
+	return runnerContext(r.DBProxyContext).QueryContext(ctx, query, args...)
+}
+
+func (r *dialectRunner) QueryRow(query string, args ...interface{}) squirrel.RowScanner {
+	return r.QueryRowContext(context.Background(), query, args...)
+}
+
+func (r *dialectRunner) QueryRowContext(ctx context.Context, query string, args ...interface{}) squirrel.RowScanner {
+	query, args, err := rewrite(r.dialect, query, args)
+	if err != nil {
+		return errRow{err}
+	}
+	return runnerContext(r.DBProxyContext).QueryRowContext(ctx, query, args...)
+}
+
+func (r *dialectRunner) Prepare(query string) (*sql.Stmt, error) {
+	query, err := rewritePrepared(r.dialect, query)
+	if err != nil {
+		return nil, err
+	}
+	return r.DBProxyContext.Prepare(query)
+}
+
+func (r *dialectRunner) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	query, err := rewritePrepared(r.dialect, query)
+	if err != nil {
+		return nil, err
+	}
+	return r.DBProxyContext.PrepareContext(ctx, query)
+}
+
+// rewritePrepared returns the given PostgreSQL statement prepared with the
+// placeholders of the given dialect, see rewrite. As the arguments of a
+// prepared statement can not be reordered, an error is returned if they
+// would need to be.
+func rewritePrepared(d Dialect, query string) (string, error) {
+	query, _, err := rewrite(d, query, nil)
+	if _, ok := err.(*UnsupportedError); err != nil && !ok {
+		return "", fmt.Errorf("kallax: the placeholders of the statements prepared for %s must be used once and in order", d.Name())
+	}
+	return query, err
+}
+
+// errRow is a row that fails with the given error when it's scanned.
+type errRow struct {
+	err error
+}
+
+func (r errRow) Scan(...interface{}) error {
+	return r.err
+}
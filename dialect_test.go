@@ -0,0 +1,310 @@
+package kallax
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewrite(t *testing.T) {
+	cases := []struct {
+		query    string
+		expected string
+		feature  Feature
+	}{
+		{"SELECT * FROM foo WHERE a = $1 AND b = $2", "SELECT * FROM foo WHERE a = ?1 AND b = ?2", ""},
+		{"UPDATE foo SET a=$1,b=$2 WHERE id=$10", "UPDATE foo SET a=?1,b=?2 WHERE id=?10", ""},
+		{"INSERT INTO foo (a) VALUES ($1) RETURNING id", "INSERT INTO foo (a) VALUES (?1) RETURNING id", ""},
+		{"SELECT '$1 :: @>', \"any\" FROM foo", "SELECT '$1 :: @>', \"any\" FROM foo", ""},
+		{"SELECT 'it''s' FROM foo WHERE a = $1", "SELECT 'it''s' FROM foo WHERE a = ?1", ""},
+		{"SELECT many FROM foo", "SELECT many FROM foo", ""},
+		{"SELECT * FROM foo WHERE a = $1::text", "", FeatureCasts},
+		{"SELECT * FROM foo WHERE a @> $1", "", FeatureContainment},
+		{"SELECT * FROM foo WHERE a && $1", "", FeatureContainment},
+		{"SELECT * FROM foo WHERE a ?| $1", "", FeatureJSONKeys},
+		{"SELECT * FROM foo WHERE a = ANY($1)", "", FeatureArrays},
+		{"SELECT * FROM foo WHERE a ilike $1", "", FeatureILike},
+		{"SELECT * FROM foo WHERE a SIMILAR TO $1", "", FeatureSimilarTo},
+		{"SELECT * FROM foo WHERE a ~ $1", "", FeatureRegex},
+		{"SELECT * FROM foo WHERE a @@ $1", "", FeatureFullTextSearch},
+	}
+
+	for _, c := range cases {
+		query, _, err := rewrite(SQLite, c.query, nil)
+		if c.feature == "" {
+			require.NoError(t, err, c.query)
+			require.Equal(t, c.expected, query, c.query)
+			continue
+		}
+
+		require.Equal(t, &UnsupportedError{"sqlite", c.feature}, err, c.query)
+	}
+}
+
+func TestUnsupportedError(t *testing.T) {
+	err := &UnsupportedError{"sqlite", FeatureCasts}
+	require.Equal(t, "kallax: type casts are not supported by the sqlite dialect", err.Error())
+}
+
+func TestDialectOf(t *testing.T) {
+	require.Equal(t, Postgres, DialectOf(nil))
+
+	db, err := openTestDB()
+	require.NoError(t, err)
+	defer db.Close()
+	require.Equal(t, Postgres, DialectOf(db))
+	require.Equal(t, Postgres, NewStore(db).Dialect())
+}
+
+func TestStoreWithDialect(t *testing.T) {
+	db, err := sql.Open("kallax_recording", "")
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := NewStore(db).WithDialect(SQLite)
+	require.Equal(t, SQLite, store.Dialect())
+	require.Equal(t, SQLite, store.Debug().Dialect())
+	require.Equal(t, SQLite, store.DisableCacher().Dialect())
+
+	recordedQueries = nil
+	q := NewBaseQuery(ModelSchema)
+	q.Where(Eq(f("name"), "foo"))
+	_, err = store.Find(q)
+	require.NoError(t, err)
+
+	_, err = store.RawExec("DELETE FROM model WHERE id = $1", 1)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"SELECT __model.id, __model.name, __model.email, __model.age FROM model __model WHERE __model.name = ?1",
+		"DELETE FROM model WHERE id = ?1",
+	}, recordedQueries)
+
+	q = NewBaseQuery(ModelSchema)
+	q.Where(ArrayContains(f("name"), "foo"))
+	_, err = store.Find(q)
+	require.Equal(t, &UnsupportedError{"sqlite", FeatureContainment}, err)
+
+	var count int64
+	_, err = store.RawQuery("SELECT a::text FROM foo")
+	require.Equal(t, &UnsupportedError{"sqlite", FeatureCasts}, err)
+	require.Equal(t, &UnsupportedError{"sqlite", FeatureCasts}, store.runner.QueryRow("SELECT $1::int").Scan(&count))
+}
+
+func TestRewrite_MySQL(t *testing.T) {
+	query, args, err := rewrite(MySQL, "UPDATE foo SET a=$1,b=$2 WHERE id=$10 AND c = '$3'", []interface{}{1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+	require.NoError(t, err)
+	require.Equal(t, "UPDATE foo SET a=?,b=? WHERE id=? AND c = '$3'", query)
+	require.Equal(t, []interface{}{1, 2, 10}, args)
+
+	_, _, err = rewrite(MySQL, "INSERT INTO foo (a) VALUES ($1) RETURNING id", nil)
+	require.Equal(t, &UnsupportedError{"mysql", FeatureReturning}, err)
+
+	_, _, err = rewrite(MySQL, "SELECT * FROM foo WHERE a ?& $1", nil)
+	require.Equal(t, &UnsupportedError{"mysql", FeatureJSONKeys}, err)
+
+	query, args, err = rewrite(MySQL, "SELECT * FROM foo WHERE a = $1 AND b = $2", []interface{}{1, 2})
+	require.NoError(t, err)
+	require.Equal(t, "SELECT * FROM foo WHERE a = ? AND b = ?", query)
+	require.Equal(t, []interface{}{1, 2}, args)
+
+	query, args, err = rewrite(MySQL, "SELECT * FROM foo WHERE (a = $1 OR b = $1) AND c = $2 AND d > $3 AND e < $2", []interface{}{1, 2, 3})
+	require.NoError(t, err)
+	require.Equal(t, "SELECT * FROM foo WHERE (a = ? OR b = ?) AND c = ? AND d > ? AND e < ?", query)
+	require.Equal(t, []interface{}{1, 1, 2, 3, 2}, args)
+
+	_, args, err = rewrite(SQLite, "SELECT * FROM foo WHERE a = $2 OR b = $1", []interface{}{1, 2})
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{1, 2}, args)
+
+	_, _, err = rewrite(MySQL, "SELECT * FROM foo WHERE a = $2", []interface{}{1})
+	require.EqualError(t, err, "kallax: there is no argument for the placeholder $2 of the statement for mysql")
+
+	_, err = rewritePrepared(MySQL, "SELECT * FROM foo WHERE a = $1 OR b = $1")
+	require.EqualError(t, err, "kallax: the placeholders of the statements prepared for mysql must be used once and in order")
+	_, err = rewritePrepared(MySQL, "INSERT INTO foo (a) VALUES ($1) RETURNING id")
+	require.Equal(t, &UnsupportedError{"mysql", FeatureReturning}, err)
+
+	db, err := sql.Open("kallax_recording", "")
+	require.NoError(t, err)
+	defer db.Close()
+
+	recordedArgs = nil
+	store := NewStore(db).WithDialect(MySQL)
+	_, err = store.RawExec("UPDATE foo SET a = $2 WHERE b = $1 OR c = $1", "x", "y")
+	require.NoError(t, err)
+	require.Equal(t, [][]driver.Value{{"y", "x", "x"}}, recordedArgs)
+}
+
+func TestOnConflictUpdate(t *testing.T) {
+	cases := []struct {
+		dialect  Dialect
+		update   []string
+		expected string
+	}{
+		{Postgres, []string{"a", "b"}, " ON CONFLICT (id) DO UPDATE SET a = EXCLUDED.a, b = EXCLUDED.b"},
+		{CockroachDB, nil, " ON CONFLICT (id) DO NOTHING"},
+		{MySQL, []string{"a", "b"}, " ON DUPLICATE KEY UPDATE a = VALUES(a), b = VALUES(b)"},
+		{MySQL, nil, " ON DUPLICATE KEY UPDATE id = id"},
+	}
+
+	for _, c := range cases {
+		require.Equal(t, c.expected, c.dialect.OnConflictUpdate([]string{"id"}, c.update), c.dialect.Name())
+	}
+}
+
+func TestInsert_LastInsertID(t *testing.T) {
+	db, err := sql.Open("kallax_recording", "")
+	require.NoError(t, err)
+	defer db.Close()
+
+	recordedQueries = nil
+	lastInsertID = 42
+	defer func() { lastInsertID = 0 }()
+
+	m := newModel("foo", "foo@bar.baz", 1)
+	require.NoError(t, NewStore(db).WithDialect(MySQL).Insert(ModelSchema, m))
+	require.Equal(t, int64(42), m.ID)
+	require.True(t, m.IsPersisted())
+	require.Equal(t, []string{"INSERT INTO model (name,email,age) VALUES (?,?,?)"}, recordedQueries)
+}
+
+func TestCockroachDBRetryable(t *testing.T) {
+	r, ok := CockroachDB.(Retrier)
+	require.True(t, ok)
+	require.True(t, r.Retryable(&pq.Error{Code: "40001"}))
+	require.False(t, r.Retryable(&pq.Error{Code: "23505"}))
+	require.False(t, r.Retryable(io.EOF))
+
+	_, ok = Postgres.(Retrier)
+	require.False(t, ok)
+}
+
+func TestTransaction_Retry(t *testing.T) {
+	db, err := sql.Open("kallax_recording", "")
+	require.NoError(t, err)
+	defer db.Close()
+
+	retryable := &pq.Error{Code: "40001", Message: "restart transaction"}
+	cases := []struct {
+		dialect  Dialect
+		failures int
+		attempts int
+		err      string
+	}{
+		{CockroachDB, 2, 3, ""},
+		{CockroachDB, 20, maxTransactionAttempts, "kallax: unable to commit transaction: pq: restart transaction (40001)"},
+		{Postgres, 2, 1, "kallax: unable to commit transaction: pq: restart transaction (40001)"},
+	}
+
+	for _, c := range cases {
+		commitErrors = nil
+		for i := 0; i < c.failures; i++ {
+			commitErrors = append(commitErrors, retryable)
+		}
+
+		var attempts int
+		err := NewStore(db).WithDialect(c.dialect).Transaction(func(*Store) error {
+			attempts++
+			return nil
+		})
+
+		require.Equal(t, c.attempts, attempts, c.dialect.Name())
+		if c.err == "" {
+			require.NoError(t, err)
+		} else {
+			require.EqualError(t, err, c.err)
+		}
+	}
+	commitErrors = nil
+}
+
+// recordedQueries are the statements prepared with the kallax_recording
+// driver, which runs no statement at all.
+var recordedQueries []string
+
+// recordedDSNs are the connection strings of the databases the statements
+// of recordedQueries are prepared in.
+var recordedDSNs []string
+
+func init() {
+	sql.Register("kallax_recording", recordingDriver{})
+}
+
+type recordingDriver struct{}
+
+func (recordingDriver) Open(dsn string) (driver.Conn, error) { return recordingConn{dsn}, nil }
+
+type recordingConn struct {
+	dsn string
+}
+
+func (c recordingConn) Prepare(query string) (driver.Stmt, error) {
+	recordedQueries = append(recordedQueries, query)
+	recordedDSNs = append(recordedDSNs, c.dsn)
+	return recordingStmt{}, nil
+}
+
+func (recordingConn) Close() error              { return nil }
+func (recordingConn) Begin() (driver.Tx, error) { return recordingTx{}, nil }
+
+// recordedTxOptions are the options of the transactions begun with the
+// kallax_recording driver.
+var recordedTxOptions []driver.TxOptions
+
+func (recordingConn) BeginTx(_ context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	recordedTxOptions = append(recordedTxOptions, opts)
+	return recordingTx{}, nil
+}
+
+// commitErrors are the errors returned by the next commits of transactions
+// of the kallax_recording driver.
+var commitErrors []error
+
+type recordingTx struct{}
+
+func (recordingTx) Rollback() error { return nil }
+
+func (recordingTx) Commit() error {
+	if len(commitErrors) == 0 {
+		return nil
+	}
+
+	err := commitErrors[0]
+	commitErrors = commitErrors[1:]
+	return err
+}
+
+type recordingStmt struct{}
+
+func (recordingStmt) Close() error                              { return nil }
+func (recordingStmt) NumInput() int                             { return -1 }
+func (recordingStmt) Query([]driver.Value) (driver.Rows, error) { return recordingRows{}, nil }
+
+// recordedArgs are the arguments of the statements executed with the
+// kallax_recording driver.
+var recordedArgs [][]driver.Value
+
+func (recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	recordedArgs = append(recordedArgs, args)
+	return recordingResult{}, nil
+}
+
+// lastInsertID is the ID of the last inserted row reported by the
+// kallax_recording driver.
+var lastInsertID int64
+
+type recordingResult struct{}
+
+func (recordingResult) LastInsertId() (int64, error) { return lastInsertID, nil }
+func (recordingResult) RowsAffected() (int64, error) { return 0, nil }
+
+type recordingRows struct{}
+
+func (recordingRows) Columns() []string         { return nil }
+func (recordingRows) Close() error              { return nil }
+func (recordingRows) Next([]driver.Value) error { return io.EOF }
@@ -0,0 +1,162 @@
+package kallax
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/lib/pq"
+)
+
+// PostgresError is an error reported by PostgreSQL, as it is returned by any
+// of its drivers.
+type PostgresError struct {
+	// Code is the SQLSTATE code of the error, such as 23505.
+	Code string
+	// Message is the primary message of the error.
+	Message string
+	// Table is the name of the table of the error, if any.
+	Table string
+	// Constraint is the name of the constraint of the error, if any.
+	Constraint string
+}
+
+// Driver is the code of kallax that depends on the database/sql driver used
+// to connect to PostgreSQL. Arrays are encoded and decoded in their text
+// representation, which all the drivers support, so they do not depend on
+// it.
+type Driver interface {
+	// Name returns the name of the driver.
+	Name() string
+	// Supports reports whether the driver supports the given feature of
+	// PostgreSQL, such as FeatureCopy, which requires COPY statements to be
+	// run with prepared statements.
+	Supports(Feature) bool
+	// PostgresError returns the error reported by PostgreSQL of the given
+	// error returned by the driver, or false if it's not one of its errors.
+	PostgresError(err error) (*PostgresError, bool)
+}
+
+var (
+	// PQ is the driver of github.com/lib/pq, which is the default one.
+	PQ Driver = pqDriver{}
+	// PGX is the driver of the database/sql package of github.com/jackc/pgx,
+	// registered as "pgx". COPY statements are not run with prepared
+	// statements, so Import and the BatchInsert copies are not supported.
+	PGX Driver = pgxDriver{}
+)
+
+var (
+	driversMut sync.RWMutex
+	// drivers are the drivers by the path of the package of their
+	// database/sql driver.
+	drivers = map[string]Driver{
+		"github.com/lib/pq":    PQ,
+		"github.com/jackc/pgx": PGX,
+	}
+)
+
+// RegisterDriver registers the given driver for the database/sql drivers of
+// the given package, or any of its subpackages, so DriverOf returns it for
+// their databases and their errors are translated by TranslateError.
+func RegisterDriver(pkg string, driver Driver) {
+	driversMut.Lock()
+	defer driversMut.Unlock()
+	drivers[pkg] = driver
+}
+
+// DriverOf returns the driver of the given database, found by the package of
+// its database/sql driver. PQ is returned for unknown drivers.
+func DriverOf(db *sql.DB) Driver {
+	if db == nil {
+		return PQ
+	}
+
+	t := reflect.TypeOf(db.Driver())
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	driversMut.RLock()
+	defer driversMut.RUnlock()
+	for pkg, driver := range drivers {
+		if t.PkgPath() == pkg || strings.HasPrefix(t.PkgPath(), pkg+"/") {
+			return driver
+		}
+	}
+	return PQ
+}
+
+// postgresError returns the error reported by PostgreSQL of the given error
+// returned by any of the registered drivers.
+func postgresError(err error) (*PostgresError, bool) {
+	if err == nil {
+		return nil, false
+	}
+
+	driversMut.RLock()
+	defer driversMut.RUnlock()
+	for _, driver := range drivers {
+		if pgErr, ok := driver.PostgresError(err); ok {
+			return pgErr, true
+		}
+	}
+	return nil, false
+}
+
+type pqDriver struct{}
+
+func (pqDriver) Name() string          { return "pq" }
+func (pqDriver) Supports(Feature) bool { return true }
+
+func (pqDriver) PostgresError(err error) (*PostgresError, bool) {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return nil, false
+	}
+
+	return &PostgresError{
+		Code:       string(pqErr.Code),
+		Message:    pqErr.Message,
+		Table:      pqErr.Table,
+		Constraint: pqErr.Constraint,
+	}, true
+}
+
+type pgxDriver struct{}
+
+func (pgxDriver) Name() string { return "pgx" }
+
+func (pgxDriver) Supports(f Feature) bool {
+	return f != FeatureCopy
+}
+
+// PostgresError returns the fields of a *pgconn.PgError, which are read
+// with reflection so kallax does not depend on pgx.
+func (pgxDriver) PostgresError(err error) (*PostgresError, bool) {
+	var sqlErr interface{ SQLState() string }
+	if !errors.As(err, &sqlErr) {
+		return nil, false
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(sqlErr))
+	if v.Kind() != reflect.Struct || !strings.HasPrefix(v.Type().PkgPath(), "github.com/jackc/") {
+		return nil, false
+	}
+
+	field := func(name string) string {
+		if f := v.FieldByName(name); f.Kind() == reflect.String {
+			return f.String()
+		}
+		return ""
+	}
+
+	return &PostgresError{
+		Code:       sqlErr.SQLState(),
+		Message:    field("Message"),
+		Table:      field("TableName"),
+		Constraint: field("ConstraintName"),
+	}, true
+}
@@ -0,0 +1,82 @@
+package kallax
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+type testDriverError struct {
+	code, constraint string
+}
+
+func (e *testDriverError) Error() string { return "test: " + e.code }
+
+type testDriver struct{}
+
+func (testDriver) Name() string            { return "test" }
+func (testDriver) Supports(f Feature) bool { return f != FeatureCopy }
+
+func (testDriver) PostgresError(err error) (*PostgresError, bool) {
+	var testErr *testDriverError
+	if !errors.As(err, &testErr) {
+		return nil, false
+	}
+	return &PostgresError{Code: testErr.code, Constraint: testErr.constraint}, true
+}
+
+func TestDriverOf(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	r.Equal(PQ, DriverOf(nil))
+	r.Equal(PQ, DriverOf(db))
+	r.Equal(PQ, NewStore(db).Driver())
+
+	RegisterDriver("gopkg.in/src-d/go-kallax.v1", testDriver{})
+	defer delete(drivers, "gopkg.in/src-d/go-kallax.v1")
+
+	store := NewStore(db)
+	r.Equal(testDriver{}, DriverOf(db))
+	r.Equal(testDriver{}, store.Driver())
+	r.NoError(store.Transaction(func(s *Store) error {
+		r.Equal(testDriver{}, s.Driver())
+		return nil
+	}))
+
+	_, err = store.Import(ModelSchema, strings.NewReader("name\nfoo\n"), CSV, ImportOptions{})
+	r.Equal(&UnsupportedError{"test", FeatureCopy}, err)
+
+	translated := TranslateError(fmt.Errorf("insert: %w", &testDriverError{"23505", "model_email_key"}))
+	var cerr *ConstraintError
+	r.True(errors.As(translated, &cerr))
+	r.Equal(UniqueConstraint, cerr.Kind)
+	r.Equal("model_email_key", cerr.Constraint)
+	r.True(CockroachDB.(Retrier).Retryable(&testDriverError{code: "40001"}))
+}
+
+func TestPostgresError(t *testing.T) {
+	r := require.New(t)
+	pgErr, ok := postgresError(fmt.Errorf("insert: %w", &pq.Error{
+		Code:       "23505",
+		Message:    "duplicate key",
+		Table:      "model",
+		Constraint: "model_email_key",
+	}))
+	r.True(ok)
+	r.Equal(&PostgresError{"23505", "duplicate key", "model", "model_email_key"}, pgErr)
+
+	_, ok = postgresError(errors.New("foo"))
+	r.False(ok)
+	_, ok = PGX.PostgresError(&pq.Error{Code: "23505"})
+	r.False(ok)
+	r.False(PGX.Supports(FeatureCopy))
+	r.True(PQ.Supports(FeatureCopy))
+}
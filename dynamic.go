@@ -0,0 +1,189 @@
+package kallax
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+)
+
+// NewDynamicSchema returns a schema, built at runtime, for the given table,
+// primary key and columns, whose records are DynamicRecords. It can be used
+// with the queries and stores like the schemas of the generated models, for
+// the tables that do not have a model. The primary key is always the first
+// column of the schema, so it does not need to be in the given columns.
+func NewDynamicSchema(table, id string, autoIncr bool, columns ...string) *BaseSchema {
+	fields := []SchemaField{NewSchemaField(id)}
+	for _, col := range columns {
+		if col != id {
+			fields = append(fields, NewSchemaField(col))
+		}
+	}
+
+	schema := NewBaseSchema(table, "__"+table, NewSchemaField(id), nil, nil, autoIncr, fields...)
+	schema.constructor = func() Record {
+		return NewDynamicRecord(schema)
+	}
+	return schema
+}
+
+// DynamicRecord is a record that keeps the values of its columns in a map,
+// instead of in the fields of a struct, so it can be used with the schemas
+// built at runtime with NewDynamicSchema. Dynamic records have no
+// relationships.
+type DynamicRecord struct {
+	Model
+	schema Schema
+	values map[string]interface{}
+}
+
+// NewDynamicRecord returns a new record of the given schema that is writable
+// and not persisted, with all its columns set to NULL.
+func NewDynamicRecord(schema Schema) *DynamicRecord {
+	return &DynamicRecord{
+		Model:  NewModel(),
+		schema: schema,
+		values: make(map[string]interface{}),
+	}
+}
+
+// Schema returns the schema of the record.
+func (r *DynamicRecord) Schema() Schema {
+	return r.schema
+}
+
+// Get returns the value of the given column, which is nil if it is NULL or
+// was not set. The values retrieved from the database are the ones returned
+// by the driver.
+func (r *DynamicRecord) Get(col string) interface{} {
+	return r.values[col]
+}
+
+// Set sets the value of the given column, which must be one of the columns
+// of the schema of the record.
+func (r *DynamicRecord) Set(col string, value interface{}) error {
+	if !r.hasColumn(col) {
+		return fmt.Errorf("kallax: column %s is not a column of table %s", col, r.schema.Table())
+	}
+
+	r.values[col] = value
+	return nil
+}
+
+// Values returns the values of all the columns of the record. Changing the
+// returned map does not change the record.
+func (r *DynamicRecord) Values() map[string]interface{} {
+	values := make(map[string]interface{}, len(r.values))
+	for col, v := range r.values {
+		values[col] = v
+	}
+	return values
+}
+
+func (r *DynamicRecord) hasColumn(col string) bool {
+	for _, c := range r.schema.Columns() {
+		if c.String() == col {
+			return true
+		}
+	}
+	return false
+}
+
+// GetID returns the primary key of the record.
+func (r *DynamicRecord) GetID() Identifier {
+	return &dynamicID{r.values[r.schema.ID().String()]}
+}
+
+// GetPrimaryKey returns the values of the columns of the primary key of the
+// record, which has more than one column if it was set with WithPrimaryKey
+// in the schema.
+func (r *DynamicRecord) GetPrimaryKey() []interface{} {
+	cols := r.schema.PrimaryKey()
+	values := make([]interface{}, len(cols))
+	for i, col := range cols {
+		values[i] = r.values[col.String()]
+	}
+	return values
+}
+
+// Value returns the value of the given column.
+func (r *DynamicRecord) Value(col string) (interface{}, error) {
+	if !r.hasColumn(col) {
+		return nil, fmt.Errorf("kallax: column does not exist: %s", col)
+	}
+	return r.values[col], nil
+}
+
+// ColumnAddress returns a sql.Scanner that sets the given column.
+func (r *DynamicRecord) ColumnAddress(col string) (interface{}, error) {
+	if !r.hasColumn(col) {
+		return nil, fmt.Errorf("kallax: column does not exist: %s", col)
+	}
+	return &dynamicColumn{r, col}, nil
+}
+
+// NewRelationshipRecord returns an error, as dynamic records have no
+// relationships.
+func (r *DynamicRecord) NewRelationshipRecord(field string) (Record, error) {
+	return nil, fmt.Errorf("kallax: no relationship found for field %s", field)
+}
+
+// SetRelationship returns an error, as dynamic records have no
+// relationships.
+func (r *DynamicRecord) SetRelationship(field string, _ interface{}) error {
+	return fmt.Errorf("kallax: no relationship found for field %s", field)
+}
+
+// dynamicColumn scans a column of a dynamic record.
+type dynamicColumn struct {
+	record *DynamicRecord
+	col    string
+}
+
+// Scan implements the Scanner interface.
+func (c *dynamicColumn) Scan(src interface{}) error {
+	// the driver may reuse the bytes once the row is scanned
+	if b, ok := src.([]byte); ok {
+		src = append([]byte(nil), b...)
+	}
+
+	c.record.values[c.col] = src
+	return nil
+}
+
+// dynamicID is the identifier of a dynamic record, which wraps the value of
+// its primary key.
+type dynamicID struct {
+	value interface{}
+}
+
+// Scan implements the Scanner interface.
+func (id *dynamicID) Scan(src interface{}) error {
+	id.value = src
+	return nil
+}
+
+// Value implements the Valuer interface.
+func (id dynamicID) Value() (driver.Value, error) {
+	return driver.DefaultParameterConverter.ConvertValue(id.value)
+}
+
+// IsEmpty returns whether the ID is empty or not, that is, whether it is
+// not set or is the zero value of its type.
+func (id dynamicID) IsEmpty() bool {
+	return id.value == nil || reflect.ValueOf(id.value).IsZero()
+}
+
+// Equals reports whether the ID and the given one are equals.
+func (id dynamicID) Equals(other Identifier) bool {
+	v, ok := other.(*dynamicID)
+	if !ok {
+		return false
+	}
+
+	return reflect.DeepEqual(id.value, v.value)
+}
+
+// Raw returns the underlying raw value.
+func (id dynamicID) Raw() interface{} {
+	return id.value
+}
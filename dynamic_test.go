@@ -0,0 +1,98 @@
+package kallax
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var dynamicSchema = NewDynamicSchema("model", "id", true, "name", "email", "age")
+
+func TestNewDynamicSchema(t *testing.T) {
+	r := require.New(t)
+	r.Equal("model", dynamicSchema.Table())
+	r.Equal("__model", dynamicSchema.Alias())
+	r.Equal("id", dynamicSchema.ID().String())
+	r.Equal([]string{"id", "name", "email", "age"}, ColumnNames(dynamicSchema.Columns()))
+	r.True(dynamicSchema.isPrimaryKeyAutoIncrementable())
+
+	record, ok := dynamicSchema.New().(*DynamicRecord)
+	r.True(ok)
+	r.Equal(dynamicSchema, record.Schema())
+	r.False(record.IsPersisted())
+	r.True(record.IsWritable())
+
+	schema := NewDynamicSchema("foo", "id", false, "id", "bar")
+	r.Equal([]string{"id", "bar"}, ColumnNames(schema.Columns()))
+}
+
+func TestDynamicRecord(t *testing.T) {
+	r := require.New(t)
+	record := NewDynamicRecord(dynamicSchema)
+	r.True(record.GetID().IsEmpty())
+
+	r.NoError(record.Set("name", "foo"))
+	r.EqualError(record.Set("foo", 1), "kallax: column foo is not a column of table model")
+	r.Equal("foo", record.Get("name"))
+	r.Nil(record.Get("age"))
+
+	v, err := record.Value("name")
+	r.NoError(err)
+	r.Equal("foo", v)
+	_, err = record.Value("foo")
+	r.Error(err)
+
+	ptr, err := record.ColumnAddress("id")
+	r.NoError(err)
+	r.NoError(ptr.(sql.Scanner).Scan(int64(1)))
+	r.False(record.GetID().IsEmpty())
+	r.True(record.GetID().Equals(&dynamicID{int64(1)}))
+
+	id, err := record.GetID().Value()
+	r.NoError(err)
+	r.Equal(driver.Value(int64(1)), id)
+
+	values := record.Values()
+	values["name"] = "bar"
+	r.Equal(map[string]interface{}{"id": int64(1), "name": "foo"}, record.Values())
+
+	_, err = record.NewRelationshipRecord("rel")
+	r.Error(err)
+	r.Error(record.SetRelationship("rel", nil))
+}
+
+func TestStore_DynamicRecord(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	recordedQueries = nil
+	lastInsertID = 42
+	defer func() { lastInsertID = 0 }()
+
+	store := NewStore(db).WithDialect(MySQL)
+	record := NewDynamicRecord(dynamicSchema)
+	r.NoError(record.Set("name", "foo"))
+	r.NoError(record.Set("email", "foo@bar.baz"))
+	r.NoError(record.Set("age", 1))
+	r.NoError(store.Insert(dynamicSchema, record))
+	r.Equal(int64(42), record.Get("id"))
+	r.True(record.IsPersisted())
+
+	r.NoError(record.Set("age", 2))
+	r.Equal([]string{"age"}, ChangesOf(record).Columns())
+
+	q := NewBaseQuery(dynamicSchema)
+	q.Where(Eq(NewSchemaField("name"), "foo"))
+	rs, err := store.Find(q)
+	r.NoError(err)
+	r.NoError(rs.Close())
+
+	r.Equal([]string{
+		"INSERT INTO model (name,email,age) VALUES (?,?,?)",
+		"SELECT __model.id, __model.name, __model.email, __model.age FROM model __model WHERE __model.name = ?",
+	}, recordedQueries)
+}
@@ -0,0 +1,150 @@
+package kallax
+
+import "sync"
+
+// RecordEvent is the event of a record written by a store with an EventBus,
+// which is published once it's committed.
+type RecordEvent struct {
+	// Operation is the kind of write of the record.
+	Operation ChangeOperation
+	// Schema is the schema of the record.
+	Schema Schema
+	// Record is the written record itself, not a copy, so the handlers must
+	// not modify it and see the changes made to it after it was written.
+	Record Record
+}
+
+// EventHandler is a function that handles the events of the records of an
+// EventBus. Its errors do not undo the writes of the records, which are
+// already committed, and are passed to the OnError function of the bus.
+type EventHandler func(*RecordEvent) error
+
+// Dispatch is the way the handlers of an EventBus are called.
+type Dispatch int
+
+const (
+	// SyncDispatch calls the handler before the method of the store that
+	// wrote or committed the records returns, in the same goroutine.
+	SyncDispatch Dispatch = iota
+	// AsyncDispatch calls the handler in a new goroutine, so the method of the
+	// store does not wait for it. See EventBus.Wait.
+	AsyncDispatch
+)
+
+// EventBusOptions are the options of an EventBus.
+type EventBusOptions struct {
+	// OnError is called with the events whose handlers return an error, and
+	// the error. It is called in the goroutine of the handler, so it must be
+	// safe for concurrent use if there are asynchronous handlers.
+	OnError func(*RecordEvent, error)
+}
+
+type subscription struct {
+	dispatch Dispatch
+	handler  EventHandler
+}
+
+// EventBus passes the events of the records inserted, updated, upserted and
+// deleted by the stores with the bus, see Store.WithEventBus, to the handlers
+// subscribed to their schemas, after they are committed. Unlike the
+// AfterInsert, AfterUpdate, AfterSave and AfterDelete events of the models,
+// the handlers are never called for the writes of transactions rolled back,
+// and their errors do not roll them back, so they can have side effects such
+// as sending emails or publishing messages. The writes of a transaction are
+// published in the order they were made once it's committed, and the ones of
+// stores without a transaction right after they are made. The records
+// written with UpdateWhere, DeleteWhere and the rest of statements by query
+// are not published. An EventBus can be used concurrently.
+type EventBus struct {
+	opts EventBusOptions
+
+	mu       sync.RWMutex
+	handlers map[string][]subscription
+	running  sync.WaitGroup
+}
+
+// NewEventBus returns a new EventBus with no handlers.
+func NewEventBus(opts EventBusOptions) *EventBus {
+	return &EventBus{
+		opts:     opts,
+		handlers: make(map[string][]subscription),
+	}
+}
+
+// Subscribe makes the bus pass the events of the records of the given schema
+// to the given handler, which is called with the given dispatch, after the
+// rest of handlers of the schema.
+func (b *EventBus) Subscribe(schema Schema, dispatch Dispatch, handler EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	table := schema.Table()
+	b.handlers[table] = append(b.handlers[table], subscription{dispatch, handler})
+}
+
+// Wait waits until all the asynchronous handlers being called return, such
+// as before the program exits.
+func (b *EventBus) Wait() {
+	b.running.Wait()
+}
+
+// publish passes the given events to their handlers. Nothing is done if the
+// bus is nil.
+func (b *EventBus) publish(events []*RecordEvent) {
+	if b == nil {
+		return
+	}
+
+	for _, e := range events {
+		b.mu.RLock()
+		subs := append([]subscription(nil), b.handlers[e.Schema.Table()]...)
+		b.mu.RUnlock()
+
+		for _, sub := range subs {
+			if sub.dispatch == AsyncDispatch {
+				b.running.Add(1)
+				go func(e *RecordEvent, handler EventHandler) {
+					defer b.running.Done()
+					b.handle(e, handler)
+				}(e, sub.handler)
+			} else {
+				b.handle(e, sub.handler)
+			}
+		}
+	}
+}
+
+func (b *EventBus) handle(e *RecordEvent, handler EventHandler) {
+	if err := handler(e); err != nil && b.opts.OnError != nil {
+		b.opts.OnError(e, err)
+	}
+}
+
+// WithEventBus returns a new store that publishes the events of the records
+// it inserts, updates, upserts and deletes to the given bus once they are
+// committed. The stores of its transactions use the same bus.
+func (s *Store) WithEventBus(bus *EventBus) *Store {
+	store := s.clone()
+	store.bus = bus
+	return store.init()
+}
+
+// emit publishes the events of the given operation of the given records of
+// the given schema to the bus of the store, if it has one, or adds them to
+// the events published when the transaction of the store is committed.
+func (s *Store) emit(op ChangeOperation, schema Schema, records ...Record) {
+	if s.bus == nil {
+		return
+	}
+
+	events := make([]*RecordEvent, len(records))
+	for i, record := range records {
+		events[i] = &RecordEvent{Operation: op, Schema: schema, Record: record}
+	}
+
+	if tx, ok := s.db.(*txRunner); ok {
+		tx.events = append(tx.events, events...)
+		return
+	}
+	s.bus.publish(events)
+}
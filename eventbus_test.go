@@ -0,0 +1,98 @@
+package kallax
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventBus(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	lastInsertID = 42
+	defer func() { lastInsertID = 0 }()
+
+	var events []*RecordEvent
+	bus := NewEventBus(EventBusOptions{})
+	bus.Subscribe(ModelSchema, SyncDispatch, func(e *RecordEvent) error {
+		events = append(events, e)
+		return nil
+	})
+
+	store := NewStore(db).WithDialect(MySQL).DisableCacher().WithEventBus(bus)
+	m := newModel("foo", "foo@bar.baz", 1)
+	r.NoError(store.Insert(ModelSchema, m))
+	r.Equal([]*RecordEvent{{ChangeInsert, ModelSchema, m}}, events)
+
+	r.NoError(store.HardDelete(ModelSchema, m))
+	r.Len(events, 2)
+	r.Equal(ChangeDelete, events[1].Operation)
+
+	events = nil
+	r.NoError(store.Insert(RelSchema, newRel(m.GetID(), "foo")))
+	r.Empty(events, "handlers of other schemas are not called")
+
+	errInner := errors.New("inner")
+	r.NoError(store.Transaction(func(s *Store) error {
+		r.NoError(s.Insert(ModelSchema, newModel("bar", "bar@bar.baz", 2)))
+		r.NoError(s.Debug().HardDelete(ModelSchema, m))
+
+		r.Equal(errInner, s.Transaction(func(s *Store) error {
+			r.NoError(s.Insert(ModelSchema, newModel("baz", "baz@bar.baz", 3)))
+			return errInner
+		}))
+
+		r.Empty(events, "events are published after the commit")
+		return nil
+	}))
+
+	r.Len(events, 2)
+	r.Equal(ChangeInsert, events[0].Operation)
+	r.Equal("bar", events[0].Record.(*model).Name)
+	r.Equal(ChangeDelete, events[1].Operation)
+
+	events = nil
+	r.Equal(errInner, store.Transaction(func(s *Store) error {
+		r.NoError(s.Insert(ModelSchema, newModel("bar", "bar@bar.baz", 2)))
+		return errInner
+	}))
+	r.Empty(events)
+}
+
+func TestEventBus_Async(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	lastInsertID = 42
+	defer func() { lastInsertID = 0 }()
+
+	errHandler := errors.New("handler")
+	failed := make(chan *RecordEvent, 1)
+	bus := NewEventBus(EventBusOptions{
+		OnError: func(e *RecordEvent, err error) {
+			r.Equal(errHandler, err)
+			failed <- e
+		},
+	})
+
+	release := make(chan struct{})
+	bus.Subscribe(ModelSchema, AsyncDispatch, func(e *RecordEvent) error {
+		<-release
+		return errHandler
+	})
+
+	m := newModel("foo", "foo@bar.baz", 1)
+	store := NewStore(db).WithDialect(MySQL).DisableCacher().WithEventBus(bus)
+	r.NoError(store.Insert(ModelSchema, m), "the store does not wait for the handler")
+
+	close(release)
+	bus.Wait()
+	r.Equal(m, (<-failed).Record)
+}
@@ -0,0 +1,466 @@
+package kallax
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/lann/builder"
+	"github.com/lib/pq"
+)
+
+// DataFormat is the format of the data written by Export and read by Import.
+// Values are always in their text representation in PostgreSQL, which is the
+// one COPY statements use, so the exported rows can be imported back as they
+// were.
+type DataFormat string
+
+const (
+	// CSV is comma-separated values with a header row with the names of the
+	// columns. NULL values are written as empty values, so they can not be
+	// told apart from empty strings.
+	CSV DataFormat = "csv"
+	// NDJSON is newline-delimited JSON, with a JSON object per row whose keys
+	// are the names of the columns and whose values are strings or null.
+	NDJSON DataFormat = "ndjson"
+)
+
+// ImportOptions are the options to import rows with Import.
+type ImportOptions struct {
+	// Columns are the columns of the imported values. If they are given, CSV
+	// data has no header row and its values are in the order of the columns,
+	// and only the given keys of every NDJSON object are imported. Otherwise,
+	// the columns are the ones in the header row of CSV data or the keys of
+	// the first object of NDJSON data.
+	Columns []SchemaField
+	// SkipUnknown makes the columns that are not in the schema of the table
+	// be ignored, instead of failing the import.
+	SkipUnknown bool
+}
+
+// Export writes the rows retrieved by the given query to the given writer in
+// the given format and returns the number of exported rows. Only the
+// selected columns of the queried schema are exported; relationships are
+// not.
+func (s *Store) Export(q Query, w io.Writer, format DataFormat) (int64, error) {
+	enc, err := newRowEncoder(w, format)
+	if err != nil {
+		return 0, err
+	}
+
+	schema := q.Schema()
+	cast := s.Dialect().Supports(FeatureCasts)
+	columns, queryBuilder := s.queryScopes().compile(q)
+	selectBuilder := builder.Set(queryBuilder, "Columns", nil).(squirrel.SelectBuilder)
+	for _, col := range columns {
+		col = schema.Alias() + "." + col
+		if cast {
+			col += "::text"
+		}
+		selectBuilder = selectBuilder.Column(col)
+	}
+
+	if offset := q.GetOffset(); offset > 0 {
+		selectBuilder = selectBuilder.Offset(offset)
+	}
+
+	if limit := q.GetLimit(); limit > 0 {
+		selectBuilder = selectBuilder.Limit(limit)
+	}
+
+	rows, err := selectBuilder.RunWith(s.runner).Query()
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	if err := enc.header(columns); err != nil {
+		return 0, err
+	}
+
+	values := make([]*string, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	var n int64
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return n, err
+		}
+
+		if err := enc.row(columns, values); err != nil {
+			return n, err
+		}
+		n++
+	}
+
+	if err := rows.Err(); err != nil {
+		return n, err
+	}
+	return n, enc.flush()
+}
+
+// Import loads the rows read from the given reader in the given format into
+// the table of the given schema with a COPY statement, and returns the number
+// of imported rows. The columns of the data are mapped to the columns of the
+// schema by name, and the values must be in their text representation in
+// PostgreSQL. The rows are imported in a transaction, so either all of them
+// or none are imported. Only the dialects supporting COPY statements can
+// import rows.
+func (s *Store) Import(schema Schema, r io.Reader, format DataFormat, opts ImportOptions) (int64, error) {
+	if d := s.Dialect(); !d.Supports(FeatureCopy) {
+		return 0, &UnsupportedError{Dialect: d.Name(), Feature: FeatureCopy}
+	}
+
+	if d := s.Driver(); !d.Supports(FeatureCopy) {
+		return 0, &UnsupportedError{Dialect: d.Name(), Feature: FeatureCopy}
+	}
+
+	dec, err := newRowDecoder(schema, r, format, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	var n int64
+	copyRows := func(s *Store) error {
+		columns, err := dec.columns()
+		if err != nil {
+			return err
+		}
+
+		stmt, err := s.db.PrepareContext(s.Context(), copyStatement(schema.Table(), columns))
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for {
+			values, err := dec.row()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return err
+			}
+
+			if _, err := stmt.ExecContext(s.Context(), values...); err != nil {
+				return err
+			}
+			n++
+		}
+
+		if _, err := stmt.ExecContext(s.Context()); err != nil {
+			return err
+		}
+		return stmt.Close()
+	}
+
+	// the transaction is not retried, as the data can not be read again
+	if db, ok := s.db.(*dbRunner); ok {
+		policy := s.transactionPolicy()
+		_, err = s.transaction(db, &policy, nil, copyRows)
+	} else {
+		err = copyRows(s)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	s.invalidate(schema.Table())
+	return n, nil
+}
+
+// copyStatement returns the COPY statement that loads the given columns of
+// the given table.
+func copyStatement(table string, columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = pq.QuoteIdentifier(col)
+	}
+	return fmt.Sprintf("COPY %s (%s) FROM STDIN", pq.QuoteIdentifier(table), strings.Join(quoted, ", "))
+}
+
+func unknownFormatError(format DataFormat) error {
+	return fmt.Errorf("kallax: unknown data format %q", format)
+}
+
+// rowEncoder writes exported rows in a data format.
+type rowEncoder interface {
+	header(columns []string) error
+	row(columns []string, values []*string) error
+	flush() error
+}
+
+func newRowEncoder(w io.Writer, format DataFormat) (rowEncoder, error) {
+	switch format {
+	case CSV:
+		return &csvEncoder{w: csv.NewWriter(w)}, nil
+	case NDJSON:
+		return &ndjsonEncoder{w: w}, nil
+	}
+	return nil, unknownFormatError(format)
+}
+
+type csvEncoder struct {
+	w      *csv.Writer
+	record []string
+}
+
+func (e *csvEncoder) header(columns []string) error {
+	e.record = make([]string, len(columns))
+	return e.w.Write(columns)
+}
+
+func (e *csvEncoder) row(_ []string, values []*string) error {
+	for i, v := range values {
+		e.record[i] = ""
+		if v != nil {
+			e.record[i] = *v
+		}
+	}
+	return e.w.Write(e.record)
+}
+
+func (e *csvEncoder) flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type ndjsonEncoder struct {
+	w   io.Writer
+	buf bytes.Buffer
+}
+
+func (e *ndjsonEncoder) header([]string) error { return nil }
+
+func (e *ndjsonEncoder) row(columns []string, values []*string) error {
+	e.buf.Reset()
+	e.buf.WriteByte('{')
+	for i, col := range columns {
+		if i > 0 {
+			e.buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(col)
+		if err != nil {
+			return err
+		}
+
+		value, err := json.Marshal(values[i])
+		if err != nil {
+			return err
+		}
+
+		e.buf.Write(key)
+		e.buf.WriteByte(':')
+		e.buf.Write(value)
+	}
+	e.buf.WriteString("}\n")
+
+	_, err := e.w.Write(e.buf.Bytes())
+	return err
+}
+
+func (e *ndjsonEncoder) flush() error { return nil }
+
+// rowDecoder reads the rows to import in a data format.
+type rowDecoder interface {
+	// columns returns the columns of the table the values are imported to.
+	columns() ([]string, error)
+	// row returns the values of the next row, or io.EOF if there are no more
+	// rows.
+	row() ([]interface{}, error)
+}
+
+func newRowDecoder(schema Schema, r io.Reader, format DataFormat, opts ImportOptions) (rowDecoder, error) {
+	switch format {
+	case CSV:
+		r := csv.NewReader(r)
+		r.ReuseRecord = true
+		return &csvDecoder{columnMapping: newColumnMapping(schema, opts), r: r}, nil
+	case NDJSON:
+		return &ndjsonDecoder{columnMapping: newColumnMapping(schema, opts), dec: json.NewDecoder(r)}, nil
+	}
+	return nil, unknownFormatError(format)
+}
+
+// columnMapping maps the columns of the imported data to the columns of the
+// schema of the table.
+type columnMapping struct {
+	schema  Schema
+	opts    ImportOptions
+	known   map[string]bool
+	names   []string
+	indexes []int
+}
+
+func newColumnMapping(schema Schema, opts ImportOptions) columnMapping {
+	known := make(map[string]bool)
+	for _, col := range ColumnNames(schema.Columns()) {
+		known[col] = true
+	}
+	return columnMapping{schema: schema, opts: opts, known: known}
+}
+
+// mapColumns maps the given columns of the data, returning an error if any of
+// them is not in the schema and unknown columns are not skipped, or if there
+// are no columns to import.
+func (m *columnMapping) mapColumns(columns []string) error {
+	for i, col := range columns {
+		if !m.known[col] {
+			if m.opts.SkipUnknown {
+				continue
+			}
+			return fmt.Errorf("kallax: column %s is not a column of table %s", col, m.schema.Table())
+		}
+
+		m.names = append(m.names, col)
+		m.indexes = append(m.indexes, i)
+	}
+
+	if len(m.names) == 0 {
+		return fmt.Errorf("kallax: there are no columns to import into table %s", m.schema.Table())
+	}
+	return nil
+}
+
+type csvDecoder struct {
+	columnMapping
+	r *csv.Reader
+}
+
+func (d *csvDecoder) columns() ([]string, error) {
+	if len(d.opts.Columns) > 0 {
+		if err := d.mapColumns(ColumnNames(d.opts.Columns)); err != nil {
+			return nil, err
+		}
+		return d.names, nil
+	}
+
+	header, err := d.r.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("kallax: CSV data to import into table %s has no header", d.schema.Table())
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := d.mapColumns(header); err != nil {
+		return nil, err
+	}
+	return d.names, nil
+}
+
+func (d *csvDecoder) row() ([]interface{}, error) {
+	record, err := d.r.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	if n := len(d.opts.Columns); n > 0 && len(record) != n {
+		return nil, fmt.Errorf("kallax: CSV row has %d values, but %d columns are imported", len(record), n)
+	}
+
+	values := make([]interface{}, len(d.indexes))
+	for i, idx := range d.indexes {
+		if record[idx] != "" {
+			values[i] = record[idx]
+		}
+	}
+	return values, nil
+}
+
+type ndjsonDecoder struct {
+	columnMapping
+	dec   *json.Decoder
+	first map[string]json.RawMessage
+}
+
+func (d *ndjsonDecoder) columns() ([]string, error) {
+	if len(d.opts.Columns) > 0 {
+		if err := d.mapColumns(ColumnNames(d.opts.Columns)); err != nil {
+			return nil, err
+		}
+		return d.names, nil
+	}
+
+	first, err := d.next()
+	if err == io.EOF {
+		return nil, fmt.Errorf("kallax: NDJSON data to import into table %s has no rows", d.schema.Table())
+	} else if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, col := range ColumnNames(d.schema.Columns()) {
+		if _, ok := first[col]; ok {
+			keys = append(keys, col)
+		}
+	}
+
+	if !d.opts.SkipUnknown {
+		for key := range first {
+			if !d.known[key] {
+				return nil, fmt.Errorf("kallax: column %s is not a column of table %s", key, d.schema.Table())
+			}
+		}
+	}
+
+	if err := d.mapColumns(keys); err != nil {
+		return nil, err
+	}
+
+	d.first = first
+	return d.names, nil
+}
+
+func (d *ndjsonDecoder) next() (map[string]json.RawMessage, error) {
+	var obj map[string]json.RawMessage
+	if err := d.dec.Decode(&obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (d *ndjsonDecoder) row() ([]interface{}, error) {
+	obj := d.first
+	d.first = nil
+	if obj == nil {
+		var err error
+		if obj, err = d.next(); err != nil {
+			return nil, err
+		}
+	}
+
+	values := make([]interface{}, len(d.names))
+	for i, col := range d.names {
+		v, err := ndjsonValue(obj[col])
+		if err != nil {
+			return nil, fmt.Errorf("kallax: invalid value of column %s: %s", col, err)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// ndjsonValue returns the value to import for the given JSON value. Strings
+// are imported as they are, objects and arrays as JSON documents, and the
+// rest of the values as their JSON representation.
+func ndjsonValue(raw json.RawMessage) (interface{}, error) {
+	raw = bytes.TrimSpace(raw)
+	switch {
+	case len(raw) == 0, bytes.Equal(raw, []byte("null")):
+		return nil, nil
+	case raw[0] == '"':
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+	return string(raw), nil
+}
@@ -0,0 +1,173 @@
+package kallax
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExport(t *testing.T) {
+	db, err := sql.Open("kallax_recording", "")
+	require.NoError(t, err)
+	defer db.Close()
+
+	cases := []struct {
+		dialect  Dialect
+		format   DataFormat
+		expected string
+	}{
+		{Postgres, CSV, "SELECT __model.id::text, __model.name::text FROM model __model WHERE __model.age > $1 LIMIT 2 OFFSET 1"},
+		{Postgres, NDJSON, "SELECT __model.id::text, __model.name::text FROM model __model WHERE __model.age > $1 LIMIT 2 OFFSET 1"},
+		{MySQL, CSV, "SELECT __model.id, __model.name FROM model __model WHERE __model.age > ? LIMIT 2 OFFSET 1"},
+	}
+
+	for _, c := range cases {
+		recordedQueries = nil
+		q := NewBaseQuery(ModelSchema)
+		q.Select(f("id"), f("name"))
+		q.Where(Gt(f("age"), 1))
+		q.Limit(2)
+		q.Offset(1)
+
+		var buf bytes.Buffer
+		n, err := NewStore(db).WithDialect(c.dialect).Export(q, &buf, c.format)
+		require.NoError(t, err, c.dialect.Name())
+		require.Equal(t, int64(0), n)
+		require.Equal(t, []string{c.expected}, recordedQueries, c.dialect.Name())
+		if c.format == CSV {
+			require.Equal(t, "id,name\n", buf.String())
+		} else {
+			require.Equal(t, "", buf.String())
+		}
+	}
+
+	_, err = NewStore(db).Export(NewBaseQuery(ModelSchema), new(bytes.Buffer), DataFormat("xml"))
+	require.EqualError(t, err, `kallax: unknown data format "xml"`)
+}
+
+func TestRowEncoders(t *testing.T) {
+	columns := []string{"id", "name", "email"}
+	name, quoted := "foo", `say "bar", baz`
+	rows := [][]*string{
+		{&name, &quoted, nil},
+		{&name, &name, &name},
+	}
+
+	cases := []struct {
+		format   DataFormat
+		expected string
+	}{
+		{CSV, "id,name,email\nfoo,\"say \"\"bar\"\", baz\",\nfoo,foo,foo\n"},
+		{NDJSON, `{"id":"foo","name":"say \"bar\", baz","email":null}` + "\n" + `{"id":"foo","name":"foo","email":"foo"}` + "\n"},
+	}
+
+	for _, c := range cases {
+		var buf bytes.Buffer
+		enc, err := newRowEncoder(&buf, c.format)
+		require.NoError(t, err)
+		require.NoError(t, enc.header(columns))
+		for _, row := range rows {
+			require.NoError(t, enc.row(columns, row))
+		}
+		require.NoError(t, enc.flush())
+		require.Equal(t, c.expected, buf.String(), string(c.format))
+	}
+}
+
+func TestImport(t *testing.T) {
+	db, err := sql.Open("kallax_recording", "")
+	require.NoError(t, err)
+	defer db.Close()
+
+	cases := []struct {
+		name    string
+		format  DataFormat
+		data    string
+		opts    ImportOptions
+		columns string
+		args    [][]driver.Value
+	}{
+		{
+			"csv with header",
+			CSV,
+			"name,age\nfoo,1\n,2\n",
+			ImportOptions{},
+			`"name", "age"`,
+			[][]driver.Value{{"foo", "1"}, {nil, "2"}, {}},
+		},
+		{
+			"csv with columns",
+			CSV,
+			"foo,1\n",
+			ImportOptions{Columns: []SchemaField{f("name"), f("age")}},
+			`"name", "age"`,
+			[][]driver.Value{{"foo", "1"}, {}},
+		},
+		{
+			"csv skipping unknown columns",
+			CSV,
+			"name,bar\nfoo,baz\n",
+			ImportOptions{SkipUnknown: true},
+			`"name"`,
+			[][]driver.Value{{"foo"}, {}},
+		},
+		{
+			"ndjson",
+			NDJSON,
+			`{"age": 1, "name": "foo"}` + "\n" + `{"name": null}` + "\n",
+			ImportOptions{},
+			`"name", "age"`,
+			[][]driver.Value{{"foo", "1"}, {nil, nil}, {}},
+		},
+		{
+			"ndjson with columns",
+			NDJSON,
+			`{"age": "1", "name": "foo", "bar": [1, 2]}` + "\n",
+			ImportOptions{Columns: []SchemaField{f("age")}},
+			`"age"`,
+			[][]driver.Value{{"1"}, {}},
+		},
+	}
+
+	for _, c := range cases {
+		recordedQueries, recordedArgs = nil, nil
+		n, err := NewStore(db).Import(ModelSchema, strings.NewReader(c.data), c.format, c.opts)
+		require.NoError(t, err, c.name)
+		require.Equal(t, int64(len(c.args)-1), n, c.name)
+		require.Equal(t, []string{`COPY "model" (` + c.columns + `) FROM STDIN`}, recordedQueries, c.name)
+		require.Equal(t, c.args, recordedArgs, c.name)
+	}
+}
+
+func TestImport_Errors(t *testing.T) {
+	db, err := sql.Open("kallax_recording", "")
+	require.NoError(t, err)
+	defer db.Close()
+
+	cases := []struct {
+		format DataFormat
+		data   string
+		opts   ImportOptions
+		err    string
+	}{
+		{CSV, "name,bar\nfoo,baz\n", ImportOptions{}, "kallax: column bar is not a column of table model"},
+		{CSV, "bar\nbaz\n", ImportOptions{SkipUnknown: true}, "kallax: there are no columns to import into table model"},
+		{CSV, "", ImportOptions{}, "kallax: CSV data to import into table model has no header"},
+		{CSV, "foo,1,2\n", ImportOptions{Columns: []SchemaField{f("name"), f("age")}}, "kallax: CSV row has 3 values, but 2 columns are imported"},
+		{NDJSON, `{"name": "foo", "bar": 1}`, ImportOptions{}, "kallax: column bar is not a column of table model"},
+		{NDJSON, "", ImportOptions{}, "kallax: NDJSON data to import into table model has no rows"},
+		{DataFormat("xml"), "", ImportOptions{}, `kallax: unknown data format "xml"`},
+	}
+
+	for _, c := range cases {
+		_, err := NewStore(db).Import(ModelSchema, strings.NewReader(c.data), c.format, c.opts)
+		require.EqualError(t, err, c.err, c.data)
+	}
+
+	_, err = NewStore(db).WithDialect(SQLite).Import(ModelSchema, strings.NewReader(""), CSV, ImportOptions{})
+	require.EqualError(t, err, "kallax: COPY statements are not supported by the sqlite dialect")
+}
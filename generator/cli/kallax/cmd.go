@@ -22,12 +22,14 @@ func newApp() *cli.App {
 	app := cli.NewApp()
 	app.Name = "kallax"
 	app.Version = version
-	app.Usage = "generate kallax models"
-	app.Flags = cmd.Generate.Flags
-	app.Action = cmd.Generate.Action
+	app.Usage = "generate kallax models and migrations"
+	app.EnableBashCompletion = true
 	app.Commands = cli.Commands{
 		&cmd.Generate,
 		&cmd.Migrate,
+		&cmd.Schema,
+		&cmd.Version,
+		&cmd.Completion,
 	}
 
 	return app
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+var Completion = cli.Command{
+	Name:      "completion",
+	Usage:     "Print the shell completion script for bash or zsh",
+	ArgsUsage: "bash|zsh",
+	Action:    completionAction,
+}
+
+const bashCompletion = `_kallax_bash_autocomplete() {
+	local cur opts
+	COMPREPLY=()
+	cur="${COMP_WORDS[COMP_CWORD]}"
+	opts=$(${COMP_WORDS[@]:0:$COMP_CWORD} --generate-bash-completion)
+	COMPREPLY=($(compgen -W "${opts}" -- ${cur}))
+	return 0
+}
+
+complete -o bashdefault -o default -o nospace -F _kallax_bash_autocomplete kallax
+`
+
+const zshCompletion = `#compdef kallax
+
+_kallax() {
+	local -a opts
+	opts=("${(@f)$(${words[@]:0:#words[@]-1} --generate-bash-completion)}")
+	_describe 'values' opts
+}
+
+compdef _kallax kallax
+`
+
+var completionScripts = map[string]string{
+	"bash": bashCompletion,
+	"zsh":  zshCompletion,
+}
+
+func completionAction(c *cli.Context) error {
+	shell := c.Args().First()
+	script, ok := completionScripts[shell]
+	if !ok {
+		return fmt.Errorf("kallax: no completion script for shell %q, it can only be bash or zsh", shell)
+	}
+
+	fmt.Print(script)
+	return nil
+}
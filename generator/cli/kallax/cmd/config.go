@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	cli "gopkg.in/urfave/cli.v1"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// defaultConfigFile is the name of the config file used when no other file is
+// given with the `--config` flag.
+const defaultConfigFile = "kallax.yml"
+
+// Config is the configuration shared by all the kallax commands. The values
+// of the flags given in the command line take precedence over the ones in the
+// config.
+//
+// An example of config file is:
+//
+//	gen:
+//	  input: ./models
+//	  exclude: [queries.go]
+//	migrations:
+//	  input: [./models, ./billing]
+//	  dir: ./migrations
+//	  dsn: user:pass@localhost:5432/database?sslmode=disable
+//	  dialect: postgres
+//
+// Relative directories are relative to the directory of the config file.
+type Config struct {
+	// Gen contains the defaults of the gen command.
+	Gen GenConfig `yaml:"gen"`
+	// Migrations contains the defaults of the migrate and schema commands.
+	Migrations MigrationsConfig `yaml:"migrations"`
+}
+
+// GenConfig is the configuration of the gen command.
+type GenConfig struct {
+	// Input is the directory of the package with the models.
+	Input string `yaml:"input"`
+	// Output is the name of the generated file.
+	Output string `yaml:"output"`
+	// Exclude is the list of files of the package excluded from generation.
+	Exclude []string `yaml:"exclude"`
+}
+
+// MigrationsConfig is the configuration of the migrate and schema commands.
+type MigrationsConfig struct {
+	// Input is the list of directories of the packages with the models.
+	Input []string `yaml:"input"`
+	// Dir is the directory where the migrations are stored.
+	Dir string `yaml:"dir"`
+	// DSN is the PostgreSQL data source name used to run the migrations.
+	DSN string `yaml:"dsn"`
+	// Dialect is the dialect of the generated migrations and schemas:
+	// postgres or cockroachdb.
+	Dialect string `yaml:"dialect"`
+}
+
+var configFlag = &cli.StringFlag{
+	Name:  "config, c",
+	Value: defaultConfigFile,
+	Usage: "Config file with the defaults of the flags. It's ignored if the default one does not exist.",
+}
+
+var jsonFlag = &cli.BoolFlag{
+	Name:  "json",
+	Usage: "Print the output as JSON",
+}
+
+// readConfig reads the config file at the given path. The relative
+// directories in it are joined with the directory of the file.
+func readConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("kallax: cannot read config file: %s", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("kallax: invalid config file %s: %s", path, err)
+	}
+
+	base := filepath.Dir(path)
+	cfg.Gen.Input = relativeTo(base, cfg.Gen.Input)
+	cfg.Migrations.Dir = relativeTo(base, cfg.Migrations.Dir)
+	for i, dir := range cfg.Migrations.Input {
+		cfg.Migrations.Input[i] = relativeTo(base, dir)
+	}
+
+	return &cfg, nil
+}
+
+func relativeTo(base, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(base, path)
+}
+
+// loadConfig loads the config file given in the `config` flag of the command.
+// If the flag was not given and the default config file does not exist, an
+// empty config is returned.
+func loadConfig(c *cli.Context) (*Config, error) {
+	path := c.String("config")
+	if _, err := os.Stat(path); os.IsNotExist(err) && !isSet(c, "config") {
+		return new(Config), nil
+	}
+
+	return readConfig(path)
+}
+
+// isSet reports whether the flag with the given name, or any of its aliases,
+// was given in the command line.
+func isSet(c *cli.Context, name string) bool {
+	for _, f := range c.Command.Flags {
+		names := strings.Split(f.GetName(), ",")
+		if strings.TrimSpace(names[0]) != name {
+			continue
+		}
+
+		for _, n := range names {
+			if c.IsSet(strings.TrimSpace(n)) {
+				return true
+			}
+		}
+	}
+
+	return c.IsSet(name)
+}
+
+// stringFlag returns the value of the flag with the given name, or the given
+// config value if the flag was not given in the command line.
+func stringFlag(c *cli.Context, name, config string) string {
+	if config == "" || isSet(c, name) {
+		return c.String(name)
+	}
+	return config
+}
+
+// stringSliceFlag returns the values of the flag with the given name, or the
+// given config values if the flag was not given in the command line.
+func stringSliceFlag(c *cli.Context, name string, config []string) []string {
+	if len(config) == 0 || isSet(c, name) {
+		return c.StringSlice(name)
+	}
+	return config
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+const configFixture = `
+gen:
+  input: ./models
+  exclude: [queries.go]
+migrations:
+  input: [./models, /abs/billing]
+  dir: migrations
+  dsn: user:pass@localhost:5432/db
+  dialect: cockroachdb
+`
+
+func TestReadConfig(t *testing.T) {
+	require := require.New(t)
+	dir, err := ioutil.TempDir("", "kallax-config")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, defaultConfigFile)
+	require.NoError(ioutil.WriteFile(path, []byte(configFixture), 0644))
+
+	cfg, err := readConfig(path)
+	require.NoError(err)
+	require.Equal(filepath.Join(dir, "models"), cfg.Gen.Input)
+	require.Equal("", cfg.Gen.Output)
+	require.Equal([]string{"queries.go"}, cfg.Gen.Exclude)
+	require.Equal([]string{filepath.Join(dir, "models"), "/abs/billing"}, cfg.Migrations.Input)
+	require.Equal(filepath.Join(dir, "migrations"), cfg.Migrations.Dir)
+	require.Equal("user:pass@localhost:5432/db", cfg.Migrations.DSN)
+	require.Equal("cockroachdb", cfg.Migrations.Dialect)
+
+	_, err = readConfig(filepath.Join(dir, "missing.yml"))
+	require.Error(err)
+
+	require.NoError(ioutil.WriteFile(path, []byte("gen: [foo"), 0644))
+	_, err = readConfig(path)
+	require.Error(err)
+}
+
+func TestFlagPrecedence(t *testing.T) {
+	require := require.New(t)
+	command := cli.Command{
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "output", Value: "kallax.go"},
+			&cli.StringSliceFlag{Name: "exclude, e"},
+		},
+	}
+
+	context := func(args ...string) *cli.Context {
+		set := flag.NewFlagSet("test", flag.ContinueOnError)
+		for _, f := range command.Flags {
+			f.Apply(set)
+		}
+		require.NoError(set.Parse(args))
+
+		c := cli.NewContext(nil, set, nil)
+		c.Command = command
+		return c
+	}
+
+	c := context()
+	require.Equal("kallax.go", stringFlag(c, "output", ""))
+	require.Equal("models.go", stringFlag(c, "output", "models.go"))
+	require.Equal([]string{"a.go"}, stringSliceFlag(c, "exclude", []string{"a.go"}))
+
+	c = context("--output", "foo.go", "-e", "b.go")
+	require.True(isSet(c, "exclude"))
+	require.Equal("foo.go", stringFlag(c, "output", "models.go"))
+	require.Equal([]string{"b.go"}, stringSliceFlag(c, "exclude", []string{"a.go"}))
+}
@@ -29,13 +29,96 @@ var Generate = cli.Command{
 			Name:  "exclude, e",
 			Usage: "List of excluded files from the package when generating the code for your models. Use this to exclude files in your package that uses the generated code. You can use this flag as many times as you want.",
 		},
+		&cli.BoolFlag{
+			Name:  "sqlmock",
+			Usage: "Generate also, in the test file " + sqlmockOutput + ", the helpers to set up go-sqlmock expectations of the statements run by the stores",
+		},
+		&cli.BoolFlag{
+			Name:  "bench",
+			Usage: "Generate also, in the test file " + benchmarkOutput + ", benchmarks of the stores. They are run with `go test -bench` against the database in the environment variable KALLAX_TEST_DSN",
+		},
+		&cli.BoolFlag{
+			Name:  "grpc",
+			Usage: "Generate also the Protocol Buffers definition of a gRPC service per model in " + protoOutput + ", and its implementation backed by the stores in " + grpcOutput + ". The Go code of the messages and services must be generated with protoc in the kallaxpb subpackage",
+		},
+		&cli.BoolFlag{
+			Name:  "mock",
+			Usage: "Generate also, in " + mockOutput + ", an in-memory mock store per model with the methods of its store, to use it instead in the tests that should not need a database",
+		},
+		&cli.BoolFlag{
+			Name:  "factories",
+			Usage: "Generate also, in " + factoryOutput + ", a factory per model that builds records with fake values in the fields that need one, and inserts them with a store, to use it in the tests",
+		},
+		&cli.BoolFlag{
+			Name:  "check",
+			Usage: "Do not write any file and fail, printing the differences, if the generated files or the lock of the migrations are out of date with the models. Use it in your build to enforce that the generated code is up to date",
+		},
+		&cli.StringFlag{
+			Name:  "migrations",
+			Usage: "Directory of the migrations whose lock is checked with `check`. By default, the one in the configuration file, if any",
+		},
+		configFlag,
+		jsonFlag,
 	},
 }
 
+// sqlmockOutput is the name of the file with the go-sqlmock helpers. It's a
+// test file, so go-sqlmock is only a dependency of the tests of the package.
+const sqlmockOutput = "kallax_sqlmock_test.go"
+
+// benchmarkOutput is the name of the file with the benchmarks of the stores.
+const benchmarkOutput = "kallax_bench_test.go"
+
+// protoOutput is the name of the file with the Protocol Buffers definition of
+// the gRPC services of the models.
+const protoOutput = "kallax.proto"
+
+// grpcOutput is the name of the file with the implementation of the gRPC
+// services of the models. It's never processed, as it depends on the code
+// generated by protoc.
+const grpcOutput = "kallax_grpc.go"
+
+// mockOutput is the name of the file with the mock stores of the models. It's
+// not a test file, so the tests of other packages can use them, and it's
+// never processed, as it depends on the generated code.
+const mockOutput = "kallax_mock.go"
+
+// factoryOutput is the name of the file with the factories of records of the
+// models. As the mock stores, it's not a test file and it's never processed.
+const factoryOutput = "factories_kallax.go"
+
+// generateResult is the output of the gen command with the `json` flag.
+type generateResult struct {
+	Package   string   `json:"package"`
+	Output    string   `json:"output"`
+	SQLMock   string   `json:"sqlmock,omitempty"`
+	Benchmark string   `json:"benchmark,omitempty"`
+	Proto     string   `json:"proto,omitempty"`
+	GRPC      string   `json:"grpc,omitempty"`
+	Mock      string   `json:"mock,omitempty"`
+	Factories string   `json:"factories,omitempty"`
+	Models    []string `json:"models"`
+}
+
 func generateAction(c *cli.Context) error {
-	input := c.String("input")
-	output := c.String("output")
-	excluded := c.StringSlice("exclude")
+	cfg, err := loadConfig(c)
+	if err != nil {
+		return err
+	}
+
+	input := stringFlag(c, "input", cfg.Gen.Input)
+	output := stringFlag(c, "output", cfg.Gen.Output)
+	excluded := stringSliceFlag(c, "exclude", cfg.Gen.Exclude)
+	asJSON := c.Bool("json")
+	if c.Bool("grpc") {
+		excluded = append(excluded, grpcOutput)
+	}
+	if c.Bool("mock") {
+		excluded = append(excluded, mockOutput)
+	}
+	if c.Bool("factories") {
+		excluded = append(excluded, factoryOutput)
+	}
 
 	ok, err := isDirectory(input)
 	if err != nil {
@@ -46,29 +129,247 @@ func generateAction(c *cli.Context) error {
 		return fmt.Errorf("kallax: Input path should be a directory %s", input)
 	}
 
+	if c.Bool("check") {
+		migrations := stringFlag(c, "migrations", cfg.Migrations.Dir)
+		return checkGenerated(c, cfg, input, output, excluded, migrations, asJSON)
+	}
+
 	var foundPrevious bool
 	if _, err = os.Stat(output); err == nil {
 		foundPrevious = true
-		fmt.Fprintf(os.Stderr, "NOTE: Previous generated file `%s` found, renaming to `%s`\n", output, output+".old")
+		if !asJSON {
+			fmt.Fprintf(os.Stderr, "NOTE: Previous generated file `%s` found, renaming to `%s`\n", output, output+".old")
+		}
 		err = os.Rename(output, output+".old")
 	}
 
 	p := generator.NewProcessor(input, excluded)
+	if asJSON {
+		p.Silent()
+	}
 	pkg, err := p.Do()
 	if err != nil {
 		return err
 	}
 
-	gen := generator.NewGenerator(filepath.Join(input, output))
+	file := filepath.Join(input, output)
+	gen := generator.NewGenerator(file)
 	err = gen.Generate(pkg)
 	if err != nil {
 		return err
 	}
 
+	var sqlmockFile string
+	if c.Bool("sqlmock") {
+		sqlmockFile = filepath.Join(input, sqlmockOutput)
+		if err := generator.NewSQLMockGenerator(sqlmockFile).Generate(pkg); err != nil {
+			return err
+		}
+	}
+
+	var benchmarkFile string
+	if c.Bool("bench") {
+		benchmarkFile = filepath.Join(input, benchmarkOutput)
+		if err := generator.NewBenchmarkGenerator(benchmarkFile).Generate(pkg); err != nil {
+			return err
+		}
+	}
+
+	var protoFile, grpcFile string
+	if c.Bool("grpc") {
+		protoFile = filepath.Join(input, protoOutput)
+		if err := generator.NewProtoGenerator(protoFile).Generate(pkg); err != nil {
+			return err
+		}
+
+		grpcFile = filepath.Join(input, grpcOutput)
+		if err := generator.NewGRPCGenerator(grpcFile).Generate(pkg); err != nil {
+			return err
+		}
+	}
+
+	var mockFile string
+	if c.Bool("mock") {
+		mockFile = filepath.Join(input, mockOutput)
+		if err := generator.NewMockGenerator(mockFile).Generate(pkg); err != nil {
+			return err
+		}
+	}
+
+	var factoryFile string
+	if c.Bool("factories") {
+		factoryFile = filepath.Join(input, factoryOutput)
+		if err := generator.NewFactoryGenerator(factoryFile).Generate(pkg); err != nil {
+			return err
+		}
+	}
+
 	if foundPrevious {
-		fmt.Fprintf(os.Stderr, "NOTE: Generation succeded, removing `%s`\n", output+".old")
+		if !asJSON {
+			fmt.Fprintf(os.Stderr, "NOTE: Generation succeded, removing `%s`\n", output+".old")
+		}
 		os.Remove(output + ".old")
 	}
 
+	if asJSON {
+		result := generateResult{Package: pkg.Name, Output: file, SQLMock: sqlmockFile, Benchmark: benchmarkFile, Proto: protoFile, GRPC: grpcFile, Mock: mockFile, Factories: factoryFile, Models: []string{}}
+		for _, m := range pkg.Models {
+			result.Models = append(result.Models, m.Name)
+		}
+		return printJSON(result)
+	}
+
 	return nil
 }
+
+// checkResult is the output of the gen command with the `check` and `json`
+// flags.
+type checkResult struct {
+	Package string      `json:"package"`
+	Stale   bool        `json:"stale"`
+	Files   []checkFile `json:"files"`
+	// Migrations are the changes of the schema that are not in the lock of
+	// the migrations.
+	Migrations []string `json:"migrations,omitempty"`
+}
+
+// checkFile is a generated file that is out of date.
+type checkFile struct {
+	File string `json:"file"`
+	Diff string `json:"diff"`
+}
+
+// checkGenerated generates the code of the package in memory and compares
+// it with the generated files, and the schema of the models with the lock of
+// the migrations in the given directory, if any. An error is returned if any
+// of them is out of date.
+func checkGenerated(c *cli.Context, cfg *Config, input, output string, excluded []string, migrations string, asJSON bool) error {
+	// The generated code may not compile with the current models, so it's
+	// not processed, as the gen command does renaming it.
+	excluded = append(excluded, filepath.Base(output))
+	if c.Bool("sqlmock") {
+		excluded = append(excluded, sqlmockOutput)
+	}
+	if c.Bool("bench") {
+		excluded = append(excluded, benchmarkOutput)
+	}
+
+	p := generator.NewProcessor(input, excluded)
+	if asJSON {
+		p.Silent()
+	}
+	pkg, err := p.Do()
+	if err != nil {
+		return err
+	}
+
+	gens := []*generator.Generator{generator.NewGenerator(filepath.Join(input, output))}
+	names := []string{filepath.Join(input, output)}
+	if c.Bool("sqlmock") {
+		gens = append(gens, generator.NewSQLMockGenerator(filepath.Join(input, sqlmockOutput)))
+		names = append(names, filepath.Join(input, sqlmockOutput))
+	}
+	if c.Bool("bench") {
+		gens = append(gens, generator.NewBenchmarkGenerator(filepath.Join(input, benchmarkOutput)))
+		names = append(names, filepath.Join(input, benchmarkOutput))
+	}
+	if c.Bool("grpc") {
+		gens = append(gens,
+			generator.NewProtoGenerator(filepath.Join(input, protoOutput)),
+			generator.NewGRPCGenerator(filepath.Join(input, grpcOutput)),
+		)
+		names = append(names, filepath.Join(input, protoOutput), filepath.Join(input, grpcOutput))
+	}
+	if c.Bool("mock") {
+		gens = append(gens, generator.NewMockGenerator(filepath.Join(input, mockOutput)))
+		names = append(names, filepath.Join(input, mockOutput))
+	}
+	if c.Bool("factories") {
+		gens = append(gens, generator.NewFactoryGenerator(filepath.Join(input, factoryOutput)))
+		names = append(names, filepath.Join(input, factoryOutput))
+	}
+
+	result := checkResult{Package: pkg.Name, Files: []checkFile{}}
+	for i, g := range gens {
+		diff, err := g.Diff(pkg)
+		if err != nil {
+			return err
+		}
+
+		if diff != "" {
+			result.Files = append(result.Files, checkFile{names[i], diff})
+		}
+	}
+
+	if migrations != "" {
+		changes, err := checkLock(pkg, cfg.Migrations.Input, migrations, cfg.Migrations.Dialect)
+		if err != nil {
+			return err
+		}
+		result.Migrations = changes
+	}
+
+	result.Stale = len(result.Files) > 0 || len(result.Migrations) > 0
+	if asJSON {
+		if err := printJSON(result); err != nil {
+			return err
+		}
+	} else {
+		for _, f := range result.Files {
+			fmt.Print(f.Diff)
+		}
+
+		if len(result.Migrations) > 0 {
+			fmt.Printf("The lock of the migrations in %s is out of date, run `kallax migrate` to generate a migration with the changes:\n\n", migrations)
+			for _, change := range result.Migrations {
+				fmt.Println(change)
+			}
+		}
+	}
+
+	if result.Stale {
+		return fmt.Errorf("kallax: generated code is out of date, run `kallax gen` to update it")
+	}
+
+	return nil
+}
+
+// checkLock returns the changes of the schema of the models in the given
+// package directories, or the given package if there are none, that are not
+// in the lock of the migrations in the given directory, generated for the
+// given dialect.
+func checkLock(pkg *generator.Package, dirs []string, migrations, dialect string) ([]string, error) {
+	ok, err := isDirectory(migrations)
+	if err != nil {
+		return nil, fmt.Errorf("kallax: cannot check migrations directory: %s", err)
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("kallax: `migrations` must be a valid directory")
+	}
+
+	pkgs := []*generator.Package{pkg}
+	if len(dirs) > 0 {
+		if pkgs, err = processPackages(dirs); err != nil {
+			return nil, err
+		}
+	}
+
+	g := generator.NewMigrationGenerator("check", migrations)
+	g.Silent()
+	if dialect != "" {
+		if err := g.SetDialect(dialect); err != nil {
+			return nil, err
+		}
+	}
+	migration, err := g.Build(pkgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []string
+	for _, change := range migration.Up {
+		changes = append(changes, change.String())
+	}
+	return changes, nil
+}
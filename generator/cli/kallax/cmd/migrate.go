@@ -1,13 +1,11 @@
 package cmd
 
 import (
+	"database/sql"
 	"fmt"
+	"os"
 	"path/filepath"
 
-	"github.com/golang-migrate/migrate"
-	_ "github.com/golang-migrate/migrate/database/postgres"
-	_ "github.com/golang-migrate/migrate/source/file"
-
 	"gopkg.in/src-d/go-kallax.v1/generator"
 	cli "gopkg.in/urfave/cli.v1"
 )
@@ -26,17 +24,46 @@ var Migrate = cli.Command{
 			Usage: "Descriptive name for the migration",
 			Value: "migration",
 		},
+		&cli.BoolFlag{
+			Name:  "data",
+			Usage: "Generate also the Go file of the migration with the stub of a data migration, which is run along with the migration by a generator.MigrationRunner in a program importing the package of the migrations",
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "Print the changes and the SQL of the migration without writing any file, and fail if it drops tables or columns, unless `allow-destructive` is given",
+		},
+		&cli.BoolFlag{
+			Name:  "allow-destructive",
+			Usage: "Do not fail on a `dry-run` if the migration drops tables or columns",
+		},
+		&cli.BoolFlag{
+			Name:  "drop-deprecated",
+			Usage: "Generate the migration that drops the tables and columns of the deprecated models and fields",
+		},
 		&cli.StringSliceFlag{
 			Name:  "input, i",
 			Usage: "List of directories to scan models from. You can use this flag as many times as you want.",
 		},
+		dialectFlag,
+		configFlag,
+		jsonFlag,
 	},
 	Subcommands: cli.Commands{
 		&Up,
 		&Down,
+		&Status,
+		&Introspect,
+		&Dump,
+		&Squash,
 	},
 }
 
+var dialectFlag = &cli.StringFlag{
+	Name:  "dialect",
+	Value: "postgres",
+	Usage: "Dialect of the database of the migrations: postgres or cockroachdb",
+}
+
 var migrationFlags = []cli.Flag{
 	&cli.StringFlag{
 		Name:  "dir, d",
@@ -55,6 +82,8 @@ var migrationFlags = []cli.Flag{
 		Name:  "version, v",
 		Usage: "Migrate to a specific version. If `steps` and this flag are given, this will be used.",
 	},
+	configFlag,
+	jsonFlag,
 }
 
 var Up = cli.Command{
@@ -74,7 +103,70 @@ var Down = cli.Command{
 	Flags:  migrationFlags,
 }
 
-func upAction(m *migrate.Migrate, steps, version uint, all bool) error {
+var Status = cli.Command{
+	Name:   "status",
+	Usage:  "Lists the migrations and whether they have been run in the database.",
+	Action: runMigrationAction(statusAction),
+	Flags: []cli.Flag{
+		migrationFlags[0],
+		migrationFlags[1],
+		configFlag,
+		jsonFlag,
+	},
+}
+
+var Introspect = cli.Command{
+	Name:   "introspect",
+	Usage:  "Writes the lock of the migrations with the schema of an existing database, so the next migration only contains the changes of the models with respect to it.",
+	Action: introspectAction,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "dir, d",
+			Value: "./migrations",
+			Usage: "Directory where your migrations are stored",
+		},
+		&cli.StringFlag{
+			Name:  "dsn",
+			Usage: "PostgreSQL data source name. Example: `user:pass@localhost:5432/database?sslmode=enable`",
+		},
+		configFlag,
+		jsonFlag,
+	},
+}
+
+var Dump = cli.Command{
+	Name:   "dump",
+	Usage:  "Prints the SQL that creates the whole schema in the lock of the migrations, which can be run on a database that already has part of it. Use it to bootstrap test databases without running all the migrations.",
+	Action: dumpAction,
+	Flags: []cli.Flag{
+		migrationFlags[0],
+		configFlag,
+		jsonFlag,
+	},
+}
+
+var Squash = cli.Command{
+	Name:   "squash",
+	Usage:  "Replaces the migrations before a version, which must have been run in the database, by a single baseline migration with the version of the last of them.",
+	Action: squashAction,
+	Flags: []cli.Flag{
+		migrationFlags[0],
+		migrationFlags[1],
+		&cli.UintFlag{
+			Name:  "before, b",
+			Usage: "Version, or timestamp, before which the migrations are squashed",
+		},
+		&cli.StringFlag{
+			Name:  "name, n",
+			Usage: "Descriptive name for the baseline migration",
+			Value: "baseline",
+		},
+		configFlag,
+		jsonFlag,
+	},
+}
+
+func upAction(m *generator.MigrationRunner, steps, version uint, all bool, asJSON bool) error {
 	if all {
 		if err := m.Up(); err != nil {
 			return fmt.Errorf("kallax: unable to upgrade the database all the way up: %s", err)
@@ -90,11 +182,10 @@ func upAction(m *migrate.Migrate, steps, version uint, all bool) error {
 	} else {
 		return fmt.Errorf("WARN: No `version` or `steps` provided")
 	}
-	reportMigrationSuccess(m)
-	return nil
+	return reportMigrationSuccess(m, asJSON)
 }
 
-func downAction(m *migrate.Migrate, steps, version uint, all bool) error {
+func downAction(m *generator.MigrationRunner, steps, version uint, all bool, asJSON bool) error {
 	if version > 0 {
 		if err := m.Migrate(version); err != nil {
 			return fmt.Errorf("kallax: unable to rollback to version %d: %s", version, err)
@@ -106,30 +197,90 @@ func downAction(m *migrate.Migrate, steps, version uint, all bool) error {
 	} else {
 		return fmt.Errorf("kallax: no `version` or `steps` provided. You need to specify one of them.")
 	}
-	reportMigrationSuccess(m)
+	return reportMigrationSuccess(m, asJSON)
+}
+
+// statusResult is the output of the status command with the `json` flag.
+type statusResult struct {
+	Version    uint                         `json:"version"`
+	Dirty      bool                         `json:"dirty"`
+	Migrations []*generator.MigrationStatus `json:"migrations"`
+}
+
+func statusAction(m *generator.MigrationRunner, steps, version uint, all bool, asJSON bool) error {
+	v, dirty, err := m.Version()
+	if err != nil {
+		return fmt.Errorf("kallax: unable to check the latest version of the database: %s", err)
+	}
+
+	migrations, err := m.Status()
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		result := statusResult{v, dirty, migrations}
+		if result.Migrations == nil {
+			result.Migrations = []*generator.MigrationStatus{}
+		}
+		return printJSON(result)
+	}
+
+	fmt.Printf("Database is at version %d.\n", v)
+	for _, migration := range migrations {
+		status := "pending"
+		if migration.Dirty {
+			status = "dirty"
+		} else if migration.Applied {
+			status = "applied"
+		}
+		fmt.Printf("%d_%s: %s\n", migration.Version, migration.Name, status)
+	}
 	return nil
 }
 
-func reportMigrationSuccess(m *migrate.Migrate) {
+// migrationResult is the output of the up and down commands with the `json`
+// flag.
+type migrationResult struct {
+	Version uint `json:"version"`
+	Dirty   bool `json:"dirty"`
+}
+
+func reportMigrationSuccess(m *generator.MigrationRunner, asJSON bool) error {
+	v, dirty, err := m.Version()
+	if asJSON {
+		if err != nil {
+			return fmt.Errorf("kallax: unable to check the latest version of the database: %s", err)
+		}
+		return printJSON(migrationResult{v, dirty})
+	}
+
 	fmt.Println("Success! the migration has been run.")
 
-	if v, _, err := m.Version(); err != nil {
+	if err != nil {
 		fmt.Printf("Unable to check the latest version of the database: %s.\n", err)
 	} else {
 		fmt.Printf("Database is now at version %d.\n", v)
 	}
+	return nil
 }
 
-type runMigrationFunc func(m *migrate.Migrate, steps, version uint, all bool) error
+type runMigrationFunc func(m *generator.MigrationRunner, steps, version uint, all bool, asJSON bool) error
 
 func runMigrationAction(fn runMigrationFunc) cli.ActionFunc {
 	return func(c *cli.Context) error {
+		cfg, err := loadConfig(c)
+		if err != nil {
+			return err
+		}
+
 		var (
-			dir     = c.String("dir")
-			dsn     = c.String("dsn")
+			dir     = stringFlag(c, "dir", cfg.Migrations.Dir)
+			dsn     = stringFlag(c, "dsn", cfg.Migrations.DSN)
 			steps   = c.Uint("steps")
 			version = c.Uint("version")
 			all     = c.Bool("all")
+			asJSON  = c.Bool("json")
 		)
 
 		ok, err := isDirectory(dir)
@@ -141,56 +292,214 @@ func runMigrationAction(fn runMigrationFunc) cli.ActionFunc {
 			return fmt.Errorf("kallax: argument `dir` must be a valid directory")
 		}
 
-		dir, err = filepath.Abs(dir)
+		m, err := generator.NewMigrationRunner(dir, dsn)
 		if err != nil {
-			return fmt.Errorf("kallax: cannot get absolute path of `dir`: %s", err)
+			return err
 		}
+		defer m.Close()
 
-		m, err := migrate.New(pathToFileURL(dir), fmt.Sprintf("postgres://%s", dsn))
-		if err != nil {
-			return fmt.Errorf("kallax: unable to open a connection with the database: %s", err)
-		}
+		return fn(m, steps, version, all, asJSON)
+	}
+}
+
+// introspectResult is the output of the introspect command with the `json`
+// flag.
+type introspectResult struct {
+	Lock   string   `json:"lock"`
+	Tables []string `json:"tables"`
+}
+
+func introspectAction(c *cli.Context) error {
+	cfg, err := loadConfig(c)
+	if err != nil {
+		return err
+	}
 
-		return fn(m, steps, version, all)
+	dir := stringFlag(c, "dir", cfg.Migrations.Dir)
+	dsn := stringFlag(c, "dsn", cfg.Migrations.DSN)
+
+	ok, err := isDirectory(dir)
+	if err != nil {
+		return fmt.Errorf("kallax: cannot check if `dir` is a directory: %s", err)
 	}
+
+	if !ok {
+		return fmt.Errorf("kallax: argument `dir` must be a valid directory")
+	}
+
+	lock := filepath.Join(dir, "lock.json")
+	if _, err := os.Stat(lock); err == nil {
+		return fmt.Errorf("kallax: the lock file %s already exists, the schema of the database can only be introspected into a new one", lock)
+	}
+
+	db, err := sql.Open("postgres", fmt.Sprintf("postgres://%s", dsn))
+	if err != nil {
+		return fmt.Errorf("kallax: unable to open a connection with the database: %s", err)
+	}
+	defer db.Close()
+
+	schema, err := generator.IntrospectSchema(db)
+	if err != nil {
+		return err
+	}
+
+	if err := generator.NewMigrationGenerator("introspect", dir).WriteLock(schema); err != nil {
+		return err
+	}
+
+	if c.Bool("json") {
+		result := introspectResult{Lock: lock, Tables: []string{}}
+		for _, t := range schema.Tables {
+			result.Tables = append(result.Tables, t.Name)
+		}
+		return printJSON(result)
+	}
+
+	fmt.Printf("Success! the schema of %d table(s) has been written to %s.\n", len(schema.Tables), lock)
+	return nil
 }
 
-func pathToFileURL(path string) string {
-	if !filepath.IsAbs(path) {
-		var err error
-		path, err = filepath.Abs(path)
-		if err != nil {
-			return ""
+// dumpResult is the output of the dump command with the `json` flag.
+type dumpResult struct {
+	Lock   string   `json:"lock"`
+	Tables []string `json:"tables"`
+	SQL    string   `json:"sql"`
+}
+
+func dumpAction(c *cli.Context) error {
+	cfg, err := loadConfig(c)
+	if err != nil {
+		return err
+	}
+
+	dir := stringFlag(c, "dir", cfg.Migrations.Dir)
+	lock := filepath.Join(dir, "lock.json")
+	if _, err := os.Stat(lock); err != nil {
+		return fmt.Errorf("kallax: unable to find the lock file of the migrations: %s", err)
+	}
+
+	schema, err := generator.NewMigrationGenerator("dump", dir).LoadLock()
+	if err != nil {
+		return err
+	}
+
+	sql, err := schema.SQL()
+	if err != nil {
+		return err
+	}
+
+	if c.Bool("json") {
+		result := dumpResult{Lock: lock, Tables: []string{}, SQL: sql}
+		for _, t := range schema.Tables {
+			result.Tables = append(result.Tables, t.Name)
 		}
+		return printJSON(result)
 	}
-	return fmt.Sprintf("file://%s", filepath.ToSlash(path))
+
+	fmt.Print(sql)
+	return nil
 }
 
-func migrateAction(c *cli.Context) error {
-	dirs := c.StringSlice("input")
-	dir := c.String("out")
-	name := c.String("name")
+// squashResult is the output of the squash command with the `json` flag.
+type squashResult struct {
+	Baseline *generator.MigrationStatus   `json:"baseline"`
+	Squashed []*generator.MigrationStatus `json:"squashed"`
+}
+
+func squashAction(c *cli.Context) error {
+	cfg, err := loadConfig(c)
+	if err != nil {
+		return err
+	}
 
+	dir := stringFlag(c, "dir", cfg.Migrations.Dir)
+	dsn := stringFlag(c, "dsn", cfg.Migrations.DSN)
+	before := c.Uint("before")
+	if before == 0 {
+		return fmt.Errorf("kallax: the `before` version of the migrations to squash is required")
+	}
+
+	ok, err := isDirectory(dir)
+	if err != nil {
+		return fmt.Errorf("kallax: cannot check if `dir` is a directory: %s", err)
+	}
+
+	if !ok {
+		return fmt.Errorf("kallax: argument `dir` must be a valid directory")
+	}
+
+	m, err := generator.NewMigrationRunner(dir, dsn)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	baseline, squashed, err := m.Squash(before, c.String("name"))
+	if err != nil {
+		return err
+	}
+
+	if c.Bool("json") {
+		return printJSON(squashResult{baseline, squashed})
+	}
+
+	fmt.Printf("Success! %d migrations have been squashed into %d_%s.\n", len(squashed), baseline.Version, baseline.Name)
+	return nil
+}
+
+// processPackages scans the models of the packages in the given directories.
+func processPackages(dirs []string) ([]*generator.Package, error) {
 	var pkgs []*generator.Package
 	for _, dir := range dirs {
 		ok, err := isDirectory(dir)
 		if err != nil {
-			return fmt.Errorf("kallax: cannot check directory in `input`: %s", err)
+			return nil, fmt.Errorf("kallax: cannot check directory in `input`: %s", err)
 		}
 
 		if !ok {
-			return fmt.Errorf("kallax: `input` must be a valid directory")
+			return nil, fmt.Errorf("kallax: `input` must be a valid directory")
 		}
 
 		p := generator.NewProcessor(dir, nil)
 		p.Silent()
 		pkg, err := p.Do()
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		pkgs = append(pkgs, pkg)
 	}
+	return pkgs, nil
+}
+
+// generateMigrationResult is the output of the migrate command with the
+// `json` flag.
+type generateMigrationResult struct {
+	Name        string   `json:"name"`
+	Dir         string   `json:"dir"`
+	Changes     []string `json:"changes"`
+	DryRun      bool     `json:"dry_run,omitempty"`
+	Up          string   `json:"up,omitempty"`
+	Down        string   `json:"down,omitempty"`
+	Destructive []string `json:"destructive,omitempty"`
+}
+
+func migrateAction(c *cli.Context) error {
+	cfg, err := loadConfig(c)
+	if err != nil {
+		return err
+	}
+
+	dirs := stringSliceFlag(c, "input", cfg.Migrations.Input)
+	dir := stringFlag(c, "out", cfg.Migrations.Dir)
+	name := c.String("name")
+	asJSON := c.Bool("json")
+	dryRun := c.Bool("dry-run")
+
+	pkgs, err := processPackages(dirs)
+	if err != nil {
+		return err
+	}
 
 	ok, err := isDirectory(dir)
 	if err != nil {
@@ -202,10 +511,84 @@ func migrateAction(c *cli.Context) error {
 	}
 
 	g := generator.NewMigrationGenerator(name, dir)
+	if asJSON {
+		g.Silent()
+	}
+
+	if err := g.SetDialect(stringFlag(c, "dialect", cfg.Migrations.Dialect)); err != nil {
+		return err
+	}
+
+	if c.Bool("drop-deprecated") {
+		g.DropDeprecated()
+	}
+
+	if c.Bool("data") {
+		g.Data()
+	}
+
+	if dryRun {
+		g.DryRun()
+	}
+
 	migration, err := g.Build(pkgs...)
 	if err != nil {
 		return err
 	}
 
-	return g.Generate(migration)
+	if err := g.Generate(migration); err != nil {
+		return err
+	}
+
+	var destructive generator.ChangeSet
+	if dryRun && !c.Bool("allow-destructive") {
+		destructive = migration.Up.Destructive()
+	}
+
+	if asJSON {
+		result := generateMigrationResult{Name: name, Dir: dir, Changes: []string{}, DryRun: dryRun}
+		for _, change := range migration.Up {
+			result.Changes = append(result.Changes, change.String())
+		}
+
+		for _, change := range destructive {
+			result.Destructive = append(result.Destructive, change.String())
+		}
+
+		if dryRun && len(migration.Up) > 0 {
+			if result.Up, result.Down, err = migrationSQL(migration); err != nil {
+				return err
+			}
+		}
+
+		if err := printJSON(result); err != nil {
+			return err
+		}
+	}
+
+	if len(destructive) > 0 {
+		return fmt.Errorf("kallax: the migration has %d destructive change(s) dropping tables or columns, use `allow-destructive` to allow them", len(destructive))
+	}
+
+	return nil
+}
+
+// migrationSQL returns the SQL of the up and down files of the given
+// migration, joining the ones of its steps in the order they are run.
+func migrationSQL(migration *generator.Migration) (up, down string, err error) {
+	for _, step := range migration.Steps() {
+		upSQL, err := step.Up.MarshalText()
+		if err != nil {
+			return "", "", err
+		}
+
+		downSQL, err := step.Down.MarshalText()
+		if err != nil {
+			return "", "", err
+		}
+
+		up += string(upSQL)
+		down = string(downSQL) + down
+	}
+	return up, down, nil
 }
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+
+	"gopkg.in/src-d/go-kallax.v1/generator"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+var Schema = cli.Command{
+	Name:   "schema",
+	Usage:  "Print the database schema of the current kallax models",
+	Action: schemaAction,
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "input, i",
+			Usage: "List of directories to scan models from. You can use this flag as many times as you want.",
+		},
+		&cli.StringFlag{
+			Name:  "dialect",
+			Value: "postgres",
+			Usage: "Dialect of the printed SQL schema: postgres, cockroachdb, mysql or sqlite. The sqlite schema is meant to run the stores with the kallax.SQLite dialect in local tests",
+		},
+		configFlag,
+		jsonFlag,
+	},
+}
+
+// sqlSchemas are the functions printing the schema for the dialects that
+// migrations are not generated for.
+var sqlSchemas = map[string]func(*generator.DBSchema) (string, error){
+	"sqlite": generator.SQLiteSchema,
+	"mysql":  generator.MySQLSchema,
+}
+
+func schemaAction(c *cli.Context) error {
+	cfg, err := loadConfig(c)
+	if err != nil {
+		return err
+	}
+
+	dirs := stringSliceFlag(c, "input", cfg.Migrations.Input)
+	if len(dirs) == 0 {
+		return fmt.Errorf("kallax: no `input` directories provided")
+	}
+
+	pkgs, err := processPackages(dirs)
+	if err != nil {
+		return err
+	}
+
+	schema, err := generator.SchemaFromPackages(pkgs...)
+	if err != nil {
+		return err
+	}
+
+	dialect := stringFlag(c, "dialect", cfg.Migrations.Dialect)
+	toSQL, ok := sqlSchemas[dialect]
+	if ok && !c.Bool("json") {
+		sql, err := toSQL(schema)
+		if err != nil {
+			return err
+		}
+
+		fmt.Print(sql)
+		return nil
+	}
+
+	if !ok {
+		if schema, err = generator.SchemaForDialect(schema, dialect); err != nil {
+			return err
+		}
+	}
+
+	if c.Bool("json") {
+		data, err := schema.MarshalText()
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(data))
+		return nil
+	}
+
+	migration, err := generator.NewMigration(new(generator.DBSchema), schema)
+	if err != nil {
+		return err
+	}
+
+	sql, err := migration.Up.MarshalText()
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(string(sql))
+	return nil
+}
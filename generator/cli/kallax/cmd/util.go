@@ -1,6 +1,9 @@
 package cmd
 
-import "os"
+import (
+	"encoding/json"
+	"os"
+)
 
 func isDirectory(name string) (bool, error) {
 	info, err := os.Stat(name)
@@ -10,3 +13,10 @@ func isDirectory(name string) (bool, error) {
 
 	return info.IsDir(), nil
 }
+
+// printJSON writes the given value to stdout as indented JSON.
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+var Version = cli.Command{
+	Name:   "version",
+	Usage:  "Print the version of kallax",
+	Action: versionAction,
+	Flags: []cli.Flag{
+		jsonFlag,
+	},
+}
+
+// versionResult is the output of the version command with the `json` flag.
+type versionResult struct {
+	Version string `json:"version"`
+	Go      string `json:"go"`
+}
+
+func versionAction(c *cli.Context) error {
+	version := c.App.Version
+	if c.Bool("json") {
+		return printJSON(versionResult{version, runtime.Version()})
+	}
+
+	fmt.Printf("kallax version %s (%s)\n", version, runtime.Version())
+	return nil
+}
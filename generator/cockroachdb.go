@@ -0,0 +1,102 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// cockroachDBSerialColumn is the type of the auto-incrementable primary
+// keys in CockroachDB. CockroachDB does not generate sequential values for
+// serial columns, but unique 64-bit integers, so they are declared with the
+// function generating them instead of relying on the normalization of the
+// serial types done by the server.
+const cockroachDBSerialColumn ColumnType = "INT8 DEFAULT unique_rowid()"
+
+// cockroachDBUnsupportedTypes are the column types that CockroachDB does not
+// support, either because it has no such type or because it's provided by an
+// extension.
+var cockroachDBUnsupportedTypes = map[ColumnType]bool{
+	CITextColumn:    true,
+	HStoreColumn:    true,
+	LTreeColumn:     true,
+	MoneyColumn:     true,
+	PGMoneyColumn:   true,
+	XMLColumn:       true,
+	MACAddrColumn:   true,
+	CIDRColumn:      true,
+	Int4RangeColumn: true,
+	Int8RangeColumn: true,
+	NumRangeColumn:  true,
+	TstzRangeColumn: true,
+}
+
+// cockroachDBIndexes are the index methods supported by CockroachDB.
+var cockroachDBIndexes = map[string]bool{
+	"":       true,
+	"unique": true,
+	"btree":  true,
+	"gin":    true,
+	"gist":   true,
+}
+
+// CockroachDBSchema returns a copy of the given schema adapted to
+// CockroachDB, so the migrations generated with it can be run without
+// patching them by hand. Auto-incrementable primary keys are declared as
+// 64-bit integers generated with unique_rowid(), as CockroachDB does with
+// serial types, so their fields must be int64. An error is returned if any
+// column has a type or an index that CockroachDB does not support, is a
+// tsvector maintained with a trigger or has a JSON schema, as they rely on
+// PostgreSQL extensions, or if any model is audited, versioned or notifies
+// its changes.
+func CockroachDBSchema(schema *DBSchema) (*DBSchema, error) {
+	if len(schema.Types) > 0 {
+		return nil, fmt.Errorf("kallax: composite type %s is not supported by CockroachDB", schema.Types[0].Name)
+	}
+
+	result := &DBSchema{Tables: make([]*TableSchema, len(schema.Tables)), Enums: schema.Enums}
+	for i, table := range schema.Tables {
+		if tracked := table.trackedTable(); tracked != "" {
+			return nil, fmt.Errorf("kallax: table %s keeps the changes of table %s with a trigger, which is not supported by CockroachDB", table.Name, tracked)
+		}
+
+		if table.Notify != nil {
+			return nil, fmt.Errorf("kallax: table %s notifies its changes with a trigger, which is not supported by CockroachDB", table.Name)
+		}
+
+		t := &TableSchema{Name: table.Name, Columns: make([]*ColumnSchema, len(table.Columns)), Deprecated: table.Deprecated, PrimaryKey: table.PrimaryKey}
+		for j, c := range table.Columns {
+			col := *c
+			if err := adaptCockroachDBColumn(table.Name, &col); err != nil {
+				return nil, err
+			}
+			t.Columns[j] = &col
+		}
+		result.Tables[i] = t
+	}
+
+	return result, nil
+}
+
+func adaptCockroachDBColumn(table string, c *ColumnSchema) error {
+	elem := ColumnType(strings.TrimRight(string(c.Type), "[]"))
+	if cockroachDBUnsupportedTypes[elem] {
+		return fmt.Errorf("kallax: column %s of table %s has type %s, which is not supported by CockroachDB", c.Name, table, c.Type)
+	}
+
+	if !cockroachDBIndexes[c.Index] {
+		return fmt.Errorf("kallax: column %s of table %s has a %s index, which is not supported by CockroachDB", c.Name, table, c.Index)
+	}
+
+	if c.TSVector != nil && c.TSVector.Trigger {
+		return fmt.Errorf("kallax: column %s of table %s is updated with a trigger, which is not supported by CockroachDB, use `tsupdate:\"generated\"` instead", c.Name, table)
+	}
+
+	if c.JSONSchema != "" {
+		return fmt.Errorf("kallax: column %s of table %s checks a JSON schema in the database, which is not supported by CockroachDB", c.Name, table)
+	}
+
+	if c.PrimaryKey && isSerial(c.Type) {
+		c.Type = cockroachDBSerialColumn
+	}
+	return nil
+}
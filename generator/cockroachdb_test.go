@@ -0,0 +1,88 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCockroachDBSchema(t *testing.T) {
+	schema := mkSchema(
+		mkTable(
+			"users",
+			mkCol("id", SerialColumn, true, true, nil),
+			mkColIndex("tags", ArrayColumn(TextColumn), false, false, "gin"),
+		),
+		mkTable(
+			"posts",
+			mkCol("id", UUIDColumn, true, true, nil),
+			mkCol("user_id", BigIntColumn, false, true, mkRef("users", "id", false)),
+		),
+	)
+
+	result, err := CockroachDBSchema(schema)
+	require.NoError(t, err)
+	require.Equal(t, cockroachDBSerialColumn, result.Table("users").Column("id").Type)
+	require.Equal(t, ArrayColumn(TextColumn), result.Table("users").Column("tags").Type)
+	require.Equal(t, UUIDColumn, result.Table("posts").Column("id").Type)
+	require.Equal(t, SerialColumn, schema.Table("users").Column("id").Type, "the original schema must not change")
+
+	migration, err := NewMigration(new(DBSchema), result)
+	require.NoError(t, err)
+	sql, err := migration.Up.MarshalText()
+	require.NoError(t, err)
+	require.Contains(t, string(sql), "\tid INT8 DEFAULT unique_rowid() NOT NULL PRIMARY KEY,\n")
+}
+
+func TestCockroachDBSchema_Unsupported(t *testing.T) {
+	cases := []struct {
+		column *ColumnSchema
+		err    string
+	}{
+		{
+			mkCol("name", CITextColumn, false, true, nil),
+			"kallax: column name of table foo has type citext, which is not supported by CockroachDB",
+		},
+		{
+			mkCol("periods", ArrayColumn(TstzRangeColumn), false, true, nil),
+			"kallax: column periods of table foo has type tstzrange[], which is not supported by CockroachDB",
+		},
+		{
+			mkColIndex("embedding", VectorColumn(3), false, true, "hnsw"),
+			"kallax: column embedding of table foo has a hnsw index, which is not supported by CockroachDB",
+		},
+		{
+			&ColumnSchema{Name: "doc", Type: TSVectorColumn, TSVector: &TSVectorSchema{Trigger: true}},
+			"kallax: column doc of table foo is updated with a trigger, which is not supported by CockroachDB, use `tsupdate:\"generated\"` instead",
+		},
+		{
+			&ColumnSchema{Name: "settings", Type: JSONBColumn, JSONSchema: "{}"},
+			"kallax: column settings of table foo checks a JSON schema in the database, which is not supported by CockroachDB",
+		},
+	}
+
+	for _, c := range cases {
+		_, err := CockroachDBSchema(mkSchema(mkTable("foo", c.column)))
+		require.EqualError(t, err, c.err)
+	}
+
+	schema := mkSchema(mkTable("foo"))
+	schema.Types = []*TypeSchema{mkType("address", mkAttr("city", TextColumn))}
+	_, err := CockroachDBSchema(schema)
+	require.EqualError(t, err, "kallax: composite type address is not supported by CockroachDB")
+}
+
+func TestSchemaForDialect(t *testing.T) {
+	schema := mkSchema(mkTable("foo", mkCol("id", SerialColumn, true, true, nil)))
+
+	result, err := SchemaForDialect(schema, "postgres")
+	require.NoError(t, err)
+	require.Equal(t, schema, result)
+
+	result, err = SchemaForDialect(schema, "cockroachdb")
+	require.NoError(t, err)
+	require.Equal(t, cockroachDBSerialColumn, result.Tables[0].Columns[0].Type)
+
+	_, err = SchemaForDialect(schema, "oracle")
+	require.EqualError(t, err, "kallax: migrations cannot be generated for dialect oracle, it must be postgres or cockroachdb")
+}
@@ -0,0 +1,387 @@
+package generator
+
+import (
+	"database/sql"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/golang-migrate/migrate"
+	"github.com/golang-migrate/migrate/database/postgres"
+)
+
+// DataMigration is a migration of the data of the database written in Go,
+// such as a backfill, which is run by MigrationRunner along with the schema
+// migration with the same version. When the statements of the schema
+// migration are wrapped in a transaction, as the generated ones are, they are
+// run in the same transaction as the data migration, along with the update of
+// the version of the database, so all of them are run or none is. Otherwise,
+// the data migration is run in a transaction of its own.
+type DataMigration struct {
+	// Up is run after the statements of the up file of the migration.
+	Up func(tx *sql.Tx) error
+	// Down is run before the statements of the down file of the migration.
+	Down func(tx *sql.Tx) error
+}
+
+var (
+	dataMigrationsMu sync.Mutex
+	dataMigrations   = make(map[uint]DataMigration)
+)
+
+// RegisterDataMigration registers the data migration of the migration with
+// the given version, which is usually done in an init function of the Go file
+// generated by MigrationGenerator along with the migration. It panics if the
+// version already has a data migration.
+func RegisterDataMigration(version uint, m DataMigration) {
+	dataMigrationsMu.Lock()
+	defer dataMigrationsMu.Unlock()
+	if _, ok := dataMigrations[version]; ok {
+		panic(fmt.Sprintf("kallax: data migration of version %d registered twice", version))
+	}
+	dataMigrations[version] = m
+}
+
+func registeredDataMigration(version uint) (DataMigration, bool) {
+	dataMigrationsMu.Lock()
+	defer dataMigrationsMu.Unlock()
+	m, ok := dataMigrations[version]
+	return m, ok
+}
+
+// dataMigrationFileRegexp matches the names of the Go files of the data
+// migrations.
+var dataMigrationFileRegexp = regexp.MustCompile(`^(\d+)_(.*)\.go$`)
+
+// dataMigrationFiles returns the versions of the migrations of the given
+// directory that have a Go file with a data migration.
+func dataMigrationFiles(dir string) (map[uint]bool, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("kallax: unable to read the migrations directory: %s", err)
+	}
+
+	versions := make(map[uint]bool)
+	for _, f := range files {
+		m := dataMigrationFileRegexp.FindStringSubmatch(f.Name())
+		if m == nil || strings.HasSuffix(f.Name(), "_test.go") {
+			continue
+		}
+
+		if v, err := strconv.ParseUint(m[1], 10, 64); err == nil {
+			versions[uint(v)] = true
+		}
+	}
+	return versions, nil
+}
+
+// hasDataMigrations reports whether any of the migrations of the runner has a
+// data migration, in which case they are run one by one.
+func (r *MigrationRunner) hasDataMigrations(migrations []*MigrationStatus) bool {
+	for _, m := range migrations {
+		if _, ok := registeredDataMigration(m.Version); ok || r.data[m.Version] {
+			return true
+		}
+	}
+	return false
+}
+
+// migrateData runs the migrations from the current version of the database
+// until the one at the given index of the migrations, or before all of them
+// if it's -1. The migrations with a data migration are run by the runner,
+// and the rest of them by golang-migrate. If short is not zero, it's returned
+// as an ErrShortLimit once the migrations are run.
+func (r *MigrationRunner) migrateData(migrations []*MigrationStatus, current, target int, short uint) error {
+	if current == target && short == 0 {
+		return migrate.ErrNoChange
+	}
+
+	for ; current < target; current++ {
+		if err := r.step(migrations, current+1, true); err != nil {
+			return err
+		}
+	}
+
+	for ; current > target; current-- {
+		if err := r.step(migrations, current, false); err != nil {
+			return err
+		}
+	}
+
+	if short > 0 {
+		return migrate.ErrShortLimit{Short: short}
+	}
+	return nil
+}
+
+// currentMigration returns the index of the migration the database is at
+// among the given ones, or -1 if no migration has been run.
+func (r *MigrationRunner) currentMigration(migrations []*MigrationStatus) (int, error) {
+	version, dirty, err := r.Version()
+	if err != nil {
+		return 0, err
+	}
+
+	if dirty {
+		return 0, migrate.ErrDirty{Version: int(version)}
+	}
+
+	if version == 0 {
+		return -1, nil
+	}
+	return migrationIndex(migrations, version)
+}
+
+func migrationIndex(migrations []*MigrationStatus, version uint) (int, error) {
+	for i, m := range migrations {
+		if m.Version == version {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("kallax: there is no migration with version %d in the migrations directory", version)
+}
+
+// step runs the migration at the given index up, or down to the previous one.
+func (r *MigrationRunner) step(migrations []*MigrationStatus, i int, up bool) error {
+	m := migrations[i]
+	data, ok := registeredDataMigration(m.Version)
+	if !ok && !r.data[m.Version] {
+		if up {
+			return r.m.Steps(1)
+		}
+		return r.m.Steps(-1)
+	}
+
+	if !ok {
+		return fmt.Errorf("kallax: migration %d_%s has a data migration that is not registered, so it must be run by a MigrationRunner in a program importing the package of the migrations", m.Version, m.Name)
+	}
+
+	var (
+		from = int(m.Version)
+		to   = int(m.Version)
+		typ  = migrationUp
+		fn   = data.Up
+	)
+	if up {
+		from = -1
+		if i > 0 {
+			from = int(migrations[i-1].Version)
+		}
+	} else {
+		to, typ, fn = -1, migrationDown, data.Down
+		if i > 0 {
+			to = int(migrations[i-1].Version)
+		}
+	}
+
+	content, err := ioutil.ReadFile(migrationFileName(r.dir, m, typ))
+	if err != nil {
+		return fmt.Errorf("kallax: unable to read migration %d_%s: %s", m.Version, m.Name, err)
+	}
+
+	run := func(tx *sql.Tx) error {
+		if fn == nil {
+			return nil
+		}
+
+		if err := fn(tx); err != nil {
+			return fmt.Errorf("kallax: data migration %d_%s failed: %s", m.Version, m.Name, err)
+		}
+		return nil
+	}
+
+	stmts, ok := transactionBody(string(content))
+	if ok {
+		return r.inTx(from, false, func(tx *sql.Tx) error {
+			if !up {
+				if err := run(tx); err != nil {
+					return err
+				}
+			}
+
+			if _, err := tx.Exec(stmts); err != nil {
+				return fmt.Errorf("kallax: migration %d_%s failed: %s", m.Version, m.Name, err)
+			}
+
+			if up {
+				if err := run(tx); err != nil {
+					return err
+				}
+			}
+			return setVersion(tx, to, false)
+		})
+	}
+
+	// the statements may not be able to run in a transaction, so they are
+	// run on their own and the database is dirty until they and the data
+	// migration are run, as it is with golang-migrate
+	dirty := int(m.Version)
+	if !up {
+		dirty = to
+	}
+
+	if err := r.inTx(from, false, func(tx *sql.Tx) error {
+		if !up {
+			if err := run(tx); err != nil {
+				return err
+			}
+		}
+		return setVersion(tx, dirty, true)
+	}); err != nil {
+		return err
+	}
+
+	if _, err := r.db.Exec(stmts); err != nil {
+		return fmt.Errorf("kallax: migration %d_%s failed: %s", m.Version, m.Name, err)
+	}
+
+	return r.inTx(dirty, true, func(tx *sql.Tx) error {
+		if up {
+			if err := run(tx); err != nil {
+				return err
+			}
+		}
+		return setVersion(tx, to, false)
+	})
+}
+
+// inTx runs the given function in a transaction in which the table of the
+// versions is locked and the database is at the given version, or at none if
+// it's -1, and is dirty or not as given. Otherwise another runner has
+// migrated the database in the meantime, and nothing is run.
+func (r *MigrationRunner) inTx(version int, dirty bool, fn func(*sql.Tx) error) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("kallax: unable to open transaction: %s", err)
+	}
+
+	if err := lockVersion(tx, version, dirty); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("kallax: unable to commit transaction: %s", err)
+	}
+	return nil
+}
+
+// lockVersion locks the table of the versions until the end of the given
+// transaction and checks the database is at the given version.
+func lockVersion(tx *sql.Tx, version int, dirty bool) error {
+	table := postgres.DefaultMigrationsTable
+	if _, err := tx.Exec(fmt.Sprintf(`LOCK TABLE "%s" IN EXCLUSIVE MODE`, table)); err != nil {
+		return fmt.Errorf("kallax: unable to lock the table %s: %s", table, err)
+	}
+
+	var (
+		current      = -1
+		currentDirty bool
+	)
+	err := tx.QueryRow(fmt.Sprintf(`SELECT version, dirty FROM "%s" LIMIT 1`, table)).Scan(&current, &currentDirty)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("kallax: unable to check the version of the database: %s", err)
+	}
+
+	if current != version || (version >= 0 && currentDirty != dirty) {
+		return fmt.Errorf("kallax: the database has been migrated by someone else while running the migrations")
+	}
+	return nil
+}
+
+// setVersion sets the version of the database in the given transaction, or
+// no version if it's -1, as golang-migrate does.
+func setVersion(tx *sql.Tx, version int, dirty bool) error {
+	table := postgres.DefaultMigrationsTable
+	if _, err := tx.Exec(fmt.Sprintf(`TRUNCATE "%s"`, table)); err != nil {
+		return fmt.Errorf("kallax: unable to set the version of the database: %s", err)
+	}
+
+	if version >= 0 {
+		query := fmt.Sprintf(`INSERT INTO "%s" (version, dirty) VALUES ($1, $2)`, table)
+		if _, err := tx.Exec(query, version, dirty); err != nil {
+			return fmt.Errorf("kallax: unable to set the version of the database: %s", err)
+		}
+	}
+	return nil
+}
+
+// transactionBody returns the statements of the given migration without the
+// transaction it's wrapped in, as the generated migrations are, and whether
+// it's wrapped in one.
+func transactionBody(sql string) (string, bool) {
+	sql = strings.TrimSpace(sql)
+	if !strings.HasPrefix(sql, "BEGIN;") || !strings.HasSuffix(sql, "COMMIT;") {
+		return sql, false
+	}
+
+	sql = strings.TrimSpace(strings.TrimPrefix(sql, "BEGIN;"))
+	return strings.TrimSpace(strings.TrimSuffix(sql, "COMMIT;")), true
+}
+
+const dataMigrationTpl = `package %s
+
+import (
+	"database/sql"
+
+	"gopkg.in/src-d/go-kallax.v1/generator"
+)
+
+func init() {
+	generator.RegisterDataMigration(%d, generator.DataMigration{
+		// Up is run after %s.
+		Up: func(tx *sql.Tx) error {
+			return nil
+		},
+		// Down is run before %s.
+		Down: func(tx *sql.Tx) error {
+			return nil
+		},
+	})
+}
+`
+
+// dataMigrationStub returns the Go file with the stub of the data migration
+// of the migration with the given files.
+func (g *MigrationGenerator) dataMigrationStub(version int64, up, down string) []byte {
+	return []byte(fmt.Sprintf(dataMigrationTpl, dataMigrationPackage(g.dir), version, filepath.Base(up), filepath.Base(down)))
+}
+
+// dataMigrationPackage returns the name of the Go package of the migrations
+// in the given directory, which is the one of its Go files, if any, or the
+// name of the directory.
+func dataMigrationPackage(dir string) string {
+	files, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	for _, f := range files {
+		if strings.HasSuffix(f, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(token.NewFileSet(), f, nil, parser.PackageClauseOnly)
+		if err == nil {
+			return file.Name.Name
+		}
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "migrations"
+	}
+
+	name := slugify(filepath.Base(abs))
+	if !token.IsIdentifier(name) {
+		return "migrations"
+	}
+	return name
+}
@@ -0,0 +1,24 @@
+package generator
+
+import "fmt"
+
+// schemaDialects are the functions that adapt the schema of the models to
+// the dialects migrations can be generated for, by name of the dialect.
+var schemaDialects = map[string]func(*DBSchema) (*DBSchema, error){
+	"postgres":    func(s *DBSchema) (*DBSchema, error) { return s, nil },
+	"cockroachdb": CockroachDBSchema,
+}
+
+// SchemaForDialect returns the given schema adapted to the dialect with the
+// given name: postgres or cockroachdb.
+func SchemaForDialect(schema *DBSchema, dialect string) (*DBSchema, error) {
+	fn, ok := schemaDialects[dialect]
+	if !ok {
+		return nil, errUnknownDialect(dialect)
+	}
+	return fn(schema)
+}
+
+func errUnknownDialect(dialect string) error {
+	return fmt.Errorf("kallax: migrations cannot be generated for dialect %s, it must be postgres or cockroachdb", dialect)
+}
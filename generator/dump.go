@@ -0,0 +1,103 @@
+package generator
+
+import (
+	"bytes"
+	"strings"
+)
+
+// SQL returns a script with the statements that create the whole schema, in
+// the same order as a migration creating it from scratch. Unlike migrations,
+// the script can be run on a database that already has any part of the
+// schema: tables and indexes are created with IF NOT EXISTS, and the
+// composite and enum types, triggers and foreign keys that already exist are
+// skipped. It's meant to bootstrap test databases with the lock of the
+// migrations without running all of them. The deprecated tables and columns
+// are created too, as they are in the database the schema is locked from.
+func (s *DBSchema) SQL() (string, error) {
+	cs, err := createChanges(s)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("BEGIN;\n\n")
+	for _, c := range cs {
+		text, err := c.MarshalText()
+		if err != nil {
+			return "", err
+		}
+		buf.WriteString(idempotentSQL(string(text)))
+		buf.WriteRune('\n')
+	}
+	buf.WriteString("COMMIT;\n")
+	return buf.String(), nil
+}
+
+// createChanges returns the changes that create the given schema in an empty
+// database, sorted. Unlike NewMigration, the deprecated tables and columns
+// are created too.
+func createChanges(schema *DBSchema) (ChangeSet, error) {
+	return SchemaDiff(new(DBSchema), schema).sorted(nil, schema.index())
+}
+
+// ifNotExistsStatements are the prefixes of the statements that are made
+// idempotent with IF NOT EXISTS.
+var ifNotExistsStatements = []string{
+	"CREATE TABLE ",
+	"CREATE INDEX ",
+	"CREATE UNIQUE INDEX ",
+}
+
+// duplicateObjectStatements are the prefixes of the statements that have no
+// IF NOT EXISTS, so they are made idempotent ignoring the error raised when
+// the object they create already exists.
+var duplicateObjectStatements = []string{
+	"CREATE TYPE ",
+	"CREATE TRIGGER ",
+	"ALTER TABLE ",
+}
+
+// idempotentSQL returns the given statements of a migration changed so they
+// don't fail if the objects they create already exist. The bodies of the
+// functions are left as they are, as the functions are always replaced.
+func idempotentSQL(sql string) string {
+	var (
+		buf       bytes.Buffer
+		inBody    bool
+		ignoreDup bool
+	)
+	for _, line := range strings.SplitAfter(sql, "\n") {
+		if strings.Count(line, "$$")%2 == 1 {
+			inBody = !inBody
+		} else if !inBody && !ignoreDup {
+			line, ignoreDup = makeIdempotent(line)
+		}
+
+		if ignoreDup && strings.HasSuffix(strings.TrimSpace(line), ";") {
+			line = strings.TrimSuffix(strings.TrimRight(line, "\n"), ";") +
+				"; EXCEPTION WHEN duplicate_object THEN null; END $$;\n"
+			ignoreDup = false
+		}
+		buf.WriteString(line)
+	}
+	return buf.String()
+}
+
+// makeIdempotent returns the given line with IF NOT EXISTS if it starts a
+// statement that supports it, or starting a block that ignores the error of
+// the statement if the object already exists, in which case it also returns
+// true, as the block must be ended with the statement.
+func makeIdempotent(line string) (string, bool) {
+	for _, prefix := range ifNotExistsStatements {
+		if strings.HasPrefix(line, prefix) {
+			return prefix + "IF NOT EXISTS " + strings.TrimPrefix(line, prefix), false
+		}
+	}
+
+	for _, prefix := range duplicateObjectStatements {
+		if strings.HasPrefix(line, prefix) {
+			return "DO $$ BEGIN " + line, true
+		}
+	}
+	return line, false
+}
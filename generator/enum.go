@@ -0,0 +1,291 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Enum returns the name and the values of the PostgreSQL enum type of the
+// field, which are given in its struct tag `enum`, as in
+// `enum:"status_type,active,disabled,banned"`. It returns false if the field
+// has no such tag.
+func (f *Field) Enum() (name string, values []string, ok bool) {
+	tag, ok := f.Tag.Lookup("enum")
+	if !ok {
+		return "", nil, false
+	}
+
+	parts := strings.Split(tag, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts[0], parts[1:], true
+}
+
+// EnumFields returns the fields of the model with the struct tag `enum`.
+func (m *Model) EnumFields() []*Field {
+	return enumFields(m.Fields)
+}
+
+func enumFields(fields []*Field) []*Field {
+	var result []*Field
+	for _, f := range fields {
+		if f.Inline() {
+			result = append(result, enumFields(f.Fields)...)
+		} else if _, _, ok := f.Enum(); ok {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+var enumNameRegex = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// validateEnums returns an error if the struct tag `enum` of any of the
+// given fields has an invalid name or values, or is in a field whose values
+// are not strings.
+func validateEnums(fields []*Field) error {
+	for _, f := range enumFields(fields) {
+		if err := validateEnum(f); err != nil {
+			return fmt.Errorf("kallax: struct tag `enum` %s. On field %s of model %s.", err, f.Name, f.Model.Name)
+		}
+	}
+	return nil
+}
+
+func validateEnum(f *Field) error {
+	name, values, _ := f.Enum()
+	if !enumNameRegex.MatchString(name) {
+		return fmt.Errorf("has an invalid type name %q", name)
+	}
+
+	if len(values) == 0 {
+		return fmt.Errorf("needs at least one value")
+	}
+
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		switch {
+		case v == "":
+			return fmt.Errorf("has an empty value")
+		case strings.IndexFunc(v, unicode.IsSpace) >= 0:
+			return fmt.Errorf("has value %q with spaces", v)
+		case seen[v]:
+			return fmt.Errorf("has value %q repeated", v)
+		}
+		seen[v] = true
+	}
+
+	if f.SQLType() != "" {
+		return fmt.Errorf("can not be used with the struct tag `sqltype`")
+	}
+
+	if f.Kind == Slice || f.Kind == Array || validationType(f) != "string" {
+		return fmt.Errorf("can only be used in strings")
+	}
+	return nil
+}
+
+// enumRule returns the validation rule of the values of the enum type of the
+// given field.
+func enumRule(f *Field) ValidationRule {
+	_, values, _ := f.Enum()
+	return ValidationRule{Name: "oneof", Param: strings.Join(values, " ")}
+}
+
+// EnumSchema represents the SQL schema of an enum type.
+type EnumSchema struct {
+	// Name is the type name.
+	Name string
+	// Values are the values of the type, in order.
+	Values []string
+}
+
+func (s *EnumSchema) String() string {
+	values := make([]string, len(s.Values))
+	for i, v := range s.Values {
+		values[i] = quoteEnumValue(v)
+	}
+	return fmt.Sprintf("CREATE TYPE %s AS ENUM (%s);\n\n", s.Name, strings.Join(values, ", "))
+}
+
+func (s *EnumSchema) Equals(s2 *EnumSchema) bool {
+	return s.Name == s2.Name && strings.Join(s.Values, ",") == strings.Join(s2.Values, ",")
+}
+
+func quoteEnumValue(v string) string {
+	return "'" + strings.Replace(v, "'", "''", -1) + "'"
+}
+
+// enumDiff returns the changes that turn the given old enum type into the
+// given new one. Values can only be added, as PostgreSQL can neither remove
+// nor reorder the values of an enum type, so any other change requires a
+// manual change.
+func enumDiff(old, new *EnumSchema) ChangeSet {
+	var (
+		cs   ChangeSet
+		next int
+	)
+	for i, v := range new.Values {
+		if next < len(old.Values) && old.Values[next] == v {
+			next++
+			continue
+		}
+
+		if enumHasValue(old, v) {
+			break
+		}
+
+		c := &AddEnumValue{Enum: new.Name, Value: v}
+		if i > 0 {
+			c.After = new.Values[i-1]
+		} else {
+			c.Before = old.Values[0]
+		}
+		cs = append(cs, c)
+	}
+
+	if next < len(old.Values) {
+		return ChangeSet{&ManualChange{
+			fmt.Sprintf("don't know how to generate migration for removing or reordering the values of enum type %s", new.Name),
+		}}
+	}
+	return cs
+}
+
+func enumHasValue(e *EnumSchema, value string) bool {
+	for _, v := range e.Values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateEnum is a change that will add a new enum type.
+type CreateEnum struct {
+	*EnumSchema
+}
+
+func (c *CreateEnum) Reverse(old *DBSchema) Change {
+	return &DropEnum{Name: c.Name}
+}
+
+func (c *CreateEnum) MarshalText() ([]byte, error) {
+	return []byte(c.EnumSchema.String()), nil
+}
+
+func (c *CreateEnum) String() string {
+	return fmt.Sprintf("A new enum type %q has been added with the following values: %s.", c.Name, strings.Join(c.Values, ", "))
+}
+
+// DropEnum is a change that will drop an enum type.
+type DropEnum struct {
+	// Name is the name of the type to drop.
+	Name string
+}
+
+func (c *DropEnum) Reverse(old *DBSchema) Change {
+	return &CreateEnum{old.Enum(c.Name)}
+}
+
+func (c *DropEnum) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("DROP TYPE %s;\n", c.Name)), nil
+}
+
+func (c *DropEnum) String() string {
+	return fmt.Sprintf("Enum type %q has been deleted, and it will be dropped.", c.Name)
+}
+
+// AddEnumValue is a change that will add a value to an enum type, in the
+// position given by the value before or after it. The value is added by its
+// own migration, outside any transaction, see Migration.Steps.
+type AddEnumValue struct {
+	// Enum is the name of the enum type.
+	Enum string
+	// Value is the added value.
+	Value string
+	// Before is the value the added value goes before, if it's the first one.
+	Before string
+	// After is the value the added value goes after, if it's not the first
+	// one.
+	After string
+}
+
+func (c *AddEnumValue) Reverse(old *DBSchema) Change {
+	return &ManualChange{
+		fmt.Sprintf("value %s can not be removed from enum type %s", c.Value, c.Enum),
+	}
+}
+
+func (c *AddEnumValue) MarshalText() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("ALTER TYPE %s ADD VALUE IF NOT EXISTS %s", c.Enum, quoteEnumValue(c.Value)))
+	if c.After != "" {
+		buf.WriteString(" AFTER " + quoteEnumValue(c.After))
+	} else if c.Before != "" {
+		buf.WriteString(" BEFORE " + quoteEnumValue(c.Before))
+	}
+	buf.WriteString(";\n")
+	return buf.Bytes(), nil
+}
+
+func (c *AddEnumValue) String() string {
+	return fmt.Sprintf("The value %q has been added to enum type %q.", c.Value, c.Enum)
+}
+
+// transformEnum adds the schema of the enum type of the given field to the
+// schema.
+func (t *packageTransformer) transformEnum(f *Field) error {
+	name, values, _ := f.Enum()
+	schema := &EnumSchema{Name: name, Values: values}
+	if prevEnum, ok := t.enums[name]; ok {
+		if !prevEnum.Equals(schema) {
+			return fmt.Errorf("kallax: found more than one definition for enum type %s", name)
+		}
+		return nil
+	}
+
+	t.schema.Enums = append(t.schema.Enums, schema)
+	t.enums[name] = schema
+	return nil
+}
+
+// GenEnums generates the constants of the values of the enum types of the
+// fields of the given model, which are named after the model, the field and
+// the value, such as UserStatusActive.
+func (td *TemplateData) GenEnums(model *Model) string {
+	var buf bytes.Buffer
+	for _, f := range model.EnumFields() {
+		name, values, _ := f.Enum()
+		typ := strings.TrimPrefix(td.GenTypeName(f), "*")
+		buf.WriteString(fmt.Sprintf("\n// The values of the enum type %s of %s.%s.\nconst (\n", name, model.Name, f.Name))
+		for _, v := range values {
+			buf.WriteString(fmt.Sprintf("%s%s%s %s = %q\n", model.Name, f.Name, enumConstName(v), typ, v))
+		}
+		buf.WriteString(")\n")
+	}
+	return buf.String()
+}
+
+// enumConstName returns the given enum value in upper camel case, without the
+// characters that can not be part of an identifier.
+func enumConstName(v string) string {
+	var buf bytes.Buffer
+	upper := true
+	for _, r := range v {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			upper = true
+		case upper:
+			buf.WriteRune(unicode.ToUpper(r))
+			upper = false
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
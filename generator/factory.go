@@ -0,0 +1,138 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// GenFactoryWiths generates the With methods of the factory of the given
+// model, which set the value of a field in all the records it builds. There
+// is one for every field but the autoincrementable primary keys and the
+// generated columns, named after the field as in the schema of the model.
+func (td *TemplateData) GenFactoryWiths(model *Model) string {
+	var buf bytes.Buffer
+	td.genFactoryWiths(&buf, model, model.Fields)
+	return buf.String()
+}
+
+const factoryWithTpl = `
+// With%[1]s makes the factory set the field %[2]s of all the records it
+// builds to the given value, and returns the factory.
+func (f *%[3]sFactory) With%[1]s(v %[4]s) *%[3]sFactory {
+	return f.Sequence(func(record *%[3]s, _ int) {
+		record.%[2]s = v
+	})
+}
+`
+
+func (td *TemplateData) genFactoryWiths(buf *bytes.Buffer, model *Model, fields []*Field) {
+	for _, f := range fields {
+		if f.Inline() {
+			td.genFactoryWiths(buf, model, f.Fields)
+			continue
+		}
+
+		if f.IsAutoIncrement() || f.IsGenerated() {
+			continue
+		}
+
+		fmt.Fprintf(buf, factoryWithTpl, f.SchemaName(), promotedFieldName(f), model.Name, typeString(f.Node.Type(), td.pkg))
+	}
+}
+
+// GenFactoryRecord generates the code that fills the fields of the record
+// number n built by the factory of the given model. Primary keys that are
+// not autoincrementable are set as in the benchmarks. Fields of enum types or
+// with the rule `oneof` are set to their first value, strings to a value made
+// of the column name and the number of the record, which is an email address
+// or a URL with the rules `email` and `url`, and unique or required integers
+// to the number of the record. The rest of fields, such as pointers and
+// relationships, are left empty.
+func (td *TemplateData) GenFactoryRecord(model *Model) string {
+	var buf bytes.Buffer
+	td.genRecordID(&buf, model, "n")
+	td.genFactoryFields(&buf, model, model.Fields)
+	return buf.String()
+}
+
+func (td *TemplateData) genFactoryFields(buf *bytes.Buffer, model *Model, fields []*Field) {
+	for _, f := range fields {
+		if f.Inline() {
+			td.genFactoryFields(buf, model, f.Fields)
+			continue
+		}
+
+		if f.IsPrimaryKey() || f.IsGenerated() || f.IsTenant() || f.IsLock() || f.IsSoftDelete() || f.IsPtr || f.Kind != Basic {
+			continue
+		}
+
+		if value, ok := td.factoryValue(model, f); ok {
+			fmt.Fprintf(buf, "record.%s = %s\n", promotedFieldName(f), value)
+		}
+	}
+}
+
+// factoryValue returns the fake value of the given field of the given model
+// in the record number n, or false if the field is left empty.
+func (td *TemplateData) factoryValue(model *Model, f *Field) (string, bool) {
+	basic, ok := f.Node.Type().Underlying().(*types.Basic)
+	if !ok {
+		return "", false
+	}
+
+	isString := basic.Info()&types.IsString != 0
+	isInteger := basic.Info()&types.IsInteger != 0
+	if !isString && !isInteger {
+		return "", false
+	}
+
+	if _, values, ok := f.Enum(); ok {
+		return model.Name + f.Name + enumConstName(values[0]), true
+	}
+
+	var required, email, url bool
+	for _, r := range f.ValidationRules() {
+		switch r.Name {
+		case "oneof":
+			value := strings.Fields(r.Param)[0]
+			if isString {
+				value = fmt.Sprintf("%q", value)
+			}
+			return td.convertFactoryValue(f, value, isString), true
+		case "required":
+			required = true
+		case "email":
+			email = true
+		case "url":
+			url = true
+		}
+	}
+
+	if isInteger {
+		if !f.IsUnique() && !required {
+			return "", false
+		}
+		return td.convertFactoryValue(f, "n", isString), true
+	}
+
+	format := f.ColumnName() + "-%d"
+	switch {
+	case email:
+		format += "@example.com"
+	case url:
+		format = "https://example.com/" + format
+	}
+	return td.convertFactoryValue(f, fmt.Sprintf("fmt.Sprintf(%q, n)", format), isString), true
+}
+
+// convertFactoryValue converts the given value, which is a string or an int,
+// to the type of the given field, if it's another one.
+func (td *TemplateData) convertFactoryValue(f *Field, value string, isString bool) string {
+	typ := td.GenTypeName(f)
+	if (isString && typ == "string") || (!isString && typ == "int") || strings.HasPrefix(value, `"`) {
+		return value
+	}
+	return fmt.Sprintf("%s(%s)", typ, value)
+}
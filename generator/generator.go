@@ -15,16 +15,57 @@ import (
 	"time"
 
 	"github.com/fatih/color"
+	"github.com/pmezard/go-difflib/difflib"
 )
 
 // Generator is in charge of generating files for packages.
 type Generator struct {
 	filename string
+	template *Template
 }
 
 // NewGenerator creates a new generator that can save on the given filename.
 func NewGenerator(filename string) *Generator {
-	return &Generator{filename}
+	return &Generator{filename, Base}
+}
+
+// NewSQLMockGenerator creates a new generator that can save on the given
+// filename the helpers to set up go-sqlmock expectations of the statements
+// run by the stores of the models.
+func NewSQLMockGenerator(filename string) *Generator {
+	return &Generator{filename, SQLMock}
+}
+
+// NewBenchmarkGenerator creates a new generator that can save on the given
+// filename the benchmarks of the stores of the models.
+func NewBenchmarkGenerator(filename string) *Generator {
+	return &Generator{filename, Benchmark}
+}
+
+// NewProtoGenerator creates a new generator that can save on the given
+// filename the Protocol Buffers definition of the messages and gRPC services
+// of the models.
+func NewProtoGenerator(filename string) *Generator {
+	return &Generator{filename, Proto}
+}
+
+// NewGRPCGenerator creates a new generator that can save on the given
+// filename the implementation of the gRPC services of the models, backed by
+// their stores.
+func NewGRPCGenerator(filename string) *Generator {
+	return &Generator{filename, GRPC}
+}
+
+// NewMockGenerator creates a new generator that can save on the given
+// filename the in-memory mock stores of the models.
+func NewMockGenerator(filename string) *Generator {
+	return &Generator{filename, Mock}
+}
+
+// NewFactoryGenerator creates a new generator that can save on the given
+// filename the factories of records of the models for tests.
+func NewFactoryGenerator(filename string) *Generator {
+	return &Generator{filename, Factory}
 }
 
 // Generate writes the file with the contents of the given package.
@@ -32,6 +73,34 @@ func (g *Generator) Generate(pkg *Package) error {
 	return g.writeFile(pkg)
 }
 
+// Diff returns the differences, in unified format, between the file of the
+// generator and the contents that would be generated for the given package,
+// without writing anything. If the file is up to date, the result is empty.
+// A file that does not exist is considered empty.
+func (g *Generator) Diff(pkg *Package) (string, error) {
+	var buf bytes.Buffer
+	if err := g.template.Execute(&buf, pkg); err != nil {
+		return "", err
+	}
+
+	current, err := ioutil.ReadFile(g.filename)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	if bytes.Equal(current, buf.Bytes()) {
+		return "", nil
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(current)),
+		B:        difflib.SplitLines(buf.String()),
+		FromFile: g.filename,
+		ToFile:   g.filename + " (generated)",
+		Context:  3,
+	})
+}
+
 func (g *Generator) writeFile(pkg *Package) (err error) {
 	file, err := os.Create(g.filename)
 	if err != nil {
@@ -56,7 +125,7 @@ func (g *Generator) writeFile(pkg *Package) (err error) {
 		}
 	}()
 
-	return Base.Execute(file, pkg)
+	return g.template.Execute(file, pkg)
 }
 
 // Timestamper is a function that returns the current time.
@@ -64,9 +133,20 @@ type Timestamper func() time.Time
 
 // MigrationGenerator is a generator of migrations.
 type MigrationGenerator struct {
-	name string
-	dir  string
-	now  Timestamper
+	name    string
+	dir     string
+	now     Timestamper
+	silent  bool
+	dialect string
+	// dropDeprecated reports whether the migration drops the tables and
+	// columns of the deprecated models and fields.
+	dropDeprecated bool
+	// data reports whether the stub of a data migration is generated along
+	// with the migration.
+	data bool
+	// dryRun reports whether the SQL of the migration is printed instead of
+	// written.
+	dryRun bool
 }
 
 type migrationFileType string
@@ -80,7 +160,45 @@ const (
 // NewMigrationGenerator returns a new migration generator with the given
 // migrations directory.
 func NewMigrationGenerator(name, dir string) *MigrationGenerator {
-	return &MigrationGenerator{slugify(name), dir, time.Now}
+	return &MigrationGenerator{slugify(name), dir, time.Now, false, "postgres", false, false, false}
+}
+
+// Silent makes the generator not print the proposed changes to stdout.
+func (g *MigrationGenerator) Silent() {
+	g.silent = true
+}
+
+// DropDeprecated makes the generator build the final migration of the
+// deprecated models and fields, which drops their tables and columns.
+func (g *MigrationGenerator) DropDeprecated() {
+	g.dropDeprecated = true
+}
+
+// Data makes the generator write also the Go file of the migration, named as
+// its SQL files but with the extension .go, with the stub of a DataMigration
+// registered with its version, to fill in with the changes of the data that
+// go along with the changes of the schema.
+func (g *MigrationGenerator) Data() {
+	g.data = true
+}
+
+// DryRun makes the generator print the SQL of the migration after the
+// proposed changes, unless it's silent, instead of writing any file.
+func (g *MigrationGenerator) DryRun() {
+	g.dryRun = true
+}
+
+// SetDialect makes the generator build the migrations for the dialect with
+// the given name, which can be postgres, the default one, or cockroachdb.
+// The same dialect must be used for all the migrations of a directory, as the
+// lock stores the adapted schema.
+func (g *MigrationGenerator) SetDialect(dialect string) error {
+	if _, ok := schemaDialects[dialect]; !ok {
+		return errUnknownDialect(dialect)
+	}
+
+	g.dialect = dialect
+	return nil
 }
 
 // Build creates a new migration from a set of scanned packages.
@@ -95,6 +213,14 @@ func (g *MigrationGenerator) Build(pkgs ...*Package) (*Migration, error) {
 		return nil, err
 	}
 
+	if new, err = SchemaForDialect(new, g.dialect); err != nil {
+		return nil, err
+	}
+
+	if g.dropDeprecated {
+		new = DropDeprecated(new)
+	}
+
 	return NewMigration(old, new)
 }
 
@@ -104,10 +230,50 @@ func (g *MigrationGenerator) Generate(migration *Migration) error {
 	if len(migration.Up) == 0 {
 		return nil
 	}
+
+	if g.dryRun {
+		return g.printMigration(migration)
+	}
 	return g.writeMigration(migration)
 }
 
+// printMigration prints the SQL of the files of the given migration.
+func (g *MigrationGenerator) printMigration(migration *Migration) error {
+	if g.silent {
+		return nil
+	}
+
+	type file struct {
+		file    string
+		content encoding.TextMarshaler
+	}
+
+	var files []file
+	now := g.now()
+	for i, step := range migration.Steps() {
+		t := stepTime(now, i)
+		files = append(files,
+			file{g.migrationFile(migrationUp, t), step.Up},
+			file{g.migrationFile(migrationDown, t), step.Down},
+		)
+	}
+
+	fmt.Println("\nThis is the SQL that would be written, but nothing has been written:")
+	for _, f := range files {
+		data, err := f.content.MarshalText()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("\n-- %s\n%s", f.file, data)
+	}
+	return nil
+}
+
 func (g *MigrationGenerator) printMigrationInfo(migration *Migration) {
+	if g.silent {
+		return
+	}
+
 	if len(migration.Up) == 0 {
 		fmt.Println("There are no changes since last migration. Nothing will be generated.")
 		return
@@ -145,26 +311,50 @@ func (g *MigrationGenerator) LoadLock() (*DBSchema, error) {
 	return &schema, nil
 }
 
+// WriteLock writes the given schema as the lock file, so the next migration
+// contains the changes of the models with respect to it.
+func (g *MigrationGenerator) WriteLock(schema *DBSchema) error {
+	return g.createFile(filepath.Join(g.dir, string(migrationLock)), schema)
+}
+
 func (g *MigrationGenerator) writeMigration(migration *Migration) error {
-	t := g.now()
-	files := []struct {
-		file    string
-		content encoding.TextMarshaler
-	}{
-		{filepath.Join(g.dir, string(migrationLock)), migration.Lock},
-		{g.migrationFile(migrationDown, t), migration.Down},
-		{g.migrationFile(migrationUp, t), migration.Up},
+	if err := g.createFile(filepath.Join(g.dir, string(migrationLock)), migration.Lock); err != nil {
+		return err
 	}
 
-	for _, f := range files {
-		if err := g.createFile(f.file, f.content); err != nil {
+	var (
+		now = g.now()
+		t   time.Time
+	)
+	for i, step := range migration.Steps() {
+		t = stepTime(now, i)
+		if err := g.createFile(g.migrationFile(migrationDown, t), step.Down); err != nil {
+			return err
+		}
+
+		if err := g.createFile(g.migrationFile(migrationUp, t), step.Up); err != nil {
 			return err
 		}
 	}
 
+	if g.data {
+		file := filepath.Join(g.dir, fmt.Sprintf("%d_%s.go", t.Unix(), g.name))
+		stub := g.dataMigrationStub(t.Unix(), g.migrationFile(migrationUp, t), g.migrationFile(migrationDown, t))
+		if err := ioutil.WriteFile(file, stub, 0644); err != nil {
+			return fmt.Errorf("error writing file: %s: %s", file, err)
+		}
+	}
+
 	return nil
 }
 
+// stepTime returns the time of the version of the given step of a migration
+// generated at the given time, see Migration.Steps. The steps are one second
+// apart, so they are run in order.
+func stepTime(t time.Time, step int) time.Time {
+	return t.Add(time.Duration(step) * time.Second)
+}
+
 func (g *MigrationGenerator) migrationFile(typ migrationFileType, t time.Time) string {
 	return filepath.Join(g.dir, fmt.Sprintf("%d_%s.%s", t.Unix(), g.name, typ))
 }
@@ -1,6 +1,8 @@
 package generator
 
 import (
+	"go/parser"
+	"go/token"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -10,6 +12,48 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func TestGeneratorDiff(t *testing.T) {
+	pkg, err := processFixture(`
+	package foo
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Foo struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+	}
+	`)
+	require.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "kallax-generator")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "kallax.go")
+	g := NewGenerator(file)
+
+	diff, err := g.Diff(pkg)
+	require.NoError(t, err)
+	require.Contains(t, diff, "+++ "+file+" (generated)")
+	require.Contains(t, diff, "+func NewFooStore(db *sql.DB) *FooStore {")
+
+	_, err = os.Stat(file)
+	require.True(t, os.IsNotExist(err), "diff must not write the file")
+
+	require.NoError(t, g.Generate(pkg))
+	diff, err = g.Diff(pkg)
+	require.NoError(t, err)
+	require.Empty(t, diff)
+
+	content, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(file, append(content, "// stale\n"...), 0644))
+
+	diff, err = g.Diff(pkg)
+	require.NoError(t, err)
+	require.Contains(t, diff, "-// stale")
+}
+
 func TestMigrationGeneratorLoadLock(t *testing.T) {
 	dir, err := ioutil.TempDir("", "kallax-migration-generator")
 	require.NoError(t, err)
@@ -50,6 +94,32 @@ func TestMigrationGeneratorBuild(t *testing.T) {
 	require.NotNil(t, migration)
 }
 
+func TestMigrationGeneratorSetDialect(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kallax-migration-generator")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	g := NewMigrationGenerator("migration", dir)
+	require.Error(t, g.SetDialect("oracle"))
+	require.NoError(t, g.SetDialect("cockroachdb"))
+
+	pkg, err := processFixture(`
+	package foo
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Foo struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+	}
+	`)
+	require.NoError(t, err)
+
+	migration, err := g.Build(pkg)
+	require.NoError(t, err)
+	require.Equal(t, cockroachDBSerialColumn, migration.Lock.Tables[0].Columns[0].Type)
+}
+
 func TestMigrationGeneratorGenerate(t *testing.T) {
 	old := mkSchema(table1)
 	new := mkSchema(table1, table2)
@@ -84,6 +154,120 @@ func TestMigrationGeneratorGenerate(t *testing.T) {
 	require.Equal(t, string(expected), string(content))
 }
 
+func TestMigrationGeneratorGenerateSteps(t *testing.T) {
+	old := mkSchema(table1)
+	old.Enums = []*EnumSchema{{Name: "status_type", Values: []string{"active"}}}
+	new := mkSchema(table1, table2)
+	new.Enums = []*EnumSchema{{Name: "status_type", Values: []string{"active", "banned"}}}
+	migration, err := NewMigration(old, new)
+	require.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "kallax-migration-generator")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	g := NewMigrationGenerator("migration", dir)
+	g.Silent()
+	g.now = func() time.Time {
+		return time.Unix(1500000000, 0)
+	}
+	require.NoError(t, g.Generate(migration))
+
+	files := map[string]string{
+		"1500000000_migration.up.sql":   "ALTER TYPE status_type ADD VALUE IF NOT EXISTS 'banned' AFTER 'active';\n",
+		"1500000000_migration.down.sql": "BEGIN;\n\n+++ THIS REQUIRES MANUAL MIGRATION: value banned can not be removed from enum type status_type +++\n\nCOMMIT;\n",
+		"1500000001_migration.up.sql":   "BEGIN;\n\n" + expectedTable2 + "\n\nCOMMIT;\n",
+		"1500000001_migration.down.sql": "BEGIN;\n\nDROP TABLE table2;\n\nCOMMIT;\n",
+	}
+	for file, expected := range files {
+		content, err := ioutil.ReadFile(filepath.Join(dir, file))
+		require.NoError(t, err)
+		require.Equal(t, expected, string(content), file)
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, len(files)+1)
+}
+
+func TestMigrationGeneratorGenerateDryRun(t *testing.T) {
+	migration, err := NewMigration(mkSchema(table1), mkSchema(table1, table2))
+	require.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "kallax-migration-generator")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	g := NewMigrationGenerator("migration", dir)
+	g.DryRun()
+	g.Silent()
+	require.NoError(t, g.Generate(migration))
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, files)
+}
+
+func TestMigrationGeneratorGenerateData(t *testing.T) {
+	migration, err := NewMigration(mkSchema(table1), mkSchema(table1, table2))
+	require.NoError(t, err)
+
+	tmp, err := ioutil.TempDir("", "kallax-migration-generator")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+	dir := filepath.Join(tmp, "migrations")
+	require.NoError(t, os.Mkdir(dir, 0755))
+
+	g := NewMigrationGenerator("add table2", dir)
+	g.Data()
+	g.now = func() time.Time {
+		return time.Unix(1500000000, 0)
+	}
+	require.NoError(t, g.Generate(migration))
+
+	file := filepath.Join(dir, "1500000000_add_table2.go")
+	content, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+	require.Contains(t, string(content), "generator.RegisterDataMigration(1500000000, generator.DataMigration{\n")
+	require.Contains(t, string(content), "// Up is run after 1500000000_add_table2.up.sql.\n")
+
+	f, err := parser.ParseFile(token.NewFileSet(), file, content, 0)
+	require.NoError(t, err)
+	require.Equal(t, "migrations", f.Name.Name)
+	require.Equal(t, "migrations", dataMigrationPackage(dir))
+
+	data, err := dataMigrationFiles(dir)
+	require.NoError(t, err)
+	require.Equal(t, map[uint]bool{1500000000: true}, data)
+
+	for _, f := range []string{"1400000000_initial.up.sql", "1400000000_initial.down.sql"} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, f), nil, 0755))
+	}
+	_, _, err = squashMigrations(dir, 1600000000, "baseline", 1500000000, false)
+	require.EqualError(t, err, "kallax: migration 1500000000_add_table2 has a data migration, which can not be squashed, so its Go file must be removed first")
+}
+
+func TestRegisterDataMigration(t *testing.T) {
+	defer delete(dataMigrations, 42)
+
+	RegisterDataMigration(42, DataMigration{})
+	_, ok := registeredDataMigration(42)
+	require.True(t, ok)
+	require.Panics(t, func() {
+		RegisterDataMigration(42, DataMigration{})
+	})
+}
+
+func TestTransactionBody(t *testing.T) {
+	stmts, ok := transactionBody("BEGIN;\n\nCREATE TABLE foo (id serial);\n\nCOMMIT;\n")
+	require.True(t, ok)
+	require.Equal(t, "CREATE TABLE foo (id serial);", stmts)
+
+	stmts, ok = transactionBody("CREATE INDEX CONCURRENTLY foo_idx ON foo (id);\n")
+	require.False(t, ok)
+	require.Equal(t, "CREATE INDEX CONCURRENTLY foo_idx ON foo (id);", stmts)
+}
+
 func TestSlugify(t *testing.T) {
 	cases := []struct {
 		input    string
@@ -99,3 +283,146 @@ func TestSlugify(t *testing.T) {
 		require.Equal(t, c.expected, slugify(c.input))
 	}
 }
+
+func TestMigrationStatus(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kallax-migration-runner")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, f := range []string{
+		"20_add_posts.up.sql",
+		"20_add_posts.down.sql",
+		"10_initial.up.sql",
+		"10_initial.down.sql",
+		"30_add_tags.up.sql",
+		"lock.json",
+	} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, f), nil, 0755))
+	}
+
+	status, err := migrationStatus(dir, 20, true)
+	require.NoError(t, err)
+	require.Equal(t, []*MigrationStatus{
+		{Version: 10, Name: "initial", Applied: true},
+		{Version: 20, Name: "add_posts", Applied: true, Dirty: true},
+		{Version: 30, Name: "add_tags"},
+	}, status)
+}
+
+func TestSquashMigrations(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kallax-migration-squash")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"10_initial.up.sql":   "BEGIN;\n\nCREATE TABLE foo (id serial);\n\nCOMMIT;\n",
+		"10_initial.down.sql": "BEGIN;\n\nDROP TABLE foo;\n\nCOMMIT;\n",
+		"20_add_bar.up.sql":   "BEGIN;\n\nCREATE TABLE bar (id serial);\n\nCOMMIT;\n",
+		"20_add_bar.down.sql": "BEGIN;\n\nDROP TABLE bar;\n\nCOMMIT;\n",
+		"30_add_baz.up.sql":   "CREATE TABLE baz (id serial);\n",
+		"30_add_baz.down.sql": "DROP TABLE baz;\n",
+		"40_add_qux.up.sql":   "BEGIN;\n\nCREATE TABLE qux (id serial);\n\nCOMMIT;\n",
+		"40_add_qux.down.sql": "BEGIN;\n\nDROP TABLE qux;\n\nCOMMIT;\n",
+		"lock.json":           "{}",
+	}
+	for f, content := range files {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, f), []byte(content), 0755))
+	}
+
+	_, _, err = squashMigrations(dir, 40, "baseline", 30, true)
+	require.EqualError(t, err, "kallax: the database is dirty at version 30, it must be fixed by hand before squashing the migrations")
+	_, _, err = squashMigrations(dir, 20, "baseline", 30, false)
+	require.EqualError(t, err, "kallax: there must be at least two migrations before version 20 to squash them")
+	_, _, err = squashMigrations(dir, 40, "baseline", 20, false)
+	require.EqualError(t, err, "kallax: the database is at version 20, but only the migrations that have been run can be squashed and migration 30 has not")
+
+	baseline, squashed, err := squashMigrations(dir, 40, "baseline", 30, false)
+	require.NoError(t, err)
+	require.Equal(t, &MigrationStatus{Version: 30, Name: "baseline", Applied: true}, baseline)
+	require.Len(t, squashed, 3)
+
+	status, err := migrationStatus(dir, 30, false)
+	require.NoError(t, err)
+	require.Equal(t, []*MigrationStatus{
+		{Version: 30, Name: "baseline", Applied: true},
+		{Version: 40, Name: "add_qux"},
+	}, status)
+
+	up, err := ioutil.ReadFile(filepath.Join(dir, "30_baseline.up.sql"))
+	require.NoError(t, err)
+	require.Equal(t, "BEGIN;\n\n"+
+		"-- 10_initial\nCREATE TABLE foo (id serial);\n\n"+
+		"-- 20_add_bar\nCREATE TABLE bar (id serial);\n\n"+
+		"-- 30_add_baz\nCREATE TABLE baz (id serial);\n\n"+
+		"COMMIT;\n", string(up))
+
+	down, err := ioutil.ReadFile(filepath.Join(dir, "30_baseline.down.sql"))
+	require.NoError(t, err)
+	require.Equal(t, "BEGIN;\n\n"+
+		"-- 30_add_baz\nDROP TABLE baz;\n\n"+
+		"-- 20_add_bar\nDROP TABLE bar;\n\n"+
+		"-- 10_initial\nDROP TABLE foo;\n\n"+
+		"COMMIT;\n", string(down))
+}
+
+func TestSquashMigrations_Lock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kallax-migration-squash")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, f := range []string{
+		"10_initial.up.sql",
+		"10_initial.down.sql",
+		"20_add_bar.up.sql",
+		"20_add_bar.down.sql",
+	} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, f), nil, 0755))
+	}
+
+	_, _, err = squashMigrations(dir, 30, "baseline", 20, false)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "kallax: unable to find the lock file of the migrations: ")
+
+	lock := mkSchema(
+		mkTable(
+			"foo",
+			mkCol("id", SerialColumn, true, true, nil),
+		),
+		mkTable(
+			"bar",
+			mkCol("id", SerialColumn, true, true, nil),
+			mkCol("foo_id", BigIntColumn, false, true, mkRef("foo", "id", false)),
+		),
+	)
+	require.NoError(t, NewMigrationGenerator("migration", dir).WriteLock(lock))
+
+	baseline, _, err := squashMigrations(dir, 30, "squashed", 20, false)
+	require.NoError(t, err)
+	require.Equal(t, &MigrationStatus{Version: 20, Name: "squashed", Applied: true}, baseline)
+
+	status, err := migrationStatus(dir, 20, false)
+	require.NoError(t, err)
+	require.Equal(t, []*MigrationStatus{baseline}, status)
+
+	up, err := ioutil.ReadFile(filepath.Join(dir, "20_squashed.up.sql"))
+	require.NoError(t, err)
+	require.Equal(t, `BEGIN;
+
+CREATE TABLE foo (
+	id serial NOT NULL PRIMARY KEY
+);
+
+
+CREATE TABLE bar (
+	id serial NOT NULL PRIMARY KEY,
+	foo_id bigint NOT NULL REFERENCES foo(id)
+);
+
+
+COMMIT;
+`, string(up))
+
+	down, err := ioutil.ReadFile(filepath.Join(dir, "20_squashed.down.sql"))
+	require.NoError(t, err)
+	require.Equal(t, "BEGIN;\n\nDROP TABLE bar;\n\nDROP TABLE foo;\n\nCOMMIT;\n", string(down))
+}
@@ -0,0 +1,416 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// protoTypes are the Protocol Buffers types of the values of the basic Go
+// types, along with the Go types of their fields in the code generated by
+// protoc.
+var protoTypes = map[types.BasicKind][2]string{
+	types.Bool:    {"bool", "bool"},
+	types.String:  {"string", "string"},
+	types.Int:     {"int64", "int64"},
+	types.Int8:    {"int32", "int32"},
+	types.Int16:   {"int32", "int32"},
+	types.Int32:   {"int32", "int32"},
+	types.Int64:   {"int64", "int64"},
+	types.Uint:    {"uint64", "uint64"},
+	types.Uint8:   {"uint32", "uint32"},
+	types.Uint16:  {"uint32", "uint32"},
+	types.Uint32:  {"uint32", "uint32"},
+	types.Uint64:  {"uint64", "uint64"},
+	types.Float32: {"float", "float32"},
+	types.Float64: {"double", "float64"},
+}
+
+// protoConversion is the way the values of a field are converted to the
+// values of the field of its message and back.
+type protoConversion int
+
+const (
+	// protoScalar values are converted with Go type conversions.
+	protoScalar protoConversion = iota
+	// protoText values are identifiers encoded as their text
+	// representation, and parsed with their Scan method.
+	protoText
+	// protoTimestamp values are times encoded as google.protobuf.Timestamp.
+	protoTimestamp
+)
+
+// protoField is a field of a model exposed in its Protocol Buffers message.
+type protoField struct {
+	*Field
+	// path is the path of the field in the record, with the names of the
+	// inlined struct fields separated by dots.
+	path string
+	// typ is the Protocol Buffers type of the field.
+	typ string
+	// goType is the Go type of the values of the field in the record.
+	goType string
+	// protoGoType is the Go type of the values of the field of the message.
+	protoGoType string
+	conversion  protoConversion
+	repeated    bool
+}
+
+// name returns the name of the field in the message, which is the name of
+// its column.
+func (f *protoField) name() string {
+	return f.ColumnName()
+}
+
+// goName returns the name of the field of the message in the Go code
+// generated by protoc.
+func (f *protoField) goName() string {
+	return protoGoName(f.name())
+}
+
+// filterable reports whether the records can be filtered by the field in
+// the list requests.
+func (f *protoField) filterable() bool {
+	return !f.repeated && f.protoGoType != "[]byte"
+}
+
+// protoGoName returns the name protoc gives in Go to the given name of a
+// field, converting it to camel case.
+func protoGoName(name string) string {
+	var buf bytes.Buffer
+	for i := 0; i < len(name); i++ {
+		switch c := name[i]; {
+		case c == '_' && i == 0:
+			buf.WriteByte('X')
+		case c == '_' && i+1 < len(name) && isASCIILower(name[i+1]):
+		case '0' <= c && c <= '9':
+			buf.WriteByte(c)
+		default:
+			if isASCIILower(c) {
+				c -= 'a' - 'A'
+			}
+			buf.WriteByte(c)
+			for ; i+1 < len(name) && isASCIILower(name[i+1]); i++ {
+				buf.WriteByte(name[i+1])
+			}
+		}
+	}
+	return buf.String()
+}
+
+func isASCIILower(c byte) bool {
+	return 'a' <= c && c <= 'z'
+}
+
+// protoFields returns the fields of the given model that are exposed in its
+// Protocol Buffers message. Relationships, JSON and composite fields, and
+// the fields of types without a Protocol Buffers counterpart are left out.
+func (td *TemplateData) protoFields(model *Model) []*protoField {
+	return td.protoFieldsOf("", model.Fields)
+}
+
+func (td *TemplateData) protoFieldsOf(parent string, fields []*Field) []*protoField {
+	var result []*protoField
+	for _, f := range fields {
+		if f.Inline() {
+			result = append(result, td.protoFieldsOf(parent+f.Name+".", f.Fields)...)
+			continue
+		}
+
+		if f.Kind == Relationship || f.IsJSON || f.IsComposite || f.Node == nil {
+			continue
+		}
+
+		if pf := td.protoField(f); pf != nil {
+			pf.path = parent + f.Name
+			result = append(result, pf)
+		}
+	}
+	return result
+}
+
+// protoField returns the description of the given field in the Protocol
+// Buffers message of its model, or nil if it can not be exposed.
+func (td *TemplateData) protoField(f *Field) *protoField {
+	typ := f.Node.Type()
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+
+	var repeated bool
+	if slice, ok := typ.(*types.Slice); ok && !isByteSlice(slice) {
+		if f.IsPtr {
+			return nil
+		}
+		repeated, typ = true, slice.Elem()
+	}
+
+	pf := &protoField{Field: f, repeated: repeated, goType: typeString(typ, td.pkg)}
+	switch name := typeName(typ); {
+	case name == "time.Time" && !repeated:
+		pf.typ, pf.protoGoType, pf.conversion = "google.protobuf.Timestamp", "*timestamppb.Timestamp", protoTimestamp
+		return pf
+	case identifierTypes[name] != "" && name != "int64":
+		if repeated || f.IsPtr {
+			return nil
+		}
+		pf.typ, pf.protoGoType, pf.conversion = "string", "string", protoText
+		return pf
+	}
+
+	switch u := typ.Underlying().(type) {
+	case *types.Basic:
+		t, ok := protoTypes[u.Kind()]
+		if !ok {
+			return nil
+		}
+		pf.typ, pf.protoGoType = t[0], t[1]
+	case *types.Slice:
+		if repeated || f.IsPtr || !isByteSlice(u) {
+			return nil
+		}
+		pf.typ, pf.protoGoType = "bytes", "[]byte"
+	default:
+		return nil
+	}
+	return pf
+}
+
+func isByteSlice(t types.Type) bool {
+	slice, ok := t.Underlying().(*types.Slice)
+	if !ok {
+		return false
+	}
+
+	basic, ok := slice.Elem().Underlying().(*types.Basic)
+	return ok && basic.Kind() == types.Byte
+}
+
+// protoID returns the primary key of the model in its Protocol Buffers
+// message, or nil if it can not be exposed.
+func (td *TemplateData) protoID(model *Model) *protoField {
+	for _, f := range td.protoFields(model) {
+		if f.IsPrimaryKey() {
+			return f
+		}
+	}
+	return nil
+}
+
+// HasProtoService reports whether the gRPC service of the given model is
+// generated, which requires its primary key to have a single field exposed
+// in its message.
+func (td *TemplateData) HasProtoService(model *Model) bool {
+	return !model.HasCompositeKey() && td.protoID(model) != nil
+}
+
+// ProtoModels returns the models whose gRPC services are generated.
+func (td *TemplateData) ProtoModels() []*Model {
+	var models []*Model
+	for _, m := range td.Package.Models {
+		if td.HasProtoService(m) {
+			models = append(models, m)
+		}
+	}
+	return models
+}
+
+// UsesProtoTimestamps reports whether any of the messages of the models has
+// a google.protobuf.Timestamp field.
+func (td *TemplateData) UsesProtoTimestamps() bool {
+	for _, m := range td.ProtoModels() {
+		for _, f := range td.protoFields(m) {
+			if f.conversion == protoTimestamp {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GenProtoGoPackage returns the import path of the package with the code
+// generated by protoc for the messages and services of the models.
+func (td *TemplateData) GenProtoGoPackage() string {
+	if path := td.importPath(); path != "" {
+		return path + "/kallaxpb"
+	}
+	return "kallaxpb"
+}
+
+// GenProtoIDType returns the Protocol Buffers type of the primary key of the
+// given model.
+func (td *TemplateData) GenProtoIDType(model *Model) string {
+	return td.protoID(model).typ
+}
+
+// GenProtoIDGoType returns the Go type of the primary key of the given
+// model in the code generated by protoc.
+func (td *TemplateData) GenProtoIDGoType(model *Model) string {
+	return td.protoID(model).protoGoType
+}
+
+// GenProtoIDGoName returns the name of the field with the primary key of the
+// given model in the code generated by protoc.
+func (td *TemplateData) GenProtoIDGoName(model *Model) string {
+	return td.protoID(model).goName()
+}
+
+// GenProtoFields generates the fields of the Protocol Buffers message of the
+// given model. The fields are numbered in the order of the model, so new
+// fields must be added at the end to keep the messages compatible.
+func (td *TemplateData) GenProtoFields(model *Model) string {
+	var buf bytes.Buffer
+	for i, f := range td.protoFields(model) {
+		label := ""
+		switch {
+		case f.repeated:
+			label = "repeated "
+		case f.IsPtr && f.conversion == protoScalar:
+			label = "optional "
+		}
+		fmt.Fprintf(&buf, "  %s%s %s = %d;\n", label, f.typ, f.name(), i+1)
+	}
+	return buf.String()
+}
+
+// GenProtoFilterFields generates the fields of the filter of the list
+// requests of the given model, which have the same numbers as in the
+// message of the model.
+func (td *TemplateData) GenProtoFilterFields(model *Model) string {
+	var buf bytes.Buffer
+	for i, f := range td.protoFields(model) {
+		if !f.filterable() {
+			continue
+		}
+
+		label := "optional "
+		if f.conversion == protoTimestamp {
+			label = ""
+		}
+		fmt.Fprintf(&buf, "  %s%s %s = %d;\n", label, f.typ, f.name(), i+1)
+	}
+	return buf.String()
+}
+
+// GenProtoFromRecord generates the code that sets the fields of the message
+// m with the values of the exposed fields of record.
+func (td *TemplateData) GenProtoFromRecord(model *Model) string {
+	var buf bytes.Buffer
+	for _, f := range td.protoFields(model) {
+		field, value := "m."+f.goName(), "record."+f.path
+		switch {
+		case f.conversion == protoText:
+			fmt.Fprintf(&buf, "%s = %s.String()\n", field, value)
+		case f.conversion == protoTimestamp && f.IsPtr:
+			fmt.Fprintf(&buf, "if %s != nil {\n%s = timestamppb.New(*%s)\n}\n", value, field, value)
+		case f.conversion == protoTimestamp:
+			fmt.Fprintf(&buf, "%s = timestamppb.New(%s)\n", field, value)
+		case f.repeated && f.goType == f.protoGoType:
+			fmt.Fprintf(&buf, "%s = %s\n", field, value)
+		case f.repeated:
+			fmt.Fprintf(&buf, "for _, v := range %s {\n%s = append(%s, %s)\n}\n", value, field, field, convert(f.protoGoType, f.goType, "v"))
+		case f.IsPtr:
+			fmt.Fprintf(&buf, "if %s != nil {\nv := %s\n%s = &v\n}\n", value, convert(f.protoGoType, f.goType, "*"+value), field)
+		default:
+			fmt.Fprintf(&buf, "%s = %s\n", field, convert(f.protoGoType, f.goType, value))
+		}
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// GenRecordFromProto generates the code that sets the exposed fields of
+// record with the values of the fields of the message m. Autoincrementable
+// primary keys and generated columns are not set, and neither are the
+// identifiers that are empty in the message.
+func (td *TemplateData) GenRecordFromProto(model *Model) string {
+	var buf bytes.Buffer
+	for _, f := range td.protoFields(model) {
+		if f.IsAutoIncrement() || f.IsGenerated() {
+			continue
+		}
+
+		field, value := "record."+f.path, "m."+f.goName()
+		switch {
+		case f.conversion == protoText:
+			fmt.Fprintf(&buf, "if %s != \"\" {\nif err := %s.Scan(%s); err != nil {\n%s\n}\n}\n", value, field, value, invalidArgument(f, "err"))
+		case f.conversion == protoTimestamp && f.IsPtr:
+			fmt.Fprintf(&buf, "%s = nil\nif %s != nil {\nt := %s.AsTime()\n%s = &t\n}\n", field, value, value, field)
+		case f.conversion == protoTimestamp:
+			fmt.Fprintf(&buf, "%s = time.Time{}\nif %s != nil {\n%s = %s.AsTime()\n}\n", field, value, field, value)
+		case f.repeated:
+			fmt.Fprintf(&buf, "%s = make([]%s, len(%s))\nfor i, v := range %s {\n%s[i] = %s\n}\n", field, f.goType, value, value, field, convert(f.goType, f.protoGoType, "v"))
+		case f.IsPtr:
+			fmt.Fprintf(&buf, "%s = nil\nif %s != nil {\nv := %s\n%s = &v\n}\n", field, value, convert(f.goType, f.protoGoType, "*"+value), field)
+		default:
+			fmt.Fprintf(&buf, "%s = %s\n", field, convert(f.goType, f.protoGoType, value))
+		}
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// GenRecordIDFromProto generates the code that declares the variable id
+// with the primary key of the given model in the variable pbID, which is
+// the value of the primary key in the messages.
+func (td *TemplateData) GenRecordIDFromProto(model *Model) string {
+	f := td.protoID(model)
+	if f.conversion == protoText {
+		return fmt.Sprintf("var id %s\nif err := id.Scan(pbID); err != nil {\nreturn nil, %s\n}\n", f.goType, invalidArgumentError(f, "err"))
+	}
+	return fmt.Sprintf("id := %s\n", convert(f.goType, f.protoGoType, "pbID"))
+}
+
+// GenProtoFilters generates the code that adds to the query q the
+// conditions of the filter of the list requests of the given model, which is
+// in the variable filter.
+func (td *TemplateData) GenProtoFilters(model *Model) string {
+	var buf bytes.Buffer
+	for _, f := range td.protoFields(model) {
+		if !f.filterable() {
+			continue
+		}
+
+		field, value := fmt.Sprintf("Schema.%s.%s", model.Name, f.Name), "filter."+f.goName()
+		switch f.conversion {
+		case protoText:
+			fmt.Fprintf(&buf, "if %s != nil {\nvar v %s\nif err := v.Scan(*%s); err != nil {\nreturn nil, %s\n}\nq.Where(kallax.Eq(%s, v))\n}\n", value, f.goType, value, invalidArgumentError(f, "err"), field)
+		case protoTimestamp:
+			fmt.Fprintf(&buf, "if %s != nil {\nq.Where(kallax.Eq(%s, %s.AsTime()))\n}\n", value, field, value)
+		default:
+			fmt.Fprintf(&buf, "if %s != nil {\nq.Where(kallax.Eq(%s, %s))\n}\n", value, field, convert(f.goType, f.protoGoType, "*"+value))
+		}
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// GenProtoUpdateColumns generates the list of the schema fields of the
+// columns of the given model that are updated by its gRPC service.
+func (td *TemplateData) GenProtoUpdateColumns(model *Model) string {
+	var cols []string
+	for _, f := range td.protoFields(model) {
+		if !f.IsPrimaryKey() && !f.IsGenerated() {
+			cols = append(cols, fmt.Sprintf("Schema.%s.%s", model.Name, f.Name))
+		}
+	}
+	return strings.Join(cols, ", ")
+}
+
+// convert returns the Go expression that converts the given value from the
+// Go type from to the Go type to.
+func convert(to, from, value string) string {
+	if to == from {
+		return value
+	}
+	if strings.HasPrefix(to, "[]") {
+		to = "(" + to + ")"
+	}
+	return fmt.Sprintf("%s(%s)", to, value)
+}
+
+func invalidArgumentError(f *protoField, err string) string {
+	return fmt.Sprintf("status.Errorf(codes.InvalidArgument, \"invalid %s: %%s\", %s)", f.name(), err)
+}
+
+func invalidArgument(f *protoField, err string) string {
+	return "return " + invalidArgumentError(f, err)
+}
@@ -0,0 +1,23 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtoGoName(t *testing.T) {
+	cases := map[string]string{
+		"id":          "Id",
+		"created_at":  "CreatedAt",
+		"user_id":     "UserId",
+		"a1b":         "A1B",
+		"foo_2":       "Foo_2",
+		"_private":    "XPrivate",
+		"html_parser": "HtmlParser",
+	}
+
+	for name, expected := range cases {
+		require.Equal(t, expected, protoGoName(name), name)
+	}
+}
@@ -0,0 +1,347 @@
+package generator
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// migrationsTable is the table where the version of the database is kept
+// when the migrations are run, which is not part of the schema of the models.
+const migrationsTable = "schema_migrations"
+
+// IntrospectSchema returns the schema of the tables, composite and enum types
+// of the current schema of the given PostgreSQL database, in the same form as
+// the schema of the models. Writing it as the lock of the migrations of a
+// database that was not created with kallax makes the next migration contain
+// only the changes of the models with respect to the database.
+//
+// Only what kallax generates is read: the columns with their types and
+// constraints, the foreign keys to single columns, and the indexes of single
+// columns named as kallax names them. The triggers of audit and history
+// tables and of notified changes, generated tsvector columns and JSON Schema
+// checks are not.
+func IntrospectSchema(db *sql.DB) (*DBSchema, error) {
+	tables, err := introspectRelations(db, introspectTablesQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := new(DBSchema)
+	for _, t := range tables {
+		if t.name == migrationsTable {
+			continue
+		}
+
+		table, err := introspectTable(db, t)
+		if err != nil {
+			return nil, err
+		}
+		schema.Tables = append(schema.Tables, table)
+	}
+
+	types, err := introspectRelations(db, introspectTypesQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range types {
+		if _, ok := typeDefinitions[ColumnType(t.name)]; ok {
+			continue
+		}
+
+		attrs, err := introspectColumns(db, t)
+		if err != nil {
+			return nil, err
+		}
+		schema.Types = append(schema.Types, &TypeSchema{Name: t.name, Attributes: attrs})
+	}
+
+	if err := introspectEnums(db, schema); err != nil {
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+const (
+	introspectTablesQuery = `SELECT c.oid, c.relname FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = current_schema() AND c.relkind = 'r'
+		ORDER BY c.relname`
+
+	introspectTypesQuery = `SELECT t.typrelid, t.typname FROM pg_type t
+		JOIN pg_namespace n ON n.oid = t.typnamespace
+		JOIN pg_class c ON c.oid = t.typrelid
+		WHERE n.nspname = current_schema() AND t.typtype = 'c' AND c.relkind = 'c'
+		ORDER BY t.typname`
+
+	introspectEnumsQuery = `SELECT t.typname, e.enumlabel FROM pg_type t
+		JOIN pg_namespace n ON n.oid = t.typnamespace
+		JOIN pg_enum e ON e.enumtypid = t.oid
+		WHERE n.nspname = current_schema()
+		ORDER BY t.typname, e.enumsortorder`
+
+	introspectColumnsQuery = `SELECT a.attname, format_type(a.atttypid, a.atttypmod),
+			a.attnotnull, COALESCE(pg_get_expr(d.adbin, d.adrelid), '')
+		FROM pg_attribute a
+		LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		WHERE a.attrelid = $1 AND a.attnum > 0 AND NOT a.attisdropped
+		ORDER BY a.attnum`
+
+	introspectConstraintsQuery = `SELECT con.contype,
+			ARRAY(SELECT a.attname FROM unnest(con.conkey) WITH ORDINALITY k(attnum, ord)
+				JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+				ORDER BY k.ord),
+			COALESCE(ref.relname, ''),
+			COALESCE((SELECT a.attname FROM pg_attribute a
+				WHERE a.attrelid = con.confrelid AND a.attnum = con.confkey[1]), ''),
+			con.confdeltype, con.confupdtype
+		FROM pg_constraint con
+		LEFT JOIN pg_class ref ON ref.oid = con.confrelid
+		WHERE con.conrelid = $1 AND con.contype IN ('p', 'f')
+		ORDER BY con.conname`
+
+	introspectIndexesQuery = `SELECT i.relname, a.attname, ix.indisunique, am.amname, opc.opcname
+		FROM pg_index ix
+		JOIN pg_class i ON i.oid = ix.indexrelid
+		JOIN pg_am am ON am.oid = i.relam
+		JOIN pg_attribute a ON a.attrelid = ix.indrelid AND a.attnum = ix.indkey[0]
+		JOIN pg_opclass opc ON opc.oid = ix.indclass[0]
+		WHERE ix.indrelid = $1 AND ix.indnatts = 1 AND NOT ix.indisprimary
+			AND ix.indexprs IS NULL AND ix.indpred IS NULL
+		ORDER BY i.relname`
+)
+
+// relation is a table or composite type of the database.
+type relation struct {
+	oid  int64
+	name string
+}
+
+func introspectRelations(db *sql.DB, query string) ([]relation, error) {
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("kallax: unable to introspect the schema: %s", err)
+	}
+	defer rows.Close()
+
+	var relations []relation
+	for rows.Next() {
+		var r relation
+		if err := rows.Scan(&r.oid, &r.name); err != nil {
+			return nil, fmt.Errorf("kallax: unable to introspect the schema: %s", err)
+		}
+		relations = append(relations, r)
+	}
+	return relations, rows.Err()
+}
+
+// introspectEnums adds the enum types of the database, with their values in
+// order, to the given schema.
+func introspectEnums(db *sql.DB, schema *DBSchema) error {
+	rows, err := db.Query(introspectEnumsQuery)
+	if err != nil {
+		return fmt.Errorf("kallax: unable to introspect the schema: %s", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return err
+		}
+
+		n := len(schema.Enums)
+		if n == 0 || schema.Enums[n-1].Name != name {
+			schema.Enums = append(schema.Enums, &EnumSchema{Name: name})
+			n++
+		}
+		schema.Enums[n-1].Values = append(schema.Enums[n-1].Values, value)
+	}
+	return rows.Err()
+}
+
+func introspectTable(db *sql.DB, t relation) (*TableSchema, error) {
+	columns, err := introspectColumns(db, t)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &TableSchema{Name: t.name, Columns: columns}
+	if err := introspectConstraints(db, t, table); err != nil {
+		return nil, err
+	}
+
+	if err := introspectIndexes(db, t, table); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+func introspectColumns(db *sql.DB, r relation) ([]*ColumnSchema, error) {
+	rows, err := db.Query(introspectColumnsQuery, r.oid)
+	if err != nil {
+		return nil, fmt.Errorf("kallax: unable to introspect the columns of %s: %s", r.name, err)
+	}
+	defer rows.Close()
+
+	var columns []*ColumnSchema
+	for rows.Next() {
+		var name, typ, def string
+		var notNull bool
+		if err := rows.Scan(&name, &typ, &notNull, &def); err != nil {
+			return nil, fmt.Errorf("kallax: unable to introspect the columns of %s: %s", r.name, err)
+		}
+
+		columns = append(columns, &ColumnSchema{
+			Name:    name,
+			Type:    introspectedType(r.name, name, typ, def),
+			NotNull: notNull,
+		})
+	}
+	return columns, rows.Err()
+}
+
+func introspectConstraints(db *sql.DB, t relation, table *TableSchema) error {
+	rows, err := db.Query(introspectConstraintsQuery, t.oid)
+	if err != nil {
+		return fmt.Errorf("kallax: unable to introspect the constraints of %s: %s", t.name, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var kind, refTable, refColumn, onDelete, onUpdate string
+		var columns pq.StringArray
+		if err := rows.Scan(&kind, &columns, &refTable, &refColumn, &onDelete, &onUpdate); err != nil {
+			return fmt.Errorf("kallax: unable to introspect the constraints of %s: %s", t.name, err)
+		}
+
+		switch {
+		case kind == "p" && len(columns) == 1:
+			if c := table.Column(columns[0]); c != nil {
+				c.PrimaryKey = true
+			}
+		case kind == "p":
+			table.PrimaryKey = columns
+		case kind == "f" && len(columns) == 1:
+			if c := table.Column(columns[0]); c != nil {
+				c.Reference = &Reference{
+					Table:    refTable,
+					Column:   refColumn,
+					OnDelete: introspectedActions[onDelete],
+					OnUpdate: introspectedActions[onUpdate],
+				}
+			}
+		}
+	}
+	return rows.Err()
+}
+
+func introspectIndexes(db *sql.DB, t relation, table *TableSchema) error {
+	rows, err := db.Query(introspectIndexesQuery, t.oid)
+	if err != nil {
+		return fmt.Errorf("kallax: unable to introspect the indexes of %s: %s", t.name, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name, column, method, opclass string
+		var unique bool
+		if err := rows.Scan(&name, &column, &unique, &method, &opclass); err != nil {
+			return fmt.Errorf("kallax: unable to introspect the indexes of %s: %s", t.name, err)
+		}
+
+		c := table.Column(column)
+		if c == nil {
+			continue
+		}
+
+		if unique {
+			c.Unique = true
+		} else if kind := indexKind(method, opclass); name == indexName(t.name, column, kind) {
+			c.Index = kind
+		}
+	}
+	return rows.Err()
+}
+
+// introspectedActions are the actions of the foreign keys by the code
+// PostgreSQL gives them. No action, which is the default one, is left empty,
+// as it is in the schema of the models.
+var introspectedActions = map[string]string{
+	"r": "RESTRICT",
+	"c": "CASCADE",
+	"n": "SET NULL",
+	"d": "SET DEFAULT",
+}
+
+// indexKind returns the kind of index of a column, as it's defined in the
+// schema of the models, of an index with the given method and operator class.
+func indexKind(method, opclass string) string {
+	for kind, idx := range vectorIndexes {
+		if idx[0] == method && idx[1] == opclass {
+			return kind
+		}
+	}
+	return method
+}
+
+var (
+	decimalTypeRegexp = regexp.MustCompile(`^numeric\((\d+),(\d+)\)$`)
+	charTypeRegexp    = regexp.MustCompile(`^character( varying)?(\(\d+\))?$`)
+)
+
+// introspectedTypes are the names of the types given by PostgreSQL that are
+// named differently in the schema of the models.
+var introspectedTypes = map[string]ColumnType{
+	"timestamp with time zone":    TimestamptzColumn,
+	"timestamp without time zone": TimestampColumn,
+}
+
+// serialTypes are the serial types by the type of the column they create.
+var serialTypes = map[ColumnType]ColumnType{
+	SmallIntColumn: SmallSerialColumn,
+	IntegerColumn:  SerialColumn,
+	BigIntColumn:   BigSerialColumn,
+}
+
+// introspectedType returns the type of a column, as it's defined in the
+// schema of the models, with the given type, as PostgreSQL formats it, and
+// default value. Serial columns are created as integer columns whose default
+// value is the next value of the sequence created for them.
+func introspectedType(table, column, typ, def string) ColumnType {
+	if strings.HasSuffix(typ, "[]") {
+		return ArrayColumn(introspectedType(table, column, strings.TrimSuffix(typ, "[]"), ""))
+	}
+
+	if t, ok := introspectedTypes[typ]; ok {
+		return t
+	}
+
+	if m := decimalTypeRegexp.FindStringSubmatch(typ); m != nil {
+		if m[2] == "0" {
+			return ColumnType(fmt.Sprintf("numeric(%s)", m[1]))
+		}
+		return ColumnType(fmt.Sprintf("decimal(%s, %s)", m[1], m[2]))
+	}
+
+	if m := charTypeRegexp.FindStringSubmatch(typ); m != nil {
+		if m[1] != "" {
+			return ColumnType("varchar" + m[2])
+		}
+		return ColumnType("char" + m[2])
+	}
+
+	if serial, ok := serialTypes[ColumnType(typ)]; ok {
+		if def == fmt.Sprintf("nextval('%s_%s_seq'::regclass)", table, column) {
+			return serial
+		}
+	}
+
+	return ColumnType(typ)
+}
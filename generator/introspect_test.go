@@ -0,0 +1,38 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntrospectedType(t *testing.T) {
+	cases := []struct {
+		typ, def string
+		expected ColumnType
+	}{
+		{"integer", "nextval('users_id_seq'::regclass)", SerialColumn},
+		{"bigint", "nextval('users_id_seq'::regclass)", BigSerialColumn},
+		{"bigint", "nextval('other_seq'::regclass)", BigIntColumn},
+		{"integer", "", IntegerColumn},
+		{"timestamp with time zone", "", TimestamptzColumn},
+		{"timestamp without time zone[]", "", ArrayColumn(TimestampColumn)},
+		{"numeric(10,2)", "", DecimalColumn(10, 2)},
+		{"numeric(20,0)", "", NumericColumn(20)},
+		{"character(1)", "", ColumnType("char(1)")},
+		{"character varying(255)", "", ColumnType("varchar(255)")},
+		{"text[]", "", ArrayColumn(TextColumn)},
+		{"geometry(Point,4326)", "", GeometryColumn("Point", 4326)},
+		{"kallax_money", "", MoneyColumn},
+	}
+
+	for _, c := range cases {
+		require.Equal(t, c.expected, introspectedType("users", "id", c.typ, c.def), c.typ)
+	}
+}
+
+func TestIndexKind(t *testing.T) {
+	require.Equal(t, "gist", indexKind("gist", "gist_geometry_ops_2d"))
+	require.Equal(t, "hnsw", indexKind("hnsw", "vector_l2_ops"))
+	require.Equal(t, "ivfflat_cosine", indexKind("ivfflat", "vector_cosine_ops"))
+}
@@ -5,6 +5,7 @@ import (
 	"encoding"
 	"encoding/json"
 	"fmt"
+	"go/types"
 	"strings"
 )
 
@@ -19,7 +20,10 @@ type Migration struct {
 }
 
 // NewMigration creates a new migration from the old and the new schema.
+// The deprecated tables and columns of the new schema that are not in the old
+// one are left out of both the migration and its lock.
 func NewMigration(old, new *DBSchema) (*Migration, error) {
+	new = withoutNewDeprecated(old, new)
 	var (
 		migration = &Migration{}
 		err       error
@@ -33,21 +37,108 @@ func NewMigration(old, new *DBSchema) (*Migration, error) {
 		return nil, err
 	}
 
-	migration.Down, err = migration.Up.
-		ReverseChangeSet(old).
-		sorted(newTables, oldTables)
-	if err != nil {
-		return nil, err
-	}
+	// the up changes are already sorted, so their reverse changes just need
+	// to be run in the opposite order
+	migration.Down = migration.Up.ReverseChangeSet(old)
 
 	migration.Lock = new
 	return migration, nil
 }
 
+// Steps returns the migrations the migration is written as. Each value added
+// to an enum type is added by its own migration, without a transaction, run
+// before the one with the rest of the changes, as PostgreSQL can not add
+// values to enum types inside transactions, nor along with other statements,
+// before version 12, and the values can not be used by the transaction that
+// adds them in later versions. The last migration has the lock.
+func (m *Migration) Steps() []*Migration {
+	var (
+		steps    []*Migration
+		up, down ChangeSet
+	)
+
+	// the down changes are the reverse of the up ones in the opposite order
+	paired := len(m.Up) == len(m.Down)
+	for i, c := range m.Up {
+		var reverse ChangeSet
+		if paired {
+			reverse = ChangeSet{m.Down[len(m.Down)-1-i]}
+		}
+
+		if _, ok := c.(*AddEnumValue); ok {
+			steps = append(steps, &Migration{Up: ChangeSet{c}, Down: reverse})
+			continue
+		}
+
+		up = append(up, c)
+		down = append(reverse, down...)
+	}
+
+	if len(steps) == 0 {
+		return []*Migration{m}
+	}
+
+	if !paired {
+		down = m.Down
+	}
+
+	if len(up) > 0 || len(down) > 0 {
+		steps = append(steps, &Migration{Up: up, Down: down})
+	}
+	steps[len(steps)-1].Lock = m.Lock
+	return steps
+}
+
+// withoutNewDeprecated returns a copy of the new schema without the
+// deprecated tables and columns that are not in the old one, so migrations
+// never create them.
+func withoutNewDeprecated(old, new *DBSchema) *DBSchema {
+	return filterDeprecated(new, func(table, column string) bool {
+		t := old.Table(table)
+		return t == nil || (column != "" && t.Column(column) == nil)
+	})
+}
+
+// DropDeprecated returns a copy of the given schema without any deprecated
+// table or column. A migration built with it drops all the tables and columns
+// of the deprecated models and fields, along with the triggers of their
+// audit and history tables.
+func DropDeprecated(schema *DBSchema) *DBSchema {
+	return filterDeprecated(schema, func(string, string) bool {
+		return true
+	})
+}
+
+// filterDeprecated returns a copy of the given schema without the deprecated
+// tables and columns for which the given function returns true. The column
+// is empty when a table is checked.
+func filterDeprecated(schema *DBSchema, remove func(table, column string) bool) *DBSchema {
+	result := &DBSchema{Types: schema.Types, Enums: schema.Enums}
+	for _, table := range schema.Tables {
+		if table.Deprecated && remove(table.Name, "") {
+			continue
+		}
+
+		t := *table
+		t.Columns = nil
+		for _, c := range table.Columns {
+			if !c.Deprecated || !remove(table.Name, c.Name) {
+				t.Columns = append(t.Columns, c)
+			}
+		}
+		result.Tables = append(result.Tables, &t)
+	}
+	return result
+}
+
 // DBSchema represents a schema of all the models in the database.
 type DBSchema struct {
 	// Tables are the schema of all the tables.
 	Tables []*TableSchema
+	// Types are the schema of all the composite types used by the tables.
+	Types []*TypeSchema `json:",omitempty"`
+	// Enums are the schema of all the enum types used by the tables.
+	Enums []*EnumSchema `json:",omitempty"`
 }
 
 // SchemaFromPackages returns a schema for the given packages models.
@@ -59,7 +150,9 @@ func SchemaFromPackages(pkgs ...*Package) (*DBSchema, error) {
 func (s *DBSchema) MarshalText() ([]byte, error) {
 	schema := struct {
 		Tables []*TableSchema
-	}{s.Tables}
+		Types  []*TypeSchema `json:",omitempty"`
+		Enums  []*EnumSchema `json:",omitempty"`
+	}{s.Tables, s.Types, s.Enums}
 	return json.MarshalIndent(schema, "", "  ")
 }
 
@@ -73,6 +166,26 @@ func (s *DBSchema) Table(name string) *TableSchema {
 	return nil
 }
 
+// Type finds a composite type with the given name.
+func (s *DBSchema) Type(name string) *TypeSchema {
+	for _, t := range s.Types {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// Enum finds an enum type with the given name.
+func (s *DBSchema) Enum(name string) *EnumSchema {
+	for _, e := range s.Enums {
+		if e.Name == name {
+			return e
+		}
+	}
+	return nil
+}
+
 func (s *DBSchema) index() map[string]*TableSchema {
 	var result = make(map[string]*TableSchema)
 	for _, t := range s.Tables {
@@ -87,6 +200,104 @@ type TableSchema struct {
 	Name string
 	// Columns are the schemas of the columns in the table.
 	Columns []*ColumnSchema
+	// Audit is the table audited by this table, if it's the audit table of a
+	// model with the `audit:"true"` tag.
+	Audit *AuditSchema `json:",omitempty"`
+	// History is the table whose versions are kept by this table, if it's
+	// the history table of a model with the `versioned:"true"` tag.
+	History *HistorySchema `json:",omitempty"`
+	// Notify is the channel the changes of the table are notified to, if it's
+	// the table of a model with the `notify:"true"` tag.
+	Notify *NotifySchema `json:",omitempty"`
+	// Deprecated reports whether the table belongs to a deprecated model, so
+	// it is kept if it exists, but it is not created.
+	Deprecated bool `json:",omitempty"`
+	// PrimaryKey are the columns of the primary key of the table, if it's a
+	// composite primary key. Primary keys of a single column are declared in
+	// the column instead.
+	PrimaryKey []string `json:",omitempty"`
+	// Indexes are the indexes of the table declared in its model with the
+	// `index` struct tag of the kallax.Model field. Indexes of a single
+	// column declared in the field are in the column instead.
+	Indexes []*IndexSchema `json:",omitempty"`
+	// Checks are the check constraints of the table declared in its model
+	// with the `check` struct tag of the kallax.Model field. Checks of a
+	// single column declared in the field are in the column instead.
+	Checks []*CheckSchema `json:",omitempty"`
+}
+
+// IndexSchema represents the schema of an index of a table.
+type IndexSchema struct {
+	// Name is the index name.
+	Name string
+	// Columns are the indexed columns, in order.
+	Columns []string
+}
+
+// Equals reports whether the index is the same as the given one.
+func (s *IndexSchema) Equals(s2 *IndexSchema) bool {
+	return s.Name == s2.Name && strings.Join(s.Columns, ",") == strings.Join(s2.Columns, ",")
+}
+
+// Index finds an index of the table with the given name.
+func (s *TableSchema) Index(name string) *IndexSchema {
+	for _, idx := range s.Indexes {
+		if idx.Name == name {
+			return idx
+		}
+	}
+	return nil
+}
+
+// CheckSchema represents the schema of a check constraint of a table.
+type CheckSchema struct {
+	// Name is the constraint name.
+	Name string
+	// Expression is the boolean SQL expression the rows must satisfy.
+	Expression string
+}
+
+// Equals reports whether the check is the same as the given one.
+func (s *CheckSchema) Equals(s2 *CheckSchema) bool {
+	return s.Name == s2.Name && s.Expression == s2.Expression
+}
+
+// Check finds a check constraint of the table with the given name.
+func (s *TableSchema) Check(name string) *CheckSchema {
+	for _, c := range s.Checks {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// HistorySchema is the table whose versions are kept in a history table by
+// a trigger, which is created along with the history table.
+type HistorySchema struct {
+	// Table is the name of the versioned table.
+	Table string
+	// PrimaryKey is the name of the primary key of the versioned table.
+	PrimaryKey string
+}
+
+// AuditSchema is the table whose changes are recorded in an audit table by
+// a trigger, which is created along with the audit table.
+type AuditSchema struct {
+	// Table is the name of the audited table.
+	Table string
+	// PrimaryKey is the name of the primary key of the audited table.
+	PrimaryKey string
+}
+
+// NotifySchema is the channel the changes of a table are notified to by a
+// trigger, which is created along with the table.
+type NotifySchema struct {
+	// Channel is the name of the channel.
+	Channel string
+	// PrimaryKey is the name of the primary key of the table, which is sent
+	// in the payloads of the notifications.
+	PrimaryKey string
 }
 
 type relationship struct {
@@ -105,25 +316,141 @@ func (s *TableSchema) relationships() []relationship {
 			}
 		}
 	}
+
+	if s.Audit != nil {
+		// the trigger of the audit table is created on the audited table
+		if _, ok := rels[s.Audit.Table]; !ok {
+			result = append(result, relationship{s.Audit.Table, false})
+		}
+	}
+
+	if s.History != nil {
+		// the trigger of the history table is created on the versioned table
+		if _, ok := rels[s.History.Table]; !ok {
+			result = append(result, relationship{s.History.Table, false})
+		}
+	}
 	return result
 }
 
 func (s *TableSchema) String() string {
 	var buf bytes.Buffer
+	var defined = make(map[string]struct{})
+	for _, c := range s.Columns {
+		for _, def := range c.definitions() {
+			if _, ok := defined[def]; !ok {
+				buf.WriteString(def)
+				defined[def] = struct{}{}
+			}
+		}
+	}
+	var constraints []string
+	if len(s.PrimaryKey) > 0 {
+		constraints = append(constraints, primaryKeyConstraint(s.PrimaryKey))
+	}
+	for _, c := range s.Checks {
+		constraints = append(constraints, "\t"+checkConstraint(c))
+	}
+
 	buf.WriteString(fmt.Sprintf("CREATE TABLE %s (\n", s.Name))
 	for i, c := range s.Columns {
 		buf.WriteRune('\t')
 		buf.WriteString(c.String())
-		if i < len(s.Columns)-1 {
+		if i < len(s.Columns)-1 || len(constraints) > 0 {
 			buf.WriteString(",\n")
 		} else {
 			buf.WriteRune('\n')
 		}
 	}
-	buf.WriteString(");\n\n")
+	if len(constraints) > 0 {
+		buf.WriteString(strings.Join(constraints, ",\n"))
+		buf.WriteRune('\n')
+	}
+	buf.WriteString(");\n")
+	for _, c := range s.Columns {
+		if c.Index != "" {
+			buf.WriteString(createIndexSQL(s.Name, c.Name, c.Index))
+		}
+
+		if c.triggerFunction(s.Name) != "" {
+			buf.WriteString(createTriggerSQL(s.Name, c))
+		}
+	}
+
+	for _, idx := range s.Indexes {
+		buf.WriteString(createTableIndexSQL(s.Name, idx))
+	}
+
+	for _, c := range s.Columns {
+		if c.Reference != nil && c.Reference.Constraint {
+			buf.WriteString(addForeignKeySQL(s.Name, c))
+		}
+	}
+
+	if s.Audit != nil {
+		buf.WriteString(createAuditTriggerSQL(s.Name, s.Audit))
+	}
+
+	if s.History != nil {
+		buf.WriteString(historyFunctionSQL(s))
+		buf.WriteString(createHistoryTriggerSQL(s.History))
+	}
+
+	if s.Notify != nil {
+		buf.WriteString(createNotifyTriggerSQL(s.Name, s.Notify))
+	}
+	buf.WriteRune('\n')
 	return buf.String()
 }
 
+// primaryKeyConstraint returns the definition of the composite primary key
+// with the given columns in a CREATE TABLE statement.
+func primaryKeyConstraint(columns []string) string {
+	return fmt.Sprintf("\tPRIMARY KEY (%s)", strings.Join(columns, ", "))
+}
+
+// checkConstraint returns the definition of the given check constraint in a
+// CREATE TABLE or an ALTER TABLE statement.
+func checkConstraint(c *CheckSchema) string {
+	return fmt.Sprintf("CONSTRAINT %s CHECK (%s)", c.Name, c.Expression)
+}
+
+// trackedTable returns the table whose changes are written to this table by
+// a trigger, if it's an audit or a history table.
+func (s *TableSchema) trackedTable() string {
+	switch {
+	case s.Audit != nil:
+		return s.Audit.Table
+	case s.History != nil:
+		return s.History.Table
+	}
+	return ""
+}
+
+// triggerFunctions returns the names of the trigger functions that maintain
+// columns of the table.
+func (s *TableSchema) triggerFunctions() []string {
+	var fns []string
+	for _, c := range s.Columns {
+		if fn := c.triggerFunction(s.Name); fn != "" {
+			fns = append(fns, fn)
+		}
+	}
+
+	if s.Audit != nil {
+		fns = append(fns, auditFunction(s.Audit.Table))
+	}
+
+	if s.History != nil {
+		fns = append(fns, historyFunction(s.History.Table))
+	}
+
+	if s.Notify != nil {
+		fns = append(fns, notifyFunction(s.Name))
+	}
+	return fns
+}
+
 // Columns returns the schema of the column with the given name.
 func (s *TableSchema) Column(name string) *ColumnSchema {
 	for _, c := range s.Columns {
@@ -134,11 +461,58 @@ func (s *TableSchema) Column(name string) *ColumnSchema {
 	return nil
 }
 
+// renamedColumn returns the column of the table that was named as given
+// before, if any.
+func (s *TableSchema) renamedColumn(oldName string) *ColumnSchema {
+	for _, c := range s.Columns {
+		if c.OldName != "" && c.OldName == oldName {
+			return c
+		}
+	}
+	return nil
+}
+
 func (s *TableSchema) Equals(s2 *TableSchema) bool {
 	if s.Name != s2.Name || len(s.Columns) != len(s2.Columns) {
 		return false
 	}
 
+	if (s.Audit == nil) != (s2.Audit == nil) || (s.Audit != nil && *s.Audit != *s2.Audit) {
+		return false
+	}
+
+	if (s.History == nil) != (s2.History == nil) || (s.History != nil && *s.History != *s2.History) {
+		return false
+	}
+
+	if !s.Notify.Equals(s2.Notify) {
+		return false
+	}
+
+	if strings.Join(s.PrimaryKey, ",") != strings.Join(s2.PrimaryKey, ",") {
+		return false
+	}
+
+	if len(s.Indexes) != len(s2.Indexes) {
+		return false
+	}
+
+	for i, idx := range s.Indexes {
+		if !idx.Equals(s2.Indexes[i]) {
+			return false
+		}
+	}
+
+	if len(s.Checks) != len(s2.Checks) {
+		return false
+	}
+
+	for i, c := range s.Checks {
+		if !c.Equals(s2.Checks[i]) {
+			return false
+		}
+	}
+
 	for i, c := range s.Columns {
 		if !c.Equals(s2.Columns[i]) {
 			return false
@@ -148,6 +522,53 @@ func (s *TableSchema) Equals(s2 *TableSchema) bool {
 	return true
 }
 
+// TypeSchema represents the SQL schema of a composite type.
+type TypeSchema struct {
+	// Name is the type name.
+	Name string
+	// Attributes are the schemas of the attributes of the type. Only their
+	// name and type are used, as attributes cannot have constraints.
+	Attributes []*ColumnSchema
+}
+
+func (s *TypeSchema) String() string {
+	var buf bytes.Buffer
+	var defined = make(map[string]struct{})
+	for _, a := range s.Attributes {
+		if def, ok := typeDefinition(a.Type); ok {
+			if _, ok := defined[def]; !ok {
+				buf.WriteString(def)
+				defined[def] = struct{}{}
+			}
+		}
+	}
+	buf.WriteString(fmt.Sprintf("CREATE TYPE %s AS (\n", s.Name))
+	for i, a := range s.Attributes {
+		buf.WriteString(fmt.Sprintf("\t%s %s", a.Name, a.Type))
+		if i < len(s.Attributes)-1 {
+			buf.WriteString(",\n")
+		} else {
+			buf.WriteRune('\n')
+		}
+	}
+	buf.WriteString(");\n\n")
+	return buf.String()
+}
+
+func (s *TypeSchema) Equals(s2 *TypeSchema) bool {
+	if s.Name != s2.Name || len(s.Attributes) != len(s2.Attributes) {
+		return false
+	}
+
+	for i, a := range s.Attributes {
+		if a.Name != s2.Attributes[i].Name || a.Type != s2.Attributes[i].Type {
+			return false
+		}
+	}
+
+	return true
+}
+
 // ColumnSchema represents the schema of a column.
 type ColumnSchema struct {
 	// Name of the column.
@@ -163,6 +584,29 @@ type ColumnSchema struct {
 	NotNull bool
 	// Unique reports whether the column has a unique constraint
 	Unique bool
+	// Index is the method of the index created for the column, if any. For
+	// example, spatial columns are indexed with "gist".
+	Index string `json:",omitempty"`
+	// TSVector is the definition of the document of a tsvector column that is
+	// computed from other columns, if any.
+	TSVector *TSVectorSchema `json:",omitempty"`
+	// JSONSchema is the JSON Schema document the values of the column must
+	// match, enforced with a CHECK constraint, if any.
+	JSONSchema string `json:",omitempty"`
+	// Check is the boolean SQL expression of the check constraint of the
+	// column, if any, which is named as PostgreSQL names the check
+	// constraints defined in the columns.
+	Check string `json:",omitempty"`
+	// Default is the SQL expression of the default value of the column, if
+	// any, such as "now()" or "0".
+	Default string `json:",omitempty"`
+	// Deprecated reports whether the column belongs to a deprecated field, so
+	// it is kept if it exists, but it is not added.
+	Deprecated bool `json:",omitempty"`
+	// OldName is the name the column had before, if it was renamed, so the
+	// column with that name is renamed instead of dropped. It's not kept in
+	// the lock, as it only matters for the next migration.
+	OldName string `json:"-"`
 }
 
 func (s *ColumnSchema) Equals(s2 *ColumnSchema) bool {
@@ -171,7 +615,12 @@ func (s *ColumnSchema) Equals(s2 *ColumnSchema) bool {
 		s.PrimaryKey == s2.PrimaryKey &&
 		s.NotNull == s2.NotNull &&
 		s.Unique == s2.Unique &&
-		s.Reference.Equals(s2.Reference)
+		s.Index == s2.Index &&
+		s.Reference.Equals(s2.Reference) &&
+		s.TSVector.Equals(s2.TSVector) &&
+		s.JSONSchema == s2.JSONSchema &&
+		s.Check == s2.Check &&
+		s.Default == s2.Default
 }
 
 func (s *ColumnSchema) String() string {
@@ -180,6 +629,17 @@ func (s *ColumnSchema) String() string {
 	buf.WriteRune(' ')
 	buf.WriteString(string(s.Type))
 
+	if s.TSVector != nil && !s.TSVector.Trigger {
+		buf.WriteString(" GENERATED ALWAYS AS (")
+		buf.WriteString(s.TSVector.Expr(""))
+		buf.WriteString(") STORED")
+	}
+
+	if s.Default != "" {
+		buf.WriteString(" DEFAULT ")
+		buf.WriteString(s.Default)
+	}
+
 	if s.NotNull {
 		buf.WriteString(" NOT NULL")
 	}
@@ -192,14 +652,250 @@ func (s *ColumnSchema) String() string {
 		buf.WriteString(" PRIMARY KEY")
 	}
 
-	if s.Reference != nil {
+	if s.Reference != nil && !s.Reference.Constraint {
 		buf.WriteString(" REFERENCES ")
 		buf.WriteString(s.Reference.String())
 	}
 
+	// the check of the column goes first, so it's the one PostgreSQL gives
+	// the name without suffix
+	if s.Check != "" {
+		buf.WriteString(" CHECK (")
+		buf.WriteString(s.Check)
+		buf.WriteString(")")
+	}
+
+	if s.JSONSchema != "" {
+		buf.WriteString(" CHECK (jsonb_matches_schema(")
+		buf.WriteString(quoteLiteral(s.JSONSchema))
+		buf.WriteString(", ")
+		buf.WriteString(s.Name)
+		buf.WriteString("))")
+	}
+
 	return buf.String()
 }
 
+// definitions returns the statements that need to be run before the column
+// is created, such as the ones creating its type or the extensions it uses.
+func (s *ColumnSchema) definitions() []string {
+	var defs []string
+	if def, ok := typeDefinition(s.Type); ok {
+		defs = append(defs, def)
+	}
+
+	if s.JSONSchema != "" {
+		defs = append(defs, jsonSchemaExtension)
+	}
+	return defs
+}
+
+// jsonSchemaExtension is the statement that creates the pg_jsonschema
+// extension, which provides the function used to check JSON schemas.
+const jsonSchemaExtension = "CREATE EXTENSION IF NOT EXISTS pg_jsonschema;\n"
+
+// quoteLiteral returns the given string as a SQL string literal.
+func quoteLiteral(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
+
+// triggerFunction returns the name of the trigger function that maintains the
+// column in the given table, or an empty string if it has none.
+func (s *ColumnSchema) triggerFunction(table string) string {
+	if s.TSVector == nil || !s.TSVector.Trigger {
+		return ""
+	}
+	return fmt.Sprintf("%s__%s__tsvector", table, s.Name)
+}
+
+// TSVectorSchema is the definition of a tsvector column computed from other
+// columns of the table. The column is either a stored generated column or a
+// regular column maintained by a trigger.
+type TSVectorSchema struct {
+	// Config is the text search configuration used to build the document.
+	Config string
+	// Sources are the columns the document is built from.
+	Sources []*TSVectorSource
+	// Trigger reports whether the column is maintained by a trigger instead of
+	// being a generated column.
+	Trigger bool `json:",omitempty"`
+}
+
+// TSVectorSource is a column of the document of a tsvector column.
+type TSVectorSource struct {
+	// Column is the name of the column.
+	Column string
+	// Weight is the weight of the lexemes of the column, from A to D. If it's
+	// empty, the lexemes have no weight.
+	Weight string `json:",omitempty"`
+}
+
+func (s *TSVectorSchema) Equals(s2 *TSVectorSchema) bool {
+	if s == nil || s2 == nil {
+		return s == s2
+	}
+
+	if s.Config != s2.Config || s.Trigger != s2.Trigger || len(s.Sources) != len(s2.Sources) {
+		return false
+	}
+
+	for i, src := range s.Sources {
+		if *src != *s2.Sources[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Expr returns the SQL expression that computes the document. The given
+// prefix is added to the source columns, e.g. "NEW." in trigger functions.
+func (s *TSVectorSchema) Expr(prefix string) string {
+	var parts = make([]string, len(s.Sources))
+	for i, src := range s.Sources {
+		part := fmt.Sprintf("to_tsvector('%s', coalesce(%s%s, ''))", s.Config, prefix, src.Column)
+		if src.Weight != "" {
+			part = fmt.Sprintf("setweight(%s, '%s')", part, src.Weight)
+		}
+		parts[i] = part
+	}
+	return strings.Join(parts, " || ")
+}
+
+// createTriggerSQL returns the statements that create the trigger that
+// maintains the given column of the table.
+func createTriggerSQL(table string, c *ColumnSchema) string {
+	fn := c.triggerFunction(table)
+	return fmt.Sprintf(`CREATE OR REPLACE FUNCTION %s() RETURNS trigger AS $$
+BEGIN
+	NEW.%s := %s;
+	RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER %s BEFORE INSERT OR UPDATE ON %s FOR EACH ROW EXECUTE PROCEDURE %s();
+`, fn, c.Name, c.TSVector.Expr("NEW."), fn, table, fn)
+}
+
+// auditFunction returns the name of the trigger function that writes the
+// changes of the given table to its audit table.
+func auditFunction(table string) string {
+	return fmt.Sprintf("%s__audit", table)
+}
+
+// createAuditTriggerSQL returns the statements that create the trigger that
+// writes the changes of the audited table to the given audit table. The
+// actor is read from the kallax.actor setting, which is set by
+// kallax.Store.AuditAs.
+func createAuditTriggerSQL(table string, audit *AuditSchema) string {
+	fn := auditFunction(audit.Table)
+	return fmt.Sprintf(`CREATE OR REPLACE FUNCTION %s() RETURNS trigger AS $$
+BEGIN
+	IF TG_OP = 'DELETE' THEN
+		INSERT INTO %s (record_id, operation, old_row, new_row, actor, changed_at)
+		VALUES (OLD.%s, TG_OP, to_jsonb(OLD), NULL, NULLIF(current_setting('kallax.actor', true), ''), now());
+		RETURN OLD;
+	END IF;
+	INSERT INTO %s (record_id, operation, old_row, new_row, actor, changed_at)
+	VALUES (NEW.%s, TG_OP, CASE WHEN TG_OP = 'UPDATE' THEN to_jsonb(OLD) END, to_jsonb(NEW), NULLIF(current_setting('kallax.actor', true), ''), now());
+	RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER %s AFTER INSERT OR UPDATE OR DELETE ON %s FOR EACH ROW EXECUTE PROCEDURE %s();
+`, fn, table, audit.PrimaryKey, table, audit.PrimaryKey, fn, audit.Table, fn)
+}
+
+const (
+	// historyValidFrom is the column of the history tables with the time a
+	// version of a record became current.
+	historyValidFrom = "valid_from"
+	// historyValidTo is the column of the history tables with the time a
+	// version of a record stopped being current, which is null for the
+	// current version.
+	historyValidTo = "valid_to"
+)
+
+// historyFunction returns the name of the trigger function that writes the
+// versions of the given table to its history table.
+func historyFunction(table string) string {
+	return fmt.Sprintf("%s__history", table)
+}
+
+// historyFunctionSQL returns the statement that creates or replaces the
+// trigger function that writes the versions of the versioned table to the
+// given history table. On every change, the current version is closed and,
+// unless the record was deleted, its new version is added.
+func historyFunctionSQL(table *TableSchema) string {
+	var cols, values []string
+	for _, c := range table.Columns {
+		if c.Name != historyValidFrom && c.Name != historyValidTo {
+			cols = append(cols, c.Name)
+			values = append(values, "NEW."+c.Name)
+		}
+	}
+
+	return fmt.Sprintf(`CREATE OR REPLACE FUNCTION %s() RETURNS trigger AS $$
+BEGIN
+	IF TG_OP <> 'INSERT' THEN
+		UPDATE %s SET %s = now() WHERE %s = OLD.%s AND %s IS NULL;
+	END IF;
+	IF TG_OP = 'DELETE' THEN
+		RETURN OLD;
+	END IF;
+	INSERT INTO %s (%s, %s) VALUES (%s, now());
+	RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+`, historyFunction(table.History.Table),
+		table.Name, historyValidTo, table.History.PrimaryKey, table.History.PrimaryKey, historyValidTo,
+		table.Name, strings.Join(cols, ", "), historyValidFrom, strings.Join(values, ", "))
+}
+
+// createHistoryTriggerSQL returns the statement that creates the trigger
+// that writes the versions of the versioned table to its history table.
+func createHistoryTriggerSQL(history *HistorySchema) string {
+	fn := historyFunction(history.Table)
+	return fmt.Sprintf("CREATE TRIGGER %s AFTER INSERT OR UPDATE OR DELETE ON %s FOR EACH ROW EXECUTE PROCEDURE %s();\n", fn, history.Table, fn)
+}
+
+// Equals reports whether the notifications are sent to the same channel with
+// the same primary key as the given ones, or both are nil.
+func (s *NotifySchema) Equals(s2 *NotifySchema) bool {
+	if s == nil || s2 == nil {
+		return s == s2
+	}
+	return *s == *s2
+}
+
+// notifyFunction returns the name of the trigger function that notifies the
+// changes of the given table.
+func notifyFunction(table string) string {
+	return fmt.Sprintf("%s__notify", table)
+}
+
+// createNotifyTriggerSQL returns the statements that create the trigger that
+// notifies the changes of the table to the channel of the given schema, with
+// payloads as the ones decoded by kallax.Listener.ListenChanges.
+func createNotifyTriggerSQL(table string, notify *NotifySchema) string {
+	fn := notifyFunction(table)
+	return fmt.Sprintf(`CREATE OR REPLACE FUNCTION %s() RETURNS trigger AS $$
+BEGIN
+	IF TG_OP = 'DELETE' THEN
+		PERFORM pg_notify('%s', json_build_object('operation', TG_OP, 'id', OLD.%s::text)::text);
+		RETURN OLD;
+	END IF;
+	PERFORM pg_notify('%s', json_build_object('operation', TG_OP, 'id', NEW.%s::text)::text);
+	RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER %s AFTER INSERT OR UPDATE OR DELETE ON %s FOR EACH ROW EXECUTE PROCEDURE %s();
+`, fn, notify.Channel, notify.PrimaryKey, notify.Channel, notify.PrimaryKey, fn, table, fn)
+}
+
+// dropTriggerSQL returns the statement that drops the given trigger function
+// and the triggers using it.
+func dropTriggerSQL(fn string) string {
+	return fmt.Sprintf("DROP FUNCTION IF EXISTS %s() CASCADE;\n", fn)
+}
+
 // ColumnType represents the SQL column type.
 type ColumnType string
 
@@ -214,12 +910,46 @@ const (
 	SerialColumn      ColumnType = "serial"
 	BigSerialColumn   ColumnType = "bigserial"
 	TimestamptzColumn ColumnType = "timestamptz"
+	TimestampColumn   ColumnType = "timestamp"
 	TextColumn        ColumnType = "text"
+	CITextColumn      ColumnType = "citext"
 	JSONBColumn       ColumnType = "jsonb"
 	BooleanColumn     ColumnType = "boolean"
 	UUIDColumn        ColumnType = "uuid"
+	HStoreColumn      ColumnType = "hstore"
+	IntervalColumn    ColumnType = "interval"
+	InetColumn        ColumnType = "inet"
+	CIDRColumn        ColumnType = "cidr"
+	MACAddrColumn     ColumnType = "macaddr"
+	MoneyColumn       ColumnType = "kallax_money"
+	PGMoneyColumn     ColumnType = "money"
+	OIDColumn         ColumnType = "oid"
+	Int4RangeColumn   ColumnType = "int4range"
+	Int8RangeColumn   ColumnType = "int8range"
+	NumRangeColumn    ColumnType = "numrange"
+	TstzRangeColumn   ColumnType = "tstzrange"
+	VarBitColumn      ColumnType = "bit varying"
+	LTreeColumn       ColumnType = "ltree"
+	TSVectorColumn    ColumnType = "tsvector"
+	XMLColumn         ColumnType = "xml"
 )
 
+// typeDefinitions contains the statements needed to create the custom types
+// used by some columns, or the extensions providing them. They must be run
+// before any column uses them, and they do nothing if the type already exists.
+var typeDefinitions = map[ColumnType]string{
+	MoneyColumn:  "DO $$ BEGIN CREATE TYPE kallax_money AS (amount numeric, currency char(3)); EXCEPTION WHEN duplicate_object THEN null; END $$;\n",
+	CITextColumn: "CREATE EXTENSION IF NOT EXISTS citext;\n",
+	LTreeColumn:  "CREATE EXTENSION IF NOT EXISTS ltree;\n",
+}
+
+// typeDefinition returns the statement needed to create the given type, or
+// the type of its elements if it's an array type.
+func typeDefinition(typ ColumnType) (string, bool) {
+	def, ok := typeDefinitions[ColumnType(strings.TrimRight(string(typ), "[]"))]
+	return def, ok
+}
+
 func NumericColumn(precision int) ColumnType {
 	return ColumnType(fmt.Sprintf("numeric(%d)", precision))
 }
@@ -228,6 +958,34 @@ func DecimalColumn(precision, scale int) ColumnType {
 	return ColumnType(fmt.Sprintf("decimal(%d, %d)", precision, scale))
 }
 
+// GeometryColumn returns a PostGIS geometry column type with the given
+// geometry type and SRID. If srid is 0, no SRID constraint is added to the
+// column.
+func GeometryColumn(typ string, srid int) ColumnType {
+	if srid != 0 {
+		return ColumnType(fmt.Sprintf("geometry(%s,%d)", typ, srid))
+	}
+
+	if typ != "" {
+		return ColumnType(fmt.Sprintf("geometry(%s)", typ))
+	}
+	return ColumnType("geometry")
+}
+
+// VectorColumn returns a pgvector column type with the given number of
+// dimensions. If dims is 0, the column accepts vectors of any dimension.
+func VectorColumn(dims int) ColumnType {
+	if dims != 0 {
+		return ColumnType(fmt.Sprintf("vector(%d)", dims))
+	}
+	return ColumnType("vector")
+}
+
+// BitColumn returns a bit string column type with the given fixed length.
+func BitColumn(n int) ColumnType {
+	return ColumnType(fmt.Sprintf("bit(%d)", n))
+}
+
 func ArrayColumn(typ ColumnType) ColumnType {
 	// only allow arrays, not matrixes
 	if strings.HasSuffix(string(typ), "[]") {
@@ -242,8 +1000,18 @@ type Reference struct {
 	// Table is the referenced table.
 	Table string
 	// Column is the referenced column.
-	Column  string
-	inverse bool
+	Column string
+	// Constraint reports whether the foreign key is added to the table as a
+	// constraint of its own, named as PostgreSQL names the foreign keys of
+	// columns, instead of in the definition of the column.
+	Constraint bool `json:",omitempty"`
+	// OnDelete is the action taken on the referencing rows when the
+	// referenced row is deleted, such as "CASCADE", if any.
+	OnDelete string `json:",omitempty"`
+	// OnUpdate is the action taken on the referencing rows when the
+	// referenced column is updated, if any.
+	OnUpdate string `json:",omitempty"`
+	inverse  bool
 }
 
 func (r *Reference) Equals(r2 *Reference) bool {
@@ -254,11 +1022,82 @@ func (r *Reference) Equals(r2 *Reference) bool {
 	}
 
 	return r.Table == r2.Table &&
-		r.Column == r2.Column
+		r.Column == r2.Column &&
+		r.OnDelete == r2.OnDelete &&
+		r.OnUpdate == r2.OnUpdate
 }
 
 func (r *Reference) String() string {
-	return fmt.Sprintf("%s(%s)", r.Table, r.Column)
+	ref := fmt.Sprintf("%s(%s)", r.Table, r.Column)
+	if r.OnDelete != "" {
+		ref += " ON DELETE " + r.OnDelete
+	}
+
+	if r.OnUpdate != "" {
+		ref += " ON UPDATE " + r.OnUpdate
+	}
+	return ref
+}
+
+// hasConstraint reports whether the reference is not nil and it is a
+// constraint of its own or has any action, so its changes can be migrated
+// dropping and adding its constraint.
+func (r *Reference) hasConstraint() bool {
+	return r != nil && (r.Constraint || r.OnDelete != "" || r.OnUpdate != "")
+}
+
+// copyConstraint sets the constraint and actions of the reference to the
+// ones of the given reference.
+func (r *Reference) copyConstraint(r2 *Reference) {
+	r.Constraint = r2.Constraint
+	r.OnDelete = r2.OnDelete
+	r.OnUpdate = r2.OnUpdate
+}
+
+// foreignKeyName returns the name of the foreign key constraint of the given
+// column, which is the one PostgreSQL gives to the foreign keys defined in
+// the columns.
+func foreignKeyName(table, column string) string {
+	return fmt.Sprintf("%s_%s_fkey", table, column)
+}
+
+// columnCheck returns the check constraint of the given column, which is
+// named as PostgreSQL names the check constraints defined in the columns.
+func columnCheck(table string, c *ColumnSchema) *CheckSchema {
+	return &CheckSchema{Name: fmt.Sprintf("%s_%s_check", table, c.Name), Expression: c.Check}
+}
+
+// addForeignKeySQL returns the SQL statement that adds the foreign key of
+// the given column as a constraint of the table.
+func addForeignKeySQL(table string, c *ColumnSchema) string {
+	return fmt.Sprintf(
+		"ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s;\n",
+		table, foreignKeyName(table, c.Name), c.Name, c.Reference,
+	)
+}
+
+// foreignKeyActions are the SQL actions of the foreign keys by the name they
+// are given in the struct tag `fk`.
+var foreignKeyActions = map[string]string{
+	"cascade":     "CASCADE",
+	"restrict":    "RESTRICT",
+	"set_null":    "SET NULL",
+	"set_default": "SET DEFAULT",
+	"no_action":   "NO ACTION",
+}
+
+// foreignKeyAction returns the SQL action with the given name, or an error
+// if there is no such action.
+func foreignKeyAction(name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+
+	action, ok := foreignKeyActions[name]
+	if !ok {
+		return "", fmt.Errorf("invalid foreign key action %q, it must be cascade, restrict, set_null, set_default or no_action", name)
+	}
+	return action, nil
 }
 
 // ChangeSet is a set of changes to be made in a migration.
@@ -272,6 +1111,12 @@ type ChangeSet []Change
 //   For example, if profiles depends on users, profiles will be removed first
 //   and then users.
 // - Finally, rest of the changes.
+// The composite types are created before anything else and dropped after the
+// tables, in the order they were added to the change set for creates and in
+// reverse order for drops, so types are created after the types they use.
+// The enum types are created, and given their new values, before the
+// composite types, which can use them, and dropped after the rest of the
+// changes, once no column uses them.
 // dropIndex and createIndex are indexes of table name to table schema
 // used to look for dependencies of changes in drops and creates respectively.
 func (cs ChangeSet) sorted(dropIndex, createIndex map[string]*TableSchema) (ChangeSet, error) {
@@ -280,12 +1125,24 @@ func (cs ChangeSet) sorted(dropIndex, createIndex map[string]*TableSchema) (Chan
 		dropTables   = make(map[string]Change)
 		createGraph  = newGraph()
 		dropGraph    = newGraph()
+		createTypes  ChangeSet
+		dropTypes    ChangeSet
+		createEnums  ChangeSet
+		dropEnums    ChangeSet
 		others       ChangeSet
 		result       ChangeSet
 	)
 
 	for _, c := range cs {
 		switch c := c.(type) {
+		case *CreateType:
+			createTypes = append(createTypes, c)
+		case *DropType:
+			dropTypes = append(ChangeSet{c}, dropTypes...)
+		case *CreateEnum, *AddEnumValue:
+			createEnums = append(createEnums, c)
+		case *DropEnum:
+			dropEnums = append(dropEnums, c)
 		case *CreateTable:
 			createTables[c.Name] = c
 			if rels := createIndex[c.Name].relationships(); len(rels) > 0 {
@@ -322,6 +1179,9 @@ func (cs ChangeSet) sorted(dropIndex, createIndex map[string]*TableSchema) (Chan
 		return nil, err
 	}
 
+	result = append(result, createEnums...)
+	result = append(result, createTypes...)
+
 	for _, c := range creates {
 		if change, ok := createTables[c]; ok {
 			result = append(result, change)
@@ -340,25 +1200,51 @@ func (cs ChangeSet) sorted(dropIndex, createIndex map[string]*TableSchema) (Chan
 		}
 	}
 
+	result = append(result, dropTypes...)
 	result = append(result, others...)
+	result = append(result, dropEnums...)
 	return result, nil
 }
 
+// MarshalText returns the SQL of the changes, which are run in a
+// transaction unless all of them add values to enum types, see
+// Migration.Steps.
 func (cs ChangeSet) MarshalText() ([]byte, error) {
 	var buf bytes.Buffer
-	buf.WriteString("BEGIN;\n\n")
-	for _, c := range cs {
+	transaction := !cs.addsEnumValues()
+	if transaction {
+		buf.WriteString("BEGIN;\n\n")
+	}
+
+	for i, c := range cs {
 		bytes, err := c.MarshalText()
 		if err != nil {
 			return nil, err
 		}
+
 		buf.Write(bytes)
-		buf.WriteRune('\n')
+		if transaction || i < len(cs)-1 {
+			buf.WriteRune('\n')
+		}
+	}
+
+	if transaction {
+		buf.WriteString("COMMIT;\n")
 	}
-	buf.WriteString("COMMIT;\n")
 	return buf.Bytes(), nil
 }
 
+// addsEnumValues reports whether all the changes of the change set add
+// values to enum types.
+func (cs ChangeSet) addsEnumValues() bool {
+	for _, c := range cs {
+		if _, ok := c.(*AddEnumValue); !ok {
+			return false
+		}
+	}
+	return len(cs) > 0
+}
+
 func (cs ChangeSet) String() string {
 	var buf bytes.Buffer
 	for _, c := range cs {
@@ -367,11 +1253,27 @@ func (cs ChangeSet) String() string {
 	return buf.String()
 }
 
-// Reverse returns the change that will revert the current change set.
+// Destructive returns the changes of the change set that drop tables or
+// columns, and their data with them.
+func (cs ChangeSet) Destructive() ChangeSet {
+	var result ChangeSet
+	for _, c := range cs {
+		switch c.(type) {
+		case *DropTable, *DropColumn:
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// Reverse returns the change that will revert the current change set, which
+// reverts its changes in the opposite order, so every change is reverted
+// before the ones made before it, such as a constraint dropped and added
+// again to change it.
 func (cs ChangeSet) Reverse(old *DBSchema) Change {
 	var result = make(ChangeSet, len(cs))
 	for i, c := range cs {
-		result[i] = c.Reverse(old)
+		result[len(cs)-1-i] = c.Reverse(old)
 	}
 	return result
 }
@@ -395,7 +1297,7 @@ type CreateTable struct {
 }
 
 func (c *CreateTable) Reverse(old *DBSchema) Change {
-	return &DropTable{Name: c.Name}
+	return &DropTable{Name: c.Name, Functions: c.TableSchema.triggerFunctions()}
 }
 
 func (c *CreateTable) MarshalText() ([]byte, error) {
@@ -414,18 +1316,65 @@ func (c *CreateTable) String() string {
 type DropTable struct {
 	// Name is the name of the table to drop.
 	Name string
+	// Functions are the trigger functions of the table, which are dropped
+	// with it.
+	Functions []string
 }
 
 func (c *DropTable) Reverse(old *DBSchema) Change {
 	return &CreateTable{old.Table(c.Name)}
 }
 
-func (c *DropTable) MarshalText() ([]byte, error) {
-	return []byte(fmt.Sprintf("DROP TABLE %s;\n", c.Name)), nil
+func (c *DropTable) MarshalText() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("DROP TABLE %s;\n", c.Name))
+	for _, fn := range c.Functions {
+		buf.WriteString(dropTriggerSQL(fn))
+	}
+	return buf.Bytes(), nil
+}
+
+func (c *DropTable) String() string {
+	return fmt.Sprintf("Table %q has been deleted, and it will be dropped.", c.Name)
+}
+
+// CreateType is a change that will add a new composite type.
+type CreateType struct {
+	*TypeSchema
+}
+
+func (c *CreateType) Reverse(old *DBSchema) Change {
+	return &DropType{Name: c.Name}
+}
+
+func (c *CreateType) MarshalText() ([]byte, error) {
+	return []byte(c.TypeSchema.String()), nil
+}
+
+func (c *CreateType) String() string {
+	var attrs = make([]string, len(c.Attributes))
+	for i, a := range c.Attributes {
+		attrs[i] = a.Name
+	}
+	return fmt.Sprintf("A new composite type %q has been added with the following attributes: %s.", c.Name, strings.Join(attrs, ", "))
+}
+
+// DropType is a change that will drop a composite type.
+type DropType struct {
+	// Name is the name of the type to drop.
+	Name string
+}
+
+func (c *DropType) Reverse(old *DBSchema) Change {
+	return &CreateType{old.Type(c.Name)}
+}
+
+func (c *DropType) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("DROP TYPE %s;\n", c.Name)), nil
 }
 
-func (c *DropTable) String() string {
-	return fmt.Sprintf("Table %q has been deleted, and it will be dropped.", c.Name)
+func (c *DropType) String() string {
+	return fmt.Sprintf("Composite type %q has been deleted, and it will be dropped.", c.Name)
 }
 
 // AddColumn is a change that will add a column.
@@ -438,8 +1387,9 @@ type AddColumn struct {
 
 func (c *AddColumn) Reverse(old *DBSchema) Change {
 	return &DropColumn{
-		Table: c.Table,
-		Name:  c.Column.Name,
+		Table:    c.Table,
+		Name:     c.Column.Name,
+		Function: c.Column.triggerFunction(c.Table),
 	}
 }
 
@@ -448,7 +1398,18 @@ func (c *AddColumn) String() string {
 }
 
 func (c *AddColumn) MarshalText() ([]byte, error) {
-	return []byte(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;\n", c.Table, c.Column)), nil
+	def := strings.Join(c.Column.definitions(), "")
+	sql := fmt.Sprintf("%sALTER TABLE %s ADD COLUMN %s;\n", def, c.Table, c.Column)
+	if c.Column.Reference != nil && c.Column.Reference.Constraint {
+		sql += addForeignKeySQL(c.Table, c.Column)
+	}
+	if c.Column.triggerFunction(c.Table) != "" {
+		// the trigger sets the value of the column on any update, so this
+		// fills it for the existing rows
+		sql += createTriggerSQL(c.Table, c.Column)
+		sql += fmt.Sprintf("UPDATE %s SET %s = NULL;\n", c.Table, c.Column.Name)
+	}
+	return []byte(sql), nil
 }
 
 // DropColumn is a change that will drop a column.
@@ -457,6 +1418,9 @@ type DropColumn struct {
 	Name string
 	// Table name.
 	Table string
+	// Function is the trigger function that maintains the column, if any,
+	// which is dropped with it.
+	Function string
 }
 
 func (c *DropColumn) Reverse(old *DBSchema) Change {
@@ -471,7 +1435,235 @@ func (c *DropColumn) String() string {
 }
 
 func (c *DropColumn) MarshalText() ([]byte, error) {
-	return []byte(fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;\n", c.Table, c.Name)), nil
+	sql := fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;\n", c.Table, c.Name)
+	if c.Function != "" {
+		sql += dropTriggerSQL(c.Function)
+	}
+	return []byte(sql), nil
+}
+
+// RenameColumn is a change that will rename a column, along with its index
+// and foreign key constraint, if any, so its data is kept.
+type RenameColumn struct {
+	// Table name.
+	Table string
+	// Column is the schema of the column before it's renamed.
+	Column *ColumnSchema
+	// Name is the new name of the column.
+	Name string
+}
+
+func (c *RenameColumn) Reverse(old *DBSchema) Change {
+	col := *c.Column
+	col.Name = c.Name
+	return &RenameColumn{
+		Table:  c.Table,
+		Column: &col,
+		Name:   c.Column.Name,
+	}
+}
+
+func (c *RenameColumn) String() string {
+	return fmt.Sprintf("The column %q of table %q has been renamed to %q.", c.Column.Name, c.Table, c.Name)
+}
+
+func (c *RenameColumn) MarshalText() ([]byte, error) {
+	sql := fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s;\n", c.Table, c.Column.Name, c.Name)
+	if c.Column.Index != "" {
+		sql += fmt.Sprintf(
+			"ALTER INDEX %s RENAME TO %s;\n",
+			indexName(c.Table, c.Column.Name, c.Column.Index),
+			indexName(c.Table, c.Name, c.Column.Index),
+		)
+	}
+
+	if c.Column.Reference != nil {
+		sql += fmt.Sprintf(
+			"ALTER TABLE %s RENAME CONSTRAINT %s TO %s;\n",
+			c.Table,
+			foreignKeyName(c.Table, c.Column.Name),
+			foreignKeyName(c.Table, c.Name),
+		)
+	}
+
+	if c.Column.Check != "" {
+		renamed := *c.Column
+		renamed.Name = c.Name
+		sql += fmt.Sprintf(
+			"ALTER TABLE %s RENAME CONSTRAINT %s TO %s;\n",
+			c.Table,
+			columnCheck(c.Table, c.Column).Name,
+			columnCheck(c.Table, &renamed).Name,
+		)
+	}
+	return []byte(sql), nil
+}
+
+// ReplaceHistoryFunction is a change that will replace the trigger function
+// that writes the versions of a versioned table to its history table, after
+// the columns of the history table change.
+type ReplaceHistoryFunction struct {
+	// TableSchema is the new schema of the history table.
+	*TableSchema
+}
+
+func (c *ReplaceHistoryFunction) Reverse(old *DBSchema) Change {
+	return &ReplaceHistoryFunction{old.Table(c.Name)}
+}
+
+func (c *ReplaceHistoryFunction) String() string {
+	return fmt.Sprintf("The columns of history table %q have changed, and the function writing to it will be replaced.", c.Name)
+}
+
+func (c *ReplaceHistoryFunction) MarshalText() ([]byte, error) {
+	return []byte(historyFunctionSQL(c.TableSchema)), nil
+}
+
+// CreateNotifyTrigger is a change that will create the trigger that notifies
+// the changes of a table, after its model is tagged with `notify:"true"`.
+type CreateNotifyTrigger struct {
+	// Table name.
+	Table string
+	// Notify is the schema of the notifications.
+	Notify *NotifySchema
+}
+
+func (c *CreateNotifyTrigger) Reverse(old *DBSchema) Change {
+	return &DropNotifyTrigger{Table: c.Table}
+}
+
+func (c *CreateNotifyTrigger) String() string {
+	return fmt.Sprintf("The changes of table %q will be notified to channel %q.", c.Table, c.Notify.Channel)
+}
+
+func (c *CreateNotifyTrigger) MarshalText() ([]byte, error) {
+	return []byte(createNotifyTriggerSQL(c.Table, c.Notify)), nil
+}
+
+// DropNotifyTrigger is a change that will drop the trigger that notifies the
+// changes of a table.
+type DropNotifyTrigger struct {
+	// Table name.
+	Table string
+}
+
+func (c *DropNotifyTrigger) Reverse(old *DBSchema) Change {
+	return &CreateNotifyTrigger{Table: c.Table, Notify: old.Table(c.Table).Notify}
+}
+
+func (c *DropNotifyTrigger) String() string {
+	return fmt.Sprintf("The changes of table %q will not be notified anymore.", c.Table)
+}
+
+func (c *DropNotifyTrigger) MarshalText() ([]byte, error) {
+	return []byte(dropTriggerSQL(notifyFunction(c.Table))), nil
+}
+
+// AlterColumnType is a change that will change the length or precision of
+// the type of a column, such as from varchar(32) to varchar(64).
+type AlterColumnType struct {
+	// Table name.
+	Table string
+	// Column name.
+	Column string
+	// Old type of the column.
+	Old ColumnType
+	// New type of the column.
+	New ColumnType
+}
+
+func (c *AlterColumnType) Reverse(old *DBSchema) Change {
+	return &AlterColumnType{
+		Table:  c.Table,
+		Column: c.Column,
+		Old:    c.New,
+		New:    c.Old,
+	}
+}
+
+func (c *AlterColumnType) String() string {
+	return fmt.Sprintf("The type of column %q of table %q has been changed from %q to %q.", c.Column, c.Table, c.Old, c.New)
+}
+
+func (c *AlterColumnType) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s;\n", c.Table, c.Column, c.New)), nil
+}
+
+// AlterColumnDefault is a change that will set or drop the default value of
+// a column.
+type AlterColumnDefault struct {
+	// Table name.
+	Table string
+	// Column name.
+	Column string
+	// Old default value of the column, if any.
+	Old string
+	// New default value of the column, if any.
+	New string
+}
+
+func (c *AlterColumnDefault) Reverse(old *DBSchema) Change {
+	return &AlterColumnDefault{
+		Table:  c.Table,
+		Column: c.Column,
+		Old:    c.New,
+		New:    c.Old,
+	}
+}
+
+func (c *AlterColumnDefault) String() string {
+	switch {
+	case c.Old == "":
+		return fmt.Sprintf("The default value %q has been added to column %q of table %q.", c.New, c.Column, c.Table)
+	case c.New == "":
+		return fmt.Sprintf("The default value %q of column %q of table %q has been removed.", c.Old, c.Column, c.Table)
+	}
+	return fmt.Sprintf("The default value of column %q of table %q has been changed from %q to %q.", c.Column, c.Table, c.Old, c.New)
+}
+
+func (c *AlterColumnDefault) MarshalText() ([]byte, error) {
+	if c.New == "" {
+		return []byte(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT;\n", c.Table, c.Column)), nil
+	}
+	return []byte(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s;\n", c.Table, c.Column, c.New)), nil
+}
+
+// modifiableTypes are the types whose length or precision can be changed
+// automatically by the migrations.
+var modifiableTypes = map[string]struct{}{
+	"varchar":           {},
+	"character varying": {},
+	"char":              {},
+	"character":         {},
+	"numeric":           {},
+	"decimal":           {},
+	"bit":               {},
+	"bit varying":       {},
+	"varbit":            {},
+}
+
+// isTypeModifierChange reports whether the only difference between the two
+// given types is their length or precision, e.g. numeric(10,2) and
+// numeric(12,4), so the column can be altered instead of requiring a manual
+// change.
+func isTypeModifierChange(old, new ColumnType) bool {
+	baseType := func(typ ColumnType) (string, bool) {
+		s := string(typ)
+		array := strings.HasSuffix(s, "[]")
+		s = strings.TrimSuffix(s, "[]")
+		if idx := strings.Index(s, "("); idx >= 0 {
+			if !strings.HasSuffix(s, ")") {
+				return "", array
+			}
+			s = s[:idx]
+		}
+		return strings.ToLower(strings.TrimSpace(s)), array
+	}
+
+	oldBase, oldArray := baseType(old)
+	newBase, newArray := baseType(new)
+	_, ok := modifiableTypes[oldBase]
+	return ok && oldBase == newBase && oldArray == newArray
 }
 
 // CreateIndex is a change that will create an index.
@@ -497,18 +1689,13 @@ func (c *CreateIndex) String() string {
 }
 
 func (c *CreateIndex) MarshalText() ([]byte, error) {
-	var unique string
-	if c.Kind == "unique" {
-		unique = "UNIQUE"
-	}
 	return []byte(fmt.Sprintf(`+++
 THIS REQUIRES MANUAL MIGRATION:
 Adding an index on a table that may not be empty.
 If you're sure about this, here's the SQL for this operation.
 +++
 
-CREATE %s INDEX %s ON %s;
-`, unique, indexName(c.Table, c.Column, c.Kind), c.Table)), nil
+%s`, createIndexSQL(c.Table, c.Column, c.Kind))), nil
 }
 
 // DropIndex is a change that will drop an index.
@@ -537,6 +1724,154 @@ func (c *DropIndex) MarshalText() ([]byte, error) {
 	return []byte(fmt.Sprintf("DROP INDEX %s;\n", indexName(c.Table, c.Column, c.Kind))), nil
 }
 
+// CreateTableIndex is a change that will create an index of a table
+// declared in its model.
+type CreateTableIndex struct {
+	// Table name.
+	Table string
+	// Index is the schema of the index.
+	Index *IndexSchema
+}
+
+func (c *CreateTableIndex) Reverse(old *DBSchema) Change {
+	return &DropTableIndex{
+		Table: c.Table,
+		Index: c.Index,
+	}
+}
+
+func (c *CreateTableIndex) String() string {
+	return fmt.Sprintf("A manual change is required because a new index %q has been added at columns %q of table %q.", c.Index.Name, strings.Join(c.Index.Columns, ", "), c.Table)
+}
+
+func (c *CreateTableIndex) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf(`+++
+THIS REQUIRES MANUAL MIGRATION:
+Adding an index on a table that may not be empty.
+If you're sure about this, here's the SQL for this operation.
++++
+
+%s`, createTableIndexSQL(c.Table, c.Index))), nil
+}
+
+// AddForeignKey is a change that will add the foreign key of a column as a
+// constraint of its table.
+type AddForeignKey struct {
+	// Table name.
+	Table string
+	// Column is the schema of the column with the foreign key.
+	Column *ColumnSchema
+}
+
+func (c *AddForeignKey) Reverse(old *DBSchema) Change {
+	return &DropForeignKey{
+		Table:  c.Table,
+		Column: c.Column,
+	}
+}
+
+func (c *AddForeignKey) String() string {
+	return fmt.Sprintf("A foreign key of column %q of table %q referencing %s has been added.", c.Column.Name, c.Table, c.Column.Reference)
+}
+
+func (c *AddForeignKey) MarshalText() ([]byte, error) {
+	return []byte(addForeignKeySQL(c.Table, c.Column)), nil
+}
+
+// DropForeignKey is a change that will drop the foreign key constraint of a
+// column.
+type DropForeignKey struct {
+	// Table name.
+	Table string
+	// Column is the schema of the column with the foreign key.
+	Column *ColumnSchema
+}
+
+func (c *DropForeignKey) Reverse(old *DBSchema) Change {
+	return &AddForeignKey{
+		Table:  c.Table,
+		Column: c.Column,
+	}
+}
+
+func (c *DropForeignKey) String() string {
+	return fmt.Sprintf("The foreign key of column %q of table %q referencing %s has been removed and it will be dropped.", c.Column.Name, c.Table, c.Column.Reference)
+}
+
+func (c *DropForeignKey) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;\n", c.Table, foreignKeyName(c.Table, c.Column.Name))), nil
+}
+
+// DropTableIndex is a change that will drop an index of a table declared in
+// its model.
+type DropTableIndex struct {
+	// Table name.
+	Table string
+	// Index is the schema of the index.
+	Index *IndexSchema
+}
+
+func (c *DropTableIndex) Reverse(old *DBSchema) Change {
+	return &CreateTableIndex{
+		Table: c.Table,
+		Index: c.Index,
+	}
+}
+
+func (c *DropTableIndex) String() string {
+	return fmt.Sprintf("The index %q of table %q has been removed and it will be dropped.", c.Index.Name, c.Table)
+}
+
+func (c *DropTableIndex) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("DROP INDEX %s;\n", c.Index.Name)), nil
+}
+
+// AddCheck is a change that will add a check constraint to a table.
+type AddCheck struct {
+	// Table name.
+	Table string
+	// Check is the schema of the check constraint.
+	Check *CheckSchema
+}
+
+func (c *AddCheck) Reverse(old *DBSchema) Change {
+	return &DropCheck{
+		Table: c.Table,
+		Check: c.Check,
+	}
+}
+
+func (c *AddCheck) String() string {
+	return fmt.Sprintf("A check constraint %q of table %q with expression %q has been added.", c.Check.Name, c.Table, c.Check.Expression)
+}
+
+func (c *AddCheck) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("ALTER TABLE %s ADD %s;\n", c.Table, checkConstraint(c.Check))), nil
+}
+
+// DropCheck is a change that will drop a check constraint of a table.
+type DropCheck struct {
+	// Table name.
+	Table string
+	// Check is the schema of the check constraint.
+	Check *CheckSchema
+}
+
+func (c *DropCheck) Reverse(old *DBSchema) Change {
+	return &AddCheck{
+		Table: c.Table,
+		Check: c.Check,
+	}
+}
+
+func (c *DropCheck) String() string {
+	return fmt.Sprintf("The check constraint %q of table %q has been removed and it will be dropped.", c.Check.Name, c.Table)
+}
+
+func (c *DropCheck) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;\n", c.Table, c.Check.Name)), nil
+}
+
 // ManualChange is a change that cannot be made automatically and requires
 // the user to write a proper migration.
 type ManualChange struct {
@@ -657,7 +1992,7 @@ func SchemaDiff(old, new *DBSchema) ChangeSet {
 	var cs ChangeSet
 	for _, oldTable := range old.Tables {
 		if t := new.Table(oldTable.Name); t == nil {
-			cs = append(cs, &DropTable{Name: oldTable.Name})
+			cs = append(cs, &DropTable{Name: oldTable.Name, Functions: oldTable.triggerFunctions()})
 		} else {
 			cs = append(cs, TableSchemaDiff(oldTable, t)...)
 		}
@@ -669,6 +2004,36 @@ func SchemaDiff(old, new *DBSchema) ChangeSet {
 		}
 	}
 
+	for _, oldType := range old.Types {
+		if t := new.Type(oldType.Name); t == nil {
+			cs = append(cs, &DropType{Name: oldType.Name})
+		} else if !oldType.Equals(t) {
+			cs = append(cs, &ManualChange{
+				fmt.Sprintf("don't know how to generate migration for a change in composite type %s", t.Name),
+			})
+		}
+	}
+
+	for _, newType := range new.Types {
+		if t := old.Type(newType.Name); t == nil {
+			cs = append(cs, &CreateType{newType})
+		}
+	}
+
+	for _, oldEnum := range old.Enums {
+		if e := new.Enum(oldEnum.Name); e == nil {
+			cs = append(cs, &DropEnum{Name: oldEnum.Name})
+		} else {
+			cs = append(cs, enumDiff(oldEnum, e)...)
+		}
+	}
+
+	for _, newEnum := range new.Enums {
+		if e := old.Enum(newEnum.Name); e == nil {
+			cs = append(cs, &CreateEnum{newEnum})
+		}
+	}
+
 	return cs
 }
 
@@ -676,25 +2041,83 @@ func SchemaDiff(old, new *DBSchema) ChangeSet {
 // schemas.
 func TableSchemaDiff(old, new *TableSchema) ChangeSet {
 	var cs ChangeSet
+	if strings.Join(old.PrimaryKey, ",") != strings.Join(new.PrimaryKey, ",") {
+		cs = append(cs, &ManualChange{
+			fmt.Sprintf("don't know how to generate migration for a change of primary key in %s", new.Name),
+		})
+	}
+
+	// the indexes and checks are dropped before their columns and created
+	// after them
+	var createIndexes ChangeSet
+	for _, oldCheck := range old.Checks {
+		if c := new.Check(oldCheck.Name); c == nil || !c.Equals(oldCheck) {
+			cs = append(cs, &DropCheck{Table: old.Name, Check: oldCheck})
+		}
+	}
+
+	for _, newCheck := range new.Checks {
+		if c := old.Check(newCheck.Name); c == nil || !c.Equals(newCheck) {
+			createIndexes = append(createIndexes, &AddCheck{Table: new.Name, Check: newCheck})
+		}
+	}
+
+	for _, oldIdx := range old.Indexes {
+		if idx := new.Index(oldIdx.Name); idx == nil || !idx.Equals(oldIdx) {
+			cs = append(cs, &DropTableIndex{Table: old.Name, Index: oldIdx})
+		}
+	}
+
+	for _, newIdx := range new.Indexes {
+		if idx := old.Index(newIdx.Name); idx == nil || !idx.Equals(newIdx) {
+			createIndexes = append(createIndexes, &CreateTableIndex{Table: new.Name, Index: newIdx})
+		}
+	}
+
+	var renamed = make(map[string]bool)
 	for _, oldCol := range old.Columns {
-		if c := new.Column(oldCol.Name); c == nil {
+		if c := new.Column(oldCol.Name); c != nil {
+			cs = append(cs, ColumnSchemaDiff(old.Name, oldCol, c)...)
+		} else if c := new.renamedColumn(oldCol.Name); c != nil && old.Column(c.Name) == nil {
+			cs = append(cs, &RenameColumn{Table: old.Name, Column: oldCol, Name: c.Name})
+			// the rest of the changes are made to the renamed column
+			renamedCol := *oldCol
+			renamedCol.Name = c.Name
+			cs = append(cs, ColumnSchemaDiff(old.Name, &renamedCol, c)...)
+			renamed[c.Name] = true
+		} else {
 			cs = append(cs, &DropColumn{
-				Table: old.Name,
-				Name:  oldCol.Name,
+				Table:    old.Name,
+				Name:     oldCol.Name,
+				Function: oldCol.triggerFunction(old.Name),
 			})
-		} else {
-			cs = append(cs, ColumnSchemaDiff(old.Name, oldCol, c)...)
 		}
 	}
 
 	for _, newCol := range new.Columns {
-		if c := old.Column(newCol.Name); c == nil {
+		if c := old.Column(newCol.Name); c == nil && !renamed[newCol.Name] {
 			cs = append(cs, &AddColumn{
 				Table:  new.Name,
 				Column: newCol,
 			})
 		}
 	}
+	cs = append(cs, createIndexes...)
+
+	if new.History != nil && len(cs) > 0 {
+		// the trigger function copies all the columns of the history table
+		cs = append(cs, &ReplaceHistoryFunction{new})
+	}
+
+	if !old.Notify.Equals(new.Notify) {
+		if old.Notify != nil {
+			cs = append(ChangeSet{&DropNotifyTrigger{Table: old.Name}}, cs...)
+		}
+
+		if new.Notify != nil {
+			cs = append(cs, &CreateNotifyTrigger{Table: new.Name, Notify: new.Notify})
+		}
+	}
 	return cs
 }
 
@@ -703,41 +2126,109 @@ func TableSchemaDiff(old, new *TableSchema) ChangeSet {
 func ColumnSchemaDiff(table string, old, new *ColumnSchema) ChangeSet {
 	var cs ChangeSet
 	if old.Type != new.Type {
+		if isTypeModifierChange(old.Type, new.Type) {
+			cs = append(cs, &AlterColumnType{
+				Table:  table,
+				Column: new.Name,
+				Old:    old.Type,
+				New:    new.Type,
+			})
+		} else {
+			cs = append(cs, &ManualChange{
+				fmt.Sprintf("don't know how to generate migration for a change of type in %s(%s)", table, new.Name),
+			})
+		}
+	}
+
+	if old.Default != new.Default {
+		cs = append(cs, &AlterColumnDefault{
+			Table:  table,
+			Column: new.Name,
+			Old:    old.Default,
+			New:    new.Default,
+		})
+	}
+
+	if old.PrimaryKey != new.PrimaryKey {
+		cs = append(cs, &ManualChange{
+			fmt.Sprintf("don't know how to generate migration for a change of primary key in %s(%s)", table, new.Name),
+		})
+	}
+
+	if old.NotNull != new.NotNull {
+		cs = append(cs, &ManualChange{
+			fmt.Sprintf("don't know how to generate migration for a change of null/not null in %s(%s)", table, new.Name),
+		})
+	}
+
+	if old.Unique && !new.Unique {
+		cs = append(cs, &DropIndex{
+			Table:  table,
+			Column: new.Name,
+			Kind:   "unique",
+		})
+	} else if new.Unique && !old.Unique {
+		cs = append(cs, &CreateIndex{
+			Table:  table,
+			Column: new.Name,
+			Kind:   "unique",
+		})
+	}
+
+	if old.Index != new.Index {
+		if old.Index != "" {
+			cs = append(cs, &DropIndex{
+				Table:  table,
+				Column: new.Name,
+				Kind:   old.Index,
+			})
+		}
+
+		if new.Index != "" {
+			cs = append(cs, &CreateIndex{
+				Table:  table,
+				Column: new.Name,
+				Kind:   new.Index,
+			})
+		}
+	}
+
+	if !old.Reference.Equals(new.Reference) && (old.Reference.hasConstraint() || new.Reference.hasConstraint()) {
+		// the foreign keys defined in the columns are named as the
+		// constraints, so they can be dropped the same way
+		if old.Reference != nil {
+			cs = append(cs, &DropForeignKey{Table: table, Column: old})
+		}
+
+		if new.Reference != nil {
+			cs = append(cs, &AddForeignKey{Table: table, Column: new})
+		}
+	} else if referenceChanged(old, new) {
 		cs = append(cs, &ManualChange{
-			fmt.Sprintf("don't know how to generate migration for a change of type in %s(%s)", table, new.Name),
+			fmt.Sprintf("don't know how to generate migration for a change of foreign key in %s(%s)", table, new.Name),
 		})
 	}
 
-	if old.PrimaryKey != new.PrimaryKey {
+	if !old.TSVector.Equals(new.TSVector) {
 		cs = append(cs, &ManualChange{
-			fmt.Sprintf("don't know how to generate migration for a change of primary key in %s(%s)", table, new.Name),
+			fmt.Sprintf("don't know how to generate migration for a change of tsvector definition in %s(%s)", table, new.Name),
 		})
 	}
 
-	if old.NotNull != new.NotNull {
+	if old.JSONSchema != new.JSONSchema {
 		cs = append(cs, &ManualChange{
-			fmt.Sprintf("don't know how to generate migration for a change of null/not null in %s(%s)", table, new.Name),
+			fmt.Sprintf("don't know how to generate migration for a change of JSON schema check in %s(%s)", table, new.Name),
 		})
 	}
 
-	if old.Unique && !new.Unique {
-		cs = append(cs, &DropIndex{
-			Table:  table,
-			Column: new.Name,
-			Kind:   "unique",
-		})
-	} else if new.Unique && !old.Unique {
-		cs = append(cs, &CreateIndex{
-			Table:  table,
-			Column: new.Name,
-			Kind:   "unique",
-		})
-	}
+	if old.Check != new.Check {
+		if old.Check != "" {
+			cs = append(cs, &DropCheck{Table: table, Check: columnCheck(table, old)})
+		}
 
-	if referenceChanged(old, new) {
-		cs = append(cs, &ManualChange{
-			fmt.Sprintf("don't know how to generate migration for a change of foreign key in %s(%s)", table, new.Name),
-		})
+		if new.Check != "" {
+			cs = append(cs, &AddCheck{Table: table, Check: columnCheck(table, new)})
+		}
 	}
 
 	return cs
@@ -765,6 +2256,10 @@ type packageTransformer struct {
 	// fks keeps all fks indexed by type name
 	// so they can be added later.
 	fks map[string][]*ColumnSchema
+	// types is a map from a composite type name to its schema
+	types map[string]*TypeSchema
+	// enums is a map from an enum type name to its schema
+	enums map[string]*EnumSchema
 }
 
 func newPackageTransformer() *packageTransformer {
@@ -774,6 +2269,8 @@ func newPackageTransformer() *packageTransformer {
 		tableIndex: make(map[string]string),
 		pkIndex:    make(map[string]*Field),
 		fks:        make(map[string][]*ColumnSchema),
+		types:      make(map[string]*TypeSchema),
+		enums:      make(map[string]*EnumSchema),
 	}
 }
 
@@ -796,6 +2293,19 @@ func (t *packageTransformer) transform(pkgs ...*Package) (*DBSchema, error) {
 		return nil, err
 	}
 
+	for _, pkg := range pkgs {
+		for _, m := range pkg.Models {
+			if m.Versioned {
+				// history tables are built after the foreign keys are added,
+				// as they have all the columns of the versioned tables
+				history := historyTable(t.tables[m.Table], m.ID.ColumnName())
+				history.Deprecated = m.Deprecated
+				t.schema.Tables = append(t.schema.Tables, history)
+				t.tables[history.Name] = history
+			}
+		}
+	}
+
 	return t.schema, nil
 }
 
@@ -810,6 +2320,13 @@ func (t *packageTransformer) applyForeignKeys() error {
 		for _, fk := range fks {
 			if col := schema.Column(fk.Name); col != nil {
 				fk.NotNull = col.NotNull
+				// the constraint of the foreign key can be given in any of
+				// the sides of the relationship
+				if col.Reference != nil && !col.Reference.hasConstraint() {
+					col.Reference.copyConstraint(fk.Reference)
+				} else if col.Reference != nil && !fk.Reference.hasConstraint() {
+					fk.Reference.copyConstraint(col.Reference)
+				}
 				if !col.Equals(fk) {
 					return fmt.Errorf("kallax: there is an inverse definition conflicting with the column definition of column %s in the table %s. Please, make sure both definitions match.", fk.Name, table)
 				}
@@ -833,12 +2350,76 @@ func (t *packageTransformer) transformPkg(pkg *Package) error {
 			return fmt.Errorf("kallax: found more than one model for table %s", m.Table)
 		}
 
+		table.Deprecated = m.Deprecated
 		t.schema.Tables = append(t.schema.Tables, table)
 		t.tables[table.Name] = table
+
+		if m.Audit {
+			audit := auditTable(table, m.ID.ColumnName())
+			audit.Deprecated = m.Deprecated
+			t.schema.Tables = append(t.schema.Tables, audit)
+			t.tables[audit.Name] = audit
+		}
 	}
 	return nil
 }
 
+// historyTable returns the schema of the history table of the given table,
+// with the given primary key. It has all the columns of the table, without
+// their constraints, and the validity of each version.
+func historyTable(table *TableSchema, pk string) *TableSchema {
+	history := &TableSchema{
+		Name:    table.Name + "_history",
+		History: &HistorySchema{Table: table.Name, PrimaryKey: pk},
+	}
+
+	for _, c := range table.Columns {
+		col := &ColumnSchema{Name: c.Name, Type: nonSerialType(c.Type), NotNull: c.NotNull, Deprecated: c.Deprecated, OldName: c.OldName}
+		if c.Name == pk {
+			col.Index = "btree"
+		}
+		history.Columns = append(history.Columns, col)
+	}
+
+	history.Columns = append(history.Columns,
+		&ColumnSchema{Name: historyValidFrom, Type: TimestamptzColumn, NotNull: true},
+		&ColumnSchema{Name: historyValidTo, Type: TimestamptzColumn},
+	)
+	return history
+}
+
+// nonSerialType returns the integer type of the values of the given serial
+// type, or the type itself if it's not serial.
+func nonSerialType(typ ColumnType) ColumnType {
+	switch typ {
+	case SmallSerialColumn:
+		return SmallIntColumn
+	case SerialColumn, BigSerialColumn:
+		return BigIntColumn
+	}
+	return typ
+}
+
+// auditTable returns the schema of the audit table of the given table, with
+// the given primary key.
+func auditTable(table *TableSchema, pk string) *TableSchema {
+	recordType := nonSerialType(table.Column(pk).Type)
+	name := table.Name + "_audit"
+	return &TableSchema{
+		Name: name,
+		Columns: []*ColumnSchema{
+			{Name: "id", Type: BigSerialColumn, PrimaryKey: true, NotNull: true},
+			{Name: "record_id", Type: recordType, NotNull: true, Index: "btree"},
+			{Name: "operation", Type: TextColumn, NotNull: true},
+			{Name: "old_row", Type: JSONBColumn},
+			{Name: "new_row", Type: JSONBColumn},
+			{Name: "actor", Type: TextColumn},
+			{Name: "changed_at", Type: TimestamptzColumn, NotNull: true},
+		},
+		Audit: &AuditSchema{Table: table.Name, PrimaryKey: pk},
+	}
+}
+
 func (t *packageTransformer) transformModel(m *Model) (*TableSchema, error) {
 	schema := &TableSchema{Name: m.Table}
 	var columns = make(map[string]*ColumnSchema)
@@ -848,6 +2429,29 @@ func (t *packageTransformer) transformModel(m *Model) (*TableSchema, error) {
 		return nil, err
 	}
 
+	if m.HasCompositeKey() {
+		for _, f := range m.PrimaryKey {
+			schema.PrimaryKey = append(schema.PrimaryKey, f.ColumnName())
+		}
+	}
+
+	for _, idx := range m.Indexes {
+		for _, c := range idx.Columns {
+			if _, ok := columns[c]; !ok {
+				return nil, fmt.Errorf("kallax: index %s of model %s has the column %s, which is not a column of the model", idx.Name, m.Name, c)
+			}
+		}
+		schema.Indexes = append(schema.Indexes, &IndexSchema{Name: idx.Name, Columns: idx.Columns})
+	}
+
+	for _, c := range m.Checks {
+		schema.Checks = append(schema.Checks, &CheckSchema{Name: c.Name, Expression: c.Expression})
+	}
+
+	if m.Notify {
+		schema.Notify = &NotifySchema{Channel: m.Table + "_changes", PrimaryKey: m.ID.ColumnName()}
+	}
+
 	return schema, nil
 }
 
@@ -855,12 +2459,16 @@ func (t *packageTransformer) transformFields(fields []*Field, columns map[string
 	var result []*ColumnSchema
 
 	for _, f := range fields {
-		if f.IsEmbedded {
+		if f.Inline() {
 			cols, err := t.transformFields(f.Fields, columns)
 			if err != nil {
 				return nil, err
 			}
 			result = append(result, cols...)
+		} else if f.IsManyToManyRelationship() {
+			if err := t.transformJoinTable(f); err != nil {
+				return nil, err
+			}
 		} else {
 			column, err := t.transformField(f)
 			if err != nil {
@@ -885,6 +2493,78 @@ func (t *packageTransformer) transformFields(fields []*Field, columns map[string
 	return result, nil
 }
 
+// transformJoinTable adds the join table of the given many to many
+// relationship to the schema, with a column referencing each model and both
+// of them as primary key. The join table is not added if there is a model for
+// it or if it was already added by the relationship on the other side.
+func (t *packageTransformer) transformJoinTable(f *Field) error {
+	if _, ok := t.pkIndex[f.Through()]; ok {
+		return nil
+	}
+
+	typ := removeTypePrefix(f.Type)
+	table, ok := t.tableIndex[typ]
+	if !ok {
+		return fmt.Errorf("kallax: unable to find table for type %s in field %s of model %s. Is the model type part of the generation input?", typ, f.Name, f.Model.Name)
+	}
+
+	fkType, err := t.transformType(f.Model.ID, false)
+	if err != nil {
+		return err
+	}
+
+	refType, err := t.transformType(t.pkIndex[table], false)
+	if err != nil {
+		return err
+	}
+
+	join := &TableSchema{
+		Name: f.Through(),
+		Columns: []*ColumnSchema{
+			{
+				Name:      f.ForeignKey(),
+				Type:      fkType,
+				NotNull:   true,
+				Reference: &Reference{Table: f.Model.Table, Column: f.Model.ID.ColumnName(), inverse: true},
+			},
+			{
+				Name:      f.JoinTableReferences(),
+				Type:      refType,
+				NotNull:   true,
+				Reference: &Reference{Table: table, Column: t.pkIndex[table].ColumnName(), inverse: true},
+			},
+		},
+		PrimaryKey: []string{f.ForeignKey(), f.JoinTableReferences()},
+	}
+
+	if prev, ok := t.tables[join.Name]; ok {
+		if !sameJoinTable(prev, join) {
+			return fmt.Errorf("kallax: there are two conflicting definitions for join table %s: \n- %s\n- %s", join.Name, prev, join)
+		}
+		return nil
+	}
+
+	t.schema.Tables = append(t.schema.Tables, join)
+	t.tables[join.Name] = join
+	return nil
+}
+
+// sameJoinTable reports whether both join tables have the same columns,
+// regardless of their order, which depends on the side of the relationship
+// they were defined on.
+func sameJoinTable(a, b *TableSchema) bool {
+	if len(a.Columns) != len(b.Columns) {
+		return false
+	}
+
+	for _, col := range a.Columns {
+		if c := b.Column(col.Name); c == nil || !c.Equals(col) {
+			return false
+		}
+	}
+	return true
+}
+
 func (t *packageTransformer) transformField(f *Field) (*ColumnSchema, error) {
 	typ, err := t.transformType(f, f.IsPrimaryKey())
 	if err != nil {
@@ -896,18 +2576,62 @@ func (t *packageTransformer) transformField(f *Field) (*ColumnSchema, error) {
 		return nil, err
 	}
 
+	if f.IsComposite {
+		if err := t.transformComposite(f); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, _, ok := f.Enum(); ok {
+		if err := t.transformEnum(f); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.IsCIText() && ColumnType(strings.TrimRight(string(typ), "[]")) != CITextColumn {
+		return nil, fmt.Errorf("kallax: struct tag `citext` can only be used in string fields. On field %s of model %s.", f.Name, f.Model.Name)
+	}
+
+	if f.Default() != "" && (f.IsAutoIncrement() || f.IsGenerated()) {
+		return nil, fmt.Errorf("kallax: struct tag `default` can not be used in auto-incrementable nor generated fields. On field %s of model %s.", f.Name, f.Model.Name)
+	}
+
 	name := f.ColumnName()
 	if f.Kind == Relationship {
 		name = f.ForeignKey()
 	}
 
+	index, err := columnIndex(f, typ)
+	if err != nil {
+		return nil, fmt.Errorf("kallax: %s. On field %s of model %s.", err, f.Name, f.Model.Name)
+	}
+
+	tsvector, err := tsvectorSchema(f, typ)
+	if err != nil {
+		return nil, fmt.Errorf("kallax: %s. On field %s of model %s.", err, f.Name, f.Model.Name)
+	}
+
+	var jsonSchema string
+	if _, check := f.JSONSchemaFile(); check {
+		jsonSchema = f.JSONSchema
+	}
+
+	_, deprecated := f.Deprecation()
+
 	return &ColumnSchema{
 		Name:       name,
-		PrimaryKey: f.IsPrimaryKey(),
-		NotNull:    !f.IsPtr,
+		PrimaryKey: f.IsPrimaryKey() && !f.Model.HasCompositeKey(),
+		NotNull:    !f.IsPtr && !f.IsNull(),
 		Type:       typ,
 		Reference:  ref,
 		Unique:     f.IsUnique(),
+		Index:      index,
+		TSVector:   tsvector,
+		JSONSchema: jsonSchema,
+		Check:      f.Check(),
+		Default:    f.Default(),
+		Deprecated: deprecated,
+		OldName:    f.OldColumnName(),
 	}, nil
 }
 
@@ -916,17 +2640,41 @@ func (t *packageTransformer) transformType(f *Field, pk bool) (ColumnType, error
 		return ColumnType(typ), nil
 	}
 
+	if name, _, ok := f.Enum(); ok {
+		return ColumnType(name), nil
+	}
+
 	if f.IsJSON {
 		return JSONBColumn, nil
 	}
 
+	if f.IsComposite {
+		return ColumnType(f.CompositeType()), nil
+	}
+
+	if f.Compression() != "" {
+		if !isCompressible(f) {
+			return ColumnType(""), fmt.Errorf("kallax: struct tag `compress` can only be used in string or []byte fields. On field %s of model %s.", f.Name, f.Model.Name)
+		}
+		return ByteaColumn, nil
+	}
+
 	if f.Kind == Array || f.Kind == Slice {
 		typ := removeTypePrefix(f.Type)
 		if typ == "byte" {
 			return ByteaColumn, nil
 		}
 
-		return ArrayColumn(typeMappings[typ]), nil
+		elem, ok := sliceElemType(f)
+		if !ok {
+			return ColumnType(""), fmt.Errorf("kallax: cannot find a suitable type (%s) for the elements of field %s of model %s. Consider using the struct tag `sqltype` to set a custom type for this column.", typ, f.Name, f.Model.Name)
+		}
+
+		elem, err := tagType(f, elem)
+		if err != nil {
+			return ColumnType(""), err
+		}
+		return ArrayColumn(elem), nil
 	}
 
 	if pk {
@@ -934,7 +2682,12 @@ func (t *packageTransformer) transformType(f *Field, pk bool) (ColumnType, error
 			return ColumnType(""), fmt.Errorf("kallax: type %s is not a valid type for a primary key. On field %s of model %s.", f.Type, f.Name, f.Model.Name)
 		}
 
-		return idTypeMappings[identifierType(f)], nil
+		typ := idTypeMappings[identifierType(f)]
+		if f.Model.HasCompositeKey() {
+			// composite primary keys are never auto-incrementable
+			typ = nonSerialType(typ)
+		}
+		return typ, nil
 	}
 
 	if f.Kind == Basic {
@@ -942,7 +2695,8 @@ func (t *packageTransformer) transformType(f *Field, pk bool) (ColumnType, error
 		if !ok {
 			return ColumnType(""), fmt.Errorf("kallax: type %s can not be converted to a SQL type. On field %s of model %s. Consider using the struct tag `sqltype` to set a custom type for this column.", f.Type, f.Name, f.Model.Name)
 		}
-		return typ, nil
+
+		return tagType(f, typ)
 	}
 
 	if f.Kind == Relationship && f.IsInverse() {
@@ -960,7 +2714,58 @@ func (t *packageTransformer) transformType(f *Field, pk bool) (ColumnType, error
 	}
 
 	if f.Kind == Interface {
+		if elem := nullElem(f.Node.Type()); elem != nil {
+			typ, ok := elemType(elem)
+			if !ok {
+				return ColumnType(""), fmt.Errorf("kallax: cannot find a suitable type (%s) for the value of field %s of model %s. Consider using the struct tag `sqltype` to set a custom type for this column.", elem, f.Name, f.Model.Name)
+			}
+
+			return tagType(f, typ)
+		}
+
 		typ := removeTypePrefix(typeName(f.Node.Type()))
+		if geom, ok := geometryTypes[typ]; ok {
+			srid, err := f.SRID()
+			if err != nil {
+				return ColumnType(""), fmt.Errorf("kallax: %s. On field %s of model %s.", err, f.Name, f.Model.Name)
+			}
+
+			return GeometryColumn(geom, srid), nil
+		}
+
+		if typ == vectorType {
+			dims, err := f.Dims()
+			if err != nil {
+				return ColumnType(""), fmt.Errorf("kallax: %s. On field %s of model %s.", err, f.Name, f.Model.Name)
+			}
+
+			return VectorColumn(dims), nil
+		}
+
+		if typ == bitStringType {
+			bits, err := f.Bits()
+			if err != nil {
+				return ColumnType(""), fmt.Errorf("kallax: %s. On field %s of model %s.", err, f.Name, f.Model.Name)
+			}
+
+			if bits == 0 {
+				return VarBitColumn, nil
+			}
+			return BitColumn(bits), nil
+		}
+
+		if typ == moneyType {
+			pg, err := f.IsPGMoney()
+			if err != nil {
+				return ColumnType(""), fmt.Errorf("kallax: %s. On field %s of model %s.", err, f.Name, f.Model.Name)
+			}
+
+			if pg {
+				return PGMoneyColumn, nil
+			}
+			return MoneyColumn, nil
+		}
+
 		if typ, ok := typeMappings[typ]; ok {
 			return typ, nil
 		}
@@ -969,6 +2774,115 @@ func (t *packageTransformer) transformType(f *Field, pk bool) (ColumnType, error
 	return ColumnType(""), fmt.Errorf("kallax: cannot find a suitable type (%s) for field %s of model %s. Consider using the struct tag `sqltype` to set a custom type for this column.", f.Type, f.Name, f.Model.Name)
 }
 
+// transformComposite adds the schema of the composite type of the given field
+// to the schema, after the composite types used by its attributes.
+func (t *packageTransformer) transformComposite(f *Field) error {
+	schema := &TypeSchema{Name: f.CompositeType()}
+	if err := t.transformAttributes(schema, f.Fields); err != nil {
+		return err
+	}
+
+	if prevType, ok := t.types[schema.Name]; ok {
+		if !prevType.Equals(schema) {
+			return fmt.Errorf("kallax: found more than one definition for composite type %s", schema.Name)
+		}
+		return nil
+	}
+
+	t.schema.Types = append(t.schema.Types, schema)
+	t.types[schema.Name] = schema
+	return nil
+}
+
+func (t *packageTransformer) transformAttributes(schema *TypeSchema, fields []*Field) error {
+	for _, f := range fields {
+		if f.IsEmbedded {
+			if err := t.transformAttributes(schema, f.Fields); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if f.IsComposite {
+			if err := t.transformComposite(f); err != nil {
+				return err
+			}
+		}
+
+		if _, _, ok := f.Enum(); ok {
+			if err := t.transformEnum(f); err != nil {
+				return err
+			}
+		}
+
+		typ, err := t.transformType(f, false)
+		if err != nil {
+			return err
+		}
+
+		schema.Attributes = append(schema.Attributes, &ColumnSchema{
+			Name: f.ColumnName(),
+			Type: typ,
+		})
+	}
+	return nil
+}
+
+// isCompressible reports whether the field can be compressed, that is, if
+// it's a string or a []byte.
+func isCompressible(f *Field) bool {
+	typ := f.Node.Type()
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+
+	switch typ := typ.Underlying().(type) {
+	case *types.Basic:
+		return typ.Kind() == types.String
+	case *types.Slice:
+		elem, ok := typ.Elem().(*types.Basic)
+		return ok && elem.Kind() == types.Byte
+	}
+	return false
+}
+
+// tagType returns the type of a column of the given type after applying the
+// struct tags of the field that change it, such as `timezone`, `citext` or `duration`.
+func tagType(f *Field, typ ColumnType) (ColumnType, error) {
+	switch typ {
+	case TimestamptzColumn:
+		return timestampType(f)
+	case TextColumn:
+		if f.IsCIText() {
+			return CITextColumn, nil
+		}
+	case BigIntColumn:
+		interval, err := f.IsDurationInterval()
+		if err != nil {
+			return ColumnType(""), fmt.Errorf("kallax: %s. On field %s of model %s.", err, f.Name, f.Model.Name)
+		}
+
+		if interval {
+			return IntervalColumn, nil
+		}
+	}
+	return typ, nil
+}
+
+// timestampType returns the type of a timestamp column, which is stored with
+// time zone unless the field has the struct tag `timezone:"false"`.
+func timestampType(f *Field) (ColumnType, error) {
+	tz, err := f.Timezone()
+	if err != nil {
+		return ColumnType(""), fmt.Errorf("kallax: %s. On field %s of model %s.", err, f.Name, f.Model.Name)
+	}
+
+	if tz {
+		return TimestamptzColumn, nil
+	}
+	return TimestampColumn, nil
+}
+
 func (t *packageTransformer) transformRef(f *Field) (*Reference, error) {
 	if f.Kind == Relationship && f.IsInverse() {
 		typ := removeTypePrefix(f.Type)
@@ -977,39 +2891,275 @@ func (t *packageTransformer) transformRef(f *Field) (*Reference, error) {
 			return nil, fmt.Errorf("kallax: unable to find table for type %s in field %s of model %s. Is the model type part of the generation input?", typ, f.Name, f.Model.Name)
 		}
 
-		return &Reference{Table: table, Column: t.pkIndex[table].ColumnName(), inverse: true}, nil
+		return foreignKeyReference(f, table, t.pkIndex[table].ColumnName(), true)
 	} else if f.Kind == Relationship {
-		return &Reference{Table: f.Model.Table, Column: f.Model.ID.ColumnName(), inverse: false}, nil
+		return foreignKeyReference(f, f.Model.Table, f.Model.ID.ColumnName(), false)
 	}
 
 	return nil, nil
 }
 
+// foreignKeyReference returns the reference of the foreign key of the given
+// relationship to the given column, with the constraint and actions of the
+// struct tag `fk` of the relationship.
+func foreignKeyReference(f *Field, table, column string, inverse bool) (*Reference, error) {
+	constraint, onDelete, onUpdate := f.ForeignKeyConstraint()
+	ref := &Reference{Table: table, Column: column, Constraint: constraint, inverse: inverse}
+
+	var err error
+	if ref.OnDelete, err = foreignKeyAction(onDelete); err != nil {
+		return nil, fmt.Errorf("kallax: %s. On field %s of model %s.", err, f.Name, f.Model.Name)
+	}
+
+	if ref.OnUpdate, err = foreignKeyAction(onUpdate); err != nil {
+		return nil, fmt.Errorf("kallax: %s. On field %s of model %s.", err, f.Name, f.Model.Name)
+	}
+
+	return ref, nil
+}
+
 var typeMappings = map[string]ColumnType{
-	"gopkg.in/src-d/go-kallax.v1.ULID":      UUIDColumn,
-	"gopkg.in/src-d/go-kallax.v1.UUID":      UUIDColumn,
-	"gopkg.in/src-d/go-kallax.v1.NumericID": BigIntColumn,
-	"github.com/satori/go.uuid.UUID":        UUIDColumn,
-	"github.com/gofrs/uuid.UUID":            UUIDColumn,
-	"string":                                TextColumn,
-	"rune":                                  ColumnType("char(1)"),
-	"uint8":                                 SmallIntColumn,
-	"int8":                                  SmallIntColumn,
-	"byte":                                  SmallIntColumn,
-	"uint16":                                IntegerColumn,
-	"int16":                                 SmallIntColumn,
-	"uint32":                                BigIntColumn,
-	"int32":                                 IntegerColumn,
-	"uint":                                  NumericColumn(20),
-	"int":                                   BigIntColumn,
-	"int64":                                 BigIntColumn,
-	"uint64":                                NumericColumn(20),
-	"float32":                               RealColumn,
-	"float64":                               DoubleColumn,
-	"bool":                                  BooleanColumn,
-	"url.URL":                               TextColumn,
-	"time.Time":                             TimestamptzColumn,
-	"time.Duration":                         BigIntColumn,
+	"gopkg.in/src-d/go-kallax.v1.ULID":          UUIDColumn,
+	"gopkg.in/src-d/go-kallax.v1.UUID":          UUIDColumn,
+	"gopkg.in/src-d/go-kallax.v1.NumericID":     BigIntColumn,
+	"gopkg.in/src-d/go-kallax.v1.HStore":        HStoreColumn,
+	"gopkg.in/src-d/go-kallax.v1.Interval":      IntervalColumn,
+	"gopkg.in/src-d/go-kallax.v1.LargeObjectID": OIDColumn,
+	"gopkg.in/src-d/go-kallax.v1.Int4Range":     Int4RangeColumn,
+	"gopkg.in/src-d/go-kallax.v1.Int8Range":     Int8RangeColumn,
+	"gopkg.in/src-d/go-kallax.v1.NumRange":      NumRangeColumn,
+	"gopkg.in/src-d/go-kallax.v1.TstzRange":     TstzRangeColumn,
+	"gopkg.in/src-d/go-kallax.v1.LTree":         LTreeColumn,
+	"gopkg.in/src-d/go-kallax.v1.TSVector":      TSVectorColumn,
+	"gopkg.in/src-d/go-kallax.v1.XML":           XMLColumn,
+	"github.com/satori/go.uuid.UUID":            UUIDColumn,
+	"github.com/gofrs/uuid.UUID":                UUIDColumn,
+	"github.com/google/uuid.UUID":               UUIDColumn,
+	"string":                                    TextColumn,
+	"rune":                                      ColumnType("char(1)"),
+	"uint8":                                     SmallIntColumn,
+	"int8":                                      SmallIntColumn,
+	"byte":                                      SmallIntColumn,
+	"uint16":                                    IntegerColumn,
+	"int16":                                     SmallIntColumn,
+	"uint32":                                    BigIntColumn,
+	"int32":                                     IntegerColumn,
+	"uint":                                      NumericColumn(20),
+	"int":                                       BigIntColumn,
+	"int64":                                     BigIntColumn,
+	"uint64":                                    NumericColumn(20),
+	"float32":                                   RealColumn,
+	"float64":                                   DoubleColumn,
+	"bool":                                      BooleanColumn,
+	"url.URL":                                   TextColumn,
+	"net.IP":                                    InetColumn,
+	"net.IPNet":                                 CIDRColumn,
+	"net.HardwareAddr":                          MACAddrColumn,
+	"time.Time":                                 TimestamptzColumn,
+	"time.Duration":                             BigIntColumn,
+}
+
+// sliceElemType returns the column type of the elements of the given slice or
+// array field. Elements of a named type are mapped to the column type of the
+// named type or, if there is none, to the one of their underlying basic type.
+func sliceElemType(f *Field) (ColumnType, bool) {
+	if typ, ok := typeMappings[removeTypePrefix(f.Type)]; ok {
+		return typ, true
+	}
+
+	if f.Node == nil {
+		return ColumnType(""), false
+	}
+
+	var elem types.Type
+	switch t := f.Node.Type().Underlying().(type) {
+	case *types.Slice:
+		elem = t.Elem()
+	case *types.Array:
+		elem = t.Elem()
+	default:
+		return ColumnType(""), false
+	}
+
+	if ptr, ok := elem.(*types.Pointer); ok {
+		elem = ptr.Elem()
+	}
+
+	return elemType(elem)
+}
+
+// elemType returns the column type of a value of the given type, which is the
+// column type of the named type or, if there is none, the one of its
+// underlying basic type.
+func elemType(elem types.Type) (ColumnType, bool) {
+	if typ, ok := typeMappings[removeTypePrefix(typeName(elem))]; ok {
+		return typ, true
+	}
+
+	if basic, ok := elem.Underlying().(*types.Basic); ok {
+		typ, ok := typeMappings[basic.Name()]
+		return typ, ok
+	}
+	return ColumnType(""), false
+}
+
+// geometryTypes are the PostGIS geometry types of the kallax spatial types.
+var geometryTypes = map[string]string{
+	"gopkg.in/src-d/go-kallax.v1.Point":    "Point",
+	"gopkg.in/src-d/go-kallax.v1.Geometry": "Geometry",
+}
+
+const vectorType = "gopkg.in/src-d/go-kallax.v1.Vector"
+
+const bitStringType = "gopkg.in/src-d/go-kallax.v1.BitString"
+
+const ltreeType = "gopkg.in/src-d/go-kallax.v1.LTree"
+
+const xmlType = "gopkg.in/src-d/go-kallax.v1.XML"
+
+// columnIndex returns the kind of the index that needs to be created for
+// the column of the given field, if any.
+func columnIndex(f *Field, typ ColumnType) (string, error) {
+	if f.Kind == Interface && f.Node != nil {
+		typ := removeTypePrefix(typeName(f.Node.Type()))
+		if _, ok := geometryTypes[typ]; ok || typ == ltreeType {
+			return "gist", nil
+		}
+
+		if typ == vectorType {
+			return vectorIndex(f)
+		}
+	}
+
+	method, ok := f.Tag.Lookup("index")
+	if !ok {
+		// tsvector columns are only used for full text search, which needs
+		// a GIN index to be fast
+		if typ == TSVectorColumn {
+			return "gin", nil
+		}
+		return "", nil
+	}
+
+	if method == "" {
+		// the values of JSON documents and arrays, and the lexemes of tsvector
+		// columns, can only be searched with GIN indexes
+		if typ == JSONBColumn || typ == TSVectorColumn || strings.HasSuffix(string(typ), "[]") {
+			return "gin", nil
+		}
+		return "btree", nil
+	}
+
+	if !indexMethods[method] {
+		return "", fmt.Errorf("invalid index %q, it must be btree, hash, gin, gist or brin", method)
+	}
+	return method, nil
+}
+
+// indexMethods are the index methods that can be set with the struct tag
+// `index` of a field.
+var indexMethods = map[string]bool{
+	"btree": true,
+	"hash":  true,
+	"gin":   true,
+	"gist":  true,
+	"brin":  true,
+}
+
+// vectorIndexes are the index kinds that can be created for a vector column,
+// with the index method and the operator class of each one.
+var vectorIndexes = map[string][2]string{
+	"hnsw":           {"hnsw", "vector_l2_ops"},
+	"hnsw_cosine":    {"hnsw", "vector_cosine_ops"},
+	"hnsw_ip":        {"hnsw", "vector_ip_ops"},
+	"ivfflat":        {"ivfflat", "vector_l2_ops"},
+	"ivfflat_cosine": {"ivfflat", "vector_cosine_ops"},
+	"ivfflat_ip":     {"ivfflat", "vector_ip_ops"},
+}
+
+// vectorIndex returns the kind of the index of a vector field, which is set
+// with the struct tag `index`. It has the format `method[,distance]`, where
+// method is hnsw or ivfflat and distance is l2 (default), cosine or ip.
+func vectorIndex(f *Field) (string, error) {
+	val := f.Tag.Get("index")
+	if val == "" {
+		return "", nil
+	}
+
+	kind := val
+	if idx := strings.Index(val, ","); idx >= 0 {
+		kind = val[:idx]
+		if dist := val[idx+1:]; dist != "l2" {
+			kind += "_" + dist
+		}
+	}
+
+	if _, ok := vectorIndexes[kind]; !ok {
+		return "", fmt.Errorf("invalid vector index %q", val)
+	}
+	return kind, nil
+}
+
+// tsvectorSchema returns the definition of the document of the tsvector
+// column of the given field, if it is computed from other columns. The
+// sources are defined with the `tsvector` struct tag, which is a list of
+// columns separated by commas, each one optionally followed by a colon and
+// its weight, e.g. `tsvector:"title:A,body:B"`. The text search configuration
+// is set with the `tsconfig` struct tag, "simple" by default. With the struct
+// tag `tsupdate:"trigger"` the column is maintained by a trigger instead of
+// being a generated column.
+func tsvectorSchema(f *Field, typ ColumnType) (*TSVectorSchema, error) {
+	val, ok := f.Tag.Lookup("tsvector")
+	if !ok {
+		return nil, nil
+	}
+
+	if typ != TSVectorColumn {
+		return nil, fmt.Errorf("struct tag `tsvector` can only be used in kallax.TSVector fields")
+	}
+
+	var columns = make(occurrences)
+	f.Model.checkFieldColumns(f.Model.Fields, columns)
+
+	schema := new(TSVectorSchema)
+	for _, src := range strings.Split(val, ",") {
+		parts := strings.SplitN(strings.TrimSpace(src), ":", 2)
+		source := &TSVectorSource{Column: parts[0]}
+		if len(parts) > 1 {
+			source.Weight = strings.ToUpper(parts[1])
+			if len(source.Weight) != 1 || source.Weight[0] < 'A' || source.Weight[0] > 'D' {
+				return nil, fmt.Errorf("invalid tsvector weight %q, it must be A, B, C or D", parts[1])
+			}
+		}
+
+		if source.Column == "" || source.Column == f.ColumnName() || columns[source.Column] == 0 {
+			return nil, fmt.Errorf("invalid tsvector source column %q", source.Column)
+		}
+		schema.Sources = append(schema.Sources, source)
+	}
+
+	if schema.Config = tsvectorConfig(f); strings.ContainsAny(schema.Config, "'\\") {
+		return nil, fmt.Errorf("invalid tsconfig %q", schema.Config)
+	}
+
+	switch upd := f.Tag.Get("tsupdate"); upd {
+	case "", "generated":
+	case "trigger":
+		schema.Trigger = true
+	default:
+		return nil, fmt.Errorf("invalid tsupdate %q, it must be generated or trigger", upd)
+	}
+
+	return schema, nil
+}
+
+// tsvectorConfig returns the text search configuration of the document of the
+// tsvector column of the given field, set with the `tsconfig` struct tag.
+func tsvectorConfig(f *Field) string {
+	if cfg := f.Tag.Get("tsconfig"); cfg != "" {
+		return cfg
+	}
+	return "simple"
 }
 
 var idTypeMappings = map[string]ColumnType{
@@ -1030,3 +3180,21 @@ func reverse(slice []string) []string {
 func indexName(table, column, kind string) string {
 	return fmt.Sprintf("%s__%s__%s", table, column, kind)
 }
+
+// createTableIndexSQL returns the statement creating the given index of a
+// table declared in its model.
+func createTableIndexSQL(table string, idx *IndexSchema) string {
+	return fmt.Sprintf("CREATE INDEX %s ON %s (%s);\n", idx.Name, table, strings.Join(idx.Columns, ", "))
+}
+
+func createIndexSQL(table, column, kind string) string {
+	name := indexName(table, column, kind)
+	if kind == "unique" {
+		return fmt.Sprintf("CREATE UNIQUE INDEX %s ON %s (%s);\n", name, table, column)
+	}
+
+	if idx, ok := vectorIndexes[kind]; ok {
+		return fmt.Sprintf("CREATE INDEX %s ON %s USING %s (%s %s);\n", name, table, idx[0], column, idx[1])
+	}
+	return fmt.Sprintf("CREATE INDEX %s ON %s USING %s (%s);\n", name, table, kind, column)
+}
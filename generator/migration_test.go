@@ -1,6 +1,10 @@
 package generator
 
 import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -8,6 +12,7 @@ import (
 
 	// we need this external libs to be able to generate code corrrectly
 	_ "github.com/gofrs/uuid"
+	_ "github.com/google/uuid"
 	_ "github.com/satori/go.uuid"
 )
 
@@ -18,13 +23,40 @@ func TestNewMigration(t *testing.T) {
 	require.NoError(t, err)
 
 	expectedUp := ChangeSet{&CreateTable{table2}}
-	expectedDown := ChangeSet{&DropTable{"table2"}}
+	expectedDown := ChangeSet{&DropTable{Name: "table2"}}
 
 	require.Equal(t, expectedUp, migration.Up)
 	require.Equal(t, expectedDown, migration.Down)
 	require.Equal(t, migration.Lock, new)
 }
 
+func TestNewMigration_Deprecated(t *testing.T) {
+	deprecatedCol := mkCol("old_num", IntegerColumn, false, false, nil)
+	deprecatedCol.Deprecated = true
+	newCol := mkCol("new_num", IntegerColumn, false, false, nil)
+	newCol.Deprecated = true
+	deprecatedTable := mkTable("table3", mkCol("id", SerialColumn, true, true, nil))
+	deprecatedTable.Deprecated = true
+
+	old := mkSchema(mkTable("table", table1.Columns[0], table1.Columns[1], deprecatedCol))
+	new := mkSchema(
+		mkTable("table", table1.Columns[0], table1.Columns[1], deprecatedCol, newCol),
+		table2,
+		deprecatedTable,
+	)
+	migration, err := NewMigration(old, new)
+	require.NoError(t, err)
+
+	require.Equal(t, ChangeSet{&CreateTable{table2}}, migration.Up)
+	require.Equal(t, mkSchema(old.Tables[0], table2), migration.Lock)
+	require.Len(t, new.Tables[0].Columns, 4)
+
+	migration, err = NewMigration(migration.Lock, DropDeprecated(migration.Lock))
+	require.NoError(t, err)
+	require.Equal(t, ChangeSet{&DropColumn{Table: "table", Name: "old_num"}}, migration.Up)
+	require.Equal(t, mkSchema(table1, table2), migration.Lock)
+}
+
 func TestMigrationOrder(t *testing.T) {
 	a := mkTable("a",
 		mkCol("id", SerialColumn, true, false, nil),
@@ -60,10 +92,10 @@ func TestMigrationOrder(t *testing.T) {
 	}
 
 	expectedDown := ChangeSet{
-		&DropTable{"d"},
-		&DropTable{"c"},
-		&DropTable{"b"},
-		&DropTable{"a"},
+		&DropTable{Name: "d"},
+		&DropTable{Name: "c"},
+		&DropTable{Name: "b"},
+		&DropTable{Name: "a"},
 	}
 
 	require.Equal(t, expectedUp, migration.Up)
@@ -112,6 +144,74 @@ func TestTableSchema(t *testing.T) {
 	require.Equal(t, expectedTable2+"\n", table2.String())
 }
 
+func TestGeometryColumn(t *testing.T) {
+	require.Equal(t, ColumnType("geometry"), GeometryColumn("", 0))
+	require.Equal(t, ColumnType("geometry(Point)"), GeometryColumn("Point", 0))
+	require.Equal(t, ColumnType("geometry(Point,4326)"), GeometryColumn("Point", 4326))
+}
+
+func TestVectorColumn(t *testing.T) {
+	require.Equal(t, ColumnType("vector"), VectorColumn(0))
+	require.Equal(t, ColumnType("vector(1536)"), VectorColumn(1536))
+}
+
+func TestBitColumn(t *testing.T) {
+	require.Equal(t, ColumnType("bit(64)"), BitColumn(64))
+}
+
+func TestVectorIndex(t *testing.T) {
+	cases := []struct {
+		tag      string
+		expected string
+		err      bool
+	}{
+		{``, "", false},
+		{`index:"hnsw"`, "hnsw", false},
+		{`index:"hnsw,l2"`, "hnsw", false},
+		{`index:"hnsw,cosine"`, "hnsw_cosine", false},
+		{`index:"ivfflat,ip"`, "ivfflat_ip", false},
+		{`index:"gist"`, "", true},
+		{`index:"hnsw,manhattan"`, "", true},
+	}
+
+	for _, c := range cases {
+		kind, err := vectorIndex(mkField("Foo", "", c.tag))
+		if c.err {
+			require.Error(t, err, c.tag)
+		} else {
+			require.NoError(t, err, c.tag)
+			require.Equal(t, c.expected, kind, c.tag)
+		}
+	}
+}
+
+func TestColumnIndex(t *testing.T) {
+	cases := []struct {
+		tag      string
+		typ      ColumnType
+		expected string
+		err      bool
+	}{
+		{``, TextColumn, "", false},
+		{`index:""`, TextColumn, "btree", false},
+		{`index:""`, JSONBColumn, "gin", false},
+		{`index:""`, ArrayColumn(TextColumn), "gin", false},
+		{`index:"gist"`, Int4RangeColumn, "gist", false},
+		{`index:"hash"`, TextColumn, "hash", false},
+		{`index:"hnsw"`, TextColumn, "", true},
+	}
+
+	for _, c := range cases {
+		kind, err := columnIndex(mkField("Foo", "", c.tag), c.typ)
+		if c.err {
+			require.Error(t, err, c.tag)
+		} else {
+			require.NoError(t, err, c.tag)
+			require.Equal(t, c.expected, kind, c.tag)
+		}
+	}
+}
+
 func TestArrayColumn(t *testing.T) {
 	require.Equal(t, ColumnType("text[]"), ArrayColumn(TextColumn))
 	require.Equal(t, ColumnType("text[]"), ArrayColumn(ArrayColumn(TextColumn)))
@@ -121,13 +221,27 @@ func TestChangeSet(t *testing.T) {
 	assertChange(
 		t,
 		ChangeSet{
-			&DropTable{"foo"},
-			&DropColumn{"col", "table"},
+			&DropTable{Name: "foo"},
+			&DropColumn{Name: "col", Table: "table"},
 		},
 		"BEGIN;\n\nDROP TABLE foo;\n\nALTER TABLE table DROP COLUMN col;\n\nCOMMIT;\n",
 	)
 }
 
+func TestChangeSetDestructive(t *testing.T) {
+	cs := ChangeSet{
+		&CreateTable{mkTable("bar", mkCol("id", SerialColumn, true, false, nil))},
+		&DropTable{Name: "foo"},
+		&AddColumn{Column: mkCol("num", IntegerColumn, false, false, nil), Table: "table"},
+		&DropColumn{Name: "col", Table: "table"},
+	}
+	require.Equal(t, ChangeSet{
+		&DropTable{Name: "foo"},
+		&DropColumn{Name: "col", Table: "table"},
+	}, cs.Destructive())
+	require.Empty(t, cs[:1].Destructive())
+}
+
 func TestCreateTable(t *testing.T) {
 	assertChange(
 		t,
@@ -150,14 +264,410 @@ func TestCreateTable(t *testing.T) {
 `)
 }
 
+func TestCreateTable_CompositePrimaryKey(t *testing.T) {
+	table := mkTable(
+		"orders",
+		mkCol("tenant_id", BigIntColumn, false, true, nil),
+		mkCol("order_id", BigIntColumn, false, true, nil),
+		mkCol("name", TextColumn, false, false, nil),
+	)
+	table.PrimaryKey = []string{"tenant_id", "order_id"}
+
+	assertChange(t, &CreateTable{table}, `CREATE TABLE orders (
+	tenant_id bigint NOT NULL,
+	order_id bigint NOT NULL,
+	name text,
+	PRIMARY KEY (tenant_id, order_id)
+);
+
+`)
+}
+
+func TestCreateTable_TableIndex(t *testing.T) {
+	table := mkTable(
+		"users",
+		mkCol("id", SerialColumn, true, false, nil),
+		mkCol("email", TextColumn, false, true, nil),
+		mkCol("tenant_id", BigIntColumn, false, true, nil),
+	)
+	table.Indexes = []*IndexSchema{{"idx_email_tenant", []string{"email", "tenant_id"}}}
+
+	assertChange(t, &CreateTable{table}, `CREATE TABLE users (
+	id serial PRIMARY KEY,
+	email text NOT NULL,
+	tenant_id bigint NOT NULL
+);
+CREATE INDEX idx_email_tenant ON users (email, tenant_id);
+
+`)
+}
+
+func TestCreateTable_Checks(t *testing.T) {
+	price := mkCol("price", IntegerColumn, false, true, nil)
+	price.Check = "price >= 0"
+	table := mkTable(
+		"products",
+		mkCol("id", SerialColumn, true, false, nil),
+		price,
+		mkCol("starts_at", TimestamptzColumn, false, true, nil),
+		mkCol("ends_at", TimestamptzColumn, false, true, nil),
+	)
+	table.Checks = []*CheckSchema{{"valid_period", "starts_at < ends_at"}}
+
+	assertChange(t, &CreateTable{table}, `CREATE TABLE products (
+	id serial PRIMARY KEY,
+	price integer NOT NULL CHECK (price >= 0),
+	starts_at timestamptz NOT NULL,
+	ends_at timestamptz NOT NULL,
+	CONSTRAINT valid_period CHECK (starts_at < ends_at)
+);
+
+`)
+}
+
+func TestCreateTable_ForeignKeyConstraint(t *testing.T) {
+	ref := mkRef("users", "id", false)
+	ref.Constraint = true
+	ref.OnDelete = "CASCADE"
+
+	assertChange(t, &CreateTable{mkTable(
+		"posts",
+		mkCol("id", SerialColumn, true, false, nil),
+		mkCol("owner_id", BigIntColumn, false, true, ref),
+		mkCol("editor_id", BigIntColumn, false, false, &Reference{Table: "users", Column: "id", OnDelete: "SET NULL"}),
+	)}, `CREATE TABLE posts (
+	id serial PRIMARY KEY,
+	owner_id bigint NOT NULL,
+	editor_id bigint REFERENCES users(id) ON DELETE SET NULL
+);
+ALTER TABLE posts ADD CONSTRAINT posts_owner_id_fkey FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE;
+
+`)
+}
+
+func TestCreateTable_Index(t *testing.T) {
+	assertChange(
+		t,
+		&CreateTable{mkTable(
+			"table",
+			mkCol("id", SerialColumn, true, false, nil),
+			mkColIndex("location", GeometryColumn("Point", 4326), false, true, "gist"),
+		)},
+		`CREATE TABLE table (
+	id serial PRIMARY KEY,
+	location geometry(Point,4326) NOT NULL
+);
+CREATE INDEX table__location__gist ON table USING gist (location);
+
+`)
+}
+
+func TestCreateTable_TypeDefinition(t *testing.T) {
+	assertChange(
+		t,
+		&CreateTable{mkTable(
+			"table",
+			mkCol("id", SerialColumn, true, false, nil),
+			mkCol("price", MoneyColumn, false, true, nil),
+			mkCol("cost", MoneyColumn, false, false, nil),
+		)},
+		typeDefinitions[MoneyColumn]+`CREATE TABLE table (
+	id serial PRIMARY KEY,
+	price kallax_money NOT NULL,
+	cost kallax_money
+);
+
+`)
+}
+
+func TestCreateIndex(t *testing.T) {
+	expected := `+++
+THIS REQUIRES MANUAL MIGRATION:
+Adding an index on a table that may not be empty.
+If you're sure about this, here's the SQL for this operation.
++++
+
+`
+	assertChange(
+		t,
+		&CreateIndex{"table", "foo", "unique"},
+		expected+"CREATE UNIQUE INDEX table__foo__unique ON table (foo);\n",
+	)
+	assertChange(
+		t,
+		&CreateIndex{"table", "foo", "gist"},
+		expected+"CREATE INDEX table__foo__gist ON table USING gist (foo);\n",
+	)
+	assertChange(
+		t,
+		&CreateIndex{"table", "foo", "hnsw_cosine"},
+		expected+"CREATE INDEX table__foo__hnsw_cosine ON table USING hnsw (foo vector_cosine_ops);\n",
+	)
+}
+
 func TestDropTable(t *testing.T) {
 	assertChange(
 		t,
-		&DropTable{"table"},
+		&DropTable{Name: "table"},
 		"DROP TABLE table;\n",
 	)
 }
 
+func TestDropTable_Triggers(t *testing.T) {
+	assertChange(
+		t,
+		&DropTable{Name: "table", Functions: []string{"table__search__tsvector"}},
+		"DROP TABLE table;\nDROP FUNCTION IF EXISTS table__search__tsvector() CASCADE;\n",
+	)
+}
+
+func TestCreateTable_TSVector(t *testing.T) {
+	search := mkCol("search", TSVectorColumn, false, true, nil)
+	search.TSVector = &TSVectorSchema{
+		Config:  "english",
+		Sources: []*TSVectorSource{{"title", "A"}, {"body", ""}},
+	}
+	triggered := mkCol("triggered", TSVectorColumn, false, false, nil)
+	triggered.TSVector = &TSVectorSchema{
+		Config:  "simple",
+		Sources: []*TSVectorSource{{"title", ""}},
+		Trigger: true,
+	}
+
+	assertChange(
+		t,
+		&CreateTable{mkTable(
+			"table",
+			mkCol("id", SerialColumn, true, false, nil),
+			mkCol("title", TextColumn, false, true, nil),
+			mkCol("body", TextColumn, false, false, nil),
+			search,
+			triggered,
+		)},
+		`CREATE TABLE table (
+	id serial PRIMARY KEY,
+	title text NOT NULL,
+	body text,
+	search tsvector GENERATED ALWAYS AS (setweight(to_tsvector('english', coalesce(title, '')), 'A') || to_tsvector('english', coalesce(body, ''))) STORED NOT NULL,
+	triggered tsvector
+);
+CREATE OR REPLACE FUNCTION table__triggered__tsvector() RETURNS trigger AS $$
+BEGIN
+	NEW.triggered := to_tsvector('simple', coalesce(NEW.title, ''));
+	RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER table__triggered__tsvector BEFORE INSERT OR UPDATE ON table FOR EACH ROW EXECUTE PROCEDURE table__triggered__tsvector();
+
+`)
+}
+
+func TestCreateTable_Audit(t *testing.T) {
+	table := mkTable(
+		"posts_audit",
+		mkCol("id", BigSerialColumn, true, true, nil),
+		mkColIndex("record_id", UUIDColumn, false, true, "btree"),
+	)
+	table.Audit = &AuditSchema{Table: "posts", PrimaryKey: "id"}
+
+	assertChange(
+		t,
+		&CreateTable{table},
+		`CREATE TABLE posts_audit (
+	id bigserial NOT NULL PRIMARY KEY,
+	record_id uuid NOT NULL
+);
+CREATE INDEX posts_audit__record_id__btree ON posts_audit USING btree (record_id);
+CREATE OR REPLACE FUNCTION posts__audit() RETURNS trigger AS $$
+BEGIN
+	IF TG_OP = 'DELETE' THEN
+		INSERT INTO posts_audit (record_id, operation, old_row, new_row, actor, changed_at)
+		VALUES (OLD.id, TG_OP, to_jsonb(OLD), NULL, NULLIF(current_setting('kallax.actor', true), ''), now());
+		RETURN OLD;
+	END IF;
+	INSERT INTO posts_audit (record_id, operation, old_row, new_row, actor, changed_at)
+	VALUES (NEW.id, TG_OP, CASE WHEN TG_OP = 'UPDATE' THEN to_jsonb(OLD) END, to_jsonb(NEW), NULLIF(current_setting('kallax.actor', true), ''), now());
+	RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER posts__audit AFTER INSERT OR UPDATE OR DELETE ON posts FOR EACH ROW EXECUTE PROCEDURE posts__audit();
+
+`)
+
+	require.Equal(t, &DropTable{Name: "posts_audit", Functions: []string{"posts__audit"}}, (&CreateTable{table}).Reverse(nil))
+}
+
+func TestCreateTable_Notify(t *testing.T) {
+	table := mkTable(
+		"posts",
+		mkCol("id", BigSerialColumn, true, true, nil),
+	)
+	table.Notify = &NotifySchema{Channel: "posts_changes", PrimaryKey: "id"}
+
+	assertChange(
+		t,
+		&CreateTable{table},
+		`CREATE TABLE posts (
+	id bigserial NOT NULL PRIMARY KEY
+);
+CREATE OR REPLACE FUNCTION posts__notify() RETURNS trigger AS $$
+BEGIN
+	IF TG_OP = 'DELETE' THEN
+		PERFORM pg_notify('posts_changes', json_build_object('operation', TG_OP, 'id', OLD.id::text)::text);
+		RETURN OLD;
+	END IF;
+	PERFORM pg_notify('posts_changes', json_build_object('operation', TG_OP, 'id', NEW.id::text)::text);
+	RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER posts__notify AFTER INSERT OR UPDATE OR DELETE ON posts FOR EACH ROW EXECUTE PROCEDURE posts__notify();
+
+`)
+
+	require.Equal(t, &DropTable{Name: "posts", Functions: []string{"posts__notify"}}, (&CreateTable{table}).Reverse(nil))
+}
+
+func TestCreateTable_History(t *testing.T) {
+	table := mkTable(
+		"posts_history",
+		mkColIndex("id", BigIntColumn, false, true, "btree"),
+		mkCol("title", TextColumn, false, true, nil),
+		mkCol("valid_from", TimestamptzColumn, false, true, nil),
+		mkCol("valid_to", TimestamptzColumn, false, false, nil),
+	)
+	table.History = &HistorySchema{Table: "posts", PrimaryKey: "id"}
+
+	assertChange(
+		t,
+		&CreateTable{table},
+		`CREATE TABLE posts_history (
+	id bigint NOT NULL,
+	title text NOT NULL,
+	valid_from timestamptz NOT NULL,
+	valid_to timestamptz
+);
+CREATE INDEX posts_history__id__btree ON posts_history USING btree (id);
+CREATE OR REPLACE FUNCTION posts__history() RETURNS trigger AS $$
+BEGIN
+	IF TG_OP <> 'INSERT' THEN
+		UPDATE posts_history SET valid_to = now() WHERE id = OLD.id AND valid_to IS NULL;
+	END IF;
+	IF TG_OP = 'DELETE' THEN
+		RETURN OLD;
+	END IF;
+	INSERT INTO posts_history (id, title, valid_from) VALUES (NEW.id, NEW.title, now());
+	RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER posts__history AFTER INSERT OR UPDATE OR DELETE ON posts FOR EACH ROW EXECUTE PROCEDURE posts__history();
+
+`)
+
+	require.Equal(t, &DropTable{Name: "posts_history", Functions: []string{"posts__history"}}, (&CreateTable{table}).Reverse(nil))
+}
+
+func TestTableSchemaDiff_Notify(t *testing.T) {
+	old := mkTable(
+		"posts",
+		mkCol("id", BigSerialColumn, true, true, nil),
+	)
+	new := mkTable(
+		"posts",
+		mkCol("id", BigSerialColumn, true, true, nil),
+	)
+	new.Notify = &NotifySchema{Channel: "posts_changes", PrimaryKey: "id"}
+
+	require.Empty(t, TableSchemaDiff(new, new))
+	require.False(t, old.Equals(new))
+
+	cs := TableSchemaDiff(old, new)
+	require.Equal(t, ChangeSet{&CreateNotifyTrigger{Table: "posts", Notify: new.Notify}}, cs)
+	require.Equal(t, &DropNotifyTrigger{Table: "posts"}, cs[0].Reverse(mkSchema(old)))
+	sql, err := cs[0].MarshalText()
+	require.NoError(t, err)
+	require.Contains(t, string(sql), "CREATE TRIGGER posts__notify AFTER INSERT OR UPDATE OR DELETE ON posts")
+
+	cs = TableSchemaDiff(new, old)
+	require.Equal(t, ChangeSet{&DropNotifyTrigger{Table: "posts"}}, cs)
+	require.Equal(t, &CreateNotifyTrigger{Table: "posts", Notify: new.Notify}, cs[0].Reverse(mkSchema(new)))
+	assertChange(t, cs[0], "DROP FUNCTION IF EXISTS posts__notify() CASCADE;\n")
+}
+
+func TestTableSchemaDiff_History(t *testing.T) {
+	old := mkTable(
+		"posts_history",
+		mkColIndex("id", BigIntColumn, false, true, "btree"),
+		mkCol("valid_from", TimestamptzColumn, false, true, nil),
+		mkCol("valid_to", TimestamptzColumn, false, false, nil),
+	)
+	old.History = &HistorySchema{Table: "posts", PrimaryKey: "id"}
+	new := mkTable(
+		"posts_history",
+		mkColIndex("id", BigIntColumn, false, true, "btree"),
+		mkCol("title", TextColumn, false, false, nil),
+		mkCol("valid_from", TimestamptzColumn, false, true, nil),
+		mkCol("valid_to", TimestamptzColumn, false, false, nil),
+	)
+	new.History = old.History
+
+	require.Empty(t, TableSchemaDiff(old, old))
+
+	cs := TableSchemaDiff(old, new)
+	require.Equal(t, ChangeSet{
+		&AddColumn{Table: "posts_history", Column: new.Columns[1]},
+		&ReplaceHistoryFunction{new},
+	}, cs)
+	require.Equal(t, &ReplaceHistoryFunction{old}, cs[1].Reverse(mkSchema(old)))
+
+	sql, err := cs[1].MarshalText()
+	require.NoError(t, err)
+	require.Contains(t, string(sql), "INSERT INTO posts_history (id, title, valid_from) VALUES (NEW.id, NEW.title, now());")
+	require.NotContains(t, string(sql), "CREATE TRIGGER")
+}
+
+func TestCreateType(t *testing.T) {
+	assertChange(
+		t,
+		&CreateType{mkType(
+			"address",
+			mkAttr("street", TextColumn),
+			mkAttr("geo", ColumnType("coords")),
+		)},
+		"CREATE TYPE address AS (\n\tstreet text,\n\tgeo coords\n);\n\n",
+	)
+}
+
+func TestDropType(t *testing.T) {
+	assertChange(
+		t,
+		&DropType{"address"},
+		"DROP TYPE address;\n",
+	)
+}
+
+func TestCreateTable_Extension(t *testing.T) {
+	assertChange(
+		t,
+		&CreateTable{mkTable(
+			"table",
+			mkCol("id", SerialColumn, true, false, nil),
+			mkCol("email", CITextColumn, false, true, nil),
+			mkCol("aliases", ArrayColumn(CITextColumn), false, true, nil),
+			mkColIndex("path", LTreeColumn, false, true, "gist"),
+		)},
+		`CREATE EXTENSION IF NOT EXISTS citext;
+CREATE EXTENSION IF NOT EXISTS ltree;
+CREATE TABLE table (
+	id serial PRIMARY KEY,
+	email citext NOT NULL,
+	aliases citext[] NOT NULL,
+	path ltree NOT NULL
+);
+CREATE INDEX table__path__gist ON table USING gist (path);
+
+`)
+}
+
 func TestAddColumn(t *testing.T) {
 	assertChange(
 		t,
@@ -168,6 +678,24 @@ func TestAddColumn(t *testing.T) {
 		"ALTER TABLE table ADD COLUMN foo smallint;\n",
 	)
 
+	assertChange(
+		t,
+		&AddColumn{
+			mkCol("price", MoneyColumn, false, false, nil),
+			"table",
+		},
+		typeDefinitions[MoneyColumn]+"ALTER TABLE table ADD COLUMN price kallax_money;\n",
+	)
+
+	assertChange(
+		t,
+		&AddColumn{
+			mkCol("aliases", ArrayColumn(CITextColumn), false, false, nil),
+			"table",
+		},
+		"CREATE EXTENSION IF NOT EXISTS citext;\nALTER TABLE table ADD COLUMN aliases citext[];\n",
+	)
+
 	assertChange(
 		t,
 		&AddColumn{
@@ -196,14 +724,123 @@ func TestAddColumn(t *testing.T) {
 	)
 }
 
+func TestAddColumn_ForeignKeyConstraint(t *testing.T) {
+	ref := mkRef("users", "id", false)
+	ref.Constraint = true
+
+	assertChange(
+		t,
+		&AddColumn{
+			mkCol("owner_id", BigIntColumn, false, false, ref),
+			"posts",
+		},
+		"ALTER TABLE posts ADD COLUMN owner_id bigint;\nALTER TABLE posts ADD CONSTRAINT posts_owner_id_fkey FOREIGN KEY (owner_id) REFERENCES users(id);\n",
+	)
+}
+
+func TestForeignKey(t *testing.T) {
+	col := mkCol("owner_id", BigIntColumn, false, false, &Reference{Table: "users", Column: "id", OnUpdate: "RESTRICT"})
+
+	assertChange(
+		t,
+		&AddForeignKey{Table: "posts", Column: col},
+		"ALTER TABLE posts ADD CONSTRAINT posts_owner_id_fkey FOREIGN KEY (owner_id) REFERENCES users(id) ON UPDATE RESTRICT;\n",
+	)
+
+	assertChange(
+		t,
+		&DropForeignKey{Table: "posts", Column: col},
+		"ALTER TABLE posts DROP CONSTRAINT posts_owner_id_fkey;\n",
+	)
+
+	require.Equal(t, &DropForeignKey{Table: "posts", Column: col}, (&AddForeignKey{Table: "posts", Column: col}).Reverse(nil))
+}
+
 func TestDropColumn(t *testing.T) {
 	assertChange(
 		t,
-		&DropColumn{"col", "table"},
+		&DropColumn{Name: "col", Table: "table"},
 		"ALTER TABLE table DROP COLUMN col;\n",
 	)
 }
 
+func TestAddColumn_TSVectorTrigger(t *testing.T) {
+	col := mkCol("search", TSVectorColumn, false, false, nil)
+	col.TSVector = &TSVectorSchema{
+		Config:  "simple",
+		Sources: []*TSVectorSource{{"title", "B"}},
+		Trigger: true,
+	}
+
+	assertChange(
+		t,
+		&AddColumn{col, "table"},
+		`ALTER TABLE table ADD COLUMN search tsvector;
+CREATE OR REPLACE FUNCTION table__search__tsvector() RETURNS trigger AS $$
+BEGIN
+	NEW.search := setweight(to_tsvector('simple', coalesce(NEW.title, '')), 'B');
+	RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER table__search__tsvector BEFORE INSERT OR UPDATE ON table FOR EACH ROW EXECUTE PROCEDURE table__search__tsvector();
+UPDATE table SET search = NULL;
+`,
+	)
+
+	require.Equal(
+		t,
+		&DropColumn{Name: "search", Table: "table", Function: "table__search__tsvector"},
+		(&AddColumn{col, "table"}).Reverse(nil),
+	)
+
+	assertChange(
+		t,
+		&DropColumn{Name: "search", Table: "table", Function: "table__search__tsvector"},
+		"ALTER TABLE table DROP COLUMN search;\nDROP FUNCTION IF EXISTS table__search__tsvector() CASCADE;\n",
+	)
+}
+
+func TestCreateTable_JSONSchema(t *testing.T) {
+	settings := mkCol("settings", JSONBColumn, false, true, nil)
+	settings.JSONSchema = `{"type":"object","pattern":"isn't"}`
+
+	assertChange(
+		t,
+		&CreateTable{mkTable(
+			"table",
+			mkCol("id", SerialColumn, true, false, nil),
+			settings,
+		)},
+		`CREATE EXTENSION IF NOT EXISTS pg_jsonschema;
+CREATE TABLE table (
+	id serial PRIMARY KEY,
+	settings jsonb NOT NULL CHECK (jsonb_matches_schema('{"type":"object","pattern":"isn''t"}', settings))
+);
+
+`)
+
+	assertChange(
+		t,
+		&AddColumn{settings, "table"},
+		`CREATE EXTENSION IF NOT EXISTS pg_jsonschema;
+ALTER TABLE table ADD COLUMN settings jsonb NOT NULL CHECK (jsonb_matches_schema('{"type":"object","pattern":"isn''t"}', settings));
+`,
+	)
+}
+
+func TestAlterColumnType(t *testing.T) {
+	assertChange(
+		t,
+		&AlterColumnType{"table", "price", "numeric(10,2)", "numeric(12,4)"},
+		"ALTER TABLE table ALTER COLUMN price TYPE numeric(12,4);\n",
+	)
+	require.Equal(
+		t,
+		&AlterColumnType{"table", "price", "numeric(12,4)", "numeric(10,2)"},
+		(&AlterColumnType{"table", "price", "numeric(10,2)", "numeric(12,4)"}).Reverse(nil),
+	)
+}
+
 func TestManualChange(t *testing.T) {
 	assertChange(
 		t,
@@ -237,7 +874,7 @@ func TestSchemaDiff(t *testing.T) {
 	)
 
 	expected := ChangeSet{
-		&DropTable{"removed"},
+		&DropTable{Name: "removed"},
 		&AddColumn{mkCol("bar", TextColumn, false, false, nil), "shared"},
 		&CreateTable{mkTable("new")},
 	}
@@ -245,6 +882,30 @@ func TestSchemaDiff(t *testing.T) {
 	require.Equal(t, expected, SchemaDiff(old, new))
 }
 
+func TestSchemaDiff_Types(t *testing.T) {
+	old := mkSchema()
+	old.Types = []*TypeSchema{
+		mkType("removed", mkAttr("foo", TextColumn)),
+		mkType("changed", mkAttr("foo", TextColumn)),
+		mkType("shared", mkAttr("foo", TextColumn)),
+	}
+
+	new := mkSchema()
+	new.Types = []*TypeSchema{
+		mkType("changed", mkAttr("foo", BigIntColumn)),
+		mkType("shared", mkAttr("foo", TextColumn)),
+		mkType("new", mkAttr("bar", TextColumn)),
+	}
+
+	expected := ChangeSet{
+		&DropType{"removed"},
+		&ManualChange{"don't know how to generate migration for a change in composite type changed"},
+		&CreateType{mkType("new", mkAttr("bar", TextColumn))},
+	}
+
+	require.Equal(t, expected, SchemaDiff(old, new))
+}
+
 func TestTableSchemaDiff(t *testing.T) {
 	old := mkTable(
 		"table",
@@ -259,13 +920,195 @@ func TestTableSchemaDiff(t *testing.T) {
 	)
 
 	expected := ChangeSet{
-		&DropColumn{"removed", "table"},
+		&DropColumn{Name: "removed", Table: "table"},
 		&AddColumn{mkCol("new", TextColumn, false, false, nil), "table"},
 	}
 
 	require.Equal(t, expected, TableSchemaDiff(old, new))
 }
 
+func TestTableSchemaDiff_PrimaryKey(t *testing.T) {
+	old := mkTable(
+		"table",
+		mkCol("foo", BigIntColumn, false, true, nil),
+		mkCol("bar", BigIntColumn, false, true, nil),
+	)
+	old.PrimaryKey = []string{"foo", "bar"}
+
+	new := *old
+	new.PrimaryKey = []string{"bar", "foo"}
+	require.False(t, old.Equals(&new))
+
+	expected := ChangeSet{
+		&ManualChange{"don't know how to generate migration for a change of primary key in table"},
+	}
+	require.Equal(t, expected, TableSchemaDiff(old, &new))
+}
+
+func TestTableSchemaDiff_Indexes(t *testing.T) {
+	old := mkTable(
+		"table",
+		mkCol("foo", BigIntColumn, false, true, nil),
+		mkCol("bar", BigIntColumn, false, true, nil),
+	)
+	old.Indexes = []*IndexSchema{
+		{"idx_kept", []string{"foo"}},
+		{"idx_removed", []string{"foo", "bar"}},
+		{"idx_changed", []string{"foo", "bar"}},
+	}
+
+	new := mkTable(
+		"table",
+		mkCol("foo", BigIntColumn, false, true, nil),
+		mkCol("baz", BigIntColumn, false, true, nil),
+	)
+	new.Indexes = []*IndexSchema{
+		{"idx_kept", []string{"foo"}},
+		{"idx_changed", []string{"foo", "baz"}},
+	}
+	require.False(t, old.Equals(new))
+
+	expected := ChangeSet{
+		&DropTableIndex{"table", old.Indexes[1]},
+		&DropTableIndex{"table", old.Indexes[2]},
+		&DropColumn{Name: "bar", Table: "table"},
+		&AddColumn{mkCol("baz", BigIntColumn, false, true, nil), "table"},
+		&CreateTableIndex{"table", new.Indexes[1]},
+	}
+	require.Equal(t, expected, TableSchemaDiff(old, new))
+
+	assertChange(t, expected[0], "DROP INDEX idx_removed;\n")
+	assertChange(t, expected[4], `+++
+THIS REQUIRES MANUAL MIGRATION:
+Adding an index on a table that may not be empty.
+If you're sure about this, here's the SQL for this operation.
++++
+
+CREATE INDEX idx_changed ON table (foo, baz);
+`)
+}
+
+func TestTableSchemaDiff_Checks(t *testing.T) {
+	old := mkTable(
+		"table",
+		mkCol("foo", BigIntColumn, false, true, nil),
+		mkCol("bar", BigIntColumn, false, true, nil),
+	)
+	old.Checks = []*CheckSchema{
+		{"check_kept", "foo > 0"},
+		{"check_removed", "foo > bar"},
+		{"check_changed", "foo > bar"},
+	}
+
+	new := mkTable(
+		"table",
+		mkCol("foo", BigIntColumn, false, true, nil),
+		mkCol("baz", BigIntColumn, false, true, nil),
+	)
+	new.Checks = []*CheckSchema{
+		{"check_kept", "foo > 0"},
+		{"check_changed", "foo > baz"},
+	}
+	require.False(t, old.Equals(new))
+
+	expected := ChangeSet{
+		&DropCheck{"table", old.Checks[1]},
+		&DropCheck{"table", old.Checks[2]},
+		&DropColumn{Name: "bar", Table: "table"},
+		&AddColumn{mkCol("baz", BigIntColumn, false, true, nil), "table"},
+		&AddCheck{"table", new.Checks[1]},
+	}
+	require.Equal(t, expected, TableSchemaDiff(old, new))
+
+	assertChange(t, expected[0], "ALTER TABLE table DROP CONSTRAINT check_removed;\n")
+	assertChange(t, expected[4], "ALTER TABLE table ADD CONSTRAINT check_changed CHECK (foo > baz);\n")
+	assertChange(t, expected[4].Reverse(nil), "ALTER TABLE table DROP CONSTRAINT check_changed;\n")
+}
+
+func TestNewMigration_ChangedCheck(t *testing.T) {
+	old := mkTable("table", mkCol("price", IntegerColumn, false, true, nil))
+	old.Checks = []*CheckSchema{{"table_price_check", "price >= 0"}}
+	new := mkTable("table", mkCol("price", IntegerColumn, false, true, nil))
+	new.Checks = []*CheckSchema{{"table_price_check", "price > 0"}}
+
+	migration, err := NewMigration(mkSchema(old), mkSchema(new))
+	require.NoError(t, err)
+	assertChange(t, migration.Up, `BEGIN;
+
+ALTER TABLE table DROP CONSTRAINT table_price_check;
+
+ALTER TABLE table ADD CONSTRAINT table_price_check CHECK (price > 0);
+
+COMMIT;
+`)
+	assertChange(t, migration.Down, `BEGIN;
+
+ALTER TABLE table DROP CONSTRAINT table_price_check;
+
+ALTER TABLE table ADD CONSTRAINT table_price_check CHECK (price >= 0);
+
+COMMIT;
+`)
+}
+
+func TestColumnSchemaDiff_Check(t *testing.T) {
+	old := mkCol("price", IntegerColumn, false, true, nil)
+	new := mkCol("price", IntegerColumn, false, true, nil)
+	new.Check = "price >= 0"
+	require.False(t, old.Equals(new))
+
+	added := ChangeSet{&AddCheck{"table", &CheckSchema{"table_price_check", "price >= 0"}}}
+	require.Equal(t, added, ColumnSchemaDiff("table", old, new))
+	assertChange(t, added[0], "ALTER TABLE table ADD CONSTRAINT table_price_check CHECK (price >= 0);\n")
+
+	require.Equal(t, ChangeSet{
+		&DropCheck{"table", &CheckSchema{"table_price_check", "price >= 0"}},
+	}, ColumnSchemaDiff("table", new, old))
+
+	changed := *new
+	changed.Check = "price > 0"
+	require.Equal(t, ChangeSet{
+		&DropCheck{"table", &CheckSchema{"table_price_check", "price >= 0"}},
+		&AddCheck{"table", &CheckSchema{"table_price_check", "price > 0"}},
+	}, ColumnSchemaDiff("table", new, &changed))
+
+	assertChange(t, &RenameColumn{Table: "table", Column: new, Name: "amount"}, "ALTER TABLE table RENAME COLUMN price TO amount;\nALTER TABLE table RENAME CONSTRAINT table_price_check TO table_amount_check;\n")
+	assertChange(t, &AddColumn{new, "table"}, "ALTER TABLE table ADD COLUMN price integer NOT NULL CHECK (price >= 0);\n")
+}
+
+func TestTableSchemaDiff_RenameColumn(t *testing.T) {
+	old := mkTable(
+		"table",
+		mkCol("id", SerialColumn, true, true, nil),
+		mkColIndex("name", TextColumn, false, true, "btree"),
+		mkCol("owner_id", BigIntColumn, false, true, mkRef("users", "id", false)),
+		mkCol("removed", TextColumn, false, true, nil),
+	)
+
+	name := mkColIndex("full_name", ArrayColumn(TextColumn), false, true, "btree")
+	name.OldName = "name"
+	owner := mkCol("user_id", BigIntColumn, false, true, mkRef("users", "id", false))
+	owner.OldName = "owner_id"
+	added := mkCol("added", TextColumn, false, true, nil)
+	added.OldName = "missing"
+	new := mkTable("table", mkCol("id", SerialColumn, true, true, nil), name, owner, added)
+
+	expected := ChangeSet{
+		&RenameColumn{Table: "table", Column: old.Columns[1], Name: "full_name"},
+		&ManualChange{"don't know how to generate migration for a change of type in table(full_name)"},
+		&RenameColumn{Table: "table", Column: old.Columns[2], Name: "user_id"},
+		&DropColumn{Name: "removed", Table: "table"},
+		&AddColumn{added, "table"},
+	}
+	require.Equal(t, expected, TableSchemaDiff(old, new))
+
+	assertChange(t, expected[0], "ALTER TABLE table RENAME COLUMN name TO full_name;\nALTER INDEX table__name__btree RENAME TO table__full_name__btree;\n")
+	assertChange(t, expected[2], "ALTER TABLE table RENAME COLUMN owner_id TO user_id;\nALTER TABLE table RENAME CONSTRAINT table_owner_id_fkey TO table_user_id_fkey;\n")
+
+	reverse := expected[2].Reverse(nil)
+	assertChange(t, reverse, "ALTER TABLE table RENAME COLUMN user_id TO owner_id;\nALTER TABLE table RENAME CONSTRAINT table_user_id_fkey TO table_owner_id_fkey;\n")
+}
+
 func TestColumnSchemaDiff_Unique(t *testing.T) {
 	cases := []struct {
 		name     string
@@ -284,6 +1127,18 @@ func TestColumnSchemaDiff_Unique(t *testing.T) {
 			mkCol("foo", TextColumn, false, false, nil),
 			&DropIndex{"table", "foo", "unique"},
 		},
+		{
+			"gist index added",
+			mkCol("foo", TextColumn, false, false, nil),
+			mkColIndex("foo", TextColumn, false, false, "gist"),
+			&CreateIndex{"table", "foo", "gist"},
+		},
+		{
+			"gist index dropped",
+			mkColIndex("foo", TextColumn, false, false, "gist"),
+			mkCol("foo", TextColumn, false, false, nil),
+			&DropIndex{"table", "foo", "gist"},
+		},
 	}
 
 	for _, tt := range cases {
@@ -295,6 +1150,89 @@ func TestColumnSchemaDiff_Unique(t *testing.T) {
 	}
 }
 
+func TestNewMigration_ChangedForeignKeyAction(t *testing.T) {
+	users := mkTable("users", mkCol("id", SerialColumn, true, true, nil))
+	old := mkSchema(users, mkTable(
+		"pets",
+		mkCol("id", SerialColumn, true, true, nil),
+		mkCol("owner_id", BigIntColumn, false, false, &Reference{Table: "users", Column: "id", Constraint: true, OnDelete: "CASCADE"}),
+	))
+	new := mkSchema(users, mkTable(
+		"pets",
+		mkCol("id", SerialColumn, true, true, nil),
+		mkCol("owner_id", BigIntColumn, false, false, &Reference{Table: "users", Column: "id", Constraint: true, OnDelete: "RESTRICT"}),
+	))
+
+	migration, err := NewMigration(old, new)
+	require.NoError(t, err)
+	assertChange(t, migration.Up, `BEGIN;
+
+ALTER TABLE pets DROP CONSTRAINT pets_owner_id_fkey;
+
+ALTER TABLE pets ADD CONSTRAINT pets_owner_id_fkey FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE RESTRICT;
+
+COMMIT;
+`)
+	assertChange(t, migration.Down, `BEGIN;
+
+ALTER TABLE pets DROP CONSTRAINT pets_owner_id_fkey;
+
+ALTER TABLE pets ADD CONSTRAINT pets_owner_id_fkey FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE;
+
+COMMIT;
+`)
+}
+
+func TestColumnSchemaDiff_ForeignKey(t *testing.T) {
+	plain := mkCol("owner_id", BigIntColumn, false, false, mkRef("users", "id", false))
+	cascade := mkCol("owner_id", BigIntColumn, false, false, &Reference{Table: "users", Column: "id", Constraint: true, OnDelete: "CASCADE"})
+	restrict := mkCol("owner_id", BigIntColumn, false, false, &Reference{Table: "users", Column: "id", Constraint: true, OnDelete: "RESTRICT"})
+	none := mkCol("owner_id", BigIntColumn, false, false, nil)
+
+	cases := []struct {
+		name     string
+		old, new *ColumnSchema
+		result   ChangeSet
+	}{
+		{
+			"constraint added",
+			none,
+			cascade,
+			ChangeSet{&AddForeignKey{"table", cascade}},
+		},
+		{
+			"constraint dropped",
+			cascade,
+			none,
+			ChangeSet{&DropForeignKey{"table", cascade}},
+		},
+		{
+			"action added",
+			plain,
+			cascade,
+			ChangeSet{&DropForeignKey{"table", plain}, &AddForeignKey{"table", cascade}},
+		},
+		{
+			"action changed",
+			cascade,
+			restrict,
+			ChangeSet{&DropForeignKey{"table", cascade}, &AddForeignKey{"table", restrict}},
+		},
+		{
+			"same constraint",
+			cascade,
+			cascade,
+			nil,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.result, ColumnSchemaDiff("table", tt.old, tt.new))
+		})
+	}
+}
+
 func TestColumnSchemaDiff(t *testing.T) {
 	cases := []struct {
 		name                 string
@@ -367,6 +1305,64 @@ func TestColumnSchemaDiff(t *testing.T) {
 	}
 }
 
+func TestColumnSchemaDiff_TypeModifier(t *testing.T) {
+	cases := []struct {
+		old, new ColumnType
+		alter    bool
+	}{
+		{"varchar(32)", "varchar(64)", true},
+		{"varchar(32)", "varchar", true},
+		{"numeric(10,2)", "numeric(12, 4)", true},
+		{"NUMERIC(10,2)", "numeric(12,4)", true},
+		{"char(2)[]", "char(3)[]", true},
+		{"bit(8)", "bit(16)", true},
+		{"char(2)", "char(3)[]", false},
+		{"varchar(32)", "text", false},
+		{"numeric(10,2)", "decimal(10,2)", false},
+		{"geometry(Point)", "geometry(Point,4326)", false},
+		{"vector(3)", "vector(4)", false},
+	}
+
+	for _, c := range cases {
+		changes := ColumnSchemaDiff("table", mkCol("foo", c.old, false, false, nil), mkCol("foo", c.new, false, false, nil))
+		require.Len(t, changes, 1, "%s -> %s", c.old, c.new)
+		if c.alter {
+			require.Equal(t, &AlterColumnType{"table", "foo", c.old, c.new}, changes[0], "%s -> %s", c.old, c.new)
+		} else {
+			require.IsType(t, &ManualChange{}, changes[0], "%s -> %s", c.old, c.new)
+		}
+	}
+}
+
+func TestColumnSchemaDiff_Default(t *testing.T) {
+	cases := []struct {
+		old, new string
+		sql      string
+	}{
+		{"", "now()", "ALTER TABLE table ALTER COLUMN foo SET DEFAULT now();\n"},
+		{"0", "1", "ALTER TABLE table ALTER COLUMN foo SET DEFAULT 1;\n"},
+		{"now()", "", "ALTER TABLE table ALTER COLUMN foo DROP DEFAULT;\n"},
+	}
+
+	for _, c := range cases {
+		old := mkCol("foo", IntegerColumn, false, true, nil)
+		old.Default = c.old
+		new := mkCol("foo", IntegerColumn, false, true, nil)
+		new.Default = c.new
+		require.False(t, old.Equals(new))
+
+		changes := ColumnSchemaDiff("table", old, new)
+		require.Equal(t, ChangeSet{&AlterColumnDefault{"table", "foo", c.old, c.new}}, changes)
+		assertChange(t, changes[0], c.sql)
+		require.Equal(t, &AlterColumnDefault{"table", "foo", c.new, c.old}, changes[0].Reverse(nil))
+	}
+
+	col := mkCol("created_at", TimestamptzColumn, false, true, nil)
+	col.Default = "now()"
+	assertChange(t, &AddColumn{col, "table"}, "ALTER TABLE table ADD COLUMN created_at timestamptz DEFAULT now() NOT NULL;\n")
+	require.Empty(t, ColumnSchemaDiff("table", col, col))
+}
+
 func TestReverseChange(t *testing.T) {
 	require := require.New(t)
 	old := mkSchema(
@@ -375,6 +1371,7 @@ func TestReverseChange(t *testing.T) {
 			mkCol("bar", SmallIntColumn, false, false, nil),
 		),
 	)
+	old.Types = []*TypeSchema{mkType("baz", mkAttr("qux", TextColumn))}
 
 	cases := []struct {
 		original Change
@@ -407,8 +1404,16 @@ func TestReverseChange(t *testing.T) {
 			&DropIndex{"foo", "bar", "baz"},
 		},
 		{
-			&DropIndex{"foo", "bar", "baz"},
-			&CreateIndex{"foo", "bar", "baz"},
+			&DropIndex{"foo", "bar", "baz"},
+			&CreateIndex{"foo", "bar", "baz"},
+		},
+		{
+			&CreateType{&TypeSchema{Name: "baz"}},
+			&DropType{Name: "baz"},
+		},
+		{
+			&DropType{Name: "baz"},
+			&CreateType{old.Type("baz")},
 		},
 		{
 			&ManualChange{"foo"},
@@ -555,8 +1560,35 @@ func TestChangeSetSorted(t *testing.T) {
 	expected := ChangeSet{
 		&CreateTable{new.Table("table4")},
 		&CreateTable{new.Table("table5")},
-		&DropTable{"table1"},
-		&DropTable{"table2"},
+		&DropTable{Name: "table1"},
+		&DropTable{Name: "table2"},
+	}
+
+	sorted, err := cs.sorted(old.index(), new.index())
+	require.NoError(t, err)
+	require.Equal(t, expected, sorted)
+}
+
+func TestChangeSetSorted_Types(t *testing.T) {
+	old := mkSchema(mkTable("table1", mkCol("foo", ColumnType("type1"), false, false, nil)))
+	old.Types = []*TypeSchema{
+		mkType("type2", mkAttr("foo", TextColumn)),
+		mkType("type1", mkAttr("foo", ColumnType("type2"))),
+	}
+	new := mkSchema(mkTable("table2", mkCol("foo", ColumnType("type3"), false, false, nil)))
+	new.Types = []*TypeSchema{
+		mkType("type4", mkAttr("foo", TextColumn)),
+		mkType("type3", mkAttr("foo", ColumnType("type4"))),
+	}
+
+	cs := SchemaDiff(old, new)
+	expected := ChangeSet{
+		&CreateType{new.Type("type4")},
+		&CreateType{new.Type("type3")},
+		&CreateTable{new.Table("table2")},
+		&DropTable{Name: "table1"},
+		&DropType{"type1"},
+		&DropType{"type2"},
 	}
 
 	sorted, err := cs.sorted(old.index(), new.index())
@@ -564,6 +1596,78 @@ func TestChangeSetSorted(t *testing.T) {
 	require.Equal(t, expected, sorted)
 }
 
+func TestSchemaDiff_Enums(t *testing.T) {
+	r := require.New(t)
+	old := mkSchema()
+	old.Enums = []*EnumSchema{
+		{Name: "status_type", Values: []string{"active", "banned"}},
+		{Name: "role_type", Values: []string{"admin"}},
+	}
+	new := mkSchema()
+	new.Enums = []*EnumSchema{
+		{Name: "status_type", Values: []string{"pending", "active", "disabled", "banned", "deleted"}},
+		{Name: "color_type", Values: []string{"red", "green"}},
+	}
+
+	m, err := NewMigration(old, new)
+	r.NoError(err)
+	r.Contains(m.Down, &DropEnum{"color_type"})
+	r.Contains(m.Down, &CreateEnum{old.Enum("role_type")})
+
+	steps := m.Steps()
+	r.Len(steps, 4)
+	var up []string
+	for _, step := range steps[:3] {
+		r.Nil(step.Lock)
+		sql, err := step.Up.MarshalText()
+		r.NoError(err)
+		up = append(up, string(sql))
+		r.Equal(ChangeSet{&ManualChange{
+			fmt.Sprintf("value %s can not be removed from enum type status_type", step.Up[0].(*AddEnumValue).Value),
+		}}, step.Down)
+	}
+	r.Equal([]string{
+		"ALTER TYPE status_type ADD VALUE IF NOT EXISTS 'pending' BEFORE 'active';\n",
+		"ALTER TYPE status_type ADD VALUE IF NOT EXISTS 'disabled' AFTER 'active';\n",
+		"ALTER TYPE status_type ADD VALUE IF NOT EXISTS 'deleted' AFTER 'banned';\n",
+	}, up)
+
+	r.Equal(m.Lock, steps[3].Lock)
+	sql, err := steps[3].Up.MarshalText()
+	r.NoError(err)
+	r.Equal(`BEGIN;
+
+CREATE TYPE color_type AS ENUM ('red', 'green');
+
+
+DROP TYPE role_type;
+
+COMMIT;
+`, string(sql))
+	sql, err = steps[3].Down.MarshalText()
+	r.NoError(err)
+	r.Equal(`BEGIN;
+
+CREATE TYPE role_type AS ENUM ('admin');
+
+
+DROP TYPE color_type;
+
+COMMIT;
+`, string(sql))
+
+	m, err = NewMigration(mkSchema(), mkSchema(mkTable("table1")))
+	r.NoError(err)
+	r.Equal([]*Migration{m}, m.Steps())
+
+	for _, values := range [][]string{{"active"}, {"banned", "active"}} {
+		cs := enumDiff(old.Enum("status_type"), &EnumSchema{Name: "status_type", Values: values})
+		r.Equal(ChangeSet{&ManualChange{
+			"don't know how to generate migration for removing or reordering the values of enum type status_type",
+		}}, cs, "%v", values)
+	}
+}
+
 type PackageTransformerSuite struct {
 	suite.Suite
 	t   *packageTransformer
@@ -575,17 +1679,20 @@ package foo
 
 import (
 	"gopkg.in/src-d/go-kallax.v1"
+	"net"
 	"net/url"
+	"time"
 	satori "github.com/satori/go.uuid"
 	gofrs "github.com/gofrs/uuid"
+	google "github.com/google/uuid"
 )
 
 type User struct {
 	kallax.Model ` + "`table:\"users\"`" + `
 	ID kallax.ULID ` + "`pk:\"\"`" + `
-	Username string ` + "`unique:\"true\"`" + `
+	Username string ` + "`unique:\"true\" citext:\"\"`" + `
 	// array field
-	Emails []string
+	Emails []string ` + "`citext:\"\"`" + `
 	Profile *Profile
 	Phone *string
 }
@@ -604,6 +1711,51 @@ type Profile struct {
 	// should be added as bigint, as it is not a pk
 	Metadata ProfileMetadata
 	SomeData []byte
+	Attributes kallax.HStore
+	Cooldown kallax.Interval
+	LastIP net.IP
+	Network *net.IPNet
+	Device net.HardwareAddr
+	Location kallax.Point ` + "`srid:\"4326\"`" + `
+	Area *kallax.Geometry
+	Balance kallax.Money
+	Salary *kallax.Money ` + "`money:\"money\"`" + `
+	Avatar kallax.LargeObjectID
+	Availability kallax.TstzRange
+	Ages *kallax.Int4Range
+	Statuses []Status
+	Cooldowns []kallax.Interval
+	Embeddings [][]float64
+	Chunks [][]byte
+	Embedding kallax.Vector ` + "`dims:\"3\" index:\"hnsw,cosine\"`" + `
+	Birthday time.Time ` + "`timezone:\"false\"`" + `
+	LastSeen *time.Time
+	Reminders []time.Time ` + "`timezone:\"false\"`" + `
+	Home Address
+	Work *Address
+	Notes string ` + "`compress:\"gzip\"`" + `
+	Attachment *[]byte ` + "`compress:\"zlib\"`" + `
+	Flags kallax.BitString ` + "`bits:\"8\"`" + `
+	Tags *kallax.BitString
+	Path kallax.LTree
+	Document *kallax.XML
+	Timeout time.Duration
+	Backoff *time.Duration ` + "`duration:\"interval\"`" + `
+}
+
+type Status string
+
+type Address struct {
+	kallax.Composite
+	Street string
+	Zip *string
+	Geo Coordinates
+}
+
+type Coordinates struct {
+	kallax.Composite ` + "`type:\"coords\"`" + `
+	Lat float64
+	Lng float64
 }
 
 type ProfileMetadata struct {
@@ -620,6 +1772,7 @@ type UUIDTable struct {
 	ID kallax.ULID ` + "`pk:\"\"`" + `
 	SatoriUUID satori.UUID
 	GofrsUUID gofrs.UUID
+	GoogleUUID google.UUID
 }
 `
 
@@ -655,6 +1808,36 @@ func (s *PackageTransformerSuite) TestTransform() {
 			mkCol("user_id", UUIDColumn, false, false, mkRef("users", "id", true)),
 			mkCol("spouse", UUIDColumn, false, false, nil),
 			mkCol("some_data", ByteaColumn, false, true, nil),
+			mkCol("attributes", HStoreColumn, false, true, nil),
+			mkCol("cooldown", IntervalColumn, false, true, nil),
+			mkCol("last_ip", InetColumn, false, true, nil),
+			mkCol("network", CIDRColumn, false, false, nil),
+			mkCol("device", MACAddrColumn, false, true, nil),
+			mkColIndex("location", GeometryColumn("Point", 4326), false, true, "gist"),
+			mkColIndex("area", GeometryColumn("Geometry", 0), false, false, "gist"),
+			mkCol("balance", MoneyColumn, false, true, nil),
+			mkCol("salary", PGMoneyColumn, false, false, nil),
+			mkCol("avatar", OIDColumn, false, true, nil),
+			mkCol("availability", TstzRangeColumn, false, true, nil),
+			mkCol("ages", Int4RangeColumn, false, false, nil),
+			mkCol("statuses", ArrayColumn(TextColumn), false, true, nil),
+			mkCol("cooldowns", ArrayColumn(IntervalColumn), false, true, nil),
+			mkCol("embeddings", ArrayColumn(DoubleColumn), false, true, nil),
+			mkCol("chunks", JSONBColumn, false, true, nil),
+			mkColIndex("embedding", VectorColumn(3), false, true, "hnsw_cosine"),
+			mkCol("birthday", TimestampColumn, false, true, nil),
+			mkCol("last_seen", TimestamptzColumn, false, false, nil),
+			mkCol("reminders", ArrayColumn(TimestampColumn), false, true, nil),
+			mkCol("home", ColumnType("address"), false, true, nil),
+			mkCol("work", ColumnType("address"), false, false, nil),
+			mkCol("notes", ByteaColumn, false, true, nil),
+			mkCol("attachment", ByteaColumn, false, false, nil),
+			mkCol("flags", BitColumn(8), false, true, nil),
+			mkCol("tags", VarBitColumn, false, false, nil),
+			mkColIndex("path", LTreeColumn, false, true, "gist"),
+			mkCol("document", XMLColumn, false, false, nil),
+			mkCol("timeout", BigIntColumn, false, true, nil),
+			mkCol("backoff", IntervalColumn, false, false, nil),
 		),
 		mkTable(
 			"metadata",
@@ -667,16 +1850,31 @@ func (s *PackageTransformerSuite) TestTransform() {
 			mkCol("id", UUIDColumn, true, true, nil),
 			mkCol("satori_uuid", UUIDColumn, false, true, nil),
 			mkCol("gofrs_uuid", UUIDColumn, false, true, nil),
+			mkCol("google_uuid", UUIDColumn, false, true, nil),
 		),
 		mkTable(
 			"users",
 			mkCol("id", UUIDColumn, true, true, nil),
-			mkColUnique("username", TextColumn, false, true, nil),
-			mkCol("emails", ArrayColumn(TextColumn), false, true, nil),
+			mkColUnique("username", CITextColumn, false, true, nil),
+			mkCol("emails", ArrayColumn(CITextColumn), false, true, nil),
 			mkCol("phone", TextColumn, false, false, nil),
 		),
 	)
 
+	expected.Types = []*TypeSchema{
+		mkType(
+			"coords",
+			mkAttr("lat", DoubleColumn),
+			mkAttr("lng", DoubleColumn),
+		),
+		mkType(
+			"address",
+			mkAttr("street", TextColumn),
+			mkAttr("zip", TextColumn),
+			mkAttr("geo", ColumnType("coords")),
+		),
+	}
+
 	require.Equal(expected, schema)
 }
 
@@ -702,6 +1900,567 @@ func (s *PackageTransformerSuite) TestApplyInverses_ConfictingCol() {
 	s.Error(s.t.applyForeignKeys())
 }
 
+func (s *PackageTransformerSuite) TestTransform_InvalidCIText() {
+	pkg, err := processFixture(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Foo struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Count int64 ` + "`citext:\"\"`" + `
+	}
+	`)
+	s.Require().NoError(err)
+
+	_, err = s.t.transform(pkg)
+	s.Error(err)
+}
+
+func (s *PackageTransformerSuite) TestTransform_InvalidCompress() {
+	pkg, err := processFixture(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Foo struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Count int64 ` + "`compress:\"gzip\"`" + `
+	}
+	`)
+	s.Require().NoError(err)
+
+	_, err = s.t.transform(pkg)
+	s.Error(err)
+}
+
+func (s *PackageTransformerSuite) TestTransform_TSVector() {
+	pkg, err := processFixture(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Post struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Title string
+		Body *string
+		Search kallax.TSVector ` + "`tsvector:\"title:A, body:b\" tsconfig:\"english\"`" + `
+		Triggered *kallax.TSVector ` + "`tsvector:\"body\" tsupdate:\"trigger\"`" + `
+	}
+	`)
+	s.Require().NoError(err)
+
+	schema, err := s.t.transform(pkg)
+	s.Require().NoError(err)
+
+	table := schema.Table("post")
+	s.Require().NotNil(table)
+	s.Equal(&TSVectorSchema{
+		Config:  "english",
+		Sources: []*TSVectorSource{{"title", "A"}, {"body", "B"}},
+	}, table.Column("search").TSVector)
+	s.Equal(&TSVectorSchema{
+		Config:  "simple",
+		Sources: []*TSVectorSource{{"body", ""}},
+		Trigger: true,
+	}, table.Column("triggered").TSVector)
+	s.Equal("gin", table.Column("search").Index)
+	s.Equal("gin", table.Column("triggered").Index)
+}
+
+func (s *PackageTransformerSuite) TestTransform_Audit() {
+	pkg, err := processFixture(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Post struct {
+		kallax.Model ` + "`table:\"posts\" audit:\"true\"`" + `
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Title string
+	}
+	`)
+	s.Require().NoError(err)
+
+	schema, err := s.t.transform(pkg)
+	s.Require().NoError(err)
+	s.Len(schema.Tables, 2)
+
+	table := schema.Table("posts_audit")
+	s.Require().NotNil(table)
+	s.Equal(&AuditSchema{Table: "posts", PrimaryKey: "id"}, table.Audit)
+	s.Equal(BigIntColumn, table.Column("record_id").Type)
+	s.Equal(JSONBColumn, table.Column("old_row").Type)
+	s.Nil(schema.Table("posts").Audit)
+}
+
+func (s *PackageTransformerSuite) TestTransform_Notify() {
+	pkg, err := processFixture(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Post struct {
+		kallax.Model ` + "`table:\"posts\" notify:\"true\"`" + `
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Title string
+	}
+	`)
+	s.Require().NoError(err)
+
+	schema, err := s.t.transform(pkg)
+	s.Require().NoError(err)
+	s.Len(schema.Tables, 1)
+	s.Equal(&NotifySchema{Channel: "posts_changes", PrimaryKey: "id"}, schema.Table("posts").Notify)
+
+	_, err = SQLiteSchema(schema)
+	s.EqualError(err, "kallax: table posts notifies its changes with a trigger, which is not supported by SQLite")
+}
+
+func (s *PackageTransformerSuite) TestTransform_Versioned() {
+	pkg, err := processFixture(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Post struct {
+		kallax.Model ` + "`table:\"posts\" versioned:\"true\"`" + `
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Title string ` + "`unique:\"true\"`" + `
+		Author *Author ` + "`fk:\",inverse\"`" + `
+	}
+
+	type Author struct {
+		kallax.Model ` + "`table:\"authors\"`" + `
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+	}
+	`)
+	s.Require().NoError(err)
+
+	schema, err := s.t.transform(pkg)
+	s.Require().NoError(err)
+	s.Len(schema.Tables, 3)
+
+	table := schema.Table("posts_history")
+	s.Require().NotNil(table)
+	s.Equal(&HistorySchema{Table: "posts", PrimaryKey: "id"}, table.History)
+	s.Equal([]*ColumnSchema{
+		mkColIndex("id", BigIntColumn, false, true, "btree"),
+		mkCol("title", TextColumn, false, true, nil),
+		mkCol("author_id", BigIntColumn, false, false, nil),
+		mkCol("valid_from", TimestamptzColumn, false, true, nil),
+		mkCol("valid_to", TimestamptzColumn, false, false, nil),
+	}, table.Columns)
+}
+
+func (s *PackageTransformerSuite) TestTransform_JSONSchema() {
+	dir, err := ioutil.TempDir("", "kallax-jsonschema")
+	s.Require().NoError(err)
+	defer os.RemoveAll(dir)
+	s.Require().NoError(ioutil.WriteFile(filepath.Join(dir, "settings.json"), []byte(`{"type": "object"}`), 0644))
+
+	p, err := processorFixture(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Profile struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Settings map[string]interface{} ` + "`jsonschema:\"settings.json,check\"`" + `
+		Extra map[string]interface{} ` + "`jsonschema:\"settings.json\"`" + `
+	}
+	`)
+	s.Require().NoError(err)
+	p.Path = dir
+	pkg, err := p.processPackage()
+	s.Require().NoError(err)
+
+	schema, err := s.t.transform(pkg)
+	s.Require().NoError(err)
+
+	table := schema.Table("profile")
+	s.Require().NotNil(table)
+	s.Equal(`{"type":"object"}`, table.Column("settings").JSONSchema)
+	s.Equal("", table.Column("extra").JSONSchema)
+}
+
+func (s *PackageTransformerSuite) TestTransform_Enum() {
+	const user = `
+	type User struct {
+		kallax.Model ` + "`table:\"users\"`" + `
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Status string ` + "`enum:\"status_type,active,disabled,banned\"`" + `
+		Previous *string ` + "`enum:\"status_type,active,disabled,banned\"`" + `
+	}
+	`
+	pkg, err := processFixture(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+	` + user)
+	s.Require().NoError(err)
+
+	schema, err := s.t.transform(pkg)
+	s.Require().NoError(err)
+	s.Equal([]*EnumSchema{{Name: "status_type", Values: []string{"active", "disabled", "banned"}}}, schema.Enums)
+	s.Equal(ColumnType("status_type"), schema.Table("users").Column("status").Type)
+	s.True(schema.Table("users").Column("status").NotNull)
+	s.Equal(ColumnType("status_type"), schema.Table("users").Column("previous").Type)
+
+	pkg, err = processFixture(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+	` + user + `
+	type Post struct {
+		kallax.Model ` + "`table:\"posts\"`" + `
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Status string ` + "`enum:\"status_type,draft,published\"`" + `
+	}
+	`)
+	s.Require().NoError(err)
+
+	s.t = newPackageTransformer()
+	_, err = s.t.transform(pkg)
+	s.EqualError(err, "kallax: found more than one definition for enum type status_type")
+}
+
+func (s *PackageTransformerSuite) TestTransform_InvalidTSVector() {
+	cases := []string{
+		"Search string `tsvector:\"title\"`",
+		"Search kallax.TSVector `tsvector:\"foo\"`",
+		"Search kallax.TSVector `tsvector:\"search\"`",
+		"Search kallax.TSVector `tsvector:\"title:E\"`",
+		"Search kallax.TSVector `tsvector:\"title\" tsupdate:\"never\"`",
+	}
+
+	for _, c := range cases {
+		pkg, err := processFixture(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Post struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Title string
+		` + c + `
+	}
+	`)
+		s.Require().NoError(err, c)
+
+		_, err = s.t.transform(pkg)
+		s.Error(err, c)
+	}
+}
+
+func (s *PackageTransformerSuite) TestTransform_Deprecated() {
+	pkg, err := processFixture(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Post struct {
+		kallax.Model ` + "`table:\"posts\" versioned:\"true\" deprecated:\"\"`" + `
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Title string
+	}
+
+	type Author struct {
+		kallax.Model ` + "`table:\"authors\"`" + `
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Name string
+		Nick string ` + "`deprecated:\"use Name instead\"`" + `
+	}
+	`)
+	s.Require().NoError(err)
+
+	schema, err := s.t.transform(pkg)
+	s.Require().NoError(err)
+	s.True(schema.Table("posts").Deprecated)
+	s.True(schema.Table("posts_history").Deprecated)
+	s.False(schema.Table("authors").Deprecated)
+	s.False(schema.Table("authors").Column("name").Deprecated)
+	s.True(schema.Table("authors").Column("nick").Deprecated)
+}
+
+func (s *PackageTransformerSuite) TestTransform_CompositePrimaryKey() {
+	pkg, err := processFixture(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Order struct {
+		kallax.Model ` + "`table:\"orders\" pk:\"tenant_id,order_id\"`" + `
+		TenantID int64
+		OrderID kallax.ULID
+		Name string
+	}
+	`)
+	s.Require().NoError(err)
+
+	schema, err := s.t.transform(pkg)
+	s.Require().NoError(err)
+
+	expected := mkTable(
+		"orders",
+		mkCol("tenant_id", BigIntColumn, false, true, nil),
+		mkCol("order_id", UUIDColumn, false, true, nil),
+		mkCol("name", TextColumn, false, true, nil),
+	)
+	expected.PrimaryKey = []string{"tenant_id", "order_id"}
+	s.Equal(expected, schema.Table("orders"))
+}
+
+func (s *PackageTransformerSuite) TestTransform_Prefix() {
+	pkg, err := processFixture(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Address struct {
+		Street string
+		Zip    *string
+	}
+
+	type Customer struct {
+		kallax.Model ` + "`table:\"customers\"`" + `
+		ID      int64 ` + "`pk:\"autoincr\"`" + `
+		Billing Address ` + "`prefix:\"\"`" + `
+		Extra   Address
+	}
+	`)
+	s.Require().NoError(err)
+
+	schema, err := s.t.transform(pkg)
+	s.Require().NoError(err)
+
+	expected := mkTable(
+		"customers",
+		mkCol("id", SerialColumn, true, true, nil),
+		mkCol("billing_street", TextColumn, false, true, nil),
+		mkCol("billing_zip", TextColumn, false, false, nil),
+		mkCol("extra", JSONBColumn, false, true, nil),
+	)
+	s.Equal(expected, schema.Table("customers"))
+}
+
+func (s *PackageTransformerSuite) TestTransform_Indexes() {
+	process := func(index string) (*DBSchema, error) {
+		pkg, err := processFixture(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type User struct {
+		kallax.Model ` + "`table:\"users\" index:\"" + index + "\"`" + `
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Email string ` + "`unique:\"\"`" + `
+		TenantID int64 ` + "`index:\"\"`" + `
+		Tags []string ` + "`index:\"\"`" + `
+		Data map[string]interface{} ` + "`index:\"\"`" + `
+	}
+	`)
+		s.Require().NoError(err)
+		return s.t.transform(pkg)
+	}
+
+	schema, err := process("idx_email_tenant,email,tenant_id")
+	s.Require().NoError(err)
+
+	table := schema.Table("users")
+	s.True(table.Column("email").Unique)
+	s.Equal("btree", table.Column("tenant_id").Index)
+	s.Equal("gin", table.Column("tags").Index)
+	s.Equal("gin", table.Column("data").Index)
+	s.Equal([]*IndexSchema{{"idx_email_tenant", []string{"email", "tenant_id"}}}, table.Indexes)
+
+	s.SetupTest()
+	_, err = process("idx_email_name,email,name")
+	s.EqualError(err, "kallax: index idx_email_name of model User has the column name, which is not a column of the model")
+}
+
+func (s *PackageTransformerSuite) TestTransform_Checks() {
+	pkg, err := processFixture(`
+	package fixture
+
+	import (
+		"time"
+
+		"gopkg.in/src-d/go-kallax.v1"
+	)
+
+	type Product struct {
+		kallax.Model ` + "`table:\"products\" check:\"valid_period,starts_at < ends_at;valid_name,name <> ''\"`" + `
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Name string
+		Price int64 ` + "`check:\"price >= 0\"`" + `
+		StartsAt time.Time
+		EndsAt time.Time
+	}
+	`)
+	s.Require().NoError(err)
+	schema, err := s.t.transform(pkg)
+	s.Require().NoError(err)
+
+	table := schema.Table("products")
+	s.Equal("price >= 0", table.Column("price").Check)
+	s.Equal("", table.Column("name").Check)
+	s.Equal([]*CheckSchema{
+		{"valid_period", "starts_at < ends_at"},
+		{"valid_name", "name <> ''"},
+	}, table.Checks)
+}
+
+func (s *PackageTransformerSuite) TestTransform_Default() {
+	process := func(id string) (*DBSchema, error) {
+		pkg, err := processFixture(`
+	package fixture
+
+	import (
+		"time"
+
+		"gopkg.in/src-d/go-kallax.v1"
+	)
+
+	type Event struct {
+		kallax.Model ` + "`table:\"events\"`" + `
+		ID int64 ` + "`pk:\"autoincr\"" + id + "`" + `
+		Name string
+		Attendees int ` + "`default:\"0\"`" + `
+		CreatedAt time.Time ` + "`default:\"now()\"`" + `
+	}
+	`)
+		s.Require().NoError(err)
+		return s.t.transform(pkg)
+	}
+
+	schema, err := process("")
+	s.Require().NoError(err)
+
+	table := schema.Table("events")
+	s.Equal("", table.Column("name").Default)
+	s.Equal("0", table.Column("attendees").Default)
+	s.Equal("now()", table.Column("created_at").Default)
+
+	s.SetupTest()
+	_, err = process(` default:"1"`)
+	s.EqualError(err, "kallax: struct tag `default` can not be used in auto-incrementable nor generated fields. On field ID of model Event.")
+}
+
+func (s *PackageTransformerSuite) TestTransform_ForeignKeyConstraint() {
+	process := func(fk string) (*DBSchema, error) {
+		pkg, err := processFixture(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type User struct {
+		kallax.Model ` + "`table:\"users\"`" + `
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Posts []*Post ` + "`fk:\"" + fk + "\"`" + `
+	}
+
+	type Post struct {
+		kallax.Model ` + "`table:\"posts\"`" + `
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Owner *User ` + "`fk:\"owner_id,inverse\"`" + `
+	}
+	`)
+		s.Require().NoError(err)
+		return s.t.transform(pkg)
+	}
+
+	schema, err := process("owner_id,constraint,on_delete=cascade")
+	s.Require().NoError(err)
+	s.Equal(
+		&Reference{Table: "users", Column: "id", Constraint: true, OnDelete: "CASCADE", inverse: true},
+		schema.Table("posts").Column("owner_id").Reference,
+	)
+
+	s.SetupTest()
+	_, err = process("owner_id,on_delete=drop")
+	s.EqualError(err, "kallax: invalid foreign key action \"drop\", it must be cascade, restrict, set_null, set_default or no_action. On field Posts of model User.")
+}
+
+func (s *PackageTransformerSuite) TestTransform_OldName() {
+	pkg, err := processFixture(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type User struct {
+		kallax.Model ` + "`table:\"users\"`" + `
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		FullName string ` + "`oldname:\"name\"`" + `
+	}
+	`)
+	s.Require().NoError(err)
+
+	schema, err := s.t.transform(pkg)
+	s.Require().NoError(err)
+	s.Equal("name", schema.Table("users").Column("full_name").OldName)
+
+	old := mkSchema(mkTable(
+		"users",
+		mkCol("id", SerialColumn, true, true, nil),
+		mkCol("name", TextColumn, false, true, nil),
+	))
+	s.Equal(
+		ChangeSet{&RenameColumn{Table: "users", Column: old.Tables[0].Columns[1], Name: "full_name"}},
+		SchemaDiff(old, schema),
+	)
+}
+
+func (s *PackageTransformerSuite) TestTransform_ManyToMany() {
+	process := func(posts string) (*DBSchema, error) {
+		pkg, err := processFixture(`
+		package fixture
+
+		import "gopkg.in/src-d/go-kallax.v1"
+
+		type Post struct {
+			kallax.Model ` + "`table:\"posts\"`" + `
+			ID int64 ` + "`pk:\"autoincr\"`" + `
+			Tags []*Tag ` + "`through:\"post_tags\"`" + `
+		}
+
+		type Tag struct {
+			kallax.Model ` + "`table:\"tags\"`" + `
+			ID kallax.ULID ` + "`pk:\"\"`" + `
+			Posts []*Post ` + posts + `
+		}
+		`)
+		s.Require().NoError(err)
+		return newPackageTransformer().transform(pkg)
+	}
+
+	schema, err := process("`through:\"post_tags\"`")
+	s.Require().NoError(err)
+	s.Len(schema.Tables, 3)
+	s.Equal(mkTable("tags", mkCol("id", UUIDColumn, true, true, nil)), schema.Table("tags"))
+
+	expected := mkTable(
+		"post_tags",
+		mkCol("post_id", BigIntColumn, false, true, mkRef("posts", "id", true)),
+		mkCol("tag_id", UUIDColumn, false, true, mkRef("tags", "id", true)),
+	)
+	expected.PrimaryKey = []string{"post_id", "tag_id"}
+	s.Equal(expected, schema.Table("post_tags"))
+
+	_, err = process("`through:\"post_tags\" fk:\"tag_id,post\"`")
+	s.Error(err)
+
+	schema, err = process("`through:\"tags\" fk:\"tag_id,post_id\"`")
+	s.Require().NoError(err)
+	s.Len(schema.Tables, 3)
+	s.Equal(mkTable("tags", mkCol("id", UUIDColumn, true, true, nil)), schema.Table("tags"))
+}
+
 func (s *PackageTransformerSuite) TestTransform_RepeatedTable() {
 	m := *s.pkg.Models[len(s.pkg.Models)-1]
 	m.Fields = nil
@@ -740,21 +2499,97 @@ func TestGraphResolve(t *testing.T) {
 }
 
 func mkSchema(tables ...*TableSchema) *DBSchema {
-	return &DBSchema{tables}
+	return &DBSchema{Tables: tables}
+}
+
+func mkType(name string, attrs ...*ColumnSchema) *TypeSchema {
+	return &TypeSchema{name, attrs}
+}
+
+func mkAttr(name string, typ ColumnType) *ColumnSchema {
+	return &ColumnSchema{Name: name, Type: typ}
 }
 
 func mkTable(name string, columns ...*ColumnSchema) *TableSchema {
-	return &TableSchema{name, columns}
+	return &TableSchema{Name: name, Columns: columns}
 }
 
 func mkCol(name string, typ ColumnType, pk, notNull bool, ref *Reference) *ColumnSchema {
-	return &ColumnSchema{name, typ, pk, ref, notNull, false}
+	return &ColumnSchema{name, typ, pk, ref, notNull, false, "", nil, "", "", "", false, ""}
 }
 
 func mkColUnique(name string, typ ColumnType, pk, notNull bool, ref *Reference) *ColumnSchema {
-	return &ColumnSchema{name, typ, pk, ref, notNull, true}
+	return &ColumnSchema{name, typ, pk, ref, notNull, true, "", nil, "", "", "", false, ""}
+}
+
+func mkColIndex(name string, typ ColumnType, pk, notNull bool, index string) *ColumnSchema {
+	return &ColumnSchema{name, typ, pk, nil, notNull, false, index, nil, "", "", "", false, ""}
 }
 
 func mkRef(table, col string, inverse bool) *Reference {
-	return &Reference{table, col, inverse}
+	return &Reference{Table: table, Column: col, inverse: inverse}
+}
+
+func TestDBSchemaSQL(t *testing.T) {
+	users := mkTable(
+		"users",
+		mkCol("id", SerialColumn, true, true, nil),
+		mkColIndex("email", TextColumn, false, true, "unique"),
+		mkCol("status", "status_type", false, true, nil),
+		mkCol("address", "address", false, false, nil),
+	)
+	users.Notify = &NotifySchema{Channel: "users", PrimaryKey: "id"}
+	posts := mkTable(
+		"posts",
+		mkCol("id", SerialColumn, true, true, nil),
+		mkCol("user_id", BigIntColumn, false, true, &Reference{Table: "users", Column: "id", Constraint: true}),
+	)
+	posts.Deprecated = true
+	schema := mkSchema(posts, users)
+	schema.Types = []*TypeSchema{mkType("address", mkAttr("street", TextColumn), mkAttr("amount", MoneyColumn))}
+	schema.Enums = []*EnumSchema{{Name: "status_type", Values: []string{"active", "banned"}}}
+
+	sql, err := schema.SQL()
+	require.NoError(t, err)
+	require.Equal(t, `BEGIN;
+
+DO $$ BEGIN CREATE TYPE status_type AS ENUM ('active', 'banned'); EXCEPTION WHEN duplicate_object THEN null; END $$;
+
+
+DO $$ BEGIN CREATE TYPE kallax_money AS (amount numeric, currency char(3)); EXCEPTION WHEN duplicate_object THEN null; END $$;
+DO $$ BEGIN CREATE TYPE address AS (
+	street text,
+	amount kallax_money
+); EXCEPTION WHEN duplicate_object THEN null; END $$;
+
+
+CREATE TABLE IF NOT EXISTS users (
+	id serial NOT NULL PRIMARY KEY,
+	email text NOT NULL,
+	status status_type NOT NULL,
+	address address
+);
+CREATE UNIQUE INDEX IF NOT EXISTS users__email__unique ON users (email);
+CREATE OR REPLACE FUNCTION users__notify() RETURNS trigger AS $$
+BEGIN
+	IF TG_OP = 'DELETE' THEN
+		PERFORM pg_notify('users', json_build_object('operation', TG_OP, 'id', OLD.id::text)::text);
+		RETURN OLD;
+	END IF;
+	PERFORM pg_notify('users', json_build_object('operation', TG_OP, 'id', NEW.id::text)::text);
+	RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+DO $$ BEGIN CREATE TRIGGER users__notify AFTER INSERT OR UPDATE OR DELETE ON users FOR EACH ROW EXECUTE PROCEDURE users__notify(); EXCEPTION WHEN duplicate_object THEN null; END $$;
+
+
+CREATE TABLE IF NOT EXISTS posts (
+	id serial NOT NULL PRIMARY KEY,
+	user_id bigint NOT NULL
+);
+DO $$ BEGIN ALTER TABLE posts ADD CONSTRAINT posts_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id); EXCEPTION WHEN duplicate_object THEN null; END $$;
+
+
+COMMIT;
+`, sql)
 }
@@ -0,0 +1,151 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// mysqlTypes are the MySQL types of the columns, which store the values in
+// the same representation the MySQL driver of database/sql expects.
+var mysqlTypes = map[ColumnType]string{
+	ByteaColumn:       "LONGBLOB",
+	SmallIntColumn:    "SMALLINT",
+	IntegerColumn:     "INT",
+	BigIntColumn:      "BIGINT",
+	SmallSerialColumn: "SMALLINT",
+	SerialColumn:      "BIGINT",
+	BigSerialColumn:   "BIGINT",
+	RealColumn:        "FLOAT",
+	DoubleColumn:      "DOUBLE",
+	TimestamptzColumn: "DATETIME(6)",
+	TimestampColumn:   "DATETIME(6)",
+	BooleanColumn:     "BOOLEAN",
+	TextColumn:        "TEXT",
+	CITextColumn:      "TEXT",
+	JSONBColumn:       "JSON",
+	UUIDColumn:        "CHAR(36)",
+	InetColumn:        "VARCHAR(45)",
+	CIDRColumn:        "VARCHAR(49)",
+	MACAddrColumn:     "VARCHAR(17)",
+}
+
+// mysqlKeyTextType is the type of the text columns that are part of a key,
+// as MySQL can not index TEXT columns without a prefix length.
+const mysqlKeyTextType = "VARCHAR(255)"
+
+// mysqlType returns the MySQL type of the given column, if it can be stored
+// in MySQL. Arrays are stored as their PostgreSQL text literal.
+func mysqlType(c *ColumnSchema) (string, bool) {
+	if (c.Type == TextColumn || c.Type == CITextColumn) && (c.PrimaryKey || c.Unique || c.Index != "" || c.Reference != nil) {
+		return mysqlKeyTextType, true
+	}
+
+	if t, ok := mysqlTypes[c.Type]; ok {
+		return t, true
+	}
+
+	s := string(c.Type)
+	switch {
+	case strings.HasSuffix(s, "[]"):
+		return "TEXT", true
+	case strings.HasPrefix(s, "numeric("), strings.HasPrefix(s, "decimal("):
+		return strings.ToUpper(s), true
+	}
+	return "", false
+}
+
+// MySQLSchema returns the statements that create the tables of the given
+// schema in MySQL or MariaDB, so the stores can be run against them with the
+// kallax.MySQL dialect. Auto-incrementable primary keys are declared as
+// BIGINT AUTO_INCREMENT columns, so their fields must be int64, and the text
+// columns that are part of a key are declared as VARCHAR(255), as MySQL can
+// not index TEXT columns. Foreign keys are declared as table constraints,
+// because MySQL ignores the inline references of the columns. Only unique,
+// btree and hash indexes are created, and check constraints and default values
+// are skipped, as their expressions are written for PostgreSQL. An error is returned if any column has
+// a type that cannot be stored in MySQL, such as composite and enum types, ranges and
+// geometries, or is a tsvector, or if any model is audited, versioned or
+// notifies its changes.
+func MySQLSchema(schema *DBSchema) (string, error) {
+	migration, err := NewMigration(new(DBSchema), schema)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	for _, change := range migration.Up {
+		if c, ok := change.(*CreateTable); ok {
+			if err := writeMySQLTable(&buf, c.TableSchema); err != nil {
+				return "", err
+			}
+		}
+	}
+	return buf.String(), nil
+}
+
+func writeMySQLTable(buf *bytes.Buffer, table *TableSchema) error {
+	if tracked := table.trackedTable(); tracked != "" {
+		return fmt.Errorf("kallax: table %s keeps the changes of table %s with a trigger, which is not supported by MySQL", table.Name, tracked)
+	}
+
+	if table.Notify != nil {
+		return fmt.Errorf("kallax: table %s notifies its changes with a trigger, which is not supported by MySQL", table.Name)
+	}
+
+	var defs, indexes []string
+	for _, c := range table.Columns {
+		typ, ok := mysqlType(c)
+		if !ok || c.TSVector != nil {
+			return fmt.Errorf("kallax: column %s of table %s has type %s, which is not supported by MySQL", c.Name, table.Name, c.Type)
+		}
+
+		def := fmt.Sprintf("\t%s %s", c.Name, typ)
+		if c.NotNull || c.PrimaryKey {
+			def += " NOT NULL"
+		}
+
+		if c.PrimaryKey && isSerial(c.Type) {
+			def += " AUTO_INCREMENT"
+		}
+
+		if c.Unique {
+			def += " UNIQUE"
+		}
+
+		if c.PrimaryKey {
+			def += " PRIMARY KEY"
+		}
+		defs = append(defs, def)
+
+		switch c.Index {
+		case "unique":
+			indexes = append(indexes, fmt.Sprintf("CREATE UNIQUE INDEX %s ON %s (%s);\n", indexName(table.Name, c.Name, c.Index), table.Name, c.Name))
+		case "btree", "hash":
+			indexes = append(indexes, fmt.Sprintf("CREATE INDEX %s ON %s (%s) USING %s;\n", indexName(table.Name, c.Name, c.Index), table.Name, c.Name, strings.ToUpper(c.Index)))
+		}
+	}
+
+	for _, idx := range table.Indexes {
+		indexes = append(indexes, fmt.Sprintf("CREATE INDEX %s ON %s (%s);\n", idx.Name, table.Name, strings.Join(idx.Columns, ", ")))
+	}
+
+	if len(table.PrimaryKey) > 0 {
+		defs = append(defs, primaryKeyConstraint(table.PrimaryKey))
+	}
+
+	for _, c := range table.Columns {
+		if c.Reference != nil {
+			defs = append(defs, fmt.Sprintf("\tFOREIGN KEY (%s) REFERENCES %s", c.Name, c.Reference))
+		}
+	}
+
+	buf.WriteString(fmt.Sprintf("CREATE TABLE %s (\n", table.Name))
+	buf.WriteString(strings.Join(defs, ",\n"))
+	buf.WriteString("\n);\n")
+	for _, idx := range indexes {
+		buf.WriteString(idx)
+	}
+	buf.WriteRune('\n')
+	return nil
+}
@@ -0,0 +1,68 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMySQLSchema(t *testing.T) {
+	schema := mkSchema(
+		mkTable(
+			"posts",
+			mkCol("id", SerialColumn, true, true, nil),
+			mkCol("author_id", UUIDColumn, false, true, mkRef("users", "id", false)),
+			mkColUnique("slug", TextColumn, false, true, nil),
+			mkCol("body", TextColumn, false, false, nil),
+			mkCol("tags", ArrayColumn(TextColumn), false, false, nil),
+			mkColIndex("doc", JSONBColumn, false, false, "gin"),
+			mkColIndex("published_at", TimestamptzColumn, false, true, "btree"),
+		),
+		mkTable(
+			"users",
+			mkCol("id", UUIDColumn, true, true, nil),
+			mkCol("name", CITextColumn, false, true, nil),
+			mkCol("balance", DecimalColumn(10, 2), false, true, nil),
+		),
+	)
+	schema.Tables[0].Indexes = []*IndexSchema{{Name: "idx_author_slug", Columns: []string{"author_id", "slug"}}}
+
+	sql, err := MySQLSchema(schema)
+	require.NoError(t, err)
+	require.Equal(t, `CREATE TABLE users (
+	id CHAR(36) NOT NULL PRIMARY KEY,
+	name TEXT NOT NULL,
+	balance DECIMAL(10, 2) NOT NULL
+);
+
+CREATE TABLE posts (
+	id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+	author_id CHAR(36) NOT NULL,
+	slug VARCHAR(255) NOT NULL UNIQUE,
+	body TEXT,
+	tags TEXT,
+	doc JSON,
+	published_at DATETIME(6) NOT NULL,
+	FOREIGN KEY (author_id) REFERENCES users(id)
+);
+CREATE INDEX posts__published_at__btree ON posts (published_at) USING BTREE;
+CREATE INDEX idx_author_slug ON posts (author_id, slug);
+
+`, sql)
+}
+
+func TestMySQLSchema_Unsupported(t *testing.T) {
+	_, err := MySQLSchema(mkSchema(mkTable(
+		"places",
+		mkCol("id", SerialColumn, true, true, nil),
+		mkCol("location", GeometryColumn("Point", 4326), false, false, nil),
+	)))
+	require.EqualError(t, err, "kallax: column location of table places has type geometry(Point,4326), which is not supported by MySQL")
+
+	_, err = MySQLSchema(mkSchema(mkTable(
+		"ranges",
+		mkCol("id", SerialColumn, true, true, nil),
+		mkCol("period", TstzRangeColumn, false, false, nil),
+	)))
+	require.Error(t, err)
+}
@@ -0,0 +1,24 @@
+//go:build go1.18
+// +build go1.18
+
+package generator
+
+import "go/types"
+
+const nullType = "gopkg.in/src-d/go-kallax.v1.Null"
+
+// nullElem returns the type T of the given kallax.Null[T] type, or nil if the
+// type is not a kallax.Null.
+func nullElem(typ types.Type) types.Type {
+	named, ok := typ.(*types.Named)
+	if !ok || named.TypeArgs().Len() != 1 {
+		return nil
+	}
+
+	obj := named.Obj()
+	if obj.Pkg() == nil || removeGoPath(obj.Pkg().Path())+"."+obj.Name() != nullType {
+		return nil
+	}
+
+	return named.TypeArgs().At(0)
+}
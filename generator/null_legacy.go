@@ -0,0 +1,12 @@
+//go:build !go1.18
+// +build !go1.18
+
+package generator
+
+import "go/types"
+
+// nullElem always returns nil, since kallax.Null requires generics, which are
+// not available before Go 1.18.
+func nullElem(typ types.Type) types.Type {
+	return nil
+}
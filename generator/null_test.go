@@ -0,0 +1,89 @@
+//go:build go1.18
+// +build go1.18
+
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const nullFixture = `
+package foo
+
+import (
+	"gopkg.in/src-d/go-kallax.v1"
+	"time"
+)
+
+type Status string
+
+type User struct {
+	kallax.Model ` + "`table:\"users\"`" + `
+	ID kallax.ULID ` + "`pk:\"\"`" + `
+	Nickname kallax.Null[string]
+	Age kallax.Null[int64]
+	Status kallax.Null[Status]
+	LastLogin kallax.Null[time.Time] ` + "`timezone:\"false\"`" + `
+	Manager kallax.Null[kallax.ULID]
+}
+`
+
+func TestNullElem(t *testing.T) {
+	require := require.New(t)
+	pkg, err := processFixture(nullFixture)
+	require.NoError(err)
+
+	m := findModel(pkg, "User")
+	require.False(m.Fields[1].IsNull())
+	for _, f := range m.Fields[2:] {
+		require.True(f.IsNull(), f.Name)
+		require.Equal(Interface, f.Kind, f.Name)
+	}
+
+	require.Equal("string", nullElem(findField(m, "Nickname").Node.Type()).String())
+}
+
+func TestTransform_Null(t *testing.T) {
+	require := require.New(t)
+	pkg, err := processFixture(nullFixture)
+	require.NoError(err)
+
+	schema, err := newPackageTransformer().transform(pkg)
+	require.NoError(err)
+
+	expected := mkSchema(mkTable(
+		"users",
+		mkCol("id", UUIDColumn, true, true, nil),
+		mkCol("nickname", TextColumn, false, false, nil),
+		mkCol("age", BigIntColumn, false, false, nil),
+		mkCol("status", TextColumn, false, false, nil),
+		mkCol("last_login", TimestampColumn, false, false, nil),
+		mkCol("manager", UUIDColumn, false, false, nil),
+	))
+	require.Equal(expected, schema)
+}
+
+func TestGenFindBy_Null(t *testing.T) {
+	require := require.New(t)
+	pkg, err := processFixture(nullFixture)
+	require.NoError(err)
+
+	td := &TemplateData{pkg, make(map[interface{}]string), make(map[string]*Field)}
+	findBys := td.GenFindBy(findModel(pkg, "User"))
+	expected := []string{
+		"func (q *UserQuery) FindByNickname(v string) *UserQuery",
+		"func (q *UserQuery) FindByNicknameIsNull() *UserQuery",
+		"func (q *UserQuery) FindByAge(cond kallax.ScalarCond, v int64) *UserQuery",
+		"func (q *UserQuery) FindByStatus(v Status) *UserQuery",
+		"func (q *UserQuery) FindByLastLogin(cond kallax.ScalarCond, v time.Time) *UserQuery",
+		"func (q *UserQuery) FindByManager(v kallax.ULID) *UserQuery",
+		"return q.Where(kallax.IsNull(Schema.User.Manager))",
+	}
+
+	for _, e := range expected {
+		require.True(strings.Contains(findBys, e), e)
+	}
+}
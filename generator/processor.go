@@ -1,22 +1,28 @@
 package generator
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"go/ast"
 	"go/build"
 	"go/parser"
 	"go/token"
 	"go/types"
+	"io/ioutil"
 	"path/filepath"
 	"reflect"
 	"strings"
 
+	ktypes "gopkg.in/src-d/go-kallax.v1/types"
 	parseutil "gopkg.in/src-d/go-parse-utils.v1"
 )
 
 const (
 	// BaseModel is the type name of the kallax base model.
 	BaseModel = "gopkg.in/src-d/go-kallax.v1.Model"
+	// BaseComposite is the type name of the kallax composite type marker.
+	BaseComposite = "gopkg.in/src-d/go-kallax.v1.Composite"
 	//URL is the type name of the net/url.URL.
 	URL = "url.URL"
 )
@@ -149,6 +155,10 @@ func (p *Processor) processPackage() (*Package, error) {
 						return nil, err
 					}
 
+					if err := p.loadJSONSchemas(m.Fields); err != nil {
+						return nil, err
+					}
+
 					models = append(models, m)
 					m.Node = t
 					m.Package = p.Package
@@ -168,6 +178,45 @@ func (p *Processor) processPackage() (*Package, error) {
 	return pkg, nil
 }
 
+// loadJSONSchemas reads the JSON Schema documents of the given fields with
+// the struct tag `jsonschema` and checks they are valid.
+func (p *Processor) loadJSONSchemas(fields []*Field) error {
+	for _, f := range fields {
+		if f.Inline() {
+			if err := p.loadJSONSchemas(f.Fields); err != nil {
+				return err
+			}
+			continue
+		}
+
+		file, _ := f.JSONSchemaFile()
+		if file == "" {
+			continue
+		}
+
+		if !f.IsJSON {
+			return fmt.Errorf("kallax: struct tag `jsonschema` can only be used in fields stored as JSON. On field %s of model %s.", f.Name, f.Model.Name)
+		}
+
+		doc, err := ioutil.ReadFile(filepath.Join(p.Path, file))
+		if err != nil {
+			return fmt.Errorf("kallax: cannot read JSON schema of field %s of model %s: %s", f.Name, f.Model.Name, err)
+		}
+
+		if _, err := ktypes.CompileJSONSchema(doc); err != nil {
+			return fmt.Errorf("%s. On field %s of model %s.", err, f.Name, f.Model.Name)
+		}
+
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, doc); err != nil {
+			return err
+		}
+		f.JSONSchema = buf.String()
+	}
+
+	return nil
+}
+
 func (p *Processor) tryMatchConstructor(pkg *Package, fun *types.Func) {
 	if !strings.HasPrefix(fun.Name(), "new") {
 		return
@@ -206,6 +255,10 @@ func (p *Processor) processModel(name string, s *types.Struct, t *types.Named) (
 		return nil, err
 	}
 
+	if m.HasValidations() && getMethodSignature(p.Package, types.NewPointer(t), "Validate") != nil {
+		return nil, fmt.Errorf("kallax: model %s has fields with the struct tags `validate` or `enum`, so its Validate method is generated and can not be defined", m.Name)
+	}
+
 	return m, nil
 }
 
@@ -247,6 +300,10 @@ func (p *Processor) processFields(s *types.Struct, done []*types.Struct, root bo
 			continue
 		}
 
+		if f.Anonymous() && typeName(f.Type()) == BaseComposite {
+			continue
+		}
+
 		field := NewField(
 			f.Name(),
 			typeName(f.Type().Underlying()),
@@ -282,9 +339,11 @@ func (p *Processor) processFields(s *types.Struct, done []*types.Struct, root bo
 // just treated as structs.
 // The following types are always set as JSON:
 //  - Map
-//  - Slice or Array with non-basic underlying type
+//  - Slice or Array with non-basic underlying type, unless it implements
+//    sql.Scanner and driver.Valuer
 //  - Interface
-//  - Struct that is not a model or is not at root level
+//  - Struct that is not a model or is not at root level, unless it is a
+//    composite type
 func (p *Processor) processField(field *Field, typ types.Type, done []*types.Struct, root bool) {
 	switch typ := typ.(type) {
 	case *types.Basic:
@@ -318,7 +377,7 @@ func (p *Processor) processField(field *Field, typ types.Type, done []*types.Str
 		}
 
 		p.processField(field, typ.Underlying(), done, root)
-		field.IsAlias = !field.IsJSON
+		field.IsAlias = !field.IsJSON && !field.IsComposite
 	case *types.Array:
 		var underlying Field
 		p.processField(&underlying, typ.Elem(), done, root)
@@ -327,7 +386,7 @@ func (p *Processor) processField(field *Field, typ types.Type, done []*types.Str
 			return
 		}
 
-		if underlying.Kind != Basic {
+		if underlying.Kind != Basic && !isSQLTypeElem(&underlying) {
 			field.IsJSON = true
 		}
 		field.Kind = Array
@@ -340,7 +399,7 @@ func (p *Processor) processField(field *Field, typ types.Type, done []*types.Str
 			return
 		}
 
-		if underlying.Kind != Basic {
+		if underlying.Kind != Basic && !isSQLTypeElem(&underlying) && !isMultiDimElem(&underlying, typ.Elem()) {
 			field.IsJSON = true
 		}
 		field.Kind = Slice
@@ -353,7 +412,11 @@ func (p *Processor) processField(field *Field, typ types.Type, done []*types.Str
 		field.IsJSON = true
 	case *types.Struct:
 		field.Kind = Struct
-		field.IsJSON = true
+		if _, ok := compositeTag(typ); ok {
+			field.IsComposite = true
+		} else {
+			field.IsJSON = true
+		}
 
 		d := false
 		for _, v := range done {
@@ -372,6 +435,42 @@ func (p *Processor) processField(field *Field, typ types.Type, done []*types.Str
 	}
 }
 
+// compositeTag returns the struct tag of the kallax.Composite embedded in the
+// given struct, and reports whether the struct is a composite type.
+func compositeTag(s *types.Struct) (reflect.StructTag, bool) {
+	for i := 0; i < s.NumFields(); i++ {
+		f := s.Field(i)
+		if f.Anonymous() && typeName(f.Type()) == BaseComposite {
+			return reflect.StructTag(s.Tag(i)), true
+		}
+	}
+	return "", false
+}
+
+// isSQLTypeElem reports whether the given element of a slice or array is a
+// non-pointer type implementing sql.Scanner and driver.Valuer, in which case
+// the slice can be stored as a Postgres array.
+func isSQLTypeElem(elem *Field) bool {
+	return elem.Kind == Interface && !elem.IsJSON && !elem.IsPtr
+}
+
+// isMultiDimElem reports whether the given element of a slice is a slice
+// that can be stored as another dimension of a Postgres array. Slices of
+// bytes are stored as bytea, so they can not be another dimension.
+func isMultiDimElem(elem *Field, typ types.Type) bool {
+	if elem.Kind != Slice || elem.IsJSON || elem.IsPtr {
+		return false
+	}
+
+	slice, ok := typ.Underlying().(*types.Slice)
+	if !ok {
+		return false
+	}
+
+	basic, ok := slice.Elem().Underlying().(*types.Basic)
+	return !ok || basic.Kind() != types.Byte
+}
+
 func isSQLType(pkg *types.Package, typ types.Type) bool {
 	scan := getMethodSignature(pkg, typ, "Scan")
 	if !signatureMatches(scan, typeCheckers{isEmptyInterface}, typeCheckers{isBuiltinError}) {
@@ -463,6 +562,21 @@ func (p *Processor) processBaseField(m *Model, f *Field) {
 	if m.Table == "" {
 		m.Table = toLowerSnakeCase(m.Name)
 	}
+	if tag, ok := f.Tag.Lookup("audit"); ok {
+		m.Audit = tag == "" || tag == "true"
+	}
+	m.Versioned = f.Tag.Get("versioned") == "true"
+	m.Notify = f.Tag.Get("notify") == "true"
+	if tag, ok := f.Tag.Lookup("index"); ok {
+		m.Indexes = parseModelIndexes(tag)
+	}
+	if tag, ok := f.Tag.Lookup("check"); ok {
+		m.Checks = parseModelChecks(tag)
+	}
+	m.DeprecationNotice, m.Deprecated = f.Tag.Lookup("deprecated")
+	if m.Deprecated && m.DeprecationNotice == "" {
+		m.DeprecationNotice = fmt.Sprintf("the model %s will be removed.", m.Name)
+	}
 }
 
 func joinDirectory(directory string, files []string) []string {
@@ -2,6 +2,9 @@ package generator
 
 import (
 	"go/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -291,6 +294,56 @@ func (s *ProcessorSuite) TestSQLTypeIsInterface() {
 	s.Equal(Interface, field.Kind)
 }
 
+func (s *ProcessorSuite) TestComposite() {
+	fixtureSrc := `
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Foo struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Bar Bar
+		Baz *Baz
+		Qux Qux
+	}
+
+	type Bar struct {
+		kallax.Composite
+		A string
+		B int
+	}
+
+	type Baz struct {
+		kallax.Composite ` + "`type:\"custom_baz\"`" + `
+		C string
+	}
+
+	type Qux struct {
+		D string
+	}
+	`
+
+	pkg := s.processFixture(fixtureSrc)
+	m := findModel(pkg, "Foo")
+
+	bar := findField(m, "Bar")
+	s.True(bar.IsComposite)
+	s.False(bar.IsJSON)
+	s.Equal("bar", bar.CompositeType())
+	s.Len(bar.Fields, 2)
+
+	baz := findField(m, "Baz")
+	s.True(baz.IsComposite)
+	s.Equal("custom_baz", baz.CompositeType())
+	s.Len(baz.Fields, 1)
+
+	qux := findField(m, "Qux")
+	s.False(qux.IsComposite)
+	s.True(qux.IsJSON)
+	s.Equal("", qux.CompositeType())
+}
+
 func (s *ProcessorSuite) TestIsSQLType() {
 	fixtureSrc := `
 	package fixture
@@ -421,6 +474,244 @@ func (s *ProcessorSuite) TestIsEmbedded() {
 	s.Equal(expected, names)
 }
 
+func (s *ProcessorSuite) TestJSONSchema() {
+	dir, err := ioutil.TempDir("", "kallax-jsonschema")
+	s.Require().NoError(err)
+	defer os.RemoveAll(dir)
+
+	s.Require().NoError(ioutil.WriteFile(filepath.Join(dir, "settings.json"), []byte(`{
+		"type": "object",
+		"required": ["theme"]
+	}`), 0644))
+	s.Require().NoError(ioutil.WriteFile(filepath.Join(dir, "invalid.json"), []byte(`{"type": "foo"}`), 0644))
+
+	process := func(typ, tag string) (*Package, error) {
+		src := `
+		package fixture
+
+		import "gopkg.in/src-d/go-kallax.v1"
+
+		type Foo struct {
+			kallax.Model
+			ID int64 ` + "`pk:\"autoincr\"`" + `
+			Settings ` + typ + ` ` + "`" + tag + "`" + `
+			Name string
+		}
+		`
+		p, err := processorFixture(src)
+		if err != nil {
+			return nil, err
+		}
+		p.Path = dir
+		return p.processPackage()
+	}
+
+	pkg, err := process("map[string]interface{}", `jsonschema:"settings.json,check"`)
+	s.Require().NoError(err)
+	f := findField(findModel(pkg, "Foo"), "Settings")
+	s.Equal(`{"type":"object","required":["theme"]}`, f.JSONSchema)
+	path, check := f.JSONSchemaFile()
+	s.Equal("settings.json", path)
+	s.True(check)
+	s.Len(findModel(pkg, "Foo").JSONSchemaFields(), 1)
+
+	_, err = process("map[string]interface{}", `jsonschema:"missing.json"`)
+	s.Error(err)
+
+	_, err = process("map[string]interface{}", `jsonschema:"invalid.json"`)
+	s.Error(err)
+
+	_, err = process("string", `jsonschema:"settings.json"`)
+	s.Error(err)
+}
+
+func (s *ProcessorSuite) TestAuditTag() {
+	cases := []struct {
+		tag   string
+		audit bool
+	}{
+		{`audit:""`, true},
+		{`audit:"true"`, true},
+		{`audit:"false"`, false},
+		{`table:"foos"`, false},
+	}
+
+	for _, c := range cases {
+		pkg, err := processFixture(`
+		package fixture
+
+		import "gopkg.in/src-d/go-kallax.v1"
+
+		type Foo struct {
+			kallax.Model ` + "`" + c.tag + "`" + `
+			ID int64 ` + "`pk:\"autoincr\"`" + `
+		}
+		`)
+		s.Require().NoError(err, c.tag)
+		s.Equal(c.audit, findModel(pkg, "Foo").Audit, c.tag)
+	}
+}
+
+func (s *ProcessorSuite) TestCompositeKey() {
+	process := func(tag, extra string) (*Package, error) {
+		src := `
+		package fixture
+
+		import "gopkg.in/src-d/go-kallax.v1"
+
+		type Order struct {
+			kallax.Model ` + "`" + tag + "`" + `
+			Name string
+			OrderID int64
+			TenantID int64
+		}
+		` + extra
+		return processFixture(src)
+	}
+
+	pkg, err := process(`pk:"tenant_id,order_id"`, "")
+	s.Require().NoError(err)
+	m := findModel(pkg, "Order")
+	s.True(m.HasCompositeKey())
+	s.Equal("TenantID", m.ID.Name)
+	s.Equal([]*Field{m.Fields[0], m.Fields[1]}, m.PrimaryKey)
+	s.Equal("OrderID", m.Fields[1].Name)
+	s.True(m.Fields[1].IsPrimaryKey())
+	s.False(findField(m, "Name").IsPrimaryKey())
+
+	pkg, err = process(`pk:"order_id"`, "")
+	s.Require().NoError(err)
+	s.False(findModel(pkg, "Order").HasCompositeKey())
+
+	_, err = process(`pk:"tenant_id,order_id,autoincr"`, "")
+	s.EqualError(err, "kallax: composite primary key defined in Model can not be auto-incrementable")
+
+	_, err = process(`pk:"tenant_id,foo"`, "")
+	s.Error(err)
+
+	_, err = process(`pk:"tenant_id,order_id" audit:"true"`, "")
+	s.EqualError(err, "kallax: model Order has a composite primary key, so it can not be audited nor versioned")
+
+	_, err = process(`pk:"tenant_id,order_id"`, `
+		type Item struct {
+			kallax.Model
+			ID int64 `+"`pk:\"autoincr\"`"+`
+			Order *Order `+"`fk:\",inverse\"`"+`
+		}
+	`)
+	s.EqualError(err, "kallax: relationship Order of model Item references model Order, which has a composite primary key, but foreign keys can only reference single-column primary keys")
+}
+
+func (s *ProcessorSuite) TestManyToMany() {
+	process := func(tags, posts string) (*Package, error) {
+		src := `
+		package fixture
+
+		import "gopkg.in/src-d/go-kallax.v1"
+
+		type Post struct {
+			kallax.Model
+			ID int64 ` + "`pk:\"autoincr\"`" + `
+			Tags ` + tags + `
+		}
+
+		type Tag struct {
+			kallax.Model
+			ID int64 ` + "`pk:\"autoincr\"`" + `
+			Posts ` + posts + `
+		}
+		`
+		return processFixture(src)
+	}
+
+	pkg, err := process("[]*Tag `through:\"post_tags\"`", "[]Post `through:\"post_tags\"`")
+	s.Require().NoError(err)
+	post, tag := findModel(pkg, "Post"), findModel(pkg, "Tag")
+	tags := findField(post, "Tags")
+	s.True(tags.IsManyToManyRelationship())
+	s.False(tags.IsOneToManyRelationship())
+	s.Equal("post_tags", tags.Through())
+	s.Equal("post_id", tags.ForeignKey())
+	s.Equal("tag_id", tags.JoinTableReferences())
+	s.Equal([]*Field{tags}, post.ManyToManyRelationships())
+	s.Len(post.NonInverses(), 0)
+	s.Len(tag.ImplicitFKs, 0)
+	s.Equal("tag_id", findField(tag, "Posts").ForeignKey())
+
+	pkg, err = process("[]*Tag `through:\"post_tags\" fk:\"post,tag\"`", "[]*Post")
+	s.Require().NoError(err)
+	tags = findField(findModel(pkg, "Post"), "Tags")
+	s.Equal("post", tags.ForeignKey())
+	s.Equal("tag", tags.JoinTableReferences())
+	s.True(findField(findModel(pkg, "Tag"), "Posts").IsOneToManyRelationship())
+
+	_, err = process("*Tag `through:\"post_tags\"`", "int64")
+	s.EqualError(err, "kallax: struct tag `through` can only be used in slices of models. On field Tags of model Post.")
+
+	_, err = process("[]*Tag `through:\"post_tags\" fk:\",inverse\"`", "int64")
+	s.EqualError(err, "kallax: many to many relationship Tags of model Post can not be inverse")
+
+	_, err = process("[]*Post `through:\"related_posts\"`", "int64")
+	s.EqualError(err, "kallax: both columns of the join table related_posts of relationship Tags of model Post are named post_id. Consider using the struct tag `fk:\"model_column,related_column\"` to name them.")
+
+	_, err = process("[]*Post `through:\"related_posts\" fk:\"post_id,related_id\"`", "int64")
+	s.NoError(err)
+}
+
+func (s *ProcessorSuite) TestValidationRules() {
+	process := func(fields, methods string) (*Package, error) {
+		src := `
+		package fixture
+
+		import "gopkg.in/src-d/go-kallax.v1"
+
+		type Status string
+
+		type User struct {
+			kallax.Model
+			ID int64 ` + "`pk:\"autoincr\"`" + `
+			` + fields + `
+		}
+		` + methods
+		p, err := processorFixture(src)
+		if err != nil {
+			return nil, err
+		}
+		return p.processPackage()
+	}
+
+	pkg, err := process("Name string `validate:\"required, max=255\"`\nStatus *Status `validate:\"oneof=active banned\"`\nAge int `validate:\"min=18\"`\nRoles []string `validate:\"len=2\"`\nBio string", "")
+	s.Require().NoError(err)
+	m := findModel(pkg, "User")
+	s.True(m.HasValidations())
+	s.Len(m.ValidationFields(), 4)
+	s.Equal([]ValidationRule{{Name: "required"}, {Name: "max", Param: "255"}}, findField(m, "Name").ValidationRules())
+
+	cases := []struct {
+		field string
+		err   string
+	}{
+		{"Name string `validate:\"foo\"`", "kallax: rule foo of struct tag `validate` is unknown. On field Name of model User."},
+		{"Name string `validate:\"max=foo\"`", "kallax: rule max=foo of struct tag `validate` needs a number. On field Name of model User."},
+		{"Name string `validate:\"required=true\"`", "kallax: rule required=true of struct tag `validate` has no parameter. On field Name of model User."},
+		{"Age int `validate:\"email\"`", "kallax: rule email of struct tag `validate` can only be used in strings. On field Age of model User."},
+		{"Age int `validate:\"len=2\"`", "kallax: rule len=2 of struct tag `validate` can only be used in strings, slices, arrays and maps. On field Age of model User."},
+		{"Ratio float64 `validate:\"oneof=1 2\"`", "kallax: rule oneof=1 2 of struct tag `validate` can only be used in strings and integers. On field Ratio of model User."},
+		{"Status Status `validate:\"oneof\"`", "kallax: rule oneof of struct tag `validate` needs at least one value. On field Status of model User."},
+	}
+
+	for _, c := range cases {
+		_, err := process(c.field, "")
+		s.EqualError(err, c.err, c.field)
+	}
+
+	_, err = process("Name string `validate:\"required\"`", "func (u *User) Validate() error { return nil }")
+	s.EqualError(err, "kallax: model User has fields with the struct tags `validate` or `enum`, so its Validate method is generated and can not be defined")
+
+	_, err = process("Name string", "func (u *User) Validate() error { return nil }")
+	s.NoError(err)
+}
+
 func TestProcessor(t *testing.T) {
 	suite.Run(t, new(ProcessorSuite))
 }
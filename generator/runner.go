@@ -0,0 +1,228 @@
+package generator
+
+import (
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/golang-migrate/migrate"
+	// The migrations are run against PostgreSQL and read from a directory.
+	_ "github.com/golang-migrate/migrate/database/postgres"
+	_ "github.com/golang-migrate/migrate/source/file"
+)
+
+// MigrationRunner runs the migrations of a directory, as they are generated
+// by MigrationGenerator, against a PostgreSQL database. The version of the
+// database is kept in the schema_migrations table, and every migration is
+// run in a transaction, as the generated ones are wrapped in one. The
+// migrations with a registered DataMigration are run by the runner itself,
+// along with their data migrations.
+type MigrationRunner struct {
+	dir string
+	m   *migrate.Migrate
+	db  *sql.DB
+	// data are the versions of the migrations with a Go file in the
+	// directory, which must have a registered data migration.
+	data map[uint]bool
+}
+
+// NewMigrationRunner returns a new runner of the migrations in the given
+// directory against the database with the given PostgreSQL data source
+// name, such as `user:pass@localhost:5432/database?sslmode=disable`.
+func NewMigrationRunner(dir, dsn string) (*MigrationRunner, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("kallax: cannot get absolute path of the migrations directory: %s", err)
+	}
+
+	data, err := dataMigrationFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := migrate.New(pathToFileURL(dir), fmt.Sprintf("postgres://%s", dsn))
+	if err != nil {
+		return nil, fmt.Errorf("kallax: unable to open a connection with the database: %s", err)
+	}
+
+	db, err := sql.Open("postgres", fmt.Sprintf("postgres://%s", dsn))
+	if err != nil {
+		m.Close()
+		return nil, fmt.Errorf("kallax: unable to open a connection with the database: %s", err)
+	}
+
+	return &MigrationRunner{dir, m, db, data}, nil
+}
+
+// Up runs all the migrations that have not been run yet.
+func (r *MigrationRunner) Up() error {
+	migrations, current, err := r.dataMigrations()
+	if err != nil {
+		return err
+	}
+
+	if migrations == nil {
+		return r.m.Up()
+	}
+
+	return r.migrateData(migrations, current, len(migrations)-1, 0)
+}
+
+// Migrate runs the migrations up, or down, until the database is at the
+// given version.
+func (r *MigrationRunner) Migrate(version uint) error {
+	migrations, current, err := r.dataMigrations()
+	if err != nil {
+		return err
+	}
+
+	if migrations == nil {
+		return r.m.Migrate(version)
+	}
+
+	target, err := migrationIndex(migrations, version)
+	if err != nil {
+		return err
+	}
+	return r.migrateData(migrations, current, target, 0)
+}
+
+// Steps runs the given number of migrations up, or down if it's negative.
+func (r *MigrationRunner) Steps(n int) error {
+	migrations, current, err := r.dataMigrations()
+	if err != nil {
+		return err
+	}
+
+	if migrations == nil {
+		return r.m.Steps(n)
+	}
+
+	var short uint
+	target := current + n
+	if target >= len(migrations) {
+		short, target = uint(target-len(migrations)+1), len(migrations)-1
+	} else if target < -1 {
+		short, target = uint(-1-target), -1
+	}
+	return r.migrateData(migrations, current, target, short)
+}
+
+// dataMigrations returns the migrations of the directory and the index of
+// the one the database is at, if any of them has a data migration, so they
+// must be run one by one. Otherwise, the migrations are nil.
+func (r *MigrationRunner) dataMigrations() ([]*MigrationStatus, int, error) {
+	migrations, err := migrationStatus(r.dir, 0, false)
+	if err != nil || !r.hasDataMigrations(migrations) {
+		return nil, 0, err
+	}
+
+	current, err := r.currentMigration(migrations)
+	if err != nil {
+		return nil, 0, err
+	}
+	return migrations, current, nil
+}
+
+// Version returns the version of the database, which is the one of the last
+// migration run, and whether that migration failed, so the database is
+// dirty and must be fixed by hand. The version is 0 if no migration has been
+// run.
+func (r *MigrationRunner) Version() (version uint, dirty bool, err error) {
+	version, dirty, err = r.m.Version()
+	if err == migrate.ErrNilVersion {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+// MigrationStatus is the status of a migration of a directory in a database.
+type MigrationStatus struct {
+	// Version is the version of the migration, which is the timestamp at the
+	// beginning of the names of its files.
+	Version uint `json:"version"`
+	// Name is the descriptive name of the migration.
+	Name string `json:"name"`
+	// Applied reports whether the migration has been run.
+	Applied bool `json:"applied"`
+	// Dirty reports whether the migration failed, so the database must be
+	// fixed by hand.
+	Dirty bool `json:"dirty,omitempty"`
+}
+
+// migrationFileRegexp matches the names of the files that upgrade the
+// database to a version.
+var migrationFileRegexp = regexp.MustCompile(`^(\d+)_(.*)\.up\.sql$`)
+
+// Status returns the status of all the migrations in the directory, sorted
+// by version.
+func (r *MigrationRunner) Status() ([]*MigrationStatus, error) {
+	version, dirty, err := r.Version()
+	if err != nil {
+		return nil, fmt.Errorf("kallax: unable to check the version of the database: %s", err)
+	}
+
+	return migrationStatus(r.dir, version, dirty)
+}
+
+// migrationStatus returns the status of the migrations in the given
+// directory in a database at the given version.
+func migrationStatus(dir string, version uint, dirty bool) ([]*MigrationStatus, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("kallax: unable to read the migrations directory: %s", err)
+	}
+
+	var status []*MigrationStatus
+	for _, f := range files {
+		m := migrationFileRegexp.FindStringSubmatch(f.Name())
+		if m == nil {
+			continue
+		}
+
+		v, err := strconv.ParseUint(m[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("kallax: invalid version of migration %s: %s", f.Name(), err)
+		}
+
+		status = append(status, &MigrationStatus{
+			Version: uint(v),
+			Name:    m[2],
+			Applied: uint(v) <= version,
+			Dirty:   dirty && uint(v) == version,
+		})
+	}
+
+	sort.Slice(status, func(i, j int) bool {
+		return status[i].Version < status[j].Version
+	})
+	return status, nil
+}
+
+// Close closes the connection with the database.
+func (r *MigrationRunner) Close() error {
+	srcErr, dbErr := r.m.Close()
+	if err := r.db.Close(); err != nil && dbErr == nil {
+		dbErr = err
+	}
+
+	if srcErr != nil {
+		return srcErr
+	}
+	return dbErr
+}
+
+func pathToFileURL(path string) string {
+	if !filepath.IsAbs(path) {
+		var err error
+		path, err = filepath.Abs(path)
+		if err != nil {
+			return ""
+		}
+	}
+	return fmt.Sprintf("file://%s", filepath.ToSlash(path))
+}
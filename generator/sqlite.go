@@ -0,0 +1,161 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// sqliteTypes are the SQLite types of the columns, which store the values
+// in the same representation the database/sql drivers of SQLite expect.
+var sqliteTypes = map[ColumnType]string{
+	ByteaColumn:       "BLOB",
+	SmallIntColumn:    "INTEGER",
+	IntegerColumn:     "INTEGER",
+	BigIntColumn:      "INTEGER",
+	SmallSerialColumn: "INTEGER",
+	SerialColumn:      "INTEGER",
+	BigSerialColumn:   "INTEGER",
+	OIDColumn:         "INTEGER",
+	RealColumn:        "REAL",
+	DoubleColumn:      "REAL",
+	TimestamptzColumn: "DATETIME",
+	TimestampColumn:   "DATETIME",
+	BooleanColumn:     "BOOLEAN",
+	TextColumn:        "TEXT",
+	CITextColumn:      "TEXT COLLATE NOCASE",
+	JSONBColumn:       "TEXT",
+	UUIDColumn:        "TEXT",
+	HStoreColumn:      "TEXT",
+	IntervalColumn:    "TEXT",
+	InetColumn:        "TEXT",
+	CIDRColumn:        "TEXT",
+	MACAddrColumn:     "TEXT",
+	Int4RangeColumn:   "TEXT",
+	Int8RangeColumn:   "TEXT",
+	NumRangeColumn:    "TEXT",
+	TstzRangeColumn:   "TEXT",
+	VarBitColumn:      "TEXT",
+	LTreeColumn:       "TEXT",
+	XMLColumn:         "TEXT",
+}
+
+// sqliteType returns the SQLite type of the given column type, if it can be
+// stored in SQLite. Arrays are stored as their PostgreSQL text literal.
+func sqliteType(typ ColumnType) (string, bool) {
+	if t, ok := sqliteTypes[typ]; ok {
+		return t, true
+	}
+
+	s := string(typ)
+	switch {
+	case strings.HasSuffix(s, "[]"):
+		return "TEXT", true
+	case strings.HasPrefix(s, "numeric("), strings.HasPrefix(s, "decimal("):
+		return "NUMERIC", true
+	case strings.HasPrefix(s, "bit("), strings.HasPrefix(s, "vector"):
+		return "TEXT", true
+	}
+	return "", false
+}
+
+// SQLiteSchema returns the statements that create the tables of the given
+// schema in SQLite, so the stores can be run against SQLite with the
+// kallax.SQLite dialect. The types of the columns are mapped to SQLite types
+// and the indexes SQLite cannot create, such as GIN and GiST ones, are
+// skipped, as they are only used by operators SQLite does not support
+// either. The JSON schemas of the columns are not checked by the database,
+// and neither the check constraints nor the default values are created, as
+// their expressions are written for PostgreSQL.
+// An error is returned if any column has a type that cannot be stored in
+// SQLite, such as composite and enum types and geometries, or is a generated
+// tsvector, or if any model is audited, versioned or notifies its changes.
+func SQLiteSchema(schema *DBSchema) (string, error) {
+	migration, err := NewMigration(new(DBSchema), schema)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	for _, change := range migration.Up {
+		if c, ok := change.(*CreateTable); ok {
+			if err := writeSQLiteTable(&buf, c.TableSchema); err != nil {
+				return "", err
+			}
+		}
+	}
+	return buf.String(), nil
+}
+
+func writeSQLiteTable(buf *bytes.Buffer, table *TableSchema) error {
+	if tracked := table.trackedTable(); tracked != "" {
+		return fmt.Errorf("kallax: table %s keeps the changes of table %s with a trigger, which is not supported by SQLite", table.Name, tracked)
+	}
+
+	if table.Notify != nil {
+		return fmt.Errorf("kallax: table %s notifies its changes with a trigger, which is not supported by SQLite", table.Name)
+	}
+
+	var indexes []string
+	buf.WriteString(fmt.Sprintf("CREATE TABLE %s (\n", table.Name))
+	for i, c := range table.Columns {
+		typ, ok := sqliteType(c.Type)
+		if !ok || c.TSVector != nil {
+			return fmt.Errorf("kallax: column %s of table %s has type %s, which is not supported by SQLite", c.Name, table.Name, c.Type)
+		}
+
+		buf.WriteString(fmt.Sprintf("\t%s %s", c.Name, typ))
+		if c.PrimaryKey && isSerial(c.Type) {
+			// Only INTEGER PRIMARY KEY columns are auto-incrementable.
+			buf.WriteString(" PRIMARY KEY AUTOINCREMENT")
+		} else {
+			if c.NotNull {
+				buf.WriteString(" NOT NULL")
+			}
+
+			if c.Unique {
+				buf.WriteString(" UNIQUE")
+			}
+
+			if c.PrimaryKey {
+				buf.WriteString(" PRIMARY KEY")
+			}
+		}
+
+		if c.Reference != nil {
+			buf.WriteString(" REFERENCES ")
+			buf.WriteString(c.Reference.String())
+		}
+
+		if i < len(table.Columns)-1 || len(table.PrimaryKey) > 0 {
+			buf.WriteString(",\n")
+		} else {
+			buf.WriteRune('\n')
+		}
+
+		switch c.Index {
+		case "unique":
+			indexes = append(indexes, fmt.Sprintf("CREATE UNIQUE INDEX %s ON %s (%s);\n", indexName(table.Name, c.Name, c.Index), table.Name, c.Name))
+		case "btree", "hash":
+			indexes = append(indexes, fmt.Sprintf("CREATE INDEX %s ON %s (%s);\n", indexName(table.Name, c.Name, c.Index), table.Name, c.Name))
+		}
+	}
+	for _, idx := range table.Indexes {
+		indexes = append(indexes, fmt.Sprintf("CREATE INDEX %s ON %s (%s);\n", idx.Name, table.Name, strings.Join(idx.Columns, ", ")))
+	}
+
+	if len(table.PrimaryKey) > 0 {
+		buf.WriteString(primaryKeyConstraint(table.PrimaryKey))
+		buf.WriteRune('\n')
+	}
+	buf.WriteString(");\n")
+	for _, idx := range indexes {
+		buf.WriteString(idx)
+	}
+	buf.WriteRune('\n')
+	return nil
+}
+
+func isSerial(typ ColumnType) bool {
+	return typ == SmallSerialColumn || typ == SerialColumn || typ == BigSerialColumn
+}
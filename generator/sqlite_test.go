@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteSchema(t *testing.T) {
+	schema := mkSchema(
+		mkTable(
+			"posts",
+			mkCol("id", SerialColumn, true, true, nil),
+			mkCol("author_id", UUIDColumn, false, true, mkRef("users", "id", false)),
+			mkColUnique("slug", TextColumn, false, true, nil),
+			mkCol("tags", ArrayColumn(TextColumn), false, false, nil),
+			mkColIndex("doc", JSONBColumn, false, false, "gin"),
+			mkColIndex("published_at", TimestamptzColumn, false, true, "btree"),
+		),
+		mkTable(
+			"users",
+			mkCol("id", UUIDColumn, true, true, nil),
+			mkCol("name", CITextColumn, false, true, nil),
+			mkCol("balance", DecimalColumn(10, 2), false, true, nil),
+		),
+	)
+	schema.Tables[0].Indexes = []*IndexSchema{{Name: "idx_author_slug", Columns: []string{"author_id", "slug"}}}
+
+	sql, err := SQLiteSchema(schema)
+	require.NoError(t, err)
+	require.Equal(t, `CREATE TABLE users (
+	id TEXT NOT NULL PRIMARY KEY,
+	name TEXT COLLATE NOCASE NOT NULL,
+	balance NUMERIC NOT NULL
+);
+
+CREATE TABLE posts (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	author_id TEXT NOT NULL REFERENCES users(id),
+	slug TEXT NOT NULL UNIQUE,
+	tags TEXT,
+	doc TEXT,
+	published_at DATETIME NOT NULL
+);
+CREATE INDEX posts__published_at__btree ON posts (published_at);
+CREATE INDEX idx_author_slug ON posts (author_id, slug);
+
+`, sql)
+}
+
+func TestSQLiteSchema_Unsupported(t *testing.T) {
+	_, err := SQLiteSchema(mkSchema(mkTable(
+		"places",
+		mkCol("id", SerialColumn, true, true, nil),
+		mkCol("location", GeometryColumn("Point", 4326), false, false, nil),
+	)))
+	require.EqualError(t, err, "kallax: column location of table places has type geometry(Point,4326), which is not supported by SQLite")
+
+	_, err = SQLiteSchema(mkSchema(mkTable(
+		"accounts",
+		mkCol("id", SerialColumn, true, true, nil),
+		mkCol("balance", MoneyColumn, false, false, nil),
+	)))
+	require.Error(t, err)
+}
@@ -0,0 +1,196 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Squash replaces the migrations of the directory with a version lower than
+// the given one by a single baseline migration with the given name and the
+// version of the last of them, and returns the baseline and the squashed
+// migrations. All of them must have been run in the database, which can't be
+// dirty, and none of them can have a data migration.
+//
+// If there are no migrations left after them, the baseline creates the whole
+// schema in the lock of the migrations, and drops it when it's reverted.
+// Otherwise, the baseline runs the statements of the squashed migrations in
+// order, and reverts them in reverse order.
+//
+// As the baseline keeps the version of the last squashed migration, the
+// version in the schema_migrations table of the databases where it has been
+// run is still valid, and it's left as it is. Databases that have not run it
+// must be migrated up to it before the squashed migrations are removed.
+func (r *MigrationRunner) Squash(before uint, name string) (*MigrationStatus, []*MigrationStatus, error) {
+	if name = slugify(name); name == "" {
+		return nil, nil, fmt.Errorf("kallax: the baseline migration needs a name")
+	}
+
+	version, dirty, err := r.Version()
+	if err != nil {
+		return nil, nil, fmt.Errorf("kallax: unable to check the version of the database: %s", err)
+	}
+
+	return squashMigrations(r.dir, before, name, version, dirty)
+}
+
+// squashMigrations squashes the migrations of the given directory with a
+// version lower than before, in a database at the given version.
+func squashMigrations(dir string, before uint, name string, version uint, dirty bool) (*MigrationStatus, []*MigrationStatus, error) {
+	if dirty {
+		return nil, nil, fmt.Errorf("kallax: the database is dirty at version %d, it must be fixed by hand before squashing the migrations", version)
+	}
+
+	status, err := migrationStatus(dir, version, dirty)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var squashed []*MigrationStatus
+	for _, s := range status {
+		if s.Version < before {
+			squashed = append(squashed, s)
+		}
+	}
+
+	if len(squashed) < 2 {
+		return nil, nil, fmt.Errorf("kallax: there must be at least two migrations before version %d to squash them", before)
+	}
+
+	data, err := dataMigrationFiles(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, m := range squashed {
+		if data[m.Version] {
+			return nil, nil, fmt.Errorf("kallax: migration %d_%s has a data migration, which can not be squashed, so its Go file must be removed first", m.Version, m.Name)
+		}
+	}
+
+	last := squashed[len(squashed)-1]
+	if !last.Applied {
+		return nil, nil, fmt.Errorf("kallax: the database is at version %d, but only the migrations that have been run can be squashed and migration %d has not", version, last.Version)
+	}
+
+	var up, down []byte
+	if len(squashed) == len(status) {
+		up, down, err = lockBaseline(dir)
+	} else {
+		up, down, err = concatMigrations(dir, squashed)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	baseline := &MigrationStatus{Version: last.Version, Name: name, Applied: true}
+	files := []struct {
+		file    string
+		content []byte
+	}{
+		{migrationFileName(dir, baseline, migrationDown), down},
+		{migrationFileName(dir, baseline, migrationUp), up},
+	}
+
+	written := make(map[string]bool)
+	for _, f := range files {
+		if err := ioutil.WriteFile(f.file, f.content, 0755); err != nil {
+			return nil, nil, fmt.Errorf("kallax: unable to write the baseline migration: %s", err)
+		}
+		written[f.file] = true
+	}
+
+	for _, m := range squashed {
+		for _, typ := range []migrationFileType{migrationUp, migrationDown} {
+			file := migrationFileName(dir, m, typ)
+			if written[file] {
+				continue
+			}
+
+			if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+				return nil, nil, fmt.Errorf("kallax: unable to remove squashed migration: %s", err)
+			}
+		}
+	}
+
+	return baseline, squashed, nil
+}
+
+// lockBaseline returns the up and down migrations that create and drop the
+// schema in the lock of the migrations of the given directory.
+func lockBaseline(dir string) (up, down []byte, err error) {
+	if _, err := os.Stat(filepath.Join(dir, string(migrationLock))); err != nil {
+		return nil, nil, fmt.Errorf("kallax: unable to find the lock file of the migrations: %s", err)
+	}
+
+	lock, err := NewMigrationGenerator("baseline", dir).LoadLock()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	create, err := createChanges(lock)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	drop, err := create.ReverseChangeSet(new(DBSchema)).sorted(lock.index(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if up, err = create.MarshalText(); err != nil {
+		return nil, nil, err
+	}
+
+	if down, err = drop.MarshalText(); err != nil {
+		return nil, nil, err
+	}
+	return up, down, nil
+}
+
+// concatMigrations returns the up migration with the statements of the up
+// files of the given migrations, in order, and the down migration with the
+// statements of their down files, in reverse order, in a single transaction
+// each.
+func concatMigrations(dir string, migrations []*MigrationStatus) (up, down []byte, err error) {
+	var ups, downs bytes.Buffer
+	ups.WriteString("BEGIN;\n\n")
+	downs.WriteString("BEGIN;\n\n")
+	for i := range migrations {
+		sql, err := readMigration(dir, migrations[i], migrationUp)
+		if err != nil {
+			return nil, nil, err
+		}
+		ups.WriteString(sql)
+
+		sql, err = readMigration(dir, migrations[len(migrations)-1-i], migrationDown)
+		if err != nil {
+			return nil, nil, err
+		}
+		downs.WriteString(sql)
+	}
+	ups.WriteString("COMMIT;\n")
+	downs.WriteString("COMMIT;\n")
+	return ups.Bytes(), downs.Bytes(), nil
+}
+
+// readMigration returns the statements of the given file of a migration,
+// without the transaction the generated migrations are wrapped in, after a
+// comment with the name of the migration.
+func readMigration(dir string, m *MigrationStatus, typ migrationFileType) (string, error) {
+	file := migrationFileName(dir, m, typ)
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("kallax: unable to read migration %s: %s", filepath.Base(file), err)
+	}
+
+	sql, _ := transactionBody(string(data))
+	return fmt.Sprintf("-- %d_%s\n%s\n\n", m.Version, m.Name, sql), nil
+}
+
+// migrationFileName returns the path of the given file of a migration.
+func migrationFileName(dir string, m *MigrationStatus, typ migrationFileType) string {
+	return filepath.Join(dir, fmt.Sprintf("%d_%s.%s", m.Version, m.Name, typ))
+}
@@ -3,6 +3,7 @@ package generator
 import (
 	"bytes"
 	"fmt"
+	"go/token"
 	"go/types"
 	"io"
 	"os"
@@ -10,6 +11,7 @@ import (
 	"sort"
 	"strings"
 	"text/template"
+	"unicode"
 
 	parseutil "gopkg.in/src-d/go-parse-utils.v1"
 
@@ -19,6 +21,8 @@ import (
 // Template renders the kallax templates using given packages.
 type Template struct {
 	template *template.Template
+	// plain templates are not Go code, so their output is not formatted.
+	plain bool
 }
 
 // TemplateData is the structure passed to fill the templates.
@@ -43,6 +47,11 @@ func (t *Template) Execute(wr io.Writer, data *Package) error {
 		return err
 	}
 
+	if t.plain {
+		_, err = wr.Write(buf.Bytes())
+		return err
+	}
+
 	return prettyfy(buf.Bytes(), wr)
 }
 
@@ -52,11 +61,286 @@ func (td *TemplateData) GenTimeTruncations(model *Model) string {
 	return buf.String()
 }
 
+const generateIDTpl = `if record.GetID().IsEmpty() {
+record.%s = %s
+}
+`
+
+// GenIDGeneration generates the code that sets a new UUID as the primary key
+// of a record being inserted if it's empty and the primary key has the struct
+// tag `uuid`.
+func (td *TemplateData) GenIDGeneration(model *Model) string {
+	version, _ := model.ID.UUIDVersion()
+	if version == "" {
+		return ""
+	}
+
+	id := fmt.Sprintf("kallax.NewUUID%s()", version)
+	if typ := td.GenTypeName(model.ID); typ != "kallax.UUID" {
+		id = fmt.Sprintf("%s(%s)", typ, id)
+	}
+
+	return fmt.Sprintf(generateIDTpl, model.ID.Name, id)
+}
+
+// uuidPackages are the packages of the UUID types supported besides
+// kallax.UUID. All of them are named uuid, so goimports can't tell which one
+// a generated uuid.UUID refers to and their imports must be generated.
+var uuidPackages = map[string]struct{}{
+	"github.com/satori/go.uuid": {},
+	"github.com/gofrs/uuid":     {},
+	"github.com/google/uuid":    {},
+}
+
+// GenUUIDImports generates the imports of the packages of the UUID types of
+// the fields of all the models, in the order they are found.
+func (td *TemplateData) GenUUIDImports() string {
+	var buf bytes.Buffer
+	imported := make(map[string]bool)
+	var genFields func([]*Field)
+	genFields = func(fields []*Field) {
+		for _, f := range fields {
+			if f.Node != nil {
+				if pkg := uuidPackage(f.Node.Type()); pkg != nil && !imported[pkg.Path()] {
+					imported[pkg.Path()] = true
+					path := removeGoPath(pkg.Path())
+					if pkg.Name() == path[strings.LastIndex(path, "/")+1:] {
+						fmt.Fprintf(&buf, "%q\n", path)
+					} else {
+						fmt.Fprintf(&buf, "%s %q\n", pkg.Name(), path)
+					}
+				}
+			}
+			genFields(f.Fields)
+		}
+	}
+
+	for _, m := range td.Models {
+		genFields(m.Fields)
+	}
+	return buf.String()
+}
+
+// uuidPackage returns the package of the given type, or of the type of its
+// elements, if it's one of the uuidPackages.
+func uuidPackage(typ types.Type) *types.Package {
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+
+	if elem := collectionElemType(typ); elem != nil {
+		return uuidPackage(elem)
+	}
+
+	named, ok := typ.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return nil
+	}
+
+	if _, ok := uuidPackages[removeGoPath(named.Obj().Pkg().Path())]; !ok {
+		return nil
+	}
+	return named.Obj().Pkg()
+}
+
+const jsonSchemaValidationTpl = `if v, err := r.Value(%q); err != nil {
+return err
+} else if err := %s.Validate(v); err != nil {
+return fmt.Errorf("%%s. On field %s of model %s", err)
+}
+`
+
+// GenJSONSchemas generates the compiled JSON Schema documents of the fields of
+// the given model with the struct tag `jsonschema` and the ValidateJSONSchemas
+// method of the model, which validates them.
+func (td *TemplateData) GenJSONSchemas(model *Model) string {
+	fields := model.JSONSchemaFields()
+	if len(fields) == 0 {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	for _, f := range fields {
+		buf.WriteString(fmt.Sprintf("var %s = types.MustCompileJSONSchema(%q)\n", jsonSchemaVarName(model, f), f.JSONSchema))
+	}
+
+	buf.WriteString(fmt.Sprintf(`
+// ValidateJSONSchemas returns an error if the value of any of the fields of %s
+// with a JSON schema does not match it. It is called before inserting and
+// updating the record.
+func (r *%s) ValidateJSONSchemas() error {
+`, model.Name, model.Name))
+	for _, f := range fields {
+		buf.WriteString(fmt.Sprintf(jsonSchemaValidationTpl, f.ColumnName(), jsonSchemaVarName(model, f), f.Name, model.Name))
+	}
+	buf.WriteString("return nil\n}\n")
+	return buf.String()
+}
+
+// GenValidate generates the Validate method of the given model, which checks
+// the rules of the struct tag `validate` of its fields.
+func (td *TemplateData) GenValidate(model *Model) string {
+	fields := model.ValidationFields()
+	if len(fields) == 0 {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf(`
+// Validate returns a *kallax.ValidationError listing the rules of the struct
+// tag validate not satisfied by the fields of %s, or nil if all of them are
+// satisfied. It is called before inserting and updating the record.
+func (r *%s) Validate() error {
+errs := kallax.NewValidationError(%q)
+`, model.Name, model.Name, model.Name))
+	for _, f := range fields {
+		var rules []string
+		for _, r := range f.ValidationRules() {
+			rules = append(rules, ruleConstructor(r))
+		}
+		buf.WriteString(fmt.Sprintf("errs.Check(%q, %s, %s)\n", f.Name, f.fieldVarName(), strings.Join(rules, ", ")))
+	}
+	buf.WriteString("return errs.Err()\n}\n")
+	return buf.String()
+}
+
+// GenWithTenant generates the WithTenant method of the store of the given
+// model, which receives a tenant of the type of its tenant field, so the
+// store can not be bound to a tenant of another type.
+func (td *TemplateData) GenWithTenant(model *Model) string {
+	f := model.TenantField()
+	if f == nil {
+		return ""
+	}
+
+	typ, ok := f.typeName()
+	if !ok {
+		typ = td.GenTypeName(f)
+	}
+
+	return fmt.Sprintf(`
+// WithTenant returns a new store that only reads and writes the records whose
+// %[1]s is the given tenant.
+func (s *%[2]s) WithTenant(tenant %[3]s) *%[2]s {
+return &%[2]s{s.Store.WithTenant(tenant)}
+}
+`, f.Name, model.StoreName, typ)
+}
+
+func jsonSchemaVarName(model *Model, f *Field) string {
+	return fmt.Sprintf("jsonSchema%s%s", model.Name, strings.Replace(f.fieldName(), ".", "", -1))
+}
+
+// GenBenchmarkRecord generates the code that fills a record of the given
+// model used in the benchmarks, which is the record number i of the dataset.
+// Primary keys that are not autoincrementable and unique columns of string
+// or integer types are set to different values for every record, and
+// relationships that are not pointers nor collections are set to benchmark
+// records of their models.
+func (td *TemplateData) GenBenchmarkRecord(model *Model) string {
+	var buf bytes.Buffer
+	td.genRecordID(&buf, model, "i + 1")
+	for _, f := range model.Fields {
+		switch {
+		case f.Kind == Relationship && !f.IsPtr && !strings.HasPrefix(f.Type, "["):
+			fmt.Fprintf(&buf, "record.%s = *newBenchmark%s(i)\n", f.Name, f.TypeSchemaName())
+		case f.Kind == Basic && f.IsUnique() && !f.IsPrimaryKey() && !f.IsPtr:
+			basic, ok := f.Node.Type().Underlying().(*types.Basic)
+			if !ok {
+				continue
+			}
+
+			var value string
+			str := fmt.Sprintf("fmt.Sprintf(%q, i)", f.ColumnName()+"-%d")
+			switch typ := td.GenTypeName(f); {
+			case basic.Info()&types.IsString != 0 && typ == "string":
+				value = str
+			case basic.Info()&types.IsString != 0:
+				value = fmt.Sprintf("%s(%s)", typ, str)
+			case basic.Info()&types.IsInteger != 0 && typ == "int":
+				value = "i"
+			case basic.Info()&types.IsInteger != 0:
+				value = fmt.Sprintf("%s(i)", typ)
+			default:
+				continue
+			}
+			fmt.Fprintf(&buf, "record.%s = %s\n", f.Name, value)
+		}
+	}
+
+	return buf.String()
+}
+
+// genRecordID generates the code that sets the primary key of a record of
+// the given model, if it's not autoincrementable, to a new ULID or UUID, or
+// to the given sequence number for the rest of types. UUIDs of other
+// versions than 4 are left for the store to generate them.
+func (td *TemplateData) genRecordID(buf *bytes.Buffer, model *Model, seq string) {
+	if model.ID.IsAutoIncrement() {
+		return
+	}
+
+	typ, id := td.GenTypeName(model.ID), identifierType(model.ID)
+	switch version, _ := model.ID.UUIDVersion(); {
+	case id == "kallax.ULID" && typ == id:
+		fmt.Fprintf(buf, "record.%s = kallax.NewULID()\n", model.ID.Name)
+	case id == "kallax.ULID":
+		fmt.Fprintf(buf, "record.%s = %s(kallax.NewULID())\n", model.ID.Name, typ)
+	case id == "kallax.UUID" && version == "" && typ == id:
+		fmt.Fprintf(buf, "record.%s = kallax.NewUUIDv4()\n", model.ID.Name)
+	case id == "kallax.UUID" && version == "":
+		fmt.Fprintf(buf, "record.%s = %s(kallax.NewUUIDv4())\n", model.ID.Name, typ)
+	case id != "kallax.UUID":
+		fmt.Fprintf(buf, "record.%s = %s(%s)\n", model.ID.Name, typ, seq)
+	}
+}
+
+// GenBenchmarkSchema generates the SQL statements that create the tables of
+// all the models, used in the benchmarks, as a Go string literal.
+func (td *TemplateData) GenBenchmarkSchema() (string, error) {
+	schema, err := SchemaFromPackages(td.Package)
+	if err != nil {
+		return "", fmt.Errorf("kallax: cannot generate the tables of the benchmarks: %s", err)
+	}
+
+	migration, err := NewMigration(new(DBSchema), schema)
+	if err != nil {
+		return "", err
+	}
+
+	// the changes are not wrapped in a transaction, as the benchmarks already
+	// run in one
+	var sql bytes.Buffer
+	for _, c := range migration.Up {
+		text, err := c.MarshalText()
+		if err != nil {
+			return "", err
+		}
+		sql.Write(text)
+		sql.WriteRune('\n')
+	}
+
+	return fmt.Sprintf("%q", sql.String()), nil
+}
+
 const truncateTimePtrTpl = `if record.%s != nil {
 record.%s = func(t time.Time) *time.Time { return &t }(record.%s.Truncate(time.Microsecond))
 }
 `
 
+// promotedFieldName returns the selector of the given field in the record,
+// in which the fields of the embedded structs are promoted, such as
+// Billing.Street for the field Street of the struct field Billing.
+func promotedFieldName(f *Field) string {
+	name := f.Name
+	for p := f.Parent; p != nil; p = p.Parent {
+		if !p.IsEmbedded {
+			name = p.Name + "." + name
+		}
+	}
+	return name
+}
+
 func (td *TemplateData) genFieldsTimeTruncations(buf *bytes.Buffer, fields []*Field) {
 	for _, f := range fields {
 		if f.Inline() {
@@ -66,10 +350,11 @@ func (td *TemplateData) genFieldsTimeTruncations(buf *bytes.Buffer, fields []*Fi
 
 		typ := removeTypePrefix(typeName(f.Node.Type()))
 		if typ == "time.Time" {
+			name := promotedFieldName(f)
 			if !f.IsPtr {
-				buf.WriteString(fmt.Sprintf("record.%s = record.%s.Truncate(time.Microsecond)\n", f.Name, f.Name))
+				buf.WriteString(fmt.Sprintf("record.%s = record.%s.Truncate(time.Microsecond)\n", name, name))
 			} else {
-				buf.WriteString(fmt.Sprintf(truncateTimePtrTpl, f.Name, f.Name, f.Name))
+				buf.WriteString(fmt.Sprintf(truncateTimePtrTpl, name, name, name))
 			}
 		}
 	}
@@ -106,10 +391,11 @@ func (td *TemplateData) genFieldsColumnAddresses(buf *bytes.Buffer, fields []*Fi
 			} else {
 				// can't scan a json if is nil
 				if (f.IsJSON || f.Kind == Interface) && f.IsPtr {
-					buf.WriteString(fmt.Sprintf(initNilPtrTpl, f.Name, f.Name, td.GenTypeName(f)))
+					name := promotedFieldName(f)
+					buf.WriteString(fmt.Sprintf(initNilPtrTpl, name, name, td.GenTypeName(f)))
 				}
 
-				if f.Kind == Basic && f.IsAlias {
+				if f.Kind == Basic && f.IsAlias && f.Compression() == "" {
 					buf.WriteString(fmt.Sprintf("return (*%s)(%s), nil\n", f.Type, f.Address()))
 				} else {
 					buf.WriteString(fmt.Sprintf("return %s, nil\n", f.Address()))
@@ -128,6 +414,93 @@ func (td *TemplateData) IdentifierType(f *Field) string {
 	return identifierType(f)
 }
 
+// GenPrimaryKeyValues generates the values of the fields of the composite
+// primary key of the given model returned by GetPrimaryKey.
+func (td *TemplateData) GenPrimaryKeyValues(model *Model) string {
+	values := make([]string, len(model.PrimaryKey))
+	for i, f := range model.PrimaryKey {
+		values[i] = fmt.Sprintf("(*%s)(%s)", td.IdentifierType(f), f.fieldVarAddress())
+	}
+	return strings.Join(values, ", ")
+}
+
+// GenPrimaryKeyColumns generates the schema fields of the columns of the
+// composite primary key of the given model.
+func (td *TemplateData) GenPrimaryKeyColumns(model *Model) string {
+	cols := make([]string, len(model.PrimaryKey))
+	for i, f := range model.PrimaryKey {
+		cols[i] = fmt.Sprintf("kallax.NewSchemaField(%q)", f.ColumnName())
+	}
+	return strings.Join(cols, ", ")
+}
+
+// GenDefaultColumns generates the columns with a default value of the given
+// model.
+func (td *TemplateData) GenDefaultColumns(model *Model) string {
+	fields := model.DefaultFields()
+	cols := make([]string, len(fields))
+	for i, f := range fields {
+		name := f.ColumnName()
+		if f.Kind == Relationship {
+			name = f.ForeignKey()
+		}
+		cols[i] = fmt.Sprintf("kallax.NewSchemaField(%q)", name)
+	}
+	return strings.Join(cols, ", ")
+}
+
+// GenPrimaryKeyParams generates the parameters of FindByPrimaryKey, one for
+// each field of the primary key of the given model.
+func (td *TemplateData) GenPrimaryKeyParams(model *Model) string {
+	params := make([]string, len(model.PrimaryKey))
+	for i, f := range model.PrimaryKey {
+		typ, _ := f.typeName()
+		params[i] = fmt.Sprintf("%s %s", paramName(f.Name), typ)
+	}
+	return strings.Join(params, ", ")
+}
+
+// GenPrimaryKeyCond generates the condition with which FindByPrimaryKey
+// finds the record with the given primary key of the given model.
+func (td *TemplateData) GenPrimaryKeyCond(model *Model) string {
+	conds := make([]string, len(model.PrimaryKey))
+	for i, f := range model.PrimaryKey {
+		conds[i] = fmt.Sprintf("kallax.Eq(Schema.%s.%s, %s)", model.Name, f.Name, paramName(f.Name))
+	}
+
+	if len(conds) == 1 {
+		return conds[0]
+	}
+	return fmt.Sprintf("kallax.And(%s)", strings.Join(conds, ", "))
+}
+
+// paramName returns the name of the parameter of a generated function that
+// receives the value of the field with the given name, which is the field
+// name with its leading initialism or first letter in lower case.
+func paramName(field string) string {
+	runes := []rune(field)
+	n := 0
+	for n < len(runes) && unicode.IsUpper(runes[n]) {
+		n++
+	}
+
+	if n > 1 && n < len(runes) {
+		// the last upper case letter starts the next word, as in "IDNumber"
+		n--
+	}
+
+	for i := 0; i < n; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+
+	name := string(runes)
+	if token.IsKeyword(name) || name == "s" {
+		// s is the receiver of the generated store methods
+		name += "Value"
+	}
+	return name
+}
+
 // GenColumnValues generates the body of the switch that returns the column
 // address given a column name for the given model.
 func (td *TemplateData) GenColumnValues(model *Model) string {
@@ -144,6 +517,10 @@ const nilPtrReturnsUntypedNilTpl = `if %s == (*%s)(nil) {
 }
 `
 
+const generatedFieldValueTpl = `case "%s":
+return nil, kallax.ErrGeneratedColumn
+`
+
 const virtualFieldValueTpl = `case "%s":
 v := r.Model.VirtualColumn(col)
 if v == nil {
@@ -158,6 +535,8 @@ func (td *TemplateData) genFieldsValues(buf *bytes.Buffer, fields []*Field) {
 			td.genFieldsValues(buf, f.Fields)
 		} else if isOneToOneRelationship(f) && f.IsInverse() {
 			buf.WriteString(fmt.Sprintf(virtualFieldValueTpl, f.ForeignKey()))
+		} else if f.IsGenerated() {
+			buf.WriteString(fmt.Sprintf(generatedFieldValueTpl, f.ColumnName()))
 		} else if f.Kind != Relationship {
 			buf.WriteString(fmt.Sprintf("case \"%s\":\n", f.ColumnName()))
 			if f.IsPtr {
@@ -191,6 +570,109 @@ func (td *TemplateData) genFieldsColumns(buf *bytes.Buffer, fields []*Field) {
 	}
 }
 
+// GenSchemaInfo generates the description of the schema of the given model
+// that is registered to be inspected at runtime. The types of the columns
+// that can not be transformed to a PostgreSQL type are left empty.
+func (td *TemplateData) GenSchemaInfo(model *Model) string {
+	var buf bytes.Buffer
+	buf.WriteString("&kallax.SchemaInfo{\n")
+	buf.WriteString(fmt.Sprintf("Model: %q,\n", model.Name))
+	buf.WriteString(fmt.Sprintf("Package: %q,\n", td.importPath()))
+	buf.WriteString(fmt.Sprintf("Schema: Schema.%s.BaseSchema,\n", model.Name))
+
+	t := newPackageTransformer()
+	t.pkg = td.Package
+	for _, m := range td.Package.Models {
+		t.tableIndex[m.Node.String()] = m.Table
+		t.pkIndex[m.Table] = m.ID
+	}
+
+	buf.WriteString("Columns: []kallax.ColumnInfo{\n")
+	td.genFieldsColumnInfo(&buf, t, "", model.Fields)
+	for _, fk := range model.ImplicitFKs {
+		var typ ColumnType
+		if f := td.implicitFKField(model, fk); f != nil {
+			typ, _ = t.transformType(f, false)
+		}
+		buf.WriteString(fmt.Sprintf("{Name: %q, Type: %q},\n", fk.Name, typ))
+	}
+	buf.WriteString("},\n")
+
+	buf.WriteString("Relationships: []kallax.RelationshipInfo{\n")
+	for _, f := range model.Relationships() {
+		if f.IsManyToManyRelationship() {
+			buf.WriteString(fmt.Sprintf(
+				"{Field: %q, Type: kallax.ManyToMany, Schema: Schema.%s.BaseSchema, ForeignKey: %q, Through: %q, References: %q},\n",
+				f.Name, f.TypeSchemaName(), f.ForeignKey(), f.Through(), f.JoinTableReferences(),
+			))
+			continue
+		}
+
+		typ := "OneToOne"
+		if f.IsOneToManyRelationship() {
+			typ = "OneToMany"
+		}
+
+		buf.WriteString(fmt.Sprintf(
+			"{Field: %q, Type: kallax.%s, Schema: Schema.%s.BaseSchema, ForeignKey: %q, Inverse: %t},\n",
+			f.Name, typ, f.TypeSchemaName(), f.ForeignKey(), f.IsInverse(),
+		))
+	}
+	buf.WriteString("},\n")
+	buf.WriteString("}")
+	return buf.String()
+}
+
+func (td *TemplateData) genFieldsColumnInfo(buf *bytes.Buffer, t *packageTransformer, parent string, fields []*Field) {
+	for _, f := range fields {
+		name := f.ColumnName()
+		if f.Inline() {
+			td.genFieldsColumnInfo(buf, t, parent+f.Name+".", f.Fields)
+			continue
+		} else if isOneToOneRelationship(f) && f.IsInverse() {
+			name = f.ForeignKey()
+		} else if f.Kind == Relationship {
+			continue
+		}
+
+		typ, _ := t.transformType(f, f.IsPrimaryKey())
+
+		buf.WriteString(fmt.Sprintf(
+			"{Name: %q, Field: %q, Type: %q, PrimaryKey: %t, NotNull: %t},\n",
+			name, parent+f.Name, typ, f.IsPrimaryKey(), !f.IsPtr && !f.IsNull(),
+		))
+	}
+}
+
+// implicitFKField returns the relationship of another model that defines
+// the given implicit foreign key of the given model.
+func (td *TemplateData) implicitFKField(model *Model, fk ImplicitFK) *Field {
+	for _, m := range td.Package.Models {
+		for _, f := range m.Relationships() {
+			if !f.IsInverse() && !f.IsManyToManyRelationship() && f.TypeSchemaName() == model.Name && f.ForeignKey() == fk.Name {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+// importPath returns the import path of the package, which is relative to
+// the GOPATH if the package was given as a directory.
+func (td *TemplateData) importPath() string {
+	if td.Package.pkg == nil {
+		return ""
+	}
+
+	path := td.Package.pkg.Path()
+	if strings.HasPrefix(path, ".") || filepath.IsAbs(path) {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = removeGoPath(abs)
+		}
+	}
+	return path
+}
+
 // GenModelSchema generates generates the fields of the struct definition
 // in the given model.
 func (td *TemplateData) GenModelSchema(model *Model) string {
@@ -210,11 +692,17 @@ func (td *TemplateData) genFieldsSchema(buf *bytes.Buffer, parent string, fields
 		} else if isOneToOneRelationship(f) && f.IsInverse() {
 			buf.WriteString(fmt.Sprintf("%sFK kallax.SchemaField\n", f.Name))
 		} else {
-			buf.WriteString(f.Name + " ")
+			if notice, ok := f.Deprecation(); ok {
+				buf.WriteString("// Deprecated: " + notice + "\n")
+			}
+			buf.WriteString(f.SchemaName() + " ")
 
 			if f.IsJSON && len(f.Fields) > 0 {
-				buf.WriteString("*schema" + parent + f.Name)
+				buf.WriteString("*schema" + parent + f.SchemaName())
 				td.findJSONSchemas(parent, f)
+			} else if f.IsComposite && len(f.Fields) > 0 {
+				buf.WriteString("*schema" + parent + f.SchemaName())
+				td.subschemas[parent+f.SchemaName()] = f
 			} else {
 				buf.WriteString("kallax.SchemaField")
 			}
@@ -225,7 +713,7 @@ func (td *TemplateData) genFieldsSchema(buf *bytes.Buffer, parent string, fields
 }
 
 func (td *TemplateData) findJSONSchemas(parent string, f *Field) {
-	n := parent + f.Name
+	n := parent + f.SchemaName()
 	if _, ok := td.subschemas[n]; ok {
 		return
 	}
@@ -287,6 +775,13 @@ func (td *TemplateData) GenSubSchemas() string {
 	for _, name := range names {
 		field := td.subschemas[name]
 		buf.WriteString("type schema" + name + " struct {\n")
+		if field.IsComposite {
+			buf.WriteString("*kallax.BaseSchemaField\n")
+			td.genCompositeFieldsSchema(&buf, field.Fields)
+			buf.WriteString("}\n\n")
+			continue
+		}
+
 		if isSliceOrArray(field) {
 			if isRootField(field) {
 				buf.WriteString("*kallax.BaseSchemaField\n")
@@ -306,6 +801,30 @@ func (td *TemplateData) GenSubSchemas() string {
 	return buf.String()
 }
 
+// genCompositeFieldsSchema generates the fields of the schema of a composite
+// type, which are its attributes.
+func (td *TemplateData) genCompositeFieldsSchema(buf *bytes.Buffer, fields []*Field) {
+	for _, f := range fields {
+		if f.IsEmbedded {
+			td.genCompositeFieldsSchema(buf, f.Fields)
+		} else {
+			buf.WriteString(f.Name + " kallax.SchemaField\n")
+		}
+	}
+}
+
+// genCompositeFieldsInit generates the initialization of the attributes of
+// the schema of a composite type.
+func (td *TemplateData) genCompositeFieldsInit(buf *bytes.Buffer, column string, fields []*Field) {
+	for _, f := range fields {
+		if f.IsEmbedded {
+			td.genCompositeFieldsInit(buf, column, f.Fields)
+		} else {
+			buf.WriteString(fmt.Sprintf("%s: kallax.NewCompositeSchemaField(%q, %q),\n", f.Name, column, f.ColumnName()))
+		}
+	}
+}
+
 // genArraySchemaAtFunc generates the `At` func for an array field schema.
 func (td *TemplateData) genArraySchemaAtFunc(buf *bytes.Buffer, parent string, f *Field) {
 	buf.WriteString(fmt.Sprintf("func (s *schema%s) At(n int) *schema%s {\n", parent, parent))
@@ -418,17 +937,24 @@ func (td *TemplateData) genFieldsInit(buf *bytes.Buffer, parent string, fields [
 		} else if isOneToOneRelationship(f) && f.IsInverse() {
 			buf.WriteString(fmt.Sprintf("%sFK:kallax.NewSchemaField(\"%s\"),\n", f.Name, f.ForeignKey()))
 		} else {
-			buf.WriteString(f.Name + ":")
+			buf.WriteString(f.SchemaName() + ":")
 			var schemaName = f.Name
 			if root {
 				schemaName = f.ColumnName()
 			}
 
 			if f.IsJSON && len(f.Fields) > 0 {
-				buf.WriteString(fmt.Sprintf("&schema%s%s{\n", parent, f.Name))
+				buf.WriteString(fmt.Sprintf("&schema%s%s{\n", parent, f.SchemaName()))
+				buf.WriteString(fmt.Sprintf(`BaseSchemaField: kallax.NewSchemaField("%s").(*kallax.BaseSchemaField),`+"\n", schemaName))
+				td.genSubschemaFieldsInit(buf, parent+f.SchemaName(), f.Fields, "")
+				buf.WriteString("},")
+			} else if f.IsComposite && len(f.Fields) > 0 {
+				buf.WriteString(fmt.Sprintf("&schema%s%s{\n", parent, f.SchemaName()))
 				buf.WriteString(fmt.Sprintf(`BaseSchemaField: kallax.NewSchemaField("%s").(*kallax.BaseSchemaField),`+"\n", schemaName))
-				td.genSubschemaFieldsInit(buf, parent+f.Name, f.Fields, "")
+				td.genCompositeFieldsInit(buf, schemaName, f.Fields)
 				buf.WriteString("},")
+			} else if _, ok := f.Tag.Lookup("tsvector"); ok && root {
+				buf.WriteString(fmt.Sprintf(`kallax.NewTSVectorSchemaField("%s", %q),`, schemaName, tsvectorConfig(f)))
 			} else {
 				buf.WriteString(fmt.Sprintf(`kallax.NewSchemaField("%s"),`, schemaName))
 			}
@@ -524,11 +1050,38 @@ var (
 	model     = addTemplate(base, "model", "templates/model.tgo")
 	query     = addTemplate(model, "query", "templates/query.tgo")
 	resultset = addTemplate(model, "resultset", "templates/resultset.tgo")
+	sqlmock   = makeTemplate("sqlmock", "templates/sqlmock.tgo")
+	benchmark = makeTemplate("benchmark", "templates/benchmark.tgo")
+	proto     = makeTemplate("proto", "templates/proto.tgo")
+	grpc      = makeTemplate("grpc", "templates/grpc.tgo")
+	mock      = makeTemplate("mock", "templates/mock.tgo")
+	factory   = makeTemplate("factory", "templates/factory.tgo")
 )
 
 // Base is the default Template instance with all templates preloaded.
 var Base = &Template{template: base}
 
+// SQLMock is the Template instance of the go-sqlmock expectation helpers.
+var SQLMock = &Template{template: sqlmock}
+
+// Benchmark is the Template instance of the benchmarks of the stores.
+var Benchmark = &Template{template: benchmark}
+
+// Proto is the Template instance of the Protocol Buffers definition of the
+// messages and gRPC services of the models.
+var Proto = &Template{template: proto, plain: true}
+
+// GRPC is the Template instance of the implementation of the gRPC services
+// of the models.
+var GRPC = &Template{template: grpc}
+
+// Mock is the Template instance of the in-memory mock stores of the models.
+var Mock = &Template{template: mock}
+
+// Factory is the Template instance of the factories of records of the models
+// used in tests.
+var Factory = &Template{template: factory}
+
 const (
 	// tplFindByCollection is the template of the FindBy autogenerated for
 	// properties that are collection.
@@ -551,6 +1104,15 @@ const (
 		func (q *%[2]s) FindBy%[1]s(v %[3]s) *%[2]s {
 			return q.Where(kallax.Eq(Schema.%[4]s.%[1]s, v))
 		}`
+	// tplFindByMappedEquality is the template of the FindBy autogenerated for
+	// properties that will be searched with an kallax.Eq condition, but need
+	// their value to be converted to their kallax type counterpart first.
+	tplFindByMappedEquality = `
+		// FindBy%[1]s adds a new filter to the query that will require that
+		// the %[1]s property is equal to the passed value.
+		func (q *%[2]s) FindBy%[1]s(v %[3]s) *%[2]s {
+			return q.Where(kallax.Eq(Schema.%[4]s.%[1]s, %[5]s(v)))
+		}`
 	// tplFindByCondition is the template of the FindBy autogenerated for
 	// properties that can be compared regarding to a kallax.ScalarCond condition.
 	tplFindByCondition = `
@@ -559,6 +1121,23 @@ const (
 		func (q *%[2]s) FindBy%[1]s(cond kallax.ScalarCond, v %[3]s) *%[2]s {
 			return q.Where(cond(Schema.%[4]s.%[1]s, v))
 		}`
+	// tplFindByMappedCondition is the template of the FindBy autogenerated for
+	// properties that can be compared regarding to a kallax.ScalarCond
+	// condition, but need a pointer to their value to be wrapped first.
+	tplFindByMappedCondition = `
+		// FindBy%[1]s adds a new filter to the query that will require that
+		// the %[1]s property is equal to the passed value.
+		func (q *%[2]s) FindBy%[1]s(cond kallax.ScalarCond, v %[3]s) *%[2]s {
+			return q.Where(cond(Schema.%[4]s.%[1]s, %[5]s(&v)))
+		}`
+	// tplFindByNull is the template of the FindBy autogenerated for
+	// kallax.Null properties to find the records in which they are null.
+	tplFindByNull = `
+		// FindBy%[1]sIsNull adds a new filter to the query that will require that
+		// the %[1]s property is null.
+		func (q *%[2]s) FindBy%[1]sIsNull() *%[2]s {
+			return q.Where(kallax.IsNull(Schema.%[4]s.%[1]s))
+		}`
 	// tplFindByID is the template of the FindBy autogenerated for the primary key.
 	// The passed values to the FindBy will be used in an kallax.In condition.
 	tplFindByID = `
@@ -591,25 +1170,131 @@ func (td *TemplateData) GenFindBy(model *Model) string {
 
 func (td *TemplateData) genFindBy(buf *bytes.Buffer, parent *Model, fields []*Field) {
 	for _, f := range fields {
-		switch {
-		case f.Inline():
+		if f.Inline() {
 			td.genFindBy(buf, parent, f.Fields)
-		case f.IsPrimaryKey():
-			writeFindByTpl(buf, parent, f.Name, f, tplFindByID)
-		case isOneToOneRelationship(f) && f.IsInverse():
-			model := td.FindModel(f.TypeSchemaName())
-			writeFindByTpl(buf, parent, f.Name, model.ID, tplFindByFK)
-		case isEqualizable(f):
-			writeFindByTpl(buf, parent, f.Name, f, tplFindByEquality)
-		case isSortable(f):
-			writeFindByTpl(buf, parent, f.Name, f, tplFindByCondition)
-		case isCollection(f):
-			writeFindByTpl(buf, parent, f.Name, f, tplFindByCollection)
+			continue
+		}
+
+		notice, deprecated := f.Deprecation()
+		if !deprecated {
+			td.genFieldFindBy(buf, parent, f)
+			continue
 		}
+
+		var fieldBuf bytes.Buffer
+		td.genFieldFindBy(&fieldBuf, parent, f)
+		buf.WriteString(deprecateFuncs(fieldBuf.String(), notice))
 	}
 }
 
-func writeFindByTpl(buf *bytes.Buffer, parent *Model, name string, f *Field, tpl string) {
+func (td *TemplateData) genFieldFindBy(buf *bytes.Buffer, parent *Model, f *Field) {
+	switch {
+	case f.IsPrimaryKey():
+		writeFindByTpl(buf, parent, f.SchemaName(), f, tplFindByID)
+	case f.Compression() != "":
+		// compressed values cannot be compared in the database
+	case f.IsGenerated():
+		// generated values are queried with their own operators
+	case isXML(f):
+		// xml values have no equality operator in the database
+	case f.isDurationInterval():
+		writeFindByTpl(buf, parent, f.SchemaName(), f, tplFindByMappedCondition, "kallax.DurationInterval")
+	case isOneToOneRelationship(f) && f.IsInverse():
+		model := td.FindModel(f.TypeSchemaName())
+		writeFindByTpl(buf, parent, f.SchemaName(), model.ID, tplFindByFK)
+	case f.IsNull():
+		writeNullFindByTpl(buf, parent, f)
+	case isEqualizable(f) && isMapped(f):
+		writeFindByTpl(buf, parent, f.SchemaName(), f, tplFindByMappedEquality, mappings[f.Type])
+	case isEqualizable(f):
+		writeFindByTpl(buf, parent, f.SchemaName(), f, tplFindByEquality)
+	case isSortable(f):
+		writeFindByTpl(buf, parent, f.SchemaName(), f, tplFindByCondition)
+	case isCollection(f):
+		writeFindByTpl(buf, parent, f.SchemaName(), f, tplFindByCollection)
+	}
+}
+
+// tplFindOneBy is the template of the FindOneBy and MustFindOneBy generated
+// in the stores for the primary key and unique properties.
+const tplFindOneBy = `
+		// FindOneBy%[1]s returns the %[2]s whose %[1]s property is equal to
+		// the passed value. ` + "`ErrNotFound`" + ` is returned if there is no such record.
+		func (s *%[3]s) FindOneBy%[1]s(v %[4]s) (*%[2]s, error) {
+			return s.FindOne(New%[6]s().Where(kallax.Eq(Schema.%[2]s.%[1]s, %[5]s)))
+		}
+
+		// MustFindOneBy%[1]s returns the %[2]s whose %[1]s property is equal
+		// to the passed value. It panics if there is an error or if there is no
+		// such record.
+		func (s *%[3]s) MustFindOneBy%[1]s(v %[4]s) *%[2]s {
+			return s.MustFindOne(New%[6]s().Where(kallax.Eq(Schema.%[2]s.%[1]s, %[5]s)))
+		}`
+
+// GenFindOneBy generates the FindOneBy and MustFindOneBy of the store of the
+// given model, or of its mock store if mock is true, for the properties
+// whose values identify a single record: the primary key, unless it is
+// composite, and the unique properties that can be compared for equality.
+func (td *TemplateData) GenFindOneBy(model *Model, mock bool) string {
+	store := model.StoreName
+	if mock {
+		store = model.MockStoreName
+	}
+
+	var buf bytes.Buffer
+	td.genFindOneBy(&buf, model, store, model.Fields)
+	return buf.String()
+}
+
+func (td *TemplateData) genFindOneBy(buf *bytes.Buffer, model *Model, store string, fields []*Field) {
+	for _, f := range fields {
+		if f.Inline() {
+			td.genFindOneBy(buf, model, store, f.Fields)
+			continue
+		}
+
+		value, ok := findOneByValue(model, f)
+		if !ok {
+			continue
+		}
+
+		typ, ok := f.typeName()
+		if !ok {
+			continue
+		}
+
+		code := fmt.Sprintf(tplFindOneBy, f.SchemaName(), model.Name, store, typ, value, model.QueryName)
+		if notice, deprecated := f.Deprecation(); deprecated {
+			code = deprecateFuncs(code, notice)
+		}
+		buf.WriteString(code)
+	}
+}
+
+// findOneByValue returns the value compared with the column of the given
+// field by FindOneBy, or false if no FindOneBy is generated for the field.
+func findOneByValue(model *Model, f *Field) (string, bool) {
+	switch {
+	case f.IsPrimaryKey():
+		return "v", !model.HasCompositeKey()
+	case !f.IsUnique(), f.Kind == Relationship, f.IsNull(), f.Compression() != "",
+		f.IsGenerated(), isXML(f), isCollection(f):
+		return "", false
+	case f.isDurationInterval():
+		return "kallax.DurationInterval(&v)", true
+	case isMapped(f):
+		return mappings[f.Type] + "(v)", true
+	}
+	return "v", isEqualizable(f) || isSortable(f)
+}
+
+// deprecateFuncs adds a `Deprecated:` paragraph with the given notice to the
+// doc comments of all the functions in the given FindBy code.
+func deprecateFuncs(code, notice string) string {
+	return strings.Replace(code, "\n\t\tfunc ", "\n\t\t//\n\t\t// Deprecated: "+notice+"\n\t\tfunc ", -1)
+}
+
+func writeFindByTpl(buf *bytes.Buffer, parent *Model, name string, f *Field, tpl string, extra ...interface{}) {
 	findableTypeName, ok := f.typeName()
 	if !ok {
 		return
@@ -617,7 +1302,37 @@ func writeFindByTpl(buf *bytes.Buffer, parent *Model, name string, f *Field, tpl
 
 	query := parent.QueryName
 	model := parent.Name
-	buf.WriteString(fmt.Sprintf(tpl, name, query, findableTypeName, model))
+	args := append([]interface{}{name, query, findableTypeName, model}, extra...)
+	buf.WriteString(fmt.Sprintf(tpl, args...))
+}
+
+// writeNullFindByTpl writes the FindBy of a kallax.Null field, which receives
+// a value of the wrapped type, and the FindBy to find the records in which the
+// field is null.
+func writeNullFindByTpl(buf *bytes.Buffer, parent *Model, f *Field) {
+	elem := nullElem(f.Node.Type())
+	args := []interface{}{f.SchemaName(), parent.QueryName, "", parent.Name}
+	if typ, ok := findableTypeName(elem, f.Node.Pkg()); ok {
+		args[2] = typ
+		tpl := tplFindByEquality
+		if isSortableType(elem) {
+			tpl = tplFindByCondition
+		}
+		buf.WriteString(fmt.Sprintf(tpl, args...))
+	}
+
+	buf.WriteString(fmt.Sprintf(tplFindByNull, args...))
+}
+
+// isSortableType returns true if the given type is a number or a time, which
+// can be compared with a kallax.ScalarCond.
+func isSortableType(typ types.Type) bool {
+	if typeName(typ) == "time.Time" {
+		return true
+	}
+
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsNumeric != 0
 }
 
 // findableTypeName returns the correct go type name with its qualifier for
@@ -648,7 +1363,7 @@ func findableTypeName(typ types.Type, pkg *types.Package) (string, bool) {
 }
 
 func isOneToOneRelationship(f *Field) bool {
-	return f.Kind == Relationship && !f.IsOneToManyRelationship()
+	return f.Kind == Relationship && !f.IsOneToManyRelationship() && !f.IsManyToManyRelationship()
 }
 
 // lookupValid returns the first valid type looking into the underlying types of
@@ -725,7 +1440,20 @@ func shortName(pkg *types.Package, typ types.Type) string {
 // isEqualizable returns true if the autogenerated FindBy will use an equal query
 func isEqualizable(f *Field) bool {
 	return f.Type == "string" || f.Type == "bool" ||
-		f.Kind == Interface || f.Type == URL
+		f.Kind == Interface || isMapped(f)
+}
+
+// isXML returns true if the field is stored in a xml column.
+func isXML(f *Field) bool {
+	return f.Kind == Interface && f.Node != nil &&
+		removeTypePrefix(typeName(f.Node.Type())) == xmlType
+}
+
+// isMapped returns true if the field type has a counterpart in kallax types
+// that needs to be used to store it
+func isMapped(f *Field) bool {
+	_, ok := mappings[f.Type]
+	return ok && f.Kind == Basic
 }
 
 // isSortable returns true if the autogenerated FindBy will use a kallax.ScalarCond
@@ -208,6 +208,92 @@ func (s *TemplateSuite) TestGenModelColumns() {
 	s.Equal(expectedColumns, result)
 }
 
+func (s *TemplateSuite) TestGenDefaultColumns() {
+	s.processSource(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Timestamps struct {
+		CreatedAt int64 ` + "`default:\"0\"`" + `
+	}
+
+	type Foo struct {
+		kallax.Model
+		ID         int64 ` + "`pk:\"autoincr\"`" + `
+		Status     string ` + "`default:\"'draft'\"`" + `
+		Name       string
+		Timestamps ` + "`kallax:\",inline\"`" + `
+	}
+
+	type Bar struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+	}
+	`)
+
+	s.Equal(
+		`kallax.NewSchemaField("status"), kallax.NewSchemaField("created_at")`,
+		s.td.GenDefaultColumns(findModel(s.td.Package, "Foo")),
+	)
+	s.Equal("", s.td.GenDefaultColumns(findModel(s.td.Package, "Bar")))
+
+	var buf bytes.Buffer
+	s.NoError(Base.Execute(&buf, s.td.Package))
+	s.Contains(buf.String(), `.WithDefaults(kallax.NewSchemaField("status"), kallax.NewSchemaField("created_at")),`)
+}
+
+const schemaInfoTpl = `
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Owner struct {
+		kallax.Model
+		ID    int64 ` + "`pk:\"autoincr\"`" + `
+		Pets  []*Pet
+	}
+
+	type Timestamps struct {
+		CreatedAt int64
+	}
+
+	type Pet struct {
+		kallax.Model
+		ID         int64 ` + "`pk:\"autoincr\"`" + `
+		Name       *string
+		Timestamps ` + "`kallax:\",inline\"`" + `
+		Toy        *Toy ` + "`fk:\",inverse\"`" + `
+	}
+
+	type Toy struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+	}
+`
+
+const expectedSchemaInfo = `&kallax.SchemaInfo{
+Model: "Pet",
+Package: "foo",
+Schema: Schema.Pet.BaseSchema,
+Columns: []kallax.ColumnInfo{
+{Name: "id", Field: "ID", Type: "serial", PrimaryKey: true, NotNull: true},
+{Name: "name", Field: "Name", Type: "text", PrimaryKey: false, NotNull: false},
+{Name: "created_at", Field: "CreatedAt", Type: "bigint", PrimaryKey: false, NotNull: true},
+{Name: "toy_id", Field: "Toy", Type: "bigint", PrimaryKey: false, NotNull: false},
+{Name: "owner_id", Type: "bigint"},
+},
+Relationships: []kallax.RelationshipInfo{
+{Field: "Toy", Type: kallax.OneToOne, Schema: Schema.Toy.BaseSchema, ForeignKey: "toy_id", Inverse: true},
+},
+}`
+
+func (s *TemplateSuite) TestGenSchemaInfo() {
+	s.processSource(schemaInfoTpl)
+	m := findModel(s.td.Package, "Pet")
+	s.Equal(expectedSchemaInfo, s.td.GenSchemaInfo(m))
+}
+
 const jsonBaseTpl = `
 	package fixture
 
@@ -441,6 +527,311 @@ func (s *TemplateSuite) TestGenTimeTruncations() {
 	s.Equal(expectedTimeTruncations, s.td.GenTimeTruncations(m))
 }
 
+func (s *TemplateSuite) TestGenIDGeneration() {
+	s.processSource(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Foo struct {
+		kallax.Model
+		ID kallax.UUID ` + "`pk:\"\" uuid:\"v4\"`" + `
+	}
+
+	type Bar struct {
+		kallax.Model
+		ID kallax.UUID ` + "`pk:\"\" uuid:\"v7\"`" + `
+	}
+
+	type Baz struct {
+		kallax.Model
+		ID kallax.UUID ` + "`pk:\"\"`" + `
+	}
+	`)
+
+	s.Equal(`if record.GetID().IsEmpty() {
+record.ID = kallax.NewUUIDv4()
+}
+`, s.td.GenIDGeneration(findModel(s.td.Package, "Foo")))
+
+	s.Equal(`if record.GetID().IsEmpty() {
+record.ID = kallax.NewUUIDv7()
+}
+`, s.td.GenIDGeneration(findModel(s.td.Package, "Bar")))
+
+	s.Equal("", s.td.GenIDGeneration(findModel(s.td.Package, "Baz")))
+}
+
+const expectedGoogleUUIDAddresses = `case "id":
+return (*kallax.UUID)(&r.ID), nil
+case "token":
+return &r.Token, nil
+case "rel_id":
+return types.Nullable(kallax.VirtualColumn("rel_id", r, new(kallax.UUID))), nil
+`
+
+func (s *TemplateSuite) TestGoogleUUID() {
+	s.processSource(`
+	package fixture
+
+	import (
+		"github.com/google/uuid"
+		"gopkg.in/src-d/go-kallax.v1"
+	)
+
+	type Rel struct {
+		kallax.Model
+		ID uuid.UUID ` + "`pk:\"\" uuid:\"v7\"`" + `
+	}
+
+	type Foo struct {
+		kallax.Model
+		ID uuid.UUID ` + "`pk:\"\" uuid:\"v4\"`" + `
+		Token uuid.UUID
+		Rel *Rel ` + "`fk:\",inverse\"`" + `
+	}
+	`)
+
+	foo := findModel(s.td.Package, "Foo")
+	s.Equal(expectedGoogleUUIDAddresses, s.td.GenColumnAddresses(foo))
+	s.Equal(`if record.GetID().IsEmpty() {
+record.ID = uuid.UUID(kallax.NewUUIDv4())
+}
+`, s.td.GenIDGeneration(foo))
+
+	s.Equal(`if record.GetID().IsEmpty() {
+record.ID = uuid.UUID(kallax.NewUUIDv7())
+}
+`, s.td.GenIDGeneration(findModel(s.td.Package, "Rel")))
+}
+
+func (s *TemplateSuite) TestExecuteUUIDImports() {
+	s.processSource(`
+	package fixture
+
+	import (
+		"github.com/google/uuid"
+		"gopkg.in/src-d/go-kallax.v1"
+	)
+
+	type Foo struct {
+		kallax.Model
+		ID uuid.UUID ` + "`pk:\"\" uuid:\"v4\"`" + `
+		Tokens []uuid.UUID
+	}
+	`)
+
+	s.Equal("\"github.com/google/uuid\"\n", s.td.GenUUIDImports())
+	for _, tpl := range []*Template{Base, Factory, Mock} {
+		var buf bytes.Buffer
+		s.NoError(tpl.Execute(&buf, s.td.Package))
+		s.Contains(buf.String(), "\t\"github.com/google/uuid\"\n")
+		s.NotContains(buf.String(), "github.com/gofrs/uuid")
+		s.NotContains(buf.String(), "github.com/satori/go.uuid")
+	}
+}
+
+func (s *TemplateSuite) TestGenFindBy_Compressed() {
+	s.processSource(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Foo struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Name string
+		Body string ` + "`compress:\"gzip\"`" + `
+	}
+	`)
+
+	findBys := s.td.GenFindBy(findModel(s.td.Package, "Foo"))
+	s.Contains(findBys, "FindByName(")
+	s.NotContains(findBys, "FindByBody(")
+}
+
+func (s *TemplateSuite) TestGenFindOneBy() {
+	s.processSource(`
+	package fixture
+
+	import (
+		"net/url"
+
+		"gopkg.in/src-d/go-kallax.v1"
+	)
+
+	type Foo struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Email string ` + "`unique:\"true\"`" + `
+		Number int64 ` + "`unique:\"true\"`" + `
+		Site url.URL ` + "`unique:\"true\"`" + `
+		Tags []string ` + "`unique:\"true\"`" + `
+		Name string
+	}
+
+	type Bar struct {
+		kallax.Model ` + "`pk:\"foo_id,position\"`" + `
+		FooID int64
+		Position int64
+	}
+	`)
+
+	findOneBys := s.td.GenFindOneBy(findModel(s.td.Package, "Foo"), false)
+	s.Contains(findOneBys, "func (s *FooStore) FindOneByID(v int64) (*Foo, error) {\n\t\t\treturn s.FindOne(NewFooQuery().Where(kallax.Eq(Schema.Foo.ID, v)))")
+	s.Contains(findOneBys, "func (s *FooStore) MustFindOneByEmail(v string) *Foo {\n\t\t\treturn s.MustFindOne(NewFooQuery().Where(kallax.Eq(Schema.Foo.Email, v)))")
+	s.Contains(findOneBys, "func (s *FooStore) FindOneByNumber(v int64) (*Foo, error) {")
+	s.Contains(findOneBys, "kallax.Eq(Schema.Foo.Site, types.URL(v))")
+	s.NotContains(findOneBys, "FindOneByTags(")
+	s.NotContains(findOneBys, "FindOneByName(")
+
+	s.Contains(s.td.GenFindOneBy(findModel(s.td.Package, "Foo"), true), "func (s *MockFooStore) FindOneByID(v int64) (*Foo, error) {")
+	s.Equal("", s.td.GenFindOneBy(findModel(s.td.Package, "Bar"), false))
+}
+
+func (s *TemplateSuite) TestGenFindBy_Duration() {
+	s.processSource(`
+	package fixture
+
+	import (
+		"time"
+
+		"gopkg.in/src-d/go-kallax.v1"
+	)
+
+	type Foo struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Timeout time.Duration
+		Window time.Duration ` + "`duration:\"interval\"`" + `
+	}
+	`)
+
+	findBys := s.td.GenFindBy(findModel(s.td.Package, "Foo"))
+	s.Contains(findBys, "FindByTimeout(cond kallax.ScalarCond, v time.Duration) *FooQuery {\n\t\t\treturn q.Where(cond(Schema.Foo.Timeout, v))")
+	s.Contains(findBys, "FindByWindow(cond kallax.ScalarCond, v time.Duration) *FooQuery {\n\t\t\treturn q.Where(cond(Schema.Foo.Window, kallax.DurationInterval(&v)))")
+}
+
+func (s *TemplateSuite) TestGenFindBy_XML() {
+	s.processSource(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Foo struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Name string
+		Payload kallax.XML
+		Backup *kallax.XML
+	}
+	`)
+
+	findBys := s.td.GenFindBy(findModel(s.td.Package, "Foo"))
+	s.Contains(findBys, "FindByName(")
+	s.NotContains(findBys, "FindByPayload(")
+	s.NotContains(findBys, "FindByBackup(")
+}
+
+func (s *TemplateSuite) TestGenColumnValues_Generated() {
+	s.processSource(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Foo struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Title string
+		Search kallax.TSVector ` + "`tsvector:\"title\"`" + `
+	}
+	`)
+
+	m := findModel(s.td.Package, "Foo")
+	s.Contains(s.td.GenColumnValues(m), "case \"search\":\nreturn nil, kallax.ErrGeneratedColumn\n")
+	s.Contains(s.td.GenColumnAddresses(m), "case \"search\":\n")
+	s.NotContains(s.td.GenFindBy(m), "FindBySearch(")
+	s.Contains(s.td.GenSchemaInit(m), "Search:kallax.NewTSVectorSchemaField(\"search\", \"simple\"),\n")
+}
+
+func (s *TemplateSuite) TestGenJSONSchemas() {
+	s.processSource(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Foo struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Settings map[string]interface{}
+	}
+	`)
+
+	m := findModel(s.td.Package, "Foo")
+	s.Equal("", s.td.GenJSONSchemas(m))
+
+	findField(m, "Settings").JSONSchema = `{"type":"object"}`
+	code := s.td.GenJSONSchemas(m)
+	s.Contains(code, "var jsonSchemaFooSettings = types.MustCompileJSONSchema(\"{\\\"type\\\":\\\"object\\\"}\")\n")
+	s.Contains(code, "func (r *Foo) ValidateJSONSchemas() error {\n")
+	s.Contains(code, "if v, err := r.Value(\"settings\"); err != nil {\nreturn err\n} else if err := jsonSchemaFooSettings.Validate(v); err != nil {\n")
+}
+
+func (s *TemplateSuite) TestGenValidate() {
+	s.processSource(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Foo struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Name string ` + "`validate:\"required,max=255\"`" + `
+		Email *string ` + "`validate:\"omitempty,email\"`" + `
+		Role string ` + "`validate:\"oneof=admin user\"`" + `
+		Bio string
+	}
+
+	type Bar struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+	}
+	`)
+
+	s.Equal("", s.td.GenValidate(findModel(s.td.Package, "Bar")))
+
+	code := s.td.GenValidate(findModel(s.td.Package, "Foo"))
+	s.Contains(code, "func (r *Foo) Validate() error {\nerrs := kallax.NewValidationError(\"Foo\")\n")
+	s.Contains(code, "errs.Check(\"Name\", r.Name, kallax.RequiredRule(), kallax.MaxRule(255))\n")
+	s.Contains(code, "errs.Check(\"Email\", r.Email, kallax.OmitEmptyRule(), kallax.EmailRule())\n")
+	s.Contains(code, "errs.Check(\"Role\", r.Role, kallax.OneOfRule(\"admin\", \"user\"))\n")
+	s.NotContains(code, "Bio")
+	s.Contains(code, "return errs.Err()\n}\n")
+}
+
+func (s *TemplateSuite) TestGenEnums() {
+	s.processSource(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Status string
+
+	type Foo struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Status Status ` + "`enum:\"status_type,active,temporarily-disabled\"`" + `
+		Color *string ` + "`enum:\"color_type,red\"`" + `
+	}
+	`)
+
+	m := findModel(s.td.Package, "Foo")
+	code := s.td.GenEnums(m)
+	s.Contains(code, "const (\nFooStatusActive Status = \"active\"\nFooStatusTemporarilyDisabled Status = \"temporarily-disabled\"\n)\n")
+	s.Contains(code, "const (\nFooColorRed string = \"red\"\n)\n")
+	s.Contains(s.td.GenValidate(m), "errs.Check(\"Status\", r.Status, kallax.OneOfRule(\"active\", \"temporarily-disabled\"))\n")
+}
+
 func (s *TemplateSuite) TestExecute() {
 	s.processSource(baseTpl)
 	var buf bytes.Buffer
@@ -448,6 +839,425 @@ func (s *TemplateSuite) TestExecute() {
 	s.Nil(err)
 }
 
+func (s *TemplateSuite) TestExecuteDeprecated() {
+	s.processSource(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Foo struct {
+		kallax.Model ` + "`deprecated:\"\"`" + `
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+	}
+
+	type Bar struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Name string
+		Nick string ` + "`deprecated:\"use Name instead.\"`" + `
+	}
+	`)
+
+	var buf bytes.Buffer
+	s.NoError(Base.Execute(&buf, s.td.Package))
+	code := buf.String()
+	s.Contains(code, "// in the database.\n//\n// Deprecated: the model Foo will be removed.\ntype FooStore struct {\n")
+	s.Contains(code, "// using a SQL database.\n//\n// Deprecated: the model Foo will be removed.\nfunc NewFooStore(")
+	s.Contains(code, "// entity.\n//\n// Deprecated: the model Foo will be removed.\ntype FooQuery struct {\n")
+	s.Contains(code, "//\n// Deprecated: the model Foo will be removed.\nfunc NewFooQuery() *FooQuery {\n")
+	s.Contains(code, "\t// Deprecated: the model Foo will be removed.\n\tFoo *schemaFoo\n")
+	s.Contains(code, "\t// Deprecated: use Name instead.\n\tNick kallax.SchemaField\n")
+	s.Contains(code, "// the Nick property is equal to the passed value.\n//\n// Deprecated: use Name instead.\nfunc (q *BarQuery) FindByNick(")
+	s.NotContains(code, "Deprecated: the model Bar")
+	s.Equal(2, strings.Count(code, "Deprecated: use Name instead."))
+}
+
+func (s *TemplateSuite) TestExecuteCompositeKey() {
+	s.processSource(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Order struct {
+		kallax.Model ` + "`pk:\"tenant_id,order_id\"`" + `
+		TenantID int64
+		OrderID kallax.ULID
+		Name string
+	}
+
+	type Foo struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+	}
+	`)
+
+	var buf bytes.Buffer
+	s.NoError(Base.Execute(&buf, s.td.Package))
+	code := buf.String()
+	s.Contains(code, "func (r *Order) GetPrimaryKey() []interface{} {\n\treturn []interface{}{(*kallax.NumericID)(&r.TenantID), (*kallax.ULID)(&r.OrderID)}\n}\n")
+	s.Contains(code, "func (s *OrderStore) FindByPrimaryKey(tenantID int64, orderID kallax.ULID) (*Order, error) {\n\treturn s.FindOne(NewOrderQuery().Where(kallax.And(kallax.Eq(Schema.Order.TenantID, tenantID), kallax.Eq(Schema.Order.OrderID, orderID))))\n}\n")
+	s.Contains(code, ").WithPrimaryKey(kallax.NewSchemaField(\"tenant_id\"), kallax.NewSchemaField(\"order_id\")),\n")
+	s.Contains(code, "func (s *FooStore) FindByPrimaryKey(id int64) (*Foo, error) {\n\treturn s.FindOne(NewFooQuery().Where(kallax.Eq(Schema.Foo.ID, id)))\n}\n")
+	s.NotContains(code, "func (r *Foo) GetPrimaryKey()")
+	s.Equal(1, strings.Count(code, ".WithPrimaryKey("))
+}
+
+func (s *TemplateSuite) TestExecuteManyToMany() {
+	s.processSource(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Post struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Tags []Tag ` + "`through:\"post_tags\"`" + `
+	}
+
+	type Tag struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+	}
+	`)
+
+	var buf bytes.Buffer
+	s.NoError(Base.Execute(&buf, s.td.Package))
+	code := buf.String()
+	s.Contains(code, "\"Tags\": kallax.NewJoinTableForeignKey(\"post_tags\", \"post_id\", \"tag_id\"),\n")
+	s.Contains(code, "func (q *PostQuery) WithTags(opts ...kallax.RelationOption) *PostQuery {\n\tq.AddRelation(Schema.Tag.BaseSchema, \"Tags\", kallax.ManyToMany, opts...)\n")
+	s.Contains(code, "func (s *PostStore) AddTags(record *Post, added ...Tag) error {\n")
+	s.Contains(code, "\t\trelated[i] = &added[i]\n")
+	s.Contains(code, "func (s *PostStore) RemoveTags(record *Post, removed ...Tag) error {\n")
+	s.Contains(code, "func (s *PostStore) ClearTags(record *Post) error {\n")
+	s.Contains(code, "{Field: \"Tags\", Type: kallax.ManyToMany, Schema: Schema.Tag.BaseSchema, ForeignKey: \"post_id\", Through: \"post_tags\", References: \"tag_id\"},\n")
+	s.NotContains(code, "func (s *PostStore) relationshipRecords(")
+}
+
+func (s *TemplateSuite) TestParamName() {
+	cases := map[string]string{
+		"ID":       "id",
+		"TenantID": "tenantID",
+		"IDNumber": "idNumber",
+		"Type":     "typeValue",
+		"S":        "sValue",
+		"name":     "name",
+	}
+
+	for field, expected := range cases {
+		s.Equal(expected, paramName(field), field)
+	}
+}
+
+func (s *TemplateSuite) TestExecuteSQLMock() {
+	s.processSource(`
+	package fixture
+
+	import (
+		"time"
+
+		"gopkg.in/src-d/go-kallax.v1"
+	)
+
+	type Foo struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Title string
+	}
+
+	type Bar struct {
+		kallax.Model
+		ID kallax.ULID ` + "`pk:\"\"`" + `
+		DeletedAt *time.Time ` + "`kallax:\",softdelete\"`" + `
+	}
+	`)
+
+	var buf bytes.Buffer
+	s.NoError(SQLMock.Execute(&buf, s.td.Package))
+	code := buf.String()
+	s.Contains(code, "func ExpectFooInsert(mock sqlmock.Sqlmock, record *Foo) *sqlmock.ExpectedQuery {\n")
+	s.Contains(code, `WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))`)
+	s.Contains(code, "func ExpectBarInsert(mock sqlmock.Sqlmock, record *Bar) *sqlmock.ExpectedExec {\n")
+	s.Contains(code, "kallax.InsertStatement(Schema.Bar.BaseSchema, record)")
+	s.Contains(code, "func ExpectFooUpdate(mock sqlmock.Sqlmock, record *Foo, cols ...kallax.SchemaField) *sqlmock.ExpectedExec {\n")
+	s.Contains(code, "kallax.UpdateStatement(Schema.Foo.BaseSchema, record, cols...)")
+	s.Contains(code, "func ExpectBarDelete(mock sqlmock.Sqlmock, record *Bar) *sqlmock.ExpectedExec {\n")
+	s.Contains(code, "kallax.SoftDeleteStatement(Schema.Bar.BaseSchema, record, time.Time{})")
+	s.Contains(code, "values[0] = sqlmock.AnyArg()")
+	s.Contains(code, "func ExpectFooDelete(mock sqlmock.Sqlmock, record *Foo) *sqlmock.ExpectedExec {\n\treturn ExpectFooHardDelete(mock, record)\n}")
+	s.Contains(code, "func ExpectBarHardDelete(mock sqlmock.Sqlmock, record *Bar) *sqlmock.ExpectedExec {\n")
+	s.Contains(code, "kallax.DeleteStatement(Schema.Bar.BaseSchema, record)")
+}
+
+func (s *TemplateSuite) TestExecuteMock() {
+	s.processSource(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Foo struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Title string
+		Bar *Bar ` + "`fk:\",inverse\"`" + `
+	}
+
+	type Bar struct {
+		kallax.Model
+		ID kallax.ULID ` + "`pk:\"\"`" + `
+	}
+	`)
+
+	var buf bytes.Buffer
+	s.NoError(Mock.Execute(&buf, s.td.Package))
+	code := buf.String()
+	s.Contains(code, "type MockFooStore struct {\n\t*kallax.MockStore\n}")
+	s.Contains(code, "func NewMockBarStore(mock *kallax.MockStore) *MockBarStore {\n")
+	s.Contains(code, "func (s *MockFooStore) Insert(record *Foo) error {\n")
+	s.Contains(code, `record.AddVirtualColumn("bar_id", record.Bar.GetID())`)
+	s.Contains(code, "s.Insert(Schema.Foo.BaseSchema, record)")
+	s.Contains(code, "func (s *MockBarStore) Find(q *BarQuery) (*BarResultSet, error) {\n")
+	s.Contains(code, "func (s *MockBarStore) Transaction(callback func(*MockBarStore) error) error {\n")
+	s.NotContains(code, "func (s *MockBarStore) setForeignKeys")
+}
+
+func (s *TemplateSuite) TestGenFactoryRecord() {
+	s.processSource(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Email string
+
+	type Address struct {
+		Street string
+		Floor int
+	}
+
+	type Foo struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Email Email ` + "`validate:\"required,email\"`" + `
+		Website string ` + "`validate:\"url\"`" + `
+		Role string ` + "`validate:\"oneof=admin user\"`" + `
+		Status string ` + "`enum:\"status_type,active,banned\"`" + `
+		Number int64 ` + "`unique:\"true\"`" + `
+		Count int
+		Nick *string
+		Billing Address ` + "`prefix:\"\"`" + `
+		Bar *Bar ` + "`fk:\",inverse\"`" + `
+	}
+
+	type Bar struct {
+		kallax.Model
+		ID kallax.ULID ` + "`pk:\"\"`" + `
+	}
+	`)
+
+	s.Equal(
+		"record.Email = Email(fmt.Sprintf(\"email-%d@example.com\", n))\n"+
+			"record.Website = fmt.Sprintf(\"https://example.com/website-%d\", n)\n"+
+			"record.Role = \"admin\"\n"+
+			"record.Status = FooStatusActive\n"+
+			"record.Number = int64(n)\n"+
+			"record.Billing.Street = fmt.Sprintf(\"billing_street-%d\", n)\n",
+		s.td.GenFactoryRecord(findModel(s.td.Package, "Foo")),
+	)
+	s.Equal("record.ID = kallax.NewULID()\n", s.td.GenFactoryRecord(findModel(s.td.Package, "Bar")))
+
+	withs := s.td.GenFactoryWiths(findModel(s.td.Package, "Foo"))
+	s.NotContains(withs, "WithID(")
+	s.Contains(withs, "func (f *FooFactory) WithEmail(v Email) *FooFactory {\n")
+	s.Contains(withs, "func (f *FooFactory) WithNick(v *string) *FooFactory {\n")
+	s.Contains(withs, "func (f *FooFactory) WithBillingStreet(v string) *FooFactory {\n")
+	s.Contains(withs, "record.Billing.Street = v\n")
+	s.Contains(withs, "func (f *FooFactory) WithBar(v *Bar) *FooFactory {\n")
+}
+
+func (s *TemplateSuite) TestExecuteFactory() {
+	s.processSource(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Foo struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Title string
+	}
+	`)
+
+	var buf bytes.Buffer
+	s.NoError(Factory.Execute(&buf, s.td.Package))
+	code := buf.String()
+	s.Contains(code, "func NewFooFactory() *FooFactory {\n")
+	s.Contains(code, "func (f *FooFactory) WithTitle(v string) *FooFactory {\n")
+	s.Contains(code, "record.Title = fmt.Sprintf(\"title-%d\", n)\n")
+	s.Contains(code, "func (f *FooFactory) CreateN(store interface{ Insert(*Foo) error }, count int) ([]*Foo, error) {\n")
+}
+
+func (s *TemplateSuite) TestGenBenchmarkRecord() {
+	s.processSource(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Email string
+
+	type Foo struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Email Email ` + "`unique:\"true\"`" + `
+		Name string ` + "`unique:\"true\"`" + `
+		Number int64 ` + "`unique:\"true\"`" + `
+		Title string
+		Bar Bar ` + "`fk:\",inverse\"`" + `
+		Bars []*Bar
+	}
+
+	type Bar struct {
+		kallax.Model
+		ID kallax.ULID ` + "`pk:\"\"`" + `
+	}
+
+	type Qux struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"\"`" + `
+	}
+	`)
+
+	s.Equal(
+		"record.Email = Email(fmt.Sprintf(\"email-%d\", i))\n"+
+			"record.Name = fmt.Sprintf(\"name-%d\", i)\n"+
+			"record.Number = int64(i)\n"+
+			"record.Bar = *newBenchmarkBar(i)\n",
+		s.td.GenBenchmarkRecord(findModel(s.td.Package, "Foo")),
+	)
+	s.Equal("record.ID = kallax.NewULID()\n", s.td.GenBenchmarkRecord(findModel(s.td.Package, "Bar")))
+	s.Equal("record.ID = int64(i + 1)\n", s.td.GenBenchmarkRecord(findModel(s.td.Package, "Qux")))
+}
+
+func (s *TemplateSuite) TestExecuteBenchmark() {
+	s.processSource(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Foo struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Title string
+		Bars []*Bar
+	}
+
+	type Bar struct {
+		kallax.Model
+		ID kallax.ULID ` + "`pk:\"\"`" + `
+	}
+	`)
+
+	var buf bytes.Buffer
+	s.NoError(Benchmark.Execute(&buf, s.td.Package))
+	code := buf.String()
+	s.Contains(code, "CREATE TABLE foo (\\n\\tid serial NOT NULL PRIMARY KEY")
+	s.NotContains(code, "BEGIN;")
+	s.Contains(code, "func newBenchmarkFoo(i int) *Foo {\n")
+	s.Contains(code, "func BenchmarkFooInsert(b *testing.B) {\n")
+	s.Contains(code, "func BenchmarkFooFind(b *testing.B) {\n")
+	s.Contains(code, "func BenchmarkFooUpdate(b *testing.B) {\n")
+	s.Contains(code, "func BenchmarkFooFindWithBars(b *testing.B) {\n")
+	s.Contains(code, "s.FindAll(NewFooQuery().WithBars(nil))")
+	s.Contains(code, "func BenchmarkBarFind(b *testing.B) {\n")
+	s.NotContains(code, "func BenchmarkBarFindWith")
+}
+
+const grpcTpl = `
+	package fixture
+
+	import (
+		"time"
+
+		"gopkg.in/src-d/go-kallax.v1"
+	)
+
+	type Email string
+
+	type Foo struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+		Email Email
+		Count *int
+		Tags []string
+		Raw []byte
+		CreatedAt time.Time
+		Props map[string]interface{}
+		Bars []*Bar
+	}
+
+	type Bar struct {
+		kallax.Model
+		ID kallax.ULID ` + "`pk:\"\"`" + `
+		Name string
+	}
+`
+
+func (s *TemplateSuite) TestExecuteProto() {
+	s.processSource(grpcTpl)
+
+	var buf bytes.Buffer
+	s.NoError(Proto.Execute(&buf, s.td.Package))
+	code := buf.String()
+	s.Contains(code, "package fixture;\n")
+	s.Contains(code, `option go_package = "foo/kallaxpb";`)
+	s.Contains(code, `import "google/protobuf/timestamp.proto";`)
+	s.Contains(code, "message Foo {\n"+
+		"  int64 id = 1;\n"+
+		"  string email = 2;\n"+
+		"  optional int64 count = 3;\n"+
+		"  repeated string tags = 4;\n"+
+		"  bytes raw = 5;\n"+
+		"  google.protobuf.Timestamp created_at = 6;\n"+
+		"}\n")
+	s.Contains(code, "message FooFilter {\n"+
+		"  optional int64 id = 1;\n"+
+		"  optional string email = 2;\n"+
+		"  optional int64 count = 3;\n"+
+		"  google.protobuf.Timestamp created_at = 6;\n"+
+		"}\n")
+	s.Contains(code, "message GetBarRequest {\n  string id = 1;\n}\n")
+	s.Contains(code, "service FooService {\n")
+	s.Contains(code, "  rpc ListFoo(ListFooRequest) returns (ListFooResponse);\n")
+	s.Contains(code, "  rpc DeleteBar(DeleteBarRequest) returns (google.protobuf.Empty);\n")
+}
+
+func (s *TemplateSuite) TestExecuteGRPC() {
+	s.processSource(grpcTpl)
+
+	var buf bytes.Buffer
+	s.NoError(GRPC.Execute(&buf, s.td.Package))
+	code := buf.String()
+	s.Contains(code, `kallaxpb "foo/kallaxpb"`)
+	s.Contains(code, "kallaxpb.RegisterFooServiceServer(s, NewFooServiceServer(&FooStore{store}))\n")
+	s.Contains(code, "func (s *FooServiceServer) ListFoo(ctx context.Context, req *kallaxpb.ListFooRequest) (*kallaxpb.ListFooResponse, error) {\n")
+	s.Contains(code, "q.Where(kallax.Eq(Schema.Foo.Email, Email(*filter.Email)))\n")
+	s.Contains(code, "s.Store.Update(record, Schema.Foo.Email, Schema.Foo.Count, Schema.Foo.Tags, Schema.Foo.Raw, Schema.Foo.CreatedAt)")
+	s.Contains(code, "func (s *BarServiceServer) find(pbID string) (*Bar, error) {\n"+
+		"\tvar id kallax.ULID\n"+
+		"\tif err := id.Scan(pbID); err != nil {\n")
+	s.Contains(code, "\tm.Email = string(record.Email)\n"+
+		"\tif record.Count != nil {\n"+
+		"\t\tv := int64(*record.Count)\n"+
+		"\t\tm.Count = &v\n"+
+		"\t}\n"+
+		"\tm.Tags = record.Tags\n")
+	s.Contains(code, "\trecord.Count = nil\n"+
+		"\tif m.Count != nil {\n"+
+		"\t\tv := int(*m.Count)\n"+
+		"\t\trecord.Count = &v\n"+
+		"\t}\n")
+	s.NotContains(code, "record.ID = m.Id")
+	s.NotContains(code, "Props")
+}
+
 func TestTemplate(t *testing.T) {
 	suite.Run(t, new(TemplateSuite))
 }
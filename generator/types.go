@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"go/types"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"unicode"
@@ -112,14 +113,22 @@ var specialTypes = map[string]string{
 	"gopkg.in/src-d/go-kallax.v1.NumericID": "kallax.NumericID",
 	"github.com/satori/go.uuid.UUID":        "kallax.UUID",
 	"github.com/gofrs/uuid.UUID":            "kallax.UUID",
+	"github.com/google/uuid.UUID":           "kallax.UUID",
 	"net/url.URL":                           "url.URL",
+	"net.IP":                                "net.IP",
+	"net.IPNet":                             "net.IPNet",
+	"net.HardwareAddr":                      "net.HardwareAddr",
 	"time.Time":                             "time.Time",
+	"time.Duration":                         "time.Duration",
 }
 
 // mappings defines the mapping between specific types and their counterpart
 // in kallax types
 var mappings = map[string]string{
-	"url.URL": "types.URL",
+	"url.URL":          "types.URL",
+	"net.IP":           "types.IP",
+	"net.IPNet":        "types.IPNet",
+	"net.HardwareAddr": "types.HardwareAddr",
 }
 
 // Package is the representation of a scanned package.
@@ -157,7 +166,15 @@ func (p *Package) FindModel(name string) *Model {
 func (p *Package) addMissingRelationships() error {
 	for _, m := range p.Models {
 		for _, f := range m.Fields {
-			if f.Kind == Relationship && !f.IsInverse() {
+			if err := p.checkRelationshipKey(f); err != nil {
+				return err
+			}
+
+			if f.IsManyToManyRelationship() {
+				if p.FindModel(f.TypeSchemaName()) == nil {
+					return fmt.Errorf("kallax: cannot find model %s of the many to many relationship %s of model %s", f.TypeSchemaName(), f.Name, f.Model.Name)
+				}
+			} else if f.Kind == Relationship && !f.IsInverse() {
 				if err := p.trySetFK(f.TypeSchemaName(), f); err != nil {
 					return err
 				}
@@ -168,6 +185,30 @@ func (p *Package) addMissingRelationships() error {
 	return nil
 }
 
+// checkRelationshipKey returns an error if the given field is a relationship
+// whose foreign key references a model with a composite primary key, as
+// foreign keys can only reference a single column. The join tables of many
+// to many relationships reference the models on both sides.
+func (p *Package) checkRelationshipKey(f *Field) error {
+	if f.Kind != Relationship {
+		return nil
+	}
+
+	referenced := []*Model{f.Model}
+	if f.IsInverse() {
+		referenced = []*Model{p.FindModel(f.TypeSchemaName())}
+	} else if f.IsManyToManyRelationship() {
+		referenced = append(referenced, p.FindModel(f.TypeSchemaName()))
+	}
+
+	for _, m := range referenced {
+		if m != nil && m.HasCompositeKey() {
+			return fmt.Errorf("kallax: relationship %s of model %s references model %s, which has a composite primary key, but foreign keys can only reference single-column primary keys", f.Name, f.Model.Name, m.Name)
+		}
+	}
+	return nil
+}
+
 func (p *Package) trySetFK(model string, fk *Field) error {
 	m := p.FindModel(model)
 	if m == nil {
@@ -214,6 +255,13 @@ const (
 	QueryNamePattern = "%sQuery"
 	// ResultSetNamePattern is the pattern used to name result sets.
 	ResultSetNamePattern = "%sResultSet"
+	// PageNamePattern is the pattern used to name pages.
+	PageNamePattern = "%sPage"
+	// AggregateNamePattern is the pattern used to name the groups of
+	// aggregation queries.
+	AggregateNamePattern = "%sAggregate"
+	// MockStoreNamePattern is the pattern used to name mock stores.
+	MockStoreNamePattern = "Mock%sStore"
 )
 
 // Model is the representation of an user-defined model.
@@ -226,6 +274,13 @@ type Model struct {
 	QueryName string
 	// ResultSetName is the name of the result set for this model.
 	ResultSetName string
+	// PageName is the name of the page of keyset pagination for this model.
+	PageName string
+	// AggregateName is the name of the group of aggregation queries for this
+	// model.
+	AggregateName string
+	// MockStoreName is the name of the in-memory mock store for this model.
+	MockStoreName string
 
 	// Table is the name of the table, which will be extracted from the `table`
 	// struct tag of the kallax.Model field in the model.
@@ -242,8 +297,46 @@ type Model struct {
 	ImplicitFKs []ImplicitFK
 	// ID contains the identifier field of the model.
 	ID *Field
+	// PrimaryKey contains the fields of the primary key of the model, which
+	// are more than one if the primary key is composite, that is, defined
+	// with more than one field name in the `pk` struct tag of the
+	// kallax.Model field. The first of them is always ID.
+	PrimaryKey []*Field
 	// Events contains the list of events implemented by the model.
 	Events Events
+	// Audit reports whether the changes of the records are recorded in an
+	// audit table, which is enabled with the `audit:"true"` or `audit:""`
+	// struct tag of the kallax.Model field in the model.
+	Audit bool
+	// Versioned reports whether the versions of the records are kept in a
+	// history table, which is enabled with the `versioned:"true"` struct tag
+	// of the kallax.Model field in the model.
+	Versioned bool
+	// Notify reports whether the changes of the records are notified to the
+	// listeners of the changes channel of their table by a trigger, which is
+	// enabled with the `notify:"true"` struct tag of the kallax.Model field
+	// in the model.
+	Notify bool
+	// Deprecated reports whether the model is deprecated, which is set with
+	// the `deprecated` struct tag of the kallax.Model field in the model.
+	// Its table is kept working, but it is not created by new migrations.
+	Deprecated bool
+	// DeprecationNotice is the notice of the generated `Deprecated:`
+	// comments of a deprecated model, which is the value of its `deprecated`
+	// struct tag.
+	DeprecationNotice string
+	// Indexes are the indexes of the table declared with the `index` struct
+	// tag of the kallax.Model field in the model, which is a list of indexes
+	// separated by semicolons, each one with its name followed by the
+	// columns it indexes, separated by commas. For example,
+	// `index:"idx_email_tenant,email,tenant_id"`.
+	Indexes []*ModelIndex
+	// Checks are the check constraints of the table declared with the
+	// `check` struct tag of the kallax.Model field in the model, which is a
+	// list of checks separated by semicolons, each one with its name
+	// followed by a comma and the boolean SQL expression the rows must
+	// satisfy. For example, `check:"valid_period,starts_at < ends_at"`.
+	Checks []*ModelCheck
 	// Node is the node where the model was defined.
 	Node *types.Named
 	// CtorFunc is a reference to the model constructor.
@@ -252,6 +345,55 @@ type Model struct {
 	Package *types.Package
 }
 
+// ModelIndex is an index of the table of a model.
+type ModelIndex struct {
+	// Name is the name of the index.
+	Name string
+	// Columns are the names of the indexed columns, in order.
+	Columns []string
+}
+
+// ModelCheck is a check constraint of the table of a model.
+type ModelCheck struct {
+	// Name is the name of the constraint.
+	Name string
+	// Expression is the boolean SQL expression the rows must satisfy.
+	Expression string
+}
+
+// parseModelChecks returns the checks declared in the given value of the
+// `check` struct tag of the kallax.Model field of a model. The expressions
+// may have commas, as only the first one separates the name.
+func parseModelChecks(tag string) []*ModelCheck {
+	var checks []*ModelCheck
+	for _, def := range strings.Split(tag, ";") {
+		parts := strings.SplitN(def, ",", 2)
+		check := &ModelCheck{Name: strings.TrimSpace(parts[0])}
+		if len(parts) > 1 {
+			check.Expression = strings.TrimSpace(parts[1])
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// parseModelIndexes returns the indexes declared in the given value of the
+// `index` struct tag of the kallax.Model field of a model.
+func parseModelIndexes(tag string) []*ModelIndex {
+	var indexes []*ModelIndex
+	for _, def := range strings.Split(tag, ";") {
+		parts := strings.Split(def, ",")
+		idx := &ModelIndex{Name: strings.TrimSpace(parts[0])}
+		for _, c := range parts[1:] {
+			if c = strings.TrimSpace(c); c != "" {
+				idx.Columns = append(idx.Columns, c)
+			}
+		}
+		indexes = append(indexes, idx)
+	}
+	return indexes
+}
+
 // NewModel creates a new model with the given name.
 func NewModel(n string) *Model {
 	return &Model{
@@ -259,6 +401,9 @@ func NewModel(n string) *Model {
 		StoreName:     fmt.Sprintf(StoreNamePattern, n),
 		QueryName:     fmt.Sprintf(QueryNamePattern, n),
 		ResultSetName: fmt.Sprintf(ResultSetNamePattern, n),
+		PageName:      fmt.Sprintf(PageNamePattern, n),
+		AggregateName: fmt.Sprintf(AggregateNamePattern, n),
+		MockStoreName: fmt.Sprintf(MockStoreNamePattern, n),
 		Type:          "struct",
 	}
 }
@@ -307,7 +452,7 @@ func (m *Model) checkFieldOccurrences(fields []*Field, occurrences occurrences)
 		if f.Inline() {
 			m.checkFieldOccurrences(f.Fields, occurrences)
 		} else {
-			occurrences.inc(f.Name)
+			occurrences.inc(f.SchemaName())
 		}
 	}
 }
@@ -339,6 +484,60 @@ func (m *Model) Validate() error {
 		return fmt.Errorf("kallax: primary key %q of model %q does not have a valid identifier type (%s)", m.ID.Name, m.Name, m.ID.Type)
 	}
 
+	if _, err := m.ID.UUIDVersion(); err != nil {
+		return fmt.Errorf("kallax: %s. On primary key %q of model %q", err, m.ID.Name, m.Name)
+	}
+
+	if m.HasCompositeKey() {
+		for _, f := range m.PrimaryKey[1:] {
+			if !isValidIdentifier(f) {
+				return fmt.Errorf("kallax: primary key %q of model %q does not have a valid identifier type (%s)", f.Name, m.Name, f.Type)
+			}
+		}
+
+		if m.Audit || m.Versioned {
+			return fmt.Errorf("kallax: model %s has a composite primary key, so it can not be audited nor versioned", m.Name)
+		}
+
+		if m.Notify {
+			return fmt.Errorf("kallax: model %s has a composite primary key, so its changes can not be notified", m.Name)
+		}
+	}
+
+	if err := validateJoinTables(m.Fields); err != nil {
+		return err
+	}
+
+	if err := validatePrefixes(m.Fields); err != nil {
+		return err
+	}
+
+	if err := validateEnums(m.Fields); err != nil {
+		return err
+	}
+
+	if err := validateRules(m.Fields); err != nil {
+		return err
+	}
+
+	if fields := softDeleteFields(m.Fields); len(fields) > 1 {
+		return fmt.Errorf("kallax: model %s has more than one soft delete field", m.Name)
+	} else if len(fields) == 1 && (!fields[0].IsPtr || fields[0].Type != "time.Time") {
+		return fmt.Errorf("kallax: soft delete field %s of model %s must be a *time.Time", fields[0].Name, m.Name)
+	}
+
+	if fields := lockFields(m.Fields); len(fields) > 1 {
+		return fmt.Errorf("kallax: model %s has more than one lock field", m.Name)
+	} else if len(fields) == 1 && (fields[0].IsPtr || fields[0].IsPrimaryKey() || !isIntegerType(fields[0])) {
+		return fmt.Errorf("kallax: lock field %s of model %s must be an integer that is not part of the primary key", fields[0].Name, m.Name)
+	}
+
+	if fields := tenantFields(m.Fields); len(fields) > 1 {
+		return fmt.Errorf("kallax: model %s has more than one tenant field", m.Name)
+	} else if len(fields) == 1 && (fields[0].IsPtr || !(isIntegerType(fields[0]) || fields[0].Type == "string" || isValidIdentifier(fields[0]))) {
+		return fmt.Errorf("kallax: tenant field %s of model %s must be an integer, a string or an identifier", fields[0].Name, m.Name)
+	}
+
 	if fields := m.repeatedFields(); len(fields) > 0 {
 		return fmt.Errorf("kallax: the following fields are repeated: %v", fields)
 	}
@@ -351,6 +550,61 @@ func (m *Model) Validate() error {
 		return fmt.Errorf("kallax: model %s has no table", m.Name)
 	}
 
+	var indexes = make(map[string]bool)
+	for _, idx := range m.Indexes {
+		if idx.Name == "" || len(idx.Columns) == 0 {
+			return fmt.Errorf("kallax: index %q of model %s must have a name and at least one column", idx.Name, m.Name)
+		}
+
+		if indexes[idx.Name] {
+			return fmt.Errorf("kallax: index %s of model %s is repeated", idx.Name, m.Name)
+		}
+		indexes[idx.Name] = true
+	}
+
+	var checks = make(map[string]bool)
+	for _, c := range m.Checks {
+		if c.Name == "" || c.Expression == "" {
+			return fmt.Errorf("kallax: check %q of model %s must have a name and an expression", c.Name, m.Name)
+		}
+
+		if checks[c.Name] {
+			return fmt.Errorf("kallax: check %s of model %s is repeated", c.Name, m.Name)
+		}
+		checks[c.Name] = true
+	}
+
+	return nil
+}
+
+// validateJoinTables returns an error if the struct tag `through` is used in
+// the given fields for anything but a many to many relationship with a column
+// for each model in its join table.
+func validateJoinTables(fields []*Field) error {
+	for _, f := range fields {
+		if f.Inline() {
+			if err := validateJoinTables(f.Fields); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if f.Through() == "" {
+			continue
+		}
+
+		if f.Kind != Relationship || !strings.HasPrefix(f.Type, "[]") {
+			return fmt.Errorf("kallax: struct tag `through` can only be used in slices of models. On field %s of model %s.", f.Name, f.Model.Name)
+		}
+
+		if f.IsInverse() {
+			return fmt.Errorf("kallax: many to many relationship %s of model %s can not be inverse", f.Name, f.Model.Name)
+		}
+
+		if f.ForeignKey() == f.JoinTableReferences() {
+			return fmt.Errorf("kallax: both columns of the join table %s of relationship %s of model %s are named %s. Consider using the struct tag `fk:\"model_column,related_column\"` to name them.", f.Through(), f.Name, f.Model.Name, f.ForeignKey())
+		}
+	}
 	return nil
 }
 
@@ -482,12 +736,16 @@ func (m *Model) CtorRetVars() string {
 // SetFields always sets the primary key as the first field of the model.
 // So, all models can expect to have the primary key in the position 0 of
 // their field slice. This is because the Store will expect the ID in that
-// position.
+// position. The fields of a composite primary key are set first, in the
+// order of the pk definition, the first of them being the ID.
 func (m *Model) SetFields(fields []*Field) error {
 	var fs []*Field
 	var id *Field
 	for _, f := range flattenFields(fields) {
 		f.Model = m
+		if f.IsComposite {
+			setAttributesModel(f.Fields, m)
+		}
 		if f.IsPrimaryKey() && f.Type != BaseModel {
 			if id != nil {
 				return fmt.Errorf(
@@ -507,6 +765,13 @@ func (m *Model) SetFields(fields []*Field) error {
 				)
 			}
 
+			if len(f.primaryKeys) > 1 && f.isAutoincrement {
+				return fmt.Errorf(
+					"kallax: composite primary key defined in %s can not be auto-incrementable",
+					f.Name,
+				)
+			}
+
 			// the pk is defined in the model, we need to collect the model
 			// and we'll look for the field afterwards, when we have collected
 			// all fields. The model is appended to the field set, though,
@@ -518,42 +783,50 @@ func (m *Model) SetFields(fields []*Field) error {
 		}
 	}
 
-	// if the id is a Model we need to look for the specified field
+	var pk []*Field
+	// if the id is a Model we need to look for the specified fields
 	if id != nil && id.Type == BaseModel {
-		for i, f := range fs {
-			if f.columnName == id.primaryKey {
-				f.isPrimaryKey = true
-				f.isAutoincrement = id.isAutoincrement
-				id = f
-
-				if len(fs)-1 == i {
-					fs = append(fs[:i])
-				} else {
+		for _, name := range id.primaryKeys {
+			var found bool
+			for i, f := range fs {
+				if f.columnName == name {
+					f.isPrimaryKey = true
+					f.isAutoincrement = id.isAutoincrement
+					pk = append(pk, f)
 					fs = append(fs[:i], fs[i+1:]...)
+					found = true
+					break
 				}
-				break
 			}
-		}
 
-		// If the ID is still a base model, means we did not find the pk
-		// field.
-		if id.Type == BaseModel {
-			return fmt.Errorf(
-				"kallax: the primary key was supposed to be %s according to the pk definition in %s, but the field could not be found",
-				id.primaryKey,
-				id.Name,
-			)
+			// If the field was not found, the pk definition is wrong.
+			if !found {
+				return fmt.Errorf(
+					"kallax: the primary key was supposed to be %s according to the pk definition in %s, but the field could not be found",
+					name,
+					id.Name,
+				)
+			}
 		}
+	} else if id != nil {
+		pk = []*Field{id}
 	}
 
-	if id != nil {
-		m.Fields = []*Field{id}
-		m.ID = id
+	if len(pk) > 0 {
+		m.Fields = pk
+		m.ID = pk[0]
+		m.PrimaryKey = pk
 	}
 	m.Fields = append(m.Fields, fs...)
 	return nil
 }
 
+// HasCompositeKey reports whether the primary key of the model has more than
+// one field.
+func (m *Model) HasCompositeKey() bool {
+	return len(m.PrimaryKey) > 1
+}
+
 // Relationships returns the fields of a model that are relationships.
 func (m *Model) Relationships() []*Field {
 	return relationshipsOnFields(m.Fields)
@@ -570,11 +843,23 @@ func (m *Model) Inverses() []*Field {
 	return inverses
 }
 
-// NonInverses returns the relationships of the model that are not inverses.
+// NonInverses returns the 1:1 and 1:N relationships of the model that are not
+// inverses.
 func (m *Model) NonInverses() []*Field {
 	var rels []*Field
 	for _, f := range relationshipsOnFields(m.Fields) {
-		if !f.IsInverse() {
+		if !f.IsInverse() && !f.IsManyToManyRelationship() {
+			rels = append(rels, f)
+		}
+	}
+	return rels
+}
+
+// ManyToManyRelationships returns the many to many relationships of the model.
+func (m *Model) ManyToManyRelationships() []*Field {
+	var rels []*Field
+	for _, f := range relationshipsOnFields(m.Fields) {
+		if f.IsManyToManyRelationship() {
 			rels = append(rels, f)
 		}
 	}
@@ -596,6 +881,147 @@ func (m *Model) HasNonInverses() bool {
 	return len(m.NonInverses()) > 0
 }
 
+// JSONSchemaFields returns the fields of the model whose values are validated
+// against a JSON Schema document.
+func (m *Model) JSONSchemaFields() []*Field {
+	return jsonSchemaFields(m.Fields)
+}
+
+// HasJSONSchemas returns whether the model has fields validated against a JSON
+// Schema document or not.
+func (m *Model) HasJSONSchemas() bool {
+	return len(m.JSONSchemaFields()) > 0
+}
+
+// SoftDeleteField returns the field that records when the records of the
+// model were deleted, if they are soft deleted, or nil otherwise.
+func (m *Model) SoftDeleteField() *Field {
+	if fields := softDeleteFields(m.Fields); len(fields) > 0 {
+		return fields[0]
+	}
+	return nil
+}
+
+func softDeleteFields(fields []*Field) []*Field {
+	var result []*Field
+	for _, f := range fields {
+		if f.Inline() {
+			result = append(result, softDeleteFields(f.Fields)...)
+		} else if f.IsSoftDelete() {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// LockField returns the field with which the updates of the records of the
+// model are optimistically locked, if they are, or nil otherwise.
+func (m *Model) LockField() *Field {
+	if fields := lockFields(m.Fields); len(fields) > 0 {
+		return fields[0]
+	}
+	return nil
+}
+
+func lockFields(fields []*Field) []*Field {
+	var result []*Field
+	for _, f := range fields {
+		if f.Inline() {
+			result = append(result, lockFields(f.Fields)...)
+		} else if f.IsLock() {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// DefaultFields returns the fields whose columns have a default value,
+// given with the struct tag `default`.
+func (m *Model) DefaultFields() []*Field {
+	return defaultFields(m.Fields)
+}
+
+func defaultFields(fields []*Field) []*Field {
+	var result []*Field
+	for _, f := range fields {
+		if f.Inline() {
+			result = append(result, defaultFields(f.Fields)...)
+		} else if f.Default() != "" {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// TenantField returns the field with the tenant the records of the model
+// belong to, if they do, or nil otherwise.
+func (m *Model) TenantField() *Field {
+	if fields := tenantFields(m.Fields); len(fields) > 0 {
+		return fields[0]
+	}
+	return nil
+}
+
+func tenantFields(fields []*Field) []*Field {
+	var result []*Field
+	for _, f := range fields {
+		if f.Inline() {
+			result = append(result, tenantFields(f.Fields)...)
+		} else if f.IsTenant() {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+var columnPrefixRegex = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// validatePrefixes returns an error if any of the given fields with the
+// struct tag `prefix` is not a struct that can be flattened into columns, or
+// its prefix is not a valid beginning of a column name.
+func validatePrefixes(fields []*Field) error {
+	for _, f := range fields {
+		prefix, ok := f.ColumnPrefix()
+		if ok && (f.Kind != Struct || f.IsPtr || f.IsComposite || f.IsEmbedded) {
+			return fmt.Errorf("kallax: struct tag `prefix` can only be used in struct fields that are neither pointers, embedded nor composite types. On field %s of model %s.", f.Name, f.Model.Name)
+		} else if ok && !columnPrefixRegex.MatchString(prefix) {
+			return fmt.Errorf("kallax: struct tag `prefix` has an invalid prefix %q. On field %s of model %s.", prefix, f.Name, f.Model.Name)
+		}
+
+		if f.Inline() {
+			if err := validatePrefixes(f.Fields); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isIntegerType reports whether the given field is stored as an integer.
+func isIntegerType(f *Field) bool {
+	if f.Kind != Basic {
+		return false
+	}
+
+	switch f.Type {
+	case "int8", "uint8", "byte", "int16", "uint16", "int32", "uint32", "int", "uint", "int64", "uint64":
+		return true
+	}
+	return false
+}
+
+func jsonSchemaFields(fields []*Field) []*Field {
+	var result []*Field
+	for _, f := range fields {
+		if f.Inline() {
+			result = append(result, jsonSchemaFields(f.Fields)...)
+		} else if f.JSONSchema != "" {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
 func relationshipsOnFields(fields []*Field) []*Field {
 	var result []*Field
 	for _, f := range fields {
@@ -644,8 +1070,15 @@ type Field struct {
 	// A struct is considered embedded if and only if the struct was embedded
 	// as defined in Go.
 	IsEmbedded bool
+	// IsComposite reports whether the field is a struct stored as a Postgres
+	// composite type, that is, a struct embedding kallax.Composite.
+	IsComposite bool
+	// JSONSchema is the JSON Schema document the values of the field are
+	// validated against, if the field has the struct tag `jsonschema`.
+	JSONSchema string
 
 	primaryKey      string
+	primaryKeys     []string
 	isPrimaryKey    bool
 	isUnique        bool
 	isAutoincrement bool
@@ -713,6 +1146,7 @@ func NewField(n, t string, tag reflect.StructTag) *Field {
 		Tag:  tag,
 
 		primaryKey:      pkName,
+		primaryKeys:     pkNames(tag),
 		columnName:      columnName(n, tag),
 		isPrimaryKey:    isPrimaryKey,
 		isUnique:        isUnique(tag),
@@ -721,7 +1155,8 @@ func NewField(n, t string, tag reflect.StructTag) *Field {
 }
 
 func isUnique(tag reflect.StructTag) bool {
-	return tag.Get("unique") == "true"
+	val, ok := tag.Lookup("unique")
+	return ok && (val == "" || val == "true")
 }
 
 // pkProperties returns the primary key properties from a struct tag.
@@ -730,6 +1165,7 @@ func isUnique(tag reflect.StructTag) bool {
 // - pk:"autoincr" -> autoincr primary key without a field name.
 // - pk:"foobar" -> non-autoincr primary key with a field name.
 // - pk:"foobar,autoincr" -> autoincr primary key with a field name.
+// - pk:"foo,bar" -> composite primary key with the given field names.
 func pkProperties(tag reflect.StructTag) (name string, autoincr, isPrimaryKey bool) {
 	val, ok := tag.Lookup("pk")
 	if !ok {
@@ -746,13 +1182,28 @@ func pkProperties(tag reflect.StructTag) (name string, autoincr, isPrimaryKey bo
 
 	parts := strings.Split(val, ",")
 	name = parts[0]
-	if len(parts) > 1 && parts[1] == "autoincr" {
-		autoincr = true
+	for _, part := range parts[1:] {
+		if part == "autoincr" {
+			autoincr = true
+		}
 	}
 
 	return
 }
 
+// pkNames returns the names of all the fields of the primary key defined in
+// the `pk` struct tag, which has more than one of them for composite primary
+// keys, such as pk:"tenant_id,order_id".
+func pkNames(tag reflect.StructTag) []string {
+	var names []string
+	for _, name := range strings.Split(tag.Get("pk"), ",") {
+		if name != "" && name != "autoincr" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 // SetFields sets all the children fields and the current field as a parent of
 // the children.
 func (f *Field) SetFields(sf []*Field) {
@@ -767,16 +1218,72 @@ func (f *Field) SetFields(sf []*Field) {
 // The struct tag `kallax` of the field can be use to set the name, otherwise
 // is the field name converted to lower snake case.
 // If the resultant name is a reserved keyword a _ will be prepended to the name.
+// The name of the fields flattened from struct fields with the struct tag
+// `prefix` is preceded by their prefixes, see ColumnPrefix.
 func (f *Field) ColumnName() string {
+	if prefix := f.parentsColumnPrefix(); prefix != "" {
+		return prefix + baseColumnName(f.Name, f.Tag)
+	}
 	return f.columnName
 }
 
-func columnName(name string, tag reflect.StructTag) string {
+// SchemaName returns the name of the field in the schema of the model and in
+// its FindBys, which is the field name preceded by the names of the struct
+// fields with the struct tag `prefix` it is flattened from, such as
+// BillingStreet for the field Street of the field Billing.
+func (f *Field) SchemaName() string {
+	name := f.Name
+	for p := f.Parent; p != nil && p.Inline(); p = p.Parent {
+		if _, ok := p.ColumnPrefix(); ok {
+			name = p.Name + name
+		}
+	}
+	return name
+}
+
+// ColumnPrefix returns the prefix of the columns of the fields of the struct
+// field, which are flattened into columns of the model instead of storing
+// the struct as JSON, as given in its struct tag `prefix`, such as
+// `prefix:"billing_"`. With an empty tag the prefix is the column name of the
+// field followed by an underscore. It returns false if the field has no such
+// tag.
+func (f *Field) ColumnPrefix() (string, bool) {
+	prefix, ok := f.Tag.Lookup("prefix")
+	if !ok {
+		return "", false
+	}
+
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" {
+		prefix = baseColumnName(f.Name, f.Tag) + "_"
+	}
+	return prefix, true
+}
+
+// parentsColumnPrefix returns the prefixes of the struct fields the field is
+// flattened from, in order.
+func (f *Field) parentsColumnPrefix() string {
+	var prefix string
+	for p := f.Parent; p != nil && p.Inline(); p = p.Parent {
+		if pre, ok := p.ColumnPrefix(); ok {
+			prefix = pre + prefix
+		}
+	}
+	return prefix
+}
+
+// baseColumnName returns the column name of the field with the given name and
+// struct tag, which may be a reserved keyword.
+func baseColumnName(name string, tag reflect.StructTag) string {
 	n := strings.TrimSpace(strings.Split(tag.Get("kallax"), ",")[0])
 	if n == "" {
 		n = toLowerSnakeCase(name)
 	}
+	return n
+}
 
+func columnName(name string, tag reflect.StructTag) string {
+	n := baseColumnName(name, tag)
 	if _, ok := reservedKeywords[strings.ToLower(n)]; ok {
 		n = "_" + n
 	}
@@ -832,10 +1339,60 @@ func (f *Field) IsInverse() bool {
 	return false
 }
 
+// ForeignKeyConstraint reports whether the foreign key of the relationship is
+// added to its table as a constraint of its own, instead of in the definition
+// of its column, and returns the actions taken on the referencing rows when
+// the referenced row is deleted or updated, as specified with `constraint`,
+// `on_delete=action` and `on_update=action` in the struct tag `fk`. For
+// example, `fk:"owner_id,constraint,on_delete=cascade"`.
+func (f *Field) ForeignKeyConstraint() (constraint bool, onDelete, onUpdate string) {
+	if f.Kind != Relationship || f.IsManyToManyRelationship() {
+		return false, "", ""
+	}
+
+	for _, part := range strings.Split(f.Tag.Get("fk"), ",")[1:] {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "constraint":
+			constraint = true
+		case strings.HasPrefix(part, "on_delete="):
+			onDelete = strings.TrimPrefix(part, "on_delete=")
+		case strings.HasPrefix(part, "on_update="):
+			onUpdate = strings.TrimPrefix(part, "on_update=")
+		}
+	}
+
+	return constraint, onDelete, onUpdate
+}
+
 // IsOneToManyRelationship returns whether the field is a one to many
 // relationship.
 func (f *Field) IsOneToManyRelationship() bool {
-	return f.Kind == Relationship && strings.HasPrefix(f.Type, "[]")
+	return f.Kind == Relationship && strings.HasPrefix(f.Type, "[]") && !f.IsManyToManyRelationship()
+}
+
+// IsManyToManyRelationship returns whether the field is a many to many
+// relationship, which is a slice of models with the struct tag `through`.
+func (f *Field) IsManyToManyRelationship() bool {
+	return f.Kind == Relationship && strings.HasPrefix(f.Type, "[]") && f.Through() != ""
+}
+
+// Through returns the name of the join table of a many to many relationship
+// as specified in the struct tag `through`.
+func (f *Field) Through() string {
+	return strings.TrimSpace(f.Tag.Get("through"))
+}
+
+// JoinTableReferences returns the name of the column of the join table of a
+// many to many relationship that references the related model, which is the
+// second part of the struct tag `fk` or the default foreign key of the
+// related model. The first part of the tag is the name of the column that
+// references the model of the field, returned by ForeignKey.
+func (f *Field) JoinTableReferences() string {
+	if parts := strings.Split(f.Tag.Get("fk"), ","); len(parts) > 1 && parts[1] != "" {
+		return strings.TrimSpace(parts[1])
+	}
+	return foreignKeyForModel(f.TypeSchemaName())
 }
 
 func foreignKeyForModel(model string) string {
@@ -845,12 +1402,17 @@ func foreignKeyForModel(model string) string {
 // Inline reports whether the field is inline and its children will be in the
 // root of the model.
 // An inline field is the one having the type kallax.Model, one that has a
-// struct tag `kallax` containing `,inline` or an embedded struct field.
+// struct tag `kallax` containing `,inline` or `prefix`, or an embedded struct
+// field.
 func (f *Field) Inline() bool {
 	if f.Type == BaseModel || f.IsEmbedded {
 		return true
 	}
 
+	if _, ok := f.ColumnPrefix(); ok {
+		return true
+	}
+
 	tag := f.Tag.Get("kallax")
 	for _, p := range strings.Split(tag, ",") {
 		if p == "inline" {
@@ -905,6 +1467,14 @@ func (f *Field) Address() string {
 		casted = true
 	}
 
+	if f.isPGMoney() {
+		name = fmt.Sprintf("kallax.PGMoney(%s)", name)
+	}
+
+	if f.isDurationInterval() {
+		return fmt.Sprintf("kallax.DurationInterval(&%s)", f.fieldVarName())
+	}
+
 	return f.wrapAddress(name, casted)
 }
 
@@ -914,9 +1484,26 @@ func (f *Field) typeName() (string, bool) {
 
 func (f *Field) wrapAddress(ptr string, casted bool) string {
 	if f.IsJSON {
+		if codec := f.JSONCodec(); codec != "" {
+			return fmt.Sprintf("types.JSONWithCodec(%q, %s)", codec, ptr)
+		}
 		return fmt.Sprintf("types.JSON(%s)", ptr)
 	}
 
+	if f.IsComposite {
+		if f.IsPtr {
+			return fmt.Sprintf("types.Composite(&%s)", ptr)
+		}
+		return fmt.Sprintf("types.Composite(%s)", ptr)
+	}
+
+	if c := f.Compression(); c != "" {
+		if f.IsPtr {
+			return fmt.Sprintf("types.Compressed(%q, &%s)", c, ptr)
+		}
+		return fmt.Sprintf("types.Compressed(%q, %s)", c, ptr)
+	}
+
 	if f.Kind == Slice {
 		if typ, ok := castSlice(f); ok {
 			return fmt.Sprintf("types.Slice((*%s)(%s))", typ, ptr)
@@ -944,11 +1531,26 @@ func (f *Field) Value() string {
 	name := f.fieldVarName()
 
 	if f.IsJSON {
+		if codec := f.JSONCodec(); codec != "" {
+			return fmt.Sprintf("types.JSONWithCodec(%q, %s), nil", codec, name)
+		}
 		return fmt.Sprintf("types.JSON(%s), nil", name)
 	}
 
+	if f.IsComposite {
+		return fmt.Sprintf("types.Composite(%s), nil", f.fieldVarAddress())
+	}
+
+	if c := f.Compression(); c != "" {
+		return fmt.Sprintf("types.Compressed(%q, %s), nil", c, f.fieldVarAddress())
+	}
+
 	switch f.Kind {
 	case Basic:
+		if f.isDurationInterval() {
+			return fmt.Sprintf("kallax.DurationInterval(%s), nil", f.fieldVarAddress())
+		}
+
 		if mapped, ok := mappings[f.Type]; ok {
 			name = fmt.Sprintf("(*%s)(%s)", mapped, f.fieldVarAddress())
 		}
@@ -965,6 +1567,10 @@ func (f *Field) Value() string {
 		return fmt.Sprintf("types.Slice(%s), nil", name)
 	case Array:
 		return fmt.Sprintf("types.Array(%s, %d), nil", f.fieldVarAddress(), arrayLen(f))
+	case Interface:
+		if f.isPGMoney() {
+			return fmt.Sprintf("kallax.PGMoney(%s), nil", f.fieldVarAddress())
+		}
 	}
 
 	return name + ", nil"
@@ -980,12 +1586,284 @@ func (f *Field) SQLType() string {
 	return f.Tag.Get("sqltype")
 }
 
+// SRID returns the spatial reference system identifier defined in the `srid`
+// struct tag of the field. If there is no such tag, a 0 is returned.
+func (f *Field) SRID() (int, error) {
+	return f.uintTag("srid")
+}
+
+// Dims returns the number of dimensions of a vector defined in the `dims`
+// struct tag of the field. If there is no such tag, a 0 is returned.
+func (f *Field) Dims() (int, error) {
+	return f.uintTag("dims")
+}
+
+// Bits returns the length of a bit string defined in the `bits` struct tag of
+// the field. If there is no such tag, a 0 is returned.
+func (f *Field) Bits() (int, error) {
+	return f.uintTag("bits")
+}
+
+func (f *Field) uintTag(name string) (int, error) {
+	val, ok := f.Tag.Lookup(name)
+	if !ok {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(val)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s %q", name, val)
+	}
+	return n, nil
+}
+
+// Timezone reports whether the time values of the field are stored with their
+// time zone, that is, in a timestamptz column. This is the default unless the
+// field has the struct tag `timezone:"false"`, in which case a timestamp
+// column is used instead.
+func (f *Field) Timezone() (bool, error) {
+	val, ok := f.Tag.Lookup("timezone")
+	if !ok {
+		return true, nil
+	}
+
+	tz, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, fmt.Errorf("invalid timezone %q, it can only be true or false", val)
+	}
+	return tz, nil
+}
+
+// CompositeType returns the name of the Postgres composite type of the field,
+// which is set with the struct tag `type` of the embedded kallax.Composite or
+// is the name of the struct in lower snake case.
+func (f *Field) CompositeType() string {
+	if !f.IsComposite || f.Node == nil {
+		return ""
+	}
+
+	typ := f.Node.Type()
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+
+	if s, ok := typ.Underlying().(*types.Struct); ok {
+		if tag, ok := compositeTag(s); ok {
+			if name := tag.Get("type"); name != "" {
+				return name
+			}
+		}
+	}
+
+	if named, ok := typ.(*types.Named); ok {
+		return toLowerSnakeCase(named.Obj().Name())
+	}
+	return ""
+}
+
+// IsNull reports whether the field is a kallax.Null, whose column is
+// nullable and has the type of the wrapped value.
+func (f *Field) IsNull() bool {
+	return f.Node != nil && nullElem(f.Node.Type()) != nil
+}
+
+const moneyType = "gopkg.in/src-d/go-kallax.v1.Money"
+
+// UUIDVersion returns the version of the UUIDs generated for the field when it
+// is an empty primary key of a record being inserted. This is configured with
+// the struct tag `uuid`, which can be "v4" or "v7". If the tag is not present
+// UUIDs are not generated and an empty string is returned.
+func (f *Field) UUIDVersion() (string, error) {
+	version, ok := f.Tag.Lookup("uuid")
+	if !ok {
+		return "", nil
+	}
+
+	if !f.IsPrimaryKey() || identifierType(f) != "kallax.UUID" {
+		return "", fmt.Errorf("struct tag `uuid` can only be used in UUID primary keys")
+	}
+
+	switch version {
+	case "v4", "v7":
+		return version, nil
+	default:
+		return "", fmt.Errorf("invalid uuid version %q, it can only be v4 or v7", version)
+	}
+}
+
+// JSONCodec returns the name of the JSON codec used to encode and decode the
+// field, which is set with the struct tag `jsoncodec`. The codec must be
+// registered with types.RegisterJSONCodec. If the tag is not present, an
+// empty string is returned and the field is encoded with encoding/json.
+func (f *Field) JSONCodec() string {
+	return f.Tag.Get("jsoncodec")
+}
+
+// JSONSchemaFile returns the path of the file with the JSON Schema document
+// the values of the field are validated against, relative to the directory of
+// the package, and whether the migrations enforce it with a CHECK constraint
+// as well. They are set with the struct tag `jsonschema:"schemas/foo.json"`,
+// or `jsonschema:"schemas/foo.json,check"` to add the constraint. If the tag
+// is not present, an empty path is returned.
+func (f *Field) JSONSchemaFile() (path string, check bool) {
+	parts := strings.Split(f.Tag.Get("jsonschema"), ",")
+	for _, opt := range parts[1:] {
+		if strings.TrimSpace(opt) == "check" {
+			check = true
+		}
+	}
+	return strings.TrimSpace(parts[0]), check
+}
+
+// Compression returns the name of the compressor used to compress the field
+// before storing it, which is set with the struct tag `compress`. The
+// compressor must be "gzip", "zlib" or one registered with
+// types.RegisterCompressor. If the tag is not present, an empty string is
+// returned and the field is not compressed.
+func (f *Field) Compression() string {
+	return f.Tag.Get("compress")
+}
+
+// Deprecation returns the notice of the generated `Deprecated:` comments of
+// the field and whether it's deprecated, which is set with the struct tag
+// `deprecated:"use Email instead"`. The column of a deprecated field is
+// kept working, but it is not added by new migrations. If the tag has no
+// value, a default notice is returned.
+func (f *Field) Deprecation() (notice string, ok bool) {
+	notice, ok = f.Tag.Lookup("deprecated")
+	if ok && notice == "" {
+		notice = fmt.Sprintf("the field %s will be removed.", f.Name)
+	}
+	return notice, ok
+}
+
+// OldColumnName returns the name the column of the field had before it was
+// renamed, as specified in the struct tag `oldname`, so the migrations rename
+// the column instead of dropping it and adding a new one.
+func (f *Field) OldColumnName() string {
+	return strings.TrimSpace(f.Tag.Get("oldname"))
+}
+
+// Check returns the boolean SQL expression of the check constraint the
+// migrations add to the column of the field, which is set with the struct tag
+// `check`, such as `check:"price >= 0"`. If the tag is not present, an empty
+// string is returned.
+func (f *Field) Check() string {
+	return strings.TrimSpace(f.Tag.Get("check"))
+}
+
+// Default returns the SQL expression of the default value the migrations
+// give to the column of the field, which is set with the struct tag
+// `default`, such as `default:"now()"` or `default:"0"`. If the tag is not
+// present, an empty string is returned.
+func (f *Field) Default() string {
+	return strings.TrimSpace(f.Tag.Get("default"))
+}
+
+// IsGenerated reports whether the value of the field is computed by the
+// database from other columns, so it is never inserted nor updated. That is
+// the case of tsvector fields with the struct tag `tsvector`.
+func (f *Field) IsGenerated() bool {
+	_, ok := f.Tag.Lookup("tsvector")
+	return ok
+}
+
+// IsCIText reports whether the field is stored in a case-insensitive citext
+// column instead of a text column. This is configured with the struct tag
+// `citext:""`.
+func (f *Field) IsCIText() bool {
+	_, ok := f.Tag.Lookup("citext")
+	return ok
+}
+
+// IsSoftDelete reports whether the field records when the record was deleted,
+// so the records of the model are soft deleted instead of removed. This is
+// configured with the `softdelete` option of the struct tag `kallax`, as in
+// `kallax:"deleted_at,softdelete"`. The field must be a *time.Time.
+func (f *Field) IsSoftDelete() bool {
+	for _, opt := range strings.Split(f.Tag.Get("kallax"), ",")[1:] {
+		if strings.TrimSpace(opt) == "softdelete" {
+			return true
+		}
+	}
+	return false
+}
+
+// IsLock reports whether the field is the version with which the updates of
+// the records of the model are optimistically locked. This is configured
+// with the `lock` option of the struct tag `kallax`, as in
+// `kallax:"version,lock"`. The field must be an integer.
+func (f *Field) IsLock() bool {
+	for _, opt := range strings.Split(f.Tag.Get("kallax"), ",")[1:] {
+		if strings.TrimSpace(opt) == "lock" {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTenant reports whether the field is the tenant the record belongs to, so
+// the stores bound to a tenant only read and write the records of their
+// tenant. This is configured with the struct tag `tenant:""`. The field must
+// be an integer, a string or an identifier.
+func (f *Field) IsTenant() bool {
+	_, ok := f.Tag.Lookup("tenant")
+	return ok
+}
+
+// IsPGMoney reports whether the field is a kallax.Money that needs to be
+// stored using the Postgres money type instead of the default composite
+// type. This is configured with the struct tag `money:"money"`.
+func (f *Field) IsPGMoney() (bool, error) {
+	if f.Kind != Interface || f.Node == nil || removeTypePrefix(typeName(f.Node.Type())) != moneyType {
+		return false, nil
+	}
+
+	switch layout := f.Tag.Get("money"); layout {
+	case "", "composite":
+		return false, nil
+	case "money":
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid money layout %q, it can only be composite or money", layout)
+	}
+}
+
+func (f *Field) isPGMoney() bool {
+	pg, _ := f.IsPGMoney()
+	return pg
+}
+
+// IsDurationInterval reports whether the field is a time.Duration that needs
+// to be stored in an interval column instead of the default bigint column of
+// nanoseconds. This is configured with the struct tag `duration:"interval"`.
+func (f *Field) IsDurationInterval() (bool, error) {
+	if f.Kind != Basic || f.Type != "time.Duration" {
+		return false, nil
+	}
+
+	switch storage := f.Tag.Get("duration"); storage {
+	case "", "bigint":
+		return false, nil
+	case "interval":
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid duration storage %q, it can only be bigint or interval", storage)
+	}
+}
+
+func (f *Field) isDurationInterval() bool {
+	interval, _ := f.IsDurationInterval()
+	return interval
+}
+
 var identifierTypes = map[string]string{
 	"gopkg.in/src-d/go-kallax.v1.UUID":      "kallax.UUID",
 	"gopkg.in/src-d/go-kallax.v1.ULID":      "kallax.ULID",
 	"gopkg.in/src-d/go-kallax.v1.NumericID": "kallax.NumericID",
 	"github.com/satori/go.uuid.UUID":        "kallax.UUID",
 	"github.com/gofrs/uuid.UUID":            "kallax.UUID",
+	"github.com/google/uuid.UUID":           "kallax.UUID",
 	"int64": "kallax.NumericID",
 }
 
@@ -1077,7 +1955,7 @@ func castSlice(f *Field) (cast string, ok bool) {
 }
 
 func typeString(ty types.Type, pkg *types.Package) string {
-	ret := types.TypeString(ty, types.RelativeTo(pkg))
+	ret := types.TypeString(ty, packageQualifier(pkg))
 	parts := strings.Split(ret, string(separator))
 	prefix := ""
 	if len(parts) > 1 {
@@ -1091,6 +1969,19 @@ func typeString(ty types.Type, pkg *types.Package) string {
 	return prefix + parts[len(parts)-1]
 }
 
+// packageQualifier works like types.RelativeTo but it qualifies the types of
+// other packages with their name instead of their path, because the last
+// element of the path is not always the package name. For example, the
+// package gopkg.in/src-d/go-kallax.v1 is named kallax.
+func packageQualifier(pkg *types.Package) types.Qualifier {
+	return func(other *types.Package) string {
+		if pkg == other {
+			return ""
+		}
+		return other.Name()
+	}
+}
+
 func isBuiltinError(typ types.Type) bool {
 	named, ok := typ.(*types.Named)
 	if !ok {
@@ -1115,6 +2006,15 @@ func toLowerSnakeCase(s string) string {
 
 // flattenFields will recursively flatten all fields removing the embedded ones
 // from the field set.
+// setAttributesModel sets the model of the given attributes of a composite
+// type, and the attributes of the composite types they contain.
+func setAttributesModel(fields []*Field, m *Model) {
+	for _, f := range fields {
+		f.Model = m
+		setAttributesModel(f.Fields, m)
+	}
+}
+
 func flattenFields(fields []*Field) []*Field {
 	var result = make([]*Field, 0, len(fields))
 
@@ -34,6 +34,8 @@ func (s *FieldSuite) TestInline() {
 		{"", `kallax:"foo,inline,omitempty"`, true},
 		{"", `kallax:",inline,omitempty"`, true},
 		{"", `kallax:",inline"`, true},
+		{"", `prefix:""`, true},
+		{"", `prefix:"billing_"`, true},
 	}
 
 	for _, c := range cases {
@@ -152,6 +154,21 @@ func (s *FieldSuite) TestAddress() {
 
 		s.Equal(c.expected, f.Address(), "Field %s, i = %d", f.Name, i)
 	}
+
+	f := withJSON(withKind(mkField("Foo", "", `jsoncodec:"protojson"`), Struct))
+	s.Equal(`types.JSONWithCodec("protojson", &r.Foo)`, f.Address())
+
+	f = withKind(mkField("Foo", "string", `compress:"gzip"`), Basic)
+	s.Equal(`types.Compressed("gzip", &r.Foo)`, f.Address())
+
+	f = withPtr(withKind(mkField("Foo", "[]byte", `compress:"zstd"`), Slice))
+	s.Equal(`types.Compressed("zstd", &r.Foo)`, f.Address())
+
+	f = withKind(mkField("Foo", "time.Duration", `duration:"interval"`), Basic)
+	s.Equal(`kallax.DurationInterval(&r.Foo)`, f.Address())
+
+	f = withPtr(withKind(mkField("Foo", "time.Duration", `duration:"interval"`), Basic))
+	s.Equal(`kallax.DurationInterval(&r.Foo)`, f.Address())
 }
 
 func (s *FieldSuite) TestValue() {
@@ -183,6 +200,22 @@ func (s *FieldSuite) TestValue() {
 			withJSON(withKind(mkField("Foo", "", ""), Map)),
 			"types.JSON(r.Foo), nil",
 		},
+		{
+			withJSON(withPtr(withKind(mkField("Foo", "", `jsoncodec:"protojson"`), Struct))),
+			`types.JSONWithCodec("protojson", r.Foo), nil`,
+		},
+		{
+			withAlias(mkField("Foo", "string", `compress:"gzip"`)),
+			`types.Compressed("gzip", &r.Foo), nil`,
+		},
+		{
+			mkField("Foo", "time.Duration", `duration:"interval"`),
+			`kallax.DurationInterval(&r.Foo), nil`,
+		},
+		{
+			withPtr(mkField("Foo", "time.Duration", `duration:"interval"`)),
+			`kallax.DurationInterval(r.Foo), nil`,
+		},
 		{
 			withKind(mkField("Foo", "", ""), Struct),
 			"r.Foo, nil",
@@ -299,6 +332,9 @@ func (s *ModelSuite) TestModel() {
 	s.Equal("UserStore", s.model.StoreName)
 	s.Equal("UserQuery", s.model.QueryName)
 	s.Equal("UserResultSet", s.model.ResultSetName)
+	s.Equal("UserPage", s.model.PageName)
+	s.Equal("UserAggregate", s.model.AggregateName)
+	s.Equal("MockUserStore", s.model.MockStoreName)
 }
 
 func (s *ModelSuite) TestCtor() {
@@ -343,6 +379,33 @@ func (s *ModelSuite) TestModelValidate() {
 	m.ID = id
 	m.Table = ""
 	require.Error(m.Validate(), "should return error")
+
+	m.Table = "foo"
+	m.Indexes = parseModelIndexes("idx_foo,foo;idx_bar")
+	require.Error(m.Validate(), "should return error")
+
+	m.Indexes = parseModelIndexes("idx_foo,foo;idx_foo,id")
+	require.Error(m.Validate(), "should return error")
+
+	m.Indexes = parseModelIndexes("idx_foo, foo, id;idx_bar,id")
+	require.Equal([]*ModelIndex{
+		{Name: "idx_foo", Columns: []string{"foo", "id"}},
+		{Name: "idx_bar", Columns: []string{"id"}},
+	}, m.Indexes)
+	require.NoError(m.Validate(), "should not return error")
+
+	m.Checks = parseModelChecks("valid_foo")
+	require.Error(m.Validate(), "should return error")
+
+	m.Checks = parseModelChecks("valid_foo,foo > 0;valid_foo,id > 0")
+	require.Error(m.Validate(), "should return error")
+
+	m.Checks = parseModelChecks("valid_foo, coalesce(foo, 0) > 0;valid_id,id > 0")
+	require.Equal([]*ModelCheck{
+		{Name: "valid_foo", Expression: "coalesce(foo, 0) > 0"},
+		{Name: "valid_id", Expression: "id > 0"},
+	}, m.Checks)
+	require.NoError(m.Validate(), "should not return error")
 }
 
 func TestFieldForeignKey(t *testing.T) {
@@ -373,6 +436,33 @@ func TestFieldForeignKey(t *testing.T) {
 	}
 }
 
+func TestFieldForeignKeyConstraint(t *testing.T) {
+	r := require.New(t)
+
+	cases := []struct {
+		tag                string
+		typ                string
+		constraint         bool
+		onDelete, onUpdate string
+	}{
+		{`fk:"owner_id"`, "*Foo", false, "", ""},
+		{`fk:"owner_id,constraint"`, "*Foo", true, "", ""},
+		{`fk:"owner_id,constraint,on_delete=cascade"`, "*Foo", true, "cascade", ""},
+		{`fk:",inverse,on_delete=set_null,on_update=cascade"`, "*Foo", false, "set_null", "cascade"},
+		{`fk:"foo_id,constraint" through:"foo_bars"`, "[]*Foo", false, "", ""},
+	}
+
+	for _, c := range cases {
+		f := NewField("", c.typ, reflect.StructTag(c.tag))
+		f.Kind = Relationship
+
+		constraint, onDelete, onUpdate := f.ForeignKeyConstraint()
+		r.Equal(c.constraint, constraint, "constraint with tag: %s", c.tag)
+		r.Equal(c.onDelete, onDelete, "on delete with tag: %s", c.tag)
+		r.Equal(c.onUpdate, onUpdate, "on update with tag: %s", c.tag)
+	}
+}
+
 func TestModelSetFields(t *testing.T) {
 	r := require.New(t)
 	cases := []struct {
@@ -458,6 +548,7 @@ func TestPkProperties(t *testing.T) {
 		{`pk:",autoincr"`, "", true, true},
 		{`bar:"baz" pk:"foo"`, "foo", false, true},
 		{`pk:"foo,autoincr"`, "foo", true, true},
+		{`pk:"foo,bar,autoincr"`, "foo", true, true},
 	}
 
 	require := require.New(t)
@@ -476,7 +567,8 @@ func TestIsUnique(t *testing.T) {
 	}{
 		{``, false},
 		{`fk:"foo"`, false},
-		{`unique:""`, false},
+		{`unique:""`, true},
+		{`unique:"false"`, false},
 		{`unique:"true"`, true},
 		{`fk:"foo" unique:"true"`, true},
 	}
@@ -488,3 +580,396 @@ func TestIsUnique(t *testing.T) {
 		})
 	}
 }
+
+func TestTimezone(t *testing.T) {
+	cases := []struct {
+		tag string
+		tz  bool
+		err bool
+	}{
+		{``, true, false},
+		{`timezone:"true"`, true, false},
+		{`timezone:"false"`, false, false},
+		{`timezone:"utc"`, false, true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.tag, func(t *testing.T) {
+			f := NewField("", "", reflect.StructTag(tt.tag))
+			tz, err := f.Timezone()
+			if tt.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.tz, tz)
+			}
+		})
+	}
+}
+
+func TestIsDurationInterval(t *testing.T) {
+	cases := []struct {
+		typ      string
+		tag      string
+		interval bool
+		err      bool
+	}{
+		{"time.Duration", ``, false, false},
+		{"time.Duration", `duration:"bigint"`, false, false},
+		{"time.Duration", `duration:"interval"`, true, false},
+		{"time.Duration", `duration:"text"`, false, true},
+		{"int64", `duration:"interval"`, false, false},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.typ+" "+tt.tag, func(t *testing.T) {
+			f := NewField("", tt.typ, reflect.StructTag(tt.tag))
+			interval, err := f.IsDurationInterval()
+			if tt.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.interval, interval)
+			}
+		})
+	}
+}
+
+func TestIsCIText(t *testing.T) {
+	require.True(t, NewField("", "", reflect.StructTag(`citext:""`)).IsCIText())
+	require.False(t, NewField("", "", reflect.StructTag(`unique:"true"`)).IsCIText())
+}
+
+func TestDeprecation(t *testing.T) {
+	notice, ok := NewField("Nick", "", reflect.StructTag(`deprecated:"use Name instead."`)).Deprecation()
+	require.True(t, ok)
+	require.Equal(t, "use Name instead.", notice)
+
+	notice, ok = NewField("Nick", "", reflect.StructTag(`deprecated:""`)).Deprecation()
+	require.True(t, ok)
+	require.Equal(t, "the field Nick will be removed.", notice)
+
+	_, ok = NewField("Nick", "", reflect.StructTag(`unique:"true"`)).Deprecation()
+	require.False(t, ok)
+}
+
+func TestSoftDeleteField(t *testing.T) {
+	r := require.New(t)
+	pkg, err := processFixture(`
+	package fixture
+
+	import (
+		"time"
+
+		"gopkg.in/src-d/go-kallax.v1"
+	)
+
+	type Foo struct {
+		kallax.Model
+		ID        int64 ` + "`pk:\"autoincr\"`" + `
+		DeletedAt *time.Time ` + "`kallax:\",softdelete\"`" + `
+	}
+
+	type Bar struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+	}
+	`)
+	r.NoError(err)
+	r.Equal("deleted_at", findModel(pkg, "Foo").SoftDeleteField().ColumnName())
+	r.Nil(findModel(pkg, "Bar").SoftDeleteField())
+
+	invalid := []string{
+		`DeletedAt time.Time ` + "`kallax:\",softdelete\"`",
+		`DeletedAt *string ` + "`kallax:\",softdelete\"`",
+		`DeletedAt *time.Time ` + "`kallax:\",softdelete\"`" + `
+		RemovedAt *time.Time ` + "`kallax:\",softdelete\"`",
+	}
+
+	for _, field := range invalid {
+		_, err := processFixture(`
+		package fixture
+
+		import (
+			"time"
+
+			"gopkg.in/src-d/go-kallax.v1"
+		)
+
+		var _ time.Time
+
+		type Foo struct {
+			kallax.Model
+			ID int64 ` + "`pk:\"autoincr\"`" + `
+			` + field + `
+		}
+		`)
+		r.Error(err, field)
+	}
+}
+
+func TestLockField(t *testing.T) {
+	r := require.New(t)
+	pkg, err := processFixture(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Foo struct {
+		kallax.Model
+		ID      int64 ` + "`pk:\"autoincr\"`" + `
+		Version int ` + "`kallax:\"lock_version,lock\"`" + `
+	}
+
+	type Bar struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+	}
+	`)
+	r.NoError(err)
+	r.Equal("lock_version", findModel(pkg, "Foo").LockField().ColumnName())
+	r.Nil(findModel(pkg, "Bar").LockField())
+
+	invalid := []string{
+		`Version string ` + "`kallax:\",lock\"`",
+		`Version *int ` + "`kallax:\",lock\"`",
+		`Version int ` + "`kallax:\",lock\"`" + `
+		Revision int ` + "`kallax:\",lock\"`",
+	}
+
+	for _, field := range invalid {
+		_, err := processFixture(`
+		package fixture
+
+		import "gopkg.in/src-d/go-kallax.v1"
+
+		type Foo struct {
+			kallax.Model
+			ID int64 ` + "`pk:\"autoincr\"`" + `
+			` + field + `
+		}
+		`)
+		r.Error(err, field)
+	}
+}
+
+func TestTenantField(t *testing.T) {
+	r := require.New(t)
+	pkg, err := processFixture(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Foo struct {
+		kallax.Model
+		ID     int64 ` + "`pk:\"autoincr\"`" + `
+		Tenant kallax.ULID ` + "`tenant:\"\"`" + `
+	}
+
+	type Bar struct {
+		kallax.Model
+		ID int64 ` + "`pk:\"autoincr\"`" + `
+	}
+	`)
+	r.NoError(err)
+	r.Equal("tenant", findModel(pkg, "Foo").TenantField().ColumnName())
+	r.Nil(findModel(pkg, "Bar").TenantField())
+
+	invalid := []string{
+		`TenantID *int64 ` + "`tenant:\"\"`",
+		`TenantID float64 ` + "`tenant:\"\"`",
+		`TenantID int64 ` + "`tenant:\"\"`" + `
+		OrgID int64 ` + "`tenant:\"\"`",
+	}
+
+	for _, field := range invalid {
+		_, err := processFixture(`
+		package fixture
+
+		import "gopkg.in/src-d/go-kallax.v1"
+
+		type Foo struct {
+			kallax.Model
+			ID int64 ` + "`pk:\"autoincr\"`" + `
+			` + field + `
+		}
+		`)
+		r.Error(err, field)
+	}
+}
+
+func TestEnumField(t *testing.T) {
+	r := require.New(t)
+	pkg, err := processFixture(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Status string
+
+	type Foo struct {
+		kallax.Model
+		ID     int64 ` + "`pk:\"autoincr\"`" + `
+		Status Status ` + "`enum:\"status_type, active, disabled\"`" + `
+		Name   string
+	}
+	`)
+	r.NoError(err)
+
+	m := findModel(pkg, "Foo")
+	name, values, ok := findField(m, "Status").Enum()
+	r.True(ok)
+	r.Equal("status_type", name)
+	r.Equal([]string{"active", "disabled"}, values)
+	_, _, ok = findField(m, "Name").Enum()
+	r.False(ok)
+	r.Equal([]*Field{findField(m, "Status")}, m.EnumFields())
+	r.Equal([]ValidationRule{{Name: "oneof", Param: "active disabled"}}, findField(m, "Status").ValidationRules())
+
+	invalid := map[string]string{
+		`Status string ` + "`enum:\"Status,active\"`":                       "has an invalid type name \"Status\"",
+		`Status string ` + "`enum:\"status_type\"`":                         "needs at least one value",
+		`Status string ` + "`enum:\"status_type,active,,banned\"`":          "has an empty value",
+		`Status string ` + "`enum:\"status_type,active,active\"`":           "has value \"active\" repeated",
+		`Status string ` + "`enum:\"status_type,active\" sqltype:\"text\"`": "can not be used with the struct tag `sqltype`",
+		`Status int64 ` + "`enum:\"status_type,active\"`":                   "can only be used in strings",
+		`Status []string ` + "`enum:\"status_type,active\"`":                "can only be used in strings",
+	}
+
+	for field, msg := range invalid {
+		_, err := processFixture(`
+		package fixture
+
+		import "gopkg.in/src-d/go-kallax.v1"
+
+		type Foo struct {
+			kallax.Model
+			ID int64 ` + "`pk:\"autoincr\"`" + `
+			` + field + `
+		}
+		`)
+		r.EqualError(err, "kallax: struct tag `enum` "+msg+". On field Status of model Foo.", field)
+	}
+}
+
+func TestColumnPrefix(t *testing.T) {
+	r := require.New(t)
+	pkg, err := processFixture(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Geo struct {
+		Lat float64
+		Lng float64
+	}
+
+	type Address struct {
+		Street string
+		Order  int
+		Geo    Geo ` + "`prefix:\"\"`" + `
+	}
+
+	type Foo struct {
+		kallax.Model
+		ID       int64 ` + "`pk:\"autoincr\"`" + `
+		Billing  Address ` + "`prefix:\"\"`" + `
+		Shipping Address ` + "`kallax:\"ship\" prefix:\"\"`" + `
+		Other    Address ` + "`prefix:\"other_\"`" + `
+	}
+	`)
+	r.NoError(err)
+
+	m := findModel(pkg, "Foo")
+	prefix, ok := findField(m, "Shipping").ColumnPrefix()
+	r.True(ok)
+	r.Equal("ship_", prefix)
+	_, ok = findField(m, "ID").ColumnPrefix()
+	r.False(ok)
+
+	billing := findField(m, "Billing")
+	cases := []struct {
+		field  *Field
+		column string
+		name   string
+	}{
+		{billing.Fields[0], "billing_street", "BillingStreet"},
+		{billing.Fields[1], "billing_order", "BillingOrder"},
+		{billing.Fields[2].Fields[0], "billing_geo_lat", "BillingGeoLat"},
+		{findField(m, "Shipping").Fields[0], "ship_street", "ShippingStreet"},
+		{findField(m, "Other").Fields[1], "other_order", "OtherOrder"},
+		{findField(m, "ID"), "id", "ID"},
+	}
+
+	for _, c := range cases {
+		r.Equal(c.column, c.field.ColumnName(), c.name)
+		r.Equal(c.name, c.field.SchemaName(), c.name)
+	}
+
+	invalid := map[string]string{
+		`Address *Address ` + "`prefix:\"\"`":        "kallax: struct tag `prefix` can only be used in struct fields that are neither pointers, embedded nor composite types. On field Address of model Foo.",
+		`Name string ` + "`prefix:\"\"`":             "kallax: struct tag `prefix` can only be used in struct fields that are neither pointers, embedded nor composite types. On field Name of model Foo.",
+		`Address Address ` + "`prefix:\"Address-\"`": "kallax: struct tag `prefix` has an invalid prefix \"Address-\". On field Address of model Foo.",
+		`Address Address ` + "`prefix:\"\"`" + `
+		AddressStreet string`: "kallax: the following fields are repeated: [AddressStreet]",
+		`Address Address ` + "`prefix:\"\"`" + `
+		AddressCity string ` + "`kallax:\"address_street\"`": "kallax: the following column names are repeated: [address_street]",
+	}
+
+	for field, msg := range invalid {
+		_, err := processFixture(`
+		package fixture
+
+		import "gopkg.in/src-d/go-kallax.v1"
+
+		type Address struct {
+			Street string
+		}
+
+		type Foo struct {
+			kallax.Model
+			ID int64 ` + "`pk:\"autoincr\"`" + `
+			` + field + `
+		}
+		`)
+		r.EqualError(err, msg, field)
+	}
+}
+
+func TestUUIDVersion(t *testing.T) {
+	r := require.New(t)
+	pkg, err := processFixture(`
+	package fixture
+
+	import "gopkg.in/src-d/go-kallax.v1"
+
+	type Foo struct {
+		kallax.Model
+		ID kallax.UUID ` + "`pk:\"\" uuid:\"v7\"`" + `
+	}
+	`)
+	r.NoError(err)
+
+	version, err := findModel(pkg, "Foo").ID.UUIDVersion()
+	r.NoError(err)
+	r.Equal("v7", version)
+
+	invalid := []string{
+		`ID kallax.UUID ` + "`pk:\"\" uuid:\"v1\"`",
+		`ID int64 ` + "`pk:\"autoincr\" uuid:\"v4\"`",
+	}
+
+	for _, field := range invalid {
+		_, err := processFixture(`
+		package fixture
+
+		import "gopkg.in/src-d/go-kallax.v1"
+
+		type Foo struct {
+			kallax.Model
+			` + field + `
+		}
+		`)
+		r.Error(err, field)
+	}
+}
@@ -0,0 +1,199 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+// ValidationRule is a rule of the struct tag `validate` of a field, such as
+// `validate:"required,max=255,email"`, which the generated Validate method of
+// its model checks.
+type ValidationRule struct {
+	// Name is the name of the rule.
+	Name string
+	// Param is the parameter of the rule, which is empty if it has none.
+	Param string
+}
+
+// String returns the rule as it is written in the struct tag.
+func (r ValidationRule) String() string {
+	if r.Param == "" {
+		return r.Name
+	}
+	return r.Name + "=" + r.Param
+}
+
+// ValidationRules returns the rules of the struct tag `validate` of the field,
+// followed by the oneof rule of the values of its enum type, if it has one.
+func (f *Field) ValidationRules() []ValidationRule {
+	var rules []ValidationRule
+	for _, part := range strings.Split(f.Tag.Get("validate"), ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		var r ValidationRule
+		if i := strings.Index(part, "="); i >= 0 {
+			r.Name, r.Param = strings.TrimSpace(part[:i]), strings.TrimSpace(part[i+1:])
+		} else {
+			r.Name = part
+		}
+		rules = append(rules, r)
+	}
+
+	if _, _, ok := f.Enum(); ok {
+		rules = append(rules, enumRule(f))
+	}
+	return rules
+}
+
+// ValidationFields returns the fields of the model with the struct tags
+// `validate` or `enum`.
+func (m *Model) ValidationFields() []*Field {
+	return validationFields(m.Fields)
+}
+
+// HasValidations returns whether the model has fields with the struct tags
+// `validate` or `enum` or not.
+func (m *Model) HasValidations() bool {
+	return len(m.ValidationFields()) > 0
+}
+
+func validationFields(fields []*Field) []*Field {
+	var result []*Field
+	for _, f := range fields {
+		if f.Inline() {
+			result = append(result, validationFields(f.Fields)...)
+		} else if len(f.ValidationRules()) > 0 {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// validateRules returns an error if the rules of the struct tag `validate`
+// of any of the given fields are unknown, have invalid parameters or can not
+// be checked with the type of the field.
+func validateRules(fields []*Field) error {
+	for _, f := range validationFields(fields) {
+		for _, r := range f.ValidationRules() {
+			if err := validateRule(f, r); err != nil {
+				return fmt.Errorf("kallax: rule %s of struct tag `validate` %s. On field %s of model %s.", r, err, f.Name, f.Model.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func validateRule(f *Field, r ValidationRule) error {
+	typ := validationType(f)
+	switch r.Name {
+	case "required", "omitempty":
+		if r.Param != "" {
+			return fmt.Errorf("has no parameter")
+		}
+	case "min", "max":
+		if _, err := strconv.ParseFloat(r.Param, 64); err != nil {
+			return fmt.Errorf("needs a number")
+		}
+		if typ == "" {
+			return fmt.Errorf("can only be used in numbers, strings, slices, arrays and maps")
+		}
+	case "len":
+		if n, err := strconv.Atoi(r.Param); err != nil || n < 0 {
+			return fmt.Errorf("needs a length")
+		}
+		if typ != "string" && typ != "collection" {
+			return fmt.Errorf("can only be used in strings, slices, arrays and maps")
+		}
+	case "email", "url":
+		if r.Param != "" {
+			return fmt.Errorf("has no parameter")
+		}
+		if typ != "string" {
+			return fmt.Errorf("can only be used in strings")
+		}
+	case "oneof":
+		if r.Param == "" {
+			return fmt.Errorf("needs at least one value")
+		}
+		if typ != "string" && typ != "integer" {
+			return fmt.Errorf("can only be used in strings and integers")
+		}
+	default:
+		return fmt.Errorf("is unknown")
+	}
+	return nil
+}
+
+// validationType returns the type of the values of the field that the rules
+// can be checked with, which is "string", "integer", "number" or
+// "collection", or an empty string if it's none of them.
+func validationType(f *Field) string {
+	if f.Node != nil {
+		typ := f.Node.Type()
+		if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+			typ = ptr.Elem()
+		}
+
+		switch t := typ.Underlying().(type) {
+		case *types.Basic:
+			switch info := t.Info(); {
+			case info&types.IsString != 0:
+				return "string"
+			case info&types.IsInteger != 0:
+				return "integer"
+			case info&types.IsFloat != 0:
+				return "number"
+			}
+		case *types.Slice, *types.Array, *types.Map:
+			return "collection"
+		}
+		return ""
+	}
+
+	switch f.Kind {
+	case Slice, Array, Map:
+		return "collection"
+	case Basic:
+		switch typ := strings.TrimPrefix(f.Type, "*"); {
+		case typ == "string":
+			return "string"
+		case typ == "float32" || typ == "float64":
+			return "number"
+		case isIntegerType(&Field{Kind: Basic, Type: typ}):
+			return "integer"
+		}
+	}
+	return ""
+}
+
+// ruleConstructor returns the code of the kallax.ValidationRule of the given
+// rule.
+func ruleConstructor(r ValidationRule) string {
+	switch r.Name {
+	case "required":
+		return "kallax.RequiredRule()"
+	case "omitempty":
+		return "kallax.OmitEmptyRule()"
+	case "min":
+		return fmt.Sprintf("kallax.MinRule(%s)", r.Param)
+	case "max":
+		return fmt.Sprintf("kallax.MaxRule(%s)", r.Param)
+	case "len":
+		return fmt.Sprintf("kallax.LenRule(%s)", r.Param)
+	case "email":
+		return "kallax.EmailRule()"
+	case "url":
+		return "kallax.URLRule()"
+	}
+
+	var values []string
+	for _, v := range strings.Fields(r.Param) {
+		values = append(values, strconv.Quote(v))
+	}
+	return fmt.Sprintf("kallax.OneOfRule(%s)", strings.Join(values, ", "))
+}
@@ -0,0 +1,260 @@
+package kallax
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	wkbPointType = 1
+	ewkbSRIDFlag = 0x20000000
+	ewkbZFlag    = 0x80000000
+	ewkbMFlag    = 0x40000000
+)
+
+// Point is a 2D point stored in a PostGIS geometry column. It can be scanned
+// from both the (E)WKB and (E)WKT representations of a point.
+type Point struct {
+	// X is the first coordinate of the point, e.g. the longitude.
+	X float64
+	// Y is the second coordinate of the point, e.g. the latitude.
+	Y float64
+	// SRID is the spatial reference system identifier of the point. If it's
+	// 0, the point has no SRID.
+	SRID int
+}
+
+// NewPoint returns a new point with the given coordinates and SRID.
+func NewPoint(x, y float64, srid int) Point {
+	return Point{X: x, Y: y, SRID: srid}
+}
+
+// String returns the EWKT representation of the point.
+func (p Point) String() string {
+	wkt := fmt.Sprintf(
+		"POINT(%s %s)",
+		strconv.FormatFloat(p.X, 'f', -1, 64),
+		strconv.FormatFloat(p.Y, 'f', -1, 64),
+	)
+	if p.SRID != 0 {
+		return fmt.Sprintf("SRID=%d;%s", p.SRID, wkt)
+	}
+	return wkt
+}
+
+// Scan implements the sql.Scanner interface.
+func (p *Point) Scan(v interface{}) error {
+	switch t := v.(type) {
+	case []byte:
+		return p.Scan(string(t))
+	case string:
+		if isWKT(t) {
+			return p.scanWKT(t)
+		}
+
+		var g Geometry
+		if err := g.Scan(t); err != nil {
+			return err
+		}
+
+		point, err := g.Point()
+		if err != nil {
+			return err
+		}
+
+		*p = point
+		return nil
+	}
+	return fmt.Errorf("kallax: cannot scan type %s into Point type", reflect.TypeOf(v))
+}
+
+func (p *Point) scanWKT(s string) error {
+	var srid int
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(strings.ToUpper(s), "SRID=") {
+		idx := strings.Index(s, ";")
+		if idx < 0 {
+			return fmt.Errorf("kallax: invalid point %q", s)
+		}
+
+		var err error
+		srid, err = strconv.Atoi(s[5:idx])
+		if err != nil {
+			return fmt.Errorf("kallax: invalid point SRID %q: %s", s, err)
+		}
+		s = s[idx+1:]
+	}
+
+	upper := strings.ToUpper(s)
+	if !strings.HasPrefix(upper, "POINT") {
+		return fmt.Errorf("kallax: invalid point %q", s)
+	}
+
+	s = strings.TrimSpace(s[len("POINT"):])
+	if !strings.HasPrefix(s, "(") || !strings.HasSuffix(s, ")") {
+		return fmt.Errorf("kallax: invalid point %q", s)
+	}
+
+	coords := strings.Fields(s[1 : len(s)-1])
+	if len(coords) != 2 {
+		return fmt.Errorf("kallax: only 2D points are supported, got %q", s)
+	}
+
+	x, err := strconv.ParseFloat(coords[0], 64)
+	if err != nil {
+		return fmt.Errorf("kallax: invalid point coordinate %q: %s", coords[0], err)
+	}
+
+	y, err := strconv.ParseFloat(coords[1], 64)
+	if err != nil {
+		return fmt.Errorf("kallax: invalid point coordinate %q: %s", coords[1], err)
+	}
+
+	*p = Point{X: x, Y: y, SRID: srid}
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (p Point) Value() (driver.Value, error) {
+	return p.String(), nil
+}
+
+// Geometry is an arbitrary geometry stored in a PostGIS geometry column.
+// The geometry is kept in its WKB representation.
+type Geometry struct {
+	// SRID is the spatial reference system identifier of the geometry. If
+	// it's 0, the geometry has no SRID.
+	SRID int
+	// WKB is the well-known binary representation of the geometry, without
+	// the SRID.
+	WKB []byte
+}
+
+// Point returns the geometry as a point. An error is returned if the
+// geometry is not a 2D point.
+func (g Geometry) Point() (Point, error) {
+	if len(g.WKB) != 21 {
+		return Point{}, fmt.Errorf("kallax: geometry is not a 2D point")
+	}
+
+	order, err := wkbByteOrder(g.WKB[0])
+	if err != nil {
+		return Point{}, err
+	}
+
+	if typ := order.Uint32(g.WKB[1:5]); typ != wkbPointType {
+		return Point{}, fmt.Errorf("kallax: geometry of type %d is not a 2D point", typ)
+	}
+
+	return Point{
+		X:    math.Float64frombits(order.Uint64(g.WKB[5:13])),
+		Y:    math.Float64frombits(order.Uint64(g.WKB[13:21])),
+		SRID: g.SRID,
+	}, nil
+}
+
+// Scan implements the sql.Scanner interface. The geometry is expected in
+// the hex-encoded EWKB format, which is the default output of PostGIS.
+func (g *Geometry) Scan(v interface{}) error {
+	switch t := v.(type) {
+	case []byte:
+		return g.Scan(string(t))
+	case string:
+		data, err := hex.DecodeString(t)
+		if err != nil {
+			return fmt.Errorf("kallax: invalid EWKB geometry: %s", err)
+		}
+
+		return g.scanEWKB(data)
+	}
+	return fmt.Errorf("kallax: cannot scan type %s into Geometry type", reflect.TypeOf(v))
+}
+
+func (g *Geometry) scanEWKB(data []byte) error {
+	if len(data) < 5 {
+		return fmt.Errorf("kallax: invalid EWKB geometry: too short")
+	}
+
+	order, err := wkbByteOrder(data[0])
+	if err != nil {
+		return err
+	}
+
+	typ := order.Uint32(data[1:5])
+	if typ&(ewkbZFlag|ewkbMFlag) != 0 {
+		return fmt.Errorf("kallax: only 2D geometries are supported")
+	}
+
+	var srid int
+	rest := data[5:]
+	if typ&ewkbSRIDFlag != 0 {
+		if len(rest) < 4 {
+			return fmt.Errorf("kallax: invalid EWKB geometry: missing SRID")
+		}
+		srid = int(order.Uint32(rest[:4]))
+		rest = rest[4:]
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte(data[0])
+	var typBytes [4]byte
+	order.PutUint32(typBytes[:], typ&^ewkbSRIDFlag)
+	buf.Write(typBytes[:])
+	buf.Write(rest)
+
+	*g = Geometry{SRID: srid, WKB: buf.Bytes()}
+	return nil
+}
+
+// Value implements the driver.Valuer interface. The geometry is sent as hex
+// encoded EWKB, the same way PostGIS outputs it.
+func (g Geometry) Value() (driver.Value, error) {
+	if g.WKB == nil {
+		return nil, nil
+	}
+
+	if g.SRID == 0 {
+		return strings.ToUpper(hex.EncodeToString(g.WKB)), nil
+	}
+
+	if len(g.WKB) < 5 {
+		return nil, fmt.Errorf("kallax: invalid WKB geometry: too short")
+	}
+
+	order, err := wkbByteOrder(g.WKB[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte(g.WKB[0])
+	var typ, srid [4]byte
+	order.PutUint32(typ[:], order.Uint32(g.WKB[1:5])|ewkbSRIDFlag)
+	order.PutUint32(srid[:], uint32(g.SRID))
+	buf.Write(typ[:])
+	buf.Write(srid[:])
+	buf.Write(g.WKB[5:])
+	return strings.ToUpper(hex.EncodeToString(buf.Bytes())), nil
+}
+
+func wkbByteOrder(b byte) (binary.ByteOrder, error) {
+	switch b {
+	case 0:
+		return binary.BigEndian, nil
+	case 1:
+		return binary.LittleEndian, nil
+	}
+	return nil, fmt.Errorf("kallax: invalid WKB byte order %d", b)
+}
+
+func isWKT(s string) bool {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	return strings.HasPrefix(s, "SRID=") || strings.HasPrefix(s, "POINT")
+}
@@ -0,0 +1,98 @@
+package kallax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	pointEWKB = "0101000020E6100000000000000000F03F0000000000000040"
+	pointWKB  = "0101000000000000000000F03F0000000000000040"
+)
+
+func TestPoint_Scan(t *testing.T) {
+	cases := []struct {
+		input    interface{}
+		expected Point
+	}{
+		{pointEWKB, NewPoint(1, 2, 4326)},
+		{[]byte(pointWKB), NewPoint(1, 2, 0)},
+		{"POINT(1 2)", NewPoint(1, 2, 0)},
+		{"SRID=4326;POINT(-3.5 40.25)", NewPoint(-3.5, 40.25, 4326)},
+		{"point (1 2)", NewPoint(1, 2, 0)},
+	}
+
+	for _, c := range cases {
+		var p Point
+		require.NoError(t, p.Scan(c.input), "%v", c.input)
+		require.Equal(t, c.expected, p, "%v", c.input)
+	}
+}
+
+func TestPoint_ScanInvalid(t *testing.T) {
+	cases := []interface{}{
+		"POINT(1 2 3)",
+		"POINT(1)",
+		"SRID=foo;POINT(1 2)",
+		"LINESTRING(1 2, 3 4)",
+		"zz",
+		1,
+	}
+
+	for _, c := range cases {
+		var p Point
+		require.Error(t, p.Scan(c), "%v", c)
+	}
+}
+
+func TestPoint_Value(t *testing.T) {
+	v, err := NewPoint(1.5, -2, 4326).Value()
+	require.NoError(t, err)
+	require.Equal(t, "SRID=4326;POINT(1.5 -2)", v)
+
+	v, err = NewPoint(1, 2, 0).Value()
+	require.NoError(t, err)
+	require.Equal(t, "POINT(1 2)", v)
+}
+
+func TestGeometry_ScanValue(t *testing.T) {
+	r := require.New(t)
+
+	var g Geometry
+	r.NoError(g.Scan(pointEWKB))
+	r.Equal(4326, g.SRID)
+
+	p, err := g.Point()
+	r.NoError(err)
+	r.Equal(NewPoint(1, 2, 4326), p)
+
+	v, err := g.Value()
+	r.NoError(err)
+	r.Equal(pointEWKB, v)
+
+	g.SRID = 0
+	v, err = g.Value()
+	r.NoError(err)
+	r.Equal(pointWKB, v)
+
+	v, err = Geometry{}.Value()
+	r.NoError(err)
+	r.Nil(v)
+}
+
+func TestGeoOperators(t *testing.T) {
+	r := require.New(t)
+	col := f("location")
+	schema := NewBaseSchema("places", "__places", f("id"), nil, nil, false, f("id"), col)
+
+	sql, args, err := GeoDWithin(col, NewPoint(1, 2, 4326), 10)(schema).ToSql()
+	r.NoError(err)
+	r.Equal("ST_DWithin(__places.location, CAST(? AS geometry), ?)", sql)
+	r.Equal([]interface{}{NewPoint(1, 2, 4326), float64(10)}, args)
+
+	sql, args, err = GeoWithin(col, Geometry{SRID: 4326})(schema).ToSql()
+	r.NoError(err)
+	r.Equal("ST_Within(__places.location, CAST(? AS geometry))", sql)
+	r.Len(args, 1)
+}
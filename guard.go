@@ -0,0 +1,537 @@
+package kallax
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// ErrUnboundedWrite is returned by RejectUnboundedWrites when a statement
+// updates or deletes all the rows of a table.
+var ErrUnboundedWrite = errors.New("kallax: UPDATE and DELETE statements without a WHERE clause, and TRUNCATE statements, are not allowed")
+
+// QueryGuard is a function that inspects every statement before a store runs
+// it. If it returns an error, the statement is not run and the store returns
+// the error instead.
+type QueryGuard func(*GuardedStatement) error
+
+// GuardedStatement is a statement inspected by a query guard before it is
+// run. Its SQL is inspected without a parser, so it only describes the
+// statement as far as it can be told from its keywords.
+type GuardedStatement struct {
+	// Query is the SQL of the statement.
+	Query string
+	// Args are the arguments of the statement.
+	Args []interface{}
+	// Kind is the uppercased keyword of the statement, such as SELECT,
+	// INSERT, UPDATE or DELETE. For the statements with WITH queries, it is
+	// the keyword of the main statement.
+	Kind string
+	// Tables are the names of the tables read or written by the statement,
+	// including the ones of its subqueries, in the order they first appear.
+	// Names are qualified with the schema only if they are in the query.
+	Tables []string
+	// HasWhere reports whether the main statement has a WHERE clause, not
+	// counting the ones of its subqueries and WITH queries.
+	HasWhere bool
+
+	tokens  []sqlToken
+	ctx     context.Context
+	runner  squirrel.DBProxyContext
+	dialect Dialect
+}
+
+// EstimatedCost returns the total cost of the statement estimated by the
+// query planner with EXPLAIN, in the arbitrary units of the planner, without
+// running the statement. It is only supported by the PostgreSQL dialect.
+func (s *GuardedStatement) EstimatedCost() (float64, error) {
+	if !s.dialect.Supports(FeatureExplain) {
+		return 0, &UnsupportedError{Dialect: s.dialect.Name(), Feature: FeatureExplain}
+	}
+
+	var output []byte
+	ctx := s.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	row := runnerContext(s.runner).QueryRowContext(ctx, "EXPLAIN (FORMAT JSON) "+s.Query, s.Args...)
+	if err := row.Scan(&output); err != nil {
+		return 0, err
+	}
+
+	var plans []struct {
+		Plan struct {
+			TotalCost float64 `json:"Total Cost"`
+		}
+	}
+	if err := json.Unmarshal(output, &plans); err != nil || len(plans) == 0 {
+		return 0, fmt.Errorf("kallax: unable to read the plan of the statement from the EXPLAIN output %q", output)
+	}
+	return plans[0].Plan.TotalCost, nil
+}
+
+// WithGuard returns a new store that runs all its statements through the
+// given guards, in order, after the ones of the store, if any, and rejects
+// the statements for which any guard returns an error. Guards are meant to
+// protect production databases from dangerous statements, such as unbounded
+// DELETEs or full scans of big tables, so they also reject raw statements.
+func (s *Store) WithGuard(guards ...QueryGuard) *Store {
+	store := s.clone()
+	store.guards = append(append([]QueryGuard(nil), s.guards...), guards...)
+	return store.init()
+}
+
+// RejectUnboundedWrites is a query guard that rejects with ErrUnboundedWrite
+// the statements that update or delete all the rows of a table, which are
+// the UPDATE and DELETE statements without a WHERE clause, including the
+// ones in subqueries and WITH queries, and the TRUNCATE statements.
+func RejectUnboundedWrites(s *GuardedStatement) error {
+	if hasUnboundedWrite(s.tokens) {
+		return ErrUnboundedWrite
+	}
+	return nil
+}
+
+// AllowTables returns a query guard that rejects the statements that read or
+// write any table that is not one of the given ones.
+func AllowTables(tables ...string) QueryGuard {
+	allowed := make(map[string]bool, len(tables))
+	for _, t := range tables {
+		allowed[t] = true
+	}
+
+	return func(s *GuardedStatement) error {
+		for _, t := range s.Tables {
+			if !allowed[t] {
+				return fmt.Errorf("kallax: table %s is not allowed by the query guard", t)
+			}
+		}
+		return nil
+	}
+}
+
+// MaxCost returns a query guard that rejects the SELECT, INSERT, UPDATE and
+// DELETE statements whose cost estimated by the query planner exceeds the
+// given one. The cost of every statement is estimated with an EXPLAIN
+// statement run before it, and all of them fail with a dialect that does not
+// support it.
+func MaxCost(cost float64) QueryGuard {
+	return func(s *GuardedStatement) error {
+		switch s.Kind {
+		case "SELECT", "INSERT", "UPDATE", "DELETE":
+		default:
+			return nil
+		}
+
+		estimated, err := s.EstimatedCost()
+		if err != nil {
+			return err
+		}
+
+		if estimated > cost {
+			return fmt.Errorf("kallax: the estimated cost %.2f of the statement exceeds the maximum cost %.2f allowed by the query guard", estimated, cost)
+		}
+		return nil
+	}
+}
+
+// guardRunner runs the statements accepted by all its guards with the
+// wrapped runner.
+type guardRunner struct {
+	squirrel.DBProxyContext
+	dialect Dialect
+	guards  []QueryGuard
+}
+
+func (r *guardRunner) check(ctx context.Context, query string, args []interface{}) error {
+	stmt := inspectStatement(query)
+	stmt.Args = args
+	stmt.ctx = ctx
+	stmt.runner = r.DBProxyContext
+	stmt.dialect = r.dialect
+	for _, guard := range r.guards {
+		if err := guard(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *guardRunner) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return r.ExecContext(context.Background(), query, args...)
+}
+
+func (r *guardRunner) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if err := r.check(ctx, query, args); err != nil {
+		return nil, err
+	}
+	return runnerContext(r.DBProxyContext).ExecContext(ctx, query, args...)
+}
+
+func (r *guardRunner) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return r.QueryContext(context.Background(), query, args...)
+}
+
+func (r *guardRunner) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if err := r.check(ctx, query, args); err != nil {
+		return nil, err
+	}
+	return runnerContext(r.DBProxyContext).QueryContext(ctx, query, args...)
+}
+
+func (r *guardRunner) QueryRow(query string, args ...interface{}) squirrel.RowScanner {
+	return r.QueryRowContext(context.Background(), query, args...)
+}
+
+func (r *guardRunner) QueryRowContext(ctx context.Context, query string, args ...interface{}) squirrel.RowScanner {
+	if err := r.check(ctx, query, args); err != nil {
+		return errRow{err}
+	}
+	return runnerContext(r.DBProxyContext).QueryRowContext(ctx, query, args...)
+}
+
+// sqlToken is a word, quoted identifier or symbol of a statement. String
+// literals and comments are skipped.
+type sqlToken struct {
+	text string
+	// depth is the number of parentheses the token is nested in. Opening
+	// and closing parentheses have the depth of their enclosing tokens.
+	depth int
+	// ident reports whether the token is a word or a quoted identifier.
+	ident bool
+	// quoted reports whether the token is a quoted identifier, so it is
+	// never a keyword.
+	quoted bool
+}
+
+// is reports whether the token is the given uppercased keyword.
+func (t sqlToken) is(keyword string) bool {
+	return t.ident && !t.quoted && strings.EqualFold(t.text, keyword)
+}
+
+// clauseKeywords are the keywords that can follow a table name, so they are
+// not taken as its alias or as a table name themselves.
+var clauseKeywords = map[string]bool{
+	"AS": true, "CROSS": true, "DEFAULT": true, "DO": true, "EXCEPT": true,
+	"FOR": true, "FULL": true, "GROUP": true, "HAVING": true, "INNER": true,
+	"INTERSECT": true, "JOIN": true, "LATERAL": true, "LEFT": true,
+	"LIMIT": true, "NATURAL": true, "OFFSET": true, "ON": true, "ORDER": true,
+	"OUTER": true, "RETURNING": true, "RIGHT": true, "SELECT": true,
+	"SET": true, "UNION": true, "USING": true, "VALUES": true, "WHERE": true,
+	"WINDOW": true,
+}
+
+func isClauseKeyword(t sqlToken) bool {
+	return t.ident && !t.quoted && clauseKeywords[strings.ToUpper(t.text)]
+}
+
+func tokenizeSQL(query string) []sqlToken {
+	var (
+		tokens []sqlToken
+		depth  int
+	)
+
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(query[i:], "--"):
+			if end := strings.IndexByte(query[i:], '\n'); end >= 0 {
+				i += end + 1
+			} else {
+				i = len(query)
+			}
+		case strings.HasPrefix(query[i:], "/*"):
+			if end := strings.Index(query[i+2:], "*/"); end >= 0 {
+				i += end + 4
+			} else {
+				i = len(query)
+			}
+		case c == '\'':
+			i = skipQuoted(query, i)
+		case c == '"' || c == '`':
+			end := skipQuoted(query, i)
+			text := strings.TrimSuffix(query[i+1:end], string(c))
+			text = strings.Replace(text, string([]byte{c, c}), string(c), -1)
+			tokens = append(tokens, sqlToken{text: text, depth: depth, ident: true, quoted: true})
+			i = end
+		case c == '$' && i+1 < len(query) && !isDigit(query[i+1]):
+			i = skipDollarQuoted(query, i)
+		case c == '(':
+			tokens = append(tokens, sqlToken{text: "(", depth: depth})
+			depth++
+			i++
+		case c == ')':
+			if depth > 0 {
+				depth--
+			}
+			tokens = append(tokens, sqlToken{text: ")", depth: depth})
+			i++
+		case isIdentByte(c):
+			end := i + 1
+			for end < len(query) && (isIdentByte(query[end]) || query[end] == '$') {
+				end++
+			}
+			tokens = append(tokens, sqlToken{text: query[i:end], depth: depth, ident: true})
+			i = end
+		default:
+			tokens = append(tokens, sqlToken{text: string(c), depth: depth})
+			i++
+		}
+	}
+	return tokens
+}
+
+// skipQuoted returns the position after the string literal or quoted
+// identifier starting at the given position, whose quotes are escaped by
+// doubling them.
+func skipQuoted(query string, i int) int {
+	quote := query[i]
+	for j := i + 1; j < len(query); j++ {
+		if query[j] != quote {
+			continue
+		}
+
+		if j+1 < len(query) && query[j+1] == quote {
+			j++
+			continue
+		}
+		return j + 1
+	}
+	return len(query)
+}
+
+// skipDollarQuoted returns the position after the dollar-quoted string
+// starting at the given position, or after the dollar sign if it does not
+// start one.
+func skipDollarQuoted(query string, i int) int {
+	end := i + 1
+	for end < len(query) && isIdentByte(query[end]) {
+		end++
+	}
+
+	if end >= len(query) || query[end] != '$' {
+		return i + 1
+	}
+
+	tag := query[i : end+1]
+	if n := strings.Index(query[end+1:], tag); n >= 0 {
+		return end + 1 + n + len(tag)
+	}
+	return len(query)
+}
+
+// isIdentByte reports whether the given byte can be part of an unquoted
+// identifier, including the bytes of non-ASCII letters.
+func isIdentByte(c byte) bool {
+	return isIdentifier(c) || c >= 0x80
+}
+
+// inspectStatement returns the guarded statement of the given query.
+func inspectStatement(query string) *GuardedStatement {
+	tokens := tokenizeSQL(query)
+	stmt := &GuardedStatement{Query: query, tokens: tokens}
+
+	main := -1
+	for i, t := range tokens {
+		if t.depth == 0 && startsStatement(tokens, i) {
+			main = i
+			break
+		}
+	}
+
+	switch {
+	case main >= 0:
+		stmt.Kind = strings.ToUpper(tokens[main].text)
+		for _, t := range tokens[main+1:] {
+			if t.depth == 0 && t.is("WHERE") {
+				stmt.HasWhere = true
+				break
+			}
+		}
+	case len(tokens) > 0:
+		stmt.Kind = strings.ToUpper(tokens[0].text)
+	}
+
+	stmt.Tables = statementTables(tokens)
+	return stmt
+}
+
+// startsStatement reports whether the token at the given position is the
+// keyword of a SELECT, INSERT, UPDATE, DELETE or TRUNCATE statement, and not
+// of a clause such as FOR UPDATE, ON DELETE or ON CONFLICT DO UPDATE.
+func startsStatement(tokens []sqlToken, i int) bool {
+	t := tokens[i]
+	if !t.is("SELECT") && !t.is("INSERT") && !t.is("UPDATE") && !t.is("DELETE") && !t.is("TRUNCATE") {
+		return false
+	}
+
+	if i == 0 {
+		return true
+	}
+
+	switch prev := tokens[i-1]; prev.text {
+	case "(", ")", ";":
+		return !prev.ident
+	}
+	return false
+}
+
+// isTableFrom reports whether the FROM keyword at the given position is the
+// one of a SELECT or DELETE statement, and not of an expression such as
+// EXTRACT(YEAR FROM t) or IS DISTINCT FROM.
+func isTableFrom(tokens []sqlToken, i int) bool {
+	if i > 0 && tokens[i-1].is("DISTINCT") {
+		return false
+	}
+
+	depth := tokens[i].depth
+	for j := i - 1; j >= 0 && tokens[j].depth >= depth; j-- {
+		if tokens[j].depth == depth && (tokens[j].is("SELECT") || tokens[j].is("DELETE")) {
+			return true
+		}
+	}
+	return false
+}
+
+// statementTables returns the tables of the given tokens of a statement,
+// which are the ones after FROM, JOIN, INTO, UPDATE and TRUNCATE, excluding
+// the names of its WITH queries.
+func statementTables(tokens []sqlToken) []string {
+	ctes := make(map[string]bool)
+	for i, t := range tokens {
+		if t.is("WITH") {
+			for _, name := range withQueryNames(tokens, i) {
+				ctes[name] = true
+			}
+		}
+	}
+
+	var (
+		tables []string
+		seen   = make(map[string]bool)
+	)
+	for i, t := range tokens {
+		switch {
+		case t.is("FROM") && isTableFrom(tokens, i), t.is("JOIN"), t.is("INTO"):
+		case (t.is("UPDATE") || t.is("TRUNCATE")) && startsStatement(tokens, i):
+		default:
+			continue
+		}
+
+		for _, name := range tableList(tokens, i+1) {
+			if !ctes[name] && !seen[name] {
+				seen[name] = true
+				tables = append(tables, name)
+			}
+		}
+	}
+	return tables
+}
+
+// tableList returns the comma-separated list of tables starting at the given
+// position, with their optional aliases.
+func tableList(tokens []sqlToken, i int) []string {
+	var tables []string
+	for i < len(tokens) {
+		for i < len(tokens) && (tokens[i].is("ONLY") || tokens[i].is("TABLE")) {
+			i++
+		}
+
+		if i >= len(tokens) || !tokens[i].ident || isClauseKeyword(tokens[i]) {
+			break
+		}
+
+		depth := tokens[i].depth
+		name := tokens[i].text
+		i++
+		for i+1 < len(tokens) && tokens[i].text == "." && !tokens[i].ident && tokens[i+1].ident {
+			name += "." + tokens[i+1].text
+			i += 2
+		}
+		tables = append(tables, name)
+
+		if i < len(tokens) && tokens[i].is("AS") {
+			i++
+		}
+		if i < len(tokens) && tokens[i].ident && !isClauseKeyword(tokens[i]) {
+			i++
+		}
+
+		if i >= len(tokens) || tokens[i].text != "," || tokens[i].ident || tokens[i].depth != depth {
+			break
+		}
+		i++
+	}
+	return tables
+}
+
+// withQueryNames returns the names of the WITH queries of the WITH keyword
+// at the given position.
+func withQueryNames(tokens []sqlToken, i int) []string {
+	depth := tokens[i].depth
+	i++
+	if i < len(tokens) && tokens[i].is("RECURSIVE") {
+		i++
+	}
+
+	var names []string
+	for i < len(tokens) && tokens[i].ident {
+		names = append(names, tokens[i].text)
+		i++
+
+		// skip the column list and the query itself
+		for i < len(tokens) && !(tokens[i].depth == depth && (tokens[i].text == "," || startsStatement(tokens, i))) {
+			i++
+		}
+
+		if i >= len(tokens) || tokens[i].text != "," {
+			break
+		}
+		i++
+	}
+	return names
+}
+
+// hasUnboundedWrite reports whether any of the UPDATE and DELETE statements
+// of the given tokens, including the ones in subqueries and WITH queries,
+// has no WHERE clause, or there is any TRUNCATE statement.
+func hasUnboundedWrite(tokens []sqlToken) bool {
+	for i, t := range tokens {
+		if !startsStatement(tokens, i) {
+			continue
+		}
+
+		if t.is("TRUNCATE") {
+			return true
+		}
+
+		if !t.is("UPDATE") && !t.is("DELETE") {
+			continue
+		}
+
+		bounded := false
+		for _, next := range tokens[i+1:] {
+			if next.depth < t.depth || (next.depth == t.depth && next.text == ";") {
+				break
+			}
+
+			if next.depth == t.depth && next.is("WHERE") {
+				bounded = true
+				break
+			}
+		}
+
+		if !bounded {
+			return true
+		}
+	}
+	return false
+}
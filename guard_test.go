@@ -0,0 +1,123 @@
+package kallax
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectStatement(t *testing.T) {
+	cases := []struct {
+		query    string
+		kind     string
+		tables   []string
+		hasWhere bool
+	}{
+		{
+			"SELECT __model.id, __model.name FROM model __model LEFT JOIN rel __rel ON (__rel.model_id = __model.id) WHERE __model.id = $1",
+			"SELECT", []string{"model", "rel"}, true,
+		},
+		{
+			"SELECT EXTRACT(YEAR FROM created_at) FROM public.model AS m, \"Other\" o WHERE m.id IN (SELECT model_id FROM rel)",
+			"SELECT", []string{"public.model", "Other", "rel"}, true,
+		},
+		{"INSERT INTO model (name, email) VALUES ($1, $2) RETURNING id", "INSERT", []string{"model"}, false},
+		{"UPDATE model SET name=$1 WHERE id=$2", "UPDATE", []string{"model"}, true},
+		{"delete from model -- WHERE id = 1", "DELETE", []string{"model"}, false},
+		{"DELETE FROM model WHERE name = 'FROM foo WHERE'", "DELETE", []string{"model"}, true},
+		{
+			"WITH q1 AS (DELETE FROM model WHERE id=$1), q2 AS (UPDATE rel SET x=$2 WHERE id=$3) SELECT (SELECT COUNT(*) FROM q2)",
+			"SELECT", []string{"model", "rel"}, false,
+		},
+		{"SELECT * FROM model FOR UPDATE OF model", "SELECT", []string{"model"}, false},
+		{"TRUNCATE TABLE ONLY model, rel", "TRUNCATE", []string{"model", "rel"}, false},
+		{"SHOW server_version_num", "SHOW", nil, false},
+	}
+
+	for _, c := range cases {
+		stmt := inspectStatement(c.query)
+		require.Equal(t, c.kind, stmt.Kind, c.query)
+		require.Equal(t, c.tables, stmt.Tables, c.query)
+		require.Equal(t, c.hasWhere, stmt.HasWhere, c.query)
+	}
+}
+
+func TestRejectUnboundedWrites(t *testing.T) {
+	cases := []struct {
+		query    string
+		rejected bool
+	}{
+		{"SELECT * FROM model", false},
+		{"DELETE FROM model WHERE id=$1", false},
+		{"UPDATE model SET name=lower(name) WHERE id=$1", false},
+		{"INSERT INTO model (name) VALUES ($1) ON CONFLICT (name) DO UPDATE SET name=$1", false},
+		{"DELETE FROM model", true},
+		{"UPDATE model SET name=(SELECT name FROM rel WHERE id=$1)", true},
+		{"WITH q1 AS (DELETE FROM model) SELECT 1 WHERE true", true},
+		{"WITH q1 AS (DELETE FROM model WHERE id=$1) DELETE FROM rel", true},
+		{"TRUNCATE model", true},
+	}
+
+	for _, c := range cases {
+		err := RejectUnboundedWrites(inspectStatement(c.query))
+		if c.rejected {
+			require.Equal(t, ErrUnboundedWrite, err, c.query)
+		} else {
+			require.NoError(t, err, c.query)
+		}
+	}
+}
+
+func TestWithGuard(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	var guarded []*GuardedStatement
+	store := NewStore(db).WithGuard(func(s *GuardedStatement) error {
+		guarded = append(guarded, s)
+		return nil
+	}).WithGuard(RejectUnboundedWrites, AllowTables("model"))
+
+	recordedQueries = nil
+	m := newModel("foo", "foo@bar.baz", 1)
+	m.ID = 1
+	r.NoError(store.Delete(ModelSchema, m))
+	_, err = store.RawExec("DELETE FROM model")
+	r.Equal(ErrUnboundedWrite, err)
+	_, err = store.RawQuery("SELECT * FROM rel")
+	r.EqualError(err, "kallax: table rel is not allowed by the query guard")
+	r.Equal(ErrUnboundedWrite, store.Transaction(func(s *Store) error {
+		_, err := s.RawExec("UPDATE model SET name=$1", "bar")
+		return err
+	}))
+
+	r.Equal([]string{"DELETE FROM model WHERE id=$1"}, recordedQueries)
+	r.Len(guarded, 4)
+	r.Equal("DELETE", guarded[0].Kind)
+	r.Equal([]interface{}{m.GetID()}, guarded[0].Args)
+	r.Equal([]interface{}{"bar"}, guarded[3].Args)
+}
+
+func TestMaxCost(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	recordedQueries = nil
+	store := NewStore(db).WithGuard(MaxCost(100))
+	_, err = store.Find(NewBaseQuery(ModelSchema))
+	r.Equal(sql.ErrNoRows, err)
+	_, err = store.RawExec("SET statement_timeout = 100")
+	r.NoError(err)
+	r.Equal([]string{
+		"EXPLAIN (FORMAT JSON) SELECT __model.id, __model.name, __model.email, __model.age FROM model __model",
+		"SET statement_timeout = 100",
+	}, recordedQueries)
+
+	_, err = store.WithDialect(SQLite).Find(NewBaseQuery(ModelSchema))
+	r.EqualError(err, "kallax: EXPLAIN cost estimates are not supported by the sqlite dialect")
+}
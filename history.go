@@ -0,0 +1,67 @@
+package kallax
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// ErrAsOfOneToMany is returned when a query with 1:N relationships is run
+// with FindAsOf, as the versions of the related records are not kept with
+// the ones of the queried records.
+var ErrAsOfOneToMany = errors.New("kallax: queries with 1:N relationships can not be run as of a time")
+
+// ErrAsOfManyToMany is returned when a query with N:M relationships is run
+// with FindAsOf, as the join tables of the relationships are not versioned.
+var ErrAsOfManyToMany = errors.New("kallax: queries with N:M relationships can not be run as of a time")
+
+// HistoryTable returns the name of the history table of the given schema,
+// which is the name of its table followed by "_history".
+func HistoryTable(schema Schema) string {
+	return schema.Table() + "_history"
+}
+
+// FindAsOf performs the given query over the versions the records had at the
+// given time, which are kept in the history table of the models with the
+// `versioned:"true"` tag in their kallax.Model field. The records are
+// retrieved as read-only, so they can not be updated. 1:1 relationships are
+// retrieved with their current version, and 1:N and N:M relationships are
+// not supported.
+func (s *Store) FindAsOf(t time.Time, q Query) (ResultSet, error) {
+	rels := q.getRelationships()
+	if containsRelationshipOfType(rels, OneToMany) {
+		return nil, ErrAsOfOneToMany
+	}
+
+	if containsRelationshipOfType(rels, ManyToMany) {
+		return nil, ErrAsOfManyToMany
+	}
+
+	schema := q.Schema()
+	alias := schema.Alias()
+	columns, builder := s.queryScopes().compile(q)
+	builder = builder.
+		From(HistoryTable(schema) + " " + alias).
+		Where(squirrel.Expr(
+			fmt.Sprintf("%s.valid_from <= ? AND (%s.valid_to IS NULL OR %s.valid_to > ?)", alias, alias, alias),
+			t, t,
+		))
+	if offset := q.GetOffset(); offset > 0 {
+		builder = builder.Offset(offset)
+	}
+
+	if limit := q.GetLimit(); limit > 0 {
+		builder = builder.Limit(limit)
+	}
+
+	rows, err := builder.RunWith(s.runner).Query()
+	if err != nil {
+		return nil, err
+	}
+
+	rs := NewResultSet(rows, true, rels, columns...)
+	rs.loc = s.loc
+	return rs, nil
+}
@@ -0,0 +1,34 @@
+package kallax
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindAsOf(t *testing.T) {
+	require := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	require.NoError(err)
+	defer db.Close()
+
+	require.Equal("model_history", HistoryTable(ModelSchema))
+
+	recordedQueries = nil
+	q := NewBaseQuery(ModelSchema)
+	q.Where(Eq(f("name"), "foo"))
+	q.Limit(5)
+	rs, err := NewStore(db).FindAsOf(time.Now(), q)
+	require.NoError(err)
+	require.NoError(rs.Close())
+	require.Equal([]string{
+		"SELECT __model.id, __model.name, __model.email, __model.age FROM model_history __model WHERE __model.name = $1 AND __model.valid_from <= $2 AND (__model.valid_to IS NULL OR __model.valid_to > $3) LIMIT 5",
+	}, recordedQueries)
+
+	q = NewBaseQuery(ModelSchema)
+	require.NoError(q.AddRelation(RelSchema, "rels", OneToMany, nil))
+	_, err = NewStore(db).FindAsOf(time.Now(), q)
+	require.Equal(ErrAsOfOneToMany, err)
+}
@@ -0,0 +1,56 @@
+package kallax
+
+import (
+	"database/sql"
+	"database/sql/driver"
+
+	"github.com/lib/pq/hstore"
+)
+
+// HStore is a set of key/value pairs stored in a Postgres hstore column.
+// A nil value represents a NULL value for that key.
+// Note that the hstore extension must be enabled in the database in order to
+// use this type.
+type HStore map[string]*string
+
+// Scan implements the sql.Scanner interface.
+func (h *HStore) Scan(v interface{}) error {
+	var hs hstore.Hstore
+	if err := hs.Scan(v); err != nil {
+		return err
+	}
+
+	if hs.Map == nil {
+		*h = nil
+		return nil
+	}
+
+	result := make(HStore, len(hs.Map))
+	for k, v := range hs.Map {
+		if v.Valid {
+			s := v.String
+			result[k] = &s
+		} else {
+			result[k] = nil
+		}
+	}
+	*h = result
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (h HStore) Value() (driver.Value, error) {
+	if h == nil {
+		return nil, nil
+	}
+
+	hs := hstore.Hstore{Map: make(map[string]sql.NullString, len(h))}
+	for k, v := range h {
+		if v != nil {
+			hs.Map[k] = sql.NullString{String: *v, Valid: true}
+		} else {
+			hs.Map[k] = sql.NullString{}
+		}
+	}
+	return hs.Value()
+}
@@ -0,0 +1,33 @@
+package kallax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHStore_ScanValue(t *testing.T) {
+	r := require.New(t)
+
+	foo := "foo"
+	expected := HStore{"a": &foo, "b": nil}
+	v, err := expected.Value()
+	r.NoError(err)
+
+	var h HStore
+	r.NoError(h.Scan(v))
+	r.Equal(expected, h)
+}
+
+func TestHStore_ScanValueNil(t *testing.T) {
+	r := require.New(t)
+
+	var expected HStore
+	v, err := expected.Value()
+	r.NoError(err)
+	r.Nil(v)
+
+	h := HStore{}
+	r.NoError(h.Scan(nil))
+	r.Nil(h)
+}
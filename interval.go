@@ -0,0 +1,226 @@
+package kallax
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/src-d/go-kallax.v1/types"
+)
+
+// Interval represents a Postgres interval. Months and days are kept apart
+// from the rest of the interval because their actual length depends on the
+// date they are applied to.
+type Interval struct {
+	// Months of the interval. Years are stored as 12 months.
+	Months int32
+	// Days of the interval.
+	Days int32
+	// Microseconds of the interval, excluding days and months.
+	Microseconds int64
+}
+
+// NewInterval returns a new Interval with the given duration. The interval
+// will not have month or day components.
+func NewInterval(d time.Duration) Interval {
+	return Interval{Microseconds: int64(d / time.Microsecond)}
+}
+
+// Duration returns the interval as a time.Duration. Because months and days
+// do not have a fixed length, a month is considered to have 30 days and a day
+// is considered to have 24 hours, as Postgres does when it justifies
+// intervals.
+func (i Interval) Duration() time.Duration {
+	days := int64(i.Months)*30 + int64(i.Days)
+	return time.Duration(days)*24*time.Hour +
+		time.Duration(i.Microseconds)*time.Microsecond
+}
+
+// IsZero reports whether the interval has no length.
+func (i Interval) IsZero() bool {
+	return i.Months == 0 && i.Days == 0 && i.Microseconds == 0
+}
+
+// String returns the interval in a format Postgres can parse.
+func (i Interval) String() string {
+	return fmt.Sprintf(
+		"%d months %d days %d microseconds",
+		i.Months,
+		i.Days,
+		i.Microseconds,
+	)
+}
+
+// Scan implements the sql.Scanner interface. Only the default `postgres`
+// interval style is supported.
+func (i *Interval) Scan(v interface{}) error {
+	switch t := v.(type) {
+	case []byte:
+		return i.Scan(string(t))
+	case string:
+		iv, err := parseInterval(t)
+		if err != nil {
+			return err
+		}
+
+		*i = iv
+		return nil
+	case int64:
+		*i = Interval{Microseconds: t}
+		return nil
+	}
+	return fmt.Errorf("kallax: cannot scan type %s into Interval type", reflect.TypeOf(v))
+}
+
+// Value implements the driver.Valuer interface.
+func (i Interval) Value() (driver.Value, error) {
+	return i.String(), nil
+}
+
+// DurationInterval returns a SQLType that stores the given duration in an
+// interval column instead of a bigint column of nanoseconds. It accepts a
+// pointer to a time.Duration or, for nullable columns, a pointer to a pointer
+// to a time.Duration. Intervals with months or days are converted to durations
+// as Interval.Duration does, and durations are stored with microsecond
+// precision.
+func DurationInterval(v interface{}) types.SQLType {
+	return &durationInterval{v}
+}
+
+type durationInterval struct {
+	val interface{}
+}
+
+func (d *durationInterval) Scan(v interface{}) error {
+	var iv Interval
+	if v != nil {
+		if err := iv.Scan(v); err != nil {
+			return err
+		}
+	}
+
+	switch t := d.val.(type) {
+	case *time.Duration:
+		*t = iv.Duration()
+		return nil
+	case **time.Duration:
+		if v == nil {
+			*t = nil
+			return nil
+		}
+
+		dur := iv.Duration()
+		*t = &dur
+		return nil
+	}
+	return fmt.Errorf("kallax: cannot scan interval into type %T", d.val)
+}
+
+func (d *durationInterval) Value() (driver.Value, error) {
+	switch t := d.val.(type) {
+	case *time.Duration:
+		if t == nil {
+			return nil, nil
+		}
+		return NewInterval(*t).Value()
+	case **time.Duration:
+		if t == nil || *t == nil {
+			return nil, nil
+		}
+		return NewInterval(**t).Value()
+	}
+	return nil, fmt.Errorf("kallax: type %T cannot be converted to an interval", d.val)
+}
+
+// parseInterval parses an interval in the `postgres` interval style, e.g.
+// `1 year 2 mons -3 days +04:05:06.789`.
+func parseInterval(s string) (Interval, error) {
+	var iv Interval
+	parts := strings.Fields(s)
+	for i := 0; i < len(parts); i++ {
+		p := parts[i]
+		if strings.Contains(p, ":") {
+			us, err := parseIntervalTime(p)
+			if err != nil {
+				return Interval{}, fmt.Errorf("kallax: invalid interval %q: %s", s, err)
+			}
+			iv.Microseconds += us
+			continue
+		}
+
+		if i+1 >= len(parts) {
+			return Interval{}, fmt.Errorf("kallax: invalid interval %q: missing unit", s)
+		}
+
+		n, err := strconv.ParseInt(p, 10, 32)
+		if err != nil {
+			return Interval{}, fmt.Errorf("kallax: invalid interval %q: %s", s, err)
+		}
+
+		i++
+		switch strings.TrimSuffix(parts[i], "s") {
+		case "year":
+			iv.Months += int32(n) * 12
+		case "mon", "month":
+			iv.Months += int32(n)
+		case "day":
+			iv.Days += int32(n)
+		default:
+			return Interval{}, fmt.Errorf("kallax: invalid interval %q: unknown unit %s", s, parts[i])
+		}
+	}
+	return iv, nil
+}
+
+// parseIntervalTime parses the time part of an interval, which has the
+// format `[+-]hh:mm:ss[.ffffff]`, and returns it in microseconds.
+func parseIntervalTime(s string) (int64, error) {
+	var sign int64 = 1
+	if strings.HasPrefix(s, "-") {
+		sign = -1
+		s = s[1:]
+	} else if strings.HasPrefix(s, "+") {
+		s = s[1:]
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid time %s", s)
+	}
+
+	hours, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	minutes, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	var micros int64
+	secs := parts[2]
+	if idx := strings.Index(secs, "."); idx >= 0 {
+		frac := secs[idx+1:]
+		if len(frac) > 6 {
+			frac = frac[:6]
+		}
+		frac += strings.Repeat("0", 6-len(frac))
+		micros, err = strconv.ParseInt(frac, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		secs = secs[:idx]
+	}
+
+	seconds, err := strconv.ParseInt(secs, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	total := ((hours*60+minutes)*60+seconds)*1000000 + micros
+	return sign * total, nil
+}
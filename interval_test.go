@@ -0,0 +1,106 @@
+package kallax
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterval_Scan(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected Interval
+	}{
+		{"00:00:00", Interval{}},
+		{"3 days", Interval{Days: 3}},
+		{"1 mon", Interval{Months: 1}},
+		{"1 year 2 mons", Interval{Months: 14}},
+		{"-1 days +02:03:00", Interval{Days: -1, Microseconds: int64(2*time.Hour+3*time.Minute) / 1000}},
+		{"01:02:03.5", Interval{Microseconds: 3723500000}},
+		{"-00:00:00.000001", Interval{Microseconds: -1}},
+		{"1 year 2 mons 3 days 04:05:06.789", Interval{
+			Months:       14,
+			Days:         3,
+			Microseconds: int64(4*time.Hour+5*time.Minute+6789*time.Millisecond) / 1000,
+		}},
+	}
+
+	for _, c := range cases {
+		var iv Interval
+		require.NoError(t, iv.Scan([]byte(c.input)), c.input)
+		require.Equal(t, c.expected, iv, c.input)
+	}
+}
+
+func TestInterval_ScanInvalid(t *testing.T) {
+	cases := []interface{}{
+		"P1Y2M",
+		"1 fortnight",
+		"3",
+		"1:2",
+		true,
+	}
+
+	for _, c := range cases {
+		var iv Interval
+		require.Error(t, iv.Scan(c), "%v", c)
+	}
+}
+
+func TestInterval_Duration(t *testing.T) {
+	r := require.New(t)
+	r.Equal(90*time.Minute, NewInterval(90*time.Minute).Duration())
+	r.Equal(31*24*time.Hour+time.Second, Interval{
+		Months:       1,
+		Days:         1,
+		Microseconds: 1000000,
+	}.Duration())
+}
+
+func TestInterval_Value(t *testing.T) {
+	v, err := Interval{Months: 1, Days: -2, Microseconds: 3}.Value()
+	require.NoError(t, err)
+	require.Equal(t, "1 months -2 days 3 microseconds", v)
+}
+
+func TestDurationInterval(t *testing.T) {
+	r := require.New(t)
+	d := 26*time.Hour + 3*time.Second + 500*time.Microsecond
+
+	v, err := DurationInterval(&d).Value()
+	r.NoError(err)
+	r.Equal("0 months 0 days 93603000500 microseconds", v)
+
+	var scanned time.Duration
+	r.NoError(DurationInterval(&scanned).Scan([]byte("1 day 02:00:03.0005")))
+	r.Equal(d, scanned)
+
+	r.NoError(DurationInterval(&scanned).Scan(nil))
+	r.Equal(time.Duration(0), scanned)
+
+	r.Error(DurationInterval(&scanned).Scan("foo"))
+	r.Error(DurationInterval(new(int64)).Scan("1 day"))
+	_, err = DurationInterval(new(int64)).Value()
+	r.Error(err)
+}
+
+func TestDurationInterval_Ptr(t *testing.T) {
+	r := require.New(t)
+	var d *time.Duration
+
+	v, err := DurationInterval(&d).Value()
+	r.NoError(err)
+	r.Nil(v)
+
+	r.NoError(DurationInterval(&d).Scan("-00:01:00"))
+	r.NotNil(d)
+	r.Equal(-time.Minute, *d)
+
+	v, err = DurationInterval(&d).Value()
+	r.NoError(err)
+	r.Equal("0 months 0 days -60000000 microseconds", v)
+
+	r.NoError(DurationInterval(&d).Scan(nil))
+	r.Nil(d)
+}
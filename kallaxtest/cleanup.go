@@ -0,0 +1,166 @@
+package kallaxtest
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/lib/pq"
+	"gopkg.in/src-d/go-kallax.v1"
+)
+
+// Truncate removes all the rows of the tables of the given schemas and
+// restarts their sequences. The tables referencing them are truncated as
+// well. If no schema is given, all the tables of the database are truncated,
+// except the one with the version of the migrations.
+func Truncate(store *kallax.Store, schemas ...kallax.Schema) error {
+	var tables []string
+	for _, s := range schemas {
+		tables = append(tables, s.Table())
+	}
+
+	if len(schemas) == 0 {
+		var err error
+		if tables, err = databaseTables(store); err != nil {
+			return err
+		}
+	}
+
+	if len(tables) == 0 {
+		return nil
+	}
+
+	query := fmt.Sprintf("TRUNCATE TABLE %s RESTART IDENTITY CASCADE", strings.Join(tables, ", "))
+	if _, err := store.RawExec(query); err != nil {
+		return fmt.Errorf("kallax: unable to truncate tables: %s", err)
+	}
+	return nil
+}
+
+// Truncate removes all the rows of the tables of the given schemas, or all
+// the tables, in the database of the harness. See Truncate.
+func (h *Harness) Truncate(schemas ...kallax.Schema) error {
+	return Truncate(h.Store(), schemas...)
+}
+
+// migrationsTable is the table where the version of the migrations is stored.
+const migrationsTable = "schema_migrations"
+
+func databaseTables(store *kallax.Store) ([]string, error) {
+	rows, err := store.RawQuery(
+		"SELECT tablename FROM pg_tables WHERE schemaname = current_schema() AND tablename <> $1 ORDER BY tablename",
+		migrationsTable,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("kallax: unable to list tables: %s", err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.RawScan(&table); err != nil {
+			return nil, err
+		}
+		tables = append(tables, pq.QuoteIdentifier(table))
+	}
+
+	return tables, nil
+}
+
+// errRollback is returned to roll back the transaction of Rollback.
+var errRollback = errors.New("kallax: rollback")
+
+// Rollback runs the given test function with a store holding a transaction
+// that is always rolled back, even if the test fails or panics, so the test
+// leaves the database untouched. Transactions opened in the store given to
+// the function reuse the same transaction.
+func Rollback(t testing.TB, store *kallax.Store, fn func(*kallax.Store)) {
+	err := store.Transaction(func(store *kallax.Store) error {
+		fn(store)
+		return errRollback
+	})
+
+	if err != errRollback {
+		t.Fatalf("kallax: unable to run the test in a transaction: %s", err)
+	}
+}
+
+// Rollback runs the given test function with a store of the harness
+// database holding a transaction that is always rolled back. See Rollback.
+func (h *Harness) Rollback(t testing.TB, fn func(*kallax.Store)) {
+	Rollback(t, h.Store(), fn)
+}
+
+var clones uint64
+
+// Clone returns a new harness with a copy of the database of the harness,
+// which is used as template. Migrations and fixtures loaded in the harness
+// are copied, which is usually faster than applying them again. The database
+// of the clone is dropped when it is closed.
+//
+// The database of the harness must not be used while it's being cloned and
+// its data source name must be a URL.
+func (h *Harness) Clone() (*Harness, error) {
+	u, err := url.Parse(h.dsn)
+	if err != nil || u.Scheme == "" {
+		return nil, fmt.Errorf("kallax: unable to clone a database whose data source name is not a URL")
+	}
+
+	template := strings.TrimPrefix(u.Path, "/")
+	name := fmt.Sprintf("%s_clone_%d_%d", template, os.Getpid(), atomic.AddUint64(&clones, 1))
+
+	maintenance, err := maintenanceDB(u)
+	if err != nil {
+		return nil, err
+	}
+
+	// PostgreSQL does not allow to copy a database with open connections.
+	// The default number of idle connections is restored afterwards.
+	h.db.SetMaxIdleConns(0)
+	defer h.db.SetMaxIdleConns(2)
+
+	_, err = maintenance.Exec(fmt.Sprintf(
+		"CREATE DATABASE %s TEMPLATE %s",
+		pq.QuoteIdentifier(name), pq.QuoteIdentifier(template),
+	))
+	if err != nil {
+		maintenance.Close()
+		return nil, fmt.Errorf("kallax: unable to clone database %s: %s", template, err)
+	}
+
+	drop := func() error {
+		defer maintenance.Close()
+		if _, err := maintenance.Exec("DROP DATABASE IF EXISTS " + pq.QuoteIdentifier(name)); err != nil {
+			return fmt.Errorf("kallax: unable to drop database %s: %s", name, err)
+		}
+		return nil
+	}
+
+	clone := *u
+	clone.Path = "/" + name
+	db, err := sql.Open("postgres", clone.String())
+	if err != nil {
+		drop()
+		return nil, fmt.Errorf("kallax: unable to open the database: %s", err)
+	}
+
+	return &Harness{db: db, dsn: clone.String(), drop: drop}, nil
+}
+
+// maintenanceDB opens the database used to create and drop databases in the
+// server of the database with the given data source name.
+func maintenanceDB(dsn *url.URL) (*sql.DB, error) {
+	u := *dsn
+	u.Path = "/postgres"
+	db, err := sql.Open("postgres", u.String())
+	if err != nil {
+		return nil, fmt.Errorf("kallax: unable to open the maintenance database: %s", err)
+	}
+	return db, nil
+}
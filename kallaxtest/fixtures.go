@@ -0,0 +1,328 @@
+package kallaxtest
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/lib/pq"
+	"gopkg.in/src-d/go-kallax.v1"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Fixtures loads records declared in YAML or JSON documents into the
+// database. The documents contain the records of the models by model name,
+// and every record has a label that is used to reference it:
+//
+//	User:
+//	  alice:
+//	    name: Alice
+//	    age: 32
+//	Post:
+//	  hello:
+//	    title: Hello world
+//	    Author: alice
+//
+// The keys of a record are the column names of the model or the names of the
+// fields of its inverse relationships, whose value is the label of the
+// referenced record. Labels are unique in all the fixtures, and records can
+// only reference records declared before them, as the records are inserted in
+// the same order they are declared.
+//
+// Primary keys of types kallax.ULID and kallax.UUID are generated if they are
+// not given and autoincrementable ones are generated by the database.
+//
+// Records are inserted with the generic store, so the events of the models
+// are not run. Fields with JSON schemas are validated, though.
+type Fixtures struct {
+	schemas map[string]kallax.Schema
+	records map[string]kallax.Record
+}
+
+// NewFixtures returns new fixtures of the models with the given schemas by
+// model name, e.g. `{"User": Schema.User.BaseSchema}`.
+func NewFixtures(schemas map[string]kallax.Schema) *Fixtures {
+	return &Fixtures{schemas, make(map[string]kallax.Record)}
+}
+
+// fixture is a record to be inserted and the records it references.
+type fixture struct {
+	label      string
+	model      string
+	schema     kallax.Schema
+	record     kallax.Record
+	references map[string]string
+}
+
+// LoadFile loads the fixtures of the YAML or JSON file at the given path.
+func (f *Fixtures) LoadFile(store *kallax.Store, path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("kallax: cannot read fixtures file: %s", err)
+	}
+
+	if err := f.Load(store, data); err != nil {
+		return fmt.Errorf("%s. In fixtures file %s", err, path)
+	}
+	return nil
+}
+
+// Load loads the fixtures of the given YAML or JSON document. All the
+// records are validated before inserting them in a single transaction, so
+// none of them is inserted if any fails.
+func (f *Fixtures) Load(store *kallax.Store, data []byte) error {
+	fixtures, err := f.parse(data)
+	if err != nil {
+		return err
+	}
+
+	err = store.Transaction(func(store *kallax.Store) error {
+		for _, fx := range fixtures {
+			for field, label := range fx.references {
+				fk, _ := fx.schema.ForeignKey(field)
+				fx.record.AddVirtualColumn(fk.String(), f.lookup(fixtures, label).GetID())
+			}
+
+			if err := store.Insert(fx.schema, fx.record); err != nil {
+				return fmt.Errorf("kallax: cannot insert fixture %s of model %s: %s", fx.label, fx.model, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, fx := range fixtures {
+		f.records[fx.label] = fx.record
+	}
+	return nil
+}
+
+// Record returns the loaded record with the given label.
+func (f *Fixtures) Record(label string) (kallax.Record, bool) {
+	r, ok := f.records[label]
+	return r, ok
+}
+
+// lookup returns the record with the given label, either being loaded or
+// previously loaded.
+func (f *Fixtures) lookup(fixtures []*fixture, label string) kallax.Record {
+	for _, fx := range fixtures {
+		if fx.label == label {
+			return fx.record
+		}
+	}
+	return f.records[label]
+}
+
+func (f *Fixtures) parse(data []byte) ([]*fixture, error) {
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("kallax: invalid fixtures: %s", err)
+	}
+
+	var fixtures []*fixture
+	labels := make(map[string]bool)
+	for _, m := range doc {
+		model := fmt.Sprint(m.Key)
+		schema, ok := f.schemas[model]
+		if !ok {
+			return nil, fmt.Errorf("kallax: unknown model %s in fixtures", model)
+		}
+
+		records, ok := m.Value.(yaml.MapSlice)
+		if !ok && m.Value != nil {
+			return nil, fmt.Errorf("kallax: fixtures of model %s must be a mapping of labels to records", model)
+		}
+
+		for _, r := range records {
+			label := fmt.Sprint(r.Key)
+			if _, ok := f.records[label]; ok || labels[label] {
+				return nil, fmt.Errorf("kallax: duplicated fixture label %s", label)
+			}
+
+			fx, err := f.newFixture(model, schema, label, r.Value, labels)
+			if err != nil {
+				return nil, err
+			}
+
+			labels[label] = true
+			fixtures = append(fixtures, fx)
+		}
+	}
+
+	return fixtures, nil
+}
+
+func (f *Fixtures) newFixture(model string, schema kallax.Schema, label string, value interface{}, labels map[string]bool) (*fixture, error) {
+	fields, ok := value.(yaml.MapSlice)
+	if !ok && value != nil {
+		return nil, fmt.Errorf("kallax: fixture %s of model %s must be a mapping", label, model)
+	}
+
+	fx := &fixture{
+		label:      label,
+		model:      model,
+		schema:     schema,
+		record:     schema.New(),
+		references: make(map[string]string),
+	}
+
+	columns := make(map[string]bool)
+	for _, c := range schema.Columns() {
+		columns[c.String()] = true
+	}
+
+	for _, item := range fields {
+		key := fmt.Sprint(item.Key)
+		if columns[key] {
+			addr, err := fx.record.ColumnAddress(key)
+			if err != nil {
+				return nil, fmt.Errorf("kallax: invalid column %s in fixture %s of model %s: %s", key, label, model, err)
+			}
+
+			if err := assign(addr, normalize(item.Value)); err != nil {
+				return nil, fmt.Errorf("kallax: invalid value of column %s in fixture %s of model %s: %s", key, label, model, err)
+			}
+			continue
+		}
+
+		fk, ok := schema.ForeignKey(key)
+		if !ok {
+			return nil, fmt.Errorf("kallax: unknown column or relationship %s in fixture %s of model %s", key, label, model)
+		}
+
+		if !fk.Inverse {
+			return nil, fmt.Errorf("kallax: relationship %s in fixture %s of model %s is not inverse, reference this record from the other model instead", key, label, model)
+		}
+
+		ref := fmt.Sprint(item.Value)
+		if _, ok := f.records[ref]; !ok && !labels[ref] {
+			return nil, fmt.Errorf("kallax: unknown reference %s in relationship %s of fixture %s of model %s, it must be declared before", ref, key, label, model)
+		}
+		fx.references[key] = ref
+	}
+
+	generateID(fx.record)
+
+	if v, ok := fx.record.(interface {
+		ValidateJSONSchemas() error
+	}); ok {
+		if err := v.ValidateJSONSchemas(); err != nil {
+			return nil, fmt.Errorf("%s. In fixture %s", err, label)
+		}
+	}
+
+	return fx, nil
+}
+
+// generateID sets a new primary key to the record if it's empty and it's an
+// ULID or UUID.
+func generateID(record kallax.Record) {
+	id := record.GetID()
+	if !id.IsEmpty() {
+		return
+	}
+
+	switch id := id.(type) {
+	case *kallax.ULID:
+		*id = kallax.NewULID()
+	case *kallax.UUID:
+		*id = kallax.NewUUIDv4()
+	}
+}
+
+// normalize converts the mappings decoded from YAML into maps with string
+// keys, so they can be encoded as JSON.
+func normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case yaml.MapSlice:
+		m := make(map[string]interface{}, len(v))
+		for _, item := range v {
+			m[fmt.Sprint(item.Key)] = normalize(item.Value)
+		}
+		return m
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			m[fmt.Sprint(k)] = normalize(val)
+		}
+		return m
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, val := range v {
+			list[i] = normalize(val)
+		}
+		return list
+	}
+	return v
+}
+
+// assign sets the given value decoded from YAML to the given column address.
+func assign(addr interface{}, v interface{}) error {
+	if scanner, ok := addr.(sql.Scanner); ok {
+		return scan(scanner, v)
+	}
+
+	if t, ok := addr.(*time.Time); ok {
+		if s, ok := v.(string); ok {
+			parsed, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return err
+			}
+			*t = parsed
+			return nil
+		}
+	}
+
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(data, addr)
+}
+
+// scan scans the given value decoded from YAML with the given scanner.
+// Mappings and lists are scanned as JSON. If lists can not be scanned as
+// JSON, they are scanned as PostgreSQL arrays. Strings that can not be
+// scanned are scanned as time if they are RFC 3339 timestamps.
+func scan(scanner sql.Scanner, v interface{}) error {
+	switch v := v.(type) {
+	case int:
+		return scanner.Scan(int64(v))
+	case map[string]interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		return scanner.Scan(data)
+	case []interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+
+		if scanner.Scan(data) == nil {
+			return nil
+		}
+
+		array, err := pq.Array(v).Value()
+		if err != nil {
+			return err
+		}
+		return scanner.Scan([]byte(array.(string)))
+	case string:
+		err := scanner.Scan(v)
+		if err != nil {
+			if t, terr := time.Parse(time.RFC3339Nano, v); terr == nil {
+				return scanner.Scan(t)
+			}
+		}
+		return err
+	}
+
+	return scanner.Scan(v)
+}
@@ -0,0 +1,126 @@
+package kallaxtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-kallax.v1"
+	"gopkg.in/src-d/go-kallax.v1/tests"
+)
+
+func newTestFixtures() *Fixtures {
+	return NewFixtures(map[string]kallax.Schema{
+		"Person":       tests.Schema.Person.BaseSchema,
+		"Car":          tests.Schema.Car.BaseSchema,
+		"Brand":        tests.Schema.Brand.BaseSchema,
+		"StoreFixture": tests.Schema.StoreFixture.BaseSchema,
+		"Nullable":     tests.Schema.Nullable.BaseSchema,
+	})
+}
+
+const fixturesYAML = `
+Person:
+  alice:
+    name: Alice
+Brand:
+  acme:
+    name: Acme
+Car:
+  beetle:
+    model_name: Beetle
+    Owner: alice
+    Brand: acme
+StoreFixture:
+  store:
+    id: 0157b8a8-8e27-4a2e-ec8d-2ca6ea7b4c7f
+    foo: bar
+    slice_prop: [a, b]
+Nullable:
+  empty: {}
+  full:
+    t: 2017-01-02T03:04:05Z
+    some_json: {foo: 42}
+`
+
+func TestFixtures_Parse(t *testing.T) {
+	require := require.New(t)
+	fixtures, err := newTestFixtures().parse([]byte(fixturesYAML))
+	require.NoError(err)
+	require.Len(fixtures, 6)
+
+	var labels []string
+	for _, fx := range fixtures {
+		labels = append(labels, fx.label)
+	}
+	require.Equal([]string{"alice", "acme", "beetle", "store", "empty", "full"}, labels)
+
+	require.Equal("Alice", fixtures[0].record.(*tests.Person).Name)
+	require.True(fixtures[0].record.GetID().IsEmpty())
+
+	car := fixtures[2].record.(*tests.Car)
+	require.Equal("Beetle", car.ModelName)
+	require.False(car.GetID().IsEmpty())
+	require.Equal(map[string]string{"Owner": "alice", "Brand": "acme"}, fixtures[2].references)
+
+	store := fixtures[3].record.(*tests.StoreFixture)
+	require.Equal("0157b8a8-8e27-4a2e-ec8d-2ca6ea7b4c7f", store.ID.String())
+	require.Equal("bar", store.Foo)
+	require.Equal([]string{"a", "b"}, store.SliceProp)
+
+	require.Nil(fixtures[4].record.(*tests.Nullable).T)
+	full := fixtures[5].record.(*tests.Nullable)
+	require.NotNil(full.T)
+	require.True(time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC).Equal(*full.T))
+	require.Equal(&tests.SomeJSON{Foo: 42}, full.SomeJSON)
+}
+
+func TestFixtures_ParseJSON(t *testing.T) {
+	require := require.New(t)
+	fixtures, err := newTestFixtures().parse([]byte(`{
+	"Person": {"bob": {"name": "Bob"}},
+	"Car": {"mini": {"model_name": "Mini", "Owner": "bob"}}
+}`))
+	require.NoError(err)
+	require.Len(fixtures, 2)
+	require.Equal("Bob", fixtures[0].record.(*tests.Person).Name)
+	require.Equal("Mini", fixtures[1].record.(*tests.Car).ModelName)
+}
+
+func TestFixtures_ParseInvalid(t *testing.T) {
+	cases := map[string]string{
+		"Foo: {}":                                "unknown model Foo",
+		"Person: [a]":                            "must be a mapping of labels to records",
+		"Person: {a: [b]}":                       "fixture a of model Person must be a mapping",
+		"Person: {a: {}, b: {}}\nBrand: {a: {}}": "duplicated fixture label a",
+		"Person: {a: {foo: 1}}":                  "unknown column or relationship foo",
+		"Person: {a: {Pets: b}}":                 "relationship Pets in fixture a of model Person is not inverse",
+		"Car: {a: {Owner: b}}\nPerson: {b: {}}":  "unknown reference b in relationship Owner",
+		"Nullable: {a: {t: foo}}":                "invalid value of column t in fixture a of model Nullable",
+		"StoreFixture: {a: {id: foo}}":           "invalid value of column id",
+		"Person: [":                              "invalid fixtures",
+	}
+
+	for doc, msg := range cases {
+		_, err := newTestFixtures().parse([]byte(doc))
+		require.Error(t, err, doc)
+		require.Contains(t, err.Error(), msg, doc)
+	}
+}
+
+func TestFixtures_ParseLoadedLabels(t *testing.T) {
+	require := require.New(t)
+	f := newTestFixtures()
+	f.records["alice"] = new(tests.Person)
+
+	fixtures, err := f.parse([]byte("Car: {beetle: {Owner: alice}}"))
+	require.NoError(err)
+	require.Len(fixtures, 1)
+
+	_, err = f.parse([]byte("Person: {alice: {}}"))
+	require.Error(err)
+
+	r, ok := f.Record("alice")
+	require.True(ok)
+	require.Equal(f.records["alice"], r)
+}
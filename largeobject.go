@@ -0,0 +1,229 @@
+package kallax
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// ErrLargeObjectNoTx is returned when a large object is opened outside of a
+// transaction. Large object descriptors are only valid for the duration of
+// the transaction they are opened in.
+var ErrLargeObjectNoTx = errors.New("kallax: large objects can only be opened inside a transaction")
+
+// ErrLargeObjectClosed is returned when an operation is performed on a large
+// object that has already been closed.
+var ErrLargeObjectClosed = errors.New("kallax: large object is closed")
+
+// LargeObjectID is the identifier of a Postgres large object. Fields of this
+// type are stored in `oid` columns and can be used to reference binary
+// payloads that are too big to be materialized in memory.
+type LargeObjectID uint32
+
+// Scan implements the sql.Scanner interface.
+func (id *LargeObjectID) Scan(v interface{}) error {
+	switch t := v.(type) {
+	case int64:
+		*id = LargeObjectID(t)
+		return nil
+	case []byte:
+		return id.Scan(string(t))
+	case string:
+		var n uint32
+		if _, err := fmt.Sscanf(t, "%d", &n); err != nil {
+			return fmt.Errorf("kallax: invalid large object id %q: %s", t, err)
+		}
+		*id = LargeObjectID(n)
+		return nil
+	}
+	return fmt.Errorf("kallax: cannot scan type %s into LargeObjectID type", reflect.TypeOf(v))
+}
+
+// Value implements the driver.Valuer interface.
+func (id LargeObjectID) Value() (driver.Value, error) {
+	return int64(id), nil
+}
+
+// LargeObjectMode is the mode in which a large object is opened.
+type LargeObjectMode int32
+
+const (
+	// LargeObjectRead opens the large object for reading only.
+	LargeObjectRead LargeObjectMode = 0x40000
+	// LargeObjectWrite opens the large object for writing only.
+	LargeObjectWrite LargeObjectMode = 0x20000
+	// LargeObjectReadWrite opens the large object for reading and writing.
+	LargeObjectReadWrite = LargeObjectRead | LargeObjectWrite
+)
+
+// LargeObject is an open Postgres large object. It implements io.Reader,
+// io.Writer, io.Seeker and io.Closer, so its contents can be streamed
+// without loading them in memory.
+// A LargeObject is only valid until the transaction it was opened in ends.
+type LargeObject struct {
+	store  *Store
+	fd     int32
+	closed bool
+}
+
+// CreateLargeObject creates a new empty large object and returns its
+// identifier.
+func (s *Store) CreateLargeObject() (LargeObjectID, error) {
+	var id LargeObjectID
+	if err := s.runner.QueryRow("SELECT lo_create(0)").Scan(&id); err != nil {
+		return 0, fmt.Errorf("kallax: unable to create large object: %s", err)
+	}
+	return id, nil
+}
+
+// OpenLargeObject opens the large object with the given identifier in the
+// given mode. The store must be inside a transaction, otherwise
+// ErrLargeObjectNoTx is returned.
+func (s *Store) OpenLargeObject(id LargeObjectID, mode LargeObjectMode) (*LargeObject, error) {
+	if _, ok := s.db.(*txRunner); !ok {
+		return nil, ErrLargeObjectNoTx
+	}
+
+	var fd int32
+	if err := s.runner.QueryRow("SELECT lo_open($1, $2)", id, int32(mode)).Scan(&fd); err != nil {
+		return nil, fmt.Errorf("kallax: unable to open large object %d: %s", id, err)
+	}
+	return &LargeObject{store: s, fd: fd}, nil
+}
+
+// UnlinkLargeObject removes the large object with the given identifier.
+func (s *Store) UnlinkLargeObject(id LargeObjectID) error {
+	if _, err := s.runner.Exec("SELECT lo_unlink($1)", id); err != nil {
+		return fmt.Errorf("kallax: unable to unlink large object %d: %s", id, err)
+	}
+	return nil
+}
+
+// WriteLargeObject creates a new large object with all the contents of the
+// given reader and returns its identifier. If the store is not inside a
+// transaction, one is opened for the operation.
+func (s *Store) WriteLargeObject(r io.Reader) (LargeObjectID, error) {
+	var id LargeObjectID
+	err := s.Transaction(func(s *Store) error {
+		var err error
+		id, err = s.CreateLargeObject()
+		if err != nil {
+			return err
+		}
+
+		lo, err := s.OpenLargeObject(id, LargeObjectWrite)
+		if err != nil {
+			return err
+		}
+
+		if _, err := io.Copy(lo, r); err != nil {
+			lo.Close()
+			return err
+		}
+
+		return lo.Close()
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// ReadLargeObject writes all the contents of the large object with the given
+// identifier to the given writer. If the store is not inside a transaction,
+// one is opened for the operation.
+func (s *Store) ReadLargeObject(id LargeObjectID, w io.Writer) error {
+	return s.Transaction(func(s *Store) error {
+		lo, err := s.OpenLargeObject(id, LargeObjectRead)
+		if err != nil {
+			return err
+		}
+
+		if _, err := io.Copy(w, lo); err != nil {
+			lo.Close()
+			return err
+		}
+
+		return lo.Close()
+	})
+}
+
+// Read reads up to len(p) bytes of the large object into p.
+func (lo *LargeObject) Read(p []byte) (int, error) {
+	if lo.closed {
+		return 0, ErrLargeObjectClosed
+	}
+
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	var data []byte
+	if err := lo.store.runner.QueryRow("SELECT loread($1, $2)", lo.fd, len(p)).Scan(&data); err != nil {
+		return 0, fmt.Errorf("kallax: unable to read large object: %s", err)
+	}
+
+	n := copy(p, data)
+	if n == 0 {
+		return 0, io.EOF
+	}
+	return n, nil
+}
+
+// Write writes the contents of p to the large object.
+func (lo *LargeObject) Write(p []byte) (int, error) {
+	if lo.closed {
+		return 0, ErrLargeObjectClosed
+	}
+
+	var n int
+	if err := lo.store.runner.QueryRow("SELECT lowrite($1, $2)", lo.fd, p).Scan(&n); err != nil {
+		return 0, fmt.Errorf("kallax: unable to write large object: %s", err)
+	}
+
+	if n < len(p) {
+		return n, io.ErrShortWrite
+	}
+	return n, nil
+}
+
+// Seek sets the offset for the next read or write on the large object.
+func (lo *LargeObject) Seek(offset int64, whence int) (int64, error) {
+	if lo.closed {
+		return 0, ErrLargeObjectClosed
+	}
+
+	var pos int64
+	if err := lo.store.runner.QueryRow("SELECT lo_lseek64($1, $2, $3)", lo.fd, offset, whence).Scan(&pos); err != nil {
+		return 0, fmt.Errorf("kallax: unable to seek large object: %s", err)
+	}
+	return pos, nil
+}
+
+// Truncate truncates the large object to the given size.
+func (lo *LargeObject) Truncate(size int64) error {
+	if lo.closed {
+		return ErrLargeObjectClosed
+	}
+
+	if _, err := lo.store.runner.Exec("SELECT lo_truncate64($1, $2)", lo.fd, size); err != nil {
+		return fmt.Errorf("kallax: unable to truncate large object: %s", err)
+	}
+	return nil
+}
+
+// Close closes the large object descriptor. Closing it more than once has no
+// effect.
+func (lo *LargeObject) Close() error {
+	if lo.closed {
+		return nil
+	}
+
+	lo.closed = true
+	if _, err := lo.store.runner.Exec("SELECT lo_close($1)", lo.fd); err != nil {
+		return fmt.Errorf("kallax: unable to close large object: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package kallax
+
+import (
+	"bytes"
+	"database/sql"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestLargeObjectID_Scan(t *testing.T) {
+	r := require.New(t)
+	var id LargeObjectID
+	r.NoError(id.Scan([]byte("16385")))
+	r.Equal(LargeObjectID(16385), id)
+
+	r.NoError(id.Scan(int64(42)))
+	r.Equal(LargeObjectID(42), id)
+
+	r.Error(id.Scan("foo"))
+	r.Error(id.Scan(true))
+
+	v, err := id.Value()
+	r.NoError(err)
+	r.Equal(int64(42), v)
+}
+
+type LargeObjectSuite struct {
+	suite.Suite
+	db    *sql.DB
+	store *Store
+}
+
+func (s *LargeObjectSuite) SetupTest() {
+	var err error
+	s.db, err = openTestDB()
+	s.Require().NoError(err)
+	s.store = NewStore(s.db)
+}
+
+func (s *LargeObjectSuite) TearDownTest() {
+	s.NoError(s.db.Close())
+}
+
+func (s *LargeObjectSuite) TestOpenLargeObject_NoTx() {
+	_, err := s.store.OpenLargeObject(LargeObjectID(1), LargeObjectRead)
+	s.Equal(ErrLargeObjectNoTx, err)
+}
+
+func (s *LargeObjectSuite) TestWriteReadLargeObject() {
+	data := bytes.Repeat([]byte("kallax"), 100000)
+	id, err := s.store.WriteLargeObject(bytes.NewReader(data))
+	s.Require().NoError(err)
+	defer func() {
+		s.NoError(s.store.UnlinkLargeObject(id))
+	}()
+
+	var buf bytes.Buffer
+	s.NoError(s.store.ReadLargeObject(id, &buf))
+	s.Equal(data, buf.Bytes())
+}
+
+func (s *LargeObjectSuite) TestLargeObject() {
+	id, err := s.store.CreateLargeObject()
+	s.Require().NoError(err)
+	defer func() {
+		s.NoError(s.store.UnlinkLargeObject(id))
+	}()
+
+	s.NoError(s.store.Transaction(func(store *Store) error {
+		lo, err := store.OpenLargeObject(id, LargeObjectReadWrite)
+		s.Require().NoError(err)
+
+		n, err := lo.Write([]byte("hello world"))
+		s.NoError(err)
+		s.Equal(11, n)
+
+		pos, err := lo.Seek(6, io.SeekStart)
+		s.NoError(err)
+		s.Equal(int64(6), pos)
+
+		data, err := ioutil.ReadAll(lo)
+		s.NoError(err)
+		s.Equal("world", string(data))
+
+		s.NoError(lo.Truncate(5))
+		_, err = lo.Seek(0, io.SeekStart)
+		s.NoError(err)
+
+		data, err = ioutil.ReadAll(lo)
+		s.NoError(err)
+		s.Equal("hello", string(data))
+
+		s.NoError(lo.Close())
+		s.NoError(lo.Close())
+
+		_, err = lo.Read(make([]byte, 1))
+		s.Equal(ErrLargeObjectClosed, err)
+		return nil
+	}))
+}
+
+func TestLargeObject(t *testing.T) {
+	suite.Run(t, new(LargeObjectSuite))
+}
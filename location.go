@@ -0,0 +1,90 @@
+package kallax
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// locationScanner is a scanner that normalizes the times scanned into its
+// destination to a location.
+type locationScanner struct {
+	dest interface{}
+	loc  *time.Location
+	// wallClock reports whether the time comes from a column without time
+	// zone, in which case its wall clock is interpreted in the location
+	// instead of being converted to it.
+	wallClock bool
+}
+
+// Scan implements the sql.Scanner interface.
+func (s *locationScanner) Scan(src interface{}) error {
+	if t, ok := src.(time.Time); ok {
+		src = inLocation(t, s.loc, s.wallClock)
+	}
+
+	switch dest := s.dest.(type) {
+	case *time.Time:
+		t, ok := src.(time.Time)
+		if !ok {
+			return fmt.Errorf("kallax: cannot scan type %s into time.Time type", reflect.TypeOf(src))
+		}
+		*dest = t
+		return nil
+	case sql.Scanner:
+		return dest.Scan(src)
+	}
+
+	return fmt.Errorf("kallax: cannot scan into type %T", s.dest)
+}
+
+func inLocation(t time.Time, loc *time.Location, wallClock bool) time.Time {
+	if !wallClock {
+		return t.In(loc)
+	}
+
+	return time.Date(
+		t.Year(), t.Month(), t.Day(),
+		t.Hour(), t.Minute(), t.Second(), t.Nanosecond(),
+		loc,
+	)
+}
+
+// withLocation wraps the given scan destinations so the times scanned into
+// them are normalized to the given location. Only pointers to time.Time and
+// sql.Scanner implementations are wrapped, the rest of destinations are left
+// untouched. Timestamp columns are the columns whose type is in the given
+// column types and does not have time zone.
+func withLocation(dest []interface{}, loc *time.Location, columnTypes []*sql.ColumnType) []interface{} {
+	result := make([]interface{}, len(dest))
+	for i, d := range dest {
+		switch d.(type) {
+		case *time.Time, sql.Scanner:
+			var wallClock bool
+			if i < len(columnTypes) {
+				wallClock = strings.ToUpper(columnTypes[i].DatabaseTypeName()) == "TIMESTAMP"
+			}
+			result[i] = &locationScanner{d, loc, wallClock}
+		default:
+			result[i] = d
+		}
+	}
+	return result
+}
+
+// valuesInLocation converts all the time values in the given list of values
+// to the given location.
+func valuesInLocation(values []interface{}, loc *time.Location) {
+	for i, v := range values {
+		switch t := v.(type) {
+		case time.Time:
+			values[i] = t.In(loc)
+		case *time.Time:
+			if t != nil {
+				values[i] = t.In(loc)
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package kallax
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-kallax.v1/types"
+)
+
+func TestLocationScanner(t *testing.T) {
+	r := require.New(t)
+	loc := time.FixedZone("kallax", 3*3600)
+	src := time.Date(2017, time.January, 2, 3, 4, 5, 0, time.UTC)
+
+	var tm time.Time
+	r.NoError((&locationScanner{&tm, loc, false}).Scan(src))
+	r.Equal(loc, tm.Location())
+	r.True(src.Equal(tm))
+
+	r.NoError((&locationScanner{&tm, loc, true}).Scan(src))
+	r.Equal(time.Date(2017, time.January, 2, 3, 4, 5, 0, loc), tm)
+
+	var ptr *time.Time
+	r.NoError((&locationScanner{types.Nullable(&ptr), loc, false}).Scan(src))
+	r.NotNil(ptr)
+	r.Equal(loc, ptr.Location())
+
+	r.NoError((&locationScanner{types.Nullable(&ptr), loc, false}).Scan(nil))
+	r.Nil(ptr)
+
+	r.Error((&locationScanner{&tm, loc, false}).Scan("foo"))
+}
+
+func TestWithLocation(t *testing.T) {
+	var (
+		s  string
+		tm time.Time
+	)
+
+	dest := withLocation([]interface{}{&s, &tm}, time.UTC, nil)
+	r := require.New(t)
+	r.Equal(&s, dest[0])
+	r.Equal(&locationScanner{&tm, time.UTC, false}, dest[1])
+}
+
+func TestValuesInLocation(t *testing.T) {
+	loc := time.FixedZone("kallax", 3*3600)
+	tm := time.Date(2017, time.January, 2, 3, 4, 5, 0, time.UTC)
+	values := []interface{}{"foo", tm, &tm, (*time.Time)(nil)}
+	valuesInLocation(values, loc)
+
+	r := require.New(t)
+	r.Equal("foo", values[0])
+	r.Equal(tm.In(loc), values[1])
+	r.Equal(tm.In(loc), values[2])
+	r.Equal((*time.Time)(nil), values[3])
+	r.Equal(time.UTC, tm.Location())
+}
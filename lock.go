@@ -0,0 +1,58 @@
+package kallax
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrStaleObject is returned when a record of a schema with optimistic
+// locking is updated, but its version in the database is not the version of
+// the record, because it has been updated or deleted since the record was
+// retrieved. The record must be reloaded before it is updated again.
+var ErrStaleObject = errors.New("kallax: record is stale, it was updated or deleted since it was retrieved")
+
+// lockVersion returns the version of the given record in the given lock
+// column.
+func lockVersion(schema Schema, record Record, col SchemaField) (int64, error) {
+	v, err := record.Value(col.String())
+	if err != nil {
+		return 0, err
+	}
+
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint()), nil
+	}
+	return 0, fmt.Errorf("kallax: lock column %s of table %s is not an integer", col, schema.Table())
+}
+
+// setLockVersion sets the given lock column of the given record to the given
+// version.
+func setLockVersion(schema Schema, record Record, col SchemaField, version int64) error {
+	ptr, err := record.ColumnAddress(col.String())
+	if err != nil {
+		return err
+	}
+
+	if scanner, ok := ptr.(sql.Scanner); ok {
+		return scanner.Scan(version)
+	}
+
+	rv := reflect.ValueOf(ptr)
+	if rv.Kind() == reflect.Ptr {
+		switch elem := rv.Elem(); elem.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			elem.SetInt(version)
+			return nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			elem.SetUint(uint64(version))
+			return nil
+		}
+	}
+	return fmt.Errorf("kallax: lock column %s of table %s is not an integer", col, schema.Table())
+}
@@ -0,0 +1,62 @@
+package kallax
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateStatement_Lock(t *testing.T) {
+	r := require.New(t)
+	schema := NewDynamicSchema("post", "id", true, "title", "version").
+		WithLock(f("version"))
+	record := NewDynamicRecord(schema)
+	r.NoError(record.Set("id", int64(1)))
+	r.NoError(record.Set("title", "foo"))
+	r.NoError(record.Set("version", int64(3)))
+
+	query, args, err := UpdateStatement(schema, record)
+	r.NoError(err)
+	r.Equal("UPDATE post SET id=$1,title=$2,version=$3 WHERE id=$4 AND version=$5", query)
+	r.Len(args, 5)
+	r.Equal([]interface{}{int64(1), "foo", int64(4)}, args[:3])
+	r.Equal(int64(3), args[4])
+
+	query, _, err = UpdateStatement(schema, record, f("title"))
+	r.NoError(err)
+	r.Equal("UPDATE post SET title=$1,version=$2 WHERE id=$3 AND version=$4", query)
+
+	r.NoError(record.Set("version", "foo"))
+	_, _, err = UpdateStatement(schema, record)
+	r.EqualError(err, "kallax: lock column version of table post is not an integer")
+}
+
+func TestStore_UpdateLock(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	schema := NewDynamicSchema("post", "id", true, "title", "version").
+		WithLock(f("version"))
+	record := NewDynamicRecord(schema)
+	r.NoError(record.Set("id", int64(1)))
+	r.NoError(record.Set("version", int64(3)))
+	record.setPersisted()
+
+	_, err = NewStore(db).Update(schema, record)
+	r.Equal(ErrStaleObject, err)
+	r.Equal(int64(3), record.Get("version"))
+
+	r.NoError(setLockVersion(schema, record, f("version"), 4))
+	r.Equal(int64(4), record.Get("version"))
+
+	m := newModel("foo", "foo@bar.baz", 1)
+	r.NoError(setLockVersion(ModelSchema, m, f("age"), 2))
+	r.Equal(2, m.Age)
+	version, err := lockVersion(ModelSchema, m, f("age"))
+	r.NoError(err)
+	r.Equal(int64(2), version)
+	r.Error(setLockVersion(ModelSchema, m, f("name"), 2))
+}
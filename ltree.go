@@ -0,0 +1,88 @@
+package kallax
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// LTree is a path of labels separated by dots, such as "Top.Science.Astronomy",
+// to be stored in a ltree column. It is used to represent materialized paths of
+// hierarchical data, which can be queried with the LTreeAncestorOf,
+// LTreeDescendantOf and LTreeMatch operators. The ltree extension is enabled
+// by the migrations and a GiST index is created for ltree columns.
+type LTree string
+
+// NewLTree returns a new path with the given labels.
+func NewLTree(labels ...string) LTree {
+	return LTree(strings.Join(labels, "."))
+}
+
+// Labels returns the labels of the path.
+func (t LTree) Labels() []string {
+	if t == "" {
+		return nil
+	}
+	return strings.Split(string(t), ".")
+}
+
+// Len returns the number of labels of the path.
+func (t LTree) Len() int {
+	return len(t.Labels())
+}
+
+// Parent returns the path without its last label. The parent of a path with
+// a single label is the empty path.
+func (t LTree) Parent() LTree {
+	labels := t.Labels()
+	if len(labels) == 0 {
+		return t
+	}
+	return NewLTree(labels[:len(labels)-1]...)
+}
+
+// Child returns the path with the given label appended.
+func (t LTree) Child(label string) LTree {
+	if t == "" {
+		return LTree(label)
+	}
+	return t + "." + LTree(label)
+}
+
+// IsAncestorOf reports whether the path is an ancestor of the given path or
+// is equal to it.
+func (t LTree) IsAncestorOf(other LTree) bool {
+	labels, otherLabels := t.Labels(), other.Labels()
+	if len(labels) > len(otherLabels) {
+		return false
+	}
+
+	for i, l := range labels {
+		if otherLabels[i] != l {
+			return false
+		}
+	}
+	return true
+}
+
+// Scan implements the sql.Scanner interface.
+func (t *LTree) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		*t = LTree(v)
+		return nil
+	case string:
+		*t = LTree(v)
+		return nil
+	case nil:
+		*t = ""
+		return nil
+	}
+	return fmt.Errorf("kallax: cannot scan type %s into LTree type", reflect.TypeOf(src))
+}
+
+// Value implements the driver.Valuer interface.
+func (t LTree) Value() (driver.Value, error) {
+	return string(t), nil
+}
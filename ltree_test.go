@@ -0,0 +1,74 @@
+package kallax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLTree(t *testing.T) {
+	r := require.New(t)
+	path := NewLTree("Top", "Science", "Astronomy")
+	r.Equal(LTree("Top.Science.Astronomy"), path)
+	r.Equal([]string{"Top", "Science", "Astronomy"}, path.Labels())
+	r.Equal(3, path.Len())
+	r.Equal(LTree("Top.Science"), path.Parent())
+	r.Equal(LTree(""), LTree("Top").Parent())
+	r.Equal(LTree(""), LTree("").Parent())
+	r.Equal(LTree("Top.Science.Astronomy.Stars"), path.Child("Stars"))
+	r.Equal(LTree("Top"), LTree("").Child("Top"))
+	r.Equal(0, LTree("").Len())
+}
+
+func TestLTree_IsAncestorOf(t *testing.T) {
+	r := require.New(t)
+	path := NewLTree("Top", "Science")
+	r.True(path.IsAncestorOf("Top.Science.Astronomy"))
+	r.True(path.IsAncestorOf("Top.Science"))
+	r.True(LTree("").IsAncestorOf("Top"))
+	r.False(path.IsAncestorOf("Top"))
+	r.False(path.IsAncestorOf("Top.Sciences"))
+	r.False(path.IsAncestorOf("Top.Hobbies.Astronomy"))
+}
+
+func TestLTree_Scan(t *testing.T) {
+	r := require.New(t)
+	var path LTree
+	r.NoError(path.Scan([]byte("Top.Science")))
+	r.Equal(LTree("Top.Science"), path)
+
+	r.NoError(path.Scan("Top"))
+	r.Equal(LTree("Top"), path)
+
+	r.NoError(path.Scan(nil))
+	r.Equal(LTree(""), path)
+
+	r.Error(path.Scan(int64(1)))
+
+	v, err := NewLTree("Top", "Science").Value()
+	r.NoError(err)
+	r.Equal("Top.Science", v)
+}
+
+func TestLTreeOperators(t *testing.T) {
+	r := require.New(t)
+	col := f("path")
+	schema := NewBaseSchema("categories", "__categories", f("id"), nil, nil, false, f("id"), col)
+
+	cases := []struct {
+		cond Condition
+		sql  string
+		arg  interface{}
+	}{
+		{LTreeAncestorOf(col, "Top.Science"), "__categories.path @> CAST(? AS ltree)", LTree("Top.Science")},
+		{LTreeDescendantOf(col, "Top.Science"), "__categories.path <@ CAST(? AS ltree)", LTree("Top.Science")},
+		{LTreeMatch(col, "*.Astronomy.*"), "__categories.path ~ CAST(? AS lquery)", "*.Astronomy.*"},
+	}
+
+	for _, c := range cases {
+		sql, args, err := c.cond(schema).ToSql()
+		r.NoError(err)
+		r.Equal(c.sql, sql)
+		r.Equal([]interface{}{c.arg}, args)
+	}
+}
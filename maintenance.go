@@ -0,0 +1,169 @@
+package kallax
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrMaintenanceInTransaction is returned when VacuumTable or
+	// ReindexConcurrently are run with a store holding a transaction, as
+	// PostgreSQL can not run them inside transaction blocks.
+	ErrMaintenanceInTransaction = errors.New("kallax: VACUUM and REINDEX CONCURRENTLY can not run inside a transaction")
+	// ErrStandbyDatabase is returned when a maintenance statement that
+	// writes is run against a standby server in recovery.
+	ErrStandbyDatabase = errors.New("kallax: the database is a standby in recovery, maintenance must be run against the primary server")
+	// ErrBackupInProgress is returned when VacuumTable or ReindexConcurrently
+	// are run while a backup of the database is in progress, as the WAL they
+	// generate would make it grow.
+	ErrBackupInProgress = errors.New("kallax: a backup of the database is in progress, try again once it is finished")
+)
+
+// reindexConcurrentlyVersion is the first version of PostgreSQL, as
+// reported by server_version_num, that supports REINDEX CONCURRENTLY.
+const reindexConcurrentlyVersion = 120000
+
+// VacuumOptions are the options of VacuumTable.
+type VacuumOptions struct {
+	// Full rewrites the whole table to reclaim all its unused space. The
+	// table is locked exclusively until it's done, so neither reads nor
+	// writes are possible meanwhile.
+	Full bool
+	// Freeze freezes the row versions of the table aggressively.
+	Freeze bool
+	// Analyze also updates the statistics of the table used by the planner.
+	Analyze bool
+}
+
+// AnalyzeTable updates the statistics of the table of the given schema used
+// by the query planner, only for the given columns if any is given. Unlike
+// the rest of the maintenance helpers, it can be run inside a transaction.
+// Maintenance helpers are only supported by the PostgreSQL dialect.
+func (s *Store) AnalyzeTable(schema Schema, cols ...SchemaField) error {
+	if d := s.Dialect(); !d.Supports(FeatureMaintenance) {
+		return &UnsupportedError{Dialect: d.Name(), Feature: FeatureMaintenance}
+	}
+
+	_, err := s.DisableCacher().runner.Exec(analyzeStatement(schema.Table(), ColumnNames(cols)))
+	return err
+}
+
+// VacuumTable reclaims the space of the dead rows of the table of the given
+// schema with the given options. It fails without running anything with
+// ErrMaintenanceInTransaction in a transaction, ErrStandbyDatabase against a
+// standby server and ErrBackupInProgress while a backup is in progress.
+func (s *Store) VacuumTable(schema Schema, opts VacuumOptions) error {
+	if _, err := s.checkMaintenance(); err != nil {
+		return err
+	}
+
+	_, err := s.DisableCacher().runner.Exec(vacuumStatement(schema.Table(), opts))
+	return err
+}
+
+// ReindexConcurrently rebuilds all the indexes of the table of the given
+// schema without locking out writes, which requires PostgreSQL 12 or later.
+// It makes the same safety checks as VacuumTable before running anything.
+func (s *Store) ReindexConcurrently(schema Schema) error {
+	version, err := s.checkMaintenance()
+	if err != nil {
+		return err
+	}
+
+	if version < reindexConcurrentlyVersion {
+		return fmt.Errorf("kallax: REINDEX CONCURRENTLY requires PostgreSQL 12 or later, but the server version is %d", version)
+	}
+
+	_, err = s.DisableCacher().runner.Exec("REINDEX TABLE CONCURRENTLY " + pq.QuoteIdentifier(schema.Table()))
+	return err
+}
+
+// checkMaintenance checks that the maintenance statements that can not run
+// inside transactions can be safely run with the store, and returns the
+// version of the server.
+func (s *Store) checkMaintenance() (int, error) {
+	if d := s.Dialect(); !d.Supports(FeatureMaintenance) {
+		return 0, &UnsupportedError{Dialect: d.Name(), Feature: FeatureMaintenance}
+	}
+
+	if _, ok := s.db.(*txRunner); ok {
+		return 0, ErrMaintenanceInTransaction
+	}
+
+	runner := s.DisableCacher().runner
+	var v string
+	if err := runner.QueryRow("SHOW server_version_num").Scan(&v); err != nil {
+		return 0, err
+	}
+
+	version, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("kallax: invalid server version %q: %s", v, err)
+	}
+
+	var standby, backup bool
+	if err := runner.QueryRow(maintenanceCheckQuery(version)).Scan(&standby, &backup); err != nil {
+		return 0, err
+	}
+
+	if standby {
+		return 0, ErrStandbyDatabase
+	}
+
+	if backup {
+		return 0, ErrBackupInProgress
+	}
+
+	return version, nil
+}
+
+// maintenanceCheckQuery returns the query that reports whether the server
+// with the given version is a standby in recovery and whether a backup is in
+// progress. Base backups are reported since PostgreSQL 13, and exclusive
+// backups, which were removed in PostgreSQL 15, before it.
+func maintenanceCheckQuery(version int) string {
+	backup := "false"
+	switch {
+	case version >= 150000:
+		backup = "EXISTS (SELECT 1 FROM pg_stat_progress_basebackup)"
+	case version >= 130000:
+		backup = "pg_is_in_backup() OR EXISTS (SELECT 1 FROM pg_stat_progress_basebackup)"
+	case version >= 90300:
+		backup = "pg_is_in_backup()"
+	}
+	return "SELECT pg_is_in_recovery(), " + backup
+}
+
+func analyzeStatement(table string, cols []string) string {
+	if len(cols) == 0 {
+		return "ANALYZE " + pq.QuoteIdentifier(table)
+	}
+
+	quoted := make([]string, len(cols))
+	for i, col := range cols {
+		quoted[i] = pq.QuoteIdentifier(col)
+	}
+	return fmt.Sprintf("ANALYZE %s (%s)", pq.QuoteIdentifier(table), strings.Join(quoted, ", "))
+}
+
+func vacuumStatement(table string, opts VacuumOptions) string {
+	var options []string
+	if opts.Full {
+		options = append(options, "FULL")
+	}
+	if opts.Freeze {
+		options = append(options, "FREEZE")
+	}
+	if opts.Analyze {
+		options = append(options, "ANALYZE")
+	}
+
+	if len(options) == 0 {
+		return "VACUUM " + pq.QuoteIdentifier(table)
+	}
+	return fmt.Sprintf("VACUUM (%s) %s", strings.Join(options, ", "), pq.QuoteIdentifier(table))
+}
@@ -0,0 +1,85 @@
+package kallax
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeTable(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	recordedQueries = nil
+	store := NewStore(db)
+	r.NoError(store.AnalyzeTable(ModelSchema))
+	r.NoError(store.AnalyzeTable(ModelSchema, f("name"), f("email")))
+	r.Equal([]string{
+		`ANALYZE "model"`,
+		`ANALYZE "model" ("name", "email")`,
+	}, recordedQueries)
+
+	err = store.WithDialect(SQLite).AnalyzeTable(ModelSchema)
+	r.EqualError(err, "kallax: maintenance statements are not supported by the sqlite dialect")
+}
+
+func TestMaintenanceChecks(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	store := NewStore(db)
+	for _, d := range []Dialect{MySQL, SQLite, CockroachDB} {
+		err := store.WithDialect(d).VacuumTable(ModelSchema, VacuumOptions{})
+		r.IsType(new(UnsupportedError), err, d.Name())
+		r.IsType(new(UnsupportedError), store.WithDialect(d).ReindexConcurrently(ModelSchema), d.Name())
+	}
+
+	recordedQueries = nil
+	r.NoError(store.Transaction(func(s *Store) error {
+		r.Equal(ErrMaintenanceInTransaction, s.VacuumTable(ModelSchema, VacuumOptions{}))
+		r.Equal(ErrMaintenanceInTransaction, s.ReindexConcurrently(ModelSchema))
+		r.NoError(s.AnalyzeTable(ModelSchema))
+		return nil
+	}))
+	r.Equal([]string{`ANALYZE "model"`}, recordedQueries)
+
+	recordedQueries = nil
+	r.Equal(sql.ErrNoRows, store.VacuumTable(ModelSchema, VacuumOptions{}))
+	r.Equal([]string{"SHOW server_version_num"}, recordedQueries)
+}
+
+func TestMaintenanceCheckQuery(t *testing.T) {
+	cases := []struct {
+		version  int
+		expected string
+	}{
+		{90200, "SELECT pg_is_in_recovery(), false"},
+		{90600, "SELECT pg_is_in_recovery(), pg_is_in_backup()"},
+		{140005, "SELECT pg_is_in_recovery(), pg_is_in_backup() OR EXISTS (SELECT 1 FROM pg_stat_progress_basebackup)"},
+		{160001, "SELECT pg_is_in_recovery(), EXISTS (SELECT 1 FROM pg_stat_progress_basebackup)"},
+	}
+
+	for _, c := range cases {
+		require.Equal(t, c.expected, maintenanceCheckQuery(c.version), "version %d", c.version)
+	}
+}
+
+func TestVacuumStatement(t *testing.T) {
+	cases := []struct {
+		opts     VacuumOptions
+		expected string
+	}{
+		{VacuumOptions{}, `VACUUM "model"`},
+		{VacuumOptions{Analyze: true}, `VACUUM (ANALYZE) "model"`},
+		{VacuumOptions{Full: true, Freeze: true, Analyze: true}, `VACUUM (FULL, FREEZE, ANALYZE) "model"`},
+	}
+
+	for _, c := range cases {
+		require.Equal(t, c.expected, vacuumStatement("model", c.opts))
+	}
+}
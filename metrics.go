@@ -0,0 +1,151 @@
+package kallax
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// QueryMetrics are the timings of a statement run by a store.
+type QueryMetrics struct {
+	// Query is the SQL of the statement.
+	Query string
+	// Wait is the time spent waiting to acquire a connection from the pool
+	// of the database. It is always zero for the statements run inside a
+	// transaction, which hold their connection.
+	Wait time.Duration
+	// Exec is the time spent running the statement once the connection was
+	// acquired. For queries, it is the time until their rows are returned,
+	// not counting the time spent reading them.
+	Exec time.Duration
+	// Err is the error returned by the statement, if any.
+	Err error
+}
+
+// MetricsHook is a function that receives the metrics of every statement
+// run by a store.
+type MetricsHook func(QueryMetrics)
+
+// WithMetrics returns a new store that reports the metrics of all the
+// statements it runs to the given hook, so the saturation of the connection
+// pool can be told apart from slow queries. Statements are run on a
+// connection explicitly acquired from the pool, so prepared statements are
+// not cached outside of transactions.
+func (s *Store) WithMetrics(hook MetricsHook) *Store {
+	store := s.clone()
+	store.metrics = hook
+	return store.init()
+}
+
+// metricsRunner reports the metrics of the statements it runs. If db is
+// not nil, the statements are run on a connection acquired from it, so the
+// time waiting for the connection can be measured. Otherwise, they are run
+// with the wrapped runner.
+type metricsRunner struct {
+	squirrel.DBProxyContext
+	db   *sql.DB
+	hook MetricsHook
+}
+
+func newMetricsRunner(db squirrel.DBProxyContext, runner squirrel.DBProxyContext, hook MetricsHook) *metricsRunner {
+	r := &metricsRunner{DBProxyContext: runner, hook: hook}
+	if db, ok := db.(*dbRunner); ok {
+		r.db = db.DB
+	}
+	return r
+}
+
+// conn acquires a connection from the pool and returns the time waited.
+func (r *metricsRunner) conn(ctx context.Context) (*sql.Conn, time.Duration, error) {
+	start := time.Now()
+	conn, err := r.db.Conn(ctx)
+	return conn, time.Since(start), err
+}
+
+func (r *metricsRunner) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return r.ExecContext(context.Background(), query, args...)
+}
+
+func (r *metricsRunner) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if r.db == nil {
+		start := time.Now()
+		result, err := runnerContext(r.DBProxyContext).ExecContext(ctx, query, args...)
+		r.report(query, 0, time.Since(start), err)
+		return result, err
+	}
+
+	conn, wait, err := r.conn(ctx)
+	if err != nil {
+		r.report(query, wait, 0, err)
+		return nil, err
+	}
+	defer conn.Close()
+
+	start := time.Now()
+	result, err := conn.ExecContext(ctx, query, args...)
+	r.report(query, wait, time.Since(start), err)
+	return result, err
+}
+
+func (r *metricsRunner) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return r.QueryContext(context.Background(), query, args...)
+}
+
+func (r *metricsRunner) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if r.db == nil {
+		start := time.Now()
+		rows, err := runnerContext(r.DBProxyContext).QueryContext(ctx, query, args...)
+		r.report(query, 0, time.Since(start), err)
+		return rows, err
+	}
+
+	conn, wait, err := r.conn(ctx)
+	if err != nil {
+		r.report(query, wait, 0, err)
+		return nil, err
+	}
+
+	start := time.Now()
+	rows, err := conn.QueryContext(ctx, query, args...)
+	r.report(query, wait, time.Since(start), err)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	// closing the connection blocks until the rows are closed, and then
+	// returns it to the pool
+	go conn.Close()
+	return rows, nil
+}
+
+func (r *metricsRunner) QueryRow(query string, args ...interface{}) squirrel.RowScanner {
+	return r.QueryRowContext(context.Background(), query, args...)
+}
+
+func (r *metricsRunner) QueryRowContext(ctx context.Context, query string, args ...interface{}) squirrel.RowScanner {
+	if r.db == nil {
+		start := time.Now()
+		row := runnerContext(r.DBProxyContext).QueryRowContext(ctx, query, args...)
+		r.report(query, 0, time.Since(start), nil)
+		return row
+	}
+
+	conn, wait, err := r.conn(ctx)
+	if err != nil {
+		r.report(query, wait, 0, err)
+		return errRow{err}
+	}
+
+	start := time.Now()
+	row := conn.QueryRowContext(ctx, query, args...)
+	r.report(query, wait, time.Since(start), nil)
+	go conn.Close()
+	return row
+}
+
+func (r *metricsRunner) report(query string, wait, exec time.Duration, err error) {
+	r.hook(QueryMetrics{Query: query, Wait: wait, Exec: exec, Err: err})
+}
@@ -0,0 +1,58 @@
+package kallax
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithMetrics(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	var metrics []QueryMetrics
+	store := NewStore(db).WithMetrics(func(m QueryMetrics) {
+		metrics = append(metrics, m)
+	})
+
+	rs, err := store.Find(NewBaseQuery(ModelSchema))
+	r.NoError(err)
+	r.NoError(rs.Close())
+
+	m := newModel("foo", "foo@bar.baz", 1)
+	m.ID = 1
+	r.NoError(store.Delete(ModelSchema, m))
+	r.NoError(store.Transaction(func(s *Store) error {
+		return s.Delete(ModelSchema, m)
+	}))
+
+	r.Len(metrics, 3)
+	r.Equal("SELECT __model.id, __model.name, __model.email, __model.age FROM model __model", metrics[0].Query)
+	r.Equal("DELETE FROM model WHERE id=$1", metrics[1].Query)
+	r.Equal("DELETE FROM model WHERE id=$1", metrics[2].Query)
+	r.Zero(metrics[2].Wait)
+	for _, m := range metrics {
+		r.NoError(m.Err)
+	}
+}
+
+func TestWithMetrics_ConnError(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	r.NoError(db.Close())
+
+	var metrics []QueryMetrics
+	store := NewStore(db).WithMetrics(func(m QueryMetrics) {
+		metrics = append(metrics, m)
+	})
+
+	_, err = store.RawExec("DELETE FROM model")
+	r.Error(err)
+	r.Len(metrics, 1)
+	r.Equal(err, metrics[0].Err)
+	r.Zero(metrics[0].Exec)
+}
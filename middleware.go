@@ -0,0 +1,117 @@
+package kallax
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// QueryExecutor runs the SQL statements of a store.
+type QueryExecutor interface {
+	// ExecContext runs a statement that does not return rows.
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	// QueryContext runs a statement that returns rows.
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// Middleware wraps the executor of the statements of a store, returning an
+// executor that runs them with the given one, so it can run code before and
+// after them, change them or run them more than once.
+type Middleware func(next QueryExecutor) QueryExecutor
+
+// ExecutorFuncs is a QueryExecutor that runs the statements with the given
+// functions, to write middlewares without declaring a type. Both functions
+// must be set.
+type ExecutorFuncs struct {
+	Exec  func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	Query func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// ExecContext runs a statement that does not return rows with Exec.
+func (f ExecutorFuncs) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return f.Exec(ctx, query, args...)
+}
+
+// QueryContext runs a statement that returns rows with Query.
+func (f ExecutorFuncs) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return f.Query(ctx, query, args...)
+}
+
+// Use returns a new store that runs all its statements through the given
+// middlewares, after the ones already used by the store. The first
+// middleware is the outermost one, so it's the first one to see the
+// statements. Statements are given to the middlewares as they are generated,
+// before they are checked by the guards of the store and rewritten for its
+// dialect. The stores holding a transaction use the same middlewares, so
+// middlewares retrying statements must not retry the ones of transactions.
+func (s *Store) Use(middlewares ...Middleware) *Store {
+	store := s.clone()
+	store.middlewares = append(append([]Middleware(nil), s.middlewares...), middlewares...)
+	return store.init()
+}
+
+// middlewareRunner runs the statements of its wrapped runner through a chain
+// of middlewares. Statements are never prepared by the middlewares.
+type middlewareRunner struct {
+	squirrel.DBProxyContext
+	executor QueryExecutor
+}
+
+func newMiddlewareRunner(runner squirrel.DBProxyContext, middlewares []Middleware) *middlewareRunner {
+	var executor QueryExecutor = runnerContext(runner)
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		executor = middlewares[i](executor)
+	}
+	return &middlewareRunner{DBProxyContext: runner, executor: executor}
+}
+
+func (r *middlewareRunner) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return r.ExecContext(context.Background(), query, args...)
+}
+
+func (r *middlewareRunner) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return r.executor.ExecContext(ctx, query, args...)
+}
+
+func (r *middlewareRunner) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return r.QueryContext(context.Background(), query, args...)
+}
+
+func (r *middlewareRunner) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return r.executor.QueryContext(ctx, query, args...)
+}
+
+func (r *middlewareRunner) QueryRow(query string, args ...interface{}) squirrel.RowScanner {
+	return r.QueryRowContext(context.Background(), query, args...)
+}
+
+// QueryRowContext runs the query with QueryContext, as the middlewares only
+// run queries returning rows, and returns its first row.
+func (r *middlewareRunner) QueryRowContext(ctx context.Context, query string, args ...interface{}) squirrel.RowScanner {
+	rows, err := r.executor.QueryContext(ctx, query, args...)
+	if err != nil {
+		return errRow{err}
+	}
+	return firstRow{rows}
+}
+
+// firstRow scans the first row of its rows, which are closed once scanned.
+type firstRow struct {
+	rows *sql.Rows
+}
+
+func (r firstRow) Scan(dest ...interface{}) error {
+	defer r.rows.Close()
+	if !r.rows.Next() {
+		if err := r.rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+
+	if err := r.rows.Scan(dest...); err != nil {
+		return err
+	}
+	return r.rows.Close()
+}
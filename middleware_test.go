@@ -0,0 +1,100 @@
+package kallax
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func recordingMiddleware(name string, log *[]string) Middleware {
+	return func(next QueryExecutor) QueryExecutor {
+		return ExecutorFuncs{
+			Exec: func(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+				*log = append(*log, name+" exec "+query)
+				return next.ExecContext(ctx, query, args...)
+			},
+			Query: func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+				*log = append(*log, name+" query "+query)
+				return next.QueryContext(ctx, query, args...)
+			},
+		}
+	}
+}
+
+func TestStoreUse(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	var log []string
+	base := NewStore(db)
+	store := base.Use(recordingMiddleware("a", &log)).Use(recordingMiddleware("b", &log))
+
+	recordedQueries = nil
+	_, err = store.RawExec("DELETE FROM model WHERE id = $1", 1)
+	r.NoError(err)
+
+	rs, err := store.Find(NewBaseQuery(ModelSchema))
+	r.NoError(err)
+	r.NoError(rs.Close())
+
+	var n int64
+	r.Equal(sql.ErrNoRows, store.runner.QueryRow("SELECT 1").Scan(&n))
+
+	r.NoError(store.Transaction(func(s *Store) error {
+		_, err := s.RawExec("DELETE FROM rel")
+		return err
+	}))
+
+	_, err = base.RawExec("DELETE FROM model")
+	r.NoError(err)
+
+	r.Equal([]string{
+		"a exec DELETE FROM model WHERE id = $1",
+		"b exec DELETE FROM model WHERE id = $1",
+		"a query SELECT __model.id, __model.name, __model.email, __model.age FROM model __model",
+		"b query SELECT __model.id, __model.name, __model.email, __model.age FROM model __model",
+		"a query SELECT 1",
+		"b query SELECT 1",
+		"a exec DELETE FROM rel",
+		"b exec DELETE FROM rel",
+	}, log)
+	r.Equal([]string{
+		"DELETE FROM model WHERE id = $1",
+		"SELECT __model.id, __model.name, __model.email, __model.age FROM model __model",
+		"SELECT 1",
+		"DELETE FROM rel",
+		"DELETE FROM model",
+	}, recordedQueries)
+}
+
+func TestStoreUse_Intercept(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	errRejected := errors.New("rejected")
+	store := NewStore(db).Use(func(next QueryExecutor) QueryExecutor {
+		return ExecutorFuncs{
+			Exec: func(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+				return nil, errRejected
+			},
+			Query: func(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+				return next.QueryContext(ctx, "SELECT 2", args...)
+			},
+		}
+	}).DisableCacher().WithContext(context.Background())
+
+	recordedQueries = nil
+	_, err = store.RawExec("DELETE FROM model")
+	r.Equal(errRejected, err)
+
+	var n int64
+	r.Equal(sql.ErrNoRows, store.runner.QueryRow("SELECT 1").Scan(&n))
+	r.Equal([]string{"SELECT 2"}, recordedQueries)
+}
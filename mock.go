@@ -0,0 +1,1137 @@
+package kallax
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// ErrMockRelationships is returned by the mock stores when they are given a
+// query with relationships, as they cannot retrieve them.
+var ErrMockRelationships = errors.New("kallax: mock stores cannot run queries with relationships")
+
+// MockStore is an in-memory database that can be used instead of a database
+// in the tests of the code that uses the stores, so they don't need a live
+// PostgreSQL. It is the generic store of the mock stores generated with the
+// `mock` flag of `kallax gen`, which can share the same mock store as they
+// would share the same database.
+//
+// The rows of the records are kept with the values they would be written with
+// to the database, and scanned back into the records retrieved by the
+// queries, which are run evaluating their conditions on the rows. Only the
+// conditions of Eq, Neq, Lt, LtOrEq, Gt, GtOrEq, IsNull, IsNotNull, In, NotIn,
+// Like, Ilike, And, Or and Not, and orders by columns, can be evaluated, and
+// the queries with relationships cannot be run. Nothing is validated by
+// constraints of the database, except that the primary keys are unique.
+//
+// The next operations of the store can be made to fail with QueueError. It
+// is safe for concurrent use.
+type MockStore struct {
+	mu     sync.Mutex
+	tables map[string]*mockTable
+	errors []error
+}
+
+// mockTable is a table of a mock store.
+type mockTable struct {
+	rows []mockRow
+	// seq is the last auto-incrementable primary key of the table.
+	seq int64
+}
+
+// mockRow is a row of a mock table, with the values of its columns by name.
+type mockRow map[string]driver.Value
+
+// NewMockStore returns a new empty mock store.
+func NewMockStore() *MockStore {
+	return &MockStore{tables: make(map[string]*mockTable)}
+}
+
+// QueueError queues the given errors, which are returned, in order, by the
+// next operations of the store instead of running them, so the code can be
+// tested with the errors of the database. Transactions are not operations,
+// but the ones run in them are.
+func (s *MockStore) QueueError(errs ...error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors = append(s.errors, errs...)
+}
+
+// nextError returns and dequeues the next queued error, if any. The store
+// must be locked.
+func (s *MockStore) nextError() error {
+	if len(s.errors) == 0 {
+		return nil
+	}
+
+	err := s.errors[0]
+	s.errors = s.errors[1:]
+	return err
+}
+
+// table returns the table of the given schema. The store must be locked.
+func (s *MockStore) table(schema Schema) *mockTable {
+	t, ok := s.tables[schema.Table()]
+	if !ok {
+		t = new(mockTable)
+		s.tables[schema.Table()] = t
+	}
+	return t
+}
+
+// Insert inserts the given record in the table of the given schema. If its
+// primary key is auto-incrementable, it is set to the next one of the table.
+func (s *MockStore) Insert(schema Schema, record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.nextError(); err != nil {
+		return err
+	}
+
+	if record.IsPersisted() {
+		return ErrNonNewDocument
+	}
+
+	if err := s.insert(schema, record); err != nil {
+		return err
+	}
+
+	record.setWritable(true)
+	record.setPersisted()
+	snapshot(record, ColumnNames(schema.Columns()), true)
+	return nil
+}
+
+// BatchInsert inserts the given records in the table of the given schema.
+// Either all of them are inserted or none is. The options are ignored.
+func (s *MockStore) BatchInsert(schema Schema, records []Record, opts BatchInsertOptions) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.nextError(); err != nil {
+		return err
+	}
+
+	t := s.table(schema)
+	rows, seq := t.rows, t.seq
+	for _, record := range records {
+		if err := s.insert(schema, record); err != nil {
+			t.rows, t.seq = rows, seq
+			return err
+		}
+	}
+
+	for _, record := range records {
+		record.setWritable(true)
+		record.setPersisted()
+		snapshot(record, ColumnNames(schema.Columns()), true)
+	}
+	return nil
+}
+
+// insert adds the row of the given record to the table of the given schema.
+// The store must be locked.
+func (s *MockStore) insert(schema Schema, record Record) error {
+	row, err := newMockRow(schema, record)
+	if err != nil {
+		return err
+	}
+
+	t := s.table(schema)
+	if schema.isPrimaryKeyAutoIncrementable() {
+		if err := t.setNextID(schema, record, row); err != nil {
+			return err
+		}
+	}
+
+	if t.find(schema, row) >= 0 {
+		return mockDuplicateError(schema)
+	}
+
+	t.rows = append(t.rows, row)
+	return nil
+}
+
+// Upsert inserts the given record in the table of the given schema or, if
+// there is a row with its values in the given columns, updates the given
+// columns of that row instead. If no columns to update are given, the
+// existing row is left as is.
+func (s *MockStore) Upsert(schema Schema, record Record, conflict []SchemaField, update ...SchemaField) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.nextError(); err != nil {
+		return err
+	}
+
+	row, err := newMockRow(schema, record)
+	if err != nil {
+		return err
+	}
+
+	t := s.table(schema)
+	i := t.match(row, ColumnNames(conflict))
+	autoIncr := schema.isPrimaryKeyAutoIncrementable()
+	persisted := true
+	switch {
+	case i < 0:
+		if autoIncr {
+			if err := t.setNextID(schema, record, row); err != nil {
+				return err
+			}
+		}
+
+		if t.find(schema, row) >= 0 {
+			return mockDuplicateError(schema)
+		}
+		t.rows = append(t.rows, row)
+	case len(update) > 0:
+		for _, col := range ColumnNames(update) {
+			t.rows[i][col] = row[col]
+		}
+
+		if autoIncr {
+			id := t.rows[i][schema.ID().String()]
+			if err := scanColumnValue(record, schema.ID().String(), id); err != nil {
+				return err
+			}
+		}
+	default:
+		persisted = !autoIncr
+	}
+
+	if persisted {
+		record.setWritable(true)
+		record.setPersisted()
+		snapshot(record, ColumnNames(schema.Columns()), true)
+	}
+	return nil
+}
+
+// Update updates the given columns of the given record in the table of the
+// given schema, or all of them if none is given. If the schema has a lock
+// column, the version of the record must be the one in the table, and it is
+// incremented.
+func (s *MockStore) Update(schema Schema, record Record, cols ...SchemaField) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.nextError(); err != nil {
+		return 0, err
+	}
+
+	if !record.IsWritable() {
+		return 0, ErrNotWritable
+	}
+
+	if !record.IsPersisted() {
+		return 0, ErrNewDocument
+	}
+
+	if record.GetID().IsEmpty() {
+		return 0, ErrEmptyID
+	}
+
+	cols, err := updatedColumns(schema, record, cols)
+	if err != nil {
+		return 0, err
+	}
+
+	row, err := newMockRow(schema, record)
+	if err != nil {
+		return 0, err
+	}
+
+	t := s.table(schema)
+	i := t.find(schema, row)
+	lock := schema.LockField()
+	if lock != nil && i >= 0 {
+		if eq, err := mockEqual(t.rows[i][lock.String()], row[lock.String()]); err != nil || !eq {
+			i = -1
+		}
+	}
+
+	if i < 0 {
+		if lock != nil {
+			return 0, ErrStaleObject
+		}
+		return 0, ErrNoRowUpdate
+	}
+
+	names := ColumnNames(cols)
+	columns := ColumnNames(schema.Columns())
+	for col, v := range row {
+		if containsString(names, col) || !containsString(columns, col) {
+			t.rows[i][col] = v
+		}
+	}
+
+	if lock != nil {
+		version, err := lockVersion(schema, record, lock)
+		if err != nil {
+			return 0, err
+		}
+
+		if err := setLockVersion(schema, record, lock, version+1); err != nil {
+			return 0, err
+		}
+		t.rows[i][lock.String()] = version + 1
+		names = append(names, lock.String())
+	}
+
+	snapshot(record, names, false)
+	return 1, nil
+}
+
+// Delete removes the given record from the table of the given schema or, if
+// its records are soft deleted, sets its soft delete column to the current
+// time.
+func (s *MockStore) Delete(schema Schema, record Record) error {
+	if col := schema.SoftDeleteField(); col != nil {
+		return s.delete(schema, record, col)
+	}
+	return s.delete(schema, record, nil)
+}
+
+// HardDelete removes the given record from the table of the given schema,
+// even if its records are soft deleted.
+func (s *MockStore) HardDelete(schema Schema, record Record) error {
+	return s.delete(schema, record, nil)
+}
+
+// delete removes the given record from the table of the given schema, or
+// sets its given soft delete column to the current time, if any.
+func (s *MockStore) delete(schema Schema, record Record, softDelete SchemaField) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.nextError(); err != nil {
+		return err
+	}
+
+	if record.GetID().IsEmpty() {
+		return ErrEmptyID
+	}
+
+	row, err := newMockRow(schema, record)
+	if err != nil {
+		return err
+	}
+
+	t := s.table(schema)
+	i := t.find(schema, row)
+	if softDelete != nil {
+		deletedAt := time.Now().Truncate(time.Microsecond)
+		if i >= 0 {
+			t.rows[i][softDelete.String()] = deletedAt
+		}
+		return setDeletionTime(schema, record, softDelete, deletedAt)
+	}
+
+	if i >= 0 {
+		t.rows = append(t.rows[:i:i], t.rows[i+1:]...)
+	}
+	return nil
+}
+
+// UpdateWhere sets the given columns to the given values in all the rows
+// selected by the given query, and returns the number of rows updated. If
+// the updates of the records of the schema are optimistically locked, their
+// versions are incremented.
+func (s *MockStore) UpdateWhere(q Query, values map[SchemaField]interface{}) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.nextError(); err != nil {
+		return 0, err
+	}
+
+	if len(values) == 0 {
+		return 0, ErrNoValues
+	}
+
+	schema := q.Schema()
+	updated := make(mockRow, len(values))
+	for col, v := range values {
+		v, err := mockValue(v)
+		if err != nil {
+			return 0, fmt.Errorf("kallax: cannot write column %s of table %s: %s", col, schema.Table(), err)
+		}
+		updated[col.String()] = v
+	}
+
+	rows, err := q.selectRows(s.table(schema).rows, true)
+	if err != nil {
+		return 0, err
+	}
+
+	lock := schema.LockField()
+	for _, row := range rows {
+		if lock != nil {
+			if _, ok := updated[lock.String()]; !ok {
+				version, _ := row[lock.String()].(int64)
+				row[lock.String()] = version + 1
+			}
+		}
+
+		for col, v := range updated {
+			row[col] = v
+		}
+	}
+	return int64(len(rows)), nil
+}
+
+// DeleteWhere removes all the rows selected by the given query or, if the
+// records of the schema are soft deleted, sets their soft delete column to
+// the current time, and returns the number of rows removed.
+func (s *MockStore) DeleteWhere(q Query) (int64, error) {
+	if col := q.Schema().SoftDeleteField(); col != nil {
+		return s.UpdateWhere(q, map[SchemaField]interface{}{
+			col: time.Now().Truncate(time.Microsecond),
+		})
+	}
+	return s.HardDeleteWhere(q)
+}
+
+// HardDeleteWhere removes all the rows selected by the given query, even if
+// the records of the schema are soft deleted, and returns the number of rows
+// removed.
+func (s *MockStore) HardDeleteWhere(q Query) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.nextError(); err != nil {
+		return 0, err
+	}
+
+	schema := q.Schema()
+	t := s.table(schema)
+	rows, err := q.selectRows(t.rows, true)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, row := range rows {
+		if i := t.find(schema, row); i >= 0 {
+			t.rows = append(t.rows[:i:i], t.rows[i+1:]...)
+		}
+	}
+	return int64(len(rows)), nil
+}
+
+// Find returns a result set with the records retrieved by the given query.
+func (s *MockStore) Find(q Query) (ResultSet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.nextError(); err != nil {
+		return nil, err
+	}
+
+	if len(q.getRelationships()) > 0 {
+		return nil, ErrMockRelationships
+	}
+
+	rows, err := q.selectRows(s.table(q.Schema()).rows, true)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, _ := q.compile()
+	result, err := newMockRows(columns, rows)
+	if err != nil {
+		return nil, err
+	}
+	rs := NewResultSet(result, false, nil, columns...)
+	rs.partial = q.isPartial()
+	return rs, nil
+}
+
+// FindPage retrieves a page of the records of the given query paginated by
+// keyset, as Store.FindPage does.
+func (s *MockStore) FindPage(q Query) (*Page, error) {
+	return findPage(q, s.Find)
+}
+
+// Count returns the number of records selected by the given query.
+func (s *MockStore) Count(q Query) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.nextError(); err != nil {
+		return 0, err
+	}
+
+	rows, err := q.selectRows(s.table(q.Schema()).rows, false)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(rows)), nil
+}
+
+// Reload refreshes the given record with its row in the table of the given
+// schema and makes it writable.
+func (s *MockStore) Reload(schema Schema, record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.nextError(); err != nil {
+		return err
+	}
+
+	if record.GetID().IsEmpty() {
+		return ErrEmptyID
+	}
+
+	q := NewBaseQuery(schema)
+	q.Where(primaryKeyCond(schema, record))
+	rows, err := q.selectRows(s.table(schema).rows, false)
+	if err != nil {
+		return err
+	}
+
+	columns, _ := q.compile()
+	result, err := newMockRows(columns, rows)
+	if err != nil {
+		return err
+	}
+
+	rs := NewResultSet(result, false, nil, columns...)
+	defer rs.Close()
+	if !rs.Next() {
+		return ErrNotFound
+	}
+	return rs.Scan(record)
+}
+
+// Transaction runs the given callback with the store, and rolls back all the
+// changes it made if it returns an error or panics. Changes made by other
+// goroutines at the same time are rolled back as well.
+func (s *MockStore) Transaction(callback func(*MockStore) error) error {
+	if callback == nil {
+		return ErrInvalidTxCallback
+	}
+
+	s.mu.Lock()
+	tables := s.copyTables()
+	s.mu.Unlock()
+
+	committed := false
+	defer func() {
+		if !committed {
+			s.mu.Lock()
+			s.tables = tables
+			s.mu.Unlock()
+		}
+	}()
+
+	if err := callback(s); err != nil {
+		return err
+	}
+
+	committed = true
+	return nil
+}
+
+// copyTables returns a copy of the tables of the store. The store must be
+// locked.
+func (s *MockStore) copyTables() map[string]*mockTable {
+	tables := make(map[string]*mockTable, len(s.tables))
+	for name, t := range s.tables {
+		rows := make([]mockRow, len(t.rows))
+		for i, row := range t.rows {
+			rows[i] = make(mockRow, len(row))
+			for col, v := range row {
+				rows[i][col] = v
+			}
+		}
+		tables[name] = &mockTable{rows: rows, seq: t.seq}
+	}
+	return tables
+}
+
+// setNextID sets the auto-incrementable primary key of the given record and
+// its given row to the next one of the table.
+func (t *mockTable) setNextID(schema Schema, record Record, row mockRow) error {
+	id := t.seq + 1
+	if err := scanColumnValue(record, schema.ID().String(), id); err != nil {
+		return err
+	}
+
+	t.seq = id
+	row[schema.ID().String()] = id
+	return nil
+}
+
+// find returns the index of the row of the table with the primary key of the
+// given row, or -1 if there is none.
+func (t *mockTable) find(schema Schema, row mockRow) int {
+	return t.match(row, ColumnNames(schema.PrimaryKey()))
+}
+
+// match returns the index of the row of the table with the values of the
+// given row in the given columns, or -1 if there is none.
+func (t *mockTable) match(row mockRow, cols []string) int {
+	for i, r := range t.rows {
+		matches := len(cols) > 0
+		for _, col := range cols {
+			if eq, err := mockEqual(r[col], row[col]); err != nil || !eq {
+				matches = false
+				break
+			}
+		}
+
+		if matches {
+			return i
+		}
+	}
+	return -1
+}
+
+// mockDuplicateError returns the error of a row inserted in the table of the
+// given schema with a primary key that already exists.
+func mockDuplicateError(schema Schema) error {
+	return &ConstraintError{
+		Kind:       UniqueConstraint,
+		Table:      schema.Table(),
+		Constraint: schema.Table() + "_pkey",
+		Err:        fmt.Errorf("kallax: duplicate primary key in table %s", schema.Table()),
+	}
+}
+
+// newMockRow returns the row of the given record, with the values of its
+// columns and virtual columns as they are written to the database.
+func newMockRow(schema Schema, record Record) (mockRow, error) {
+	values, cols, err := RecordValues(record, ColumnNames(schema.Columns())...)
+	if err != nil {
+		return nil, err
+	}
+
+	virtualCols, virtualValues := virtualColumns(record, cols)
+	cols = append(cols, virtualCols...)
+	values = append(values, virtualValues...)
+
+	row := make(mockRow, len(cols))
+	for i, col := range cols {
+		v, err := mockValue(values[i])
+		if err != nil {
+			return nil, fmt.Errorf("kallax: cannot write column %s of table %s: %s", col, schema.Table(), err)
+		}
+		row[col] = v
+	}
+	return row, nil
+}
+
+// mockValue returns the given value as it is written to the database.
+func mockValue(v interface{}) (driver.Value, error) {
+	v, err := driver.DefaultParameterConverter.ConvertValue(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if b, ok := v.([]byte); ok {
+		v = append([]byte(nil), b...)
+	}
+	return v, nil
+}
+
+// scanColumnValue scans the given value into the given column of the given
+// record, as it is scanned from the database.
+func scanColumnValue(record Record, col string, v driver.Value) error {
+	ptr, err := record.ColumnAddress(col)
+	if err != nil {
+		return err
+	}
+
+	rows, err := newMockRows([]string{col}, []mockRow{{col: v}})
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return rows.Err()
+	}
+	return rows.Scan(ptr)
+}
+
+// selectRows returns the given rows of a mock table that are selected by the
+// query, in its order and, if paginate is true, skipping its offset and up to
+// its limit.
+func (q *BaseQuery) selectRows(rows []mockRow, paginate bool) ([]mockRow, error) {
+	conds := append([]ToSqler(nil), q.wheres...)
+	if col := q.schema.SoftDeleteField(); col != nil {
+		switch q.deleted {
+		case excludeDeleted:
+			conds = append(conds, IsNull(col)(q.schema))
+		case onlyDeleted:
+			conds = append(conds, IsNotNull(col)(q.schema))
+		}
+	}
+
+	if q.cursor != nil && q.cursor.cursor != "" {
+		conds = append(conds, q.cursorCond()(q.schema))
+	}
+
+	var selected []mockRow
+	for _, row := range rows {
+		ok, err := mockMatch(squirrel.And(sqlizers(conds)), q.schema.Alias(), row)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			selected = append(selected, row)
+		}
+	}
+
+	if err := q.sortRows(selected); err != nil {
+		return nil, err
+	}
+
+	if !paginate {
+		return selected, nil
+	}
+
+	if offset := int(q.offset); offset > 0 {
+		if offset > len(selected) {
+			offset = len(selected)
+		}
+		selected = selected[offset:]
+	}
+
+	if limit := int(q.limit); limit > 0 && limit < len(selected) {
+		selected = selected[:limit]
+	}
+	return selected, nil
+}
+
+// sortRows sorts the given rows in the order of the query.
+func (q *BaseQuery) sortRows(rows []mockRow) error {
+	var keys []*colOrder
+	for _, o := range q.orders {
+		if q.cursor != nil && q.cursor.before {
+			o = reverseOrder(o)
+		}
+
+		key, ok := o.(*colOrder)
+		if !ok {
+			return fmt.Errorf("kallax: mock stores cannot sort by %s", o.ToSql(q.schema))
+		}
+		keys = append(keys, key)
+	}
+
+	var err error
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, key := range keys {
+			a, b := rows[i][key.col.String()], rows[j][key.col.String()]
+			// NULL values are the greatest ones, as in PostgreSQL.
+			var c int
+			switch {
+			case a == nil && b == nil:
+				continue
+			case a == nil:
+				c = 1
+			case b == nil:
+				c = -1
+			default:
+				var cerr error
+				if c, cerr = mockCompare(a, b); cerr != nil {
+					err = cerr
+					return false
+				}
+			}
+
+			if c != 0 {
+				return (c < 0) == (key.order == asc)
+			}
+		}
+		return false
+	})
+	return err
+}
+
+func sqlizers(conds []ToSqler) []squirrel.Sqlizer {
+	result := make([]squirrel.Sqlizer, len(conds))
+	for i, cond := range conds {
+		result[i] = cond
+	}
+	return result
+}
+
+// mockMatch reports whether the given row of a table with the given alias
+// matches the given condition.
+func mockMatch(cond ToSqler, alias string, row mockRow) (bool, error) {
+	switch c := cond.(type) {
+	case squirrel.And:
+		for _, cond := range c {
+			if ok, err := mockMatch(cond, alias, row); err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	case squirrel.Or:
+		for _, cond := range c {
+			if ok, err := mockMatch(cond, alias, row); err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	case not:
+		ok, err := mockMatch(c.cond, alias, row)
+		return !ok, err
+	case squirrel.Eq:
+		return mockMatchEq(c, alias, row, false)
+	case squirrel.NotEq:
+		return mockMatchEq(squirrel.Eq(c), alias, row, true)
+	case squirrel.Lt:
+		return mockMatchCmp(c, alias, row, func(c int) bool { return c < 0 })
+	case squirrel.LtOrEq:
+		return mockMatchCmp(c, alias, row, func(c int) bool { return c <= 0 })
+	case squirrel.Gt:
+		return mockMatchCmp(c, alias, row, func(c int) bool { return c > 0 })
+	case squirrel.GtOrEq:
+		return mockMatchCmp(c, alias, row, func(c int) bool { return c >= 0 })
+	case *colOp:
+		switch c.op {
+		case "LIKE", "ILIKE":
+			return mockMatchLike(c, alias, row)
+		}
+	case errOp:
+		_, _, err := c.ToSql()
+		return false, err
+	case *errOp:
+		_, _, err := c.ToSql()
+		return false, err
+	}
+
+	sql, _, err := cond.ToSql()
+	if err != nil {
+		return false, err
+	}
+	return false, fmt.Errorf("kallax: mock stores cannot evaluate the condition %s", sql)
+}
+
+// mockColumn returns the value in the given row of the given column, which
+// is qualified by the given alias.
+func mockColumn(row mockRow, alias, col string) driver.Value {
+	return row[strings.TrimPrefix(col, alias+".")]
+}
+
+// mockMatchEq reports whether the given row matches the given equality
+// conditions, or the inequality conditions if not is true.
+func mockMatchEq(eq squirrel.Eq, alias string, row mockRow, not bool) (bool, error) {
+	for col, v := range eq {
+		value := mockColumn(row, alias, col)
+		v, list, err := mockCondValue(v)
+		if err != nil {
+			return false, err
+		}
+
+		var ok bool
+		switch {
+		case list != nil && len(list) == 0:
+			ok = not
+		case list != nil:
+			in := false
+			for _, elem := range list {
+				if in, err = mockEqual(value, elem); err != nil {
+					return false, err
+				} else if in {
+					break
+				}
+			}
+			ok = value != nil && in != not
+		case v == nil:
+			ok = (value == nil) != not
+		default:
+			eq, err := mockEqual(value, v)
+			if err != nil {
+				return false, err
+			}
+			ok = value != nil && eq != not
+		}
+
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// mockMatchCmp reports whether the given row matches the given comparison
+// conditions, which are true when the given function is true for the result
+// of comparing the column with the value.
+func mockMatchCmp(cond map[string]interface{}, alias string, row mockRow, fn func(int) bool) (bool, error) {
+	for col, v := range cond {
+		value := mockColumn(row, alias, col)
+		v, err := mockValue(v)
+		if err != nil {
+			return false, err
+		}
+
+		if value == nil || v == nil {
+			return false, nil
+		}
+
+		c, err := mockCompare(value, v)
+		if err != nil {
+			return false, err
+		}
+
+		if !fn(c) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// mockMatchLike reports whether the given row matches the given LIKE or
+// ILIKE condition.
+func mockMatchLike(op *colOp, alias string, row mockRow) (bool, error) {
+	value, ok := mockColumn(row, alias, op.col).(string)
+	if !ok {
+		return false, nil
+	}
+
+	pattern, _ := op.value.(string)
+	var expr bytes.Buffer
+	expr.WriteString("(?s)")
+	if op.op == "ILIKE" {
+		expr.WriteString("(?i)")
+	}
+
+	expr.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '%':
+			expr.WriteString(".*")
+		case c == '_':
+			expr.WriteString(".")
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	expr.WriteString("$")
+
+	re, err := regexp.Compile(expr.String())
+	if err != nil {
+		return false, err
+	}
+	return re.MatchString(value), nil
+}
+
+// mockCondValue returns the given value of an equality condition as it is
+// written to the database or, if it is a list of values, as in the In
+// conditions, the list of them.
+func mockCondValue(v interface{}) (driver.Value, []driver.Value, error) {
+	if _, ok := v.(driver.Valuer); !ok && v != nil && !driver.IsValue(v) {
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+			list := make([]driver.Value, rv.Len())
+			for i := range list {
+				elem, err := mockValue(rv.Index(i).Interface())
+				if err != nil {
+					return nil, nil, err
+				}
+				list[i] = elem
+			}
+			return nil, list, nil
+		}
+	}
+
+	value, err := mockValue(v)
+	return value, nil, err
+}
+
+// mockEqual reports whether the given values, as they are written to the
+// database, are equal. NULL values are not equal to any other.
+func mockEqual(a, b driver.Value) (bool, error) {
+	if a == nil || b == nil {
+		return false, nil
+	}
+
+	c, err := mockCompare(a, b)
+	return c == 0, err
+}
+
+// mockCompare compares the given non-NULL values, as they are written to the
+// database, and returns -1, 0 or 1 if the first one is lower, equal or
+// greater than the second one. Strings are converted to the type of the
+// other value, as the database does with the values of the cursors.
+func mockCompare(a, b driver.Value) (int, error) {
+	switch a := a.(type) {
+	case int64:
+		switch b := b.(type) {
+		case int64:
+			return compareOrder(a < b, a > b), nil
+		case float64:
+			return compareOrder(float64(a) < b, float64(a) > b), nil
+		case string:
+			f, err := strconv.ParseFloat(b, 64)
+			if err != nil {
+				return 0, fmt.Errorf("kallax: cannot compare %d with %q", a, b)
+			}
+			return mockCompare(float64(a), f)
+		}
+	case float64:
+		switch b := b.(type) {
+		case int64:
+			return mockCompare(a, float64(b))
+		case float64:
+			return compareOrder(a < b, a > b), nil
+		case string:
+			f, err := strconv.ParseFloat(b, 64)
+			if err != nil {
+				return 0, fmt.Errorf("kallax: cannot compare %v with %q", a, b)
+			}
+			return mockCompare(a, f)
+		}
+	case bool:
+		switch b := b.(type) {
+		case bool:
+			return compareOrder(!a && b, a && !b), nil
+		case string:
+			v, err := strconv.ParseBool(b)
+			if err != nil {
+				return 0, fmt.Errorf("kallax: cannot compare %t with %q", a, b)
+			}
+			return mockCompare(a, v)
+		}
+	case time.Time:
+		switch b := b.(type) {
+		case time.Time:
+			return compareOrder(a.Before(b), a.After(b)), nil
+		case string:
+			t, err := time.Parse(time.RFC3339Nano, b)
+			if err != nil {
+				return 0, fmt.Errorf("kallax: cannot compare %s with %q", a, b)
+			}
+			return mockCompare(a, t)
+		}
+	case string:
+		switch b := b.(type) {
+		case string:
+			return strings.Compare(a, b), nil
+		case []byte:
+			return bytes.Compare([]byte(a), b), nil
+		default:
+			c, err := mockCompare(b, a)
+			return -c, err
+		}
+	case []byte:
+		switch b := b.(type) {
+		case []byte:
+			return bytes.Compare(a, b), nil
+		case string:
+			return bytes.Compare(a, []byte(b)), nil
+		}
+	}
+	return 0, fmt.Errorf("kallax: cannot compare values of types %T and %T", a, b)
+}
+
+// compareOrder returns -1 if lt is true, 1 if gt is true and 0 otherwise.
+func compareOrder(lt, gt bool) int {
+	switch {
+	case lt:
+		return -1
+	case gt:
+		return 1
+	}
+	return 0
+}
+
+// newMockRows returns the given rows of a mock table as the rows of a query
+// that selects the given columns.
+func newMockRows(columns []string, rows []mockRow) (*sql.Rows, error) {
+	values := make([][]driver.Value, len(rows))
+	for i, row := range rows {
+		values[i] = make([]driver.Value, len(columns))
+		for j, col := range columns {
+			v := row[col]
+			if b, ok := v.([]byte); ok {
+				v = append([]byte(nil), b...)
+			}
+			values[i][j] = v
+		}
+	}
+
+	db := sql.OpenDB(mockConnector{columns, values})
+	defer db.Close()
+	return db.Query("")
+}
+
+// mockConnector is a connector to a database whose only query returns the
+// given rows, so they are scanned by database/sql as the rows of any driver.
+type mockConnector struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (c mockConnector) Connect(context.Context) (driver.Conn, error) {
+	return &mockConn{c}, nil
+}
+
+func (c mockConnector) Driver() driver.Driver {
+	return mockDriver{}
+}
+
+type mockDriver struct{}
+
+func (mockDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("kallax: the mock driver has no databases to open")
+}
+
+type mockConn struct {
+	mockConnector
+}
+
+func (c *mockConn) Prepare(string) (driver.Stmt, error) {
+	return &mockStmt{c}, nil
+}
+
+func (c *mockConn) Close() error { return nil }
+
+func (c *mockConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("kallax: the mock driver does not support transactions")
+}
+
+type mockStmt struct {
+	conn *mockConn
+}
+
+func (s *mockStmt) Close() error  { return nil }
+func (s *mockStmt) NumInput() int { return -1 }
+
+func (s *mockStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("kallax: the mock driver does not support statements")
+}
+
+func (s *mockStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &mockDriverRows{columns: s.conn.columns, values: s.conn.values}, nil
+}
+
+type mockDriverRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *mockDriverRows) Columns() []string { return r.columns }
+func (r *mockDriverRows) Close() error      { return nil }
+
+func (r *mockDriverRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
@@ -0,0 +1,248 @@
+package kallax
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func mockModels(r *require.Assertions, store *MockStore) {
+	for _, m := range []*model{
+		newModel("Alice", "alice@example.com", 32),
+		newModel("Bob", "bob@example.com", 20),
+		newModel("Carol", "carol@example.org", 45),
+	} {
+		r.NoError(store.Insert(ModelSchema, m))
+	}
+}
+
+func mockFind(r *require.Assertions, store *MockStore, q *BaseQuery) []string {
+	rs, err := store.Find(q)
+	r.NoError(err)
+	defer rs.Close()
+
+	var names []string
+	for rs.Next() {
+		record, err := rs.Get(ModelSchema)
+		r.NoError(err)
+		names = append(names, record.(*model).Name)
+	}
+	return names
+}
+
+func TestMockStore_Insert(t *testing.T) {
+	r := require.New(t)
+	store := NewMockStore()
+
+	m := newModel("Alice", "alice@example.com", 32)
+	r.NoError(store.Insert(ModelSchema, m))
+	r.Equal(int64(1), m.ID)
+	r.True(m.IsPersisted())
+	r.True(m.IsWritable())
+	r.Equal(ErrNonNewDocument, store.Insert(ModelSchema, m))
+
+	other := newModel("Bob", "bob@example.com", 20)
+	r.NoError(store.Insert(ModelSchema, other))
+	r.Equal(int64(2), other.ID)
+
+	m.Name = "Changed"
+	q := NewBaseQuery(ModelSchema)
+	q.Where(Eq(f("id"), m.GetID()))
+	r.Equal([]string{"Alice"}, mockFind(r, store, q))
+}
+
+func TestMockStore_Find(t *testing.T) {
+	r := require.New(t)
+	store := NewMockStore()
+	mockModels(r, store)
+
+	q := NewBaseQuery(ModelSchema)
+	q.Where(Gt(f("age"), 20))
+	q.Order(Desc(f("age")))
+	r.Equal([]string{"Carol", "Alice"}, mockFind(r, store, q))
+
+	q = NewBaseQuery(ModelSchema)
+	q.Where(Or(Like(f("email"), "%.org"), In(f("name"), "Bob", "Dave")))
+	q.Order(Asc(f("name")))
+	r.Equal([]string{"Bob", "Carol"}, mockFind(r, store, q))
+
+	q = NewBaseQuery(ModelSchema)
+	q.Where(Not(Ilike(f("name"), "a%")))
+	q.Where(LtOrEq(f("age"), 45))
+	r.Equal([]string{"Bob", "Carol"}, mockFind(r, store, q))
+
+	q = NewBaseQuery(ModelSchema)
+	q.Order(Asc(f("age")))
+	q.Offset(1)
+	q.Limit(1)
+	r.Equal([]string{"Alice"}, mockFind(r, store, q))
+
+	count, err := store.Count(q)
+	r.NoError(err)
+	r.Equal(int64(3), count)
+
+	q = NewBaseQuery(ModelSchema)
+	q.Where(ArrayContains(f("name"), "Alice"))
+	_, err = store.Find(q)
+	r.Error(err)
+
+	q = NewBaseQuery(ModelSchema)
+	r.NoError(q.AddRelation(RelSchema, "rel", OneToOne, nil))
+	_, err = store.Find(q)
+	r.Equal(ErrMockRelationships, err)
+}
+
+func TestMockStore_FindPage(t *testing.T) {
+	r := require.New(t)
+	store := NewMockStore()
+	mockModels(r, store)
+
+	q := NewBaseQuery(ModelSchema)
+	q.Order(Asc(f("age")), Asc(f("id")))
+	q.Limit(2)
+	page, err := store.FindPage(q)
+	r.NoError(err)
+	r.Len(page.Records, 2)
+	r.Equal("Bob", page.Records[0].(*model).Name)
+
+	q.AfterCursor(page.NextCursor())
+	page, err = store.FindPage(q)
+	r.NoError(err)
+	r.Len(page.Records, 1)
+	r.Equal("Carol", page.Records[0].(*model).Name)
+	r.Empty(page.NextCursor())
+}
+
+func TestMockStore_Update(t *testing.T) {
+	r := require.New(t)
+	store := NewMockStore()
+	m := newModel("Alice", "alice@example.com", 32)
+	r.NoError(store.Insert(ModelSchema, m))
+
+	m.Name = "Alicia"
+	m.Age = 33
+	updated, err := store.Update(ModelSchema, m, f("name"))
+	r.NoError(err)
+	r.Equal(int64(1), updated)
+
+	r.NoError(store.Reload(ModelSchema, m))
+	r.Equal("Alicia", m.Name)
+	r.Equal(32, m.Age)
+
+	other := newModel("Bob", "bob@example.com", 20)
+	other.ID = 5
+	other.setPersisted()
+	other.setWritable(true)
+	_, err = store.Update(ModelSchema, other)
+	r.Equal(ErrNoRowUpdate, err)
+	r.Equal(ErrNotFound, store.Reload(ModelSchema, other))
+}
+
+func TestMockStore_UpdatePartial(t *testing.T) {
+	r := require.New(t)
+	store := NewMockStore()
+	r.NoError(store.Insert(ModelSchema, newModel("Alice", "alice@example.com", 32)))
+
+	q := NewBaseQuery(ModelSchema)
+	q.SelectNot(f("age"))
+	rs, err := store.Find(q)
+	r.NoError(err)
+	r.True(rs.Next())
+	record, err := rs.Get(ModelSchema)
+	r.NoError(err)
+	r.NoError(rs.Close())
+
+	m := record.(*model)
+	r.True(m.IsWritable())
+	r.True(m.IsPartial())
+	r.Equal(0, m.Age)
+
+	m.Name = "Alicia"
+	_, err = store.Update(ModelSchema, m, f("age"))
+	r.Equal(ErrNotFetched, err)
+	_, err = store.Update(ModelSchema, m)
+	r.NoError(err)
+
+	r.NoError(store.Reload(ModelSchema, m))
+	r.False(m.IsPartial())
+	r.Equal("Alicia", m.Name)
+	r.Equal(32, m.Age)
+}
+
+func TestMockStore_Lock(t *testing.T) {
+	r := require.New(t)
+	store := NewMockStore()
+	schema := NewDynamicSchema("post", "id", true, "title", "version").
+		WithLock(f("version"))
+	record := NewDynamicRecord(schema)
+	r.NoError(record.Set("title", "foo"))
+	r.NoError(record.Set("version", int64(1)))
+	r.NoError(store.Insert(schema, record))
+
+	_, err := store.Update(schema, record)
+	r.NoError(err)
+	r.Equal(int64(2), record.Get("version"))
+
+	r.NoError(record.Set("version", int64(1)))
+	_, err = store.Update(schema, record)
+	r.Equal(ErrStaleObject, err)
+}
+
+func TestMockStore_Delete(t *testing.T) {
+	r := require.New(t)
+	store := NewMockStore()
+	mockModels(r, store)
+
+	q := NewBaseQuery(ModelSchema)
+	q.Where(Eq(f("name"), "Bob"))
+	rs, err := store.Find(q)
+	r.NoError(err)
+	r.True(rs.Next())
+	bob, err := rs.Get(ModelSchema)
+	r.NoError(err)
+	r.NoError(rs.Close())
+
+	r.NoError(store.Delete(ModelSchema, bob))
+	r.Equal([]string{"Alice", "Carol"}, mockFind(r, store, NewBaseQuery(ModelSchema)))
+}
+
+func TestMockStore_QueueError(t *testing.T) {
+	r := require.New(t)
+	store := NewMockStore()
+	errFoo, errBar := errors.New("foo"), errors.New("bar")
+	store.QueueError(errFoo, errBar)
+
+	r.Equal(errFoo, store.Insert(ModelSchema, newModel("Alice", "alice@example.com", 32)))
+	_, err := store.Count(NewBaseQuery(ModelSchema))
+	r.Equal(errBar, err)
+
+	count, err := store.Count(NewBaseQuery(ModelSchema))
+	r.NoError(err)
+	r.Equal(int64(0), count)
+}
+
+func TestMockStore_Transaction(t *testing.T) {
+	r := require.New(t)
+	store := NewMockStore()
+	mockModels(r, store)
+
+	errFoo := errors.New("foo")
+	err := store.Transaction(func(store *MockStore) error {
+		r.NoError(store.Insert(ModelSchema, newModel("Dave", "dave@example.com", 50)))
+		return errFoo
+	})
+	r.Equal(errFoo, err)
+	r.Equal(int64(3), mustMockCount(r, store))
+
+	r.NoError(store.Transaction(func(store *MockStore) error {
+		return store.Insert(ModelSchema, newModel("Dave", "dave@example.com", 50))
+	}))
+	r.Equal(int64(4), mustMockCount(r, store))
+}
+
+func mustMockCount(r *require.Assertions, store *MockStore) int64 {
+	count, err := store.Count(NewBaseQuery(ModelSchema))
+	r.NoError(err)
+	return count
+}
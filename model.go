@@ -38,6 +38,13 @@ type Model struct {
 	persisted      bool
 	writable       bool
 	saving         bool
+	// loaded are the values of the columns when the model was loaded or
+	// saved, which are compared to report its changes.
+	loaded map[string]driver.Value
+	// fetched are the columns retrieved by the partial query the model was
+	// loaded with, which are the only ones updated, or nil if all of them
+	// were retrieved.
+	fetched []string
 }
 
 // NewModel creates a new Model that is writable and not persisted.
@@ -61,10 +68,8 @@ func (m *Model) setPersisted() {
 }
 
 // IsWritable returns whether this Model can be saved into the database.
-// For example, a model with partially retrieved data is not writable, so
-// it is not saved by accident and the data is corrupted. For example, if
-// you select only 2 columns out of all the ones the table has, it will not
-// be writable.
+// For example, a model whose relationships were retrieved with a filter is
+// not writable, so it is not saved by accident and the data is corrupted.
 func (m *Model) IsWritable() bool {
 	return m.writable
 }
@@ -73,6 +78,23 @@ func (m *Model) setWritable(w bool) {
 	m.writable = w
 }
 
+// IsPartial returns whether only some of the columns of the Model were
+// retrieved from the database, because it was loaded with a query with
+// Select or SelectNot. Only the retrieved columns of a partial Model are
+// updated, so the rest of them are not overwritten with the values they
+// have in it. Reloading the Model retrieves all its columns.
+func (m *Model) IsPartial() bool {
+	return m.fetched != nil
+}
+
+func (m *Model) fetchedColumns() []string {
+	return m.fetched
+}
+
+func (m *Model) setFetched(cols []string) {
+	m.fetched = cols
+}
+
 // IsSaving reports whether the model is in the process of being saved or not.
 func (m *Model) IsSaving() bool {
 	return m.saving
@@ -86,6 +108,21 @@ func (m *Model) SetSaving(saving bool) {
 	m.saving = saving
 }
 
+func (m *Model) loadedValues() map[string]driver.Value {
+	return m.loaded
+}
+
+func (m *Model) setLoadedValues(values map[string]driver.Value, reset bool) {
+	if reset || m.loaded == nil {
+		m.loaded = values
+		return
+	}
+
+	for col, v := range values {
+		m.loaded[col] = v
+	}
+}
+
 // ClearVirtualColumns clears all the previous virtual columns.
 // This method is only intended for internal use. It is only exposed for
 // technical reasons.
@@ -135,6 +172,14 @@ type Identifiable interface {
 	GetID() Identifier
 }
 
+// CompositeIdentifiable must be implemented by those values whose primary key
+// is composed of more than one column.
+type CompositeIdentifiable interface {
+	// GetPrimaryKey returns the values of all the columns of the primary
+	// key, in the same order as the columns of the primary key of the schema.
+	GetPrimaryKey() []interface{}
+}
+
 // Persistable must be implemented by those values that can be persisted.
 type Persistable interface {
 	// IsPersisted returns whether this Model is new in the store or not.
@@ -187,15 +232,21 @@ type VirtualColumnContainer interface {
 
 var ErrEmptyVirtualColumn = fmt.Errorf("empty virtual column")
 
+// ErrGeneratedColumn is returned by the Value method of a record for the
+// columns whose value is computed by the database, such as tsvector columns,
+// which cannot be inserted nor updated.
+var ErrGeneratedColumn = fmt.Errorf("generated column")
+
 // RecordValues returns the values of a record at the given columns in the same
 // order as the columns.
-// It also returns the columns with any empty virtual column removed.
+// It also returns the columns with any empty virtual column or generated
+// column removed.
 func RecordValues(record Valuer, columns ...string) ([]interface{}, []string, error) {
 	var cols = make([]string, 0, len(columns))
 	var values = make([]interface{}, 0, len(columns))
 	for _, col := range columns {
 		v, err := record.Value(col)
-		if err == ErrEmptyVirtualColumn {
+		if err == ErrEmptyVirtualColumn || err == ErrGeneratedColumn {
 			continue
 		}
 
@@ -417,6 +468,17 @@ func (id NumericID) Raw() interface{} {
 // automatically converted to and from in the generated code.
 type UUID uuid.UUID
 
+// NewUUIDv4 returns a new random UUID, version 4.
+func NewUUIDv4() UUID {
+	return UUID(uuid.Must(uuid.NewV4()))
+}
+
+// NewUUIDv7 returns a new time-ordered UUID, version 7, which is lexically
+// sortable by its creation time.
+func NewUUIDv7() UUID {
+	return UUID(uuid.Must(uuid.NewV7()))
+}
+
 // Scan implements the Scanner interface.
 func (id *UUID) Scan(src interface{}) error {
 	return (*uuid.UUID)(id).Scan(src)
@@ -1,9 +1,11 @@
 package kallax
 
 import (
+	"fmt"
 	"sync"
 	"testing"
 
+	"github.com/gofrs/uuid"
 	"github.com/stretchr/testify/require"
 )
 
@@ -70,6 +72,19 @@ func TestULID_ScanValue(t *testing.T) {
 	r.NoError(id.Scan([]byte("015af13d-2271-fb69-2dcd-fb24a1fd7dcc")))
 }
 
+func TestNewUUID(t *testing.T) {
+	r := require.New(t)
+
+	v4 := NewUUIDv4()
+	r.False(v4.IsEmpty())
+	r.Equal(byte(4), uuid.UUID(v4).Version())
+	r.NotEqual(v4, NewUUIDv4())
+
+	v7 := NewUUIDv7()
+	r.False(v7.IsEmpty())
+	r.Equal(byte(7), uuid.UUID(v7).Version())
+}
+
 func TestVirtualColumn(t *testing.T) {
 	r := require.New(t)
 	record := newModel("", "", 0)
@@ -88,3 +103,35 @@ func TestVirtualColumn(t *testing.T) {
 
 	r.Error(s.Scan(nil))
 }
+
+type columnValuer map[string]interface{}
+
+func (v columnValuer) Value(col string) (interface{}, error) {
+	val, ok := v[col]
+	if !ok {
+		return nil, fmt.Errorf("kallax: column does not exist: %s", col)
+	}
+
+	if err, ok := val.(error); ok {
+		return nil, err
+	}
+	return val, nil
+}
+
+func TestRecordValues(t *testing.T) {
+	r := require.New(t)
+	record := columnValuer{
+		"id":     int64(1),
+		"rel_id": ErrEmptyVirtualColumn,
+		"search": ErrGeneratedColumn,
+		"name":   "foo",
+	}
+
+	values, cols, err := RecordValues(record, "id", "rel_id", "search", "name")
+	r.NoError(err)
+	r.Equal([]string{"id", "name"}, cols)
+	r.Equal([]interface{}{int64(1), "foo"}, values)
+
+	_, _, err = RecordValues(record, "id", "foo")
+	r.Error(err)
+}
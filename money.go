@@ -0,0 +1,187 @@
+package kallax
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/src-d/go-kallax.v1/types"
+)
+
+// MoneyType is the name of the composite type used to store Money values
+// with their currency.
+const MoneyType = "kallax_money"
+
+// Money is an amount of money in a specific currency. The amount is kept in
+// the minor unit of the currency, e.g. cents, to avoid rounding errors.
+// By default, Money is stored in a column of the composite type
+// `kallax_money`, which has a numeric amount and a currency code. If the
+// field has the struct tag `money:"money"` it will be stored in a column
+// with the Postgres money type instead, which does not store the currency.
+type Money struct {
+	// Amount in the minor unit of the currency.
+	Amount int64
+	// Currency is the ISO 4217 code of the currency.
+	Currency string
+}
+
+// NewMoney returns a new amount of money in the minor unit of the given
+// currency.
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: strings.ToUpper(currency)}
+}
+
+// currencyExponents contains the number of decimals of the currencies whose
+// minor unit is not the hundredth of the major unit.
+var currencyExponents = map[string]int{
+	"BHD": 3, "BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "IQD": 3, "ISK": 0,
+	"JOD": 3, "JPY": 0, "KMF": 0, "KRW": 0, "KWD": 3, "LYD": 3, "OMR": 3,
+	"PYG": 0, "RWF": 0, "TND": 3, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0,
+	"XOF": 0, "XPF": 0,
+}
+
+func currencyExponent(currency string) int {
+	if exp, ok := currencyExponents[strings.ToUpper(currency)]; ok {
+		return exp
+	}
+	return 2
+}
+
+// Decimal returns the amount in the major unit of the currency as a decimal
+// string, e.g. 1234 USD is "12.34".
+func (m Money) Decimal() string {
+	return formatMinorUnits(m.Amount, currencyExponent(m.Currency))
+}
+
+// String returns the amount and the currency of the money, e.g. "12.34 USD".
+func (m Money) String() string {
+	return fmt.Sprintf("%s %s", m.Decimal(), m.Currency)
+}
+
+// Scan implements the sql.Scanner interface. The value is expected to be a
+// `kallax_money` row literal, e.g. `(12.34,USD)`.
+func (m *Money) Scan(v interface{}) error {
+	switch t := v.(type) {
+	case []byte:
+		return m.Scan(string(t))
+	case string:
+		if !strings.HasPrefix(t, "(") || !strings.HasSuffix(t, ")") {
+			return fmt.Errorf("kallax: invalid money value %q", t)
+		}
+
+		parts := strings.Split(t[1:len(t)-1], ",")
+		if len(parts) != 2 {
+			return fmt.Errorf("kallax: invalid money value %q", t)
+		}
+
+		currency := strings.TrimSpace(parts[1])
+		amount, err := parseMinorUnits(parts[0], currencyExponent(currency))
+		if err != nil {
+			return fmt.Errorf("kallax: invalid money value %q: %s", t, err)
+		}
+
+		*m = Money{Amount: amount, Currency: currency}
+		return nil
+	}
+	return fmt.Errorf("kallax: cannot scan type %s into Money type", reflect.TypeOf(v))
+}
+
+// Value implements the driver.Valuer interface.
+func (m Money) Value() (driver.Value, error) {
+	return fmt.Sprintf("(%s,%s)", m.Decimal(), m.Currency), nil
+}
+
+// PGMoney returns a SQLType that stores the given money in a column of the
+// Postgres money type. Note that the money type does not store the currency,
+// so the currency of the money is left untouched when scanned. The database
+// is expected to use a monetary locale with 2 decimals.
+func PGMoney(m *Money) types.SQLType {
+	return (*pgMoney)(m)
+}
+
+type pgMoney Money
+
+func (m *pgMoney) Scan(v interface{}) error {
+	switch t := v.(type) {
+	case []byte:
+		return m.Scan(string(t))
+	case string:
+		// remove currency symbols and group separators added by the locale
+		var buf bytes.Buffer
+		for _, r := range t {
+			if (r >= '0' && r <= '9') || r == '.' || r == '-' {
+				buf.WriteRune(r)
+			}
+		}
+
+		amount, err := parseMinorUnits(buf.String(), 2)
+		if err != nil {
+			return fmt.Errorf("kallax: invalid money value %q: %s", t, err)
+		}
+
+		m.Amount = amount
+		return nil
+	}
+	return fmt.Errorf("kallax: cannot scan type %s into Money type", reflect.TypeOf(v))
+}
+
+func (m *pgMoney) Value() (driver.Value, error) {
+	return formatMinorUnits(m.Amount, 2), nil
+}
+
+func formatMinorUnits(amount int64, exp int) string {
+	if exp == 0 {
+		return strconv.FormatInt(amount, 10)
+	}
+
+	var sign string
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	s := strconv.FormatInt(amount, 10)
+	if len(s) <= exp {
+		s = strings.Repeat("0", exp-len(s)+1) + s
+	}
+	return sign + s[:len(s)-exp] + "." + s[len(s)-exp:]
+}
+
+func parseMinorUnits(s string, exp int) (int64, error) {
+	s = strings.TrimSpace(s)
+	var neg bool
+	if strings.HasPrefix(s, "-") {
+		neg = true
+		s = s[1:]
+	}
+
+	whole, frac := s, ""
+	if idx := strings.Index(s, "."); idx >= 0 {
+		whole, frac = s[:idx], s[idx+1:]
+	}
+
+	if len(frac) > exp {
+		if strings.Trim(frac[exp:], "0") != "" {
+			return 0, fmt.Errorf("too many decimals for currency")
+		}
+		frac = frac[:exp]
+	}
+	frac += strings.Repeat("0", exp-len(frac))
+
+	if whole == "" {
+		whole = "0"
+	}
+
+	amount, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if neg {
+		amount = -amount
+	}
+	return amount, nil
+}
@@ -0,0 +1,99 @@
+package kallax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoney_Decimal(t *testing.T) {
+	cases := []struct {
+		money    Money
+		expected string
+	}{
+		{NewMoney(1234, "usd"), "12.34"},
+		{NewMoney(5, "EUR"), "0.05"},
+		{NewMoney(-5, "EUR"), "-0.05"},
+		{NewMoney(-1234, "EUR"), "-12.34"},
+		{NewMoney(1234, "JPY"), "1234"},
+		{NewMoney(1234, "KWD"), "1.234"},
+		{Money{}, "0.00"},
+	}
+
+	for _, c := range cases {
+		require.Equal(t, c.expected, c.money.Decimal(), c.money.String())
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	require.Equal(t, "12.34 USD", NewMoney(1234, "usd").String())
+}
+
+func TestMoney_Scan(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected Money
+	}{
+		{"(12.34,USD)", NewMoney(1234, "USD")},
+		{"(12.3,USD)", NewMoney(1230, "USD")},
+		{"(12,USD)", NewMoney(1200, "USD")},
+		{"(-0.05,EUR)", NewMoney(-5, "EUR")},
+		{"(1234,JPY)", NewMoney(1234, "JPY")},
+		{"(12.340000,USD)", NewMoney(1234, "USD")},
+	}
+
+	for _, c := range cases {
+		var m Money
+		require.NoError(t, m.Scan([]byte(c.input)), c.input)
+		require.Equal(t, c.expected, m, c.input)
+	}
+}
+
+func TestMoney_ScanInvalid(t *testing.T) {
+	cases := []interface{}{
+		"12.34 USD",
+		"(12.34)",
+		"(12.345,USD)",
+		"(foo,USD)",
+		int64(1234),
+	}
+
+	for _, c := range cases {
+		var m Money
+		require.Error(t, m.Scan(c), "%v", c)
+	}
+}
+
+func TestMoney_Value(t *testing.T) {
+	v, err := NewMoney(1234, "USD").Value()
+	require.NoError(t, err)
+	require.Equal(t, "(12.34,USD)", v)
+
+	v, err = NewMoney(1234, "JPY").Value()
+	require.NoError(t, err)
+	require.Equal(t, "(1234,JPY)", v)
+}
+
+func TestPGMoney(t *testing.T) {
+	r := require.New(t)
+	m := NewMoney(0, "USD")
+	r.NoError(PGMoney(&m).Scan([]byte("-$1,234.50")))
+	r.Equal(NewMoney(-123450, "USD"), m)
+
+	v, err := PGMoney(&m).Value()
+	r.NoError(err)
+	r.Equal("-1234.50", v)
+
+	r.Error(PGMoney(&m).Scan(int64(1)))
+}
+
+func TestMoneyOperators(t *testing.T) {
+	r := require.New(t)
+	col := f("price")
+	schema := NewBaseSchema("products", "__products", f("id"), nil, nil, false, f("id"), col)
+
+	sql, args, err := MoneyGtOrEq(col, NewMoney(1234, "USD"))(schema).ToSql()
+	r.NoError(err)
+	r.Equal("((__products.price).currency = ? AND (__products.price).amount >= CAST(? AS numeric))", sql)
+	r.Equal([]interface{}{"USD", "12.34"}, args)
+}
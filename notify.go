@@ -0,0 +1,311 @@
+package kallax
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ErrListenerClosed is returned when a channel is listened to or unlistened
+// with a closed Listener.
+var ErrListenerClosed = errors.New("kallax: the listener is closed")
+
+// Notification is a notification sent to a channel of PostgreSQL with NOTIFY
+// or Store.Notify.
+type Notification struct {
+	// Channel is the name of the channel the notification was sent to.
+	Channel string
+	// Payload is the payload of the notification, which is empty if it was
+	// sent without one.
+	Payload string
+}
+
+// ListenerOptions are the options of a Listener.
+type ListenerOptions struct {
+	// MinReconnect is the time to wait before reconnecting after the
+	// connection of the listener is lost, which is doubled after every failed
+	// attempt up to MaxReconnect. They are a second and a minute by default.
+	MinReconnect, MaxReconnect time.Duration
+	// OnReconnect is called after the connection of the listener is
+	// reestablished. The notifications sent while it was lost are never
+	// received, so anything kept up to date with them must be refreshed.
+	OnReconnect func()
+	// OnError is called with the errors of the notifications that can not be
+	// handled, such as the changes with payloads that can not be decoded.
+	OnError func(error)
+}
+
+// notificationConn is the connection a Listener receives its notifications
+// from, which is a *pq.Listener.
+type notificationConn interface {
+	Listen(channel string) error
+	Unlisten(channel string) error
+	NotificationChannel() <-chan *pq.Notification
+	Close() error
+}
+
+// Listener receives the notifications sent to channels of PostgreSQL, with a
+// dedicated connection opened with lib/pq whatever the driver of the stores
+// is, and passes them to the handlers listening to their channels. The
+// handlers are called one at a time, in the order the notifications are
+// received, so they must not block. A Listener can be used concurrently.
+type Listener struct {
+	conn notificationConn
+	opts ListenerOptions
+
+	mu        sync.Mutex
+	closed    bool
+	handlers  map[string][]func(*Notification)
+	reconnect []func()
+	done      chan struct{}
+}
+
+// NewListener returns a new Listener that connects to the PostgreSQL database
+// with the given connection string, reconnecting if the connection is lost.
+func NewListener(dsn string, opts ListenerOptions) *Listener {
+	if opts.MinReconnect <= 0 {
+		opts.MinReconnect = time.Second
+	}
+
+	if opts.MaxReconnect < opts.MinReconnect {
+		opts.MaxReconnect = time.Minute
+	}
+
+	return newListener(pq.NewListener(dsn, opts.MinReconnect, opts.MaxReconnect, nil), opts)
+}
+
+func newListener(conn notificationConn, opts ListenerOptions) *Listener {
+	l := &Listener{
+		conn:     conn,
+		opts:     opts,
+		handlers: make(map[string][]func(*Notification)),
+		done:     make(chan struct{}),
+	}
+	go l.run()
+	return l
+}
+
+// Listen makes the listener pass the notifications of the given channel to
+// the given handler, along with the rest of handlers of the channel. The
+// channel is listened to with its first handler, which blocks until the
+// connection of the listener is established.
+func (l *Listener) Listen(channel string, handler func(*Notification)) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return ErrListenerClosed
+	}
+
+	if len(l.handlers[channel]) == 0 {
+		if err := l.conn.Listen(channel); err != nil {
+			return fmt.Errorf("kallax: unable to listen to channel %s: %s", channel, err)
+		}
+	}
+
+	l.handlers[channel] = append(l.handlers[channel], handler)
+	return nil
+}
+
+// Unlisten stops listening to the given channel, removing all its handlers.
+func (l *Listener) Unlisten(channel string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return ErrListenerClosed
+	}
+
+	if len(l.handlers[channel]) == 0 {
+		return nil
+	}
+
+	delete(l.handlers, channel)
+	if err := l.conn.Unlisten(channel); err != nil {
+		return fmt.Errorf("kallax: unable to unlisten channel %s: %s", channel, err)
+	}
+	return nil
+}
+
+// Close stops listening to all the channels and closes the connection of
+// the listener.
+func (l *Listener) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return nil
+	}
+
+	l.closed = true
+	close(l.done)
+	return l.conn.Close()
+}
+
+// onReconnect adds the given function to the ones called after the
+// connection of the listener is reestablished.
+func (l *Listener) onReconnect(fn func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.reconnect = append(l.reconnect, fn)
+}
+
+func (l *Listener) run() {
+	notifications := l.conn.NotificationChannel()
+	for {
+		select {
+		case <-l.done:
+			return
+		case n, ok := <-notifications:
+			if !ok {
+				return
+			}
+
+			// lib/pq sends a nil notification after reconnecting
+			if n == nil {
+				l.reconnected()
+			} else {
+				l.dispatch(&Notification{Channel: n.Channel, Payload: n.Extra})
+			}
+		}
+	}
+}
+
+func (l *Listener) reconnected() {
+	l.mu.Lock()
+	fns := append([]func(){}, l.reconnect...)
+	l.mu.Unlock()
+
+	for _, fn := range fns {
+		fn()
+	}
+
+	if l.opts.OnReconnect != nil {
+		l.opts.OnReconnect()
+	}
+}
+
+func (l *Listener) dispatch(n *Notification) {
+	l.mu.Lock()
+	handlers := append([]func(*Notification){}, l.handlers[n.Channel]...)
+	l.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler(n)
+	}
+}
+
+func (l *Listener) error(err error) {
+	if l.opts.OnError != nil {
+		l.opts.OnError(err)
+	}
+}
+
+// ChangeOperation is the kind of change of a record notified by the trigger
+// of its table or published to an EventBus.
+type ChangeOperation string
+
+const (
+	// ChangeInsert is the operation of the inserted records.
+	ChangeInsert ChangeOperation = "INSERT"
+	// ChangeUpdate is the operation of the updated records.
+	ChangeUpdate ChangeOperation = "UPDATE"
+	// ChangeDelete is the operation of the deleted records.
+	ChangeDelete ChangeOperation = "DELETE"
+	// ChangeUpsert is the operation of the upserted records, which is only
+	// published to an EventBus, as it's not known whether they were inserted
+	// or updated. The triggers notify upserts as inserts or updates.
+	ChangeUpsert ChangeOperation = "UPSERT"
+)
+
+// ChangesChannel returns the channel the changes of the records of the given
+// schema are notified to, which is the name of its table followed by
+// "_changes".
+func ChangesChannel(schema Schema) string {
+	return schema.Table() + "_changes"
+}
+
+// Change is a change of a record of a model with the `notify:"true"` tag in
+// its kallax.Model field, notified by the trigger generated with its
+// migrations.
+type Change struct {
+	// Operation is the kind of change.
+	Operation ChangeOperation
+	// Record is the changed record, which only has its primary key set. It's
+	// partial, so it must be reloaded before it is updated.
+	Record Record
+}
+
+// changePayload is the payload of the notifications of the changes, as it
+// is sent by the notification triggers.
+type changePayload struct {
+	Operation ChangeOperation `json:"operation"`
+	ID        string          `json:"id"`
+}
+
+// ListenChanges makes the listener pass the changes of the records of the
+// given schema to the given handler. The notifications that can not be
+// decoded are passed to the OnError function of the listener instead.
+func (l *Listener) ListenChanges(schema Schema, handler func(*Change)) error {
+	return l.Listen(ChangesChannel(schema), func(n *Notification) {
+		change, err := decodeChange(schema, n.Payload)
+		if err != nil {
+			l.error(err)
+			return
+		}
+		handler(change)
+	})
+}
+
+// InvalidateCache makes the listener invalidate the queries of the tables
+// of the given schemas cached in the given cache whenever their records
+// change, so the cache can be shared by processes that do not see the
+// changes made by each other. As the changes notified while the connection
+// is lost are never received, the cache is purged after reconnecting.
+func (l *Listener) InvalidateCache(cache *QueryCache, schemas ...Schema) error {
+	l.onReconnect(cache.Purge)
+	for _, schema := range schemas {
+		table := schema.Table()
+		err := l.Listen(ChangesChannel(schema), func(*Notification) {
+			cache.Invalidate(table)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeChange returns the change of a record of the given schema in the
+// given payload of a notification.
+func decodeChange(schema Schema, payload string) (*Change, error) {
+	var p changePayload
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return nil, fmt.Errorf("kallax: invalid change of table %s: %s", schema.Table(), err)
+	}
+
+	pk := schema.ID().String()
+	record := schema.New()
+	if err := scanColumnValue(record, pk, p.ID); err != nil {
+		return nil, fmt.Errorf("kallax: invalid change of table %s: %s", schema.Table(), err)
+	}
+	setFetched(record, []string{pk}, true)
+
+	return &Change{Operation: p.Operation, Record: record}, nil
+}
+
+// Notify sends a notification with the given payload to the given channel,
+// which is received by the listeners of the channel when the transaction
+// held by the store, if any, is committed.
+func (s *Store) Notify(channel, payload string) error {
+	if d := s.Dialect(); !d.Supports(FeatureNotifications) {
+		return &UnsupportedError{Dialect: d.Name(), Feature: FeatureNotifications}
+	}
+
+	_, err := s.runner.Exec("SELECT pg_notify($1, $2)", channel, payload)
+	return err
+}
@@ -0,0 +1,157 @@
+package kallax
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNotificationConn is a notificationConn whose notifications are sent by
+// the tests.
+type fakeNotificationConn struct {
+	channels      map[string]bool
+	notifications chan *pq.Notification
+	closed        bool
+}
+
+func newFakeNotificationConn() *fakeNotificationConn {
+	return &fakeNotificationConn{
+		channels:      make(map[string]bool),
+		notifications: make(chan *pq.Notification),
+	}
+}
+
+func (c *fakeNotificationConn) Listen(channel string) error {
+	if c.channels[channel] {
+		return pq.ErrChannelAlreadyOpen
+	}
+	c.channels[channel] = true
+	return nil
+}
+
+func (c *fakeNotificationConn) Unlisten(channel string) error {
+	delete(c.channels, channel)
+	return nil
+}
+
+func (c *fakeNotificationConn) NotificationChannel() <-chan *pq.Notification {
+	return c.notifications
+}
+
+func (c *fakeNotificationConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestListener(t *testing.T) {
+	r := require.New(t)
+	conn := newFakeNotificationConn()
+	var reconnects int
+	l := newListener(conn, ListenerOptions{OnReconnect: func() { reconnects++ }})
+
+	received := make(chan string, 2)
+	for i := 0; i < 2; i++ {
+		r.NoError(l.Listen("foo", func(n *Notification) {
+			received <- n.Channel + ":" + n.Payload
+		}))
+	}
+	r.Equal(map[string]bool{"foo": true}, conn.channels)
+
+	conn.notifications <- &pq.Notification{Channel: "bar", Extra: "baz"}
+	conn.notifications <- &pq.Notification{Channel: "foo", Extra: "baz"}
+	r.Equal("foo:baz", <-received)
+	r.Equal("foo:baz", <-received)
+
+	conn.notifications <- nil
+	conn.notifications <- &pq.Notification{Channel: "bar"}
+	r.Equal(1, reconnects)
+
+	r.NoError(l.Unlisten("foo"))
+	r.Empty(conn.channels)
+
+	r.NoError(l.Close())
+	r.True(conn.closed)
+	r.Equal(ErrListenerClosed, l.Listen("foo", func(*Notification) {}))
+	r.Equal(ErrListenerClosed, l.Unlisten("foo"))
+}
+
+func TestListener_ListenChanges(t *testing.T) {
+	r := require.New(t)
+	conn := newFakeNotificationConn()
+	errs := make(chan error, 1)
+	l := newListener(conn, ListenerOptions{OnError: func(err error) { errs <- err }})
+	defer l.Close()
+
+	changes := make(chan *Change, 1)
+	r.NoError(l.ListenChanges(ModelSchema, func(c *Change) { changes <- c }))
+	r.True(conn.channels["model_changes"])
+
+	conn.notifications <- &pq.Notification{Channel: "model_changes", Extra: `{"operation":"UPDATE","id":"42"}`}
+	change := <-changes
+	r.Equal(ChangeUpdate, change.Operation)
+	r.Equal(int64(42), change.Record.(*model).ID)
+	r.True(change.Record.(*model).IsPartial())
+
+	conn.notifications <- &pq.Notification{Channel: "model_changes", Extra: "foo"}
+	r.Contains((<-errs).Error(), "kallax: invalid change of table model")
+}
+
+func TestListener_InvalidateCache(t *testing.T) {
+	r := require.New(t)
+	conn := newFakeNotificationConn()
+	l := newListener(conn, ListenerOptions{})
+	defer l.Close()
+
+	cache := NewQueryCache()
+	r.NoError(l.InvalidateCache(cache, ModelSchema, RelSchema))
+	r.Equal(map[string]bool{"model_changes": true, "rel_changes": true}, conn.channels)
+
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	recordedQueries = nil
+	store := NewStore(db).DisableCacher().WithCache(cache, 0)
+	// the recording driver returns no rows, so the counts fail
+	count := func() { store.Count(NewBaseQuery(ModelSchema)) }
+
+	count()
+	count()
+	r.Len(recordedQueries, 1)
+
+	conn.notifications <- &pq.Notification{Channel: "rel_changes", Extra: `{"operation":"INSERT","id":"1"}`}
+	conn.notifications <- &pq.Notification{Channel: "foo"}
+	count()
+	r.Len(recordedQueries, 1)
+
+	conn.notifications <- &pq.Notification{Channel: "model_changes", Extra: `{"operation":"DELETE","id":"1"}`}
+	conn.notifications <- &pq.Notification{Channel: "foo"}
+	count()
+	r.Len(recordedQueries, 2)
+
+	conn.notifications <- nil
+	conn.notifications <- &pq.Notification{Channel: "foo"}
+	r.Equal(0, cache.Len())
+}
+
+func TestStore_Notify(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	recordedQueries, recordedArgs = nil, nil
+	store := NewStore(db)
+	r.NoError(store.Notify("foo", "bar"))
+	r.Equal([]string{"SELECT pg_notify($1, $2)"}, recordedQueries)
+	r.Equal([][]driver.Value{{"foo", "bar"}}, recordedArgs)
+
+	err = store.WithDialect(CockroachDB).Notify("foo", "bar")
+	var unsupported *UnsupportedError
+	r.True(errors.As(err, &unsupported))
+	r.Equal(FeatureNotifications, unsupported.Feature)
+}
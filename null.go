@@ -0,0 +1,68 @@
+//go:build go1.18
+// +build go1.18
+
+package kallax
+
+import (
+	"database/sql/driver"
+
+	"gopkg.in/src-d/go-kallax.v1/types"
+)
+
+// Null is a value of type T that may be null. It can be used instead of
+// pointers or the sql.Null* types for nullable columns, and is handled
+// natively by the generator for all the types kallax can store: the column has
+// the type of T and is nullable.
+type Null[T any] struct {
+	// Val is the value. If Valid is false, it is the zero value of T.
+	Val T
+	// Valid reports whether the value is not null.
+	Valid bool
+}
+
+// NewNull returns a new valid Null with the given value.
+func NewNull[T any](v T) Null[T] {
+	return Null[T]{Val: v, Valid: true}
+}
+
+// IsNull reports whether the value is null.
+func (n Null[T]) IsNull() bool {
+	return !n.Valid
+}
+
+// Ptr returns a pointer to the value, or nil if the value is null.
+func (n Null[T]) Ptr() *T {
+	if !n.Valid {
+		return nil
+	}
+	v := n.Val
+	return &v
+}
+
+// Scan implements the sql.Scanner interface.
+func (n *Null[T]) Scan(src interface{}) error {
+	if src == nil {
+		*n = Null[T]{}
+		return nil
+	}
+
+	var v T
+	if err := types.Nullable(&v).Scan(src); err != nil {
+		return err
+	}
+
+	*n = NewNull(v)
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (n Null[T]) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+
+	if v, ok := interface{}(n.Val).(driver.Valuer); ok {
+		return v.Value()
+	}
+	return driver.DefaultParameterConverter.ConvertValue(n.Val)
+}
@@ -0,0 +1,91 @@
+//go:build go1.18
+// +build go1.18
+
+package kallax
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNull_Scan(t *testing.T) {
+	r := require.New(t)
+
+	var s Null[string]
+	r.NoError(s.Scan([]byte("foo")))
+	r.Equal(NewNull("foo"), s)
+	r.False(s.IsNull())
+
+	r.NoError(s.Scan(nil))
+	r.Equal(Null[string]{}, s)
+	r.True(s.IsNull())
+
+	var n Null[int64]
+	r.NoError(n.Scan(int64(42)))
+	r.Equal(NewNull(int64(42)), n)
+
+	now := time.Now()
+	var tm Null[time.Time]
+	r.NoError(tm.Scan(now))
+	r.Equal(NewNull(now), tm)
+
+	var id Null[ULID]
+	ulid := NewULID()
+	r.NoError(id.Scan(ulid.String()))
+	r.Equal(NewNull(ulid), id)
+
+	type status string
+	var st Null[status]
+	r.NoError(st.Scan("active"))
+	r.Equal(NewNull(status("active")), st)
+
+	r.Error(n.Scan("foo"))
+}
+
+func TestNull_Value(t *testing.T) {
+	r := require.New(t)
+
+	v, err := Null[string]{}.Value()
+	r.NoError(err)
+	r.Nil(v)
+
+	v, err = NewNull("foo").Value()
+	r.NoError(err)
+	r.Equal("foo", v)
+
+	v, err = NewNull(int32(1)).Value()
+	r.NoError(err)
+	r.Equal(int64(1), v)
+
+	type status string
+	v, err = NewNull(status("active")).Value()
+	r.NoError(err)
+	r.Equal("active", v)
+
+	ulid := NewULID()
+	v, err = NewNull(ulid).Value()
+	r.NoError(err)
+	r.Equal(ulid.String(), v)
+}
+
+func TestNull_Ptr(t *testing.T) {
+	r := require.New(t)
+	r.Nil(Null[string]{}.Ptr())
+	r.Equal("foo", *NewNull("foo").Ptr())
+}
+
+func TestIsNull(t *testing.T) {
+	r := require.New(t)
+	col := f("nickname")
+	schema := NewBaseSchema("users", "__users", f("id"), nil, nil, false, f("id"), col)
+
+	sql, _, err := IsNull(col)(schema).ToSql()
+	r.NoError(err)
+	r.Equal("__users.nickname IS NULL", sql)
+
+	sql, _, err = IsNotNull(col)(schema).ToSql()
+	r.NoError(err)
+	r.Equal("__users.nickname IS NOT NULL", sql)
+}
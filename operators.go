@@ -4,11 +4,15 @@ import (
 	"database/sql/driver"
 	"errors"
 	"fmt"
+	"net"
+	"reflect"
 	"strings"
+	"time"
 
 	"gopkg.in/src-d/go-kallax.v1/types"
 
 	"github.com/Masterminds/squirrel"
+	"github.com/lann/builder"
 )
 
 // ScalarCond returns a kallax.Condition that compares a property with the passed
@@ -124,6 +128,16 @@ func Neq(col SchemaField, value interface{}) Condition {
 	}
 }
 
+// IsNull returns a condition that will be true when `col` is null.
+func IsNull(col SchemaField) Condition {
+	return Eq(col, nil)
+}
+
+// IsNotNull returns a condition that will be true when `col` is not null.
+func IsNotNull(col SchemaField) Condition {
+	return Neq(col, nil)
+}
+
 // Like returns a condition that will be true when `col` matches the given `value`.
 // The match is case-sensitive.
 // See https://www.postgresql.org/docs/9.6/static/functions-matching.html.
@@ -182,21 +196,71 @@ func Not(cond Condition) Condition {
 }
 
 // In returns a condition that will be true when `col` is equal to any of the
-// passed `values`.
+// passed `values`. If the only value passed is a query, `col` is compared
+// with the values of the single column selected by the query.
+//
+//	In(Schema.User.ID, NewPostQuery().Select(Schema.Post.AuthorFK))
+//	// ... id IN (SELECT author_id FROM post ...)
 func In(col SchemaField, values ...interface{}) Condition {
 	return func(schema Schema) ToSqler {
+		if q, ok := subquery(values); ok {
+			return &subqueryOp{col.QualifiedName(schema), "IN", q}
+		}
 		return squirrel.Eq{col.QualifiedName(schema): values}
 	}
 }
 
 // NotIn returns a condition that will be true when `col` is distinct to all of the
-// passed `values`.
+// passed `values`. As with In, the only value passed can be a query.
 func NotIn(col SchemaField, values ...interface{}) Condition {
 	return func(schema Schema) ToSqler {
+		if q, ok := subquery(values); ok {
+			return &subqueryOp{col.QualifiedName(schema), "NOT IN", q}
+		}
 		return squirrel.NotEq{col.QualifiedName(schema): values}
 	}
 }
 
+// Exists returns a condition that will be true when the given query
+// retrieves any row. The query can be correlated with the one it's used in
+// by comparing its columns with the ones of the latter using EqOuter.
+func Exists(q Query) Condition {
+	return func(schema Schema) ToSqler {
+		return &subqueryOp{"", "EXISTS", q}
+	}
+}
+
+// NotExists returns a condition that will be true when the given query does
+// not retrieve any row.
+func NotExists(q Query) Condition {
+	return func(schema Schema) ToSqler {
+		return &subqueryOp{"", "NOT EXISTS", q}
+	}
+}
+
+// EqOuter returns a condition that will be true when `col` is equal to the
+// column `outerCol` of the given schema. It's meant to correlate a query used
+// in Exists or In with the query of the given schema it's used in.
+//
+//	Exists(NewPostQuery().Where(EqOuter(Schema.Post.AuthorFK, Schema.User, Schema.User.ID)))
+//	// ... EXISTS (SELECT 1 FROM post __post WHERE __post.author_id = __user.id)
+func EqOuter(col SchemaField, outer Schema, outerCol SchemaField) Condition {
+	return func(schema Schema) ToSqler {
+		return &colColOp{col.QualifiedName(schema), "=", outerCol.QualifiedName(outer)}
+	}
+}
+
+// subquery returns the query of the given values of a condition, if the
+// query is the only value.
+func subquery(values []interface{}) (Query, bool) {
+	if len(values) != 1 {
+		return nil, false
+	}
+
+	q, ok := values[0].(Query)
+	return q, ok
+}
+
 // ArrayEq returns a condition that will be true when `col` is equal to an
 // array with the given elements.
 func ArrayEq(col SchemaField, values ...interface{}) Condition {
@@ -279,6 +343,14 @@ func ArrayOverlap(col SchemaField, values ...interface{}) Condition {
 	}
 }
 
+// AnyEquals returns a condition that will be true when any of the elements
+// of the array in `col` is equal to the given value.
+func AnyEquals(col SchemaField, value interface{}) Condition {
+	return func(schema Schema) ToSqler {
+		return &anyOp{col.QualifiedName(schema), "=", value}
+	}
+}
+
 // JSONIsObject returns a condition that will be true when `col` is a JSON
 // object.
 func JSONIsObject(col SchemaField) Condition {
@@ -327,6 +399,14 @@ func JSONContainedBy(col SchemaField, elem interface{}) Condition {
 	}
 }
 
+// JSONHasKey returns a condition that will be true when `col` contains the
+// given key. Will also match elements if the column is an array.
+func JSONHasKey(col SchemaField, key string) Condition {
+	return func(schema Schema) ToSqler {
+		return &colOp{col.QualifiedName(schema), "??", key}
+	}
+}
+
 // JSONContainsAnyKey returns a condition that will be true when `col` contains
 // any of the given keys. Will also match elements if the column is an array.
 func JSONContainsAnyKey(col SchemaField, keys ...string) Condition {
@@ -344,6 +424,328 @@ func JSONContainsAllKeys(col SchemaField, keys ...string) Condition {
 	}
 }
 
+// HStoreHasKey returns a condition that will be true when `col` contains
+// the given key.
+func HStoreHasKey(col SchemaField, key string) Condition {
+	return func(schema Schema) ToSqler {
+		return &colOp{col.QualifiedName(schema), "??", driver.Value(key)}
+	}
+}
+
+// HStoreHasAnyKey returns a condition that will be true when `col` contains
+// any of the given keys.
+func HStoreHasAnyKey(col SchemaField, keys ...string) Condition {
+	return func(schema Schema) ToSqler {
+		return &colOp{col.QualifiedName(schema), "??|", types.Slice(keys)}
+	}
+}
+
+// HStoreHasAllKeys returns a condition that will be true when `col` contains
+// all the given keys.
+func HStoreHasAllKeys(col SchemaField, keys ...string) Condition {
+	return func(schema Schema) ToSqler {
+		return &colOp{col.QualifiedName(schema), "??&", types.Slice(keys)}
+	}
+}
+
+// HStoreContains returns a condition that will be true when `col` contains
+// all the key/value pairs of the given hstore.
+func HStoreContains(col SchemaField, elem HStore) Condition {
+	return func(schema Schema) ToSqler {
+		return &colOp{col.QualifiedName(schema), "@>", elem}
+	}
+}
+
+// HStoreContainedBy returns a condition that will be true when all the
+// key/value pairs of `col` are present in the given hstore.
+func HStoreContainedBy(col SchemaField, elem HStore) Condition {
+	return func(schema Schema) ToSqler {
+		return &colOp{col.QualifiedName(schema), "<@", elem}
+	}
+}
+
+// OlderThan returns a condition that will be true when the time in `col` is
+// before the current time minus the given interval.
+func OlderThan(col SchemaField, iv Interval) Condition {
+	return func(schema Schema) ToSqler {
+		return newCustomOp(":col: < now() - CAST(:arg: AS interval)", col.QualifiedName(schema), []interface{}{iv}, false)
+	}
+}
+
+// NewerThan returns a condition that will be true when the time in `col` is
+// after the current time minus the given interval.
+func NewerThan(col SchemaField, iv Interval) Condition {
+	return func(schema Schema) ToSqler {
+		return newCustomOp(":col: > now() - CAST(:arg: AS interval)", col.QualifiedName(schema), []interface{}{iv}, false)
+	}
+}
+
+// ElapsedGt returns a condition that will be true when the time elapsed
+// between `from` and `to` is greater than the given interval.
+func ElapsedGt(from, to SchemaField, iv Interval) Condition {
+	return func(schema Schema) ToSqler {
+		return &elapsedOp{from.QualifiedName(schema), to.QualifiedName(schema), ">", iv}
+	}
+}
+
+// ElapsedLt returns a condition that will be true when the time elapsed
+// between `from` and `to` is lower than the given interval.
+func ElapsedLt(from, to SchemaField, iv Interval) Condition {
+	return func(schema Schema) ToSqler {
+		return &elapsedOp{from.QualifiedName(schema), to.QualifiedName(schema), "<", iv}
+	}
+}
+
+// InetContainedBy returns a condition that will be true when the address or
+// network in `col` is strictly contained by the given network.
+func InetContainedBy(col SchemaField, network net.IPNet) Condition {
+	return func(schema Schema) ToSqler {
+		return &colOp{col.QualifiedName(schema), "<<", types.IPNet(network)}
+	}
+}
+
+// InetContainedByOrEq returns a condition that will be true when the address
+// or network in `col` is contained by or equal to the given network.
+func InetContainedByOrEq(col SchemaField, network net.IPNet) Condition {
+	return func(schema Schema) ToSqler {
+		return &colOp{col.QualifiedName(schema), "<<=", types.IPNet(network)}
+	}
+}
+
+// InetContains returns a condition that will be true when the network in
+// `col` strictly contains the given address.
+func InetContains(col SchemaField, ip net.IP) Condition {
+	return func(schema Schema) ToSqler {
+		return &colOp{col.QualifiedName(schema), ">>", types.IP(ip)}
+	}
+}
+
+// InetContainsOrEq returns a condition that will be true when the network in
+// `col` contains or is equal to the given address.
+func InetContainsOrEq(col SchemaField, ip net.IP) Condition {
+	return func(schema Schema) ToSqler {
+		return &colOp{col.QualifiedName(schema), ">>=", types.IP(ip)}
+	}
+}
+
+// GeoWithin returns a condition that will be true when the geometry in `col`
+// is completely inside the given geometry, which can be either a Point or a
+// Geometry.
+func GeoWithin(col SchemaField, geom driver.Valuer) Condition {
+	return func(schema Schema) ToSqler {
+		return &geoOp{"ST_Within", col.QualifiedName(schema), geom, nil}
+	}
+}
+
+// GeoContains returns a condition that will be true when the geometry in
+// `col` completely contains the given geometry, which can be either a Point
+// or a Geometry.
+func GeoContains(col SchemaField, geom driver.Valuer) Condition {
+	return func(schema Schema) ToSqler {
+		return &geoOp{"ST_Contains", col.QualifiedName(schema), geom, nil}
+	}
+}
+
+// GeoIntersects returns a condition that will be true when the geometry in
+// `col` shares any portion of space with the given geometry, which can be
+// either a Point or a Geometry.
+func GeoIntersects(col SchemaField, geom driver.Valuer) Condition {
+	return func(schema Schema) ToSqler {
+		return &geoOp{"ST_Intersects", col.QualifiedName(schema), geom, nil}
+	}
+}
+
+// GeoDWithin returns a condition that will be true when the geometry in
+// `col` is within the given distance of the given geometry, which can be
+// either a Point or a Geometry. The distance is expressed in the units of the
+// spatial reference system of the geometries.
+func GeoDWithin(col SchemaField, geom driver.Valuer, distance float64) Condition {
+	return func(schema Schema) ToSqler {
+		return &geoOp{"ST_DWithin", col.QualifiedName(schema), geom, []interface{}{distance}}
+	}
+}
+
+// RangeContains returns a condition that will be true when the range in
+// `col` contains the given range.
+func RangeContains(col SchemaField, r driver.Valuer) Condition {
+	return func(schema Schema) ToSqler {
+		return &colOp{col.QualifiedName(schema), "@>", r}
+	}
+}
+
+// RangeContainsElem returns a condition that will be true when the range in
+// `col` contains the given element. The element must be of the same type as
+// the elements of the range: int32 for Int4Range, int64 for Int8Range,
+// float64 for NumRange and time.Time for TstzRange.
+func RangeContainsElem(col SchemaField, elem interface{}) Condition {
+	return func(schema Schema) ToSqler {
+		typ, ok := rangeElemTypes[reflect.TypeOf(elem)]
+		if !ok {
+			return &errOp{fmt.Sprintf("kallax: invalid range element type %T", elem)}
+		}
+
+		return newCustomOp(
+			fmt.Sprintf(":col: @> CAST(:arg: AS %s)", typ),
+			col.QualifiedName(schema),
+			[]interface{}{elem},
+			false,
+		)
+	}
+}
+
+// RangeContainedBy returns a condition that will be true when the range in
+// `col` is contained by the given range.
+func RangeContainedBy(col SchemaField, r driver.Valuer) Condition {
+	return func(schema Schema) ToSqler {
+		return &colOp{col.QualifiedName(schema), "<@", r}
+	}
+}
+
+// RangeOverlaps returns a condition that will be true when the range in `col`
+// has any element in common with the given range.
+func RangeOverlaps(col SchemaField, r driver.Valuer) Condition {
+	return func(schema Schema) ToSqler {
+		return &colOp{col.QualifiedName(schema), "&&", r}
+	}
+}
+
+// RangeStrictlyLeft returns a condition that will be true when all the
+// elements of the range in `col` are lower than the elements of the given
+// range.
+func RangeStrictlyLeft(col SchemaField, r driver.Valuer) Condition {
+	return func(schema Schema) ToSqler {
+		return &colOp{col.QualifiedName(schema), "<<", r}
+	}
+}
+
+// RangeStrictlyRight returns a condition that will be true when all the
+// elements of the range in `col` are greater than the elements of the given
+// range.
+func RangeStrictlyRight(col SchemaField, r driver.Valuer) Condition {
+	return func(schema Schema) ToSqler {
+		return &colOp{col.QualifiedName(schema), ">>", r}
+	}
+}
+
+// RangeAdjacent returns a condition that will be true when the range in
+// `col` and the given range are adjacent, that is, they do not overlap and
+// there is no gap between them.
+func RangeAdjacent(col SchemaField, r driver.Valuer) Condition {
+	return func(schema Schema) ToSqler {
+		return &colOp{col.QualifiedName(schema), "-|-", r}
+	}
+}
+
+var rangeElemTypes = map[reflect.Type]string{
+	reflect.TypeOf(int32(0)):    "integer",
+	reflect.TypeOf(int64(0)):    "bigint",
+	reflect.TypeOf(float64(0)):  "numeric",
+	reflect.TypeOf(time.Time{}): "timestamptz",
+}
+
+// MoneyEq returns a condition that will be true when the money in `col` has
+// the same currency and amount as the given money.
+func MoneyEq(col SchemaField, m Money) Condition {
+	return moneyCond(col, "=", m)
+}
+
+// MoneyLt returns a condition that will be true when the money in `col` has
+// the same currency and a lower amount than the given money.
+func MoneyLt(col SchemaField, m Money) Condition {
+	return moneyCond(col, "<", m)
+}
+
+// MoneyGt returns a condition that will be true when the money in `col` has
+// the same currency and a greater amount than the given money.
+func MoneyGt(col SchemaField, m Money) Condition {
+	return moneyCond(col, ">", m)
+}
+
+// MoneyLtOrEq returns a condition that will be true when the money in `col`
+// has the same currency and a lower or equal amount than the given money.
+func MoneyLtOrEq(col SchemaField, m Money) Condition {
+	return moneyCond(col, "<=", m)
+}
+
+// MoneyGtOrEq returns a condition that will be true when the money in `col`
+// has the same currency and a greater or equal amount than the given money.
+func MoneyGtOrEq(col SchemaField, m Money) Condition {
+	return moneyCond(col, ">=", m)
+}
+
+func moneyCond(col SchemaField, op string, m Money) Condition {
+	return func(schema Schema) ToSqler {
+		return &moneyOp{col.QualifiedName(schema), op, m}
+	}
+}
+
+// BitsAll returns a condition that will be true when all the bits set in the
+// given mask are also set in the bit string in `col`. The mask must have the
+// same length as the bit strings in the column.
+func BitsAll(col SchemaField, mask BitString) Condition {
+	return bitsCond(col, "=", mask, mask)
+}
+
+// BitsAny returns a condition that will be true when any of the bits set in
+// the given mask is also set in the bit string in `col`. The mask must have
+// the same length as the bit strings in the column.
+func BitsAny(col SchemaField, mask BitString) Condition {
+	return bitsCond(col, "<>", mask, NewBitString(mask.Len()))
+}
+
+// BitsNone returns a condition that will be true when none of the bits set in
+// the given mask is set in the bit string in `col`. The mask must have the
+// same length as the bit strings in the column.
+func BitsNone(col SchemaField, mask BitString) Condition {
+	return bitsCond(col, "=", mask, NewBitString(mask.Len()))
+}
+
+// BitSet returns a condition that will be true when the bit at the given
+// position, starting at 0, is set in the bit string in `col`.
+func BitSet(col SchemaField, i int) Condition {
+	return func(schema Schema) ToSqler {
+		return newCustomOp("get_bit(:col:, :arg:) = 1", col.QualifiedName(schema), []interface{}{i}, false)
+	}
+}
+
+func bitsCond(col SchemaField, op string, mask, result BitString) Condition {
+	return func(schema Schema) ToSqler {
+		return &bitsOp{col.QualifiedName(schema), op, mask, result}
+	}
+}
+
+// LTreeAncestorOf returns a condition that will be true when the path in `col`
+// is an ancestor of the given path or is equal to it.
+func LTreeAncestorOf(col SchemaField, path LTree) Condition {
+	return func(schema Schema) ToSqler {
+		return newCustomOp(":col: @> CAST(:arg: AS ltree)", col.QualifiedName(schema), []interface{}{path}, false)
+	}
+}
+
+// LTreeDescendantOf returns a condition that will be true when the path in
+// `col` is a descendant of the given path or is equal to it.
+func LTreeDescendantOf(col SchemaField, path LTree) Condition {
+	return func(schema Schema) ToSqler {
+		return newCustomOp(":col: <@ CAST(:arg: AS ltree)", col.QualifiedName(schema), []interface{}{path}, false)
+	}
+}
+
+// LTreeMatch returns a condition that will be true when the path in `col`
+// matches the given lquery pattern, e.g. "*.Science.*{1}".
+func LTreeMatch(col SchemaField, lquery string) Condition {
+	return func(schema Schema) ToSqler {
+		return newCustomOp(":col: ~ CAST(:arg: AS lquery)", col.QualifiedName(schema), []interface{}{lquery}, false)
+	}
+}
+
+// XPathExists returns a condition that will be true when the given XPath 1.0
+// expression returns any node on the XML in `col`.
+func XPathExists(col SchemaField, xpath string) Condition {
+	return func(schema Schema) ToSqler {
+		return newCustomOp("xpath_exists(:arg:, :col:)", col.QualifiedName(schema), []interface{}{xpath}, false)
+	}
+}
+
 // MatchRegexCase returns a condition that will be true when `col` matches
 // the given POSIX regex. Match is case sensitive.
 func MatchRegexCase(col SchemaField, pattern string) Condition {
@@ -400,6 +802,51 @@ type (
 		col    string
 		values []interface{}
 	}
+
+	geoOp struct {
+		fn    string
+		col   string
+		value driver.Valuer
+		extra []interface{}
+	}
+
+	moneyOp struct {
+		col   string
+		op    string
+		value Money
+	}
+
+	bitsOp struct {
+		col    string
+		op     string
+		mask   BitString
+		result BitString
+	}
+
+	elapsedOp struct {
+		from  string
+		to    string
+		op    string
+		value Interval
+	}
+
+	subqueryOp struct {
+		col string
+		op  string
+		q   Query
+	}
+
+	colColOp struct {
+		col   string
+		op    string
+		other string
+	}
+
+	anyOp struct {
+		col   string
+		op    string
+		value interface{}
+	}
 )
 
 func (n not) ToSql() (string, []interface{}, error) {
@@ -415,6 +862,43 @@ func (o colOp) ToSql() (string, []interface{}, error) {
 	return fmt.Sprintf("%s %s ?", o.col, o.op), []interface{}{o.value}, nil
 }
 
+func (o anyOp) ToSql() (string, []interface{}, error) {
+	return fmt.Sprintf("? %s ANY(%s)", o.op, o.col), []interface{}{o.value}, nil
+}
+
+func (o colColOp) ToSql() (string, []interface{}, error) {
+	return fmt.Sprintf("%s %s %s", o.col, o.op, o.other), nil, nil
+}
+
+func (o subqueryOp) ToSql() (string, []interface{}, error) {
+	columns, b := o.q.compile()
+	if o.col == "" {
+		b = builder.Set(b, "Columns", nil).(squirrel.SelectBuilder).Column("1")
+	} else if len(columns) != 1 {
+		return "", nil, fmt.Errorf("kallax: the query used in %s must select a single column, but it selects %d columns", o.op, len(columns))
+	}
+
+	if offset := o.q.GetOffset(); offset > 0 {
+		b = b.Offset(offset)
+	}
+
+	if limit := o.q.GetLimit(); limit > 0 {
+		b = b.Limit(limit)
+	}
+
+	// The placeholders are replaced when the query the condition is used in
+	// is built.
+	sql, args, err := b.PlaceholderFormat(squirrel.Question).ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if o.col == "" {
+		return fmt.Sprintf("%s (%s)", o.op, sql), args, nil
+	}
+	return fmt.Sprintf("%s %s (%s)", o.col, o.op, sql), args, nil
+}
+
 func (o colUnaryOp) ToSql() (string, []interface{}, error) {
 	return fmt.Sprintf("%s %s", o.col, o.op), nil, nil
 }
@@ -437,6 +921,46 @@ func (o containsAny) ToSql() (string, []interface{}, error) {
 	), args, nil
 }
 
+func (o elapsedOp) ToSql() (string, []interface{}, error) {
+	return fmt.Sprintf(
+		"(%s - %s) %s CAST(? AS interval)",
+		o.to,
+		o.from,
+		o.op,
+	), []interface{}{o.value}, nil
+}
+
+func (o geoOp) ToSql() (string, []interface{}, error) {
+	var extra string
+	for range o.extra {
+		extra += ", ?"
+	}
+
+	return fmt.Sprintf(
+		"%s(%s, CAST(? AS geometry)%s)",
+		o.fn,
+		o.col,
+		extra,
+	), append([]interface{}{o.value}, o.extra...), nil
+}
+
+func (o moneyOp) ToSql() (string, []interface{}, error) {
+	return fmt.Sprintf(
+		"((%s).currency = ? AND (%s).amount %s CAST(? AS numeric))",
+		o.col,
+		o.col,
+		o.op,
+	), []interface{}{o.value.Currency, o.value.Decimal()}, nil
+}
+
+func (o bitsOp) ToSql() (string, []interface{}, error) {
+	return fmt.Sprintf(
+		"(%s & CAST(? AS varbit)) %s CAST(? AS varbit)",
+		o.col,
+		o.op,
+	), []interface{}{o.mask, o.result}, nil
+}
+
 func condsToSqlizers(conds []Condition, schema Schema) []squirrel.Sqlizer {
 	var result = make([]squirrel.Sqlizer, len(conds))
 	for i, v := range conds {
@@ -2,8 +2,11 @@ package kallax
 
 import (
 	"database/sql"
+	"net"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"gopkg.in/src-d/go-kallax.v1/types"
 )
@@ -43,6 +46,10 @@ func (s *OpsSuite) TestOperators() {
 	customGt := NewOperator(":col: > :arg:")
 	customIn := NewMultiOperator(":col: IN :arg:")
 
+	adults := NewBaseQuery(ModelSchema)
+	adults.Select(f("name"))
+	adults.Where(Gt(f("age"), 1))
+
 	cases := []struct {
 		name  string
 		cond  Condition
@@ -53,6 +60,8 @@ func (s *OpsSuite) TestOperators() {
 		{"customGt", customGt(f("age"), 1), 2},
 		{"Lt", Lt(f("age"), 2), 1},
 		{"Neq", Neq(f("name"), "Joe"), 2},
+		{"IsNull", IsNull(f("name")), 0},
+		{"IsNotNull", IsNotNull(f("name")), 3},
 		{"Like upper", Like(f("name"), "J%"), 2},
 		{"Like lower", Like(f("name"), "j%"), 0},
 		{"Ilike upper", Ilike(f("name"), "J%"), 2},
@@ -67,6 +76,9 @@ func (s *OpsSuite) TestOperators() {
 		{"In", In(f("name"), "Joe", "Jane"), 2},
 		{"customIn", customIn(f("name"), "Joe", "Jane"), 2},
 		{"NotIn", NotIn(f("name"), "Joe", "Jane"), 1},
+		{"In query", In(f("name"), adults), 2},
+		{"NotIn query", NotIn(f("name"), adults), 1},
+		{"Exists", Exists(adults), 3},
 		{"MatchRegexCase upper", MatchRegexCase(f("name"), "J.*"), 2},
 		{"MatchRegexCase lower", MatchRegexCase(f("name"), "j.*"), 0},
 		{"MatchRegex upper", MatchRegex(f("name"), "J.*"), 2},
@@ -125,6 +137,8 @@ func (s *OpsSuite) TestArrayOperators() {
 		{"ArrayContainedBy fail", ArrayContainedBy(f, 1, 2, 5, 6), false},
 		{"ArrayOverlap", ArrayOverlap(f, 5, 1, 7), true},
 		{"ArrayOverlap fail", ArrayOverlap(f, 6, 7, 8, 9), false},
+		{"AnyEquals", AnyEquals(f, 2), true},
+		{"AnyEquals fail", AnyEquals(f, 4), false},
 	}
 
 	_, err := s.db.Exec("INSERT INTO slices (id,elems) VALUES ($1, $2)", NewULID(), types.Slice([]int64{1, 2, 3}))
@@ -174,6 +188,11 @@ func (s *OpsSuite) TestJSONOperators() {
 			object{"a": 1},
 			object{"a": true},
 		), 2},
+		{"JSONHasKey with array match", JSONHasKey(f, "a"), 3},
+		{"JSONHasKey", JSONHasKey(f, "b"), 2},
+		{"JSONArrayLength", Eq(JSONArrayLength(f), 3), 3},
+		{"JSONArrayLength of key", Eq(JSONArrayLength(NewJSONSchemaKey(JSONAny, "elem", "b")), 3), 1},
+		{"AtJSONPath", Eq(AtJSONPath(f, JSONText, "c", "d"), "foo"), 1},
 	}
 
 	var records = []interface{}{
@@ -206,10 +225,249 @@ func (s *OpsSuite) TestJSONOperators() {
 	}
 }
 
+func (s *OpsSuite) TestHStoreOperators() {
+	s.create(`CREATE EXTENSION IF NOT EXISTS hstore`)
+	s.create(`CREATE TABLE hstores (
+		id uuid primary key,
+		elem hstore
+	)`)
+	defer s.remove("hstores")
+
+	str := func(s string) *string { return &s }
+
+	f := f("elem")
+	cases := []struct {
+		name string
+		cond Condition
+		n    int64
+	}{
+		{"HStoreHasKey", HStoreHasKey(f, "a"), 2},
+		{"HStoreHasKey fail", HStoreHasKey(f, "z"), 0},
+		{"HStoreHasAnyKey", HStoreHasAnyKey(f, "b", "c"), 2},
+		{"HStoreHasAllKeys", HStoreHasAllKeys(f, "a", "b"), 1},
+		{"HStoreContains", HStoreContains(f, HStore{"a": str("1")}), 1},
+		{"HStoreContainedBy", HStoreContainedBy(f, HStore{
+			"a": str("1"),
+			"b": str("2"),
+		}), 1},
+	}
+
+	var records = []HStore{
+		{"a": str("1"), "b": str("2")},
+		{"a": str("2"), "c": nil},
+		{"d": str("4")},
+	}
+
+	for _, r := range records {
+		_, err := s.db.Exec("INSERT INTO hstores (id,elem) VALUES ($1, $2)", NewULID(), r)
+		s.NoError(err)
+	}
+
+	for _, c := range cases {
+		q := NewBaseQuery(HStoresSchema)
+		q.Where(c.cond)
+		cnt, err := s.store.Count(q)
+		s.NoError(err, c.name)
+		s.Equal(c.n, cnt, "should retrieve %d records: %s", c.n, c.name)
+	}
+}
+
+func (s *OpsSuite) TestIntervalOperators() {
+	s.create(`CREATE TABLE intervals (
+		id uuid primary key,
+		started_at timestamptz,
+		finished_at timestamptz
+	)`)
+	defer s.remove("intervals")
+
+	started, finished := f("started_at"), f("finished_at")
+	cases := []struct {
+		name string
+		cond Condition
+		n    int64
+	}{
+		{"OlderThan", OlderThan(started, NewInterval(time.Hour)), 1},
+		{"NewerThan", NewerThan(started, Interval{Days: 1}), 1},
+		{"ElapsedGt", ElapsedGt(started, finished, NewInterval(time.Minute)), 1},
+		{"ElapsedLt", ElapsedLt(started, finished, NewInterval(time.Minute)), 1},
+	}
+
+	now := time.Now()
+	var records = [][]time.Time{
+		{now.Add(-time.Minute), now},
+		{now.Add(-48 * time.Hour), now.Add(-48*time.Hour + time.Second)},
+	}
+
+	for _, r := range records {
+		_, err := s.db.Exec("INSERT INTO intervals (id,started_at,finished_at) VALUES ($1, $2, $3)", NewULID(), r[0], r[1])
+		s.NoError(err)
+	}
+
+	for _, c := range cases {
+		q := NewBaseQuery(IntervalsSchema)
+		q.Where(c.cond)
+		cnt, err := s.store.Count(q)
+		s.NoError(err, c.name)
+		s.Equal(c.n, cnt, "should retrieve %d records: %s", c.n, c.name)
+	}
+}
+
+func (s *OpsSuite) TestInetOperators() {
+	s.create(`CREATE TABLE inets (
+		id uuid primary key,
+		elem inet
+	)`)
+	defer s.remove("inets")
+
+	cidr := func(s string) net.IPNet {
+		_, n, _ := net.ParseCIDR(s)
+		return *n
+	}
+
+	f := f("elem")
+	cases := []struct {
+		name string
+		cond Condition
+		n    int64
+	}{
+		{"InetContainedBy", InetContainedBy(f, cidr("10.0.0.0/8")), 2},
+		{"InetContainedBy fail", InetContainedBy(f, cidr("172.16.0.0/12")), 0},
+		{"InetContainedByOrEq", InetContainedByOrEq(f, cidr("10.1.0.0/16")), 2},
+		{"InetContains", InetContains(f, net.ParseIP("10.1.2.3")), 1},
+		{"InetContainsOrEq", InetContainsOrEq(f, net.ParseIP("10.2.0.1")), 1},
+	}
+
+	for _, r := range []string{"10.1.0.0/16", "10.2.0.1", "192.168.1.1"} {
+		_, err := s.db.Exec("INSERT INTO inets (id,elem) VALUES ($1, $2)", NewULID(), r)
+		s.NoError(err)
+	}
+
+	for _, c := range cases {
+		q := NewBaseQuery(InetsSchema)
+		q.Where(c.cond)
+		cnt, err := s.store.Count(q)
+		s.NoError(err, c.name)
+		s.Equal(c.n, cnt, "should retrieve %d records: %s", c.n, c.name)
+	}
+}
+
+func (s *OpsSuite) TestMoneyOperators() {
+	s.create(`DO $$ BEGIN CREATE TYPE kallax_money AS (amount numeric, currency char(3)); EXCEPTION WHEN duplicate_object THEN null; END $$`)
+	s.create(`CREATE TABLE moneys (
+		id uuid primary key,
+		elem kallax_money
+	)`)
+	defer s.remove("moneys")
+
+	f := f("elem")
+	cases := []struct {
+		name string
+		cond Condition
+		n    int64
+	}{
+		{"MoneyEq", MoneyEq(f, NewMoney(1050, "USD")), 1},
+		{"MoneyEq other currency", MoneyEq(f, NewMoney(1050, "EUR")), 0},
+		{"MoneyGt", MoneyGt(f, NewMoney(1050, "USD")), 1},
+		{"MoneyGtOrEq", MoneyGtOrEq(f, NewMoney(1050, "USD")), 2},
+		{"MoneyLt", MoneyLt(f, NewMoney(2000, "USD")), 1},
+		{"MoneyLtOrEq", MoneyLtOrEq(f, NewMoney(2000, "JPY")), 1},
+	}
+
+	for _, m := range []Money{NewMoney(1050, "USD"), NewMoney(1999, "USD"), NewMoney(1999, "JPY")} {
+		_, err := s.db.Exec("INSERT INTO moneys (id,elem) VALUES ($1, $2)", NewULID(), m)
+		s.NoError(err)
+	}
+
+	for _, c := range cases {
+		q := NewBaseQuery(MoneysSchema)
+		q.Where(c.cond)
+		cnt, err := s.store.Count(q)
+		s.NoError(err, c.name)
+		s.Equal(c.n, cnt, "should retrieve %d records: %s", c.n, c.name)
+	}
+}
+
+func (s *OpsSuite) TestRangeOperators() {
+	s.create(`CREATE TABLE ranges (
+		id uuid primary key,
+		elem int4range
+	)`)
+	defer s.remove("ranges")
+
+	f := f("elem")
+	cases := []struct {
+		name string
+		cond Condition
+		n    int64
+	}{
+		{"RangeContains", RangeContains(f, NewInt4Range(2, 4, "[)")), 1},
+		{"RangeContainsElem", RangeContainsElem(f, int32(10)), 2},
+		{"RangeContainedBy", RangeContainedBy(f, NewInt4Range(0, 20, "[]")), 2},
+		{"RangeOverlaps", RangeOverlaps(f, NewInt4Range(4, 6, "[)")), 2},
+		{"RangeStrictlyLeft", RangeStrictlyLeft(f, NewInt4Range(10, 20, "[)")), 1},
+		{"RangeStrictlyRight", RangeStrictlyRight(f, NewInt4Range(0, 5, "[)")), 2},
+		{"RangeAdjacent", RangeAdjacent(f, NewInt4Range(5, 8, "[)")), 1},
+	}
+
+	for _, r := range []Int4Range{
+		NewInt4Range(1, 5, "[)"),
+		NewInt4Range(5, 15, "[)"),
+		{RangeBounds: RangeBounds{LowerInclusive: true, UpperInfinite: true}, Lower: 10},
+	} {
+		_, err := s.db.Exec("INSERT INTO ranges (id,elem) VALUES ($1, $2)", NewULID(), r)
+		s.NoError(err)
+	}
+
+	for _, c := range cases {
+		q := NewBaseQuery(RangesSchema)
+		q.Where(c.cond)
+		cnt, err := s.store.Count(q)
+		s.NoError(err, c.name)
+		s.Equal(c.n, cnt, "should retrieve %d records: %s", c.n, c.name)
+	}
+}
+
 func TestOperators(t *testing.T) {
 	suite.Run(t, new(OpsSuite))
 }
 
+func TestAnyEquals(t *testing.T) {
+	sql, args, err := AnyEquals(f("elems"), 2)(SlicesSchema).ToSql()
+	require.NoError(t, err)
+	require.Equal(t, "? = ANY(_sl.elems)", sql)
+	require.Equal(t, []interface{}{2}, args)
+}
+
+func TestSubqueryOperators(t *testing.T) {
+	r := require.New(t)
+	rels := NewBaseQuery(RelSchema)
+	rels.Select(f("model_id"))
+	rels.Where(Eq(f("foo"), "bar"))
+	rels.Limit(5)
+
+	sql, args, err := In(f("id"), rels)(ModelSchema).ToSql()
+	r.NoError(err)
+	r.Equal("__model.id IN (SELECT __rel.model_id FROM rel __rel WHERE __rel.foo = ? LIMIT 5)", sql)
+	r.Equal([]interface{}{"bar"}, args)
+
+	correlated := NewBaseQuery(RelSchema)
+	correlated.Where(EqOuter(f("model_id"), ModelSchema, f("id")))
+	correlated.Where(Eq(f("foo"), "baz"))
+
+	q := NewBaseQuery(ModelSchema)
+	q.Select(f("id"))
+	q.Where(Eq(f("name"), "foo"))
+	q.Where(NotExists(correlated))
+	_, builder := q.compile()
+	sql, args, err = builder.ToSql()
+	r.NoError(err)
+	r.Equal("SELECT __model.id FROM model __model WHERE __model.name = $1 AND NOT EXISTS (SELECT 1 FROM rel __rel WHERE __rel.model_id = __model.id AND __rel.foo = $2)", sql)
+	r.Equal([]interface{}{"foo", "baz"}, args)
+
+	_, _, err = NotIn(f("id"), NewBaseQuery(RelSchema))(ModelSchema).ToSql()
+	r.EqualError(err, "kallax: the query used in NOT IN must select a single column, but it selects 3 columns")
+}
+
 var SlicesSchema = &BaseSchema{
 	alias: "_sl",
 	table: "slices",
@@ -229,3 +487,54 @@ var JsonsSchema = &BaseSchema{
 		f("elem"),
 	},
 }
+
+var HStoresSchema = &BaseSchema{
+	alias: "_hs",
+	table: "hstores",
+	id:    f("id"),
+	columns: []SchemaField{
+		f("id"),
+		f("elem"),
+	},
+}
+
+var IntervalsSchema = &BaseSchema{
+	alias: "_iv",
+	table: "intervals",
+	id:    f("id"),
+	columns: []SchemaField{
+		f("id"),
+		f("started_at"),
+		f("finished_at"),
+	},
+}
+
+var InetsSchema = &BaseSchema{
+	alias: "_in",
+	table: "inets",
+	id:    f("id"),
+	columns: []SchemaField{
+		f("id"),
+		f("elem"),
+	},
+}
+
+var MoneysSchema = &BaseSchema{
+	alias: "_mo",
+	table: "moneys",
+	id:    f("id"),
+	columns: []SchemaField{
+		f("id"),
+		f("elem"),
+	},
+}
+
+var RangesSchema = &BaseSchema{
+	alias: "_ra",
+	table: "ranges",
+	id:    f("id"),
+	columns: []SchemaField{
+		f("id"),
+		f("elem"),
+	},
+}
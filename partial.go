@@ -0,0 +1,68 @@
+package kallax
+
+import "errors"
+
+// ErrNotFetched is returned when the columns of a partial record that were
+// not retrieved from the database are updated.
+var ErrNotFetched = errors.New("kallax: cannot update columns that were not retrieved from the database, reload the record first")
+
+// fetchTracker is implemented by the records that keep the columns retrieved
+// for them by partial queries, which all the records embedding Model do.
+type fetchTracker interface {
+	fetchedColumns() []string
+	setFetched(cols []string)
+}
+
+// setFetched keeps the given columns as the ones retrieved for the record if
+// they are not all the columns of its schema.
+func setFetched(record Record, cols []string, partial bool) {
+	t, ok := record.(fetchTracker)
+	if !ok {
+		return
+	}
+
+	if partial {
+		t.setFetched(append([]string(nil), cols...))
+	} else {
+		t.setFetched(nil)
+	}
+}
+
+// updatedColumns returns the columns of the record updated with the given
+// ones, which are all the columns of the schema if none are given. Only the
+// retrieved columns of partial records are updated, and ErrNotFetched is
+// returned if any of the given ones, or the version of the optimistic lock,
+// was not retrieved.
+func updatedColumns(schema Schema, record Record, cols []SchemaField) ([]SchemaField, error) {
+	t, ok := record.(fetchTracker)
+	if !ok || t.fetchedColumns() == nil {
+		if len(cols) == 0 {
+			return schema.Columns(), nil
+		}
+		return cols, nil
+	}
+
+	fetched := make(map[string]bool)
+	for _, col := range t.fetchedColumns() {
+		fetched[col] = true
+	}
+
+	if len(cols) == 0 {
+		for _, col := range schema.Columns() {
+			if fetched[col.String()] {
+				cols = append(cols, col)
+			}
+		}
+	}
+
+	if lock := schema.LockField(); lock != nil && !fetched[lock.String()] {
+		return nil, ErrNotFetched
+	}
+
+	for _, col := range cols {
+		if !fetched[col.String()] {
+			return nil, ErrNotFetched
+		}
+	}
+	return cols, nil
+}
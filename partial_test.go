@@ -0,0 +1,40 @@
+package kallax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateStatement_Partial(t *testing.T) {
+	r := require.New(t)
+	m := newModel("foo", "foo@bar.baz", 1)
+	m.ID = 1
+	setFetched(m, []string{"id", "name"}, true)
+	r.True(m.IsPartial())
+
+	query, args, err := UpdateStatement(ModelSchema, m)
+	r.NoError(err)
+	r.Equal("UPDATE model SET id=$1,name=$2 WHERE id=$3", query)
+	r.Equal([]interface{}{int64(1), "foo"}, args[:2])
+
+	query, _, err = UpdateStatement(ModelSchema, m, f("name"))
+	r.NoError(err)
+	r.Equal("UPDATE model SET name=$1 WHERE id=$2", query)
+
+	_, _, err = UpdateStatement(ModelSchema, m, f("name"), f("age"))
+	r.Equal(ErrNotFetched, err)
+
+	schema := NewDynamicSchema("post", "id", true, "title", "version").
+		WithLock(f("version"))
+	record := NewDynamicRecord(schema)
+	setFetched(record, []string{"id", "title"}, true)
+	_, _, err = UpdateStatement(schema, record)
+	r.Equal(ErrNotFetched, err)
+
+	setFetched(m, []string{"id", "name"}, false)
+	r.False(m.IsPartial())
+	query, _, err = UpdateStatement(ModelSchema, m)
+	r.NoError(err)
+	r.Equal("UPDATE model SET id=$1,name=$2,email=$3,age=$4 WHERE id=$5", query)
+}
@@ -0,0 +1,326 @@
+package kallax
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// ErrCircuitOpen is returned instead of running a statement or transaction
+// when the circuit breaker of its operation policy is open.
+var ErrCircuitOpen = errors.New("kallax: circuit breaker is open, the operation was not run")
+
+// Policy is the resilience policy of a store, which controls the retries,
+// timeouts and circuit breaking of its statements and transactions. Each
+// class of operations has its own policy, so reads can be retried and
+// writes can be given longer timeouts, for example.
+type Policy struct {
+	// Reads is the policy of the queries, except the ones that insert,
+	// update or delete rows.
+	Reads OperationPolicy
+	// Writes is the policy of the statements that insert, update or delete
+	// rows, including the ones returning rows, and of the raw statements
+	// run with RawExec.
+	Writes OperationPolicy
+	// Transactions is the policy of the transactions run with Transaction.
+	// The retries of a transaction run its callback again in a new
+	// transaction.
+	Transactions OperationPolicy
+}
+
+// OperationPolicy is the resilience policy of a class of operations. Its
+// zero value runs the operations once, without a timeout, except for
+// transactions, which are retried as they are without a policy.
+type OperationPolicy struct {
+	// Attempts is the maximum number of times an operation is run while it
+	// fails with retryable errors. Statements are never retried inside a
+	// transaction, as the transaction is aborted by their error in most
+	// databases, but the transaction itself can be. If it is zero,
+	// statements are run once and transactions up to 10 times.
+	Attempts int
+	// Backoff returns the time to wait before the given attempt is run,
+	// starting from the second one. If it is nil, attempts are run right
+	// away. See ExponentialBackoff.
+	Backoff func(attempt int) time.Duration
+	// Retryable reports whether an operation failed with the given error
+	// can be run again. If it is nil, the operations failed with the
+	// retryable errors of the dialect of the store, if it is a Retrier,
+	// and with driver.ErrBadConn are retried.
+	Retryable func(error) bool
+	// Timeout is the maximum duration of an operation, after which its
+	// context is cancelled. For queries, it includes the time spent reading
+	// their rows. If it is zero, operations have no timeout.
+	Timeout time.Duration
+	// Breaker is the circuit breaker of the operations, which can be shared
+	// between classes of operations and stores. Only the errors that are
+	// retryable and the timeouts count as failures. If it is nil, there is
+	// no circuit breaking.
+	Breaker *CircuitBreaker
+	// OnRetry is called with the given attempt, starting from the second
+	// one, and the error of the previous one before an operation is
+	// retried, so the retries can be logged or measured.
+	OnRetry func(attempt int, err error)
+}
+
+// context returns a new context derived from the given one with the timeout
+// of the policy, if any.
+func (p *OperationPolicy) context(parent context.Context) (context.Context, context.CancelFunc) {
+	if p.Timeout <= 0 {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeout(parent, p.Timeout)
+}
+
+// retryable reports whether an operation failed with the given error can be
+// run again.
+func (p *OperationPolicy) retryable(dialect Dialect, err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+
+	if err == driver.ErrBadConn {
+		return true
+	}
+
+	retrier, ok := dialect.(Retrier)
+	return ok && retrier.Retryable(err)
+}
+
+// failed reports whether the given error, returned by an operation run with
+// the given context derived from the given parent, is a failure for the
+// circuit breaker. The errors of the operations whose parent context is done
+// are not, as they are not caused by the database.
+func (p *OperationPolicy) failed(parent, ctx context.Context, dialect Dialect, err error) bool {
+	if err == nil || parent.Err() != nil {
+		return false
+	}
+	return ctx.Err() == context.DeadlineExceeded || p.retryable(dialect, err)
+}
+
+// retry calls the retry hook of the policy, if any, before running the given
+// attempt of an operation failed with the given error.
+func (p *OperationPolicy) retry(attempt int, err error) {
+	if p.OnRetry != nil {
+		p.OnRetry(attempt, err)
+	}
+}
+
+// wait waits the backoff of the policy before running the given attempt, or
+// until the given context is done. It returns whether the attempt can be run.
+func (p *OperationPolicy) wait(ctx context.Context, attempt int) bool {
+	if p.Backoff == nil {
+		return ctx.Err() == nil
+	}
+
+	timer := time.NewTimer(p.Backoff(attempt))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// ExponentialBackoff returns a backoff that waits the given base duration
+// before the second attempt and doubles it for every following attempt, up
+// to the given maximum duration.
+func ExponentialBackoff(base, max time.Duration) func(attempt int) time.Duration {
+	return func(attempt int) time.Duration {
+		d := base
+		for i := 2; i < attempt && d < max; i++ {
+			d *= 2
+		}
+
+		if d > max {
+			return max
+		}
+		return d
+	}
+}
+
+// CircuitBreaker stops running operations after a number of consecutive
+// failures, so a database that is down or overloaded is not flooded with
+// operations that will fail anyway. Once open, it lets operations run again
+// after a cooldown; the first one to fail opens it again, and the first one
+// to succeed closes it. It is safe for concurrent use.
+type CircuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+}
+
+// NewCircuitBreaker returns a new circuit breaker that opens after the given
+// number of consecutive failures and stays open for the given cooldown.
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &CircuitBreaker{threshold: threshold, cooldown: cooldown}
+}
+
+// Open reports whether the circuit breaker is open, so operations are not
+// run.
+func (b *CircuitBreaker) Open() bool {
+	if b == nil {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.failures >= b.threshold && time.Since(b.openedAt) < b.cooldown
+}
+
+// record records the result of an operation.
+func (b *CircuitBreaker) record(failed bool) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !failed {
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openedAt = time.Now()
+	}
+}
+
+// WithPolicy returns a new store that runs its statements and transactions
+// with the given resilience policy, replacing the one of the store, if any.
+// Statements are classified as reads or writes by their keyword, so the
+// INSERT statements returning the generated IDs are writes.
+func (s *Store) WithPolicy(policy Policy) *Store {
+	store := s.clone()
+	store.policy = &policy
+	return store.init()
+}
+
+// transactionPolicy returns the policy of the transactions of the store.
+func (s *Store) transactionPolicy() OperationPolicy {
+	if s.policy == nil {
+		return OperationPolicy{}
+	}
+	return s.policy.Transactions
+}
+
+// policyRunner runs the statements with the policy of their class of
+// operations.
+type policyRunner struct {
+	squirrel.DBProxyContext
+	runner  contextRunner
+	policy  *Policy
+	dialect Dialect
+	inTx    bool
+}
+
+func newPolicyRunner(runner squirrel.DBProxyContext, policy *Policy, dialect Dialect, inTx bool) *policyRunner {
+	return &policyRunner{
+		DBProxyContext: runner,
+		runner:         runnerContext(runner),
+		policy:         policy,
+		dialect:        dialect,
+		inTx:           inTx,
+	}
+}
+
+// queryPolicy returns the policy of the given query.
+func (r *policyRunner) queryPolicy(query string) *OperationPolicy {
+	switch inspectStatement(query).Kind {
+	case "INSERT", "UPDATE", "DELETE", "UPSERT", "REPLACE", "MERGE", "TRUNCATE":
+		return &r.policy.Writes
+	}
+	return &r.policy.Reads
+}
+
+// run runs the given function with the given policy and a context derived
+// from the given one. Its context is cancelled once it returns, unless keep
+// is true and it succeeds, in which case the context is only cancelled by its
+// timeout or its parent.
+func (r *policyRunner) run(parent context.Context, p *OperationPolicy, keep bool, fn func(context.Context) error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if p.Breaker.Open() {
+			if err != nil {
+				return err
+			}
+			return ErrCircuitOpen
+		}
+
+		ctx, cancel := p.context(parent)
+		err = fn(ctx)
+		p.Breaker.record(p.failed(parent, ctx, r.dialect, err))
+		if err != nil || !keep {
+			cancel()
+		}
+
+		if err == nil || r.inTx || attempt >= p.Attempts || !p.retryable(r.dialect, err) {
+			return err
+		}
+
+		p.retry(attempt+1, err)
+		if !p.wait(parent, attempt+1) {
+			return err
+		}
+	}
+}
+
+func (r *policyRunner) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return r.ExecContext(context.Background(), query, args...)
+}
+
+func (r *policyRunner) ExecContext(ctx context.Context, query string, args ...interface{}) (result sql.Result, err error) {
+	err = r.run(ctx, &r.policy.Writes, false, func(ctx context.Context) error {
+		result, err = r.runner.ExecContext(ctx, query, args...)
+		return err
+	})
+	return result, err
+}
+
+func (r *policyRunner) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return r.QueryContext(context.Background(), query, args...)
+}
+
+func (r *policyRunner) QueryContext(ctx context.Context, query string, args ...interface{}) (rows *sql.Rows, err error) {
+	err = r.run(ctx, r.queryPolicy(query), true, func(ctx context.Context) error {
+		rows, err = r.runner.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// QueryRow returns a row that runs the query with its policy when it is
+// scanned, as the errors of the query are only returned by then.
+func (r *policyRunner) QueryRow(query string, args ...interface{}) squirrel.RowScanner {
+	return r.QueryRowContext(context.Background(), query, args...)
+}
+
+func (r *policyRunner) QueryRowContext(ctx context.Context, query string, args ...interface{}) squirrel.RowScanner {
+	return &policyRow{runner: r, ctx: ctx, query: query, args: args}
+}
+
+type policyRow struct {
+	runner *policyRunner
+	ctx    context.Context
+	query  string
+	args   []interface{}
+}
+
+func (row *policyRow) Scan(dest ...interface{}) error {
+	r := row.runner
+	return r.run(row.ctx, r.queryPolicy(row.query), false, func(ctx context.Context) error {
+		return r.runner.QueryRowContext(ctx, row.query, row.args...).Scan(dest...)
+	})
+}
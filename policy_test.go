@@ -0,0 +1,160 @@
+package kallax
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(10*time.Millisecond, 50*time.Millisecond)
+	require.Equal(t, 10*time.Millisecond, backoff(2))
+	require.Equal(t, 20*time.Millisecond, backoff(3))
+	require.Equal(t, 40*time.Millisecond, backoff(4))
+	require.Equal(t, 50*time.Millisecond, backoff(5))
+	require.Equal(t, 50*time.Millisecond, backoff(20))
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	r := require.New(t)
+	b := NewCircuitBreaker(2, time.Minute)
+	b.record(true)
+	r.False(b.Open())
+	b.record(false)
+	b.record(true)
+	r.False(b.Open())
+	b.record(true)
+	r.True(b.Open())
+
+	b.openedAt = time.Now().Add(-time.Minute)
+	r.False(b.Open())
+	b.record(true)
+	r.True(b.Open())
+	b.record(false)
+	r.False(b.Open())
+
+	var nilBreaker *CircuitBreaker
+	nilBreaker.record(true)
+	r.False(nilBreaker.Open())
+}
+
+func TestPolicyRunner(t *testing.T) {
+	r := require.New(t)
+	retryable := &pq.Error{Code: "40001"}
+	var waits []int
+	breaker := NewCircuitBreaker(4, time.Minute)
+	policy := &Policy{
+		Reads: OperationPolicy{
+			Attempts: 3,
+			Backoff: func(attempt int) time.Duration {
+				waits = append(waits, attempt)
+				return 0
+			},
+			Timeout: time.Minute,
+			Breaker: breaker,
+		},
+	}
+
+	inner := &failingRunner{errs: []error{retryable, retryable}}
+	runner := newPolicyRunner(inner, policy, CockroachDB, false)
+	_, err := runner.Query("SELECT * FROM model")
+	r.NoError(err)
+	r.Equal(3, inner.calls)
+	r.Equal([]int{2, 3}, waits)
+	r.True(inner.deadline)
+	r.False(breaker.Open())
+
+	inner = &failingRunner{errs: []error{retryable}}
+	runner = newPolicyRunner(inner, policy, CockroachDB, false)
+	var id int64
+	r.Equal(retryable, runner.QueryRow("INSERT INTO model (name) VALUES ($1) RETURNING id", "foo").Scan(&id))
+	r.Equal(1, inner.calls)
+	r.False(inner.deadline)
+	_, err = runner.Exec("DELETE FROM model WHERE id = $1", 1)
+	r.NoError(err)
+	r.Equal(2, inner.calls)
+
+	inner = &failingRunner{errs: []error{retryable}}
+	runner = newPolicyRunner(inner, policy, CockroachDB, true)
+	r.Equal(retryable, runner.QueryRow("SELECT COUNT(*) FROM model").Scan(&id))
+	r.Equal(1, inner.calls)
+
+	inner = &failingRunner{errs: []error{retryable, retryable, retryable}}
+	runner = newPolicyRunner(inner, policy, CockroachDB, false)
+	_, err = runner.Query("SELECT * FROM model")
+	r.Equal(retryable, err)
+	r.Equal(3, inner.calls)
+	r.True(breaker.Open())
+	_, err = runner.Query("SELECT * FROM model")
+	r.Equal(ErrCircuitOpen, err)
+	r.Equal(3, inner.calls)
+}
+
+func TestTransaction_Policy(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	retryable := &pq.Error{Code: "40001", Message: "restart transaction"}
+	commitErrors = []error{retryable, retryable, retryable}
+	defer func() { commitErrors = nil }()
+
+	var attempts int
+	store := NewStore(db).WithDialect(CockroachDB).WithPolicy(Policy{
+		Transactions: OperationPolicy{
+			Attempts: 2,
+			Timeout:  time.Minute,
+			Breaker:  NewCircuitBreaker(2, time.Minute),
+		},
+	})
+	err = store.Transaction(func(*Store) error {
+		attempts++
+		return nil
+	})
+	r.EqualError(err, "kallax: unable to commit transaction: pq: restart transaction (40001)")
+	r.Equal(2, attempts)
+
+	r.Equal(ErrCircuitOpen, store.Transaction(func(*Store) error {
+		attempts++
+		return nil
+	}))
+	r.Equal(2, attempts)
+}
+
+// failingRunner returns the given errors from its next statements.
+type failingRunner struct {
+	squirrel.DBProxyContext
+	errs     []error
+	calls    int
+	deadline bool
+}
+
+func (r *failingRunner) next(ctx context.Context) error {
+	r.calls++
+	_, r.deadline = ctx.Deadline()
+	if len(r.errs) == 0 {
+		return nil
+	}
+
+	err := r.errs[0]
+	r.errs = r.errs[1:]
+	return err
+}
+
+func (r *failingRunner) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return nil, r.next(ctx)
+}
+
+func (r *failingRunner) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, r.next(ctx)
+}
+
+func (r *failingRunner) QueryRowContext(ctx context.Context, query string, args ...interface{}) squirrel.RowScanner {
+	return errRow{r.next(ctx)}
+}
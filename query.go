@@ -9,8 +9,12 @@ import (
 
 var (
 	// ErrManyToManyNotSupported is returned when a many to many relationship
-	// is added to a query.
-	ErrManyToManyNotSupported = errors.New("kallax: many to many relationships are not supported")
+	// is added to a query and its foreign key has no join table.
+	ErrManyToManyNotSupported = errors.New("kallax: many to many relationships are only supported through a join table")
+	// ErrOneToOnePreload is returned when a one to one relationship is added
+	// to a query with relationships to preload, as they are joined instead of
+	// loaded in batches.
+	ErrOneToOnePreload = errors.New("kallax: the relationships of one to one relationships cannot be preloaded")
 )
 
 // Query is the common interface all queries must satisfy. The basic abilities
@@ -19,7 +23,11 @@ var (
 type Query interface {
 	compile() ([]string, squirrel.SelectBuilder)
 	getRelationships() []Relationship
-	isReadOnly() bool
+	getGroupBy() []SchemaField
+	isPartial() bool
+	readsPrimary() bool
+	pageQuery() (*BaseQuery, error)
+	selectRows(rows []mockRow, paginate bool) ([]mockRow, error)
 	// Schema returns the schema of the query model.
 	Schema() Schema
 	// GetOffset returns the number of skipped rows in the query.
@@ -27,7 +35,7 @@ type Query interface {
 	// GetLimit returns the max number of rows retrieved by the query.
 	GetLimit() uint64
 	// GetBatchSize returns the number of rows retrieved by the store per
-	// batch. This is only used and has effect on queries with 1:N
+	// batch. This is only used and has effect on queries with 1:N or N:M
 	// relationships.
 	GetBatchSize() uint64
 }
@@ -84,12 +92,35 @@ type BaseQuery struct {
 	relationColumns []string
 	relationships   []Relationship
 	builder         squirrel.SelectBuilder
+	// wheres are the conditions of the query, which are evaluated by the
+	// mock stores.
+	wheres []ToSqler
 
 	selectChanged bool
 	batchSize     uint64
 	offset        uint64
 	limit         uint64
-}
+	deleted       deletedRecords
+	orders        []ColumnOrder
+	cursor        *cursorBound
+	// groupBy are the columns the rows are grouped by, which are selected
+	// along with the aggregates by Store.Aggregate.
+	groupBy []SchemaField
+	// lock is the row-level lock taken on the retrieved rows, if any.
+	lock *rowLock
+	// primary reports whether the query is run in the primary database even
+	// if the store has replicas.
+	primary bool
+}
+
+// deletedRecords are the soft deleted records selected by a query.
+type deletedRecords int
+
+const (
+	excludeDeleted deletedRecords = iota
+	includeDeleted
+	onlyDeleted
+)
 
 // NewBaseQuery creates a new BaseQuery for querying the table of the given schema.
 func NewBaseQuery(schema Schema) *BaseQuery {
@@ -109,8 +140,10 @@ func (q *BaseQuery) Schema() Schema {
 	return q.schema
 }
 
-func (q *BaseQuery) isReadOnly() bool {
-	return q.selectChanged
+// isPartial reports whether the query does not select all the columns of
+// its schema.
+func (q *BaseQuery) isPartial() bool {
+	return q.selectChanged || len(q.excludedColumns) > 0
 }
 
 // Select adds the given columns to the list of selected columns in the query.
@@ -143,6 +176,13 @@ func (q *BaseQuery) Copy() *BaseQuery {
 		limit:           q.GetLimit(),
 		offset:          q.GetOffset(),
 		schema:          q.schema,
+		deleted:         q.deleted,
+		orders:          append([]ColumnOrder(nil), q.orders...),
+		cursor:          q.cursor,
+		wheres:          append([]ToSqler(nil), q.wheres...),
+		groupBy:         append([]SchemaField(nil), q.groupBy...),
+		lock:            q.lock,
+		primary:         q.primary,
 	}
 }
 
@@ -150,6 +190,10 @@ func (q *BaseQuery) getRelationships() []Relationship {
 	return q.relationships
 }
 
+func (q *BaseQuery) getGroupBy() []SchemaField {
+	return q.groupBy
+}
+
 func (q *BaseQuery) selectedColumns() []SchemaField {
 	var result = make([]SchemaField, 0, len(q.columns))
 	for _, col := range q.columns {
@@ -160,28 +204,76 @@ func (q *BaseQuery) selectedColumns() []SchemaField {
 	return result
 }
 
+// RelationOption is an option of a relationship added to a query. Conditions
+// are options that filter the related records, and Preload returns an option
+// that loads the relationships of the related records along with them.
+type RelationOption interface {
+	applyRelation(*Relationship)
+}
+
+func (c Condition) applyRelation(r *Relationship) {
+	if c == nil {
+		return
+	}
+
+	if r.Filter != nil {
+		r.Filter = And(r.Filter, c)
+	} else {
+		r.Filter = c
+	}
+}
+
+// Preload returns an option of a one to many or many to many relationship
+// added to a query that loads the relationships at the given dot-separated
+// paths of the related records along with them. For example, "Items.Product"
+// loads the items of each related record and the product of each item. Every
+// relationship in the paths is loaded with a single query for the records of
+// a whole batch, no matter its type.
+func Preload(paths ...string) RelationOption {
+	return preload(paths)
+}
+
+type preload []string
+
+func (p preload) applyRelation(r *Relationship) {
+	r.Preload = append(r.Preload, p...)
+}
+
 // AddRelation adds a relationship if the given to the query, which is present
-// in the given field of the query base schema. A condition to filter can also
-// be passed in the case of one to many relationships.
-func (q *BaseQuery) AddRelation(schema Schema, field string, typ RelationshipType, filter Condition) error {
-	if typ == ManyToMany {
+// in the given field of the query base schema. Conditions to filter and
+// relationships to preload can also be passed in the case of one to many and
+// many to many relationships.
+func (q *BaseQuery) AddRelation(schema Schema, field string, typ RelationshipType, opts ...RelationOption) error {
+	fk, ok := q.schema.ForeignKey(field)
+	if typ == ManyToMany && (!ok || fk.Through == nil) {
 		return ErrManyToManyNotSupported
 	}
 
-	fk, ok := q.schema.ForeignKey(field)
 	if !ok {
 		return fmt.Errorf(
 			"kallax: cannot find foreign key to join tables %s and %s",
 			q.schema.Table(), schema.Table(),
 		)
 	}
+	rel := Relationship{Type: typ, Field: field}
+	for _, opt := range opts {
+		if opt != nil {
+			opt.applyRelation(&rel)
+		}
+	}
+
+	if typ == OneToOne && len(rel.Preload) > 0 {
+		return ErrOneToOnePreload
+	}
+
 	schema = schema.WithAlias(field)
+	rel.Schema = schema
 
 	if typ == OneToOne {
 		q.join(schema, fk)
 	}
 
-	q.relationships = append(q.relationships, Relationship{typ, field, schema, filter})
+	q.relationships = append(q.relationships, rel)
 	return nil
 }
 
@@ -212,11 +304,7 @@ func (q *BaseQuery) join(schema Schema, fk *ForeignKey) {
 // Order adds the given order clauses to the list of columns to order the
 // results by.
 func (q *BaseQuery) Order(cols ...ColumnOrder) {
-	var c = make([]string, len(cols))
-	for i, v := range cols {
-		c[i] = v.ToSql(q.schema)
-	}
-	q.builder = q.builder.OrderBy(c...)
+	q.orders = append(q.orders, cols...)
 }
 
 // BatchSize sets the batch size.
@@ -225,7 +313,7 @@ func (q *BaseQuery) BatchSize(size uint64) {
 }
 
 // GetBatchSize returns the number of rows retrieved per batch while retrieving
-// 1:N relationships.
+// 1:N or N:M relationships.
 func (q *BaseQuery) GetBatchSize() uint64 {
 	return q.batchSize
 }
@@ -256,11 +344,78 @@ func (q *BaseQuery) GetOffset() uint64 {
 //   q.Where(Gt(AgeColumn, 18))
 //   // ... WHERE name = "foo" AND age > 18
 func (q *BaseQuery) Where(cond Condition) {
-	q.builder = q.builder.Where(cond(q.schema))
+	where := cond(q.schema)
+	q.wheres = append(q.wheres, where)
+	q.builder = q.builder.Where(where)
+}
+
+// GroupBy groups the rows retrieved by the query by the given columns. The
+// values of the columns and of aggregates of each group are retrieved with
+// Store.Aggregate.
+//   q.GroupBy(CountryColumn)
+//   // ... GROUP BY country
+func (q *BaseQuery) GroupBy(cols ...SchemaField) {
+	var qualified = make([]string, len(cols))
+	for i, col := range cols {
+		qualified[i] = col.QualifiedName(q.schema)
+	}
+
+	q.groupBy = append(q.groupBy, cols...)
+	q.builder = q.builder.GroupBy(qualified...)
+}
+
+// Having adds a new condition to filter the groups of the query. All
+// conditions added are concatenated with "and". Aggregates can be used in
+// the conditions as columns.
+//   q.Having(Gt(Sum(TotalColumn), 100))
+//   // ... HAVING SUM(total) > 100
+func (q *BaseQuery) Having(cond Condition) {
+	q.builder = q.builder.Having(cond(q.schema))
+}
+
+// Unscoped makes the query select the soft deleted records as well, if the
+// records of its schema are soft deleted.
+func (q *BaseQuery) Unscoped() {
+	q.deleted = includeDeleted
+}
+
+// OnlyDeleted makes the query select only the soft deleted records, if the
+// records of its schema are soft deleted.
+func (q *BaseQuery) OnlyDeleted() {
+	q.deleted = onlyDeleted
 }
 
 // compile returns the selected column names and the select builder.
 func (q *BaseQuery) compile() ([]string, squirrel.SelectBuilder) {
+	builder := q.builder
+	if col := q.schema.SoftDeleteField(); col != nil {
+		switch q.deleted {
+		case excludeDeleted:
+			builder = builder.Where(IsNull(col)(q.schema))
+		case onlyDeleted:
+			builder = builder.Where(IsNotNull(col)(q.schema))
+		}
+	}
+
+	if q.cursor != nil && q.cursor.cursor != "" {
+		builder = builder.Where(q.cursorCond()(q.schema))
+	}
+
+	if len(q.orders) > 0 {
+		var orders = make([]string, len(q.orders))
+		for i, o := range q.orders {
+			if q.cursor != nil && q.cursor.before {
+				o = reverseOrder(o)
+			}
+			orders[i] = o.ToSql(q.schema)
+		}
+		builder = builder.OrderBy(orders...)
+	}
+
+	if q.lock != nil {
+		builder = builder.Suffix(q.lock.clause(q.schema))
+	}
+
 	columns := q.selectedColumns()
 	var (
 		qualifiedColumns = make([]string, len(columns))
@@ -271,7 +426,7 @@ func (q *BaseQuery) compile() ([]string, squirrel.SelectBuilder) {
 		qualifiedColumns[i] = columns[i].QualifiedName(q.schema)
 		columnNames[i] = columns[i].String()
 	}
-	return columnNames, q.builder.Columns(
+	return columnNames, builder.Columns(
 		append(qualifiedColumns, q.relationColumns...)...,
 	)
 }
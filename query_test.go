@@ -77,6 +77,20 @@ func (s *QuerySuite) TestWhere() {
 	s.assertSql("SELECT __model.foo FROM model __model WHERE __model.foo = $1 AND __model.bar = $2")
 }
 
+func (s *QuerySuite) TestWhere_Nested() {
+	s.q.Select(f("foo"))
+	s.q.Where(Or(
+		And(Eq(f("foo"), 5), Not(IsNull(f("bar")))),
+		Not(Or(Gt(f("baz"), 1), Lt(f("baz"), -1))),
+	))
+	s.q.Where(Eq(f("qux"), "a"))
+
+	sql, args, err := s.q.ToSql()
+	s.NoError(err)
+	s.Equal("SELECT __model.foo FROM model __model WHERE ((__model.foo = $1 AND NOT (__model.bar IS NULL)) OR NOT ((__model.baz > $2 OR __model.baz < $3))) AND __model.qux = $4", sql)
+	s.Equal([]interface{}{5, 1, -1, "a"}, args)
+}
+
 func (s *QuerySuite) TestString() {
 	s.q.Select(f("foo"))
 	s.Equal("SELECT __model.foo FROM model __model", s.q.String())
@@ -107,6 +121,22 @@ func (s *QuerySuite) TestAddRelation_ManyToMany() {
 	s.Equal(ErrManyToManyNotSupported, err)
 }
 
+func (s *QuerySuite) TestAddRelation_JoinTable() {
+	s.Nil(s.q.AddRelation(RelSchema, "joined", ManyToMany, nil))
+	s.Equal("SELECT __model.id, __model.name, __model.email, __model.age FROM model __model", s.q.String())
+	s.Len(s.q.getRelationships(), 1)
+}
+
+func (s *QuerySuite) TestAddRelation_Preload() {
+	s.Nil(s.q.AddRelation(RelSchema, "rels", OneToMany, Eq(f("foo"), "bar"), nil, Preload("Items.Product", "Owner")))
+	rels := s.q.getRelationships()
+	s.Len(rels, 1)
+	s.NotNil(rels[0].Filter)
+	s.Equal([]string{"Items.Product", "Owner"}, rels[0].Preload)
+
+	s.Equal(ErrOneToOnePreload, s.q.AddRelation(RelSchema, "rel", OneToOne, Preload("Owner")))
+}
+
 func (s *QuerySuite) TestAddRelation_FKNotFound() {
 	s.Error(s.q.AddRelation(RelSchema, "fooo", OneToOne, nil))
 }
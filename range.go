@@ -0,0 +1,354 @@
+package kallax
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RangeBounds describes the bounds of a Postgres range.
+type RangeBounds struct {
+	// Empty reports whether the range contains no values. If it's true, the
+	// rest of the bounds and values of the range are ignored.
+	Empty bool
+	// LowerInclusive reports whether the lower bound is part of the range.
+	LowerInclusive bool
+	// UpperInclusive reports whether the upper bound is part of the range.
+	UpperInclusive bool
+	// LowerInfinite reports whether the range has no lower bound.
+	LowerInfinite bool
+	// UpperInfinite reports whether the range has no upper bound.
+	UpperInfinite bool
+}
+
+// NewRangeBounds returns the bounds described by the given string, which
+// uses the same notation as Postgres range constructors: "[)", "[]", "()"
+// or "(]". A square bracket means the bound is inclusive and a parenthesis
+// means it's exclusive. Any other string will result in the default "[)"
+// bounds.
+func NewRangeBounds(bounds string) RangeBounds {
+	b := RangeBounds{LowerInclusive: true}
+	if len(bounds) == 2 {
+		b.LowerInclusive = bounds[0] == '['
+		b.UpperInclusive = bounds[1] == ']'
+	}
+	return b
+}
+
+func (b RangeBounds) format(lower, upper string) string {
+	if b.Empty {
+		return "empty"
+	}
+
+	var buf bytes.Buffer
+	if b.LowerInclusive && !b.LowerInfinite {
+		buf.WriteRune('[')
+	} else {
+		buf.WriteRune('(')
+	}
+
+	if !b.LowerInfinite {
+		buf.WriteString(lower)
+	}
+	buf.WriteRune(',')
+	if !b.UpperInfinite {
+		buf.WriteString(upper)
+	}
+
+	if b.UpperInclusive && !b.UpperInfinite {
+		buf.WriteRune(']')
+	} else {
+		buf.WriteRune(')')
+	}
+	return buf.String()
+}
+
+// parseRange parses the text representation of a Postgres range and returns
+// its bounds and the unquoted lower and upper values.
+func parseRange(v interface{}, typ string) (b RangeBounds, lower, upper string, err error) {
+	var s string
+	switch t := v.(type) {
+	case []byte:
+		s = string(t)
+	case string:
+		s = t
+	default:
+		err = fmt.Errorf("kallax: cannot scan type %s into %s type", reflect.TypeOf(v), typ)
+		return
+	}
+
+	s = strings.TrimSpace(s)
+	if strings.ToLower(s) == "empty" {
+		b.Empty = true
+		return
+	}
+
+	if len(s) < 3 {
+		err = fmt.Errorf("kallax: invalid range %q", s)
+		return
+	}
+
+	switch s[0] {
+	case '[':
+		b.LowerInclusive = true
+	case '(':
+	default:
+		err = fmt.Errorf("kallax: invalid range %q: invalid lower bound", s)
+		return
+	}
+
+	switch s[len(s)-1] {
+	case ']':
+		b.UpperInclusive = true
+	case ')':
+	default:
+		err = fmt.Errorf("kallax: invalid range %q: invalid upper bound", s)
+		return
+	}
+
+	var values []string
+	var quoted bool
+	var buf bytes.Buffer
+	body := s[1 : len(s)-1]
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case c == '\\' && i+1 < len(body):
+			i++
+			buf.WriteByte(body[i])
+		case c == '"':
+			if quoted && i+1 < len(body) && body[i+1] == '"' {
+				i++
+				buf.WriteByte('"')
+			} else {
+				quoted = !quoted
+			}
+		case c == ',' && !quoted:
+			values = append(values, buf.String())
+			buf.Reset()
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	values = append(values, buf.String())
+
+	if len(values) != 2 {
+		err = fmt.Errorf("kallax: invalid range %q", s)
+		return
+	}
+
+	lower, upper = values[0], values[1]
+	b.LowerInfinite = lower == ""
+	b.UpperInfinite = upper == ""
+	if b.LowerInfinite {
+		b.LowerInclusive = false
+	}
+	if b.UpperInfinite {
+		b.UpperInclusive = false
+	}
+	return
+}
+
+// Int4Range is a range of integers stored in an int4range column.
+type Int4Range struct {
+	RangeBounds
+	Lower int32
+	Upper int32
+}
+
+// NewInt4Range returns a new range of integers with the given bounds. See
+// NewRangeBounds for the format of bounds.
+func NewInt4Range(lower, upper int32, bounds string) Int4Range {
+	return Int4Range{NewRangeBounds(bounds), lower, upper}
+}
+
+// Scan implements the sql.Scanner interface.
+func (r *Int4Range) Scan(v interface{}) error {
+	b, lower, upper, err := parseRange(v, "Int4Range")
+	if err != nil {
+		return err
+	}
+
+	var rng = Int4Range{RangeBounds: b}
+	if !b.Empty && !b.LowerInfinite {
+		n, err := strconv.ParseInt(lower, 10, 32)
+		if err != nil {
+			return fmt.Errorf("kallax: invalid int4range lower bound %q: %s", lower, err)
+		}
+		rng.Lower = int32(n)
+	}
+
+	if !b.Empty && !b.UpperInfinite {
+		n, err := strconv.ParseInt(upper, 10, 32)
+		if err != nil {
+			return fmt.Errorf("kallax: invalid int4range upper bound %q: %s", upper, err)
+		}
+		rng.Upper = int32(n)
+	}
+
+	*r = rng
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (r Int4Range) Value() (driver.Value, error) {
+	return r.format(
+		strconv.FormatInt(int64(r.Lower), 10),
+		strconv.FormatInt(int64(r.Upper), 10),
+	), nil
+}
+
+// Int8Range is a range of big integers stored in an int8range column.
+type Int8Range struct {
+	RangeBounds
+	Lower int64
+	Upper int64
+}
+
+// NewInt8Range returns a new range of big integers with the given bounds.
+// See NewRangeBounds for the format of bounds.
+func NewInt8Range(lower, upper int64, bounds string) Int8Range {
+	return Int8Range{NewRangeBounds(bounds), lower, upper}
+}
+
+// Scan implements the sql.Scanner interface.
+func (r *Int8Range) Scan(v interface{}) error {
+	b, lower, upper, err := parseRange(v, "Int8Range")
+	if err != nil {
+		return err
+	}
+
+	var rng = Int8Range{RangeBounds: b}
+	if !b.Empty && !b.LowerInfinite {
+		rng.Lower, err = strconv.ParseInt(lower, 10, 64)
+		if err != nil {
+			return fmt.Errorf("kallax: invalid int8range lower bound %q: %s", lower, err)
+		}
+	}
+
+	if !b.Empty && !b.UpperInfinite {
+		rng.Upper, err = strconv.ParseInt(upper, 10, 64)
+		if err != nil {
+			return fmt.Errorf("kallax: invalid int8range upper bound %q: %s", upper, err)
+		}
+	}
+
+	*r = rng
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (r Int8Range) Value() (driver.Value, error) {
+	return r.format(
+		strconv.FormatInt(r.Lower, 10),
+		strconv.FormatInt(r.Upper, 10),
+	), nil
+}
+
+// NumRange is a range of numbers stored in a numrange column.
+type NumRange struct {
+	RangeBounds
+	Lower float64
+	Upper float64
+}
+
+// NewNumRange returns a new range of numbers with the given bounds. See
+// NewRangeBounds for the format of bounds.
+func NewNumRange(lower, upper float64, bounds string) NumRange {
+	return NumRange{NewRangeBounds(bounds), lower, upper}
+}
+
+// Scan implements the sql.Scanner interface.
+func (r *NumRange) Scan(v interface{}) error {
+	b, lower, upper, err := parseRange(v, "NumRange")
+	if err != nil {
+		return err
+	}
+
+	var rng = NumRange{RangeBounds: b}
+	if !b.Empty && !b.LowerInfinite {
+		rng.Lower, err = strconv.ParseFloat(lower, 64)
+		if err != nil {
+			return fmt.Errorf("kallax: invalid numrange lower bound %q: %s", lower, err)
+		}
+	}
+
+	if !b.Empty && !b.UpperInfinite {
+		rng.Upper, err = strconv.ParseFloat(upper, 64)
+		if err != nil {
+			return fmt.Errorf("kallax: invalid numrange upper bound %q: %s", upper, err)
+		}
+	}
+
+	*r = rng
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (r NumRange) Value() (driver.Value, error) {
+	return r.format(
+		strconv.FormatFloat(r.Lower, 'f', -1, 64),
+		strconv.FormatFloat(r.Upper, 'f', -1, 64),
+	), nil
+}
+
+const tstzRangeLayout = "2006-01-02 15:04:05.999999-07:00"
+
+// TstzRange is a range of times stored in a tstzrange column.
+type TstzRange struct {
+	RangeBounds
+	Lower time.Time
+	Upper time.Time
+}
+
+// NewTstzRange returns a new range of times with the given bounds. See
+// NewRangeBounds for the format of bounds.
+func NewTstzRange(lower, upper time.Time, bounds string) TstzRange {
+	return TstzRange{NewRangeBounds(bounds), lower, upper}
+}
+
+// Scan implements the sql.Scanner interface.
+func (r *TstzRange) Scan(v interface{}) error {
+	b, lower, upper, err := parseRange(v, "TstzRange")
+	if err != nil {
+		return err
+	}
+
+	var rng = TstzRange{RangeBounds: b}
+	if !b.Empty && !b.LowerInfinite {
+		rng.Lower, err = parseRangeTime(lower)
+		if err != nil {
+			return fmt.Errorf("kallax: invalid tstzrange lower bound %q: %s", lower, err)
+		}
+	}
+
+	if !b.Empty && !b.UpperInfinite {
+		rng.Upper, err = parseRangeTime(upper)
+		if err != nil {
+			return fmt.Errorf("kallax: invalid tstzrange upper bound %q: %s", upper, err)
+		}
+	}
+
+	*r = rng
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (r TstzRange) Value() (driver.Value, error) {
+	return r.format(
+		strconv.Quote(r.Lower.Format(tstzRangeLayout)),
+		strconv.Quote(r.Upper.Format(tstzRangeLayout)),
+	), nil
+}
+
+func parseRangeTime(s string) (time.Time, error) {
+	// Postgres may omit the minutes of the timezone offset.
+	if len(s) > 3 && (s[len(s)-3] == '+' || s[len(s)-3] == '-') {
+		s += ":00"
+	}
+	return time.Parse(tstzRangeLayout, s)
+}
@@ -0,0 +1,103 @@
+package kallax
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRangeBounds(t *testing.T) {
+	require.Equal(t, RangeBounds{LowerInclusive: true}, NewRangeBounds("[)"))
+	require.Equal(t, RangeBounds{LowerInclusive: true, UpperInclusive: true}, NewRangeBounds("[]"))
+	require.Equal(t, RangeBounds{}, NewRangeBounds("()"))
+	require.Equal(t, RangeBounds{UpperInclusive: true}, NewRangeBounds("(]"))
+	require.Equal(t, RangeBounds{LowerInclusive: true}, NewRangeBounds(""))
+}
+
+func TestInt4Range(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected Int4Range
+	}{
+		{"[1,5)", NewInt4Range(1, 5, "[)")},
+		{"(1,5]", NewInt4Range(1, 5, "(]")},
+		{"[-3,-1]", NewInt4Range(-3, -1, "[]")},
+		{"empty", Int4Range{RangeBounds: RangeBounds{Empty: true}}},
+		{"(,5)", Int4Range{RangeBounds: RangeBounds{LowerInfinite: true}, Upper: 5}},
+		{"[1,)", Int4Range{RangeBounds: RangeBounds{LowerInclusive: true, UpperInfinite: true}, Lower: 1}},
+	}
+
+	for _, c := range cases {
+		var r Int4Range
+		require.NoError(t, r.Scan([]byte(c.input)), c.input)
+		require.Equal(t, c.expected, r, c.input)
+
+		v, err := r.Value()
+		require.NoError(t, err)
+		require.Equal(t, c.input, v, c.input)
+	}
+}
+
+func TestInt8Range(t *testing.T) {
+	var r Int8Range
+	require.NoError(t, r.Scan("[1,9223372036854775807)"))
+	require.Equal(t, NewInt8Range(1, 9223372036854775807, "[)"), r)
+
+	v, err := r.Value()
+	require.NoError(t, err)
+	require.Equal(t, "[1,9223372036854775807)", v)
+}
+
+func TestNumRange(t *testing.T) {
+	var r NumRange
+	require.NoError(t, r.Scan("[1.5,2.25]"))
+	require.Equal(t, NewNumRange(1.5, 2.25, "[]"), r)
+
+	v, err := r.Value()
+	require.NoError(t, err)
+	require.Equal(t, "[1.5,2.25]", v)
+}
+
+func TestTstzRange(t *testing.T) {
+	var r TstzRange
+	require.NoError(t, r.Scan(`["2017-01-02 03:04:05.6+00","2017-02-01 00:00:00+05:30")`))
+	lower := time.Date(2017, time.January, 2, 3, 4, 5, 600000000, time.UTC)
+	upper := time.Date(2017, time.February, 1, 0, 0, 0, 0, time.FixedZone("", 5*3600+1800))
+	require.True(t, lower.Equal(r.Lower), r.Lower.String())
+	require.True(t, upper.Equal(r.Upper), r.Upper.String())
+	require.Equal(t, NewRangeBounds("[)"), r.RangeBounds)
+
+	v, err := NewTstzRange(lower, upper, "[]").Value()
+	require.NoError(t, err)
+	require.Equal(t, `["2017-01-02 03:04:05.6+00:00","2017-02-01 00:00:00+05:30"]`, v)
+}
+
+func TestRange_ScanInvalid(t *testing.T) {
+	cases := []interface{}{
+		"1,5",
+		"{1,5}",
+		"[1,2,3]",
+		"[a,5)",
+		int64(1),
+	}
+
+	for _, c := range cases {
+		var r Int4Range
+		require.Error(t, r.Scan(c), "%v", c)
+	}
+}
+
+func TestRangeContainsElem(t *testing.T) {
+	r := require.New(t)
+	col := f("ages")
+	schema := NewBaseSchema("rooms", "__rooms", f("id"), nil, nil, false, f("id"), col)
+
+	sql, args, err := RangeContainsElem(col, int32(5))(schema).ToSql()
+	r.NoError(err)
+	r.Equal("__rooms.ages @> CAST(? AS integer)", sql)
+	r.Equal([]interface{}{int32(5)}, args)
+
+	_, _, err = RangeContainsElem(col, "foo")(schema).ToSql()
+	r.Error(err)
+}
@@ -0,0 +1,137 @@
+package kallax
+
+import (
+	"sort"
+	"sync"
+)
+
+// SchemaInfo describes the schema of a generated model, so generic tooling
+// can inspect the models at runtime. The schemas of all the generated models
+// are registered when their packages are initialized, see Schemas.
+type SchemaInfo struct {
+	// Model is the name of the model.
+	Model string
+	// Package is the import path of the package of the model.
+	Package string
+	// Schema is the schema of the model, which can be used to build queries
+	// and run them with a store.
+	Schema Schema
+	// Columns are the columns of the table of the model.
+	Columns []ColumnInfo
+	// Relationships are the relationships of the model.
+	Relationships []RelationshipInfo
+}
+
+// Table returns the name of the table of the model.
+func (i *SchemaInfo) Table() string {
+	return i.Schema.Table()
+}
+
+// Column returns the column with the given name, if the model has it.
+func (i *SchemaInfo) Column(name string) (ColumnInfo, bool) {
+	for _, c := range i.Columns {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return ColumnInfo{}, false
+}
+
+// relationship returns the relationship of the model in the given field, if
+// it has it.
+func (i *SchemaInfo) relationship(field string) (RelationshipInfo, bool) {
+	for _, r := range i.Relationships {
+		if r.Field == field {
+			return r, true
+		}
+	}
+	return RelationshipInfo{}, false
+}
+
+// ColumnInfo describes a column of the table of a model.
+type ColumnInfo struct {
+	// Name is the name of the column.
+	Name string
+	// Field is the path of the field of the model stored in the column, with
+	// the names of the inlined struct fields separated by dots. Fields of
+	// embedded structs are promoted, so they are not prefixed. It is empty
+	// for the foreign keys of the relationships defined in other models.
+	Field string
+	// Type is the PostgreSQL type of the column, which is empty if it could
+	// not be determined when the model was generated.
+	Type string
+	// PrimaryKey reports whether the column is the primary key of the table.
+	PrimaryKey bool
+	// NotNull reports whether the column can not be NULL.
+	NotNull bool
+}
+
+// RelationshipInfo describes a relationship of a model.
+type RelationshipInfo struct {
+	// Field is the name of the field of the model with the relationship.
+	Field string
+	// Type is the type of the relationship, OneToOne, OneToMany or
+	// ManyToMany.
+	Type RelationshipType
+	// Schema is the schema of the related model.
+	Schema Schema
+	// ForeignKey is the name of the foreign key of the relationship, which is
+	// the column of the join table referencing the model in many to many
+	// relationships.
+	ForeignKey string
+	// Inverse reports whether the foreign key is in the table of the model,
+	// instead of in the one of the related model.
+	Inverse bool
+	// Through is the name of the join table of a many to many relationship.
+	Through string
+	// References is the name of the column of the join table of a many to
+	// many relationship referencing the related model.
+	References string
+}
+
+var registry = struct {
+	sync.RWMutex
+	schemas map[string]*SchemaInfo
+}{schemas: make(map[string]*SchemaInfo)}
+
+// RegisterSchema registers the description of the schema of a model, which
+// replaces any other registered for the same model. It is called by the
+// generated code, and registering the schemas of models that are not
+// generated is only needed for them to be listed by Schemas.
+func RegisterSchema(info *SchemaInfo) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.schemas[info.Package+"."+info.Model] = info
+}
+
+// Schemas returns the descriptions of the schemas of all the registered
+// models, sorted by package and model name.
+func Schemas() []*SchemaInfo {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	result := make([]*SchemaInfo, 0, len(registry.schemas))
+	for _, info := range registry.schemas {
+		result = append(result, info)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Package != result[j].Package {
+			return result[i].Package < result[j].Package
+		}
+		return result[i].Model < result[j].Model
+	})
+	return result
+}
+
+// SchemaByTable returns the description of the schema of the registered
+// model with the given table. If several models have the same table, the
+// first one returned by Schemas is returned.
+func SchemaByTable(table string) (*SchemaInfo, bool) {
+	for _, info := range Schemas() {
+		if info.Table() == table {
+			return info, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,51 @@
+package kallax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterSchema(t *testing.T) {
+	r := require.New(t)
+
+	RegisterSchema(&SchemaInfo{Model: "Rel", Package: "example.com/registry", Schema: RelSchema})
+	RegisterSchema(&SchemaInfo{
+		Model:   "Model",
+		Package: "example.com/registry",
+		Schema:  ModelSchema,
+		Columns: []ColumnInfo{
+			{Name: "id", Field: "ID", Type: "serial", PrimaryKey: true, NotNull: true},
+			{Name: "name", Field: "Name", Type: "text", NotNull: true},
+		},
+	})
+	RegisterSchema(&SchemaInfo{Model: "Model", Package: "example.com/another", Schema: ModelSchema})
+
+	var names []string
+	for _, info := range Schemas() {
+		names = append(names, info.Package+"."+info.Model)
+	}
+	r.Equal([]string{
+		"example.com/another.Model",
+		"example.com/registry.Model",
+		"example.com/registry.Rel",
+	}, names)
+
+	info, ok := SchemaByTable("rel")
+	r.True(ok)
+	r.Equal("Rel", info.Model)
+
+	info, ok = SchemaByTable("model")
+	r.True(ok)
+	r.Equal("example.com/another", info.Package)
+
+	_, ok = SchemaByTable("nope")
+	r.False(ok)
+
+	registered, ok := Schemas()[1].Column("name")
+	r.True(ok)
+	r.Equal(ColumnInfo{Name: "name", Field: "Name", Type: "text", NotNull: true}, registered)
+
+	_, ok = info.Column("name")
+	r.False(ok)
+}
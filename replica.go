@@ -0,0 +1,170 @@
+package kallax
+
+import (
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// ReplicaBalancer picks the replica each read-only query of a store with
+// replicas is run in. It must be safe for concurrent use.
+type ReplicaBalancer interface {
+	// Pick returns the index of the replica the next query is run in, out
+	// of the given replicas, which are never empty.
+	Pick(replicas []*sql.DB) int
+}
+
+// NewRoundRobinBalancer returns a balancer that picks the replicas in turns,
+// which is the one used by WithReplicas if none is given.
+func NewRoundRobinBalancer() ReplicaBalancer {
+	return new(roundRobinBalancer)
+}
+
+type roundRobinBalancer struct {
+	next uint64
+}
+
+func (b *roundRobinBalancer) Pick(replicas []*sql.DB) int {
+	return int((atomic.AddUint64(&b.next, 1) - 1) % uint64(len(replicas)))
+}
+
+// NewLeastConnectionsBalancer returns a balancer that picks the replica with
+// the fewest connections in use, or the first of them if there are several.
+func NewLeastConnectionsBalancer() ReplicaBalancer {
+	return leastConnectionsBalancer{}
+}
+
+type leastConnectionsBalancer struct{}
+
+func (leastConnectionsBalancer) Pick(replicas []*sql.DB) int {
+	var picked, inUse int
+	for i, db := range replicas {
+		if n := db.Stats().InUse; i == 0 || n < inUse {
+			picked, inUse = i, n
+		}
+	}
+	return picked
+}
+
+// replicaSet are the replicas of a store, which are shared by its copies.
+type replicaSet struct {
+	dbs      []*sql.DB
+	balancer ReplicaBalancer
+}
+
+// pick returns the index of the replica the next query is run in.
+func (r *replicaSet) pick() int {
+	i := r.balancer.Pick(r.dbs)
+	if i < 0 || i >= len(r.dbs) {
+		return 0
+	}
+	return i
+}
+
+// OpenReplicas opens the databases with the given connection strings with
+// the given driver, to be used as the replicas of a store with WithReplicas.
+// The databases that have been opened are closed if any of them fails.
+func OpenReplicas(driverName string, dsns ...string) ([]*sql.DB, error) {
+	var replicas []*sql.DB
+	for i, dsn := range dsns {
+		db, err := sql.Open(driverName, dsn)
+		if err != nil {
+			for _, db := range replicas {
+				db.Close()
+			}
+			return nil, fmt.Errorf("kallax: unable to open replica %d: %s", i, err)
+		}
+		replicas = append(replicas, db)
+	}
+	return replicas, nil
+}
+
+// WithReplicas returns a new store that runs the read-only queries of Find,
+// Count, Reload and Aggregate in one of the given replicas of its database,
+// picked by the given balancer for each query, or in turns if it's nil. The
+// rest of statements and the transactions are always run in the primary
+// database, as are the queries that lock their rows or have the ForcePrimary
+// option. The replicas are run with the dialect of the store, and their
+// prepared statements are cached separately. If no replica is given, the
+// returned store reads from its primary database.
+func (s *Store) WithReplicas(balancer ReplicaBalancer, replicas ...*sql.DB) *Store {
+	var set *replicaSet
+	if len(replicas) > 0 {
+		if balancer == nil {
+			balancer = NewRoundRobinBalancer()
+		}
+		set = &replicaSet{dbs: append([]*sql.DB(nil), replicas...), balancer: balancer}
+	}
+
+	store := s.clone()
+	store.replicas = set
+	return store.init()
+}
+
+// Primary returns a new store that runs all its queries in the primary
+// database, which can be used to read the records just written with a store
+// with replicas, as they may not have been replicated yet.
+func (s *Store) Primary() *Store {
+	return s.WithReplicas(nil)
+}
+
+// reader returns the runner the given query is run with, which is the one of
+// a replica picked by the balancer of the store if it has replicas and the
+// query does not have to be run in the primary database. Reload gives no
+// query.
+func (s *Store) reader(q Query) squirrel.DBProxyContext {
+	if !s.readsReplica(q) {
+		return s.runner
+	}
+
+	runner := s.replicaChains[s.replicas.pick()]
+	if s.ctx != nil {
+		runner = &contextBoundRunner{DBProxyContext: runner, ctx: s.ctx}
+	}
+	return runner
+}
+
+// readsReplica reports whether the given query is run in a replica of the
+// store, see reader.
+func (s *Store) readsReplica(q Query) bool {
+	if len(s.replicaChains) == 0 || (q != nil && q.readsPrimary()) {
+		return false
+	}
+
+	_, inTx := s.db.(*txRunner)
+	return !inTx
+}
+
+// QueryOption is an option of a query that changes how it is run by the
+// stores. See BaseQuery.Options.
+type QueryOption interface {
+	applyQuery(*BaseQuery)
+}
+
+// ForcePrimary returns an option that makes a query run in the primary
+// database even if the store has replicas, so it reads the records written
+// before it, which may not have been replicated yet.
+func ForcePrimary() QueryOption {
+	return forcePrimary{}
+}
+
+type forcePrimary struct{}
+
+func (forcePrimary) applyQuery(q *BaseQuery) {
+	q.primary = true
+}
+
+// Options sets the given options of the query, such as ForcePrimary.
+func (q *BaseQuery) Options(opts ...QueryOption) {
+	for _, opt := range opts {
+		opt.applyQuery(q)
+	}
+}
+
+// readsPrimary reports whether the query must be run in the primary
+// database, because it has the ForcePrimary option or locks the rows.
+func (q *BaseQuery) readsPrimary() bool {
+	return q.primary || q.lock != nil
+}
@@ -0,0 +1,131 @@
+package kallax
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_WithReplicas(t *testing.T) {
+	r := require.New(t)
+	primary, err := sql.Open("kallax_recording", "primary")
+	r.NoError(err)
+	defer primary.Close()
+
+	replicas, err := OpenReplicas("kallax_recording", "replica1", "replica2")
+	r.NoError(err)
+	for _, db := range replicas {
+		defer db.Close()
+	}
+
+	recordedQueries, recordedDSNs = nil, nil
+	store := NewStore(primary).DisableCacher().WithReplicas(nil, replicas...)
+
+	_, err = store.Find(NewBaseQuery(ModelSchema))
+	r.NoError(err)
+	store.Count(NewBaseQuery(ModelSchema))
+	r.Equal(ErrNotFound, store.WithContext(context.Background()).Reload(ModelSchema, &model{ID: 1}))
+
+	q := NewBaseQuery(ModelSchema)
+	q.Options(ForcePrimary())
+	_, err = store.Find(q.Copy())
+	r.NoError(err)
+
+	q = NewBaseQuery(ModelSchema)
+	q.LockForUpdate()
+	_, err = store.Find(q)
+	r.NoError(err)
+
+	_, err = store.RawExec("DELETE FROM model")
+	r.NoError(err)
+	_, err = store.Primary().Find(NewBaseQuery(ModelSchema))
+	r.NoError(err)
+	r.NoError(store.Transaction(func(s *Store) error {
+		_, err := s.Find(NewBaseQuery(ModelSchema))
+		return err
+	}))
+
+	r.Equal([]string{
+		"replica1",
+		"replica2",
+		"replica1",
+		"primary",
+		"primary",
+		"primary",
+		"primary",
+		"primary",
+	}, recordedDSNs)
+}
+
+func TestStore_WithReplicas_Cache(t *testing.T) {
+	r := require.New(t)
+	primary, err := sql.Open("kallax_recording", "primary")
+	r.NoError(err)
+	defer primary.Close()
+
+	replicas, err := OpenReplicas("kallax_recording", "replica1")
+	r.NoError(err)
+	defer replicas[0].Close()
+
+	recordedQueries, recordedDSNs = nil, nil
+	cache := NewQueryCache()
+	store := NewStore(primary).DisableCacher().WithCache(cache, 0).WithReplicas(nil, replicas...)
+	find := func(s *Store, opts ...QueryOption) {
+		q := NewBaseQuery(ModelSchema)
+		q.Options(opts...)
+		rs, err := s.Find(q)
+		r.NoError(err)
+		r.NoError(rs.Close())
+	}
+
+	find(store)
+	find(store)
+	find(store, ForcePrimary())
+	find(store, ForcePrimary())
+	find(store.Primary())
+	r.Equal(2, cache.Len())
+	r.Equal([]string{"replica1", "primary"}, recordedDSNs)
+}
+
+func TestStore_WithReplicas_StatementCache(t *testing.T) {
+	r := require.New(t)
+	primary, err := sql.Open("kallax_recording", "primary")
+	r.NoError(err)
+	defer primary.Close()
+
+	replicas, err := OpenReplicas("kallax_recording", "replica1", "replica2")
+	r.NoError(err)
+	for _, db := range replicas {
+		defer db.Close()
+	}
+
+	recordedQueries, recordedDSNs = nil, nil
+	store := NewStore(primary).WithReplicas(NewRoundRobinBalancer(), replicas...)
+	for i := 0; i < 4; i++ {
+		rs, err := store.DebugWith(func(string, ...interface{}) {}).Find(NewBaseQuery(ModelSchema))
+		r.NoError(err)
+		r.NoError(rs.Close())
+	}
+
+	r.Equal([]string{"replica1", "replica2"}, recordedDSNs)
+}
+
+func TestLeastConnectionsBalancer(t *testing.T) {
+	r := require.New(t)
+	replicas, err := OpenReplicas("kallax_recording", "replica1", "replica2")
+	r.NoError(err)
+	for _, db := range replicas {
+		defer db.Close()
+	}
+
+	balancer := NewLeastConnectionsBalancer()
+	r.Equal(0, balancer.Pick(replicas))
+
+	rows, err := replicas[0].Query("SELECT 1")
+	r.NoError(err)
+	r.Equal(1, balancer.Pick(replicas))
+	r.NoError(rows.Close())
+	r.Equal(0, balancer.Pick(replicas))
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"errors"
 	"io"
+	"time"
 
 	"gopkg.in/src-d/go-kallax.v1/types"
 )
@@ -35,6 +36,12 @@ type BaseResultSet struct {
 	columns       []string
 	readOnly      bool
 	*sql.Rows
+	// partial reports whether the columns are not all the ones of the
+	// schema, so only they are updated in the scanned records.
+	partial bool
+	// loc is the location scanned times are normalized to, if any.
+	loc         *time.Location
+	columnTypes []*sql.ColumnType
 }
 
 // NewResultSet creates a new result set with the given rows and columns.
@@ -42,10 +49,10 @@ type BaseResultSet struct {
 // equal to the ones in the query that produced the rows.
 func NewResultSet(rows *sql.Rows, readOnly bool, relationships []Relationship, columns ...string) *BaseResultSet {
 	return &BaseResultSet{
-		relationships,
-		columns,
-		readOnly,
-		rows,
+		relationships: relationships,
+		columns:       columns,
+		readOnly:      readOnly,
+		Rows:          rows,
 	}
 }
 
@@ -95,13 +102,14 @@ func (rs *BaseResultSet) Scan(record Record) error {
 		relationships[i] = rec
 	}
 
-	if err := rs.Rows.Scan(pointers...); err != nil {
+	if err := rs.scan(pointers...); err != nil {
 		return err
 	}
 
 	for i, r := range rs.relationships {
 		relationships[i].setPersisted()
 		relationships[i].setWritable(true)
+		snapshot(relationships[i], ColumnNames(r.Schema.Columns()), true)
 		err := record.SetRelationship(r.Field, relationships[i])
 		if err != nil {
 			return err
@@ -110,6 +118,8 @@ func (rs *BaseResultSet) Scan(record Record) error {
 
 	record.setWritable(!rs.readOnly)
 	record.setPersisted()
+	snapshot(record, rs.columns, true)
+	setFetched(record, rs.columns, rs.partial)
 	return nil
 }
 
@@ -117,7 +127,23 @@ func (rs *BaseResultSet) Scan(record Record) error {
 // dest. The number of values in dest must be the same as the number of columns
 // selected in the query.
 func (rs *BaseResultSet) RawScan(dest ...interface{}) error {
-	return rs.Rows.Scan(dest...)
+	return rs.scan(dest...)
+}
+
+func (rs *BaseResultSet) scan(dest ...interface{}) error {
+	if rs.loc == nil {
+		return rs.Rows.Scan(dest...)
+	}
+
+	if rs.columnTypes == nil {
+		var err error
+		rs.columnTypes, err = rs.Rows.ColumnTypes()
+		if err != nil {
+			return err
+		}
+	}
+
+	return rs.Rows.Scan(withLocation(dest, rs.loc, rs.columnTypes)...)
 }
 
 // NewBatchingResultSet returns a new result set that performs batching
@@ -0,0 +1,118 @@
+package kallax
+
+import "strings"
+
+// WithReturning returns a new store that returns the given columns from the
+// inserts, upserts and updates of the records of the given schema, including
+// the ones of batches and BatchInsert, and scans them back into the records,
+// so the values set by the database, such as defaults or the ones set by
+// triggers, are not left stale without querying the records again. The
+// columns replace the ones the store already returns for the schema, and no
+// columns are returned if none are given. The dialect of the store must
+// support RETURNING clauses.
+func (s *Store) WithReturning(schema Schema, cols ...SchemaField) *Store {
+	returning := make(map[string][]string, len(s.returning)+1)
+	for table, cols := range s.returning {
+		returning[table] = cols
+	}
+
+	if len(cols) > 0 {
+		returning[schema.Table()] = ColumnNames(cols)
+	} else {
+		delete(returning, schema.Table())
+	}
+
+	store := s.clone()
+	store.returning = returning
+	return store.init()
+}
+
+// returningColumns returns the columns returned by the store from the
+// inserts and updates of the records of the given schema. An UnsupportedError
+// is returned if there are any and the dialect does not support them.
+func (s *Store) returningColumns(schema Schema) ([]string, error) {
+	cols := s.returning[schema.Table()]
+	if d := s.Dialect(); len(cols) > 0 && !d.Supports(FeatureReturning) {
+		return nil, &UnsupportedError{Dialect: d.Name(), Feature: FeatureReturning}
+	}
+	return cols, nil
+}
+
+// insertReturningColumns returns the columns returned by the store from the
+// insert of the given record of the given schema, which are its returned
+// columns along with the ones left out of the insert to be given their
+// default values, if the dialect supports returning them.
+func (s *Store) insertReturningColumns(schema Schema, record Record) ([]string, error) {
+	cols, err := s.returningColumns(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.Dialect().Supports(FeatureReturning) {
+		return cols, nil
+	}
+
+	defaulted, err := defaultedColumns(schema, record)
+	if err != nil {
+		return nil, err
+	}
+
+	result := append([]string(nil), cols...)
+	for _, col := range defaulted {
+		if !containsString(cols, col) {
+			result = append(result, col)
+		}
+	}
+	return result, nil
+}
+
+// appendReturning appends the given columns to the RETURNING clause of the
+// given statement, adding it if the statement has none.
+func appendReturning(query string, hasReturning bool, cols []string) string {
+	if len(cols) == 0 {
+		return query
+	}
+
+	if hasReturning {
+		return query + ", " + strings.Join(cols, ", ")
+	}
+	return query + " RETURNING " + strings.Join(cols, ", ")
+}
+
+// returnedAddresses returns the addresses the given returned columns of the
+// record are scanned into, preceded by the one of its primary key if pk is
+// true.
+func returnedAddresses(schema Schema, record Record, pk bool, cols []string) ([]interface{}, error) {
+	if pk {
+		cols = append([]string{schema.ID().String()}, cols...)
+	}
+
+	addrs := make([]interface{}, len(cols))
+	for i, col := range cols {
+		addr, err := record.ColumnAddress(col)
+		if err != nil {
+			return nil, err
+		}
+		addrs[i] = addr
+	}
+	return addrs, nil
+}
+
+// queryReturned runs the given statement and scans the values it returns
+// into the given addresses. It returns whether the statement returned a row.
+func (s *Store) queryReturned(query string, values []interface{}, addrs []interface{}) (bool, error) {
+	rows, err := s.runner.Query(query, values...)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return false, rows.Err()
+	}
+
+	if err := rows.Scan(addrs...); err != nil {
+		return false, err
+	}
+	return true, rows.Close()
+}
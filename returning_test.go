@@ -0,0 +1,67 @@
+package kallax
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreWithReturning(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	store := NewStore(db).WithReturning(ModelSchema, f("email"), f("age"))
+	recordedQueries = nil
+	m := newModel("foo", "foo@bar.baz", 1)
+	r.NoError(store.Insert(ModelSchema, m))
+
+	m.ID = 1
+	_, err = store.Update(ModelSchema, m, f("name"))
+	r.Equal(ErrNoRowUpdate, err)
+
+	m.setPersisted()
+	r.NoError(store.Upsert(ModelSchema, m, []SchemaField{f("email")}, f("name")))
+
+	r.Equal([]string{
+		"INSERT INTO model (name,email,age) VALUES ($1,$2,$3) RETURNING id, email, age",
+		"UPDATE model SET name=$1 WHERE id=$2 RETURNING email, age",
+		"INSERT INTO model (name,email,age) VALUES ($1,$2,$3) ON CONFLICT (email) DO UPDATE SET name = EXCLUDED.name RETURNING id, email, age",
+	}, recordedQueries)
+
+	recordedQueries = nil
+	_, err = store.WithReturning(ModelSchema).Update(ModelSchema, m, f("name"))
+	r.Equal(ErrNoRowUpdate, err)
+	r.Equal([]string{"UPDATE model SET name=$1 WHERE id=$2"}, recordedQueries)
+
+	err = store.WithDialect(MySQL).Insert(ModelSchema, newModel("foo", "foo@bar.baz", 1))
+	r.Equal(&UnsupportedError{"mysql", FeatureReturning}, err)
+}
+
+func TestBatchWithReturning(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	store := NewStore(db).WithReturning(ModelSchema, f("age"))
+	updated := newModel("bar", "bar@bar.baz", 2)
+	updated.ID = 2
+	updated.setPersisted()
+
+	b := store.NewBatch()
+	r.NoError(b.Insert(ModelSchema, newModel("foo", "foo@bar.baz", 1)))
+	r.NoError(b.Update(ModelSchema, updated, f("name")))
+	query, _, err := b.ToSql()
+	r.NoError(err)
+	r.Equal("WITH q1 AS (INSERT INTO model (name,email,age) VALUES ($1,$2,$3) RETURNING id, age), "+
+		"q2 AS (UPDATE model SET name=$4 WHERE id=$5 RETURNING 1, age) "+
+		"SELECT (SELECT id FROM q1), (SELECT age FROM q1), (SELECT COUNT(*) FROM q2), (SELECT age FROM q2)", query)
+
+	recordedQueries = nil
+	records := []Record{newModel("foo", "foo@bar.baz", 1), newModel("bar", "bar@bar.baz", 2)}
+	r.Error(store.BatchInsert(ModelSchema, records, BatchInsertOptions{}))
+	r.Equal([]string{"INSERT INTO model (name,email,age) VALUES ($1,$2,$3),($4,$5,$6) RETURNING id, age"}, recordedQueries)
+}
@@ -0,0 +1,77 @@
+package kallax
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/lann/builder"
+)
+
+// LockOption is an option of the row-level locks taken by queries, which
+// sets what the queries do when the rows they retrieve are already locked by
+// other transactions. By default, they wait until the rows are unlocked.
+type LockOption int
+
+const (
+	// NoWait makes the query fail right away if any of the rows it retrieves
+	// are locked by another transaction.
+	NoWait LockOption = iota + 1
+	// SkipLocked makes the query skip the rows locked by other transactions,
+	// so concurrent workers can claim different rows of a job queue.
+	SkipLocked
+)
+
+// rowLock is the row-level lock taken by a query on the rows it retrieves.
+type rowLock struct {
+	strength string
+	option   LockOption
+}
+
+// LockForUpdate makes the query lock the rows it retrieves, so they can not
+// be updated, deleted or locked by other transactions until the transaction
+// the query is run in ends. Outside of a transaction, the rows are unlocked
+// as soon as the query ends. Only the rows of the schema of the query are
+// locked, not the ones of its 1:1 relationships. The given option, if any,
+// sets what the query does with the rows already locked.
+//
+//	q.LockForUpdate(SkipLocked)
+//	// ... FOR UPDATE OF __model SKIP LOCKED
+func (q *BaseQuery) LockForUpdate(opts ...LockOption) {
+	q.lock = newRowLock("UPDATE", opts)
+}
+
+// LockForShare makes the query lock the rows it retrieves, as LockForUpdate
+// does, but with a shared lock, so other transactions can still lock them for
+// share, but not update, delete or lock them for update.
+func (q *BaseQuery) LockForShare(opts ...LockOption) {
+	q.lock = newRowLock("SHARE", opts)
+}
+
+func newRowLock(strength string, opts []LockOption) *rowLock {
+	lock := &rowLock{strength: strength}
+	for _, opt := range opts {
+		lock.option = opt
+	}
+	return lock
+}
+
+// clause returns the locking clause of the lock for the rows of the given
+// schema.
+func (l *rowLock) clause(schema Schema) string {
+	clause := fmt.Sprintf("FOR %s OF %s", l.strength, schema.Alias())
+	switch l.option {
+	case NoWait:
+		clause += " NOWAIT"
+	case SkipLocked:
+		clause += " SKIP LOCKED"
+	}
+	return clause
+}
+
+// aggregateBuilder returns the given select builder of a query without its
+// columns and its locking clause, which can not be used with aggregates, so
+// the aggregates computed by the query can be selected.
+func aggregateBuilder(b squirrel.SelectBuilder) squirrel.SelectBuilder {
+	b = builder.Set(b, "Columns", nil).(squirrel.SelectBuilder)
+	return builder.Set(b, "Suffixes", nil).(squirrel.SelectBuilder)
+}
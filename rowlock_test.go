@@ -0,0 +1,59 @@
+package kallax
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseQuery_Lock(t *testing.T) {
+	cases := []struct {
+		lock     func(*BaseQuery)
+		expected string
+	}{
+		{func(q *BaseQuery) { q.LockForUpdate() }, "FOR UPDATE OF __model"},
+		{func(q *BaseQuery) { q.LockForUpdate(NoWait) }, "FOR UPDATE OF __model NOWAIT"},
+		{func(q *BaseQuery) { q.LockForUpdate(SkipLocked) }, "FOR UPDATE OF __model SKIP LOCKED"},
+		{func(q *BaseQuery) { q.LockForShare() }, "FOR SHARE OF __model"},
+		{func(q *BaseQuery) { q.LockForShare(NoWait, SkipLocked) }, "FOR SHARE OF __model SKIP LOCKED"},
+	}
+
+	for _, c := range cases {
+		q := NewBaseQuery(ModelSchema)
+		q.Select(f("id"))
+		q.Order(Asc(f("id")))
+		c.lock(q)
+		require.Equal(t, "SELECT __model.id FROM model __model ORDER BY __model.id ASC "+c.expected, q.String())
+		require.Equal(t, q.String(), q.Copy().String())
+	}
+}
+
+func TestStore_FindLocked(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	q := NewBaseQuery(ModelSchema)
+	q.Where(Eq(f("name"), "foo"))
+	q.Limit(10)
+	q.LockForUpdate(SkipLocked)
+
+	recordedQueries = nil
+	store := NewStore(db)
+	_, err = store.Find(q)
+	r.NoError(err)
+	store.Count(q)
+	r.Equal([]string{
+		"SELECT __model.id, __model.name, __model.email, __model.age FROM model __model WHERE __model.name = $1 LIMIT 10 FOR UPDATE OF __model SKIP LOCKED",
+		"SELECT COUNT(*) FROM model __model WHERE __model.name = $1",
+	}, recordedQueries)
+
+	_, err = store.WithDialect(SQLite).Find(q)
+	r.Equal(&UnsupportedError{"sqlite", FeatureRowLocks}, err)
+
+	query, _, err := rewrite(MySQL, q.String(), nil)
+	r.NoError(err)
+	r.Contains(query, "FOR UPDATE OF __model SKIP LOCKED")
+}
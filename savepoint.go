@@ -0,0 +1,91 @@
+package kallax
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// ErrSavepointNoTx is returned when a savepoint is created, rolled back to
+// or released outside of a transaction.
+var ErrSavepointNoTx = errors.New("kallax: savepoints can only be used inside a transaction")
+
+// savepointRegex matches the valid names of savepoints, which are written
+// in the statements as they are.
+var savepointRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Savepoint creates a savepoint with the given name in the transaction held
+// by the store, so the changes made after it can be rolled back with
+// RollbackTo without rolling back the whole transaction. Creating a
+// savepoint with the name of an existing one hides it until the new one is
+// released.
+func (s *Store) Savepoint(name string) error {
+	return s.savepointExec("SAVEPOINT", name)
+}
+
+// RollbackTo rolls back the changes made in the transaction held by the
+// store since the savepoint with the given name was created. The savepoint
+// is kept, so it can be rolled back to again.
+func (s *Store) RollbackTo(name string) error {
+	return s.savepointExec("ROLLBACK TO SAVEPOINT", name)
+}
+
+// ReleaseSavepoint destroys the savepoint with the given name, and all the
+// ones created after it, keeping the changes made since it was created.
+func (s *Store) ReleaseSavepoint(name string) error {
+	return s.savepointExec("RELEASE SAVEPOINT", name)
+}
+
+func (s *Store) savepointExec(stmt, name string) error {
+	if _, ok := s.db.(*txRunner); !ok {
+		return ErrSavepointNoTx
+	}
+
+	if !savepointRegex.MatchString(name) {
+		return fmt.Errorf("kallax: invalid savepoint name %q", name)
+	}
+
+	_, err := s.runner.Exec(stmt + " " + name)
+	return err
+}
+
+// nestedTransaction runs the given callback of a Transaction call made in the
+// transaction held by the store in a savepoint, which is rolled back to if
+// the callback returns an error or panics, leaving the rest of the
+// transaction as is, and released otherwise.
+func (s *Store) nestedTransaction(tx *txRunner, callback func(*Store) error) error {
+	tx.savepoints++
+	defer func() { tx.savepoints-- }()
+
+	name := fmt.Sprintf("kallax_savepoint_%d", tx.savepoints)
+	if err := s.Savepoint(name); err != nil {
+		return fmt.Errorf("kallax: can't create savepoint: %s", err)
+	}
+
+	var returned bool
+	defer func() {
+		if !returned {
+			s.RollbackTo(name)
+		}
+	}()
+
+	// the events of the records written in the savepoint are discarded if
+	// it's rolled back
+	events := len(tx.events)
+
+	err := callback(s)
+	returned = true
+	if err != nil {
+		tx.events = tx.events[:events]
+
+		if rerr := s.RollbackTo(name); rerr != nil {
+			return fmt.Errorf("kallax: unable to rollback to savepoint: %s", rerr)
+		}
+		return err
+	}
+
+	if err := s.ReleaseSavepoint(name); err != nil {
+		return fmt.Errorf("kallax: unable to release savepoint: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package kallax
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreTransaction_Nested(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	errInner := errors.New("inner")
+	store := NewStore(db).DisableCacher()
+	recordedQueries = nil
+	r.NoError(store.Transaction(func(s *Store) error {
+		err := s.Transaction(func(s *Store) error {
+			return s.Transaction(func(s *Store) error {
+				return errInner
+			})
+		})
+		r.Equal(errInner, err)
+
+		r.NoError(s.Transaction(func(s *Store) error { return nil }))
+		r.NoError(s.Savepoint("foo"))
+		r.NoError(s.RollbackTo("foo"))
+		r.NoError(s.ReleaseSavepoint("foo"))
+		r.EqualError(s.Savepoint("foo; DROP TABLE model"), `kallax: invalid savepoint name "foo; DROP TABLE model"`)
+		return nil
+	}))
+
+	r.Equal([]string{
+		"SAVEPOINT kallax_savepoint_1",
+		"SAVEPOINT kallax_savepoint_2",
+		"ROLLBACK TO SAVEPOINT kallax_savepoint_2",
+		"ROLLBACK TO SAVEPOINT kallax_savepoint_1",
+		"SAVEPOINT kallax_savepoint_1",
+		"RELEASE SAVEPOINT kallax_savepoint_1",
+		"SAVEPOINT foo",
+		"ROLLBACK TO SAVEPOINT foo",
+		"RELEASE SAVEPOINT foo",
+	}, recordedQueries)
+
+	r.Equal(ErrSavepointNoTx, store.Savepoint("foo"))
+}
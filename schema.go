@@ -14,6 +14,10 @@ type Schema interface {
 	Table() string
 	// ID returns the name of the identifier of the table.
 	ID() SchemaField
+	// PrimaryKey returns the columns of the primary key of the table, which
+	// are more than one if it's a composite primary key, the first of them
+	// being the identifier.
+	PrimaryKey() []SchemaField
 	// Columns returns the list of columns in the schema.
 	Columns() []SchemaField
 	// ForeignKey returns the name of the foreign key of the given model field.
@@ -25,6 +29,19 @@ type Schema interface {
 	WithAlias(string) Schema
 	// New creates a new record with the given schema.
 	New() Record
+	// SoftDeleteField returns the column that records when the records of
+	// the table were deleted, if they are soft deleted, or nil otherwise.
+	SoftDeleteField() SchemaField
+	// LockField returns the version column with which the updates of the
+	// records of the table are optimistically locked, or nil if they are not.
+	LockField() SchemaField
+	// TenantField returns the column with the tenant the records of the
+	// table belong to, or nil if they do not belong to tenants.
+	TenantField() SchemaField
+	// DefaultFields returns the columns with a default value in the
+	// database, which are left out of the inserts of the records whose
+	// values of them are zero.
+	DefaultFields() []SchemaField
 	isPrimaryKeyAutoIncrementable() bool
 }
 
@@ -34,9 +51,14 @@ type BaseSchema struct {
 	table       string
 	foreignKeys ForeignKeys
 	id          SchemaField
+	primaryKey  []SchemaField
 	columns     []SchemaField
 	constructor RecordConstructor
 	autoIncr    bool
+	softDelete  SchemaField
+	lock        SchemaField
+	tenant      SchemaField
+	defaults    []SchemaField
 }
 
 // RecordConstructor is a function that creates a record.
@@ -60,6 +82,12 @@ func (s *BaseSchema) Alias() string          { return s.alias }
 func (s *BaseSchema) Table() string          { return s.table }
 func (s *BaseSchema) ID() SchemaField        { return s.id }
 func (s *BaseSchema) Columns() []SchemaField { return s.columns }
+func (s *BaseSchema) PrimaryKey() []SchemaField {
+	if len(s.primaryKey) == 0 {
+		return []SchemaField{s.id}
+	}
+	return s.primaryKey
+}
 func (s *BaseSchema) ForeignKey(field string) (*ForeignKey, bool) {
 	k, ok := s.foreignKeys[field]
 	return k, ok
@@ -70,8 +98,59 @@ func (s *BaseSchema) WithAlias(field string) Schema {
 func (s *BaseSchema) New() Record {
 	return s.constructor()
 }
+func (s *BaseSchema) SoftDeleteField() SchemaField        { return s.softDelete }
+func (s *BaseSchema) LockField() SchemaField              { return s.lock }
+func (s *BaseSchema) TenantField() SchemaField            { return s.tenant }
+func (s *BaseSchema) DefaultFields() []SchemaField        { return s.defaults }
 func (s *BaseSchema) isPrimaryKeyAutoIncrementable() bool { return s.autoIncr }
 
+// WithPrimaryKey sets the columns of the composite primary key of the
+// schema, the first of them being its identifier, and returns the schema,
+// so it can be chained to NewBaseSchema. The records of a schema with a
+// composite primary key must implement CompositeIdentifiable.
+func (s *BaseSchema) WithPrimaryKey(cols ...SchemaField) *BaseSchema {
+	s.primaryKey = cols
+	return s
+}
+
+// WithSoftDelete sets the nullable timestamp column that records when the
+// records of the schema were deleted and returns the schema, so it can be
+// chained to NewBaseSchema. The records of a schema with soft delete are not
+// removed by Store.Delete, which sets the column instead, and the queries
+// of the schema exclude the deleted ones, unless they are Unscoped.
+func (s *BaseSchema) WithSoftDelete(col SchemaField) *BaseSchema {
+	s.softDelete = col
+	return s
+}
+
+// WithLock sets the integer version column with which the updates of the
+// records of the schema are optimistically locked and returns the schema, so
+// it can be chained to NewBaseSchema. Store.Update only updates a record if
+// its version has not changed since it was retrieved, and increments it.
+func (s *BaseSchema) WithLock(col SchemaField) *BaseSchema {
+	s.lock = col
+	return s
+}
+
+// WithTenant sets the column with the tenant the records of the schema
+// belong to and returns the schema, so it can be chained to NewBaseSchema.
+// The stores bound to a tenant with Store.WithTenant only read and write the
+// records of the schema of their tenant.
+func (s *BaseSchema) WithTenant(col SchemaField) *BaseSchema {
+	s.tenant = col
+	return s
+}
+
+// WithDefaults sets the columns with a default value in the database and
+// returns the schema, so it can be chained to NewBaseSchema. The inserts of
+// the records whose values of the columns are zero leave them out, so the
+// database gives them their default values, which the stores read back into
+// the records if their dialect supports RETURNING clauses.
+func (s *BaseSchema) WithDefaults(cols ...SchemaField) *BaseSchema {
+	s.defaults = cols
+	return s
+}
+
 type aliasSchema struct {
 	*BaseSchema
 	alias string
@@ -123,11 +202,36 @@ func (f *BaseSchemaField) QualifiedName(schema Schema) string {
 type ForeignKey struct {
 	*BaseSchemaField
 	Inverse bool
+	// Through is the join table of a many to many relationship, in which case
+	// the foreign key is the column of the join table referencing the model.
+	// It is nil for the rest of relationships.
+	Through *JoinTable
 }
 
 // NewForeignKey creates a new Foreign key with the given name.
 func NewForeignKey(name string, inverse bool) *ForeignKey {
-	return &ForeignKey{&BaseSchemaField{name}, inverse}
+	return &ForeignKey{&BaseSchemaField{name}, inverse, nil}
+}
+
+// JoinTable is the table that links the records of both sides of a many to
+// many relationship.
+type JoinTable struct {
+	// Table is the name of the join table.
+	Table string
+	// References is the column of the join table referencing the related
+	// model.
+	References SchemaField
+}
+
+// NewJoinTableForeignKey creates the foreign key of a many to many
+// relationship through the given join table, whose column fk references the
+// model and whose column references references the related model.
+func NewJoinTableForeignKey(table, fk, references string) *ForeignKey {
+	return &ForeignKey{
+		&BaseSchemaField{fk},
+		false,
+		&JoinTable{table, &BaseSchemaField{references}},
+	}
 }
 
 // JSONSchemaKey is a SchemaField that represents a key in a JSON object.
@@ -215,6 +319,31 @@ func AtJSONPath(field SchemaField, typ JSONKeyType, path ...string) SchemaField
 	return NewJSONSchemaKey(typ, field.String(), path...)
 }
 
+// JSONArrayLength returns the schema field to query the number of elements
+// of the JSON array in the given field, which can be a key of a JSON object.
+// It's null if the element is not an array.
+func JSONArrayLength(field SchemaField) SchemaField {
+	return &jsonArrayLength{field}
+}
+
+type jsonArrayLength struct {
+	field SchemaField
+}
+
+func (f *jsonArrayLength) QualifiedName(schema Schema) string {
+	return jsonArrayLengthOf(f.field.QualifiedName(schema))
+}
+
+func (f *jsonArrayLength) String() string {
+	return jsonArrayLengthOf(f.field.String())
+}
+
+func jsonArrayLengthOf(col string) string {
+	return fmt.Sprintf("jsonb_array_length(CASE jsonb_typeof(%s) WHEN 'array' THEN %s END)", col, col)
+}
+
+func (*jsonArrayLength) isSchemaField() {}
+
 // Relationship is a relationship with its schema and the field of te relation
 // in the record.
 type Relationship struct {
@@ -227,6 +356,9 @@ type Relationship struct {
 	// Filter establishes the filter to be applied when retrieving rows of the
 	// relationships.
 	Filter Condition
+	// Preload are the dot-separated paths of the relationships of the related
+	// records that are loaded along with them. See Preload.
+	Preload []string
 }
 
 // RelationshipType describes the type of the relationship.
@@ -240,8 +372,7 @@ const (
 	// in another table.
 	OneToMany
 	// ManyToMany is a relationship between many records on both sides of the
-	// relationship.
-	// NOTE: It is not supported yet.
+	// relationship, which are linked through a join table.
 	ManyToMany
 )
 
@@ -63,3 +63,15 @@ func TestJSONSchemaKeyQualifiedName(t *testing.T) {
 		r.Equal(c.expected, c.key.QualifiedName(c.schema), c.name)
 	}
 }
+
+func TestJSONArrayLength(t *testing.T) {
+	r := require.New(t)
+	r.Equal(
+		"jsonb_array_length(CASE jsonb_typeof(__model.foo #>'{bar}') WHEN 'array' THEN __model.foo #>'{bar}' END)",
+		JSONArrayLength(NewJSONSchemaKey(JSONAny, "foo", "bar")).QualifiedName(ModelSchema),
+	)
+	r.Equal(
+		"jsonb_array_length(CASE jsonb_typeof(foo) WHEN 'array' THEN foo END)",
+		JSONArrayLength(f("foo")).String(),
+	)
+}
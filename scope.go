@@ -0,0 +1,63 @@
+package kallax
+
+import "github.com/Masterminds/squirrel"
+
+// scopes are the default conditions of the queries run by a store, by the
+// table of their schema.
+type scopes map[string][]Condition
+
+// allTables is the key of the scopes of the queries of every table, such as
+// the one of the tenant of the store, whose conditions match all the records
+// when they do not apply to a schema.
+const allTables = ""
+
+// with returns a copy of the scopes with the given condition added to the
+// ones of the table of the given schema.
+func (sc scopes) with(schema Schema, cond Condition) scopes {
+	result := make(scopes, len(sc)+1)
+	for table, conds := range sc {
+		result[table] = conds
+	}
+
+	table := schema.Table()
+	result[table] = append(append([]Condition(nil), sc[table]...), cond)
+	return result
+}
+
+// compile compiles the given query with the default conditions of the table
+// of its schema, if any, followed by the ones of every table.
+func (sc scopes) compile(q Query) ([]string, squirrel.SelectBuilder) {
+	columns, builder := q.compile()
+	schema := q.Schema()
+	for _, cond := range sc[schema.Table()] {
+		builder = builder.Where(cond(schema))
+	}
+
+	for _, cond := range sc[allTables] {
+		if pred := cond(schema); pred != nil {
+			builder = builder.Where(pred)
+		}
+	}
+	return columns, builder
+}
+
+// WithScope returns a new store that adds the given condition to all the
+// queries of the table of the given schema it runs, along with the default
+// conditions the store already has, such as `tenant_id = ?` or
+// `deleted_at IS NULL`. The conditions are added when the queries are
+// compiled, so they also filter the records of their 1:N relationships, but
+// not the ones of 1:1 relationships. Records are still inserted, updated and
+// deleted by their primary key. Unscoped returns a store without them.
+func (s *Store) WithScope(schema Schema, cond Condition) *Store {
+	store := s.clone()
+	store.scopes = s.scopes.with(schema, cond)
+	return store.init()
+}
+
+// Unscoped returns a new store without the default conditions added to its
+// queries with WithScope.
+func (s *Store) Unscoped() *Store {
+	store := s.clone()
+	store.scopes = nil
+	return store.init()
+}
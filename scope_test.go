@@ -0,0 +1,70 @@
+package kallax
+
+import (
+	"bytes"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScopesCompile(t *testing.T) {
+	r := require.New(t)
+	var sc scopes
+	sc = sc.with(ModelSchema, Eq(f("name"), "foo"))
+	scoped := sc.with(ModelSchema, Gt(f("age"), 1))
+	r.Len(sc[ModelSchema.Table()], 1)
+
+	q := NewBaseQuery(ModelSchema)
+	q.Where(Eq(f("email"), "foo@bar.baz"))
+	_, builder := scoped.compile(q)
+	query, args, err := builder.ToSql()
+	r.NoError(err)
+	r.Equal("SELECT __model.id, __model.name, __model.email, __model.age FROM model __model WHERE __model.email = $1 AND __model.name = $2 AND __model.age > $3", query)
+	r.Equal([]interface{}{"foo@bar.baz", "foo", 1}, args)
+
+	_, builder = scoped.compile(NewBaseQuery(RelSchema.WithAlias("rels")))
+	query, _, err = builder.ToSql()
+	r.NoError(err)
+	r.Equal("SELECT __rel_rels.id, __rel_rels.model_id, __rel_rels.foo FROM rel __rel_rels", query)
+}
+
+func TestStore_WithScope(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	recordedQueries = nil
+	store := NewStore(db).WithScope(ModelSchema, Eq(f("name"), "foo"))
+	rs, err := store.Find(NewBaseQuery(ModelSchema))
+	r.NoError(err)
+	r.NoError(rs.Close())
+	_, err = store.Count(NewBaseQuery(ModelSchema))
+	r.Equal(sql.ErrNoRows, err)
+	m := newModel("foo", "foo@bar.baz", 1)
+	m.ID = 1
+	r.Equal(ErrNotFound, store.Reload(ModelSchema, m))
+	_, err = store.Export(NewBaseQuery(ModelSchema), new(bytes.Buffer), CSV)
+	r.NoError(err)
+	r.NoError(store.Transaction(func(s *Store) error {
+		rs, err := s.Find(NewBaseQuery(ModelSchema))
+		if err != nil {
+			return err
+		}
+		return rs.Close()
+	}))
+
+	rs, err = store.Unscoped().Find(NewBaseQuery(ModelSchema))
+	r.NoError(err)
+	r.NoError(rs.Close())
+
+	r.Equal([]string{
+		"SELECT __model.id, __model.name, __model.email, __model.age FROM model __model WHERE __model.name = $1",
+		"SELECT COUNT(*) FROM model __model WHERE __model.name = $1",
+		"SELECT __model.id, __model.name, __model.email, __model.age FROM model __model WHERE __model.id = $1 AND __model.name = $2",
+		"SELECT __model.id::text, __model.name::text, __model.email::text, __model.age::text FROM model __model WHERE __model.name = $1",
+		"SELECT __model.id, __model.name, __model.email, __model.age FROM model __model WHERE __model.name = $1",
+		"SELECT __model.id, __model.name, __model.email, __model.age FROM model __model",
+	}, recordedQueries)
+}
@@ -0,0 +1,183 @@
+package kallax
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// DefaultStatementCacheSize is the maximum number of prepared statements
+// cached by the stores returned by NewStore.
+const DefaultStatementCacheSize = 1000
+
+// stmtCache is a cache of prepared statements by their SQL, which closes the
+// least recently used statement when it's full. It is shared by all the
+// copies of a store, except the ones holding a transaction, which prepare
+// their statements in it.
+type stmtCache struct {
+	mu    sync.Mutex
+	size  int
+	lru   *list.List
+	stmts map[string]*list.Element
+	// replicas are the caches of the statements prepared on the replicas of
+	// the stores, which have the same size.
+	replicas map[*sql.DB]*stmtCache
+}
+
+// cachedStmt is a statement of a cache, which is closed once it has been
+// evicted and is no longer being run.
+type cachedStmt struct {
+	query   string
+	stmt    *sql.Stmt
+	running int
+	evicted bool
+}
+
+func newStmtCache(size int) *stmtCache {
+	return &stmtCache{
+		size:  size,
+		lru:   list.New(),
+		stmts: make(map[string]*list.Element),
+	}
+}
+
+// empty returns a new empty cache with the same size.
+func (c *stmtCache) empty() *stmtCache {
+	if c == nil {
+		return nil
+	}
+	return newStmtCache(c.size)
+}
+
+// replica returns the cache of the statements prepared on the given replica,
+// creating it if it does not exist yet.
+func (c *stmtCache) replica(db *sql.DB) *stmtCache {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.replicas == nil {
+		c.replicas = make(map[*sql.DB]*stmtCache)
+	}
+
+	cache, ok := c.replicas[db]
+	if !ok {
+		cache = newStmtCache(c.size)
+		c.replicas[db] = cache
+	}
+	return cache
+}
+
+// acquire returns the cached statement with the given SQL, preparing it with
+// the given runner if it's not cached. It must be released once it has been
+// run.
+func (c *stmtCache) acquire(ctx context.Context, runner squirrel.DBProxyContext, query string) (*cachedStmt, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.stmts[query]; ok {
+		c.lru.MoveToFront(elem)
+		cs := elem.Value.(*cachedStmt)
+		cs.running++
+		return cs, nil
+	}
+
+	stmt, err := runner.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	cs := &cachedStmt{query: query, stmt: stmt, running: 1}
+	c.stmts[query] = c.lru.PushFront(cs)
+	for c.lru.Len() > c.size {
+		c.evict(c.lru.Back())
+	}
+	return cs, nil
+}
+
+func (c *stmtCache) evict(elem *list.Element) {
+	cs := c.lru.Remove(elem).(*cachedStmt)
+	delete(c.stmts, cs.query)
+	cs.evicted = true
+	if cs.running == 0 {
+		cs.stmt.Close()
+	}
+}
+
+// release releases a statement once it has been run. The rows of queries
+// can be read after, as they are kept open by the database even if the
+// statement is closed.
+func (c *stmtCache) release(cs *cachedStmt) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cs.running--
+	if cs.evicted && cs.running == 0 {
+		cs.stmt.Close()
+	}
+}
+
+// stmtCacheRunner runs all the statements prepared with its runner and
+// cached in its cache.
+type stmtCacheRunner struct {
+	squirrel.DBProxyContext
+	cache *stmtCache
+}
+
+func (r *stmtCacheRunner) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return r.ExecContext(context.Background(), query, args...)
+}
+
+func (r *stmtCacheRunner) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	cs, err := r.cache.acquire(ctx, r.DBProxyContext, query)
+	if err != nil {
+		return nil, err
+	}
+	defer r.cache.release(cs)
+	return cs.stmt.ExecContext(ctx, args...)
+}
+
+func (r *stmtCacheRunner) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return r.QueryContext(context.Background(), query, args...)
+}
+
+func (r *stmtCacheRunner) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	cs, err := r.cache.acquire(ctx, r.DBProxyContext, query)
+	if err != nil {
+		return nil, err
+	}
+	defer r.cache.release(cs)
+	return cs.stmt.QueryContext(ctx, args...)
+}
+
+func (r *stmtCacheRunner) QueryRow(query string, args ...interface{}) squirrel.RowScanner {
+	return r.QueryRowContext(context.Background(), query, args...)
+}
+
+func (r *stmtCacheRunner) QueryRowContext(ctx context.Context, query string, args ...interface{}) squirrel.RowScanner {
+	cs, err := r.cache.acquire(ctx, r.DBProxyContext, query)
+	if err != nil {
+		return errRow{err}
+	}
+	defer r.cache.release(cs)
+	return cs.stmt.QueryRowContext(ctx, args...)
+}
+
+// WithStatementCache returns a new store that caches up to the given number
+// of prepared statements, closing the least recently used one when the cache
+// is full. The cache is shared by the copies of the store. If the given size
+// is zero or negative, statements are not prepared at all, as with
+// DisableCacher, which is required by pools of connections that do not keep
+// the session of the clients, such as PgBouncer in transaction mode.
+func (s *Store) WithStatementCache(size int) *Store {
+	if size <= 0 {
+		return s.DisableCacher()
+	}
+
+	store := s.clone()
+	store.useCacher = true
+	store.stmts = newStmtCache(size)
+	return store.init()
+}
@@ -0,0 +1,62 @@
+package kallax
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreWithStatementCache(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	store := NewStore(db).WithStatementCache(2)
+	recordedQueries = nil
+	for _, query := range []string{"DELETE FROM a", "DELETE FROM b", "DELETE FROM a", "DELETE FROM c", "DELETE FROM b"} {
+		_, err := store.RawExec(query)
+		r.NoError(err)
+	}
+	r.Equal([]string{"DELETE FROM a", "DELETE FROM b", "DELETE FROM c", "DELETE FROM b"}, recordedQueries)
+
+	recordedQueries = nil
+	_, err = store.Debug().RawExec("DELETE FROM c")
+	r.NoError(err)
+	r.NoError(store.Transaction(func(s *Store) error {
+		_, err := s.RawExec("DELETE FROM c")
+		return err
+	}))
+	r.Equal([]string{"DELETE FROM c"}, recordedQueries)
+
+	r.False(store.WithStatementCache(0).useCacher)
+}
+
+func TestStmtCache_Evict(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	ctx := context.Background()
+	cache := newStmtCache(1)
+	runner := &dbRunner{db}
+
+	running, err := cache.acquire(ctx, runner, "DELETE FROM a")
+	r.NoError(err)
+	released, err := cache.acquire(ctx, runner, "DELETE FROM b")
+	r.NoError(err)
+	cache.release(released)
+
+	_, err = cache.acquire(ctx, runner, "DELETE FROM c")
+	r.NoError(err)
+	r.Len(cache.stmts, 1)
+
+	_, err = running.stmt.Exec()
+	r.NoError(err)
+	cache.release(running)
+	_, err = running.stmt.Exec()
+	r.Error(err)
+}
@@ -2,13 +2,16 @@ package kallax
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
 	"log"
+	"reflect"
+	"time"
 
 	"github.com/Masterminds/squirrel"
-	"github.com/lann/builder"
 )
 
 var (
@@ -25,6 +28,9 @@ var (
 	ErrNotWritable = errors.New("kallax: record is not writable")
 	// ErrStop can be returned inside a ForEach callback to stop iteration.
 	ErrStop = errors.New("kallax: stopped ForEach execution")
+	// ErrInvalidBatchSize is returned by ForEachBatch when the size of the
+	// batches is not greater than zero.
+	ErrInvalidBatchSize = errors.New("kallax: the size of the batches must be greater than zero")
 	// ErrInvalidTxCallback is returned when a nil callback is passed.
 	ErrInvalidTxCallback = errors.New("kallax: invalid transaction callback given")
 	// ErrNotFound is returned when a certain entity is not found.
@@ -36,6 +42,9 @@ var (
 	// ErrNoColumns is an error returned when the user tries to insert a model
 	// with no other columns than the autoincrementable primary key.
 	ErrNoColumns = errors.New("kallax: your model does not have any column besides its autoincrementable primary key and cannot be inserted")
+	// ErrNoConflictColumns is returned when an upsert is built without the
+	// columns that identify the conflicting rows.
+	ErrNoConflictColumns = errors.New("kallax: an upsert needs at least one conflict column")
 )
 
 // GenericStorer is a type that contains a generic store and has methods to
@@ -71,18 +80,30 @@ type proxyLogger struct {
 }
 
 func (p *proxyLogger) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return p.ExecContext(context.Background(), query, args...)
+}
+
+func (p *proxyLogger) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
 	p.logger(fmt.Sprintf("kallax: Exec: %s", query), args...)
-	return p.DBProxyContext.Exec(query, args...)
+	return runnerContext(p.DBProxyContext).ExecContext(ctx, query, args...)
 }
 
 func (p *proxyLogger) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return p.QueryContext(context.Background(), query, args...)
+}
+
+func (p *proxyLogger) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
 	p.logger(fmt.Sprintf("kallax: Query: %s", query), args...)
-	return p.DBProxyContext.Query(query, args...)
+	return runnerContext(p.DBProxyContext).QueryContext(ctx, query, args...)
 }
 
 func (p *proxyLogger) QueryRow(query string, args ...interface{}) squirrel.RowScanner {
+	return p.QueryRowContext(context.Background(), query, args...)
+}
+
+func (p *proxyLogger) QueryRowContext(ctx context.Context, query string, args ...interface{}) squirrel.RowScanner {
 	p.logger(fmt.Sprintf("kallax: QueryRow: %s", query), args...)
-	return p.DBProxyContext.QueryRow(query, args...)
+	return runnerContext(p.DBProxyContext).QueryRowContext(ctx, query, args...)
 }
 
 func (p *proxyLogger) Prepare(query string) (*sql.Stmt, error) {
@@ -105,15 +126,31 @@ func (r *dbRunner) QueryRow(query string, args ...interface{}) squirrel.RowScann
 	return r.DB.QueryRow(query, args...)
 }
 
+func (r *dbRunner) QueryRowContext(ctx context.Context, query string, args ...interface{}) squirrel.RowScanner {
+	return r.DB.QueryRowContext(ctx, query, args...)
+}
+
 // txRunner does the analogous for sql.Tx
 type txRunner struct {
 	*sql.Tx
+	// driver is the driver of the database of the transaction.
+	driver Driver
+	// savepoints is the number of savepoints created by the nested
+	// transactions being run.
+	savepoints int
+	// events are the events of the records written in the transaction,
+	// which are published once it is committed.
+	events []*RecordEvent
 }
 
 func (r *txRunner) QueryRow(query string, args ...interface{}) squirrel.RowScanner {
 	return r.Tx.QueryRow(query, args...)
 }
 
+func (r *txRunner) QueryRowContext(ctx context.Context, query string, args ...interface{}) squirrel.RowScanner {
+	return r.Tx.QueryRowContext(ctx, query, args...)
+}
+
 // Store is a structure capable of retrieving records from a concrete table in
 // the database.
 type Store struct {
@@ -121,29 +158,124 @@ type Store struct {
 	runner    squirrel.DBProxyContext
 	useCacher bool
 	logger    LoggerFunc
+	loc       *time.Location
+	dialect   Dialect
+	cache     *QueryCache
+	cacheTTL  time.Duration
+	metrics   MetricsHook
+	guards    []QueryGuard
+	policy    *Policy
+	scopes    scopes
+	ctx       context.Context
+	// stmts are the prepared statements cached by the store, which are
+	// shared by its copies.
+	stmts *stmtCache
+	// middlewares are the middlewares all the statements are run through.
+	middlewares []Middleware
+	// returning are the columns returned by the inserts and updates of the
+	// records by their table, which are scanned back into them.
+	returning map[string][]string
+	// chain is the runner of the store without its context.
+	chain squirrel.DBProxyContext
+	// replicas are the replicas the store reads from, if any.
+	replicas *replicaSet
+	// replicaChains are the runners of the replicas of the store without
+	// its context.
+	replicaChains []squirrel.DBProxyContext
+	// invalidated are the tables invalidated in the cache by a store holding
+	// a transaction, which are invalidated again once it is committed. It is
+	// shared by the stores derived from the one holding the transaction.
+	invalidated *[]string
+	// bus is the event bus the events of the records written by the store
+	// are published to, if any.
+	bus *EventBus
+	// tenant is the tenant whose records are the only ones read and written
+	// by the store, if any.
+	tenant interface{}
 }
 
-// NewStore returns a new Store instance.
+// NewStore returns a new Store instance. The dialect of the store is the one
+// of the database, see DialectOf.
 func NewStore(db *sql.DB) *Store {
 	return (&Store{
 		db:        &dbRunner{db},
 		useCacher: true,
+		stmts:     newStmtCache(DefaultStatementCacheSize),
+		dialect:   DialectOf(db),
 	}).init()
 }
 
 // init initializes the store runner with debugging or caching, and returns itself for chainability
 func (s *Store) init() *Store {
-	s.runner = s.db
+	s.runner = s.chainOf(s.db, nil)
+	s.chain = s.runner
+	if s.ctx != nil {
+		s.runner = &contextBoundRunner{DBProxyContext: s.chain, ctx: s.ctx}
+	}
 
-	if s.useCacher {
-		s.runner = squirrel.NewStmtCacher(s.db)
+	s.replicaChains = nil
+	if s.replicas != nil {
+		for _, db := range s.replicas.dbs {
+			s.replicaChains = append(s.replicaChains, s.chainOf(&dbRunner{db}, db))
+		}
+	}
+
+	return s
+}
+
+// clone returns a copy of the store with all its fields, to be changed by
+// the methods returning new stores, which must initialize its runners with
+// init once it's changed.
+func (s *Store) clone() *Store {
+	store := *s
+	return &store
+}
+
+// chainOf returns the runner of the statements of the store run with the
+// given database, which is the given replica of the store if it's not nil.
+func (s *Store) chainOf(db squirrel.DBProxyContext, replica *sql.DB) squirrel.DBProxyContext {
+	runner := db
+
+	// statements are not cached when connections are acquired to measure
+	// the metrics, as they are prepared on the whole database
+	_, inTx := db.(*txRunner)
+	if s.useCacher && (s.metrics == nil || inTx) {
+		if s.stmts == nil {
+			s.stmts = newStmtCache(DefaultStatementCacheSize)
+		}
+
+		stmts := s.stmts
+		if replica != nil {
+			stmts = s.stmts.replica(replica)
+		}
+		runner = &stmtCacheRunner{DBProxyContext: db, cache: stmts}
+	}
+
+	if s.metrics != nil {
+		runner = newMetricsRunner(db, runner, s.metrics)
+	}
+
+	if s.policy != nil {
+		runner = newPolicyRunner(runner, s.policy, s.Dialect(), inTx)
 	}
 
 	if s.logger != nil {
-		s.runner = &proxyLogger{logger: s.logger, DBProxyContext: s.runner}
+		runner = &proxyLogger{logger: s.logger, DBProxyContext: runner}
 	}
 
-	return s
+	if rewrites(s.dialect) {
+		runner = &dialectRunner{dialect: s.dialect, DBProxyContext: runner}
+	}
+
+	if len(s.guards) > 0 {
+		runner = &guardRunner{DBProxyContext: runner, dialect: s.Dialect(), guards: s.guards}
+	}
+
+	if len(s.middlewares) > 0 {
+		runner = newMiddlewareRunner(runner, s.middlewares)
+	}
+
+	return runner
 }
 
 // Debug returns a new store that will print all SQL statements to stdout using
@@ -155,20 +287,68 @@ func (s *Store) Debug() *Store {
 // DebugWith returns a new store that will print all SQL statements using the
 // given logger function.
 func (s *Store) DebugWith(logger LoggerFunc) *Store {
-	return (&Store{
-		db:        s.db,
-		useCacher: s.useCacher,
-		logger:    logger,
-	}).init()
+	store := s.clone()
+	store.logger = logger
+	return store.init()
 }
 
 // DisableCacher returns a new store with prepared statements turned off, which can be useful in some scenarios.
 func (s *Store) DisableCacher() *Store {
-	return (&Store{
-		db:        s.db,
-		logger:    s.logger,
-		useCacher: false,
-	}).init()
+	store := s.clone()
+	store.useCacher = false
+	return store.init()
+}
+
+// WithLocation returns a new store that normalizes all the times it writes
+// and scans to the given location, regardless of the time zone of the server
+// or the database session. Times in columns with time zone are converted to
+// the location, and times in columns without time zone are interpreted as
+// being in the location, so they are stored and retrieved with the same wall
+// clock. Usually, the location will be time.UTC.
+func (s *Store) WithLocation(loc *time.Location) *Store {
+	store := s.clone()
+	store.loc = loc
+	return store.init()
+}
+
+// WithDialect returns a new store that runs its statements with the given
+// dialect, instead of the one detected from the database driver.
+func (s *Store) WithDialect(dialect Dialect) *Store {
+	store := s.clone()
+	store.dialect = dialect
+	return store.init()
+}
+
+// WithCache returns a new store that caches the rows retrieved by its
+// queries in the given cache for the given time, or until their tables are
+// invalidated if ttl is zero. The rows of queries with 1:N relationships and
+// the ones run inside transactions are never cached. Inserting, updating or
+// deleting records with the store invalidates the cached rows of their
+// table.
+func (s *Store) WithCache(cache *QueryCache, ttl time.Duration) *Store {
+	store := s.clone()
+	store.cache = cache
+	store.cacheTTL = ttl
+	return store.init()
+}
+
+// Dialect returns the dialect of the store.
+func (s *Store) Dialect() Dialect {
+	if s.dialect == nil {
+		return Postgres
+	}
+	return s.dialect
+}
+
+// Driver returns the driver of the database of the store, see DriverOf.
+func (s *Store) Driver() Driver {
+	switch db := s.db.(type) {
+	case *dbRunner:
+		return DriverOf(db.DB)
+	case *txRunner:
+		return db.driver
+	}
+	return PQ
 }
 
 // Insert insert the given record in the table, returns error if no-new
@@ -178,6 +358,100 @@ func (s *Store) Insert(schema Schema, record Record) error {
 		return ErrNonNewDocument
 	}
 
+	if err := s.bindTenant(schema, record); err != nil {
+		return err
+	}
+
+	returnedCols, err := s.insertReturningColumns(schema, record)
+	if err != nil {
+		return err
+	}
+
+	returning := s.Dialect().Supports(FeatureReturning)
+	autoincr := schema.isPrimaryKeyAutoIncrementable()
+	query, values, err := insertStatement(schema, record, returning)
+	if err != nil {
+		return err
+	}
+	query = appendReturning(query, returning && autoincr, returnedCols)
+
+	if s.loc != nil {
+		valuesInLocation(values, s.loc)
+	}
+
+	if autoincr && !returning {
+		err = s.insertLastID(schema, record, query, values)
+	} else if autoincr || len(returnedCols) > 0 {
+		var addrs []interface{}
+		addrs, err = returnedAddresses(schema, record, autoincr, returnedCols)
+		if err != nil {
+			return err
+		}
+		_, err = s.queryReturned(query, values, addrs)
+	} else {
+		_, err = s.runner.Exec(query, values...)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	record.setWritable(true)
+	record.setPersisted()
+	snapshot(record, ColumnNames(schema.Columns()), true)
+	s.invalidate(schema.Table())
+	s.emit(ChangeInsert, schema, record)
+	return nil
+}
+
+// insertLastID runs the given insert statement and sets the primary key of
+// the record to the ID of the inserted row reported by the database, for the
+// dialects that do not support returning it from the statement.
+func (s *Store) insertLastID(schema Schema, record Record, query string, values []interface{}) error {
+	pk, err := record.ColumnAddress(schema.ID().String())
+	if err != nil {
+		return err
+	}
+
+	result, err := s.runner.Exec(query, values...)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	return setLastInsertID(pk, id)
+}
+
+// setLastInsertID sets the given primary key to the given ID of an inserted
+// row.
+func setLastInsertID(pk interface{}, id int64) error {
+	switch pk := pk.(type) {
+	case *int64:
+		*pk = id
+	case sql.Scanner:
+		return pk.Scan(id)
+	default:
+		return fmt.Errorf("kallax: cannot set primary key of type %T, it must be an int64 or implement sql.Scanner", pk)
+	}
+	return nil
+}
+
+// InsertStatement returns the SQL statement, and its arguments, run by Insert
+// to insert the given record. If the primary key is auto-incrementable, the
+// statement returns it. The columns with a default value that are zero in
+// the record are left out, see BaseSchema.WithDefaults.
+func InsertStatement(schema Schema, record Record) (string, []interface{}, error) {
+	return insertStatement(schema, record, true)
+}
+
+// insertStatement returns the statement that inserts the given record. If
+// returning is true and the primary key is auto-incrementable, the statement
+// returns it.
+func insertStatement(schema Schema, record Record, returning bool) (string, []interface{}, error) {
 	cols := ColumnNames(schema.Columns())
 	if schema.isPrimaryKeyAutoIncrementable() {
 		// we have to remove the pk from the list, in case the
@@ -186,13 +460,28 @@ func (s *Store) Insert(schema Schema, record Record) error {
 		cols = cols[1:]
 	}
 
+	defaulted, err := defaultedColumns(schema, record)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(defaulted) > 0 {
+		inserted := make([]string, 0, len(cols))
+		for _, col := range cols {
+			if !containsString(defaulted, col) {
+				inserted = append(inserted, col)
+			}
+		}
+		cols = inserted
+	}
+
 	if len(cols) == 0 {
-		return ErrNoColumns
+		return "", nil, ErrNoColumns
 	}
 
 	values, cols, err := RecordValues(record, cols...)
 	if err != nil {
-		return err
+		return "", nil, err
 	}
 
 	virtualCols, virtualColValues := virtualColumns(record, cols)
@@ -220,43 +509,174 @@ func (s *Store) Insert(schema Schema, record Record) error {
 	query.WriteString(valBuf.String())
 	query.WriteString(")")
 
-	if schema.isPrimaryKeyAutoIncrementable() {
-		var pk interface{}
-		pk, err = record.ColumnAddress(schema.ID().String())
+	if returning && schema.isPrimaryKeyAutoIncrementable() {
+		query.WriteString(fmt.Sprintf(" RETURNING %s", schema.ID().String()))
+	}
+
+	return query.String(), values, nil
+}
+
+// defaultedColumns returns the columns with a default value of the given
+// schema that are zero in the given record, which are left out of its
+// insert so the database gives them their default values.
+func defaultedColumns(schema Schema, record Record) ([]string, error) {
+	var cols []string
+	for _, col := range schema.DefaultFields() {
+		v, err := record.Value(col.String())
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		query.WriteString(fmt.Sprintf(" RETURNING %s", schema.ID().String()))
-		//err = s.runner.QueryRow(query.String(), values...).Scan(pk)
-		rows, err := s.runner.Query(query.String(), values...)
+		if isZeroValue(v) {
+			cols = append(cols, col.String())
+		}
+	}
+	return cols, nil
+}
+
+// isZeroValue reports whether the given value of a column is the zero value
+// of its type or, if it's a driver.Valuer, is stored as one.
+func isZeroValue(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || rv.IsZero() {
+		return true
+	}
+
+	if valuer, ok := v.(driver.Valuer); ok {
+		dv, err := valuer.Value()
 		if err != nil {
+			return false
+		}
+
+		rv = reflect.ValueOf(dv)
+		return !rv.IsValid() || rv.IsZero()
+	}
+	return false
+}
+
+// UpsertStatement returns the SQL statement, and its arguments, that inserts
+// the given record in the given dialect or, if it conflicts with an existing
+// row in the given columns, updates the given columns of that row instead.
+// If no columns to update are given, the existing row is left as is. If the
+// primary key is auto-incrementable and the dialect supports it, the
+// statement returns it.
+func UpsertStatement(dialect Dialect, schema Schema, record Record, conflict []SchemaField, update ...SchemaField) (string, []interface{}, error) {
+	if len(conflict) == 0 {
+		return "", nil, ErrNoConflictColumns
+	}
+
+	query, values, err := insertStatement(schema, record, false)
+	if err != nil {
+		return "", nil, err
+	}
+
+	query += dialect.OnConflictUpdate(ColumnNames(conflict), ColumnNames(update))
+	if dialect.Supports(FeatureReturning) && schema.isPrimaryKeyAutoIncrementable() {
+		query += fmt.Sprintf(" RETURNING %s", schema.ID().String())
+	}
+
+	return query, values, nil
+}
+
+// Upsert inserts the given record in the table or, if it conflicts with an
+// existing row in the given columns, updates the given columns of that row
+// instead, in a single statement. If no columns to update are given, the
+// existing row is left as is. If the primary key is auto-incrementable, it
+// is set to the one of the inserted or updated row, if the database returns
+// it. Otherwise, such as when the existing row is left as is, the record is
+// not marked as persisted, as the row it belongs to is unknown.
+func (s *Store) Upsert(schema Schema, record Record, conflict []SchemaField, update ...SchemaField) error {
+	if col := schema.TenantField(); s.tenant != nil && col != nil {
+		if !containsString(ColumnNames(conflict), col.String()) {
+			return fmt.Errorf("kallax: the upserts of table %s of a store with a tenant must conflict in its tenant column %s", schema.Table(), col)
+		}
+
+		if err := s.bindTenant(schema, record); err != nil {
 			return err
 		}
-		if rows.Next() {
-			err = rows.Scan(pk)
-			rows.Close()
-			if err != nil {
-				return err
-			}
+	}
+
+	returnedCols, err := s.insertReturningColumns(schema, record)
+	if err != nil {
+		return err
+	}
+
+	dialect := s.Dialect()
+	autoincr := schema.isPrimaryKeyAutoIncrementable()
+	query, values, err := UpsertStatement(dialect, schema, record, conflict, update...)
+	if err != nil {
+		return err
+	}
+	query = appendReturning(query, dialect.Supports(FeatureReturning) && autoincr, returnedCols)
+
+	if s.loc != nil {
+		valuesInLocation(values, s.loc)
+	}
+
+	persisted := true
+	if autoincr {
+		persisted, err = s.upsertID(dialect, schema, record, query, values, returnedCols)
+	} else if len(returnedCols) > 0 {
+		var addrs []interface{}
+		addrs, err = returnedAddresses(schema, record, false, returnedCols)
+		if err != nil {
+			return err
 		}
+		_, err = s.queryReturned(query, values, addrs)
 	} else {
-		_, err = s.runner.Exec(query.String(), values...)
+		_, err = s.runner.Exec(query, values...)
 	}
 
 	if err != nil {
 		return err
 	}
 
-	record.setWritable(true)
-	record.setPersisted()
+	s.invalidate(schema.Table())
+	if persisted {
+		record.setWritable(true)
+		record.setPersisted()
+		snapshot(record, ColumnNames(schema.Columns()), true)
+	}
+	s.emit(ChangeUpsert, schema, record)
 	return nil
 }
 
+// upsertID runs the given upsert statement and sets the auto-incrementable
+// primary key of the record, and the given returned columns, to the ones of
+// the row inserted or updated by it. It returns whether the database
+// reported the primary key.
+func (s *Store) upsertID(dialect Dialect, schema Schema, record Record, query string, values []interface{}, returnedCols []string) (bool, error) {
+	addrs, err := returnedAddresses(schema, record, true, returnedCols)
+	if err != nil {
+		return false, err
+	}
+
+	if !dialect.Supports(FeatureReturning) {
+		pk := addrs[0]
+		result, err := s.runner.Exec(query, values...)
+		if err != nil {
+			return false, err
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil || id == 0 {
+			return false, err
+		}
+		return true, setLastInsertID(pk, id)
+	}
+
+	return s.queryReturned(query, values, addrs)
+}
+
 // Update updates the given fields of a record in the table. All fields are
 // updated if no fields are provided. For an update to take place, the record is
 // required to have a non-empty ID and not to be a new record.
 // Returns the number of updated rows and an error, if any.
+// If the updates of the records of the schema are optimistically locked, the
+// record is only updated if its version is still the one in the database,
+// and its version is incremented. Otherwise, ErrStaleObject is returned.
+// Only the retrieved fields of partial records are updated, and
+// ErrNotFetched is returned if any of the given fields was not retrieved.
 func (s *Store) Update(schema Schema, record Record, cols ...SchemaField) (int64, error) {
 	if !record.IsWritable() {
 		return 0, ErrNotWritable
@@ -270,20 +690,123 @@ func (s *Store) Update(schema Schema, record Record, cols ...SchemaField) (int64
 		return 0, ErrEmptyID
 	}
 
-	if len(cols) == 0 {
-		cols = schema.Columns()
+	if err := s.bindTenant(schema, record); err != nil {
+		return 0, err
+	}
+
+	cols, err := updatedColumns(schema, record, cols)
+	if err != nil {
+		return 0, err
+	}
+
+	returnedCols, err := s.returningColumns(schema)
+	if err != nil {
+		return 0, err
+	}
+
+	query, values, err := UpdateStatement(schema, record, cols...)
+	if err != nil {
+		return 0, err
+	}
+	query, values = s.tenantStatement(schema, query, values)
+
+	if s.loc != nil {
+		valuesInLocation(values, s.loc)
+	}
+
+	cnt, err := s.update(schema, record, appendReturning(query, false, returnedCols), values, returnedCols)
+	if err != nil {
+		return 0, err
+	}
+
+	lock := schema.LockField()
+	if cnt == 0 {
+		if lock != nil {
+			return 0, ErrStaleObject
+		}
+		return 0, ErrNoRowUpdate
+	}
+
+	names := append(ColumnNames(cols), returnedCols...)
+	if lock != nil && !containsString(returnedCols, lock.String()) {
+		version, err := lockVersion(schema, record, lock)
+		if err != nil {
+			return 0, err
+		}
+
+		if err := setLockVersion(schema, record, lock, version+1); err != nil {
+			return 0, err
+		}
+		names = append(names, lock.String())
+	}
+
+	snapshot(record, names, false)
+	s.invalidate(schema.Table())
+	s.emit(ChangeUpdate, schema, record)
+	return cnt, nil
+}
+
+// update runs the given update statement of the record and returns the
+// number of updated rows. If any columns are returned by the statement, they
+// are scanned into the record.
+func (s *Store) update(schema Schema, record Record, query string, values []interface{}, returnedCols []string) (int64, error) {
+	if len(returnedCols) == 0 {
+		result, err := s.runner.Exec(query, values...)
+		if err != nil {
+			return 0, err
+		}
+		return result.RowsAffected()
+	}
+
+	addrs, err := returnedAddresses(schema, record, false, returnedCols)
+	if err != nil {
+		return 0, err
+	}
+
+	updated, err := s.queryReturned(query, values, addrs)
+	if err != nil || !updated {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// UpdateStatement returns the SQL statement, and its arguments, run by Update
+// to update the given fields of a record. All fields are updated if no fields
+// are provided, or only the retrieved ones if the record is partial. The last
+// arguments are the values of the primary key of the record, followed by its
+// version if the updates of the records of the schema are optimistically
+// locked.
+func UpdateStatement(schema Schema, record Record, cols ...SchemaField) (string, []interface{}, error) {
+	cols, err := updatedColumns(schema, record, cols)
+	if err != nil {
+		return "", nil, err
+	}
+
+	lock := schema.LockField()
+	var version int64
+	if lock != nil {
+		if version, err = lockVersion(schema, record, lock); err != nil {
+			return "", nil, err
+		}
+		set := columnSet(cols)
+		set.remove(lock)
+		cols = set
 	}
 
 	// remove the ID from there
 	columnNames := ColumnNames(cols)
 	values, columnNames, err := RecordValues(record, columnNames...)
 	if err != nil {
-		return 0, err
+		return "", nil, err
 	}
 
 	virtualCols, virtualColValues := virtualColumns(record, columnNames)
 	columnNames = append(columnNames, virtualCols...)
 	values = append(values, virtualColValues...)
+	if lock != nil {
+		columnNames = append(columnNames, lock.String())
+		values = append(values, version+1)
+	}
 
 	var query bytes.Buffer
 	query.WriteString("UPDATE ")
@@ -298,25 +821,14 @@ func (s *Store) Update(schema Schema, record Record, cols ...SchemaField) (int64
 		query.WriteString(fmt.Sprintf("$%d", i+1))
 	}
 	query.WriteString(" WHERE ")
-	query.WriteString(schema.ID().String())
-	query.WriteRune('=')
-	query.WriteString(fmt.Sprintf("$%d", len(columnNames)+1))
+	writePrimaryKeyCond(&query, schema, len(columnNames)+1)
 
-	result, err := s.runner.Exec(query.String(), append(values, record.GetID())...)
-	if err != nil {
-		return 0, err
+	values = append(values, primaryKeyValues(schema, record)...)
+	if lock != nil {
+		query.WriteString(fmt.Sprintf(" AND %s=$%d", lock, len(values)+1))
+		values = append(values, version)
 	}
-
-	cnt, err := result.RowsAffected()
-	if err != nil {
-		return 0, err
-	}
-
-	if cnt == 0 {
-		return 0, ErrNoRowUpdate
-	}
-
-	return cnt, nil
+	return query.String(), values, nil
 }
 
 // Save inserts or updates the given record in the table.
@@ -334,21 +846,278 @@ func (s *Store) Save(schema Schema, record Record) (updated bool, err error) {
 }
 
 // Delete removes the record from the table. A non-new record with non-empty
-// ID is required.
+// ID is required. If the records of the schema are soft deleted, the record
+// is not removed, but its soft delete column is set to the current time;
+// HardDelete removes it anyway.
 func (s *Store) Delete(schema Schema, record Record) error {
+	if col := schema.SoftDeleteField(); col != nil {
+		return s.softDelete(schema, record, col)
+	}
+	return s.HardDelete(schema, record)
+}
+
+// HardDelete removes the record from the table, even if the records of the
+// schema are soft deleted. A non-new record with non-empty ID is required.
+func (s *Store) HardDelete(schema Schema, record Record) error {
 	if record.GetID().IsEmpty() {
 		return ErrEmptyID
 	}
 
+	query, args := DeleteStatement(schema, record)
+	query, args = s.tenantStatement(schema, query, args)
+	if _, err := s.runner.Exec(query, args...); err != nil {
+		return err
+	}
+
+	s.invalidate(schema.Table())
+	s.emit(ChangeDelete, schema, record)
+	return nil
+}
+
+// softDelete sets the given soft delete column of the record to the current
+// time, both in the table and in the record.
+func (s *Store) softDelete(schema Schema, record Record, col SchemaField) error {
+	if record.GetID().IsEmpty() {
+		return ErrEmptyID
+	}
+
+	deletedAt := s.deletionTime()
+	query, args := softDeleteStatement(schema, record, col, deletedAt)
+	query, args = s.tenantStatement(schema, query, args)
+	if _, err := s.runner.Exec(query, args...); err != nil {
+		return err
+	}
+
+	if err := setDeletionTime(schema, record, col, deletedAt); err != nil {
+		return err
+	}
+
+	s.invalidate(schema.Table())
+	s.emit(ChangeDelete, schema, record)
+	return nil
+}
+
+// deletionTime returns the time the records soft deleted now are deleted
+// at, in the location of the store, if any.
+func (s *Store) deletionTime() time.Time {
+	t := time.Now().Truncate(time.Microsecond)
+	if s.loc != nil {
+		t = t.In(s.loc)
+	}
+	return t
+}
+
+// softDeleteStatement returns the SQL statement, and its arguments, that
+// sets the given soft delete column of the given record to the given time.
+func softDeleteStatement(schema Schema, record Record, col SchemaField, deletedAt time.Time) (string, []interface{}) {
+	var query bytes.Buffer
+	query.WriteString("UPDATE ")
+	query.WriteString(schema.Table())
+	query.WriteString(" SET ")
+	query.WriteString(col.String())
+	query.WriteString("=$1 WHERE ")
+	writePrimaryKeyCond(&query, schema, 2)
+
+	return query.String(), append([]interface{}{deletedAt}, primaryKeyValues(schema, record)...)
+}
+
+// setDeletionTime sets the given soft delete column of the given record to
+// the given time.
+func setDeletionTime(schema Schema, record Record, col SchemaField, deletedAt time.Time) error {
+	ptr, err := record.ColumnAddress(col.String())
+	if err != nil {
+		return err
+	}
+
+	scanner, ok := ptr.(sql.Scanner)
+	if !ok {
+		return fmt.Errorf("kallax: soft delete column %s of table %s is not a nullable timestamp", col, schema.Table())
+	}
+
+	if err := scanner.Scan(deletedAt); err != nil {
+		return err
+	}
+
+	snapshot(record, []string{col.String()}, false)
+	return nil
+}
+
+// SoftDeleteStatement returns the SQL statement, and its arguments, run by
+// Delete to soft delete the given record at the given time. The records of
+// the schema must be soft deleted.
+func SoftDeleteStatement(schema Schema, record Record, deletedAt time.Time) (string, []interface{}) {
+	return softDeleteStatement(schema, record, schema.SoftDeleteField(), deletedAt)
+}
+
+// DeleteStatement returns the SQL statement, and its arguments, run by
+// HardDelete to remove the given record.
+func DeleteStatement(schema Schema, record Record) (string, []interface{}) {
 	var query bytes.Buffer
 	query.WriteString("DELETE FROM ")
 	query.WriteString(schema.Table())
 	query.WriteString(" WHERE ")
-	query.WriteString(schema.ID().String())
-	query.WriteString("=$1")
+	writePrimaryKeyCond(&query, schema, 1)
 
-	_, err := s.runner.Exec(query.String(), record.GetID())
-	return err
+	return query.String(), primaryKeyValues(schema, record)
+}
+
+// writePrimaryKeyCond writes the condition that matches the rows by the
+// columns of the primary key of the given schema, numbering their
+// placeholders from the given one.
+func writePrimaryKeyCond(query *bytes.Buffer, schema Schema, placeholder int) {
+	for i, col := range schema.PrimaryKey() {
+		if i != 0 {
+			query.WriteString(" AND ")
+		}
+		query.WriteString(col.String())
+		query.WriteString(fmt.Sprintf("=$%d", placeholder+i))
+	}
+}
+
+// primaryKeyValues returns the values of the primary key of the given record,
+// in the same order as the columns of the primary key of the given schema.
+func primaryKeyValues(schema Schema, record Record) []interface{} {
+	if r, ok := record.(CompositeIdentifiable); ok && len(schema.PrimaryKey()) > 1 {
+		return r.GetPrimaryKey()
+	}
+	return []interface{}{record.GetID()}
+}
+
+// primaryKeyCond returns the condition that matches the row of the given
+// record by the columns of the primary key of the given schema.
+func primaryKeyCond(schema Schema, record Record) Condition {
+	cols := schema.PrimaryKey()
+	if len(cols) == 1 {
+		return Eq(schema.ID(), record.GetID())
+	}
+
+	values := primaryKeyValues(schema, record)
+	conds := make([]Condition, len(cols))
+	for i, col := range cols {
+		conds[i] = Eq(col, values[i])
+	}
+	return And(conds...)
+}
+
+// AddRelations links the given related records to the record through the
+// join table of the many to many relationship in the given field of the
+// schema. Links that already exist are left as is. Records with empty IDs are
+// not allowed.
+func (s *Store) AddRelations(schema Schema, record Record, field string, related ...Record) error {
+	fk, err := joinTableForeignKey(schema, field)
+	if err != nil {
+		return err
+	}
+
+	if record.GetID().IsEmpty() {
+		return ErrEmptyID
+	}
+
+	if len(related) == 0 {
+		return nil
+	}
+
+	var query bytes.Buffer
+	query.WriteString("INSERT INTO ")
+	query.WriteString(fk.Through.Table)
+	query.WriteString(fmt.Sprintf(" (%s, %s) VALUES ", fk, fk.Through.References))
+
+	values := make([]interface{}, 0, 2*len(related))
+	for i, r := range related {
+		if r.GetID().IsEmpty() {
+			return ErrEmptyID
+		}
+
+		if i != 0 {
+			query.WriteString(", ")
+		}
+		query.WriteString(fmt.Sprintf("($%d, $%d)", 2*i+1, 2*i+2))
+		values = append(values, record.GetID(), r.GetID())
+	}
+	query.WriteString(s.Dialect().OnConflictUpdate(
+		[]string{fk.String(), fk.Through.References.String()},
+		nil,
+	))
+
+	if _, err := s.runner.Exec(query.String(), values...); err != nil {
+		return err
+	}
+
+	s.invalidate(fk.Through.Table)
+	return nil
+}
+
+// RemoveRelations unlinks the given related records from the record, removing
+// their rows from the join table of the many to many relationship in the
+// given field of the schema. The related records themselves are not deleted.
+func (s *Store) RemoveRelations(schema Schema, record Record, field string, related ...Record) error {
+	fk, err := joinTableForeignKey(schema, field)
+	if err != nil {
+		return err
+	}
+
+	if record.GetID().IsEmpty() {
+		return ErrEmptyID
+	}
+
+	if len(related) == 0 {
+		return nil
+	}
+
+	var query bytes.Buffer
+	query.WriteString(fmt.Sprintf("DELETE FROM %s WHERE %s=$1 AND %s IN (", fk.Through.Table, fk, fk.Through.References))
+	values := []interface{}{record.GetID()}
+	for i, r := range related {
+		if r.GetID().IsEmpty() {
+			return ErrEmptyID
+		}
+
+		if i != 0 {
+			query.WriteString(", ")
+		}
+		query.WriteString(fmt.Sprintf("$%d", i+2))
+		values = append(values, r.GetID())
+	}
+	query.WriteString(")")
+
+	if _, err := s.runner.Exec(query.String(), values...); err != nil {
+		return err
+	}
+
+	s.invalidate(fk.Through.Table)
+	return nil
+}
+
+// ClearRelations unlinks all the records related to the record, removing
+// its rows from the join table of the many to many relationship in the given
+// field of the schema. The related records themselves are not deleted.
+func (s *Store) ClearRelations(schema Schema, record Record, field string) error {
+	fk, err := joinTableForeignKey(schema, field)
+	if err != nil {
+		return err
+	}
+
+	if record.GetID().IsEmpty() {
+		return ErrEmptyID
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s=$1", fk.Through.Table, fk)
+	if _, err := s.runner.Exec(query, record.GetID()); err != nil {
+		return err
+	}
+
+	s.invalidate(fk.Through.Table)
+	return nil
+}
+
+// joinTableForeignKey returns the foreign key of the many to many
+// relationship in the given field of the schema.
+func joinTableForeignKey(schema Schema, field string) (*ForeignKey, error) {
+	fk, ok := schema.ForeignKey(field)
+	if !ok || fk.Through == nil {
+		return nil, fmt.Errorf("kallax: cannot find join table on field %s for table %s", field, schema.Table())
+	}
+	return fk, nil
 }
 
 // RawQuery performs a raw SQL query with the given parameters and returns a
@@ -361,7 +1130,9 @@ func (s *Store) RawQuery(sql string, params ...interface{}) (ResultSet, error) {
 		return nil, err
 	}
 
-	return NewResultSet(rows, true, nil), nil
+	rs := NewResultSet(rows, true, nil)
+	rs.loc = s.loc
+	return rs, nil
 }
 
 // RawExec executes a raw SQL query with the given parameters and returns
@@ -375,14 +1146,18 @@ func (s *Store) RawExec(sql string, params ...interface{}) (int64, error) {
 	return result.RowsAffected()
 }
 
-// Find performs a query and returns a result set with the results.
+// Find performs a query and returns a result set with the results. If the
+// store has replicas, the query is run in one of them picked by its balancer,
+// unless it locks the rows or it has the ForcePrimary option.
 func (s *Store) Find(q Query) (ResultSet, error) {
 	rels := q.getRelationships()
-	if containsRelationshipOfType(rels, OneToMany) {
-		return NewBatchingResultSet(newBatchQueryRunner(q.Schema(), s.runner, q)), nil
+	if containsRelationshipOfType(rels, OneToMany) || containsRelationshipOfType(rels, ManyToMany) {
+		runner := newBatchQueryRunner(q.Schema(), s.reader(q), q, s.queryScopes())
+		runner.loc = s.loc
+		return NewBatchingResultSet(runner), nil
 	}
 
-	columns, builder := q.compile()
+	columns, builder := s.queryScopes().compile(q)
 	if offset := q.GetOffset(); offset > 0 {
 		builder = builder.Offset(offset)
 	}
@@ -391,17 +1166,20 @@ func (s *Store) Find(q Query) (ResultSet, error) {
 		builder = builder.Limit(limit)
 	}
 
-	rows, err := builder.RunWith(s.runner).Query()
+	rows, err := s.query(q, builder)
 	if err != nil {
 		return nil, err
 	}
 
-	return NewResultSet(
+	rs := NewResultSet(
 		rows,
-		q.isReadOnly(),
+		false,
 		q.getRelationships(),
 		columns...,
-	), nil
+	)
+	rs.partial = q.isPartial()
+	rs.loc = s.loc
+	return rs, nil
 }
 
 // MustFind performs a query and returns a result set with the results.
@@ -415,23 +1193,25 @@ func (s *Store) MustFind(q Query) ResultSet {
 }
 
 // Reload refreshes the record with the data in the database and makes the
-// record writable.
+// record writable. The data is read from one of the replicas of the store, if
+// it has any, so Primary must be used to reload a record just written.
 func (s *Store) Reload(schema Schema, record Record) error {
 	if record.GetID().IsEmpty() {
 		return ErrEmptyID
 	}
 
 	q := NewBaseQuery(schema)
-	q.Where(Eq(schema.ID(), record.GetID()))
+	q.Where(primaryKeyCond(schema, record))
 	q.Limit(1)
-	columns, builder := q.compile()
+	columns, builder := s.queryScopes().compile(q)
 
-	rows, err := builder.RunWith(s.runner).Query()
+	rows, err := builder.RunWith(s.reader(nil)).Query()
 	if err != nil {
 		return err
 	}
 
 	rs := NewResultSet(rows, false, nil, columns...)
+	rs.loc = s.loc
 	if !rs.Next() {
 		return ErrNotFound
 	}
@@ -439,14 +1219,30 @@ func (s *Store) Reload(schema Schema, record Record) error {
 	return rs.Scan(record)
 }
 
-// Count returns the number of rows selected by the given query.
+// Count returns the number of rows selected by the given query. The rows are
+// not locked, even if the query locks them. As with Find, the rows are
+// counted in one of the replicas of the store, if it has any.
 func (s *Store) Count(q Query) (count int64, err error) {
-	_, queryBuilder := q.compile()
-	builder := builder.Set(queryBuilder, "Columns", nil).(squirrel.SelectBuilder)
-	err = builder.Column("COUNT(*)").
-		RunWith(s.runner).
-		QueryRow().
-		Scan(&count)
+	_, queryBuilder := s.queryScopes().compile(q)
+	builder := aggregateBuilder(queryBuilder).Column("COUNT(*)")
+	if s.cache == nil {
+		err = builder.RunWith(s.reader(q)).QueryRow().Scan(&count)
+		return
+	}
+
+	rows, err := s.query(q, builder)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err = rows.Err(); err == nil {
+			err = sql.ErrNoRows
+		}
+		return 0, err
+	}
+	err = rows.Scan(&count)
 	return
 }
 
@@ -466,40 +1262,159 @@ func (s *Store) MustCount(q Query) int64 {
 // The transaction is only open in the store passed as a parameter to the
 // callback.
 // If a transaction is already opened in this store, instead of opening a new
-// one, the other will be reused.
+// one, the callback is run in a savepoint of it, which is rolled back to if
+// the callback returns an error, so only the changes made by the callback are
+// discarded and the error can be handled by the outer transaction.
+// The transaction is rolled back as well if the callback panics or exits the
+// goroutine, e.g. with testing.T.FailNow.
+// If the dialect of the store is a Retrier, such as CockroachDB, and the
+// transaction fails with a retryable error, the callback is run again in a new
+// transaction, so it must not have side effects outside of it.
+// The retries, timeout and circuit breaking of the transactions can be
+// configured with the Transactions policy of WithPolicy.
 func (s *Store) Transaction(callback func(*Store) error) error {
-	var tx *sql.Tx
-	var err error
-	if db, ok := s.db.(*dbRunner); ok {
-		// db is *sql.DB, not *sql.Tx
-		tx, err = db.Begin()
-		if err != nil {
-			return fmt.Errorf("kallax: can't open transaction: %s", err)
+	return s.transactionWithOptions(nil, callback)
+}
+
+// transactionWithOptions runs the given callback as Transaction does, in a
+// new transaction with the given options, if any.
+func (s *Store) transactionWithOptions(opts *TxOptions, callback func(*Store) error) error {
+	if tx, ok := s.db.(*txRunner); ok {
+		if opts != nil && *opts != (TxOptions{}) {
+			return ErrNestedTxOptions
 		}
-	} else {
-		// store is already holding a transaction
+		return s.nestedTransaction(tx, callback)
+	}
+
+	db, ok := s.db.(*dbRunner)
+	if !ok {
 		return callback(s)
 	}
 
-	txStore := (&Store{
-		db:        &txRunner{tx},
-		logger:    s.logger,
-		useCacher: s.useCacher,
-	}).init()
+	policy := s.transactionPolicy()
+	attempts := policy.Attempts
+	if attempts == 0 {
+		attempts = maxTransactionAttempts
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if policy.Breaker.Open() {
+			if err != nil {
+				return err
+			}
+			return ErrCircuitOpen
+		}
 
-	if err := callback(txStore); err != nil {
-		if err := tx.Rollback(); err != nil {
-			return fmt.Errorf("kallax: unable to rollback transaction: %s", err)
+		var cause error
+		cause, err = s.transaction(db, &policy, opts, callback)
+		if err == nil || attempt >= attempts {
+			return err
 		}
 
-		return err
+		if !policy.retryable(s.dialect, cause) && !(policy.Retryable == nil && opts.retriesSerialization() && isSerializationFailure(cause)) {
+			return err
+		}
+
+		policy.retry(attempt+1, cause)
+		if !policy.wait(s.Context(), attempt+1) {
+			return err
+		}
+	}
+}
+
+// maxTransactionAttempts is the number of times a transaction is run if it
+// keeps failing with errors that the dialect of the store considers
+// retryable.
+const maxTransactionAttempts = 10
+
+// transaction runs the given callback in a new transaction of the given
+// database with the given options. It returns the error to return from
+// Transaction and the error that caused it, which is returned by the
+// database or the callback. The transaction is run with the timeout of the
+// given policy, and its result is recorded in the circuit breaker of the
+// policy.
+func (s *Store) transaction(db *dbRunner, policy *OperationPolicy, opts *TxOptions, callback func(*Store) error) (cause, err error) {
+	parent := s.Context()
+	ctx, cancel := policy.context(parent)
+	defer func() {
+		if cause != nil && ctx.Err() == context.DeadlineExceeded {
+			cause = ctx.Err()
+		}
+		policy.Breaker.record(policy.failed(parent, ctx, s.dialect, cause))
+		cancel()
+	}()
+
+	tx, err := db.BeginTx(ctx, opts.sqlOptions())
+	if err != nil {
+		return err, fmt.Errorf("kallax: can't open transaction: %s", err)
+	}
+
+	runner := &txRunner{Tx: tx, driver: DriverOf(db.DB)}
+	txStore := s.clone()
+	txStore.db = runner
+	txStore.stmts = s.stmts.empty()
+	txStore.replicas = nil
+	txStore.invalidated = new([]string)
+	txStore.init()
+
+	var returned bool
+	defer func() {
+		if !returned {
+			tx.Rollback()
+		}
+	}()
+
+	err = callback(txStore)
+	returned = true
+	if err != nil {
+		if rerr := tx.Rollback(); rerr != nil {
+			return err, fmt.Errorf("kallax: unable to rollback transaction: %s", rerr)
+		}
+
+		return err, err
 	}
 
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("kallax: unable to commit transaction: %s", err)
+		return err, fmt.Errorf("kallax: unable to commit transaction: %s", err)
 	}
 
-	return nil
+	if s.cache != nil && len(*txStore.invalidated) > 0 {
+		s.cache.Invalidate(*txStore.invalidated...)
+	}
+	s.bus.publish(runner.events)
+	return nil, nil
+}
+
+// invalidate removes the cached rows of the given tables from the cache of
+// the store, if it has one.
+func (s *Store) invalidate(tables ...string) {
+	if s.cache == nil {
+		return
+	}
+
+	s.cache.Invalidate(tables...)
+	if s.invalidated != nil {
+		*s.invalidated = append(*s.invalidated, tables...)
+	}
+}
+
+// query runs the given select built for the given query, retrieving the rows
+// from the cache of the store if it has one and it is not holding a
+// transaction, or from one of its replicas otherwise, see reader. The rows
+// read from the replicas are cached apart from the ones read from the
+// primary database, so the queries that must read the primary one never get
+// rows that may not have been replicated yet.
+func (s *Store) query(q Query, builder squirrel.SelectBuilder) (*sql.Rows, error) {
+	if _, ok := s.db.(*txRunner); ok || s.cache == nil {
+		return builder.RunWith(s.reader(q)).Query()
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	return s.cache.query(s.reader(q), s.readsReplica(q), queryTables(q), s.cacheTTL, query, args...)
 }
 
 // RecordWithSchema is a structure that contains both a record and its schema.
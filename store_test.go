@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"testing"
+	"time"
 
 	_ "github.com/lib/pq"
 	"github.com/stretchr/testify/require"
@@ -99,6 +100,21 @@ func (s *StoreSuite) TestUpdate() {
 	s.Equal(ErrNotWritable, err)
 }
 
+func (s *StoreSuite) TestUpdate_Returning() {
+	store := s.store.WithReturning(ModelSchema, f("email"))
+	m := newModel("a", "a@a.a", 1)
+	s.NoError(store.Insert(ModelSchema, m))
+
+	_, err := s.store.RawExec("UPDATE model SET email = 'b@b.b' WHERE id = $1", m.ID)
+	s.NoError(err)
+
+	m.Name = "b"
+	_, err = store.Update(ModelSchema, m, f("name"))
+	s.NoError(err)
+	s.Equal("b@b.b", m.Email)
+	s.assertModel(m)
+}
+
 func (s *StoreSuite) TestUpdate_ColumnNotFound() {
 	var m = newModel("a", "a@a.a", 1)
 	s.NoError(s.store.Insert(ModelSchema, m))
@@ -186,6 +202,22 @@ func (s *StoreSuite) TestRawQuery_Transaction() {
 	}))
 }
 
+func (s *StoreSuite) TestWithLocation() {
+	loc := time.FixedZone("kallax", 3*3600)
+	rs, err := s.store.WithLocation(loc).RawQuery(
+		"SELECT '2017-01-02 03:04:05+00'::timestamptz, '2017-01-02 03:04:05'::timestamp",
+	)
+	s.Require().NoError(err)
+	defer rs.Close()
+
+	var tz, notz time.Time
+	s.Require().True(rs.Next())
+	s.NoError(rs.RawScan(&tz, &notz))
+	s.Equal(loc, tz.Location())
+	s.Equal(time.Date(2017, time.January, 2, 6, 4, 5, 0, loc), tz)
+	s.Equal(time.Date(2017, time.January, 2, 3, 4, 5, 0, loc), notz)
+}
+
 func (s *StoreSuite) TestRawQuery_Fail() {
 	rs, err := s.errStore.RawQuery("SELECT name FROM model WHERE age > $1", 1)
 	s.Nil(rs)
@@ -321,6 +353,52 @@ func (s *StoreSuite) TestTransaction() {
 	s.assertCount(2)
 }
 
+func (s *StoreSuite) TestTransaction_NestedRollback() {
+	err := s.store.Transaction(func(store *Store) error {
+		s.NoError(store.Insert(ModelSchema, newModel("Joe", "", 1)))
+
+		err := store.Transaction(func(store *Store) error {
+			s.NoError(store.Insert(ModelSchema, newModel("Anna", "", 1)))
+			return fmt.Errorf("kallax: rollback only this one")
+		})
+		s.Error(err)
+
+		s.NoError(store.Savepoint("foo"))
+		s.NoError(store.Insert(ModelSchema, newModel("Mary", "", 1)))
+		return store.RollbackTo("foo")
+	})
+	s.NoError(err)
+	s.assertCount(1)
+}
+
+func (s *StoreSuite) TestFind_SkipLocked() {
+	s.NoError(s.store.Insert(ModelSchema, newModel("Joe", "", 1)))
+	s.NoError(s.store.Insert(ModelSchema, newModel("Anna", "", 2)))
+
+	claim := func(store *Store) *model {
+		q := NewBaseQuery(ModelSchema)
+		q.Order(Asc(f("age")))
+		q.Limit(1)
+		q.LockForUpdate(SkipLocked)
+		rs, err := store.Find(q)
+		s.NoError(err)
+		s.True(rs.Next())
+		record, err := rs.Get(ModelSchema)
+		s.NoError(err)
+		s.NoError(rs.Close())
+		return record.(*model)
+	}
+
+	err := s.store.Transaction(func(store *Store) error {
+		s.Equal("Joe", claim(store).Name)
+		return s.store.Transaction(func(other *Store) error {
+			s.Equal("Anna", claim(other).Name)
+			return nil
+		})
+	})
+	s.NoError(err)
+}
+
 func (s *StoreSuite) TestTransaction_CantOpen() {
 	err := s.errStore.Transaction(func(store *Store) error {
 		return nil
@@ -338,6 +416,16 @@ func (s *StoreSuite) TestTransaction_Rollback() {
 	s.assertCount(0)
 }
 
+func (s *StoreSuite) TestTransaction_Panic() {
+	s.Panics(func() {
+		s.store.Transaction(func(store *Store) error {
+			s.NoError(store.Insert(ModelSchema, newModel("Joe", "", 1)))
+			panic("kallax: panic in transaction")
+		})
+	})
+	s.assertCount(0)
+}
+
 func (s *StoreSuite) TestTransaction_RawExec() {
 	err := s.store.Transaction(func(store *Store) error {
 		_, err := store.RawExec("INSERT INTO model (name, email, age) VALUES ($1, $2, $3)", "foo", "bar", 1)
@@ -351,9 +439,8 @@ func (s *StoreSuite) TestTransaction_RawExec() {
 func (s *StoreSuite) TestReload() {
 	s.NoError(s.store.Insert(ModelSchema, newModel("Joe", "", 1)))
 
-	// If we don't select all the fields, the records
-	// retrieved will not be writable, as it could be a potential danger
-	// to the user to save a partial model.
+	// If we don't select all the fields, the records retrieved will be
+	// partial, and only the retrieved fields will be updated.
 	q := NewBaseQuery(ModelSchema)
 	q.Select(NewSchemaField("name"), ModelSchema.ID())
 	rs, err := s.store.Find(q)
@@ -370,18 +457,23 @@ func (s *StoreSuite) TestReload() {
 	s.True(ok)
 	s.NoError(err)
 
-	// Model is not writable, as we said
-	s.False(m.IsWritable())
+	// Model is partial, as we said
+	s.True(m.IsWritable())
+	s.True(m.IsPartial())
 	s.Equal(0, m.Age)
 
+	m.Name = "bar"
+	_, err = s.store.Update(ModelSchema, m, f("age"))
+	s.Equal(ErrNotFetched, err)
 	_, err = s.store.Update(ModelSchema, m)
-	s.Equal(ErrNotWritable, err)
+	s.NoError(err)
 
 	// Now, the model is reloaded with all the fields
 	s.NoError(s.store.Reload(ModelSchema, m))
 
-	// And so, it becomes writable
-	s.True(m.IsWritable())
+	// And so, it is no longer partial
+	s.False(m.IsPartial())
+	s.Equal("bar", m.Name)
 	s.Equal(1, m.Age)
 }
 
@@ -602,3 +694,229 @@ func TestStoreFrom(t *testing.T) {
 	StoreFrom(&s2, s1)
 	require.Exactly(s1.Store, s2.Store)
 }
+
+func TestStatements(t *testing.T) {
+	require := require.New(t)
+	m := newModel("foo", "foo@bar.baz", 42)
+
+	query, args, err := InsertStatement(ModelSchema, m)
+	require.NoError(err)
+	require.Equal("INSERT INTO model (name,email,age) VALUES ($1,$2,$3) RETURNING id", query)
+	require.Equal([]interface{}{"foo", "foo@bar.baz", 42}, args)
+
+	_, _, err = InsertStatement(onlyPkModelSchema, new(onlyPkModel))
+	require.Equal(ErrNoColumns, err)
+
+	m.ID = 5
+	query, args, err = UpdateStatement(ModelSchema, m, f("name"), f("age"))
+	require.NoError(err)
+	require.Equal("UPDATE model SET name=$1,age=$2 WHERE id=$3", query)
+	require.Len(args, 3)
+	require.Equal([]interface{}{"foo", 42}, args[:2])
+	require.Equal(m.GetID(), args[2])
+
+	query, args = DeleteStatement(ModelSchema, m)
+	require.Equal("DELETE FROM model WHERE id=$1", query)
+	require.Equal([]interface{}{m.GetID()}, args)
+
+	schema := NewDynamicSchema("post", "id", true, "title", "deleted_at").
+		WithSoftDelete(f("deleted_at"))
+	record := NewDynamicRecord(schema)
+	require.NoError(record.Set("id", int64(1)))
+	deletedAt := time.Now()
+	query, args = SoftDeleteStatement(schema, record, deletedAt)
+	require.Equal("UPDATE post SET deleted_at=$1 WHERE id=$2", query)
+	require.Equal([]interface{}{deletedAt, record.GetID()}, args)
+
+	m.ID = 0
+	query, args, err = UpsertStatement(Postgres, ModelSchema, m, []SchemaField{f("email")}, f("name"), f("age"))
+	require.NoError(err)
+	require.Equal("INSERT INTO model (name,email,age) VALUES ($1,$2,$3) ON CONFLICT (email) DO UPDATE SET name = EXCLUDED.name, age = EXCLUDED.age RETURNING id", query)
+	require.Equal([]interface{}{"foo", "foo@bar.baz", 42}, args)
+
+	query, _, err = UpsertStatement(SQLite, ModelSchema, m, []SchemaField{f("name"), f("email")})
+	require.NoError(err)
+	require.Equal("INSERT INTO model (name,email,age) VALUES ($1,$2,$3) ON CONFLICT (name, email) DO NOTHING RETURNING id", query)
+
+	query, _, err = UpsertStatement(MySQL, ModelSchema, m, []SchemaField{f("email")}, f("name"))
+	require.NoError(err)
+	require.Equal("INSERT INTO model (name,email,age) VALUES ($1,$2,$3) ON DUPLICATE KEY UPDATE name = VALUES(name)", query)
+
+	_, _, err = UpsertStatement(Postgres, ModelSchema, m, nil)
+	require.Equal(ErrNoConflictColumns, err)
+}
+
+func TestStore_Upsert(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	recordedQueries = nil
+	m := newModel("foo", "foo@bar.baz", 42)
+	r.NoError(NewStore(db).Upsert(ModelSchema, m, []SchemaField{f("email")}, f("name")))
+	r.False(m.IsPersisted())
+
+	lastInsertID = 5
+	defer func() { lastInsertID = 0 }()
+	r.NoError(NewStore(db).WithDialect(MySQL).Upsert(ModelSchema, m, []SchemaField{f("email")}, f("name")))
+	r.True(m.IsPersisted())
+	r.Equal(int64(5), m.ID)
+
+	r.Equal(ErrNoConflictColumns, NewStore(db).Upsert(ModelSchema, m, nil))
+	r.Equal([]string{
+		"INSERT INTO model (name,email,age) VALUES ($1,$2,$3) ON CONFLICT (email) DO UPDATE SET name = EXCLUDED.name RETURNING id",
+		"INSERT INTO model (name,email,age) VALUES (?,?,?) ON DUPLICATE KEY UPDATE name = VALUES(name)",
+	}, recordedQueries)
+}
+
+func TestStore_SoftDelete(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	recordedQueries = nil
+	schema := NewDynamicSchema("post", "id", true, "title", "deleted_at").
+		WithSoftDelete(f("deleted_at"))
+	record := NewDynamicRecord(schema)
+	r.NoError(record.Set("id", int64(1)))
+
+	store := NewStore(db)
+	r.NoError(store.Delete(schema, record))
+	r.IsType(time.Time{}, record.Get("deleted_at"))
+	r.NoError(store.HardDelete(schema, record))
+
+	batch := store.NewBatch()
+	r.NoError(batch.Delete(schema, record))
+	query, _, err := batch.ToSql()
+	r.NoError(err)
+	r.Equal("WITH q1 AS (UPDATE post SET deleted_at=$1 WHERE id=$2) SELECT 1", query)
+
+	q := NewBaseQuery(schema)
+	q.Where(Eq(f("title"), "foo"))
+	_, err = store.Count(q)
+	r.Equal(sql.ErrNoRows, err)
+	q.Unscoped()
+	_, err = store.Count(q)
+	r.Equal(sql.ErrNoRows, err)
+	q.OnlyDeleted()
+	_, err = store.Count(q.Copy())
+	r.Equal(sql.ErrNoRows, err)
+
+	r.Equal([]string{
+		"UPDATE post SET deleted_at=$1 WHERE id=$2",
+		"DELETE FROM post WHERE id=$1",
+		"SELECT COUNT(*) FROM post __post WHERE __post.title = $1 AND __post.deleted_at IS NULL",
+		"SELECT COUNT(*) FROM post __post WHERE __post.title = $1",
+		"SELECT COUNT(*) FROM post __post WHERE __post.title = $1 AND __post.deleted_at IS NOT NULL",
+	}, recordedQueries)
+}
+
+func TestStore_InsertDefaults(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	schema := NewDynamicSchema("post", "id", true, "title", "status", "score").
+		WithDefaults(f("status"), f("score"))
+	record := NewDynamicRecord(schema)
+	r.NoError(record.Set("title", "foo"))
+	r.NoError(record.Set("score", 0))
+
+	recordedQueries = nil
+	store := NewStore(db)
+	r.NoError(store.Insert(schema, record))
+	record = NewDynamicRecord(schema)
+	r.NoError(record.Set("title", "foo"))
+	r.NoError(record.Set("status", "draft"))
+	r.NoError(store.WithReturning(schema, f("title")).Insert(schema, record))
+
+	lastInsertID = 5
+	defer func() { lastInsertID = 0 }()
+	r.NoError(store.WithDialect(MySQL).Insert(schema, NewDynamicRecord(schema)))
+
+	r.Equal([]string{
+		"INSERT INTO post (title) VALUES ($1) RETURNING id, status, score",
+		"INSERT INTO post (title,status) VALUES ($1,$2) RETURNING id, title, score",
+		"INSERT INTO post (title) VALUES (?)",
+	}, recordedQueries)
+
+	query, args, err := InsertStatement(schema, record)
+	r.NoError(err)
+	r.Equal("INSERT INTO post (title,status) VALUES ($1,$2) RETURNING id", query)
+	r.Equal([]interface{}{"foo", "draft"}, args)
+}
+
+func TestStore_CompositePrimaryKey(t *testing.T) {
+	r := require.New(t)
+	schema := NewDynamicSchema("orders", "tenant_id", false, "order_id", "name").
+		WithPrimaryKey(NewSchemaField("tenant_id"), NewSchemaField("order_id"))
+	r.Equal([]string{"tenant_id", "order_id"}, ColumnNames(schema.PrimaryKey()))
+	r.Equal([]string{"id"}, ColumnNames(ModelSchema.PrimaryKey()))
+
+	record := NewDynamicRecord(schema)
+	r.NoError(record.Set("tenant_id", 1))
+	r.NoError(record.Set("order_id", 2))
+	r.NoError(record.Set("name", "foo"))
+	r.Equal([]interface{}{1, 2}, record.GetPrimaryKey())
+
+	query, args, err := UpdateStatement(schema, record, NewSchemaField("name"))
+	r.NoError(err)
+	r.Equal("UPDATE orders SET name=$1 WHERE tenant_id=$2 AND order_id=$3", query)
+	r.Equal([]interface{}{"foo", 1, 2}, args)
+
+	query, args = DeleteStatement(schema, record)
+	r.Equal("DELETE FROM orders WHERE tenant_id=$1 AND order_id=$2", query)
+	r.Equal([]interface{}{1, 2}, args)
+
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	recordedQueries = nil
+	store := NewStore(db)
+	r.NoError(store.Delete(schema, record))
+	r.Equal(ErrNotFound, store.Reload(schema, record))
+	r.Equal([]string{
+		"DELETE FROM orders WHERE tenant_id=$1 AND order_id=$2",
+		"SELECT __orders.tenant_id, __orders.order_id, __orders.name FROM orders __orders WHERE (__orders.tenant_id = $1 AND __orders.order_id = $2)",
+	}, recordedQueries)
+}
+
+func TestStore_Relations(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	m := newModel("foo", "foo@bar.baz", 1)
+	m.ID = 1
+	rel1, rel2 := newRel(m.GetID(), "1"), newRel(m.GetID(), "2")
+	rel1.ID, rel2.ID = 2, 3
+
+	recordedQueries = nil
+	store := NewStore(db)
+	r.NoError(store.AddRelations(ModelSchema, m, "joined", rel1, rel2))
+	r.NoError(store.WithDialect(MySQL).AddRelations(ModelSchema, m, "joined", rel1))
+	r.NoError(store.RemoveRelations(ModelSchema, m, "joined", rel1, rel2))
+	r.NoError(store.ClearRelations(ModelSchema, m, "joined"))
+	r.NoError(store.AddRelations(ModelSchema, m, "joined"))
+	r.Equal([]string{
+		"INSERT INTO model_rel (model_id, rel_id) VALUES ($1, $2), ($3, $4) ON CONFLICT (model_id, rel_id) DO NOTHING",
+		"INSERT INTO model_rel (model_id, rel_id) VALUES (?, ?) ON DUPLICATE KEY UPDATE model_id = model_id",
+		"DELETE FROM model_rel WHERE model_id=$1 AND rel_id IN ($2, $3)",
+		"DELETE FROM model_rel WHERE model_id=$1",
+	}, recordedQueries)
+
+	recordedQueries = nil
+	r.Equal(ErrEmptyID, store.AddRelations(ModelSchema, m, "joined", newRel(m.GetID(), "3")))
+	r.Equal(ErrEmptyID, store.RemoveRelations(ModelSchema, m, "joined", rel1, newRel(m.GetID(), "3")))
+	r.Equal(ErrEmptyID, store.ClearRelations(ModelSchema, newModel("bar", "bar@bar.baz", 2), "joined"))
+	r.EqualError(
+		store.AddRelations(ModelSchema, m, "rels", rel1),
+		"kallax: cannot find join table on field rels for table model",
+	)
+	r.Empty(recordedQueries)
+}
@@ -0,0 +1,120 @@
+package kallax
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrCrossTenant is returned when a store bound to a tenant writes a record
+// that belongs to another tenant.
+var ErrCrossTenant = errors.New("kallax: record belongs to another tenant than the one of the store")
+
+// WithTenant returns a new store bound to the given tenant, which only reads
+// and writes the records of the tenant in the tables with a tenant column,
+// see BaseSchema.WithTenant. The condition `tenant_id = ?` is added to all
+// the queries it runs, as with WithScope, and to the updates and deletes of
+// the records, including UpdateWhere and DeleteWhere. The records inserted,
+// updated and upserted are given the tenant if they have none, and
+// ErrCrossTenant is returned if they have another one. Unlike the default
+// scopes, the tenant is kept by Unscoped and the stores of the transactions,
+// and the stores returned by WithTenant(nil) are not bound to any tenant.
+// Raw statements and the rows of 1:1 relationships are not filtered.
+func (s *Store) WithTenant(tenant interface{}) *Store {
+	store := s.clone()
+	store.tenant = tenant
+	return store.init()
+}
+
+// Tenant returns the tenant the store is bound to, or nil if it is not bound
+// to any.
+func (s *Store) Tenant() interface{} {
+	return s.tenant
+}
+
+// queryScopes returns the default conditions of the queries of the store,
+// along with the one of its tenant, if any.
+func (s *Store) queryScopes() scopes {
+	if s.tenant == nil {
+		return s.scopes
+	}
+
+	result := make(scopes, len(s.scopes)+1)
+	for table, conds := range s.scopes {
+		result[table] = conds
+	}
+	result[allTables] = []Condition{tenantCondition(s.tenant)}
+	return result
+}
+
+// tenantCondition returns the condition that matches the records of the
+// given tenant, which matches all of them in the schemas without a tenant
+// column.
+func tenantCondition(tenant interface{}) Condition {
+	return func(schema Schema) ToSqler {
+		col := schema.TenantField()
+		if col == nil {
+			return nil
+		}
+		return Eq(col, tenant)(schema)
+	}
+}
+
+// tenantStatement returns the given update or delete statement of a record
+// of the given schema, and its arguments, with the condition that matches
+// the rows of the tenant of the store, if both of them have one.
+func (s *Store) tenantStatement(schema Schema, query string, args []interface{}) (string, []interface{}) {
+	col := schema.TenantField()
+	if s.tenant == nil || col == nil {
+		return query, args
+	}
+
+	query += fmt.Sprintf(" AND %s=$%d", col, len(args)+1)
+	return query, append(args, s.tenant)
+}
+
+// bindTenant sets the tenant column of the given record of the given schema
+// to the tenant of the store, if both of them have one, and the record has
+// none. It returns ErrCrossTenant if the record has another tenant.
+func (s *Store) bindTenant(schema Schema, record Record) error {
+	col := schema.TenantField()
+	if s.tenant == nil || col == nil {
+		return nil
+	}
+
+	ptr, err := record.ColumnAddress(col.String())
+	if err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(ptr).Elem()
+	tenant := reflect.ValueOf(s.tenant)
+	if !tenant.Type().ConvertibleTo(v.Type()) || (v.Kind() == reflect.String && tenant.Kind() != reflect.String) {
+		return fmt.Errorf("kallax: tenant %v of the store is not a valid value of the tenant column %s of table %s", s.tenant, col, schema.Table())
+	}
+	tenant = tenant.Convert(v.Type())
+
+	if v.IsZero() {
+		v.Set(tenant)
+		return nil
+	}
+
+	if !reflect.DeepEqual(v.Interface(), tenant.Interface()) {
+		return ErrCrossTenant
+	}
+	return nil
+}
+
+// sameTenant returns whether the given values are the same tenant. They are
+// compared by the values given to the driver for them, so values of
+// different types, such as int and int64 or a UUID and its string form, are
+// the same tenant if they are stored the same way.
+func sameTenant(a, b interface{}) bool {
+	va, errA := driver.DefaultParameterConverter.ConvertValue(a)
+	vb, errB := driver.DefaultParameterConverter.ConvertValue(b)
+	if errA != nil || errB != nil {
+		return reflect.DeepEqual(a, b)
+	}
+	return reflect.DeepEqual(va, vb)
+}
@@ -0,0 +1,101 @@
+package kallax
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var tenantSchema = NewBaseSchema(
+	"model",
+	"__model",
+	f("id"),
+	ForeignKeys{},
+	func() Record {
+		return new(model)
+	},
+	true,
+	f("id"),
+	f("name"),
+	f("email"),
+	f("age"),
+).WithTenant(f("email"))
+
+func TestStore_WithTenant(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	lastInsertID = 42
+	defer func() { lastInsertID = 0 }()
+
+	store := NewStore(db).WithDialect(MySQL).DisableCacher().WithTenant("acme")
+	r.Equal("acme", store.Unscoped().Tenant())
+	r.Nil(store.WithTenant(nil).Tenant())
+
+	recordedQueries = nil
+	rs, err := store.Find(NewBaseQuery(tenantSchema))
+	r.NoError(err)
+	r.NoError(rs.Close())
+	rs, err = store.Find(NewBaseQuery(ModelSchema))
+	r.NoError(err)
+	r.NoError(rs.Close())
+	r.Equal([]string{
+		"SELECT __model.id, __model.name, __model.email, __model.age FROM model __model WHERE __model.email = ?",
+		"SELECT __model.id, __model.name, __model.email, __model.age FROM model __model",
+	}, recordedQueries)
+
+	m := newModel("foo", "", 1)
+	r.NoError(store.Insert(tenantSchema, m))
+	r.Equal("acme", m.Email)
+
+	recordedQueries = nil
+	_, err = store.Update(tenantSchema, m, f("name"))
+	r.Equal(ErrNoRowUpdate, err)
+	r.NoError(store.Delete(tenantSchema, m))
+	r.Equal([]string{
+		"UPDATE model SET name=? WHERE id=? AND email=?",
+		"DELETE FROM model WHERE id=? AND email=?",
+	}, recordedQueries)
+
+	r.Equal(ErrCrossTenant, store.Insert(tenantSchema, newModel("bar", "other", 1)))
+	m.Email = "other"
+	_, err = store.Update(tenantSchema, m)
+	r.Equal(ErrCrossTenant, err)
+	r.NoError(store.WithTenant(nil).Insert(tenantSchema, newModel("bar", "other", 1)))
+	_, err = store.UpdateWhere(NewBaseQuery(tenantSchema), map[SchemaField]interface{}{f("email"): "other"})
+	r.Equal(ErrCrossTenant, err)
+
+	err = store.Upsert(tenantSchema, newModel("baz", "", 1), []SchemaField{f("id")}, f("name"))
+	r.EqualError(err, "kallax: the upserts of table model of a store with a tenant must conflict in its tenant column email")
+
+	err = store.WithTenant(42).Insert(tenantSchema, newModel("baz", "", 1))
+	r.EqualError(err, "kallax: tenant 42 of the store is not a valid value of the tenant column email of table model")
+}
+
+func TestStore_UpdateWhereTenant(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	schema := NewDynamicSchema("post", "id", true, "title", "tenant_id").
+		WithTenant(f("tenant_id"))
+	q := NewBaseQuery(schema)
+
+	recordedQueries = nil
+	store := NewStore(db).WithTenant(1)
+	_, err = store.UpdateWhere(q, map[SchemaField]interface{}{f("tenant_id"): int64(1)})
+	r.NoError(err)
+	_, err = store.UpdateWhere(q, map[SchemaField]interface{}{f("tenant_id"): int64(2)})
+	r.Equal(ErrCrossTenant, err)
+	_, err = store.UpdateWhere(q, map[SchemaField]interface{}{f("tenant_id"): []int{1}})
+	r.Equal(ErrCrossTenant, err)
+
+	id := NewUUIDv4()
+	_, err = NewStore(db).WithTenant(id).UpdateWhere(q, map[SchemaField]interface{}{f("tenant_id"): id.String()})
+	r.NoError(err)
+	r.Len(recordedQueries, 2)
+}
@@ -0,0 +1,9 @@
+package tests
+
+import kallax "gopkg.in/src-d/go-kallax.v1"
+
+type AuditedPost struct {
+	kallax.Model `table:"audited_posts" audit:"true"`
+	ID           int64 `pk:"autoincr"`
+	Title        string
+}
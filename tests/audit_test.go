@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	kallax "gopkg.in/src-d/go-kallax.v1"
+)
+
+type AuditSuite struct {
+	BaseTestSuite
+}
+
+func TestAuditSuite(t *testing.T) {
+	schema := []string{
+		`CREATE TABLE IF NOT EXISTS audited_posts (
+			id serial primary key,
+			title text not null
+		)`,
+		`CREATE TABLE IF NOT EXISTS audited_posts_audit (
+			id bigserial primary key,
+			record_id bigint not null,
+			operation text not null,
+			old_row jsonb,
+			new_row jsonb,
+			actor text,
+			changed_at timestamptz not null
+		)`,
+		`CREATE OR REPLACE FUNCTION audited_posts__audit() RETURNS trigger AS $$
+		BEGIN
+			IF TG_OP = 'DELETE' THEN
+				INSERT INTO audited_posts_audit (record_id, operation, old_row, new_row, actor, changed_at)
+				VALUES (OLD.id, TG_OP, to_jsonb(OLD), NULL, NULLIF(current_setting('kallax.actor', true), ''), now());
+				RETURN OLD;
+			END IF;
+			INSERT INTO audited_posts_audit (record_id, operation, old_row, new_row, actor, changed_at)
+			VALUES (NEW.id, TG_OP, CASE WHEN TG_OP = 'UPDATE' THEN to_jsonb(OLD) END, to_jsonb(NEW), NULLIF(current_setting('kallax.actor', true), ''), now());
+			RETURN NEW;
+		END
+		$$ LANGUAGE plpgsql`,
+		`CREATE TRIGGER audited_posts__audit AFTER INSERT OR UPDATE OR DELETE ON audited_posts FOR EACH ROW EXECUTE PROCEDURE audited_posts__audit()`,
+	}
+	suite.Run(t, &AuditSuite{NewBaseSuite(schema, "audited_posts", "audited_posts_audit")})
+}
+
+func (s *AuditSuite) TestHistory() {
+	require := s.Require()
+	store := NewAuditedPostStore(s.db)
+
+	post := &AuditedPost{Title: "foo"}
+	require.NoError(store.Insert(post))
+
+	require.NoError(store.AuditAs("alice", func(store *AuditedPostStore) error {
+		post.Title = "bar"
+		_, err := store.Update(post)
+		return err
+	}))
+
+	require.NoError(store.Delete(post))
+
+	entries, err := store.History(post)
+	require.NoError(err)
+	require.Len(entries, 3)
+
+	require.Equal(kallax.AuditDelete, entries[0].Operation)
+	require.Equal(fmt.Sprint(post.ID), entries[0].RecordID)
+	require.Empty(entries[0].New)
+	require.Equal("bar", title(s, entries[0].Old))
+
+	require.Equal(kallax.AuditUpdate, entries[1].Operation)
+	require.Equal("alice", entries[1].Actor)
+	require.Equal("foo", title(s, entries[1].Old))
+	require.Equal("bar", title(s, entries[1].New))
+
+	require.Equal(kallax.AuditInsert, entries[2].Operation)
+	require.Empty(entries[2].Actor)
+	require.Empty(entries[2].Old)
+
+	entries, err = store.FindAudits(NewAuditedPostAuditQuery().ByActor("alice"))
+	require.NoError(err)
+	require.Len(entries, 1)
+	require.Equal(kallax.AuditUpdate, entries[0].Operation)
+
+	entries, err = store.FindAudits(NewAuditedPostAuditQuery().
+		Operation(kallax.AuditInsert, kallax.AuditDelete).
+		Oldest().
+		Limit(1))
+	require.NoError(err)
+	require.Len(entries, 1)
+	require.Equal(kallax.AuditInsert, entries[0].Operation)
+}
+
+func title(s *AuditSuite, row json.RawMessage) string {
+	var post struct{ Title string }
+	s.Require().NoError(json.Unmarshal(row, &post))
+	return post.Title
+}
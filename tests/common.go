@@ -1,3 +1,3 @@
 package tests
 
-//go:generate kallax gen
+//go:generate kallax gen --mock --factories
@@ -0,0 +1,11 @@
+package tests
+
+import kallax "gopkg.in/src-d/go-kallax.v1"
+
+type AccountStatus string
+
+type Account struct {
+	kallax.Model `table:"accounts"`
+	ID           int64         `pk:"autoincr"`
+	Status       AccountStatus `enum:"account_status,active,disabled,banned"`
+}
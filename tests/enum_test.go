@@ -0,0 +1,23 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	kallax "gopkg.in/src-d/go-kallax.v1"
+)
+
+func TestEnum(t *testing.T) {
+	r := require.New(t)
+	store := NewMockAccountStore(kallax.NewMockStore())
+
+	account := &Account{Status: "deleted"}
+	var verr *kallax.ValidationError
+	r.True(errors.As(store.Insert(account), &verr))
+	r.Equal([]*kallax.FieldError{{Field: "Status", Rule: "oneof=active disabled banned"}}, verr.Fields)
+
+	account.Status = AccountStatusBanned
+	r.NoError(store.Insert(account))
+	r.Equal(AccountStatus("banned"), AccountStatusBanned)
+}
@@ -0,0 +1,4007 @@
+// Code generated by https://github.com/src-d/go-kallax. DO NOT EDIT.
+// Please, do not touch the code below, and if you do, do it under your own
+// risk. Take into account that all the code you write here will be completely
+// erased from earth the next time you generate the kallax models.
+package tests
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/src-d/go-kallax.v1"
+	"gopkg.in/src-d/go-kallax.v1/tests/fixtures"
+)
+
+// AFactory builds records of the type A for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type AFactory struct {
+	n        int
+	builders []func(record *A, n int)
+}
+
+// NewAFactory returns a new factory of records of the type
+// A, whose records are numbered from 1.
+func NewAFactory() *AFactory {
+	return new(AFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *AFactory) Sequence(fn func(record *A, n int)) *AFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithName makes the factory set the field Name of all the records it
+// builds to the given value, and returns the factory.
+func (f *AFactory) WithName(v string) *AFactory {
+	return f.Sequence(func(record *A, _ int) {
+		record.Name = v
+	})
+}
+
+// WithB makes the factory set the field B of all the records it
+// builds to the given value, and returns the factory.
+func (f *AFactory) WithB(v *B) *AFactory {
+	return f.Sequence(func(record *A, _ int) {
+		record.B = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *AFactory) Build() *A {
+	f.n++
+	n := f.n
+	record := new(A)
+	record.Name = fmt.Sprintf("name-%d", n)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *AFactory) BuildN(count int) []*A {
+	records := make([]*A, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a AStore or a MockAStore.
+func (f *AFactory) Create(store interface{ Insert(*A) error }) (*A, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create A number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a AStore or a
+// MockAStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *AFactory) CreateN(store interface{ Insert(*A) error }, count int) ([]*A, error) {
+	records := make([]*A, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// AccountFactory builds records of the type Account for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type AccountFactory struct {
+	n        int
+	builders []func(record *Account, n int)
+}
+
+// NewAccountFactory returns a new factory of records of the type
+// Account, whose records are numbered from 1.
+func NewAccountFactory() *AccountFactory {
+	return new(AccountFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *AccountFactory) Sequence(fn func(record *Account, n int)) *AccountFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithStatus makes the factory set the field Status of all the records it
+// builds to the given value, and returns the factory.
+func (f *AccountFactory) WithStatus(v AccountStatus) *AccountFactory {
+	return f.Sequence(func(record *Account, _ int) {
+		record.Status = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *AccountFactory) Build() *Account {
+	f.n++
+	n := f.n
+	record := new(Account)
+	record.Status = AccountStatusActive
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *AccountFactory) BuildN(count int) []*Account {
+	records := make([]*Account, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a AccountStore or a MockAccountStore.
+func (f *AccountFactory) Create(store interface{ Insert(*Account) error }) (*Account, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create Account number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a AccountStore or a
+// MockAccountStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *AccountFactory) CreateN(store interface{ Insert(*Account) error }, count int) ([]*Account, error) {
+	records := make([]*Account, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// AuditedPostFactory builds records of the type AuditedPost for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type AuditedPostFactory struct {
+	n        int
+	builders []func(record *AuditedPost, n int)
+}
+
+// NewAuditedPostFactory returns a new factory of records of the type
+// AuditedPost, whose records are numbered from 1.
+func NewAuditedPostFactory() *AuditedPostFactory {
+	return new(AuditedPostFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *AuditedPostFactory) Sequence(fn func(record *AuditedPost, n int)) *AuditedPostFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithTitle makes the factory set the field Title of all the records it
+// builds to the given value, and returns the factory.
+func (f *AuditedPostFactory) WithTitle(v string) *AuditedPostFactory {
+	return f.Sequence(func(record *AuditedPost, _ int) {
+		record.Title = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *AuditedPostFactory) Build() *AuditedPost {
+	f.n++
+	n := f.n
+	record := new(AuditedPost)
+	record.Title = fmt.Sprintf("title-%d", n)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *AuditedPostFactory) BuildN(count int) []*AuditedPost {
+	records := make([]*AuditedPost, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a AuditedPostStore or a MockAuditedPostStore.
+func (f *AuditedPostFactory) Create(store interface{ Insert(*AuditedPost) error }) (*AuditedPost, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create AuditedPost number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a AuditedPostStore or a
+// MockAuditedPostStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *AuditedPostFactory) CreateN(store interface{ Insert(*AuditedPost) error }, count int) ([]*AuditedPost, error) {
+	records := make([]*AuditedPost, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// BFactory builds records of the type B for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type BFactory struct {
+	n        int
+	builders []func(record *B, n int)
+}
+
+// NewBFactory returns a new factory of records of the type
+// B, whose records are numbered from 1.
+func NewBFactory() *BFactory {
+	return new(BFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *BFactory) Sequence(fn func(record *B, n int)) *BFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithName makes the factory set the field Name of all the records it
+// builds to the given value, and returns the factory.
+func (f *BFactory) WithName(v string) *BFactory {
+	return f.Sequence(func(record *B, _ int) {
+		record.Name = v
+	})
+}
+
+// WithA makes the factory set the field A of all the records it
+// builds to the given value, and returns the factory.
+func (f *BFactory) WithA(v *A) *BFactory {
+	return f.Sequence(func(record *B, _ int) {
+		record.A = v
+	})
+}
+
+// WithC makes the factory set the field C of all the records it
+// builds to the given value, and returns the factory.
+func (f *BFactory) WithC(v *C) *BFactory {
+	return f.Sequence(func(record *B, _ int) {
+		record.C = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *BFactory) Build() *B {
+	f.n++
+	n := f.n
+	record := new(B)
+	record.Name = fmt.Sprintf("name-%d", n)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *BFactory) BuildN(count int) []*B {
+	records := make([]*B, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a BStore or a MockBStore.
+func (f *BFactory) Create(store interface{ Insert(*B) error }) (*B, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create B number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a BStore or a
+// MockBStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *BFactory) CreateN(store interface{ Insert(*B) error }, count int) ([]*B, error) {
+	records := make([]*B, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// BrandFactory builds records of the type Brand for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type BrandFactory struct {
+	n        int
+	builders []func(record *Brand, n int)
+}
+
+// NewBrandFactory returns a new factory of records of the type
+// Brand, whose records are numbered from 1.
+func NewBrandFactory() *BrandFactory {
+	return new(BrandFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *BrandFactory) Sequence(fn func(record *Brand, n int)) *BrandFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithID makes the factory set the field ID of all the records it
+// builds to the given value, and returns the factory.
+func (f *BrandFactory) WithID(v kallax.ULID) *BrandFactory {
+	return f.Sequence(func(record *Brand, _ int) {
+		record.ID = v
+	})
+}
+
+// WithName makes the factory set the field Name of all the records it
+// builds to the given value, and returns the factory.
+func (f *BrandFactory) WithName(v string) *BrandFactory {
+	return f.Sequence(func(record *Brand, _ int) {
+		record.Name = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *BrandFactory) Build() *Brand {
+	f.n++
+	n := f.n
+	record := new(Brand)
+	record.ID = kallax.NewULID()
+	record.Name = fmt.Sprintf("name-%d", n)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *BrandFactory) BuildN(count int) []*Brand {
+	records := make([]*Brand, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a BrandStore or a MockBrandStore.
+func (f *BrandFactory) Create(store interface{ Insert(*Brand) error }) (*Brand, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create Brand number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a BrandStore or a
+// MockBrandStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *BrandFactory) CreateN(store interface{ Insert(*Brand) error }, count int) ([]*Brand, error) {
+	records := make([]*Brand, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// CFactory builds records of the type C for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type CFactory struct {
+	n        int
+	builders []func(record *C, n int)
+}
+
+// NewCFactory returns a new factory of records of the type
+// C, whose records are numbered from 1.
+func NewCFactory() *CFactory {
+	return new(CFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *CFactory) Sequence(fn func(record *C, n int)) *CFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithName makes the factory set the field Name of all the records it
+// builds to the given value, and returns the factory.
+func (f *CFactory) WithName(v string) *CFactory {
+	return f.Sequence(func(record *C, _ int) {
+		record.Name = v
+	})
+}
+
+// WithB makes the factory set the field B of all the records it
+// builds to the given value, and returns the factory.
+func (f *CFactory) WithB(v *B) *CFactory {
+	return f.Sequence(func(record *C, _ int) {
+		record.B = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *CFactory) Build() *C {
+	f.n++
+	n := f.n
+	record := new(C)
+	record.Name = fmt.Sprintf("name-%d", n)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *CFactory) BuildN(count int) []*C {
+	records := make([]*C, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a CStore or a MockCStore.
+func (f *CFactory) Create(store interface{ Insert(*C) error }) (*C, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create C number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a CStore or a
+// MockCStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *CFactory) CreateN(store interface{ Insert(*C) error }, count int) ([]*C, error) {
+	records := make([]*C, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// CarFactory builds records of the type Car for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type CarFactory struct {
+	n        int
+	builders []func(record *Car, n int)
+}
+
+// NewCarFactory returns a new factory of records of the type
+// Car, whose records are numbered from 1.
+func NewCarFactory() *CarFactory {
+	return new(CarFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *CarFactory) Sequence(fn func(record *Car, n int)) *CarFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithID makes the factory set the field ID of all the records it
+// builds to the given value, and returns the factory.
+func (f *CarFactory) WithID(v kallax.ULID) *CarFactory {
+	return f.Sequence(func(record *Car, _ int) {
+		record.ID = v
+	})
+}
+
+// WithOwner makes the factory set the field Owner of all the records it
+// builds to the given value, and returns the factory.
+func (f *CarFactory) WithOwner(v *Person) *CarFactory {
+	return f.Sequence(func(record *Car, _ int) {
+		record.Owner = v
+	})
+}
+
+// WithModelName makes the factory set the field ModelName of all the records it
+// builds to the given value, and returns the factory.
+func (f *CarFactory) WithModelName(v string) *CarFactory {
+	return f.Sequence(func(record *Car, _ int) {
+		record.ModelName = v
+	})
+}
+
+// WithBrand makes the factory set the field Brand of all the records it
+// builds to the given value, and returns the factory.
+func (f *CarFactory) WithBrand(v Brand) *CarFactory {
+	return f.Sequence(func(record *Car, _ int) {
+		record.Brand = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *CarFactory) Build() *Car {
+	f.n++
+	n := f.n
+	record := new(Car)
+	record.ID = kallax.NewULID()
+	record.ModelName = fmt.Sprintf("model_name-%d", n)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *CarFactory) BuildN(count int) []*Car {
+	records := make([]*Car, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a CarStore or a MockCarStore.
+func (f *CarFactory) Create(store interface{ Insert(*Car) error }) (*Car, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create Car number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a CarStore or a
+// MockCarStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *CarFactory) CreateN(store interface{ Insert(*Car) error }, count int) ([]*Car, error) {
+	records := make([]*Car, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// ChildFactory builds records of the type Child for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type ChildFactory struct {
+	n        int
+	builders []func(record *Child, n int)
+}
+
+// NewChildFactory returns a new factory of records of the type
+// Child, whose records are numbered from 1.
+func NewChildFactory() *ChildFactory {
+	return new(ChildFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *ChildFactory) Sequence(fn func(record *Child, n int)) *ChildFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithName makes the factory set the field Name of all the records it
+// builds to the given value, and returns the factory.
+func (f *ChildFactory) WithName(v string) *ChildFactory {
+	return f.Sequence(func(record *Child, _ int) {
+		record.Name = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *ChildFactory) Build() *Child {
+	f.n++
+	n := f.n
+	record := new(Child)
+	record.Name = fmt.Sprintf("name-%d", n)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *ChildFactory) BuildN(count int) []*Child {
+	records := make([]*Child, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a ChildStore or a MockChildStore.
+func (f *ChildFactory) Create(store interface{ Insert(*Child) error }) (*Child, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create Child number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a ChildStore or a
+// MockChildStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *ChildFactory) CreateN(store interface{ Insert(*Child) error }, count int) ([]*Child, error) {
+	records := make([]*Child, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// CompositeKeyFixtureFactory builds records of the type CompositeKeyFixture for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type CompositeKeyFixtureFactory struct {
+	n        int
+	builders []func(record *CompositeKeyFixture, n int)
+}
+
+// NewCompositeKeyFixtureFactory returns a new factory of records of the type
+// CompositeKeyFixture, whose records are numbered from 1.
+func NewCompositeKeyFixtureFactory() *CompositeKeyFixtureFactory {
+	return new(CompositeKeyFixtureFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *CompositeKeyFixtureFactory) Sequence(fn func(record *CompositeKeyFixture, n int)) *CompositeKeyFixtureFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithTenantID makes the factory set the field TenantID of all the records it
+// builds to the given value, and returns the factory.
+func (f *CompositeKeyFixtureFactory) WithTenantID(v int64) *CompositeKeyFixtureFactory {
+	return f.Sequence(func(record *CompositeKeyFixture, _ int) {
+		record.TenantID = v
+	})
+}
+
+// WithOrderID makes the factory set the field OrderID of all the records it
+// builds to the given value, and returns the factory.
+func (f *CompositeKeyFixtureFactory) WithOrderID(v int64) *CompositeKeyFixtureFactory {
+	return f.Sequence(func(record *CompositeKeyFixture, _ int) {
+		record.OrderID = v
+	})
+}
+
+// WithName makes the factory set the field Name of all the records it
+// builds to the given value, and returns the factory.
+func (f *CompositeKeyFixtureFactory) WithName(v string) *CompositeKeyFixtureFactory {
+	return f.Sequence(func(record *CompositeKeyFixture, _ int) {
+		record.Name = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *CompositeKeyFixtureFactory) Build() *CompositeKeyFixture {
+	f.n++
+	n := f.n
+	record := new(CompositeKeyFixture)
+	record.TenantID = int64(n)
+	record.Name = fmt.Sprintf("name-%d", n)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *CompositeKeyFixtureFactory) BuildN(count int) []*CompositeKeyFixture {
+	records := make([]*CompositeKeyFixture, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a CompositeKeyFixtureStore or a MockCompositeKeyFixtureStore.
+func (f *CompositeKeyFixtureFactory) Create(store interface {
+	Insert(*CompositeKeyFixture) error
+}) (*CompositeKeyFixture, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create CompositeKeyFixture number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a CompositeKeyFixtureStore or a
+// MockCompositeKeyFixtureStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *CompositeKeyFixtureFactory) CreateN(store interface {
+	Insert(*CompositeKeyFixture) error
+}, count int) ([]*CompositeKeyFixture, error) {
+	records := make([]*CompositeKeyFixture, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// CustomerFactory builds records of the type Customer for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type CustomerFactory struct {
+	n        int
+	builders []func(record *Customer, n int)
+}
+
+// NewCustomerFactory returns a new factory of records of the type
+// Customer, whose records are numbered from 1.
+func NewCustomerFactory() *CustomerFactory {
+	return new(CustomerFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *CustomerFactory) Sequence(fn func(record *Customer, n int)) *CustomerFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithName makes the factory set the field Name of all the records it
+// builds to the given value, and returns the factory.
+func (f *CustomerFactory) WithName(v string) *CustomerFactory {
+	return f.Sequence(func(record *Customer, _ int) {
+		record.Name = v
+	})
+}
+
+// WithBillingStreet makes the factory set the field Billing.Street of all the records it
+// builds to the given value, and returns the factory.
+func (f *CustomerFactory) WithBillingStreet(v string) *CustomerFactory {
+	return f.Sequence(func(record *Customer, _ int) {
+		record.Billing.Street = v
+	})
+}
+
+// WithBillingCity makes the factory set the field Billing.City of all the records it
+// builds to the given value, and returns the factory.
+func (f *CustomerFactory) WithBillingCity(v string) *CustomerFactory {
+	return f.Sequence(func(record *Customer, _ int) {
+		record.Billing.City = v
+	})
+}
+
+// WithBillingZip makes the factory set the field Billing.Zip of all the records it
+// builds to the given value, and returns the factory.
+func (f *CustomerFactory) WithBillingZip(v *string) *CustomerFactory {
+	return f.Sequence(func(record *Customer, _ int) {
+		record.Billing.Zip = v
+	})
+}
+
+// WithShippingStreet makes the factory set the field Shipping.Street of all the records it
+// builds to the given value, and returns the factory.
+func (f *CustomerFactory) WithShippingStreet(v string) *CustomerFactory {
+	return f.Sequence(func(record *Customer, _ int) {
+		record.Shipping.Street = v
+	})
+}
+
+// WithShippingCity makes the factory set the field Shipping.City of all the records it
+// builds to the given value, and returns the factory.
+func (f *CustomerFactory) WithShippingCity(v string) *CustomerFactory {
+	return f.Sequence(func(record *Customer, _ int) {
+		record.Shipping.City = v
+	})
+}
+
+// WithShippingZip makes the factory set the field Shipping.Zip of all the records it
+// builds to the given value, and returns the factory.
+func (f *CustomerFactory) WithShippingZip(v *string) *CustomerFactory {
+	return f.Sequence(func(record *Customer, _ int) {
+		record.Shipping.Zip = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *CustomerFactory) Build() *Customer {
+	f.n++
+	n := f.n
+	record := new(Customer)
+	record.Name = fmt.Sprintf("name-%d", n)
+	record.Billing.Street = fmt.Sprintf("billing_street-%d", n)
+	record.Billing.City = fmt.Sprintf("billing_city-%d", n)
+	record.Shipping.Street = fmt.Sprintf("ship_street-%d", n)
+	record.Shipping.City = fmt.Sprintf("ship_city-%d", n)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *CustomerFactory) BuildN(count int) []*Customer {
+	records := make([]*Customer, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a CustomerStore or a MockCustomerStore.
+func (f *CustomerFactory) Create(store interface{ Insert(*Customer) error }) (*Customer, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create Customer number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a CustomerStore or a
+// MockCustomerStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *CustomerFactory) CreateN(store interface{ Insert(*Customer) error }, count int) ([]*Customer, error) {
+	records := make([]*Customer, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// EventsAllFixtureFactory builds records of the type EventsAllFixture for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type EventsAllFixtureFactory struct {
+	n        int
+	builders []func(record *EventsAllFixture, n int)
+}
+
+// NewEventsAllFixtureFactory returns a new factory of records of the type
+// EventsAllFixture, whose records are numbered from 1.
+func NewEventsAllFixtureFactory() *EventsAllFixtureFactory {
+	return new(EventsAllFixtureFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *EventsAllFixtureFactory) Sequence(fn func(record *EventsAllFixture, n int)) *EventsAllFixtureFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithID makes the factory set the field ID of all the records it
+// builds to the given value, and returns the factory.
+func (f *EventsAllFixtureFactory) WithID(v kallax.ULID) *EventsAllFixtureFactory {
+	return f.Sequence(func(record *EventsAllFixture, _ int) {
+		record.ID = v
+	})
+}
+
+// WithChecks makes the factory set the field Checks of all the records it
+// builds to the given value, and returns the factory.
+func (f *EventsAllFixtureFactory) WithChecks(v map[string]bool) *EventsAllFixtureFactory {
+	return f.Sequence(func(record *EventsAllFixture, _ int) {
+		record.Checks = v
+	})
+}
+
+// WithMustFailBefore makes the factory set the field MustFailBefore of all the records it
+// builds to the given value, and returns the factory.
+func (f *EventsAllFixtureFactory) WithMustFailBefore(v error) *EventsAllFixtureFactory {
+	return f.Sequence(func(record *EventsAllFixture, _ int) {
+		record.MustFailBefore = v
+	})
+}
+
+// WithMustFailAfter makes the factory set the field MustFailAfter of all the records it
+// builds to the given value, and returns the factory.
+func (f *EventsAllFixtureFactory) WithMustFailAfter(v error) *EventsAllFixtureFactory {
+	return f.Sequence(func(record *EventsAllFixture, _ int) {
+		record.MustFailAfter = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *EventsAllFixtureFactory) Build() *EventsAllFixture {
+	f.n++
+	n := f.n
+	record := new(EventsAllFixture)
+	record.ID = kallax.NewULID()
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *EventsAllFixtureFactory) BuildN(count int) []*EventsAllFixture {
+	records := make([]*EventsAllFixture, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a EventsAllFixtureStore or a MockEventsAllFixtureStore.
+func (f *EventsAllFixtureFactory) Create(store interface{ Insert(*EventsAllFixture) error }) (*EventsAllFixture, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create EventsAllFixture number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a EventsAllFixtureStore or a
+// MockEventsAllFixtureStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *EventsAllFixtureFactory) CreateN(store interface{ Insert(*EventsAllFixture) error }, count int) ([]*EventsAllFixture, error) {
+	records := make([]*EventsAllFixture, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// EventsFixtureFactory builds records of the type EventsFixture for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type EventsFixtureFactory struct {
+	n        int
+	builders []func(record *EventsFixture, n int)
+}
+
+// NewEventsFixtureFactory returns a new factory of records of the type
+// EventsFixture, whose records are numbered from 1.
+func NewEventsFixtureFactory() *EventsFixtureFactory {
+	return new(EventsFixtureFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *EventsFixtureFactory) Sequence(fn func(record *EventsFixture, n int)) *EventsFixtureFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithID makes the factory set the field ID of all the records it
+// builds to the given value, and returns the factory.
+func (f *EventsFixtureFactory) WithID(v kallax.ULID) *EventsFixtureFactory {
+	return f.Sequence(func(record *EventsFixture, _ int) {
+		record.ID = v
+	})
+}
+
+// WithChecks makes the factory set the field Checks of all the records it
+// builds to the given value, and returns the factory.
+func (f *EventsFixtureFactory) WithChecks(v map[string]bool) *EventsFixtureFactory {
+	return f.Sequence(func(record *EventsFixture, _ int) {
+		record.Checks = v
+	})
+}
+
+// WithMustFailBefore makes the factory set the field MustFailBefore of all the records it
+// builds to the given value, and returns the factory.
+func (f *EventsFixtureFactory) WithMustFailBefore(v error) *EventsFixtureFactory {
+	return f.Sequence(func(record *EventsFixture, _ int) {
+		record.MustFailBefore = v
+	})
+}
+
+// WithMustFailAfter makes the factory set the field MustFailAfter of all the records it
+// builds to the given value, and returns the factory.
+func (f *EventsFixtureFactory) WithMustFailAfter(v error) *EventsFixtureFactory {
+	return f.Sequence(func(record *EventsFixture, _ int) {
+		record.MustFailAfter = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *EventsFixtureFactory) Build() *EventsFixture {
+	f.n++
+	n := f.n
+	record := new(EventsFixture)
+	record.ID = kallax.NewULID()
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *EventsFixtureFactory) BuildN(count int) []*EventsFixture {
+	records := make([]*EventsFixture, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a EventsFixtureStore or a MockEventsFixtureStore.
+func (f *EventsFixtureFactory) Create(store interface{ Insert(*EventsFixture) error }) (*EventsFixture, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create EventsFixture number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a EventsFixtureStore or a
+// MockEventsFixtureStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *EventsFixtureFactory) CreateN(store interface{ Insert(*EventsFixture) error }, count int) ([]*EventsFixture, error) {
+	records := make([]*EventsFixture, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// EventsSaveFixtureFactory builds records of the type EventsSaveFixture for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type EventsSaveFixtureFactory struct {
+	n        int
+	builders []func(record *EventsSaveFixture, n int)
+}
+
+// NewEventsSaveFixtureFactory returns a new factory of records of the type
+// EventsSaveFixture, whose records are numbered from 1.
+func NewEventsSaveFixtureFactory() *EventsSaveFixtureFactory {
+	return new(EventsSaveFixtureFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *EventsSaveFixtureFactory) Sequence(fn func(record *EventsSaveFixture, n int)) *EventsSaveFixtureFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithID makes the factory set the field ID of all the records it
+// builds to the given value, and returns the factory.
+func (f *EventsSaveFixtureFactory) WithID(v kallax.ULID) *EventsSaveFixtureFactory {
+	return f.Sequence(func(record *EventsSaveFixture, _ int) {
+		record.ID = v
+	})
+}
+
+// WithChecks makes the factory set the field Checks of all the records it
+// builds to the given value, and returns the factory.
+func (f *EventsSaveFixtureFactory) WithChecks(v map[string]bool) *EventsSaveFixtureFactory {
+	return f.Sequence(func(record *EventsSaveFixture, _ int) {
+		record.Checks = v
+	})
+}
+
+// WithMustFailBefore makes the factory set the field MustFailBefore of all the records it
+// builds to the given value, and returns the factory.
+func (f *EventsSaveFixtureFactory) WithMustFailBefore(v error) *EventsSaveFixtureFactory {
+	return f.Sequence(func(record *EventsSaveFixture, _ int) {
+		record.MustFailBefore = v
+	})
+}
+
+// WithMustFailAfter makes the factory set the field MustFailAfter of all the records it
+// builds to the given value, and returns the factory.
+func (f *EventsSaveFixtureFactory) WithMustFailAfter(v error) *EventsSaveFixtureFactory {
+	return f.Sequence(func(record *EventsSaveFixture, _ int) {
+		record.MustFailAfter = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *EventsSaveFixtureFactory) Build() *EventsSaveFixture {
+	f.n++
+	n := f.n
+	record := new(EventsSaveFixture)
+	record.ID = kallax.NewULID()
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *EventsSaveFixtureFactory) BuildN(count int) []*EventsSaveFixture {
+	records := make([]*EventsSaveFixture, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a EventsSaveFixtureStore or a MockEventsSaveFixtureStore.
+func (f *EventsSaveFixtureFactory) Create(store interface {
+	Insert(*EventsSaveFixture) error
+}) (*EventsSaveFixture, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create EventsSaveFixture number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a EventsSaveFixtureStore or a
+// MockEventsSaveFixtureStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *EventsSaveFixtureFactory) CreateN(store interface {
+	Insert(*EventsSaveFixture) error
+}, count int) ([]*EventsSaveFixture, error) {
+	records := make([]*EventsSaveFixture, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// GoogleUUIDItemFactory builds records of the type GoogleUUIDItem for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type GoogleUUIDItemFactory struct {
+	n        int
+	builders []func(record *GoogleUUIDItem, n int)
+}
+
+// NewGoogleUUIDItemFactory returns a new factory of records of the type
+// GoogleUUIDItem, whose records are numbered from 1.
+func NewGoogleUUIDItemFactory() *GoogleUUIDItemFactory {
+	return new(GoogleUUIDItemFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *GoogleUUIDItemFactory) Sequence(fn func(record *GoogleUUIDItem, n int)) *GoogleUUIDItemFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithID makes the factory set the field ID of all the records it
+// builds to the given value, and returns the factory.
+func (f *GoogleUUIDItemFactory) WithID(v uuid.UUID) *GoogleUUIDItemFactory {
+	return f.Sequence(func(record *GoogleUUIDItem, _ int) {
+		record.ID = v
+	})
+}
+
+// WithToken makes the factory set the field Token of all the records it
+// builds to the given value, and returns the factory.
+func (f *GoogleUUIDItemFactory) WithToken(v uuid.UUID) *GoogleUUIDItemFactory {
+	return f.Sequence(func(record *GoogleUUIDItem, _ int) {
+		record.Token = v
+	})
+}
+
+// WithOwner makes the factory set the field Owner of all the records it
+// builds to the given value, and returns the factory.
+func (f *GoogleUUIDItemFactory) WithOwner(v *GoogleUUIDOwner) *GoogleUUIDItemFactory {
+	return f.Sequence(func(record *GoogleUUIDItem, _ int) {
+		record.Owner = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *GoogleUUIDItemFactory) Build() *GoogleUUIDItem {
+	f.n++
+	n := f.n
+	record := new(GoogleUUIDItem)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *GoogleUUIDItemFactory) BuildN(count int) []*GoogleUUIDItem {
+	records := make([]*GoogleUUIDItem, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a GoogleUUIDItemStore or a MockGoogleUUIDItemStore.
+func (f *GoogleUUIDItemFactory) Create(store interface{ Insert(*GoogleUUIDItem) error }) (*GoogleUUIDItem, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create GoogleUUIDItem number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a GoogleUUIDItemStore or a
+// MockGoogleUUIDItemStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *GoogleUUIDItemFactory) CreateN(store interface{ Insert(*GoogleUUIDItem) error }, count int) ([]*GoogleUUIDItem, error) {
+	records := make([]*GoogleUUIDItem, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// GoogleUUIDOwnerFactory builds records of the type GoogleUUIDOwner for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type GoogleUUIDOwnerFactory struct {
+	n        int
+	builders []func(record *GoogleUUIDOwner, n int)
+}
+
+// NewGoogleUUIDOwnerFactory returns a new factory of records of the type
+// GoogleUUIDOwner, whose records are numbered from 1.
+func NewGoogleUUIDOwnerFactory() *GoogleUUIDOwnerFactory {
+	return new(GoogleUUIDOwnerFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *GoogleUUIDOwnerFactory) Sequence(fn func(record *GoogleUUIDOwner, n int)) *GoogleUUIDOwnerFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithID makes the factory set the field ID of all the records it
+// builds to the given value, and returns the factory.
+func (f *GoogleUUIDOwnerFactory) WithID(v uuid.UUID) *GoogleUUIDOwnerFactory {
+	return f.Sequence(func(record *GoogleUUIDOwner, _ int) {
+		record.ID = v
+	})
+}
+
+// WithName makes the factory set the field Name of all the records it
+// builds to the given value, and returns the factory.
+func (f *GoogleUUIDOwnerFactory) WithName(v string) *GoogleUUIDOwnerFactory {
+	return f.Sequence(func(record *GoogleUUIDOwner, _ int) {
+		record.Name = v
+	})
+}
+
+// WithItems makes the factory set the field Items of all the records it
+// builds to the given value, and returns the factory.
+func (f *GoogleUUIDOwnerFactory) WithItems(v []*GoogleUUIDItem) *GoogleUUIDOwnerFactory {
+	return f.Sequence(func(record *GoogleUUIDOwner, _ int) {
+		record.Items = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *GoogleUUIDOwnerFactory) Build() *GoogleUUIDOwner {
+	f.n++
+	n := f.n
+	record := new(GoogleUUIDOwner)
+	record.Name = fmt.Sprintf("name-%d", n)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *GoogleUUIDOwnerFactory) BuildN(count int) []*GoogleUUIDOwner {
+	records := make([]*GoogleUUIDOwner, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a GoogleUUIDOwnerStore or a MockGoogleUUIDOwnerStore.
+func (f *GoogleUUIDOwnerFactory) Create(store interface{ Insert(*GoogleUUIDOwner) error }) (*GoogleUUIDOwner, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create GoogleUUIDOwner number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a GoogleUUIDOwnerStore or a
+// MockGoogleUUIDOwnerStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *GoogleUUIDOwnerFactory) CreateN(store interface{ Insert(*GoogleUUIDOwner) error }, count int) ([]*GoogleUUIDOwner, error) {
+	records := make([]*GoogleUUIDOwner, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// JSONModelFactory builds records of the type JSONModel for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type JSONModelFactory struct {
+	n        int
+	builders []func(record *JSONModel, n int)
+}
+
+// NewJSONModelFactory returns a new factory of records of the type
+// JSONModel, whose records are numbered from 1.
+func NewJSONModelFactory() *JSONModelFactory {
+	return new(JSONModelFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *JSONModelFactory) Sequence(fn func(record *JSONModel, n int)) *JSONModelFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithID makes the factory set the field ID of all the records it
+// builds to the given value, and returns the factory.
+func (f *JSONModelFactory) WithID(v kallax.ULID) *JSONModelFactory {
+	return f.Sequence(func(record *JSONModel, _ int) {
+		record.ID = v
+	})
+}
+
+// WithFoo makes the factory set the field Foo of all the records it
+// builds to the given value, and returns the factory.
+func (f *JSONModelFactory) WithFoo(v string) *JSONModelFactory {
+	return f.Sequence(func(record *JSONModel, _ int) {
+		record.Foo = v
+	})
+}
+
+// WithBar makes the factory set the field Bar of all the records it
+// builds to the given value, and returns the factory.
+func (f *JSONModelFactory) WithBar(v *Bar) *JSONModelFactory {
+	return f.Sequence(func(record *JSONModel, _ int) {
+		record.Bar = v
+	})
+}
+
+// WithBazSlice makes the factory set the field BazSlice of all the records it
+// builds to the given value, and returns the factory.
+func (f *JSONModelFactory) WithBazSlice(v []Baz) *JSONModelFactory {
+	return f.Sequence(func(record *JSONModel, _ int) {
+		record.BazSlice = v
+	})
+}
+
+// WithBaz makes the factory set the field Baz of all the records it
+// builds to the given value, and returns the factory.
+func (f *JSONModelFactory) WithBaz(v map[string]interface{}) *JSONModelFactory {
+	return f.Sequence(func(record *JSONModel, _ int) {
+		record.Baz = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *JSONModelFactory) Build() *JSONModel {
+	f.n++
+	n := f.n
+	record := new(JSONModel)
+	record.ID = kallax.NewULID()
+	record.Foo = fmt.Sprintf("foo-%d", n)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *JSONModelFactory) BuildN(count int) []*JSONModel {
+	records := make([]*JSONModel, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a JSONModelStore or a MockJSONModelStore.
+func (f *JSONModelFactory) Create(store interface{ Insert(*JSONModel) error }) (*JSONModel, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create JSONModel number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a JSONModelStore or a
+// MockJSONModelStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *JSONModelFactory) CreateN(store interface{ Insert(*JSONModel) error }, count int) ([]*JSONModel, error) {
+	records := make([]*JSONModel, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// LockedPostFactory builds records of the type LockedPost for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type LockedPostFactory struct {
+	n        int
+	builders []func(record *LockedPost, n int)
+}
+
+// NewLockedPostFactory returns a new factory of records of the type
+// LockedPost, whose records are numbered from 1.
+func NewLockedPostFactory() *LockedPostFactory {
+	return new(LockedPostFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *LockedPostFactory) Sequence(fn func(record *LockedPost, n int)) *LockedPostFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithTitle makes the factory set the field Title of all the records it
+// builds to the given value, and returns the factory.
+func (f *LockedPostFactory) WithTitle(v string) *LockedPostFactory {
+	return f.Sequence(func(record *LockedPost, _ int) {
+		record.Title = v
+	})
+}
+
+// WithVersion makes the factory set the field Version of all the records it
+// builds to the given value, and returns the factory.
+func (f *LockedPostFactory) WithVersion(v int) *LockedPostFactory {
+	return f.Sequence(func(record *LockedPost, _ int) {
+		record.Version = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *LockedPostFactory) Build() *LockedPost {
+	f.n++
+	n := f.n
+	record := new(LockedPost)
+	record.Title = fmt.Sprintf("title-%d", n)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *LockedPostFactory) BuildN(count int) []*LockedPost {
+	records := make([]*LockedPost, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a LockedPostStore or a MockLockedPostStore.
+func (f *LockedPostFactory) Create(store interface{ Insert(*LockedPost) error }) (*LockedPost, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create LockedPost number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a LockedPostStore or a
+// MockLockedPostStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *LockedPostFactory) CreateN(store interface{ Insert(*LockedPost) error }, count int) ([]*LockedPost, error) {
+	records := make([]*LockedPost, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// MultiKeySortFixtureFactory builds records of the type MultiKeySortFixture for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type MultiKeySortFixtureFactory struct {
+	n        int
+	builders []func(record *MultiKeySortFixture, n int)
+}
+
+// NewMultiKeySortFixtureFactory returns a new factory of records of the type
+// MultiKeySortFixture, whose records are numbered from 1.
+func NewMultiKeySortFixtureFactory() *MultiKeySortFixtureFactory {
+	return new(MultiKeySortFixtureFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *MultiKeySortFixtureFactory) Sequence(fn func(record *MultiKeySortFixture, n int)) *MultiKeySortFixtureFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithID makes the factory set the field ID of all the records it
+// builds to the given value, and returns the factory.
+func (f *MultiKeySortFixtureFactory) WithID(v kallax.ULID) *MultiKeySortFixtureFactory {
+	return f.Sequence(func(record *MultiKeySortFixture, _ int) {
+		record.ID = v
+	})
+}
+
+// WithName makes the factory set the field Name of all the records it
+// builds to the given value, and returns the factory.
+func (f *MultiKeySortFixtureFactory) WithName(v string) *MultiKeySortFixtureFactory {
+	return f.Sequence(func(record *MultiKeySortFixture, _ int) {
+		record.Name = v
+	})
+}
+
+// WithStart makes the factory set the field Start of all the records it
+// builds to the given value, and returns the factory.
+func (f *MultiKeySortFixtureFactory) WithStart(v time.Time) *MultiKeySortFixtureFactory {
+	return f.Sequence(func(record *MultiKeySortFixture, _ int) {
+		record.Start = v
+	})
+}
+
+// WithEnd makes the factory set the field End of all the records it
+// builds to the given value, and returns the factory.
+func (f *MultiKeySortFixtureFactory) WithEnd(v time.Time) *MultiKeySortFixtureFactory {
+	return f.Sequence(func(record *MultiKeySortFixture, _ int) {
+		record.End = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *MultiKeySortFixtureFactory) Build() *MultiKeySortFixture {
+	f.n++
+	n := f.n
+	record := new(MultiKeySortFixture)
+	record.ID = kallax.NewULID()
+	record.Name = fmt.Sprintf("name-%d", n)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *MultiKeySortFixtureFactory) BuildN(count int) []*MultiKeySortFixture {
+	records := make([]*MultiKeySortFixture, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a MultiKeySortFixtureStore or a MockMultiKeySortFixtureStore.
+func (f *MultiKeySortFixtureFactory) Create(store interface {
+	Insert(*MultiKeySortFixture) error
+}) (*MultiKeySortFixture, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create MultiKeySortFixture number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a MultiKeySortFixtureStore or a
+// MockMultiKeySortFixtureStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *MultiKeySortFixtureFactory) CreateN(store interface {
+	Insert(*MultiKeySortFixture) error
+}, count int) ([]*MultiKeySortFixture, error) {
+	records := make([]*MultiKeySortFixture, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// NotifiedPostFactory builds records of the type NotifiedPost for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type NotifiedPostFactory struct {
+	n        int
+	builders []func(record *NotifiedPost, n int)
+}
+
+// NewNotifiedPostFactory returns a new factory of records of the type
+// NotifiedPost, whose records are numbered from 1.
+func NewNotifiedPostFactory() *NotifiedPostFactory {
+	return new(NotifiedPostFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *NotifiedPostFactory) Sequence(fn func(record *NotifiedPost, n int)) *NotifiedPostFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithTitle makes the factory set the field Title of all the records it
+// builds to the given value, and returns the factory.
+func (f *NotifiedPostFactory) WithTitle(v string) *NotifiedPostFactory {
+	return f.Sequence(func(record *NotifiedPost, _ int) {
+		record.Title = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *NotifiedPostFactory) Build() *NotifiedPost {
+	f.n++
+	n := f.n
+	record := new(NotifiedPost)
+	record.Title = fmt.Sprintf("title-%d", n)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *NotifiedPostFactory) BuildN(count int) []*NotifiedPost {
+	records := make([]*NotifiedPost, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a NotifiedPostStore or a MockNotifiedPostStore.
+func (f *NotifiedPostFactory) Create(store interface{ Insert(*NotifiedPost) error }) (*NotifiedPost, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create NotifiedPost number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a NotifiedPostStore or a
+// MockNotifiedPostStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *NotifiedPostFactory) CreateN(store interface{ Insert(*NotifiedPost) error }, count int) ([]*NotifiedPost, error) {
+	records := make([]*NotifiedPost, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// NullableFactory builds records of the type Nullable for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type NullableFactory struct {
+	n        int
+	builders []func(record *Nullable, n int)
+}
+
+// NewNullableFactory returns a new factory of records of the type
+// Nullable, whose records are numbered from 1.
+func NewNullableFactory() *NullableFactory {
+	return new(NullableFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *NullableFactory) Sequence(fn func(record *Nullable, n int)) *NullableFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithT makes the factory set the field T of all the records it
+// builds to the given value, and returns the factory.
+func (f *NullableFactory) WithT(v *time.Time) *NullableFactory {
+	return f.Sequence(func(record *Nullable, _ int) {
+		record.T = v
+	})
+}
+
+// WithSomeJSON makes the factory set the field SomeJSON of all the records it
+// builds to the given value, and returns the factory.
+func (f *NullableFactory) WithSomeJSON(v *SomeJSON) *NullableFactory {
+	return f.Sequence(func(record *Nullable, _ int) {
+		record.SomeJSON = v
+	})
+}
+
+// WithScanner makes the factory set the field Scanner of all the records it
+// builds to the given value, and returns the factory.
+func (f *NullableFactory) WithScanner(v *kallax.ULID) *NullableFactory {
+	return f.Sequence(func(record *Nullable, _ int) {
+		record.Scanner = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *NullableFactory) Build() *Nullable {
+	f.n++
+	n := f.n
+	record := new(Nullable)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *NullableFactory) BuildN(count int) []*Nullable {
+	records := make([]*Nullable, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a NullableStore or a MockNullableStore.
+func (f *NullableFactory) Create(store interface{ Insert(*Nullable) error }) (*Nullable, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create Nullable number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a NullableStore or a
+// MockNullableStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *NullableFactory) CreateN(store interface{ Insert(*Nullable) error }, count int) ([]*Nullable, error) {
+	records := make([]*Nullable, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// ParentFactory builds records of the type Parent for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type ParentFactory struct {
+	n        int
+	builders []func(record *Parent, n int)
+}
+
+// NewParentFactory returns a new factory of records of the type
+// Parent, whose records are numbered from 1.
+func NewParentFactory() *ParentFactory {
+	return new(ParentFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *ParentFactory) Sequence(fn func(record *Parent, n int)) *ParentFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithName makes the factory set the field Name of all the records it
+// builds to the given value, and returns the factory.
+func (f *ParentFactory) WithName(v string) *ParentFactory {
+	return f.Sequence(func(record *Parent, _ int) {
+		record.Name = v
+	})
+}
+
+// WithChildren makes the factory set the field Children of all the records it
+// builds to the given value, and returns the factory.
+func (f *ParentFactory) WithChildren(v []*Child) *ParentFactory {
+	return f.Sequence(func(record *Parent, _ int) {
+		record.Children = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *ParentFactory) Build() *Parent {
+	f.n++
+	n := f.n
+	record := new(Parent)
+	record.Name = fmt.Sprintf("name-%d", n)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *ParentFactory) BuildN(count int) []*Parent {
+	records := make([]*Parent, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a ParentStore or a MockParentStore.
+func (f *ParentFactory) Create(store interface{ Insert(*Parent) error }) (*Parent, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create Parent number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a ParentStore or a
+// MockParentStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *ParentFactory) CreateN(store interface{ Insert(*Parent) error }, count int) ([]*Parent, error) {
+	records := make([]*Parent, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// ParentNoPtrFactory builds records of the type ParentNoPtr for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type ParentNoPtrFactory struct {
+	n        int
+	builders []func(record *ParentNoPtr, n int)
+}
+
+// NewParentNoPtrFactory returns a new factory of records of the type
+// ParentNoPtr, whose records are numbered from 1.
+func NewParentNoPtrFactory() *ParentNoPtrFactory {
+	return new(ParentNoPtrFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *ParentNoPtrFactory) Sequence(fn func(record *ParentNoPtr, n int)) *ParentNoPtrFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithName makes the factory set the field Name of all the records it
+// builds to the given value, and returns the factory.
+func (f *ParentNoPtrFactory) WithName(v string) *ParentNoPtrFactory {
+	return f.Sequence(func(record *ParentNoPtr, _ int) {
+		record.Name = v
+	})
+}
+
+// WithChildren makes the factory set the field Children of all the records it
+// builds to the given value, and returns the factory.
+func (f *ParentNoPtrFactory) WithChildren(v []Child) *ParentNoPtrFactory {
+	return f.Sequence(func(record *ParentNoPtr, _ int) {
+		record.Children = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *ParentNoPtrFactory) Build() *ParentNoPtr {
+	f.n++
+	n := f.n
+	record := new(ParentNoPtr)
+	record.Name = fmt.Sprintf("name-%d", n)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *ParentNoPtrFactory) BuildN(count int) []*ParentNoPtr {
+	records := make([]*ParentNoPtr, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a ParentNoPtrStore or a MockParentNoPtrStore.
+func (f *ParentNoPtrFactory) Create(store interface{ Insert(*ParentNoPtr) error }) (*ParentNoPtr, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create ParentNoPtr number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a ParentNoPtrStore or a
+// MockParentNoPtrStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *ParentNoPtrFactory) CreateN(store interface{ Insert(*ParentNoPtr) error }, count int) ([]*ParentNoPtr, error) {
+	records := make([]*ParentNoPtr, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// PersonFactory builds records of the type Person for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type PersonFactory struct {
+	n        int
+	builders []func(record *Person, n int)
+}
+
+// NewPersonFactory returns a new factory of records of the type
+// Person, whose records are numbered from 1.
+func NewPersonFactory() *PersonFactory {
+	return new(PersonFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *PersonFactory) Sequence(fn func(record *Person, n int)) *PersonFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithName makes the factory set the field Name of all the records it
+// builds to the given value, and returns the factory.
+func (f *PersonFactory) WithName(v string) *PersonFactory {
+	return f.Sequence(func(record *Person, _ int) {
+		record.Name = v
+	})
+}
+
+// WithPets makes the factory set the field Pets of all the records it
+// builds to the given value, and returns the factory.
+func (f *PersonFactory) WithPets(v []*Pet) *PersonFactory {
+	return f.Sequence(func(record *Person, _ int) {
+		record.Pets = v
+	})
+}
+
+// WithCar makes the factory set the field Car of all the records it
+// builds to the given value, and returns the factory.
+func (f *PersonFactory) WithCar(v *Car) *PersonFactory {
+	return f.Sequence(func(record *Person, _ int) {
+		record.Car = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *PersonFactory) Build() *Person {
+	f.n++
+	n := f.n
+	record := new(Person)
+	record.Name = fmt.Sprintf("name-%d", n)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *PersonFactory) BuildN(count int) []*Person {
+	records := make([]*Person, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a PersonStore or a MockPersonStore.
+func (f *PersonFactory) Create(store interface{ Insert(*Person) error }) (*Person, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create Person number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a PersonStore or a
+// MockPersonStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *PersonFactory) CreateN(store interface{ Insert(*Person) error }, count int) ([]*Person, error) {
+	records := make([]*Person, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// PetFactory builds records of the type Pet for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type PetFactory struct {
+	n        int
+	builders []func(record *Pet, n int)
+}
+
+// NewPetFactory returns a new factory of records of the type
+// Pet, whose records are numbered from 1.
+func NewPetFactory() *PetFactory {
+	return new(PetFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *PetFactory) Sequence(fn func(record *Pet, n int)) *PetFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithID makes the factory set the field ID of all the records it
+// builds to the given value, and returns the factory.
+func (f *PetFactory) WithID(v kallax.ULID) *PetFactory {
+	return f.Sequence(func(record *Pet, _ int) {
+		record.ID = v
+	})
+}
+
+// WithName makes the factory set the field Name of all the records it
+// builds to the given value, and returns the factory.
+func (f *PetFactory) WithName(v string) *PetFactory {
+	return f.Sequence(func(record *Pet, _ int) {
+		record.Name = v
+	})
+}
+
+// WithKind makes the factory set the field Kind of all the records it
+// builds to the given value, and returns the factory.
+func (f *PetFactory) WithKind(v string) *PetFactory {
+	return f.Sequence(func(record *Pet, _ int) {
+		record.Kind = v
+	})
+}
+
+// WithOwner makes the factory set the field Owner of all the records it
+// builds to the given value, and returns the factory.
+func (f *PetFactory) WithOwner(v *Person) *PetFactory {
+	return f.Sequence(func(record *Pet, _ int) {
+		record.Owner = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *PetFactory) Build() *Pet {
+	f.n++
+	n := f.n
+	record := new(Pet)
+	record.ID = kallax.NewULID()
+	record.Name = fmt.Sprintf("name-%d", n)
+	record.Kind = fmt.Sprintf("kind-%d", n)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *PetFactory) BuildN(count int) []*Pet {
+	records := make([]*Pet, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a PetStore or a MockPetStore.
+func (f *PetFactory) Create(store interface{ Insert(*Pet) error }) (*Pet, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create Pet number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a PetStore or a
+// MockPetStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *PetFactory) CreateN(store interface{ Insert(*Pet) error }, count int) ([]*Pet, error) {
+	records := make([]*Pet, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// PostFactory builds records of the type Post for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type PostFactory struct {
+	n        int
+	builders []func(record *Post, n int)
+}
+
+// NewPostFactory returns a new factory of records of the type
+// Post, whose records are numbered from 1.
+func NewPostFactory() *PostFactory {
+	return new(PostFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *PostFactory) Sequence(fn func(record *Post, n int)) *PostFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithTitle makes the factory set the field Title of all the records it
+// builds to the given value, and returns the factory.
+func (f *PostFactory) WithTitle(v string) *PostFactory {
+	return f.Sequence(func(record *Post, _ int) {
+		record.Title = v
+	})
+}
+
+// WithTags makes the factory set the field Tags of all the records it
+// builds to the given value, and returns the factory.
+func (f *PostFactory) WithTags(v []*Tag) *PostFactory {
+	return f.Sequence(func(record *Post, _ int) {
+		record.Tags = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *PostFactory) Build() *Post {
+	f.n++
+	n := f.n
+	record := new(Post)
+	record.Title = fmt.Sprintf("title-%d", n)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *PostFactory) BuildN(count int) []*Post {
+	records := make([]*Post, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a PostStore or a MockPostStore.
+func (f *PostFactory) Create(store interface{ Insert(*Post) error }) (*Post, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create Post number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a PostStore or a
+// MockPostStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *PostFactory) CreateN(store interface{ Insert(*Post) error }, count int) ([]*Post, error) {
+	records := make([]*Post, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// QueryFixtureFactory builds records of the type QueryFixture for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type QueryFixtureFactory struct {
+	n        int
+	builders []func(record *QueryFixture, n int)
+}
+
+// NewQueryFixtureFactory returns a new factory of records of the type
+// QueryFixture, whose records are numbered from 1.
+func NewQueryFixtureFactory() *QueryFixtureFactory {
+	return new(QueryFixtureFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *QueryFixtureFactory) Sequence(fn func(record *QueryFixture, n int)) *QueryFixtureFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithID makes the factory set the field ID of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithID(v kallax.ULID) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.ID = v
+	})
+}
+
+// WithRelation makes the factory set the field Relation of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithRelation(v *QueryRelationFixture) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.Relation = v
+	})
+}
+
+// WithInverse makes the factory set the field Inverse of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithInverse(v *QueryRelationFixture) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.Inverse = v
+	})
+}
+
+// WithNRelation makes the factory set the field NRelation of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithNRelation(v []*QueryRelationFixture) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.NRelation = v
+	})
+}
+
+// WithEmbedded makes the factory set the field Embedded of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithEmbedded(v fixtures.QueryDummy) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.Embedded = v
+	})
+}
+
+// WithInline makes the factory set the field Inline.Inline of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithInline(v string) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.Inline.Inline = v
+	})
+}
+
+// WithMapOfString makes the factory set the field MapOfString of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithMapOfString(v map[string]string) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.MapOfString = v
+	})
+}
+
+// WithMapOfInterface makes the factory set the field MapOfInterface of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithMapOfInterface(v map[string]interface{}) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.MapOfInterface = v
+	})
+}
+
+// WithMapOfSomeType makes the factory set the field MapOfSomeType of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithMapOfSomeType(v map[string]fixtures.QueryDummy) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.MapOfSomeType = v
+	})
+}
+
+// WithFoo makes the factory set the field Foo of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithFoo(v string) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.Foo = v
+	})
+}
+
+// WithStringProperty makes the factory set the field StringProperty of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithStringProperty(v string) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.StringProperty = v
+	})
+}
+
+// WithInteger makes the factory set the field Integer of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithInteger(v int) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.Integer = v
+	})
+}
+
+// WithInteger64 makes the factory set the field Integer64 of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithInteger64(v int64) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.Integer64 = v
+	})
+}
+
+// WithFloat32 makes the factory set the field Float32 of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithFloat32(v float32) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.Float32 = v
+	})
+}
+
+// WithBoolean makes the factory set the field Boolean of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithBoolean(v bool) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.Boolean = v
+	})
+}
+
+// WithArrayParam makes the factory set the field ArrayParam of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithArrayParam(v [3]string) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.ArrayParam = v
+	})
+}
+
+// WithSliceParam makes the factory set the field SliceParam of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithSliceParam(v []string) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.SliceParam = v
+	})
+}
+
+// WithAliasArrayParam makes the factory set the field AliasArrayParam of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithAliasArrayParam(v fixtures.AliasArray) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.AliasArrayParam = v
+	})
+}
+
+// WithAliasSliceParam makes the factory set the field AliasSliceParam of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithAliasSliceParam(v fixtures.AliasSlice) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.AliasSliceParam = v
+	})
+}
+
+// WithAliasStringParam makes the factory set the field AliasStringParam of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithAliasStringParam(v fixtures.AliasString) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.AliasStringParam = v
+	})
+}
+
+// WithAliasIntParam makes the factory set the field AliasIntParam of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithAliasIntParam(v fixtures.AliasInt) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.AliasIntParam = v
+	})
+}
+
+// WithDummyParam makes the factory set the field DummyParam of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithDummyParam(v fixtures.QueryDummy) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.DummyParam = v
+	})
+}
+
+// WithAliasDummyParam makes the factory set the field AliasDummyParam of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithAliasDummyParam(v fixtures.AliasDummyParam) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.AliasDummyParam = v
+	})
+}
+
+// WithSliceDummyParam makes the factory set the field SliceDummyParam of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithSliceDummyParam(v []fixtures.QueryDummy) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.SliceDummyParam = v
+	})
+}
+
+// WithIDPropertyParam makes the factory set the field IDPropertyParam of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithIDPropertyParam(v kallax.ULID) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.IDPropertyParam = v
+	})
+}
+
+// WithInterfacePropParam makes the factory set the field InterfacePropParam of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithInterfacePropParam(v fixtures.InterfaceImplementation) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.InterfacePropParam = v
+	})
+}
+
+// WithURLParam makes the factory set the field URLParam of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithURLParam(v url.URL) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.URLParam = v
+	})
+}
+
+// WithTimeParam makes the factory set the field TimeParam of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithTimeParam(v time.Time) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.TimeParam = v
+	})
+}
+
+// WithAliasArrAliasStringParam makes the factory set the field AliasArrAliasStringParam of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithAliasArrAliasStringParam(v fixtures.AliasArrAliasString) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.AliasArrAliasStringParam = v
+	})
+}
+
+// WithAliasHereArrayParam makes the factory set the field AliasHereArrayParam of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithAliasHereArrayParam(v AliasHereArray) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.AliasHereArrayParam = v
+	})
+}
+
+// WithArrayAliasHereStringParam makes the factory set the field ArrayAliasHereStringParam of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithArrayAliasHereStringParam(v []AliasHereString) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.ArrayAliasHereStringParam = v
+	})
+}
+
+// WithScannerValuerParam makes the factory set the field ScannerValuerParam of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryFixtureFactory) WithScannerValuerParam(v ScannerValuer) *QueryFixtureFactory {
+	return f.Sequence(func(record *QueryFixture, _ int) {
+		record.ScannerValuerParam = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *QueryFixtureFactory) Build() *QueryFixture {
+	f.n++
+	n := f.n
+	record := new(QueryFixture)
+	record.ID = kallax.NewULID()
+	record.Inline.Inline = fmt.Sprintf("inline-%d", n)
+	record.Foo = fmt.Sprintf("foo-%d", n)
+	record.StringProperty = fmt.Sprintf("string_property-%d", n)
+	record.AliasStringParam = fixtures.AliasString(fmt.Sprintf("alias_string_param-%d", n))
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *QueryFixtureFactory) BuildN(count int) []*QueryFixture {
+	records := make([]*QueryFixture, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a QueryFixtureStore or a MockQueryFixtureStore.
+func (f *QueryFixtureFactory) Create(store interface{ Insert(*QueryFixture) error }) (*QueryFixture, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create QueryFixture number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a QueryFixtureStore or a
+// MockQueryFixtureStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *QueryFixtureFactory) CreateN(store interface{ Insert(*QueryFixture) error }, count int) ([]*QueryFixture, error) {
+	records := make([]*QueryFixture, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// QueryRelationFixtureFactory builds records of the type QueryRelationFixture for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type QueryRelationFixtureFactory struct {
+	n        int
+	builders []func(record *QueryRelationFixture, n int)
+}
+
+// NewQueryRelationFixtureFactory returns a new factory of records of the type
+// QueryRelationFixture, whose records are numbered from 1.
+func NewQueryRelationFixtureFactory() *QueryRelationFixtureFactory {
+	return new(QueryRelationFixtureFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *QueryRelationFixtureFactory) Sequence(fn func(record *QueryRelationFixture, n int)) *QueryRelationFixtureFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithID makes the factory set the field ID of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryRelationFixtureFactory) WithID(v kallax.ULID) *QueryRelationFixtureFactory {
+	return f.Sequence(func(record *QueryRelationFixture, _ int) {
+		record.ID = v
+	})
+}
+
+// WithName makes the factory set the field Name of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryRelationFixtureFactory) WithName(v string) *QueryRelationFixtureFactory {
+	return f.Sequence(func(record *QueryRelationFixture, _ int) {
+		record.Name = v
+	})
+}
+
+// WithOwner makes the factory set the field Owner of all the records it
+// builds to the given value, and returns the factory.
+func (f *QueryRelationFixtureFactory) WithOwner(v *QueryFixture) *QueryRelationFixtureFactory {
+	return f.Sequence(func(record *QueryRelationFixture, _ int) {
+		record.Owner = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *QueryRelationFixtureFactory) Build() *QueryRelationFixture {
+	f.n++
+	n := f.n
+	record := new(QueryRelationFixture)
+	record.ID = kallax.NewULID()
+	record.Name = fmt.Sprintf("name-%d", n)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *QueryRelationFixtureFactory) BuildN(count int) []*QueryRelationFixture {
+	records := make([]*QueryRelationFixture, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a QueryRelationFixtureStore or a MockQueryRelationFixtureStore.
+func (f *QueryRelationFixtureFactory) Create(store interface {
+	Insert(*QueryRelationFixture) error
+}) (*QueryRelationFixture, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create QueryRelationFixture number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a QueryRelationFixtureStore or a
+// MockQueryRelationFixtureStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *QueryRelationFixtureFactory) CreateN(store interface {
+	Insert(*QueryRelationFixture) error
+}, count int) ([]*QueryRelationFixture, error) {
+	records := make([]*QueryRelationFixture, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// ResultSetFixtureFactory builds records of the type ResultSetFixture for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type ResultSetFixtureFactory struct {
+	n        int
+	builders []func(record *ResultSetFixture, n int)
+}
+
+// NewResultSetFixtureFactory returns a new factory of records of the type
+// ResultSetFixture, whose records are numbered from 1.
+func NewResultSetFixtureFactory() *ResultSetFixtureFactory {
+	return new(ResultSetFixtureFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *ResultSetFixtureFactory) Sequence(fn func(record *ResultSetFixture, n int)) *ResultSetFixtureFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithID makes the factory set the field ID of all the records it
+// builds to the given value, and returns the factory.
+func (f *ResultSetFixtureFactory) WithID(v kallax.ULID) *ResultSetFixtureFactory {
+	return f.Sequence(func(record *ResultSetFixture, _ int) {
+		record.ID = v
+	})
+}
+
+// WithFoo makes the factory set the field Foo of all the records it
+// builds to the given value, and returns the factory.
+func (f *ResultSetFixtureFactory) WithFoo(v string) *ResultSetFixtureFactory {
+	return f.Sequence(func(record *ResultSetFixture, _ int) {
+		record.Foo = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *ResultSetFixtureFactory) Build() *ResultSetFixture {
+	f.n++
+	n := f.n
+	record := new(ResultSetFixture)
+	record.ID = kallax.NewULID()
+	record.Foo = fmt.Sprintf("foo-%d", n)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *ResultSetFixtureFactory) BuildN(count int) []*ResultSetFixture {
+	records := make([]*ResultSetFixture, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a ResultSetFixtureStore or a MockResultSetFixtureStore.
+func (f *ResultSetFixtureFactory) Create(store interface{ Insert(*ResultSetFixture) error }) (*ResultSetFixture, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create ResultSetFixture number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a ResultSetFixtureStore or a
+// MockResultSetFixtureStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *ResultSetFixtureFactory) CreateN(store interface{ Insert(*ResultSetFixture) error }, count int) ([]*ResultSetFixture, error) {
+	records := make([]*ResultSetFixture, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// SchemaFixtureFactory builds records of the type SchemaFixture for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type SchemaFixtureFactory struct {
+	n        int
+	builders []func(record *SchemaFixture, n int)
+}
+
+// NewSchemaFixtureFactory returns a new factory of records of the type
+// SchemaFixture, whose records are numbered from 1.
+func NewSchemaFixtureFactory() *SchemaFixtureFactory {
+	return new(SchemaFixtureFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *SchemaFixtureFactory) Sequence(fn func(record *SchemaFixture, n int)) *SchemaFixtureFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithID makes the factory set the field ID of all the records it
+// builds to the given value, and returns the factory.
+func (f *SchemaFixtureFactory) WithID(v kallax.ULID) *SchemaFixtureFactory {
+	return f.Sequence(func(record *SchemaFixture, _ int) {
+		record.ID = v
+	})
+}
+
+// WithString makes the factory set the field String of all the records it
+// builds to the given value, and returns the factory.
+func (f *SchemaFixtureFactory) WithString(v string) *SchemaFixtureFactory {
+	return f.Sequence(func(record *SchemaFixture, _ int) {
+		record.String = v
+	})
+}
+
+// WithInt makes the factory set the field Int of all the records it
+// builds to the given value, and returns the factory.
+func (f *SchemaFixtureFactory) WithInt(v int) *SchemaFixtureFactory {
+	return f.Sequence(func(record *SchemaFixture, _ int) {
+		record.Int = v
+	})
+}
+
+// WithNested makes the factory set the field Nested of all the records it
+// builds to the given value, and returns the factory.
+func (f *SchemaFixtureFactory) WithNested(v *SchemaFixture) *SchemaFixtureFactory {
+	return f.Sequence(func(record *SchemaFixture, _ int) {
+		record.Nested = v
+	})
+}
+
+// WithInline makes the factory set the field Inline.Inline of all the records it
+// builds to the given value, and returns the factory.
+func (f *SchemaFixtureFactory) WithInline(v string) *SchemaFixtureFactory {
+	return f.Sequence(func(record *SchemaFixture, _ int) {
+		record.Inline.Inline = v
+	})
+}
+
+// WithMapOfString makes the factory set the field MapOfString of all the records it
+// builds to the given value, and returns the factory.
+func (f *SchemaFixtureFactory) WithMapOfString(v map[string]string) *SchemaFixtureFactory {
+	return f.Sequence(func(record *SchemaFixture, _ int) {
+		record.MapOfString = v
+	})
+}
+
+// WithMapOfInterface makes the factory set the field MapOfInterface of all the records it
+// builds to the given value, and returns the factory.
+func (f *SchemaFixtureFactory) WithMapOfInterface(v map[string]interface{}) *SchemaFixtureFactory {
+	return f.Sequence(func(record *SchemaFixture, _ int) {
+		record.MapOfInterface = v
+	})
+}
+
+// WithMapOfSomeType makes the factory set the field MapOfSomeType of all the records it
+// builds to the given value, and returns the factory.
+func (f *SchemaFixtureFactory) WithMapOfSomeType(v map[string]struct{ Foo string }) *SchemaFixtureFactory {
+	return f.Sequence(func(record *SchemaFixture, _ int) {
+		record.MapOfSomeType = v
+	})
+}
+
+// WithInverse makes the factory set the field Inverse of all the records it
+// builds to the given value, and returns the factory.
+func (f *SchemaFixtureFactory) WithInverse(v *SchemaRelationshipFixture) *SchemaFixtureFactory {
+	return f.Sequence(func(record *SchemaFixture, _ int) {
+		record.Inverse = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *SchemaFixtureFactory) Build() *SchemaFixture {
+	f.n++
+	n := f.n
+	record := new(SchemaFixture)
+	record.ID = kallax.NewULID()
+	record.String = fmt.Sprintf("string-%d", n)
+	record.Inline.Inline = fmt.Sprintf("inline-%d", n)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *SchemaFixtureFactory) BuildN(count int) []*SchemaFixture {
+	records := make([]*SchemaFixture, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a SchemaFixtureStore or a MockSchemaFixtureStore.
+func (f *SchemaFixtureFactory) Create(store interface{ Insert(*SchemaFixture) error }) (*SchemaFixture, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create SchemaFixture number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a SchemaFixtureStore or a
+// MockSchemaFixtureStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *SchemaFixtureFactory) CreateN(store interface{ Insert(*SchemaFixture) error }, count int) ([]*SchemaFixture, error) {
+	records := make([]*SchemaFixture, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// SchemaRelationshipFixtureFactory builds records of the type SchemaRelationshipFixture for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type SchemaRelationshipFixtureFactory struct {
+	n        int
+	builders []func(record *SchemaRelationshipFixture, n int)
+}
+
+// NewSchemaRelationshipFixtureFactory returns a new factory of records of the type
+// SchemaRelationshipFixture, whose records are numbered from 1.
+func NewSchemaRelationshipFixtureFactory() *SchemaRelationshipFixtureFactory {
+	return new(SchemaRelationshipFixtureFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *SchemaRelationshipFixtureFactory) Sequence(fn func(record *SchemaRelationshipFixture, n int)) *SchemaRelationshipFixtureFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithID makes the factory set the field ID of all the records it
+// builds to the given value, and returns the factory.
+func (f *SchemaRelationshipFixtureFactory) WithID(v kallax.ULID) *SchemaRelationshipFixtureFactory {
+	return f.Sequence(func(record *SchemaRelationshipFixture, _ int) {
+		record.ID = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *SchemaRelationshipFixtureFactory) Build() *SchemaRelationshipFixture {
+	f.n++
+	n := f.n
+	record := new(SchemaRelationshipFixture)
+	record.ID = kallax.NewULID()
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *SchemaRelationshipFixtureFactory) BuildN(count int) []*SchemaRelationshipFixture {
+	records := make([]*SchemaRelationshipFixture, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a SchemaRelationshipFixtureStore or a MockSchemaRelationshipFixtureStore.
+func (f *SchemaRelationshipFixtureFactory) Create(store interface {
+	Insert(*SchemaRelationshipFixture) error
+}) (*SchemaRelationshipFixture, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create SchemaRelationshipFixture number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a SchemaRelationshipFixtureStore or a
+// MockSchemaRelationshipFixtureStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *SchemaRelationshipFixtureFactory) CreateN(store interface {
+	Insert(*SchemaRelationshipFixture) error
+}, count int) ([]*SchemaRelationshipFixture, error) {
+	records := make([]*SchemaRelationshipFixture, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// SoftDeletedPostFactory builds records of the type SoftDeletedPost for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type SoftDeletedPostFactory struct {
+	n        int
+	builders []func(record *SoftDeletedPost, n int)
+}
+
+// NewSoftDeletedPostFactory returns a new factory of records of the type
+// SoftDeletedPost, whose records are numbered from 1.
+func NewSoftDeletedPostFactory() *SoftDeletedPostFactory {
+	return new(SoftDeletedPostFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *SoftDeletedPostFactory) Sequence(fn func(record *SoftDeletedPost, n int)) *SoftDeletedPostFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithTitle makes the factory set the field Title of all the records it
+// builds to the given value, and returns the factory.
+func (f *SoftDeletedPostFactory) WithTitle(v string) *SoftDeletedPostFactory {
+	return f.Sequence(func(record *SoftDeletedPost, _ int) {
+		record.Title = v
+	})
+}
+
+// WithDeletedAt makes the factory set the field DeletedAt of all the records it
+// builds to the given value, and returns the factory.
+func (f *SoftDeletedPostFactory) WithDeletedAt(v *time.Time) *SoftDeletedPostFactory {
+	return f.Sequence(func(record *SoftDeletedPost, _ int) {
+		record.DeletedAt = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *SoftDeletedPostFactory) Build() *SoftDeletedPost {
+	f.n++
+	n := f.n
+	record := new(SoftDeletedPost)
+	record.Title = fmt.Sprintf("title-%d", n)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *SoftDeletedPostFactory) BuildN(count int) []*SoftDeletedPost {
+	records := make([]*SoftDeletedPost, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a SoftDeletedPostStore or a MockSoftDeletedPostStore.
+func (f *SoftDeletedPostFactory) Create(store interface{ Insert(*SoftDeletedPost) error }) (*SoftDeletedPost, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create SoftDeletedPost number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a SoftDeletedPostStore or a
+// MockSoftDeletedPostStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *SoftDeletedPostFactory) CreateN(store interface{ Insert(*SoftDeletedPost) error }, count int) ([]*SoftDeletedPost, error) {
+	records := make([]*SoftDeletedPost, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// StoreFixtureFactory builds records of the type StoreFixture for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type StoreFixtureFactory struct {
+	n        int
+	builders []func(record *StoreFixture, n int)
+}
+
+// NewStoreFixtureFactory returns a new factory of records of the type
+// StoreFixture, whose records are numbered from 1.
+func NewStoreFixtureFactory() *StoreFixtureFactory {
+	return new(StoreFixtureFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *StoreFixtureFactory) Sequence(fn func(record *StoreFixture, n int)) *StoreFixtureFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithID makes the factory set the field ID of all the records it
+// builds to the given value, and returns the factory.
+func (f *StoreFixtureFactory) WithID(v kallax.ULID) *StoreFixtureFactory {
+	return f.Sequence(func(record *StoreFixture, _ int) {
+		record.ID = v
+	})
+}
+
+// WithFoo makes the factory set the field Foo of all the records it
+// builds to the given value, and returns the factory.
+func (f *StoreFixtureFactory) WithFoo(v string) *StoreFixtureFactory {
+	return f.Sequence(func(record *StoreFixture, _ int) {
+		record.Foo = v
+	})
+}
+
+// WithSliceProp makes the factory set the field SliceProp of all the records it
+// builds to the given value, and returns the factory.
+func (f *StoreFixtureFactory) WithSliceProp(v []string) *StoreFixtureFactory {
+	return f.Sequence(func(record *StoreFixture, _ int) {
+		record.SliceProp = v
+	})
+}
+
+// WithAliasSliceProp makes the factory set the field AliasSliceProp of all the records it
+// builds to the given value, and returns the factory.
+func (f *StoreFixtureFactory) WithAliasSliceProp(v AliasSliceString) *StoreFixtureFactory {
+	return f.Sequence(func(record *StoreFixture, _ int) {
+		record.AliasSliceProp = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *StoreFixtureFactory) Build() *StoreFixture {
+	f.n++
+	n := f.n
+	record := new(StoreFixture)
+	record.ID = kallax.NewULID()
+	record.Foo = fmt.Sprintf("foo-%d", n)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *StoreFixtureFactory) BuildN(count int) []*StoreFixture {
+	records := make([]*StoreFixture, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a StoreFixtureStore or a MockStoreFixtureStore.
+func (f *StoreFixtureFactory) Create(store interface{ Insert(*StoreFixture) error }) (*StoreFixture, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create StoreFixture number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a StoreFixtureStore or a
+// MockStoreFixtureStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *StoreFixtureFactory) CreateN(store interface{ Insert(*StoreFixture) error }, count int) ([]*StoreFixture, error) {
+	records := make([]*StoreFixture, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// StoreWithConstructFixtureFactory builds records of the type StoreWithConstructFixture for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type StoreWithConstructFixtureFactory struct {
+	n        int
+	builders []func(record *StoreWithConstructFixture, n int)
+}
+
+// NewStoreWithConstructFixtureFactory returns a new factory of records of the type
+// StoreWithConstructFixture, whose records are numbered from 1.
+func NewStoreWithConstructFixtureFactory() *StoreWithConstructFixtureFactory {
+	return new(StoreWithConstructFixtureFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *StoreWithConstructFixtureFactory) Sequence(fn func(record *StoreWithConstructFixture, n int)) *StoreWithConstructFixtureFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithID makes the factory set the field ID of all the records it
+// builds to the given value, and returns the factory.
+func (f *StoreWithConstructFixtureFactory) WithID(v kallax.ULID) *StoreWithConstructFixtureFactory {
+	return f.Sequence(func(record *StoreWithConstructFixture, _ int) {
+		record.ID = v
+	})
+}
+
+// WithFoo makes the factory set the field Foo of all the records it
+// builds to the given value, and returns the factory.
+func (f *StoreWithConstructFixtureFactory) WithFoo(v string) *StoreWithConstructFixtureFactory {
+	return f.Sequence(func(record *StoreWithConstructFixture, _ int) {
+		record.Foo = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *StoreWithConstructFixtureFactory) Build() *StoreWithConstructFixture {
+	f.n++
+	n := f.n
+	record := new(StoreWithConstructFixture)
+	record.ID = kallax.NewULID()
+	record.Foo = fmt.Sprintf("foo-%d", n)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *StoreWithConstructFixtureFactory) BuildN(count int) []*StoreWithConstructFixture {
+	records := make([]*StoreWithConstructFixture, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a StoreWithConstructFixtureStore or a MockStoreWithConstructFixtureStore.
+func (f *StoreWithConstructFixtureFactory) Create(store interface {
+	Insert(*StoreWithConstructFixture) error
+}) (*StoreWithConstructFixture, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create StoreWithConstructFixture number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a StoreWithConstructFixtureStore or a
+// MockStoreWithConstructFixtureStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *StoreWithConstructFixtureFactory) CreateN(store interface {
+	Insert(*StoreWithConstructFixture) error
+}, count int) ([]*StoreWithConstructFixture, error) {
+	records := make([]*StoreWithConstructFixture, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// StoreWithNewFixtureFactory builds records of the type StoreWithNewFixture for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type StoreWithNewFixtureFactory struct {
+	n        int
+	builders []func(record *StoreWithNewFixture, n int)
+}
+
+// NewStoreWithNewFixtureFactory returns a new factory of records of the type
+// StoreWithNewFixture, whose records are numbered from 1.
+func NewStoreWithNewFixtureFactory() *StoreWithNewFixtureFactory {
+	return new(StoreWithNewFixtureFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *StoreWithNewFixtureFactory) Sequence(fn func(record *StoreWithNewFixture, n int)) *StoreWithNewFixtureFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithID makes the factory set the field ID of all the records it
+// builds to the given value, and returns the factory.
+func (f *StoreWithNewFixtureFactory) WithID(v kallax.ULID) *StoreWithNewFixtureFactory {
+	return f.Sequence(func(record *StoreWithNewFixture, _ int) {
+		record.ID = v
+	})
+}
+
+// WithFoo makes the factory set the field Foo of all the records it
+// builds to the given value, and returns the factory.
+func (f *StoreWithNewFixtureFactory) WithFoo(v string) *StoreWithNewFixtureFactory {
+	return f.Sequence(func(record *StoreWithNewFixture, _ int) {
+		record.Foo = v
+	})
+}
+
+// WithBar makes the factory set the field Bar of all the records it
+// builds to the given value, and returns the factory.
+func (f *StoreWithNewFixtureFactory) WithBar(v string) *StoreWithNewFixtureFactory {
+	return f.Sequence(func(record *StoreWithNewFixture, _ int) {
+		record.Bar = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *StoreWithNewFixtureFactory) Build() *StoreWithNewFixture {
+	f.n++
+	n := f.n
+	record := new(StoreWithNewFixture)
+	record.ID = kallax.NewULID()
+	record.Foo = fmt.Sprintf("foo-%d", n)
+	record.Bar = fmt.Sprintf("bar-%d", n)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *StoreWithNewFixtureFactory) BuildN(count int) []*StoreWithNewFixture {
+	records := make([]*StoreWithNewFixture, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a StoreWithNewFixtureStore or a MockStoreWithNewFixtureStore.
+func (f *StoreWithNewFixtureFactory) Create(store interface {
+	Insert(*StoreWithNewFixture) error
+}) (*StoreWithNewFixture, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create StoreWithNewFixture number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a StoreWithNewFixtureStore or a
+// MockStoreWithNewFixtureStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *StoreWithNewFixtureFactory) CreateN(store interface {
+	Insert(*StoreWithNewFixture) error
+}, count int) ([]*StoreWithNewFixture, error) {
+	records := make([]*StoreWithNewFixture, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// TagFactory builds records of the type Tag for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type TagFactory struct {
+	n        int
+	builders []func(record *Tag, n int)
+}
+
+// NewTagFactory returns a new factory of records of the type
+// Tag, whose records are numbered from 1.
+func NewTagFactory() *TagFactory {
+	return new(TagFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *TagFactory) Sequence(fn func(record *Tag, n int)) *TagFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithID makes the factory set the field ID of all the records it
+// builds to the given value, and returns the factory.
+func (f *TagFactory) WithID(v kallax.ULID) *TagFactory {
+	return f.Sequence(func(record *Tag, _ int) {
+		record.ID = v
+	})
+}
+
+// WithName makes the factory set the field Name of all the records it
+// builds to the given value, and returns the factory.
+func (f *TagFactory) WithName(v string) *TagFactory {
+	return f.Sequence(func(record *Tag, _ int) {
+		record.Name = v
+	})
+}
+
+// WithPosts makes the factory set the field Posts of all the records it
+// builds to the given value, and returns the factory.
+func (f *TagFactory) WithPosts(v []*Post) *TagFactory {
+	return f.Sequence(func(record *Tag, _ int) {
+		record.Posts = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *TagFactory) Build() *Tag {
+	f.n++
+	n := f.n
+	record := new(Tag)
+	record.ID = kallax.NewULID()
+	record.Name = fmt.Sprintf("name-%d", n)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *TagFactory) BuildN(count int) []*Tag {
+	records := make([]*Tag, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a TagStore or a MockTagStore.
+func (f *TagFactory) Create(store interface{ Insert(*Tag) error }) (*Tag, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create Tag number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a TagStore or a
+// MockTagStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *TagFactory) CreateN(store interface{ Insert(*Tag) error }, count int) ([]*Tag, error) {
+	records := make([]*Tag, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// TenantPostFactory builds records of the type TenantPost for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type TenantPostFactory struct {
+	n        int
+	builders []func(record *TenantPost, n int)
+}
+
+// NewTenantPostFactory returns a new factory of records of the type
+// TenantPost, whose records are numbered from 1.
+func NewTenantPostFactory() *TenantPostFactory {
+	return new(TenantPostFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *TenantPostFactory) Sequence(fn func(record *TenantPost, n int)) *TenantPostFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithTenantID makes the factory set the field TenantID of all the records it
+// builds to the given value, and returns the factory.
+func (f *TenantPostFactory) WithTenantID(v int64) *TenantPostFactory {
+	return f.Sequence(func(record *TenantPost, _ int) {
+		record.TenantID = v
+	})
+}
+
+// WithTitle makes the factory set the field Title of all the records it
+// builds to the given value, and returns the factory.
+func (f *TenantPostFactory) WithTitle(v string) *TenantPostFactory {
+	return f.Sequence(func(record *TenantPost, _ int) {
+		record.Title = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *TenantPostFactory) Build() *TenantPost {
+	f.n++
+	n := f.n
+	record := new(TenantPost)
+	record.Title = fmt.Sprintf("title-%d", n)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *TenantPostFactory) BuildN(count int) []*TenantPost {
+	records := make([]*TenantPost, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a TenantPostStore or a MockTenantPostStore.
+func (f *TenantPostFactory) Create(store interface{ Insert(*TenantPost) error }) (*TenantPost, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create TenantPost number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a TenantPostStore or a
+// MockTenantPostStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *TenantPostFactory) CreateN(store interface{ Insert(*TenantPost) error }, count int) ([]*TenantPost, error) {
+	records := make([]*TenantPost, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// ValidatedUserFactory builds records of the type ValidatedUser for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type ValidatedUserFactory struct {
+	n        int
+	builders []func(record *ValidatedUser, n int)
+}
+
+// NewValidatedUserFactory returns a new factory of records of the type
+// ValidatedUser, whose records are numbered from 1.
+func NewValidatedUserFactory() *ValidatedUserFactory {
+	return new(ValidatedUserFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *ValidatedUserFactory) Sequence(fn func(record *ValidatedUser, n int)) *ValidatedUserFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithName makes the factory set the field Name of all the records it
+// builds to the given value, and returns the factory.
+func (f *ValidatedUserFactory) WithName(v string) *ValidatedUserFactory {
+	return f.Sequence(func(record *ValidatedUser, _ int) {
+		record.Name = v
+	})
+}
+
+// WithEmail makes the factory set the field Email of all the records it
+// builds to the given value, and returns the factory.
+func (f *ValidatedUserFactory) WithEmail(v string) *ValidatedUserFactory {
+	return f.Sequence(func(record *ValidatedUser, _ int) {
+		record.Email = v
+	})
+}
+
+// WithWebsite makes the factory set the field Website of all the records it
+// builds to the given value, and returns the factory.
+func (f *ValidatedUserFactory) WithWebsite(v *string) *ValidatedUserFactory {
+	return f.Sequence(func(record *ValidatedUser, _ int) {
+		record.Website = v
+	})
+}
+
+// WithRole makes the factory set the field Role of all the records it
+// builds to the given value, and returns the factory.
+func (f *ValidatedUserFactory) WithRole(v string) *ValidatedUserFactory {
+	return f.Sequence(func(record *ValidatedUser, _ int) {
+		record.Role = v
+	})
+}
+
+// WithTags makes the factory set the field Tags of all the records it
+// builds to the given value, and returns the factory.
+func (f *ValidatedUserFactory) WithTags(v []string) *ValidatedUserFactory {
+	return f.Sequence(func(record *ValidatedUser, _ int) {
+		record.Tags = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *ValidatedUserFactory) Build() *ValidatedUser {
+	f.n++
+	n := f.n
+	record := new(ValidatedUser)
+	record.Name = fmt.Sprintf("name-%d", n)
+	record.Email = fmt.Sprintf("email-%d@example.com", n)
+	record.Role = "admin"
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *ValidatedUserFactory) BuildN(count int) []*ValidatedUser {
+	records := make([]*ValidatedUser, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a ValidatedUserStore or a MockValidatedUserStore.
+func (f *ValidatedUserFactory) Create(store interface{ Insert(*ValidatedUser) error }) (*ValidatedUser, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create ValidatedUser number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a ValidatedUserStore or a
+// MockValidatedUserStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *ValidatedUserFactory) CreateN(store interface{ Insert(*ValidatedUser) error }, count int) ([]*ValidatedUser, error) {
+	records := make([]*ValidatedUser, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// VersionedPostFactory builds records of the type VersionedPost for tests. The
+// fields that need a value are filled with fake ones, which are different in
+// every record, so the records can be inserted right away, and the rest of
+// them are left empty. Use the With methods to set the value of a field in
+// all the records and Sequence to set it from the number of every record.
+// It's not safe for concurrent use.
+type VersionedPostFactory struct {
+	n        int
+	builders []func(record *VersionedPost, n int)
+}
+
+// NewVersionedPostFactory returns a new factory of records of the type
+// VersionedPost, whose records are numbered from 1.
+func NewVersionedPostFactory() *VersionedPostFactory {
+	return new(VersionedPostFactory)
+}
+
+// Sequence makes the factory call the given function with every record it
+// builds and its number, once its fields are filled, and returns the
+// factory. The functions are called in the order they were given.
+func (f *VersionedPostFactory) Sequence(fn func(record *VersionedPost, n int)) *VersionedPostFactory {
+	f.builders = append(f.builders, fn)
+	return f
+}
+
+// WithTitle makes the factory set the field Title of all the records it
+// builds to the given value, and returns the factory.
+func (f *VersionedPostFactory) WithTitle(v string) *VersionedPostFactory {
+	return f.Sequence(func(record *VersionedPost, _ int) {
+		record.Title = v
+	})
+}
+
+// Build returns the next record of the factory, which is not persisted.
+func (f *VersionedPostFactory) Build() *VersionedPost {
+	f.n++
+	n := f.n
+	record := new(VersionedPost)
+	record.Title = fmt.Sprintf("title-%d", n)
+
+	for _, fn := range f.builders {
+		fn(record, n)
+	}
+	return record
+}
+
+// BuildN returns the next count records of the factory, which are not
+// persisted.
+func (f *VersionedPostFactory) BuildN(count int) []*VersionedPost {
+	records := make([]*VersionedPost, count)
+	for i := range records {
+		records[i] = f.Build()
+	}
+	return records
+}
+
+// Create builds the next record of the factory and inserts it with the given
+// store, which can be a VersionedPostStore or a MockVersionedPostStore.
+func (f *VersionedPostFactory) Create(store interface{ Insert(*VersionedPost) error }) (*VersionedPost, error) {
+	record := f.Build()
+	if err := store.Insert(record); err != nil {
+		return nil, fmt.Errorf("kallax: unable to create VersionedPost number %d: %s", f.n, err)
+	}
+	return record, nil
+}
+
+// CreateN builds the next count records of the factory and inserts them
+// with the given store, which can be a VersionedPostStore or a
+// MockVersionedPostStore. It stops at the first record that can not be inserted,
+// returning the error.
+func (f *VersionedPostFactory) CreateN(store interface{ Insert(*VersionedPost) error }, count int) ([]*VersionedPost, error) {
+	records := make([]*VersionedPost, count)
+	for i := range records {
+		record, err := f.Create(store)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
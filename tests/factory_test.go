@@ -0,0 +1,37 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	kallax "gopkg.in/src-d/go-kallax.v1"
+)
+
+func TestFactory(t *testing.T) {
+	r := require.New(t)
+	store := NewMockValidatedUserStore(kallax.NewMockStore())
+
+	users, err := NewValidatedUserFactory().CreateN(store, 50)
+	r.NoError(err)
+	r.Len(users, 50)
+	r.Equal("name-1", users[0].Name)
+	r.Equal("email-50@example.com", users[49].Email)
+	r.Equal(int64(50), store.MustCount(NewValidatedUserQuery()))
+
+	factory := NewValidatedUserFactory().
+		WithName("Alice").
+		Sequence(func(u *ValidatedUser, n int) {
+			u.Email = fmt.Sprintf("alice+%d@example.com", n)
+		})
+	r.Equal("Alice", factory.Build().Name)
+	r.Equal("alice+2@example.com", factory.Build().Email)
+
+	_, err = NewValidatedUserFactory().WithEmail("alice").Create(store)
+	r.Error(err)
+	r.Contains(err.Error(), "kallax: unable to create ValidatedUser number 1: ")
+
+	customer := NewCustomerFactory().WithShippingCity("Madrid").Build()
+	r.Equal("billing_city-1", customer.Billing.City)
+	r.Equal("Madrid", customer.Shipping.City)
+}
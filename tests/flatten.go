@@ -0,0 +1,17 @@
+package tests
+
+import kallax "gopkg.in/src-d/go-kallax.v1"
+
+type Address struct {
+	Street string
+	City   string
+	Zip    *string
+}
+
+type Customer struct {
+	kallax.Model `table:"customers"`
+	ID           int64 `pk:"autoincr"`
+	Name         string
+	Billing      Address `prefix:""`
+	Shipping     Address `prefix:"ship_"`
+}
@@ -0,0 +1,56 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type FlattenSuite struct {
+	BaseTestSuite
+}
+
+func TestFlattenSuite(t *testing.T) {
+	schema := []string{
+		`CREATE TABLE IF NOT EXISTS customers (
+			id serial primary key,
+			name text not null,
+			billing_street text not null,
+			billing_city text not null,
+			billing_zip text,
+			ship_street text not null,
+			ship_city text not null,
+			ship_zip text
+		)`,
+	}
+	suite.Run(t, &FlattenSuite{NewBaseSuite(schema, "customers")})
+}
+
+func (s *FlattenSuite) TestInsertAndFind() {
+	require := s.Require()
+	store := NewCustomerStore(s.db)
+	zip := "28001"
+	customer := &Customer{
+		Name:     "foo",
+		Billing:  Address{Street: "Gran Via 1", City: "Madrid", Zip: &zip},
+		Shipping: Address{Street: "Rambla 2", City: "Barcelona"},
+	}
+	require.NoError(store.Insert(customer))
+
+	var city string
+	require.NoError(s.db.QueryRow("SELECT ship_city FROM customers WHERE id = $1", customer.ID).Scan(&city))
+	s.Equal("Barcelona", city)
+
+	found, err := store.FindOne(NewCustomerQuery().
+		FindByBillingCity("Madrid").
+		FindByShippingStreet("Rambla 2"))
+	require.NoError(err)
+	s.Equal(customer.Billing, found.Billing)
+	s.Equal(customer.Shipping, found.Shipping)
+
+	customer.Shipping.City = "Girona"
+	_, err = store.Update(customer, Schema.Customer.ShippingCity)
+	require.NoError(err)
+	s.Equal(int64(1), store.MustCount(NewCustomerQuery().FindByShippingCity("Girona")))
+	s.Equal(int64(0), store.MustCount(NewCustomerQuery().FindByBillingCity("Girona")))
+}
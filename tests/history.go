@@ -0,0 +1,9 @@
+package tests
+
+import kallax "gopkg.in/src-d/go-kallax.v1"
+
+type VersionedPost struct {
+	kallax.Model `table:"versioned_posts" versioned:"true"`
+	ID           int64 `pk:"autoincr"`
+	Title        string
+}
@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type HistorySuite struct {
+	BaseTestSuite
+}
+
+func TestHistorySuite(t *testing.T) {
+	schema := []string{
+		`CREATE TABLE IF NOT EXISTS versioned_posts (
+			id serial primary key,
+			title text not null
+		)`,
+		`CREATE TABLE IF NOT EXISTS versioned_posts_history (
+			id bigint not null,
+			title text not null,
+			valid_from timestamptz not null,
+			valid_to timestamptz
+		)`,
+		`CREATE OR REPLACE FUNCTION versioned_posts__history() RETURNS trigger AS $$
+		BEGIN
+			IF TG_OP <> 'INSERT' THEN
+				UPDATE versioned_posts_history SET valid_to = now() WHERE id = OLD.id AND valid_to IS NULL;
+			END IF;
+			IF TG_OP = 'DELETE' THEN
+				RETURN OLD;
+			END IF;
+			INSERT INTO versioned_posts_history (id, title, valid_from) VALUES (NEW.id, NEW.title, now());
+			RETURN NEW;
+		END
+		$$ LANGUAGE plpgsql`,
+		`CREATE TRIGGER versioned_posts__history AFTER INSERT OR UPDATE OR DELETE ON versioned_posts FOR EACH ROW EXECUTE PROCEDURE versioned_posts__history()`,
+	}
+	suite.Run(t, &HistorySuite{NewBaseSuite(schema, "versioned_posts", "versioned_posts_history")})
+}
+
+func (s *HistorySuite) TestFindAsOf() {
+	require := s.Require()
+	store := NewVersionedPostStore(s.db)
+
+	post := &VersionedPost{Title: "foo"}
+	require.NoError(store.Insert(post))
+	inserted := s.now()
+
+	post.Title = "bar"
+	_, err := store.Update(post)
+	require.NoError(err)
+	updated := s.now()
+
+	require.NoError(store.Delete(post))
+	deleted := s.now()
+
+	q := NewVersionedPostQuery().FindByID(post.ID)
+	s.assertTitles(store, inserted, q, "foo")
+	s.assertTitles(store, updated, q, "bar")
+	s.assertTitles(store, deleted, q)
+	s.assertTitles(store, inserted.Add(-time.Hour), q)
+}
+
+func (s *HistorySuite) now() time.Time {
+	var now time.Time
+	s.Require().NoError(s.db.QueryRow("SELECT clock_timestamp()").Scan(&now))
+	return now
+}
+
+func (s *HistorySuite) assertTitles(store *VersionedPostStore, t time.Time, q *VersionedPostQuery, titles ...string) {
+	require := s.Require()
+	rs, err := store.FindAsOf(t, q.Copy())
+	require.NoError(err)
+
+	posts, err := rs.All()
+	require.NoError(err)
+	require.Len(posts, len(titles))
+	for i, title := range titles {
+		require.Equal(title, posts[i].Title)
+		require.False(posts[i].IsWritable())
+	}
+}
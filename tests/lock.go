@@ -0,0 +1,10 @@
+package tests
+
+import kallax "gopkg.in/src-d/go-kallax.v1"
+
+type LockedPost struct {
+	kallax.Model `table:"locked_posts"`
+	ID           int64 `pk:"autoincr"`
+	Title        string
+	Version      int `kallax:",lock"`
+}
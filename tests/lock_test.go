@@ -0,0 +1,50 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	kallax "gopkg.in/src-d/go-kallax.v1"
+)
+
+type LockSuite struct {
+	BaseTestSuite
+}
+
+func TestLockSuite(t *testing.T) {
+	schema := []string{
+		`CREATE TABLE IF NOT EXISTS locked_posts (
+			id serial primary key,
+			title text not null,
+			version bigint not null
+		)`,
+	}
+	suite.Run(t, &LockSuite{NewBaseSuite(schema, "locked_posts")})
+}
+
+func (s *LockSuite) TestUpdate() {
+	require := s.Require()
+	store := NewLockedPostStore(s.db)
+	post := &LockedPost{Title: "foo"}
+	require.NoError(store.Insert(post))
+
+	stale, err := store.FindOne(NewLockedPostQuery().FindByID(post.ID))
+	require.NoError(err)
+
+	post.Title = "bar"
+	_, err = store.Update(post)
+	require.NoError(err)
+	s.Equal(1, post.Version)
+
+	stale.Title = "baz"
+	_, err = store.Update(stale)
+	s.Equal(kallax.ErrStaleObject, err)
+	s.Equal(0, stale.Version)
+
+	require.NoError(store.Reload(stale))
+	s.Equal("bar", stale.Title)
+	stale.Title = "baz"
+	_, err = store.Save(stale)
+	require.NoError(err)
+	s.Equal(2, stale.Version)
+}
@@ -0,0 +1,191 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	kallax "gopkg.in/src-d/go-kallax.v1"
+)
+
+func TestMockStore(t *testing.T) {
+	r := require.New(t)
+	mock := kallax.NewMockStore()
+	as, bs := NewMockAStore(mock), NewMockBStore(mock)
+
+	a := newA("foo")
+	r.NoError(as.Insert(a))
+	b := newB("bar", a)
+	r.NoError(bs.Insert(b))
+	r.NoError(bs.Insert(&B{Name: "baz"}))
+
+	found, err := bs.FindOne(NewBQuery().Where(kallax.Eq(Schema.B.AFK, a.ID)))
+	r.NoError(err)
+	r.Equal(b.ID, found.ID)
+	r.Equal("bar", found.Name)
+
+	count, err := bs.Count(NewBQuery().FindByName("baz"))
+	r.NoError(err)
+	r.Equal(int64(1), count)
+
+	found.Name = "qux"
+	_, err = bs.Save(found)
+	r.NoError(err)
+	r.NoError(bs.Reload(b))
+	r.Equal("qux", b.Name)
+
+	r.NoError(bs.Delete(b))
+	_, err = bs.FindByPrimaryKey(b.ID)
+	r.Equal(kallax.ErrNotFound, err)
+}
+
+func TestMockStore_ForEachBatch(t *testing.T) {
+	r := require.New(t)
+	store := NewMockAStore(kallax.NewMockStore())
+	for _, name := range []string{"foo", "bar", "baz", "qux", "quux"} {
+		r.NoError(store.Insert(newA(name)))
+	}
+
+	var names [][]string
+	rs, err := store.Find(NewAQuery().Order(kallax.Asc(Schema.A.Name)))
+	r.NoError(err)
+	err = rs.ForEachBatch(2, func(batch []*A) error {
+		var batchNames []string
+		for _, a := range batch {
+			batchNames = append(batchNames, a.Name)
+		}
+		names = append(names, batchNames)
+		return nil
+	})
+	r.NoError(err)
+	r.Equal([][]string{{"bar", "baz"}, {"foo", "quux"}, {"qux"}}, names)
+
+	var count int
+	rs, err = store.Find(NewAQuery())
+	r.NoError(err)
+	r.NoError(rs.ForEachBatch(2, func(batch []*A) error {
+		count += len(batch)
+		return kallax.ErrStop
+	}))
+	r.Equal(2, count)
+
+	rs, err = store.Find(NewAQuery())
+	r.NoError(err)
+	r.Equal(kallax.ErrInvalidBatchSize, rs.ForEachBatch(0, func([]*A) error { return nil }))
+}
+
+func TestMockStore_MustFind(t *testing.T) {
+	r := require.New(t)
+	store := NewMockAStore(kallax.NewMockStore())
+	a := newA("foo")
+	r.NoError(store.Insert(a))
+	r.NoError(store.Insert(newA("bar")))
+
+	r.Equal("foo", store.MustFindOneByID(a.ID).Name)
+	r.Equal("foo", store.MustFindByPrimaryKey(a.ID).Name)
+	r.Len(store.MustFindAll(NewAQuery()), 2)
+
+	_, err := store.FindOneByID(a.ID + 10)
+	r.Equal(kallax.ErrNotFound, err)
+	r.PanicsWithValue(kallax.ErrNotFound, func() {
+		store.MustFindOneByID(a.ID + 10)
+	})
+}
+
+func TestMockStore_Events(t *testing.T) {
+	r := require.New(t)
+	store := NewMockEventsFixtureStore(kallax.NewMockStore())
+
+	doc := newEventsFixture()
+	r.NoError(store.Insert(doc))
+	r.True(doc.Checks["BeforeInsert"])
+	r.True(doc.Checks["AfterInsert"])
+
+	doc = newEventsFixture()
+	doc.MustFailAfter = errors.New("foo")
+	r.Error(store.Insert(doc))
+	r.Equal(int64(1), store.MustCount(NewEventsFixtureQuery()))
+}
+
+func TestMockStore_QueueError(t *testing.T) {
+	r := require.New(t)
+	mock := kallax.NewMockStore()
+	store := NewMockLockedPostStore(mock)
+	mock.QueueError(errors.New("foo"))
+
+	r.EqualError(store.Insert(&LockedPost{Title: "foo"}), "foo")
+	post := &LockedPost{Title: "foo"}
+	r.NoError(store.Insert(post))
+
+	stale, err := store.FindByPrimaryKey(post.ID)
+	r.NoError(err)
+	post.Title = "bar"
+	_, err = store.Update(post)
+	r.NoError(err)
+	r.Equal(1, post.Version)
+
+	_, err = store.Update(stale)
+	r.Equal(kallax.ErrStaleObject, err)
+}
+
+func TestMockStore_SoftDelete(t *testing.T) {
+	r := require.New(t)
+	store := NewMockSoftDeletedPostStore(kallax.NewMockStore())
+	post := &SoftDeletedPost{Title: "foo"}
+	r.NoError(store.Insert(post))
+	r.NoError(store.Delete(post))
+	r.NotNil(post.DeletedAt)
+
+	r.Equal(int64(0), store.MustCount(NewSoftDeletedPostQuery()))
+	q := NewSoftDeletedPostQuery()
+	q.Unscoped()
+	r.Equal(int64(1), store.MustCount(q))
+
+	r.NoError(store.HardDelete(post))
+	q = NewSoftDeletedPostQuery()
+	q.Unscoped()
+	r.Equal(int64(0), store.MustCount(q))
+}
+
+func TestMockStore_UpdateWhere(t *testing.T) {
+	r := require.New(t)
+	store := NewMockAStore(kallax.NewMockStore())
+	for _, name := range []string{"foo", "bar", "baz"} {
+		r.NoError(store.Insert(newA(name)))
+	}
+
+	q := NewAQuery().Where(kallax.Like(Schema.A.Name, "ba%"))
+	updated, err := store.UpdateWhere(q, map[kallax.SchemaField]interface{}{
+		Schema.A.Name: "qux",
+	})
+	r.NoError(err)
+	r.Equal(int64(2), updated)
+	r.Equal(int64(2), store.MustCount(NewAQuery().FindByName("qux")))
+
+	deleted, err := store.DeleteWhere(NewAQuery().FindByName("qux").Limit(1))
+	r.NoError(err)
+	r.Equal(int64(1), deleted)
+	r.Equal(int64(2), store.MustCount(NewAQuery()))
+}
+
+func TestMockStore_DeleteWhere(t *testing.T) {
+	r := require.New(t)
+	store := NewMockSoftDeletedPostStore(kallax.NewMockStore())
+	for _, title := range []string{"foo", "bar"} {
+		r.NoError(store.Insert(&SoftDeletedPost{Title: title}))
+	}
+
+	deleted, err := store.DeleteWhere(NewSoftDeletedPostQuery().FindByTitle("foo"))
+	r.NoError(err)
+	r.Equal(int64(1), deleted)
+	r.Equal(int64(1), store.MustCount(NewSoftDeletedPostQuery()))
+
+	q := NewSoftDeletedPostQuery()
+	q.Unscoped()
+	deleted, err = store.HardDeleteWhere(q)
+	r.NoError(err)
+	r.Equal(int64(2), deleted)
+	q = NewSoftDeletedPostQuery()
+	q.Unscoped()
+	r.Equal(int64(0), store.MustCount(q))
+}
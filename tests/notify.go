@@ -0,0 +1,9 @@
+package tests
+
+import kallax "gopkg.in/src-d/go-kallax.v1"
+
+type NotifiedPost struct {
+	kallax.Model `table:"notified_posts" notify:"true"`
+	ID           int64 `pk:"autoincr"`
+	Title        string
+}
@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	kallax "gopkg.in/src-d/go-kallax.v1"
+)
+
+type NotifySuite struct {
+	BaseTestSuite
+}
+
+func TestNotifySuite(t *testing.T) {
+	schema := []string{
+		`CREATE TABLE IF NOT EXISTS notified_posts (
+			id serial primary key,
+			title text not null
+		)`,
+		`CREATE OR REPLACE FUNCTION notified_posts__notify() RETURNS trigger AS $$
+		BEGIN
+			IF TG_OP = 'DELETE' THEN
+				PERFORM pg_notify('notified_posts_changes', json_build_object('operation', TG_OP, 'id', OLD.id::text)::text);
+				RETURN OLD;
+			END IF;
+			PERFORM pg_notify('notified_posts_changes', json_build_object('operation', TG_OP, 'id', NEW.id::text)::text);
+			RETURN NEW;
+		END
+		$$ LANGUAGE plpgsql`,
+		`CREATE TRIGGER notified_posts__notify AFTER INSERT OR UPDATE OR DELETE ON notified_posts FOR EACH ROW EXECUTE PROCEDURE notified_posts__notify()`,
+	}
+	suite.Run(t, &NotifySuite{NewBaseSuite(schema, "notified_posts")})
+}
+
+type notifiedChange struct {
+	op kallax.ChangeOperation
+	id int64
+}
+
+func (s *NotifySuite) TestListenChanges() {
+	require := s.Require()
+	// listening blocks until the listener connects to the database
+	require.NoError(s.db.Ping())
+	listener := kallax.NewListener(fmt.Sprintf(connectionString, user, password, host, database), kallax.ListenerOptions{
+		OnError: func(err error) { s.Fail(err.Error()) },
+	})
+	defer listener.Close()
+
+	store := NewNotifiedPostStore(s.db)
+	changes := make(chan notifiedChange, 3)
+	require.NoError(store.ListenChanges(listener, func(op kallax.ChangeOperation, post *NotifiedPost) {
+		changes <- notifiedChange{op, post.ID}
+	}))
+
+	post := &NotifiedPost{Title: "foo"}
+	require.NoError(store.Insert(post))
+	post.Title = "bar"
+	_, err := store.Update(post)
+	require.NoError(err)
+	require.NoError(store.Delete(post))
+
+	for _, op := range []kallax.ChangeOperation{kallax.ChangeInsert, kallax.ChangeUpdate, kallax.ChangeDelete} {
+		select {
+		case c := <-changes:
+			require.Equal(notifiedChange{op, post.ID}, c)
+		case <-time.After(5 * time.Second):
+			require.FailNow("change not notified", string(op))
+		}
+	}
+}
@@ -201,6 +201,27 @@ func (s *QuerySuite) TestFindBy() {
 	})
 }
 
+func (s *QuerySuite) TestAggregate() {
+	store := NewQueryFixtureStore(s.db)
+	count, sum := kallax.CountAll(), kallax.Sum(Schema.QueryFixture.Integer)
+	groups, err := store.Aggregate(
+		NewQueryFixtureQuery().
+			GroupBy(Schema.QueryFixture.Boolean).
+			Having(kallax.Gt(count, 0)).
+			Order(kallax.Desc(Schema.QueryFixture.Boolean)),
+		count, sum,
+	)
+	s.NoError(err)
+	s.Len(groups, 2)
+
+	s.True(groups[0].Group.Boolean)
+	s.Equal(int64(2), groups[0].Int64(count))
+	s.Equal(int64(2), groups[0].Int64(sum))
+	s.False(groups[1].Group.Boolean)
+	s.Equal(int64(1), groups[1].Int64(count))
+	s.Equal(float64(1), groups[1].Float64(sum))
+}
+
 func (s *QuerySuite) TestGeneration() {
 	var cases = []struct {
 		propertyName        string
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/suite"
+	kallax "gopkg.in/src-d/go-kallax.v1"
 )
 
 type RelationshipsSuite struct {
@@ -32,8 +33,21 @@ func TestRelationships(t *testing.T) {
 			kind text,
 			owner_id integer references persons(id)
 		)`,
+		`CREATE TABLE IF NOT EXISTS posts (
+			id serial primary key,
+			title text
+		)`,
+		`CREATE TABLE IF NOT EXISTS tags (
+			id uuid primary key,
+			name text
+		)`,
+		`CREATE TABLE IF NOT EXISTS post_tags (
+			post_id integer not null references posts(id),
+			tag_id uuid not null references tags(id),
+			primary key (post_id, tag_id)
+		)`,
 	}
-	suite.Run(t, &RelationshipsSuite{NewBaseSuite(schemas, "cars", "pets", "persons")})
+	suite.Run(t, &RelationshipsSuite{NewBaseSuite(schemas, "cars", "pets", "persons", "post_tags", "posts", "tags")})
 }
 
 func (s *RelationshipsSuite) TestInsertFind() {
@@ -160,6 +174,76 @@ func (s *RelationshipsSuite) TestSaveRelations() {
 	s.NotNil(car.Brand)
 }
 
+func (s *RelationshipsSuite) TestManyToMany() {
+	require := s.Require()
+	posts, tags := NewPostStore(s.db), NewTagStore(s.db)
+	post := NewPost("foo")
+	require.NoError(posts.Insert(post))
+	golang, orm := NewTag("golang"), NewTag("orm")
+	require.NoError(tags.Insert(golang))
+	require.NoError(tags.Insert(orm))
+
+	require.NoError(posts.AddTags(post, golang, orm))
+	require.NoError(posts.AddTags(post, golang))
+	require.Len(post.Tags, 2)
+
+	found := posts.MustFindOne(NewPostQuery().WithTags(nil).FindByID(post.ID))
+	require.Len(found.Tags, 2)
+	tag := tags.MustFindOne(NewTagQuery().WithPosts(nil).FindByID(golang.ID))
+	require.Len(tag.Posts, 1)
+	require.Equal(post.ID, tag.Posts[0].ID)
+
+	found = posts.MustFindOne(NewPostQuery().WithTags(kallax.Eq(Schema.Tag.Name, "orm")).FindByID(post.ID))
+	require.Len(found.Tags, 1)
+	require.Equal(orm.ID, found.Tags[0].ID)
+
+	require.NoError(posts.RemoveTags(post, golang))
+	require.Len(post.Tags, 1)
+	found = posts.MustFindOne(NewPostQuery().WithTags(nil).FindByID(post.ID))
+	require.Len(found.Tags, 1)
+	require.Equal(orm.ID, found.Tags[0].ID)
+
+	require.NoError(posts.ClearTags(post))
+	require.Nil(post.Tags)
+	found = posts.MustFindOne(NewPostQuery().WithTags(nil).FindByID(post.ID))
+	require.Len(found.Tags, 0)
+	require.Equal(int64(2), tags.MustCount(NewTagQuery()))
+}
+
+func (s *RelationshipsSuite) TestPreload() {
+	require := s.Require()
+	p := NewPerson("Dolan")
+	car := NewCar("Tesla Model S", p)
+	car.Brand = Brand{ID: kallax.NewULID(), Name: "Tesla"}
+	NewPet("Garfield", "cat", p)
+	NewPet("Oddie", "dog", p)
+	require.NoError(NewPersonStore(s.db).Insert(p))
+
+	q := NewPersonQuery().WithPets(kallax.Preload("Owner.Car.Brand"))
+	pers, err := NewPersonStore(s.db).FindOne(q)
+	require.NoError(err)
+	require.Len(pers.Pets, 2)
+	for _, pet := range pers.Pets {
+		require.NotNil(pet.Owner)
+		require.Equal(p.ID, pet.Owner.ID)
+		require.NotNil(pet.Owner.Car)
+		require.Equal(car.ID, pet.Owner.Car.ID)
+		require.Equal("Tesla", pet.Owner.Car.Brand.Name)
+	}
+
+	posts, tags := NewPostStore(s.db), NewTagStore(s.db)
+	post := NewPost("foo")
+	require.NoError(posts.Insert(post))
+	golang := NewTag("golang")
+	require.NoError(tags.Insert(golang))
+	require.NoError(posts.AddTags(post, golang))
+
+	found := posts.MustFindOne(NewPostQuery().WithTags(kallax.Preload("Posts")).FindByID(post.ID))
+	require.Len(found.Tags, 1)
+	require.Len(found.Tags[0].Posts, 1)
+	require.Equal(post.ID, found.Tags[0].Posts[0].ID)
+}
+
 func (s *RelationshipsSuite) assertEvents(evs map[string]int, events ...string) {
 	for _, e := range events {
 		s.Equal(1, evs[e])
@@ -136,6 +136,23 @@ func (s *ResulsetSuite) TestResultSetForEachError() {
 	})
 }
 
+func (s *ResulsetSuite) TestResultSetForEachBatch() {
+	store := NewResultSetFixtureStore(s.db)
+	for _, foo := range []string{"bar", "baz", "foo"} {
+		s.Nil(store.Insert(NewResultSetFixture(foo)))
+	}
+
+	var sizes []int
+	rs := store.MustFind(NewResultSetFixtureQuery())
+	err := rs.ForEachBatch(2, func(batch []*ResultSetFixture) error {
+		sizes = append(sizes, len(batch))
+		return nil
+	})
+
+	s.NoError(err)
+	s.Equal([]int{2, 1}, sizes)
+}
+
 func (s *ResulsetSuite) TestForEachAndCount() {
 	store := NewResultSetFixtureStore(s.db)
 
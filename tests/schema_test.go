@@ -4,7 +4,9 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	kallax "gopkg.in/src-d/go-kallax.v1"
 )
 
 type SchemaSuite struct {
@@ -52,3 +54,30 @@ func (s *SchemaSuite) TestSchemaIgnored() {
 	field := reflect.Indirect(schema).FieldByName("ShouldIgnore")
 	s.False(field.IsValid())
 }
+
+func TestRegisteredSchemas(t *testing.T) {
+	r := require.New(t)
+
+	info, ok := kallax.SchemaByTable(Schema.SchemaFixture.Table())
+	r.True(ok)
+	r.Equal("SchemaFixture", info.Model)
+	r.Equal("gopkg.in/src-d/go-kallax.v1/tests", info.Package)
+
+	col, ok := info.Column("inline")
+	r.True(ok)
+	r.Equal(kallax.ColumnInfo{Name: "inline", Field: "Inline.Inline", Type: "text", NotNull: true}, col)
+
+	col, ok = info.Column("rel_id")
+	r.True(ok)
+	r.Equal("uuid", col.Type)
+	r.False(col.NotNull)
+
+	r.Len(info.Relationships, 2)
+	r.Equal(kallax.RelationshipInfo{
+		Field:      "Inverse",
+		Type:       kallax.OneToOne,
+		Schema:     Schema.SchemaRelationshipFixture.BaseSchema,
+		ForeignKey: "rel_id",
+		Inverse:    true,
+	}, info.Relationships[1])
+}
@@ -0,0 +1,14 @@
+package tests
+
+import (
+	"time"
+
+	kallax "gopkg.in/src-d/go-kallax.v1"
+)
+
+type SoftDeletedPost struct {
+	kallax.Model `table:"soft_deleted_posts"`
+	ID           int64 `pk:"autoincr"`
+	Title        string
+	DeletedAt    *time.Time `kallax:",softdelete"`
+}
@@ -0,0 +1,43 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SoftDeleteSuite struct {
+	BaseTestSuite
+}
+
+func TestSoftDeleteSuite(t *testing.T) {
+	schema := []string{
+		`CREATE TABLE IF NOT EXISTS soft_deleted_posts (
+			id serial primary key,
+			title text not null,
+			deleted_at timestamptz
+		)`,
+	}
+	suite.Run(t, &SoftDeleteSuite{NewBaseSuite(schema, "soft_deleted_posts")})
+}
+
+func (s *SoftDeleteSuite) TestDelete() {
+	require := s.Require()
+	store := NewSoftDeletedPostStore(s.db)
+	post := &SoftDeletedPost{Title: "foo"}
+	require.NoError(store.Insert(post))
+	require.NoError(store.Insert(&SoftDeletedPost{Title: "bar"}))
+
+	require.NoError(store.Delete(post))
+	require.NotNil(post.DeletedAt)
+
+	s.Equal(int64(1), store.MustCount(NewSoftDeletedPostQuery()))
+	s.Equal(int64(2), store.MustCount(NewSoftDeletedPostQuery().Unscoped()))
+	deleted, err := store.FindOne(NewSoftDeletedPostQuery().OnlyDeleted())
+	require.NoError(err)
+	s.Equal(post.ID, deleted.ID)
+	s.Equal(post.DeletedAt.Unix(), deleted.DeletedAt.Unix())
+
+	require.NoError(store.HardDelete(post))
+	s.Equal(int64(1), store.MustCount(NewSoftDeletedPostQuery().Unscoped()))
+}
@@ -125,3 +125,10 @@ type ParentNoPtr struct {
 	Name         string
 	Children     []Child `fk:"parent_id"`
 }
+
+type CompositeKeyFixture struct {
+	kallax.Model `table:"composite_key" pk:"tenant_id,order_id"`
+	TenantID     int64
+	OrderID      int64
+	Name         string
+}
@@ -1,6 +1,8 @@
 package tests
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -61,8 +63,14 @@ func TestStoreSuite(t *testing.T) {
 			name text,
 			b_id bigint references b(id)
 		)`,
+		`CREATE TABLE IF NOT EXISTS composite_key (
+			tenant_id bigint,
+			order_id bigint,
+			name text,
+			primary key (tenant_id, order_id)
+		)`,
 	}
-	suite.Run(t, &StoreSuite{NewBaseSuite(schema, "store_construct", "store", "store_new", "query", "nullable", "children", "parents", "c", "b", "a")})
+	suite.Run(t, &StoreSuite{NewBaseSuite(schema, "store_construct", "store", "store_new", "query", "nullable", "children", "parents", "c", "b", "a", "composite_key")})
 }
 
 type StoreSuite struct {
@@ -130,6 +138,248 @@ func (s *StoreSuite) TestStoreInsertUpdateMustFind() {
 	})
 }
 
+func (s *StoreSuite) TestChanges() {
+	store := NewStoreWithConstructFixtureStore(s.db)
+	s.Require().NoError(store.Insert(NewStoreWithConstructFixture("foo")))
+
+	doc := store.MustFindOne(NewStoreWithConstructFixtureQuery())
+	s.Empty(doc.Changes())
+
+	doc.Foo = "bar"
+	s.Equal(kallax.Changeset{"foo": {Old: "foo", New: "bar"}}, doc.Changes())
+	s.True(doc.Changes().Has(Schema.StoreWithConstructFixture.Foo))
+
+	_, err := store.Update(doc)
+	s.Require().NoError(err)
+	s.Empty(doc.Changes())
+}
+
+func (s *StoreSuite) TestExportImport() {
+	store := NewStoreFixtureStore(s.db)
+	doc := NewStoreFixture()
+	doc.Foo = "foo"
+	doc.SliceProp = []string{"a", "b"}
+	s.Require().NoError(store.Insert(doc))
+
+	for _, format := range []kallax.DataFormat{kallax.CSV, kallax.NDJSON} {
+		var buf bytes.Buffer
+		n, err := store.Export(NewStoreFixtureQuery(), &buf, format)
+		s.Require().NoError(err)
+		s.Equal(int64(1), n)
+
+		_, err = s.db.Exec("DELETE FROM store")
+		s.Require().NoError(err)
+
+		n, err = store.Import(&buf, format, kallax.ImportOptions{})
+		s.Require().NoError(err)
+		s.Equal(int64(1), n)
+
+		imported := store.MustFindOne(NewStoreFixtureQuery())
+		s.Equal(doc.ID, imported.ID)
+		s.Equal("foo", imported.Foo)
+		s.Equal([]string{"a", "b"}, imported.SliceProp)
+	}
+}
+
+func (s *StoreSuite) TestQueryCache() {
+	cache := kallax.NewQueryCache()
+	store := NewStoreWithConstructFixtureStore(s.db).WithCache(cache, time.Minute)
+	s.Require().NoError(store.Insert(NewStoreWithConstructFixture("foo")))
+	s.Equal("foo", store.MustFindOne(NewStoreWithConstructFixtureQuery()).Foo)
+
+	_, err := s.db.Exec("UPDATE store_construct SET foo = 'bar'")
+	s.Require().NoError(err)
+	s.Equal("foo", store.MustFindOne(NewStoreWithConstructFixtureQuery()).Foo)
+	s.Equal(int64(1), store.MustCount(NewStoreWithConstructFixtureQuery()))
+
+	cache.Invalidate(Schema.StoreWithConstructFixture.Table())
+	s.Equal("bar", store.MustFindOne(NewStoreWithConstructFixtureQuery()).Foo)
+
+	s.Require().NoError(store.Insert(NewStoreWithConstructFixture("baz")))
+	s.Equal(int64(2), store.MustCount(NewStoreWithConstructFixtureQuery()))
+}
+
+func (s *StoreSuite) TestBatch() {
+	store := NewStoreWithConstructFixtureStore(s.db)
+	existing := NewStoreWithConstructFixture("foo")
+	s.Require().NoError(store.Insert(existing))
+	removed := NewStoreWithConstructFixture("bar")
+	s.Require().NoError(store.Insert(removed))
+
+	schema := Schema.StoreWithConstructFixture.BaseSchema
+	batch := store.NewBatch()
+	inserted := NewStoreWithConstructFixture("baz")
+	s.Require().NoError(batch.Insert(schema, inserted))
+	existing.Foo = "qux"
+	s.Require().NoError(batch.Update(schema, existing))
+	s.Require().NoError(batch.Delete(schema, removed))
+	s.Require().NoError(batch.Flush())
+
+	s.True(inserted.IsPersisted())
+	s.Empty(existing.Changes())
+	s.Equal(int64(2), store.MustCount(NewStoreWithConstructFixtureQuery()))
+	s.Equal("qux", store.MustFindOne(NewStoreWithConstructFixtureQuery().FindByID(existing.ID)).Foo)
+}
+
+func (s *StoreSuite) TestMetrics() {
+	var metrics []kallax.QueryMetrics
+	store := NewStoreWithConstructFixtureStore(s.db).WithMetrics(func(m kallax.QueryMetrics) {
+		metrics = append(metrics, m)
+	})
+
+	s.Require().NoError(store.Insert(NewStoreWithConstructFixture("foo")))
+	s.Equal("foo", store.MustFindOne(NewStoreWithConstructFixtureQuery()).Foo)
+
+	s.Require().Len(metrics, 2)
+	for _, m := range metrics {
+		s.NoError(m.Err)
+		s.True(m.Exec > 0)
+	}
+}
+
+func (s *StoreSuite) TestDynamicRecord() {
+	schema := kallax.NewDynamicSchema("store_construct", "id", false, "foo")
+	store := kallax.NewStore(s.db)
+
+	record := kallax.NewDynamicRecord(schema)
+	s.Require().NoError(record.Set("id", kallax.NewULID()))
+	s.Require().NoError(record.Set("foo", "foo"))
+	s.Require().NoError(store.Insert(schema, record))
+
+	s.Require().NoError(record.Set("foo", "bar"))
+	_, err := store.Update(schema, record)
+	s.Require().NoError(err)
+
+	q := kallax.NewBaseQuery(schema)
+	q.Where(kallax.Eq(kallax.NewSchemaField("foo"), "bar"))
+	rs, err := store.Find(q)
+	s.Require().NoError(err)
+	defer rs.Close()
+
+	s.Require().True(rs.Next())
+	found, err := rs.Get(schema)
+	s.Require().NoError(err)
+	s.Equal("bar", found.(*kallax.DynamicRecord).Get("foo"))
+	s.False(rs.Next())
+
+	s.Equal("bar", NewStoreWithConstructFixtureStore(s.db).MustFindOne(NewStoreWithConstructFixtureQuery()).Foo)
+}
+
+func (s *StoreSuite) TestWithScope() {
+	store := NewStoreWithConstructFixtureStore(s.db)
+	s.Require().NoError(store.Insert(NewStoreWithConstructFixture("foo")))
+	s.Require().NoError(store.Insert(NewStoreWithConstructFixture("bar")))
+
+	scoped := store.WithScope(kallax.Eq(Schema.StoreWithConstructFixture.Foo, "foo"))
+	s.Equal(int64(1), scoped.MustCount(NewStoreWithConstructFixtureQuery()))
+	s.Equal("foo", scoped.MustFindOne(NewStoreWithConstructFixtureQuery()).Foo)
+	s.Equal(int64(2), scoped.Unscoped().MustCount(NewStoreWithConstructFixtureQuery()))
+}
+
+func (s *StoreSuite) TestCompositeKey() {
+	store := NewCompositeKeyFixtureStore(s.db)
+	s.Require().NoError(store.Insert(&CompositeKeyFixture{TenantID: 1, OrderID: 1, Name: "foo"}))
+	s.Require().NoError(store.Insert(&CompositeKeyFixture{TenantID: 2, OrderID: 1, Name: "bar"}))
+
+	record, err := store.FindByPrimaryKey(2, 1)
+	s.Require().NoError(err)
+	s.Equal("bar", record.Name)
+
+	record.Name = "baz"
+	updated, err := store.Save(record)
+	s.NoError(err)
+	s.True(updated)
+	s.Equal("foo", store.MustFindOne(NewCompositeKeyFixtureQuery().FindByTenantID(1)).Name)
+
+	s.NoError(store.Delete(record))
+	_, err = store.FindByPrimaryKey(2, 1)
+	s.Equal(kallax.ErrNotFound, err)
+	s.Equal(int64(1), store.MustCount(NewCompositeKeyFixtureQuery()))
+}
+
+func (s *StoreSuite) TestFromRows() {
+	store := NewStoreWithConstructFixtureStore(s.db)
+	doc := NewStoreWithConstructFixture("foo")
+	s.Require().NoError(store.Insert(doc))
+
+	rows, err := store.RawRows("SELECT id, foo FROM store_construct WHERE foo = $1", "foo")
+	s.Require().NoError(err)
+	rs, err := store.FromRows(rows)
+	s.Require().NoError(err)
+	record, err := rs.One()
+	s.Require().NoError(err)
+	s.Equal(doc.ID, record.ID)
+	s.Equal("foo", record.Foo)
+
+	_, err = store.RawRows("INSERT INTO store_construct (id, foo) VALUES ($1, $2) RETURNING id", doc.ID, "bar")
+	cerr, ok := err.(*kallax.ConstraintError)
+	s.Require().True(ok, "%v", err)
+	s.Equal(kallax.UniqueConstraint, cerr.Kind)
+	s.Equal("store_construct", cerr.Table)
+}
+
+func (s *StoreSuite) TestFindBySQL() {
+	store := NewStoreWithConstructFixtureStore(s.db)
+	doc := NewStoreWithConstructFixture("foo")
+	s.Require().NoError(store.Insert(doc))
+	s.Require().NoError(store.Insert(NewStoreWithConstructFixture("bar")))
+
+	rs, err := store.FindBySQL("SELECT * FROM store_construct WHERE foo IN (SELECT $1::text)", "foo")
+	s.Require().NoError(err)
+	records, err := rs.All()
+	s.Require().NoError(err)
+	s.Require().Len(records, 1)
+	s.Equal(doc.ID, records[0].ID)
+	s.Equal("foo", records[0].Foo)
+}
+
+func (s *StoreSuite) TestBatchInsert() {
+	store := NewAStore(s.db)
+	records := []*A{newA("foo"), newA("bar"), newA("baz")}
+	s.Require().NoError(store.BatchInsert(records, kallax.BatchInsertOptions{BatchSize: 2}))
+	for _, record := range records {
+		found, err := store.FindOne(NewAQuery().FindByID(record.ID))
+		s.Require().NoError(err)
+		s.Equal(record.Name, found.Name)
+	}
+
+	fixtures := []*StoreWithConstructFixture{NewStoreWithConstructFixture("foo"), NewStoreWithConstructFixture("bar")}
+	s.Require().NoError(NewStoreWithConstructFixtureStore(s.db).BatchInsert(fixtures, kallax.BatchInsertOptions{CopyThreshold: 2}))
+	s.Equal(int64(2), NewStoreWithConstructFixtureStore(s.db).MustCount(NewStoreWithConstructFixtureQuery()))
+}
+
+func (s *StoreSuite) TestWithContext() {
+	store := NewStoreWithConstructFixtureStore(s.db)
+	s.Require().NoError(store.Insert(NewStoreWithConstructFixture("foo")))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Equal(int64(1), store.WithContext(ctx).MustCount(NewStoreWithConstructFixtureQuery()))
+
+	cancel()
+	_, err := store.WithContext(ctx).Find(NewStoreWithConstructFixtureQuery())
+	s.Equal(context.Canceled, err)
+	_, err = store.WithContext(ctx).Count(NewStoreWithConstructFixtureQuery())
+	s.Equal(context.Canceled, err)
+	s.Equal(int64(1), store.MustCount(NewStoreWithConstructFixtureQuery()))
+}
+
+func (s *StoreSuite) TestUpsert() {
+	store := NewStoreWithConstructFixtureStore(s.db)
+	doc := NewStoreWithConstructFixture("foo")
+	conflict := []kallax.SchemaField{Schema.StoreWithConstructFixture.ID}
+	s.Require().NoError(store.Upsert(doc, conflict, Schema.StoreWithConstructFixture.Foo))
+	s.True(doc.IsPersisted())
+
+	other := NewStoreWithConstructFixture("bar")
+	other.ID = doc.ID
+	s.Require().NoError(store.Upsert(other, conflict))
+	s.Equal("foo", store.MustFindOne(NewStoreWithConstructFixtureQuery()).Foo)
+
+	s.Require().NoError(store.Upsert(other, conflict, Schema.StoreWithConstructFixture.Foo))
+	s.Equal(int64(1), store.MustCount(NewStoreWithConstructFixtureQuery()))
+	s.Equal("bar", store.MustFindOne(NewStoreWithConstructFixtureQuery()).Foo)
+}
+
 func (s *StoreSuite) TestStoreSave() {
 	store := NewStoreWithConstructFixtureStore(s.db)
 
@@ -490,3 +740,50 @@ func (s *StoreSuite) TestInsertEmptyVirtualColumns() {
 	s.NoError(err)
 	s.NotNil(retrievedA.B)
 }
+
+func (s *StoreSuite) TestFindPage() {
+	store := NewStoreWithConstructFixtureStore(s.db)
+	for _, foo := range []string{"e", "b", "d", "a", "c"} {
+		s.Require().NoError(store.Insert(NewStoreWithConstructFixture(foo)))
+	}
+
+	foos := func(page *StoreWithConstructFixturePage) []string {
+		var result []string
+		for _, r := range page.Records {
+			result = append(result, r.Foo)
+		}
+		return result
+	}
+
+	query := func() *StoreWithConstructFixtureQuery {
+		return NewStoreWithConstructFixtureQuery().
+			Order(kallax.Asc(Schema.StoreWithConstructFixture.Foo), kallax.Asc(Schema.StoreWithConstructFixture.ID)).
+			Limit(2)
+	}
+
+	page, err := store.FindPage(query())
+	s.Require().NoError(err)
+	s.Equal([]string{"a", "b"}, foos(page))
+	s.Empty(page.PrevCursor())
+
+	page, err = store.FindPage(query().AfterCursor(page.NextCursor()))
+	s.Require().NoError(err)
+	s.Equal([]string{"c", "d"}, foos(page))
+
+	last, err := store.FindPage(query().AfterCursor(page.NextCursor()))
+	s.Require().NoError(err)
+	s.Equal([]string{"e"}, foos(last))
+	s.Empty(last.NextCursor())
+
+	page, err = store.FindPage(query().BeforeCursor(last.PrevCursor()))
+	s.Require().NoError(err)
+	s.Equal([]string{"c", "d"}, foos(page))
+
+	page, err = store.FindPage(query().BeforeCursor(page.PrevCursor()))
+	s.Require().NoError(err)
+	s.Equal([]string{"a", "b"}, foos(page))
+	s.Empty(page.PrevCursor())
+
+	_, err = store.FindPage(query().AfterCursor("foo"))
+	s.Equal(kallax.ErrInvalidCursor, err)
+}
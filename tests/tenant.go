@@ -0,0 +1,10 @@
+package tests
+
+import kallax "gopkg.in/src-d/go-kallax.v1"
+
+type TenantPost struct {
+	kallax.Model `table:"tenant_posts"`
+	ID           int64 `pk:"autoincr"`
+	TenantID     int64 `tenant:""`
+	Title        string
+}
@@ -0,0 +1,52 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	kallax "gopkg.in/src-d/go-kallax.v1"
+)
+
+type TenantSuite struct {
+	BaseTestSuite
+}
+
+func TestTenantSuite(t *testing.T) {
+	schema := []string{
+		`CREATE TABLE IF NOT EXISTS tenant_posts (
+			id serial primary key,
+			tenant_id bigint not null,
+			title text not null
+		)`,
+	}
+	suite.Run(t, &TenantSuite{NewBaseSuite(schema, "tenant_posts")})
+}
+
+func (s *TenantSuite) TestWithTenant() {
+	require := s.Require()
+	store := NewTenantPostStore(s.db)
+	acme, other := store.WithTenant(1), store.WithTenant(2)
+
+	post := &TenantPost{Title: "foo"}
+	require.NoError(acme.Insert(post))
+	s.Equal(int64(1), post.TenantID)
+	require.NoError(other.Insert(&TenantPost{Title: "bar"}))
+
+	s.Equal(int64(1), acme.MustCount(NewTenantPostQuery()))
+	s.Equal(int64(2), store.MustCount(NewTenantPostQuery()))
+	_, err := other.FindOne(NewTenantPostQuery().FindByID(post.ID))
+	s.Equal(kallax.ErrNotFound, err)
+
+	post.Title = "baz"
+	_, err = other.Update(post)
+	s.Equal(kallax.ErrCrossTenant, err)
+
+	post.TenantID = 0
+	_, err = other.Update(post)
+	s.Equal(kallax.ErrNoRowUpdate, err)
+
+	n, err := other.DeleteWhere(NewTenantPostQuery())
+	require.NoError(err)
+	s.Equal(int64(1), n)
+	s.Equal(int64(1), store.MustCount(NewTenantPostQuery()))
+}
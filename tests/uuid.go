@@ -0,0 +1,20 @@
+package tests
+
+import (
+	"github.com/google/uuid"
+	kallax "gopkg.in/src-d/go-kallax.v1"
+)
+
+type GoogleUUIDOwner struct {
+	kallax.Model `table:"google_uuid_owners"`
+	ID           uuid.UUID `pk:"" uuid:"v4"`
+	Name         string
+	Items        []*GoogleUUIDItem `fk:"owner_id"`
+}
+
+type GoogleUUIDItem struct {
+	kallax.Model `table:"google_uuid_items"`
+	ID           uuid.UUID `pk:"" uuid:"v7"`
+	Token        uuid.UUID
+	Owner        *GoogleUUIDOwner `fk:"owner_id,inverse"`
+}
@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	kallax "gopkg.in/src-d/go-kallax.v1"
+)
+
+type GoogleUUIDSuite struct {
+	BaseTestSuite
+}
+
+func TestGoogleUUIDSuite(t *testing.T) {
+	schema := []string{
+		`CREATE TABLE IF NOT EXISTS google_uuid_owners (
+			id uuid primary key,
+			name text not null
+		)`,
+		`CREATE TABLE IF NOT EXISTS google_uuid_items (
+			id uuid primary key,
+			token uuid not null,
+			owner_id uuid references google_uuid_owners(id)
+		)`,
+	}
+	suite.Run(t, &GoogleUUIDSuite{NewBaseSuite(schema, "google_uuid_items", "google_uuid_owners")})
+}
+
+func (s *GoogleUUIDSuite) TestInsertFind() {
+	require := s.Require()
+	owners := NewGoogleUUIDOwnerStore(s.db)
+	items := NewGoogleUUIDItemStore(s.db)
+
+	owner := &GoogleUUIDOwner{Name: "foo"}
+	require.NoError(owners.Insert(owner))
+	require.NotEqual(uuid.Nil, owner.ID)
+	require.Equal(uuid.Version(4), owner.ID.Version())
+
+	token := uuid.New()
+	item := &GoogleUUIDItem{Token: token, Owner: owner}
+	require.NoError(items.Insert(item))
+	require.NotEqual(uuid.Nil, item.ID)
+	require.Equal(uuid.Version(7), item.ID.Version())
+
+	found, err := items.FindOne(NewGoogleUUIDItemQuery().
+		FindByID(kallax.UUID(item.ID)).
+		FindByToken(kallax.UUID(token)).
+		FindByOwner(kallax.UUID(owner.ID)).
+		WithOwner())
+	require.NoError(err)
+	require.Equal(item.ID, found.ID)
+	require.Equal(token, found.Token)
+	require.NotNil(found.Owner)
+	require.Equal(owner.ID, found.Owner.ID)
+
+	owner, err = owners.FindOne(NewGoogleUUIDOwnerQuery().WithItems(nil))
+	require.NoError(err)
+	require.Len(owner.Items, 1)
+	require.Equal(item.ID, owner.Items[0].ID)
+}
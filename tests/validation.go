@@ -0,0 +1,20 @@
+package tests
+
+import kallax "gopkg.in/src-d/go-kallax.v1"
+
+type ValidatedUser struct {
+	kallax.Model `table:"validated_users"`
+	ID           int64    `pk:"autoincr"`
+	Name         string   `validate:"required,max=10"`
+	Email        string   `validate:"required,email"`
+	Website      *string  `validate:"url"`
+	Role         string   `validate:"omitempty,oneof=admin user"`
+	Tags         []string `validate:"max=2"`
+}
+
+func (u *ValidatedUser) BeforeSave() error {
+	if u.Role == "" {
+		u.Role = "user"
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	kallax "gopkg.in/src-d/go-kallax.v1"
+)
+
+func TestValidate(t *testing.T) {
+	r := require.New(t)
+	website := "example.com"
+	user := &ValidatedUser{
+		Name:    "Alice Wonderland",
+		Website: &website,
+		Role:    "root",
+		Tags:    []string{"foo", "bar", "baz"},
+	}
+
+	err := user.Validate()
+	var verr *kallax.ValidationError
+	r.True(errors.As(err, &verr))
+	r.Equal("ValidatedUser", verr.Model)
+	r.Equal([]*kallax.FieldError{
+		{Field: "Name", Rule: "max=10"},
+		{Field: "Email", Rule: "required"},
+		{Field: "Website", Rule: "url"},
+		{Field: "Role", Rule: "oneof=admin user"},
+		{Field: "Tags", Rule: "max=2"},
+	}, verr.Fields)
+	r.EqualError(err, "kallax: invalid ValidatedUser: field Name does not satisfy max=10, field Email does not satisfy required, field Website does not satisfy url, field Role does not satisfy oneof=admin user, field Tags does not satisfy max=2")
+
+	r.NoError((&ValidatedUser{Name: "Alice", Email: "alice@example.com"}).Validate())
+}
+
+func TestValidate_BeforeSave(t *testing.T) {
+	r := require.New(t)
+	store := NewMockValidatedUserStore(kallax.NewMockStore())
+
+	user := &ValidatedUser{Name: "Alice"}
+	var verr *kallax.ValidationError
+	r.True(errors.As(store.Insert(user), &verr))
+	r.Equal([]*kallax.FieldError{{Field: "Email", Rule: "required"}}, verr.Fields)
+	r.False(user.IsPersisted())
+
+	user.Email = "alice@example.com"
+	r.NoError(store.Insert(user))
+	r.Equal("user", user.Role)
+
+	user.Name = ""
+	_, err := store.Update(user)
+	r.True(errors.As(err, &verr))
+	r.Equal([]*kallax.FieldError{{Field: "Name", Rule: "required"}}, verr.Fields)
+}
@@ -0,0 +1,89 @@
+package kallax
+
+import (
+	"context"
+	"database/sql"
+)
+
+// TracedStatement is a statement traced by a tracer. Its kind and tables are
+// inspected without a parser, as the ones of a GuardedStatement are.
+type TracedStatement struct {
+	// Query is the parameterized SQL of the statement. Its arguments are
+	// never given to the tracer.
+	Query string
+	// Kind is the uppercased keyword of the statement, such as SELECT,
+	// INSERT, UPDATE or DELETE.
+	Kind string
+	// Tables are the names of the tables read or written by the statement,
+	// in the order they first appear.
+	Tables []string
+}
+
+// Tracer starts a span for every statement run by a store, such as an
+// OpenTelemetry span.
+type Tracer interface {
+	// StartSpan starts the span of the given statement, which is run with
+	// the returned context, so the spans started by the driver are children
+	// of it.
+	StartSpan(ctx context.Context, stmt *TracedStatement) (context.Context, Span)
+}
+
+// Span is the span of a statement started by a tracer.
+type Span interface {
+	// End ends the span once the statement has been run, with the number of
+	// rows affected by it and its error, if any. The number of rows is -1 for
+	// queries, as their rows are read after the span has ended, and for the
+	// statements whose driver does not report it.
+	End(rows int64, err error)
+}
+
+// WithTracer returns a new store that traces all the statements it runs,
+// including raw ones and the ones of its transactions, with the given
+// tracer. The spans are started with the middleware returned by Trace, after
+// the middlewares the store already uses.
+func (s *Store) WithTracer(tracer Tracer) *Store {
+	return s.Use(Trace(tracer))
+}
+
+// Trace returns a middleware that traces the statements run through it with
+// the given tracer.
+func Trace(tracer Tracer) Middleware {
+	return func(next QueryExecutor) QueryExecutor {
+		return &tracingExecutor{next, tracer}
+	}
+}
+
+type tracingExecutor struct {
+	next   QueryExecutor
+	tracer Tracer
+}
+
+func (e *tracingExecutor) start(ctx context.Context, query string) (context.Context, Span) {
+	stmt := inspectStatement(query)
+	return e.tracer.StartSpan(ctx, &TracedStatement{
+		Query:  query,
+		Kind:   stmt.Kind,
+		Tables: stmt.Tables,
+	})
+}
+
+func (e *tracingExecutor) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := e.start(ctx, query)
+	result, err := e.next.ExecContext(ctx, query, args...)
+
+	rows := int64(-1)
+	if err == nil {
+		if n, err := result.RowsAffected(); err == nil {
+			rows = n
+		}
+	}
+	span.End(rows, err)
+	return result, err
+}
+
+func (e *tracingExecutor) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, span := e.start(ctx, query)
+	rows, err := e.next.QueryContext(ctx, query, args...)
+	span.End(-1, err)
+	return rows, err
+}
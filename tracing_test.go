@@ -0,0 +1,83 @@
+package kallax
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type recordedSpan struct {
+	stmt TracedStatement
+	rows int64
+	err  error
+}
+
+type recordingTracer struct {
+	spans []*recordedSpan
+}
+
+func (t *recordingTracer) StartSpan(ctx context.Context, stmt *TracedStatement) (context.Context, Span) {
+	span := &recordedSpan{stmt: *stmt}
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+func (s *recordedSpan) End(rows int64, err error) {
+	s.rows, s.err = rows, err
+}
+
+func TestStoreWithTracer(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	tracer := new(recordingTracer)
+	errRejected := errors.New("rejected")
+	store := NewStore(db).WithTracer(tracer).WithGuard(func(s *GuardedStatement) error {
+		if s.Kind == "DELETE" {
+			return errRejected
+		}
+		return nil
+	})
+
+	q := NewBaseQuery(ModelSchema)
+	q.Where(Eq(f("name"), "foo"))
+	rs, err := store.Find(q)
+	r.NoError(err)
+	r.NoError(rs.Close())
+
+	r.NoError(store.Insert(ModelSchema, newModel("foo", "foo@bar.baz", 1)))
+
+	_, err = store.RawExec("DELETE FROM model")
+	r.Equal(errRejected, err)
+
+	r.Equal([]*recordedSpan{
+		{
+			TracedStatement{
+				"SELECT __model.id, __model.name, __model.email, __model.age FROM model __model WHERE __model.name = $1",
+				"SELECT",
+				[]string{"model"},
+			},
+			-1,
+			nil,
+		},
+		{
+			TracedStatement{
+				"INSERT INTO model (name,email,age) VALUES ($1,$2,$3) RETURNING id",
+				"INSERT",
+				[]string{"model"},
+			},
+			-1,
+			nil,
+		},
+		{
+			TracedStatement{"DELETE FROM model", "DELETE", []string{"model"}},
+			-1,
+			errRejected,
+		},
+	}, tracer.spans)
+}
@@ -0,0 +1,92 @@
+package kallax
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+)
+
+// TSVector is a document preprocessed for full text search, stored in a
+// tsvector column. A TSVector field can be maintained by the database from
+// other columns of the model with the `tsvector` struct tag, in which case it
+// is never inserted nor updated by kallax, only retrieved.
+type TSVector string
+
+// Scan implements the sql.Scanner interface.
+func (v *TSVector) Scan(src interface{}) error {
+	switch t := src.(type) {
+	case []byte:
+		*v = TSVector(t)
+		return nil
+	case string:
+		*v = TSVector(t)
+		return nil
+	case nil:
+		*v = ""
+		return nil
+	}
+	return fmt.Errorf("kallax: cannot scan type %s into TSVector type", reflect.TypeOf(src))
+}
+
+// Value implements the driver.Valuer interface.
+func (v TSVector) Value() (driver.Value, error) {
+	return string(v), nil
+}
+
+// NewTSVectorSchemaField creates a new schema field of a tsvector column whose
+// document is computed with the given text search configuration, which is
+// the one used to parse the queries of the Matches conditions of the field.
+func NewTSVectorSchemaField(name, config string) SchemaField {
+	return &tsvectorSchemaField{&BaseSchemaField{name}, config}
+}
+
+type tsvectorSchemaField struct {
+	*BaseSchemaField
+	config string
+}
+
+// Matches returns a condition that will be true when the document in `col`
+// matches the given query of plain text, whose words must all be in the
+// document. The query is parsed with plainto_tsquery.
+func Matches(col SchemaField, query string) Condition {
+	return tsqueryCond("plainto_tsquery", col, query)
+}
+
+// MatchesWebSearch returns a condition that will be true when the document
+// in `col` matches the given query written as in web search engines, with
+// quoted phrases, "or" and "-" to exclude words. The query is parsed with
+// websearch_to_tsquery.
+func MatchesWebSearch(col SchemaField, query string) Condition {
+	return tsqueryCond("websearch_to_tsquery", col, query)
+}
+
+// MatchesTSQuery returns a condition that will be true when the document in
+// `col` matches the given query written in the syntax of tsquery, with the
+// operators &, |, ! and <->. The query is parsed with to_tsquery.
+func MatchesTSQuery(col SchemaField, query string) Condition {
+	return tsqueryCond("to_tsquery", col, query)
+}
+
+func tsqueryCond(fn string, col SchemaField, query string) Condition {
+	return func(schema Schema) ToSqler {
+		var config string
+		if f, ok := col.(*tsvectorSchemaField); ok {
+			config = f.config
+		}
+		return &tsqueryOp{col.QualifiedName(schema), fn, config, query}
+	}
+}
+
+type tsqueryOp struct {
+	col    string
+	fn     string
+	config string
+	query  string
+}
+
+func (o tsqueryOp) ToSql() (string, []interface{}, error) {
+	if o.config == "" {
+		return fmt.Sprintf("%s @@ %s(?)", o.col, o.fn), []interface{}{o.query}, nil
+	}
+	return fmt.Sprintf("%s @@ %s(?::regconfig, ?)", o.col, o.fn), []interface{}{o.config, o.query}, nil
+}
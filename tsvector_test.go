@@ -0,0 +1,49 @@
+package kallax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTSVectorScan(t *testing.T) {
+	r := require.New(t)
+	var v TSVector
+	r.NoError(v.Scan([]byte("'foo':1A 'bar':2")))
+	r.Equal(TSVector("'foo':1A 'bar':2"), v)
+	r.NoError(v.Scan(nil))
+	r.Equal(TSVector(""), v)
+	r.Error(v.Scan(1))
+}
+
+func TestMatches(t *testing.T) {
+	r := require.New(t)
+	cases := []struct {
+		cond Condition
+		sql  string
+		args []interface{}
+	}{
+		{
+			Matches(f("search"), "foo bar"),
+			"__model.search @@ plainto_tsquery(?)",
+			[]interface{}{"foo bar"},
+		},
+		{
+			MatchesWebSearch(NewTSVectorSchemaField("search", "english"), `"foo bar" -baz`),
+			"__model.search @@ websearch_to_tsquery(?::regconfig, ?)",
+			[]interface{}{"english", `"foo bar" -baz`},
+		},
+		{
+			MatchesTSQuery(NewTSVectorSchemaField("search", "simple"), "foo & !bar"),
+			"__model.search @@ to_tsquery(?::regconfig, ?)",
+			[]interface{}{"simple", "foo & !bar"},
+		},
+	}
+
+	for _, c := range cases {
+		sql, args, err := c.cond(ModelSchema).ToSql()
+		r.NoError(err)
+		r.Equal(c.sql, sql)
+		r.Equal(c.args, args)
+	}
+}
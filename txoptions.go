@@ -0,0 +1,79 @@
+package kallax
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// ErrNestedTxOptions is returned when a transaction with options is run
+// inside another transaction, as its isolation level and access mode can
+// not be changed once it has started.
+var ErrNestedTxOptions = errors.New("kallax: the options of a transaction can not be set inside another transaction")
+
+// The isolation levels of transactions supported by PostgreSQL.
+const (
+	// DefaultIsolation is the default isolation level of the database,
+	// which is ReadCommitted in PostgreSQL.
+	DefaultIsolation = sql.LevelDefault
+	// ReadCommitted sees only the rows committed before each statement of
+	// the transaction starts.
+	ReadCommitted = sql.LevelReadCommitted
+	// RepeatableRead sees only the rows committed before the transaction
+	// starts. It fails with a serialization failure if it changes rows that
+	// were changed by concurrent transactions.
+	RepeatableRead = sql.LevelRepeatableRead
+	// Serializable runs the transaction as if the concurrent transactions
+	// had been run one after another. It fails with a serialization
+	// failure if they can not.
+	Serializable = sql.LevelSerializable
+)
+
+// TxOptions are the options of the transactions run with
+// TransactionWithOptions.
+type TxOptions struct {
+	// Isolation is the isolation level of the transaction, such as
+	// Serializable. If it is zero, the default one of the database is used.
+	Isolation sql.IsolationLevel
+	// ReadOnly makes the transaction read-only, so its writes are rejected
+	// by the database.
+	ReadOnly bool
+}
+
+// TransactionWithOptions executes the given callback in a transaction with
+// the given options, as Transaction does, and its statements with the given
+// context, unless it is nil. If the transaction is RepeatableRead or
+// Serializable and it fails with a serialization failure, SQLSTATE 40001,
+// it is retried with the Transactions policy of the store, unless the policy
+// has its own Retryable function, up to 10 times by default. Its callback
+// must not have side effects outside of the transaction, then.
+// ErrNestedTxOptions is returned if the store is already holding a
+// transaction and any options are given.
+func (s *Store) TransactionWithOptions(ctx context.Context, opts *TxOptions, callback func(*Store) error) error {
+	if ctx != nil {
+		s = s.WithContext(ctx)
+	}
+	return s.transactionWithOptions(opts, callback)
+}
+
+// sqlOptions returns the options of database/sql of the transactions with
+// the options, which are nil if there are none.
+func (o *TxOptions) sqlOptions() *sql.TxOptions {
+	if o == nil || *o == (TxOptions{}) {
+		return nil
+	}
+	return &sql.TxOptions{Isolation: o.Isolation, ReadOnly: o.ReadOnly}
+}
+
+// retriesSerialization reports whether the transactions with the options
+// are retried when they fail with a serialization failure.
+func (o *TxOptions) retriesSerialization() bool {
+	return o != nil && (o.Isolation == RepeatableRead || o.Isolation == Serializable)
+}
+
+// isSerializationFailure reports whether the given error is a serialization
+// failure reported by PostgreSQL.
+func isSerializationFailure(err error) bool {
+	pgErr, ok := postgresError(err)
+	return ok && pgErr.Code == "40001"
+}
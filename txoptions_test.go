@@ -0,0 +1,118 @@
+package kallax
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionWithOptions(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	recordedTxOptions = nil
+	defer func() { recordedTxOptions = nil }()
+
+	store := NewStore(db)
+	ctx := context.Background()
+	r.NoError(store.TransactionWithOptions(ctx, &TxOptions{Isolation: Serializable, ReadOnly: true}, func(s *Store) error {
+		r.Equal(ctx, s.Context())
+		return nil
+	}))
+	r.NoError(store.TransactionWithOptions(nil, nil, func(*Store) error { return nil }))
+	r.NoError(store.Transaction(func(*Store) error { return nil }))
+
+	r.Equal([]driver.TxOptions{
+		{Isolation: driver.IsolationLevel(sql.LevelSerializable), ReadOnly: true},
+		{},
+		{},
+	}, recordedTxOptions)
+}
+
+func TestTransactionWithOptions_Nested(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	err = NewStore(db).DisableCacher().Transaction(func(s *Store) error {
+		r.Equal(ErrNestedTxOptions, s.TransactionWithOptions(nil, &TxOptions{Isolation: Serializable}, func(*Store) error {
+			r.FailNow("nested transaction with options run")
+			return nil
+		}))
+
+		var called bool
+		r.NoError(s.TransactionWithOptions(nil, &TxOptions{}, func(*Store) error {
+			called = true
+			return nil
+		}))
+		r.True(called)
+		return nil
+	})
+	r.NoError(err)
+}
+
+func TestTransactionWithOptions_SerializationRetry(t *testing.T) {
+	r := require.New(t)
+	db, err := sql.Open("kallax_recording", "")
+	r.NoError(err)
+	defer db.Close()
+
+	failure := &pq.Error{Code: "40001", Message: "could not serialize access"}
+	defer func() { commitErrors = nil }()
+
+	var retries []int
+	store := NewStore(db).WithDialect(Postgres).WithPolicy(Policy{
+		Transactions: OperationPolicy{
+			Attempts: 4,
+			OnRetry: func(attempt int, err error) {
+				r.Equal(failure, err)
+				retries = append(retries, attempt)
+			},
+		},
+	})
+
+	cases := []struct {
+		isolation sql.IsolationLevel
+		failures  int
+		attempts  int
+		err       string
+	}{
+		{Serializable, 2, 3, ""},
+		{RepeatableRead, 2, 3, ""},
+		{Serializable, 10, 4, "kallax: unable to commit transaction: pq: could not serialize access (40001)"},
+		{ReadCommitted, 2, 1, "kallax: unable to commit transaction: pq: could not serialize access (40001)"},
+	}
+
+	for _, c := range cases {
+		commitErrors = nil
+		for i := 0; i < c.failures; i++ {
+			commitErrors = append(commitErrors, failure)
+		}
+
+		var attempts int
+		retries = nil
+		err := store.TransactionWithOptions(nil, &TxOptions{Isolation: c.isolation}, func(*Store) error {
+			attempts++
+			return nil
+		})
+
+		r.Equal(c.attempts, attempts, c.isolation.String())
+		var expected []int
+		for i := 2; i <= c.attempts; i++ {
+			expected = append(expected, i)
+		}
+		r.Equal(expected, retries, c.isolation.String())
+		if c.err == "" {
+			r.NoError(err)
+		} else {
+			r.EqualError(err, c.err)
+		}
+	}
+}
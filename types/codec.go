@@ -0,0 +1,126 @@
+package types
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// JSONCodec encodes and decodes the values stored in JSON columns. It can be
+// used to store values that need a custom JSON representation, such as
+// protobuf messages, that does not depend on the encoding/json defaults.
+type JSONCodec interface {
+	// Marshal returns the JSON encoding of v.
+	Marshal(v interface{}) ([]byte, error)
+	// Unmarshal decodes the given JSON into the value pointed by v.
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// StdJSONCodec is the JSONCodec that uses the encoding/json package. It is
+// registered with the name "json".
+var StdJSONCodec JSONCodec = stdJSONCodec{}
+
+type stdJSONCodec struct{}
+
+func (stdJSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (stdJSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+var jsonCodecs = struct {
+	sync.RWMutex
+	codecs map[string]JSONCodec
+}{codecs: map[string]JSONCodec{"json": StdJSONCodec}}
+
+// RegisterJSONCodec registers the given codec with the given name, so it can
+// be used in JSON columns with JSONWithCodec. If there is already a codec
+// registered with the same name it is replaced.
+func RegisterJSONCodec(name string, codec JSONCodec) {
+	jsonCodecs.Lock()
+	defer jsonCodecs.Unlock()
+	jsonCodecs.codecs[name] = codec
+}
+
+func jsonCodec(name string) (JSONCodec, error) {
+	jsonCodecs.RLock()
+	defer jsonCodecs.RUnlock()
+	codec, ok := jsonCodecs.codecs[name]
+	if !ok {
+		return nil, fmt.Errorf("kallax: there is no JSON codec registered with name %q", name)
+	}
+	return codec, nil
+}
+
+type codecJSON struct {
+	codec string
+	val   interface{}
+}
+
+// JSONWithCodec is like JSON, but the given value is encoded and decoded
+// with the JSON codec registered with the given name instead of the
+// encoding/json package. When scanning into a pointer to a pointer, the
+// pointer is allocated if it's nil and the codec receives it instead, so
+// codecs always get the same pointer type on scan. Nil pointers are stored
+// as NULL.
+func JSONWithCodec(codec string, v interface{}) SQLType {
+	return &codecJSON{codec, v}
+}
+
+func (j *codecJSON) Scan(v interface{}) error {
+	var data []byte
+	switch v := v.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	case nil:
+		return nil
+	default:
+		return fmt.Errorf("kallax: cannot scan type %s into JSON type", reflect.TypeOf(v))
+	}
+
+	codec, err := jsonCodec(j.codec)
+	if err != nil {
+		return err
+	}
+
+	dest := j.val
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() && rv.Elem().Kind() == reflect.Ptr {
+		if rv.Elem().IsNil() {
+			if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+				return nil
+			}
+			rv.Elem().Set(reflect.New(rv.Elem().Type().Elem()))
+		}
+		dest = rv.Elem().Interface()
+	}
+
+	return codec.Unmarshal(data, dest)
+}
+
+func (j *codecJSON) Value() (driver.Value, error) {
+	codec, err := jsonCodec(j.codec)
+	if err != nil {
+		return nil, err
+	}
+
+	val := j.val
+	rv := reflect.ValueOf(val)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() && rv.Elem().Kind() == reflect.Ptr {
+		rv = rv.Elem()
+		val = rv.Interface()
+	}
+
+	if rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil, nil
+	}
+
+	return codec.Marshal(val)
+}
@@ -0,0 +1,72 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// upperCodec is a JSON codec that stores the keys of the objects in upper
+// case.
+type upperCodec struct{}
+
+func (upperCodec) Marshal(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.ToUpper(data), nil
+}
+
+func (upperCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(bytes.ToLower(data), v)
+}
+
+type codecType struct {
+	Foo string `json:"foo"`
+}
+
+func TestJSONWithCodec(t *testing.T) {
+	r := require.New(t)
+	RegisterJSONCodec("upper", upperCodec{})
+
+	val, err := JSONWithCodec("upper", codecType{"a"}).Value()
+	r.NoError(err)
+	r.Equal(`{"FOO":"A"}`, string(val.([]byte)))
+
+	var dst codecType
+	r.NoError(JSONWithCodec("upper", &dst).Scan([]byte(`{"FOO":"A"}`)))
+	r.Equal(codecType{"a"}, dst)
+
+	val, err = JSONWithCodec("json", codecType{"a"}).Value()
+	r.NoError(err)
+	r.Equal(`{"foo":"a"}`, string(val.([]byte)))
+
+	_, err = JSONWithCodec("unknown", codecType{"a"}).Value()
+	r.Error(err)
+	r.Error(JSONWithCodec("unknown", &dst).Scan([]byte(`{}`)))
+	r.Error(JSONWithCodec("upper", &dst).Scan(1))
+}
+
+func TestJSONWithCodec_Ptr(t *testing.T) {
+	r := require.New(t)
+
+	var dst *codecType
+	r.NoError(JSONWithCodec("json", &dst).Scan(`{"foo":"a"}`))
+	r.Equal(&codecType{"a"}, dst)
+
+	val, err := JSONWithCodec("json", dst).Value()
+	r.NoError(err)
+	r.Equal(`{"foo":"a"}`, string(val.([]byte)))
+
+	dst = nil
+	r.NoError(JSONWithCodec("json", &dst).Scan(nil))
+	r.NoError(JSONWithCodec("json", &dst).Scan("null"))
+	r.Nil(dst)
+
+	val, err = JSONWithCodec("json", dst).Value()
+	r.NoError(err)
+	r.Nil(val)
+}
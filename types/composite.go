@@ -0,0 +1,428 @@
+package types
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type composite struct {
+	val interface{}
+}
+
+// Composite wraps a pointer to a struct, or a pointer to a pointer to a
+// struct, so it can be scanned from and converted to a PostgreSQL composite
+// type. The exported fields of the struct are the attributes of the composite
+// type, in the same order. Fields with the struct tag `kallax:"-"` and empty
+// embedded structs, such as kallax.Composite, are ignored. The fields of
+// embedded structs are added to the struct.
+// The following types can be used as fields:
+//   - Go basic types and []byte
+//   - time.Time
+//   - types that implement sql.Scanner and driver.Valuer
+//   - slices and arrays, which are stored as PostgreSQL arrays
+//   - structs embedding kallax.Composite, which are stored as composites
+//   - pointers to the above, which are nullable
+//
+// Any other type is stored as JSON.
+func Composite(v interface{}) SQLType {
+	return &composite{v}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+const compositeTimeLayout = "2006-01-02 15:04:05.999999-07:00"
+
+func (c *composite) Scan(v interface{}) error {
+	rv := reflect.ValueOf(c.val)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("kallax: cannot scan composite into non-pointer type %T", c.val)
+	}
+
+	elem := rv.Elem()
+	if v == nil {
+		elem.Set(reflect.Zero(elem.Type()))
+		return nil
+	}
+
+	var s string
+	switch v := v.(type) {
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return fmt.Errorf("kallax: cannot scan type %s into composite type %s", reflect.TypeOf(v), elem.Type())
+	}
+
+	attrs, err := parseComposite(s)
+	if err != nil {
+		return err
+	}
+
+	if elem.Kind() == reflect.Ptr {
+		if elem.IsNil() {
+			elem.Set(reflect.New(elem.Type().Elem()))
+		}
+		elem = elem.Elem()
+	}
+
+	if elem.Kind() != reflect.Struct {
+		return fmt.Errorf("kallax: cannot scan composite into type %s, it is not a struct", elem.Type())
+	}
+
+	fields := compositeFields(elem)
+	if len(fields) != len(attrs) {
+		return fmt.Errorf("kallax: cannot scan composite with %d attributes into type %s with %d fields", len(attrs), elem.Type(), len(fields))
+	}
+
+	for i, f := range fields {
+		if err := scanAttr(f, attrs[i]); err != nil {
+			return fmt.Errorf("kallax: cannot scan attribute %d of composite type %s: %s", i, elem.Type(), err)
+		}
+	}
+
+	return nil
+}
+
+func (c *composite) Value() (driver.Value, error) {
+	rv := reflect.ValueOf(c.val)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("kallax: type %s cannot be converted to a composite, it is not a struct", rv.Type())
+	}
+
+	var buf bytes.Buffer
+	buf.WriteRune('(')
+	for i, f := range compositeFields(rv) {
+		if i > 0 {
+			buf.WriteRune(',')
+		}
+
+		attr, null, err := attrValue(f)
+		if err != nil {
+			return nil, err
+		}
+
+		if !null {
+			buf.WriteString(quoteAttr(attr))
+		}
+	}
+	buf.WriteRune(')')
+	return buf.String(), nil
+}
+
+// compositeFields returns the fields of the given struct that are attributes
+// of the composite type.
+func compositeFields(v reflect.Value) []reflect.Value {
+	var fields []reflect.Value
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if strings.Split(f.Tag.Get("kallax"), ",")[0] == "-" {
+			continue
+		}
+
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			fields = append(fields, compositeFields(v.Field(i))...)
+			continue
+		}
+
+		if f.PkgPath != "" {
+			continue
+		}
+
+		fields = append(fields, v.Field(i))
+	}
+	return fields
+}
+
+// isComposite reports whether the given type is a struct that embeds
+// kallax.Composite.
+func isComposite(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Name() == "Composite" && strings.HasSuffix(f.Type.PkgPath(), "go-kallax.v1") {
+			return true
+		}
+	}
+	return false
+}
+
+func scanAttr(v reflect.Value, s *string) error {
+	if s == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+
+	ptr := v.Addr().Interface()
+	if scanner, ok := ptr.(sql.Scanner); ok {
+		return scanner.Scan([]byte(*s))
+	}
+
+	if v.Type() == timeType {
+		t, err := pq.ParseTimestamp(nil, *s)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(*s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(*s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(*s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(*s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(*s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b, err := hex.DecodeString(strings.TrimPrefix(*s, `\x`))
+			if err != nil {
+				return err
+			}
+			v.SetBytes(b)
+			return nil
+		}
+		return Slice(ptr).Scan([]byte(*s))
+	case reflect.Array:
+		return Array(ptr, v.Len()).Scan([]byte(*s))
+	case reflect.Struct:
+		if isComposite(v.Type()) {
+			return Composite(ptr).Scan(*s)
+		}
+		return json.Unmarshal([]byte(*s), ptr)
+	default:
+		return json.Unmarshal([]byte(*s), ptr)
+	}
+
+	return nil
+}
+
+func attrValue(v reflect.Value) (attr string, null bool, err error) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", true, nil
+		}
+	}
+
+	if valuer, ok := v.Interface().(driver.Valuer); ok {
+		val, err := valuer.Value()
+		if err != nil {
+			return "", false, err
+		}
+		return driverValueAttr(val)
+	}
+
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	if v.CanAddr() {
+		if valuer, ok := v.Addr().Interface().(driver.Valuer); ok {
+			val, err := valuer.Value()
+			if err != nil {
+				return "", false, err
+			}
+			return driverValueAttr(val)
+		}
+	}
+
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).Format(compositeTimeLayout), false, nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), false, nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), false, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), false, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), false, nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), false, nil
+	case reflect.Slice:
+		if v.IsNil() {
+			return "", true, nil
+		}
+
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return `\x` + hex.EncodeToString(v.Bytes()), false, nil
+		}
+		return sqlTypeAttr(Slice(addressable(v).Interface()))
+	case reflect.Array:
+		return sqlTypeAttr(Array(addressable(v).Interface(), v.Len()))
+	case reflect.Struct:
+		if isComposite(v.Type()) {
+			return sqlTypeAttr(Composite(addressable(v).Interface()))
+		}
+	}
+
+	bytes, err := json.Marshal(v.Interface())
+	if err != nil {
+		return "", false, err
+	}
+	return string(bytes), false, nil
+}
+
+// addressable returns a pointer to the given value, copying it if it's not
+// addressable.
+func addressable(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v.Addr()
+	}
+
+	ptr := reflect.New(v.Type())
+	ptr.Elem().Set(v)
+	return ptr
+}
+
+func sqlTypeAttr(v driver.Valuer) (string, bool, error) {
+	val, err := v.Value()
+	if err != nil {
+		return "", false, err
+	}
+	return driverValueAttr(val)
+}
+
+func driverValueAttr(v driver.Value) (string, bool, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", true, nil
+	case string:
+		return v, false, nil
+	case []byte:
+		return string(v), false, nil
+	case time.Time:
+		return v.Format(compositeTimeLayout), false, nil
+	case bool:
+		return strconv.FormatBool(v), false, nil
+	case int64:
+		return strconv.FormatInt(v, 10), false, nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), false, nil
+	}
+	return "", false, fmt.Errorf("kallax: invalid driver value of type %T", v)
+}
+
+func quoteAttr(s string) string {
+	var buf bytes.Buffer
+	buf.WriteRune('"')
+	for _, r := range s {
+		if r == '"' || r == '\\' {
+			buf.WriteRune('\\')
+		}
+		buf.WriteRune(r)
+	}
+	buf.WriteRune('"')
+	return buf.String()
+}
+
+// parseComposite parses the text representation of a PostgreSQL composite
+// value and returns its attributes. NULL attributes are returned as nil.
+func parseComposite(s string) ([]*string, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 || s[0] != '(' || s[len(s)-1] != ')' {
+		return nil, fmt.Errorf("kallax: invalid composite %q", s)
+	}
+
+	var (
+		attrs  []*string
+		buf    bytes.Buffer
+		quoted bool
+		// present reports whether the current attribute has any content,
+		// even an empty quoted string, and thus is not null.
+		present bool
+	)
+
+	add := func() {
+		if present {
+			attr := buf.String()
+			attrs = append(attrs, &attr)
+		} else {
+			attrs = append(attrs, nil)
+		}
+		buf.Reset()
+		present = false
+	}
+
+	body := s[1 : len(s)-1]
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case c == '\\' && i+1 < len(body):
+			i++
+			buf.WriteByte(body[i])
+			present = true
+		case c == '"':
+			if quoted && i+1 < len(body) && body[i+1] == '"' {
+				i++
+				buf.WriteByte('"')
+			} else {
+				quoted = !quoted
+			}
+			present = true
+		case c == ',' && !quoted:
+			add()
+		default:
+			buf.WriteByte(c)
+			present = true
+		}
+	}
+
+	if quoted {
+		return nil, fmt.Errorf("kallax: invalid composite %q: unterminated quoted attribute", s)
+	}
+
+	add()
+	return attrs, nil
+}
@@ -0,0 +1,118 @@
+package types
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type compositeAddress struct {
+	Street  string
+	Number  int
+	Zip     *string
+	Tags    []string
+	Ignored string `kallax:"-"`
+	private string
+}
+
+type compositePlace struct {
+	Name     string
+	Location *compositeLocation
+	Visited  time.Time
+	URL      URL
+	Data     []byte
+}
+
+type compositeLocation struct {
+	Lat float64
+	Lng float64
+}
+
+func TestParseComposite(t *testing.T) {
+	str := func(s string) *string { return &s }
+	cases := []struct {
+		input    string
+		expected []*string
+	}{
+		{`(1,foo)`, []*string{str("1"), str("foo")}},
+		{`(,"")`, []*string{nil, str("")}},
+		{`("a ""quoted"", value",b\,c)`, []*string{str(`a "quoted", value`), str("b,c")}},
+		{`("esc\\aped")`, []*string{str(`esc\aped`)}},
+		{`()`, []*string{nil}},
+	}
+
+	for _, c := range cases {
+		attrs, err := parseComposite(c.input)
+		require.NoError(t, err, c.input)
+		require.Equal(t, c.expected, attrs, c.input)
+	}
+
+	for _, input := range []string{"", "1,2", "(1,2", `("foo)`} {
+		_, err := parseComposite(input)
+		require.Error(t, err, input)
+	}
+}
+
+func TestComposite(t *testing.T) {
+	r := require.New(t)
+	zip := "28001"
+	addr := compositeAddress{"Gran \"Vía\"", 1, &zip, []string{"a", "b"}, "foo", "bar"}
+
+	v, err := Composite(&addr).Value()
+	r.NoError(err)
+	r.Equal(`("Gran \"Vía\"","1","28001","{\"a\",\"b\"}")`, v)
+
+	var scanned compositeAddress
+	r.NoError(Composite(&scanned).Scan([]byte(v.(string))))
+	r.Equal(compositeAddress{Street: addr.Street, Number: 1, Zip: &zip, Tags: addr.Tags}, scanned)
+
+	r.NoError(Composite(&scanned).Scan(`(foo,2,,{})`))
+	r.Equal(compositeAddress{Street: "foo", Number: 2, Tags: []string{}}, scanned)
+
+	r.Error(Composite(&scanned).Scan(`(foo,2)`))
+	r.Error(Composite(&scanned).Scan(`(foo,bar,,{})`))
+	r.Error(Composite(&scanned).Scan(int64(1)))
+}
+
+func TestComposite_Ptr(t *testing.T) {
+	r := require.New(t)
+
+	var addr *compositeAddress
+	v, err := Composite(&addr).Value()
+	r.NoError(err)
+	r.Nil(v)
+
+	r.NoError(Composite(&addr).Scan(`(foo,1,,)`))
+	r.Equal(&compositeAddress{Street: "foo", Number: 1}, addr)
+
+	r.NoError(Composite(&addr).Scan(nil))
+	r.Nil(addr)
+}
+
+func TestComposite_Types(t *testing.T) {
+	r := require.New(t)
+	u, err := url.Parse("https://foo.com/bar")
+	r.NoError(err)
+
+	visited := time.Date(2017, time.January, 2, 3, 4, 5, 0, time.UTC)
+	place := compositePlace{
+		Name:     "foo",
+		Location: &compositeLocation{1.5, -2},
+		Visited:  visited,
+		URL:      URL(*u),
+		Data:     []byte{0xde, 0xad},
+	}
+
+	v, err := Composite(&place).Value()
+	r.NoError(err)
+
+	var scanned compositePlace
+	r.NoError(Composite(&scanned).Scan(v))
+	r.Equal(place.Name, scanned.Name)
+	r.Equal(place.Location, scanned.Location)
+	r.True(visited.Equal(scanned.Visited))
+	r.Equal(place.URL, scanned.URL)
+	r.Equal(place.Data, scanned.Data)
+}
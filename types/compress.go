@@ -0,0 +1,181 @@
+package types
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sync"
+)
+
+// Compressor compresses and decompresses the values stored in compressed
+// columns.
+type Compressor interface {
+	// Compress returns the compressed data.
+	Compress(data []byte) ([]byte, error)
+	// Decompress returns the original data of the given compressed data.
+	Decompress(data []byte) ([]byte, error)
+}
+
+type streamCompressor struct {
+	writer func(io.Writer) io.WriteCloser
+	reader func(io.Reader) (io.ReadCloser, error)
+}
+
+func (c streamCompressor) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := c.writer(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (c streamCompressor) Decompress(data []byte) ([]byte, error) {
+	r, err := c.reader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+var compressors = struct {
+	sync.RWMutex
+	compressors map[string]Compressor
+}{compressors: map[string]Compressor{
+	"gzip": streamCompressor{
+		func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+		func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) },
+	},
+	"zlib": streamCompressor{
+		func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) },
+		zlib.NewReader,
+	},
+}}
+
+// RegisterCompressor registers the given compressor with the given name, so
+// it can be used in compressed columns with Compressed. The "gzip" and "zlib"
+// compressors are always registered. If there is already a compressor
+// registered with the same name it is replaced.
+func RegisterCompressor(name string, c Compressor) {
+	compressors.Lock()
+	defer compressors.Unlock()
+	compressors.compressors[name] = c
+}
+
+func compressor(name string) (Compressor, error) {
+	compressors.RLock()
+	defer compressors.RUnlock()
+	c, ok := compressors.compressors[name]
+	if !ok {
+		return nil, fmt.Errorf("kallax: there is no compressor registered with name %q", name)
+	}
+	return c, nil
+}
+
+type compressed struct {
+	compressor string
+	val        interface{}
+}
+
+// Compressed wraps a pointer to a string or a []byte, or a pointer to a
+// pointer to them, so it is compressed with the compressor registered with
+// the given name when it's converted to SQL and decompressed when it's
+// scanned. Named types of string and []byte are supported as well. The
+// column must be a bytea column. Nil pointers are stored as NULL.
+func Compressed(compressor string, v interface{}) SQLType {
+	return &compressed{compressor, v}
+}
+
+func (c *compressed) Scan(v interface{}) error {
+	rv := reflect.ValueOf(c.val)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("kallax: cannot scan compressed value into non-pointer type %T", c.val)
+	}
+
+	elem := rv.Elem()
+	if v == nil {
+		elem.Set(reflect.Zero(elem.Type()))
+		return nil
+	}
+
+	var src []byte
+	switch v := v.(type) {
+	case []byte:
+		src = v
+	case string:
+		src = []byte(v)
+	default:
+		return fmt.Errorf("kallax: cannot scan type %s into compressed type", reflect.TypeOf(v))
+	}
+
+	comp, err := compressor(c.compressor)
+	if err != nil {
+		return err
+	}
+
+	data, err := comp.Decompress(src)
+	if err != nil {
+		return fmt.Errorf("kallax: cannot decompress value with %s: %s", c.compressor, err)
+	}
+
+	if elem.Kind() == reflect.Ptr {
+		if elem.IsNil() {
+			elem.Set(reflect.New(elem.Type().Elem()))
+		}
+		elem = elem.Elem()
+	}
+
+	switch {
+	case elem.Kind() == reflect.String:
+		elem.SetString(string(data))
+	case elem.Kind() == reflect.Slice && elem.Type().Elem().Kind() == reflect.Uint8:
+		elem.SetBytes(data)
+	default:
+		return fmt.Errorf("kallax: cannot scan compressed value into type %s", elem.Type())
+	}
+	return nil
+}
+
+func (c *compressed) Value() (driver.Value, error) {
+	rv := reflect.ValueOf(c.val)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+
+	var data []byte
+	switch {
+	case rv.Kind() == reflect.String:
+		data = []byte(rv.String())
+	case rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		data = rv.Bytes()
+	default:
+		return nil, fmt.Errorf("kallax: type %s cannot be compressed, it is not a string or []byte", rv.Type())
+	}
+
+	comp, err := compressor(c.compressor)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := comp.Compress(data)
+	if err != nil {
+		return nil, fmt.Errorf("kallax: cannot compress value with %s: %s", c.compressor, err)
+	}
+	return out, nil
+}
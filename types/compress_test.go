@@ -0,0 +1,99 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type compressedText string
+
+func TestCompressed(t *testing.T) {
+	for _, name := range []string{"gzip", "zlib"} {
+		t.Run(name, func(t *testing.T) {
+			r := require.New(t)
+
+			str := "foo bar baz foo bar baz foo bar baz"
+			v, err := Compressed(name, &str).Value()
+			r.NoError(err)
+			r.NotEqual([]byte(str), v)
+
+			var scannedStr string
+			r.NoError(Compressed(name, &scannedStr).Scan(v))
+			r.Equal(str, scannedStr)
+
+			b := []byte{0xde, 0xad, 0xbe, 0xef}
+			v, err = Compressed(name, &b).Value()
+			r.NoError(err)
+
+			var scannedBytes []byte
+			r.NoError(Compressed(name, &scannedBytes).Scan(v))
+			r.Equal(b, scannedBytes)
+
+			text := compressedText("qux")
+			v, err = Compressed(name, &text).Value()
+			r.NoError(err)
+
+			var scannedText compressedText
+			r.NoError(Compressed(name, &scannedText).Scan(v))
+			r.Equal(text, scannedText)
+		})
+	}
+}
+
+func TestCompressed_Ptr(t *testing.T) {
+	r := require.New(t)
+
+	var str *string
+	v, err := Compressed("gzip", &str).Value()
+	r.NoError(err)
+	r.Nil(v)
+
+	foo := "foo"
+	v, err = Compressed("gzip", &foo).Value()
+	r.NoError(err)
+
+	r.NoError(Compressed("gzip", &str).Scan(v))
+	r.Equal(&foo, str)
+
+	r.NoError(Compressed("gzip", &str).Scan(nil))
+	r.Nil(str)
+}
+
+type reverseCompressor struct{}
+
+func (reverseCompressor) Compress(data []byte) ([]byte, error) {
+	return reverseBytes(data), nil
+}
+
+func (reverseCompressor) Decompress(data []byte) ([]byte, error) {
+	return reverseBytes(data), nil
+}
+
+func reverseBytes(data []byte) []byte {
+	result := make([]byte, len(data))
+	for i, b := range data {
+		result[len(data)-1-i] = b
+	}
+	return result
+}
+
+func TestCompressed_Register(t *testing.T) {
+	r := require.New(t)
+	str := "foo"
+
+	_, err := Compressed("reverse", &str).Value()
+	r.Error(err)
+
+	RegisterCompressor("reverse", reverseCompressor{})
+	v, err := Compressed("reverse", &str).Value()
+	r.NoError(err)
+	r.Equal([]byte("oof"), v)
+
+	r.Error(Compressed("gzip", &str).Scan([]byte("not compressed")))
+	r.Error(Compressed("gzip", &str).Scan(1))
+
+	n := 1
+	_, err = Compressed("gzip", &n).Value()
+	r.Error(err)
+}
@@ -0,0 +1,631 @@
+package types
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// JSONSchema is a compiled JSON Schema document used to validate the values
+// stored in JSON columns. Only the validation keywords of the schema are
+// supported, that is:
+//   - type, enum and const
+//   - properties, required and additionalProperties
+//   - items, minItems, maxItems and uniqueItems
+//   - minimum, maximum, exclusiveMinimum, exclusiveMaximum and multipleOf
+//   - minLength, maxLength and pattern
+//   - allOf, anyOf, oneOf and not
+//
+// Annotations, such as title or description, are ignored. References with
+// $ref are not supported and the schema fails to compile if it uses them.
+type JSONSchema struct {
+	root *jsonSchemaNode
+}
+
+type jsonSchemaNode struct {
+	// valid is the result of the validation if the schema is a boolean
+	// schema.
+	valid *bool
+
+	types    []string
+	enum     []interface{}
+	constant interface{}
+	hasConst bool
+
+	properties           map[string]*jsonSchemaNode
+	required             []string
+	additionalProperties *jsonSchemaNode
+
+	items       *jsonSchemaNode
+	minItems    *int
+	maxItems    *int
+	uniqueItems bool
+
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum *float64
+	exclusiveMaximum *float64
+	multipleOf       *float64
+
+	minLength *int
+	maxLength *int
+	pattern   *regexp.Regexp
+
+	allOf []*jsonSchemaNode
+	anyOf []*jsonSchemaNode
+	oneOf []*jsonSchemaNode
+	not   *jsonSchemaNode
+}
+
+var jsonSchemaTypes = map[string]struct{}{
+	"null":    {},
+	"boolean": {},
+	"object":  {},
+	"array":   {},
+	"number":  {},
+	"integer": {},
+	"string":  {},
+}
+
+// CompileJSONSchema compiles the given JSON Schema document. An error is
+// returned if the document is not valid JSON, it uses $ref or any of the
+// supported keywords has an invalid value.
+func CompileJSONSchema(doc []byte) (*JSONSchema, error) {
+	v, err := decodeJSON(doc)
+	if err != nil {
+		return nil, fmt.Errorf("kallax: invalid JSON schema: %s", err)
+	}
+
+	root, err := compileJSONSchemaNode(v, "")
+	if err != nil {
+		return nil, fmt.Errorf("kallax: invalid JSON schema: %s", err)
+	}
+
+	return &JSONSchema{root}, nil
+}
+
+// MustCompileJSONSchema is like CompileJSONSchema, but panics if the document
+// can not be compiled.
+func MustCompileJSONSchema(doc string) *JSONSchema {
+	s, err := CompileJSONSchema([]byte(doc))
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Validate returns an error if the given value does not match the schema. If
+// the value is a driver.Valuer, such as the ones returned by JSON and
+// JSONWithCodec, its JSON representation is the one returned by its Value
+// method, and null values are always valid, as they are stored as NULL.
+// Otherwise, the value is encoded with encoding/json.
+func (s *JSONSchema) Validate(v interface{}) error {
+	var data []byte
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case driver.Valuer:
+		val, err := v.Value()
+		if err != nil {
+			return err
+		}
+
+		switch val := val.(type) {
+		case nil:
+			return nil
+		case []byte:
+			data = val
+		case string:
+			data = []byte(val)
+		default:
+			return fmt.Errorf("kallax: cannot validate value of type %T against a JSON schema", val)
+		}
+	default:
+		var err error
+		data, err = json.Marshal(v)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.ValidateJSON(data)
+}
+
+// ValidateJSON returns an error if the given JSON document does not match
+// the schema.
+func (s *JSONSchema) ValidateJSON(data []byte) error {
+	v, err := decodeJSON(data)
+	if err != nil {
+		return fmt.Errorf("kallax: invalid JSON: %s", err)
+	}
+
+	return s.root.validate(v, "")
+}
+
+func decodeJSON(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+	return v, nil
+}
+
+func compileJSONSchemaNode(v interface{}, path string) (*jsonSchemaNode, error) {
+	if b, ok := v.(bool); ok {
+		return &jsonSchemaNode{valid: &b}, nil
+	}
+
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: schema must be an object or a boolean", pointer(path))
+	}
+
+	if _, ok := obj["$ref"]; ok {
+		return nil, fmt.Errorf("%s: $ref is not supported", pointer(path))
+	}
+
+	n := new(jsonSchemaNode)
+	var err error
+	for key, val := range obj {
+		kpath := path + "/" + key
+		switch key {
+		case "type":
+			n.types, err = compileJSONSchemaTypes(val, kpath)
+		case "enum":
+			vals, ok := val.([]interface{})
+			if !ok {
+				err = fmt.Errorf("%s: must be an array", pointer(kpath))
+			}
+			n.enum = vals
+		case "const":
+			n.constant = val
+			n.hasConst = true
+		case "properties":
+			props, ok := val.(map[string]interface{})
+			if !ok {
+				err = fmt.Errorf("%s: must be an object", pointer(kpath))
+				break
+			}
+
+			n.properties = make(map[string]*jsonSchemaNode, len(props))
+			for name, prop := range props {
+				if n.properties[name], err = compileJSONSchemaNode(prop, kpath+"/"+name); err != nil {
+					break
+				}
+			}
+		case "required":
+			n.required, err = compileJSONSchemaStrings(val, kpath)
+		case "additionalProperties":
+			n.additionalProperties, err = compileJSONSchemaNode(val, kpath)
+		case "items":
+			n.items, err = compileJSONSchemaNode(val, kpath)
+		case "minItems":
+			n.minItems, err = compileJSONSchemaInt(val, kpath)
+		case "maxItems":
+			n.maxItems, err = compileJSONSchemaInt(val, kpath)
+		case "uniqueItems":
+			b, ok := val.(bool)
+			if !ok {
+				err = fmt.Errorf("%s: must be a boolean", pointer(kpath))
+			}
+			n.uniqueItems = b
+		case "minimum":
+			n.minimum, err = compileJSONSchemaNumber(val, kpath)
+		case "maximum":
+			n.maximum, err = compileJSONSchemaNumber(val, kpath)
+		case "exclusiveMinimum":
+			n.exclusiveMinimum, err = compileJSONSchemaNumber(val, kpath)
+		case "exclusiveMaximum":
+			n.exclusiveMaximum, err = compileJSONSchemaNumber(val, kpath)
+		case "multipleOf":
+			n.multipleOf, err = compileJSONSchemaNumber(val, kpath)
+			if err == nil && *n.multipleOf <= 0 {
+				err = fmt.Errorf("%s: must be greater than 0", pointer(kpath))
+			}
+		case "minLength":
+			n.minLength, err = compileJSONSchemaInt(val, kpath)
+		case "maxLength":
+			n.maxLength, err = compileJSONSchemaInt(val, kpath)
+		case "pattern":
+			s, ok := val.(string)
+			if !ok {
+				err = fmt.Errorf("%s: must be a string", pointer(kpath))
+				break
+			}
+
+			if n.pattern, err = regexp.Compile(s); err != nil {
+				err = fmt.Errorf("%s: %s", pointer(kpath), err)
+			}
+		case "allOf":
+			n.allOf, err = compileJSONSchemaNodes(val, kpath)
+		case "anyOf":
+			n.anyOf, err = compileJSONSchemaNodes(val, kpath)
+		case "oneOf":
+			n.oneOf, err = compileJSONSchemaNodes(val, kpath)
+		case "not":
+			n.not, err = compileJSONSchemaNode(val, kpath)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return n, nil
+}
+
+func compileJSONSchemaTypes(v interface{}, path string) ([]string, error) {
+	var types []string
+	if s, ok := v.(string); ok {
+		types = []string{s}
+	} else {
+		var err error
+		if types, err = compileJSONSchemaStrings(v, path); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, t := range types {
+		if _, ok := jsonSchemaTypes[t]; !ok {
+			return nil, fmt.Errorf("%s: invalid type %q", pointer(path), t)
+		}
+	}
+	return types, nil
+}
+
+func compileJSONSchemaStrings(v interface{}, path string) ([]string, error) {
+	vals, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: must be an array of strings", pointer(path))
+	}
+
+	result := make([]string, len(vals))
+	for i, val := range vals {
+		s, ok := val.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: must be an array of strings", pointer(path))
+		}
+		result[i] = s
+	}
+	return result, nil
+}
+
+func compileJSONSchemaNodes(v interface{}, path string) ([]*jsonSchemaNode, error) {
+	vals, ok := v.([]interface{})
+	if !ok || len(vals) == 0 {
+		return nil, fmt.Errorf("%s: must be a non-empty array of schemas", pointer(path))
+	}
+
+	nodes := make([]*jsonSchemaNode, len(vals))
+	for i, val := range vals {
+		var err error
+		if nodes[i], err = compileJSONSchemaNode(val, fmt.Sprintf("%s/%d", path, i)); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+func compileJSONSchemaNumber(v interface{}, path string) (*float64, error) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return nil, fmt.Errorf("%s: must be a number", pointer(path))
+	}
+
+	f, err := n.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", pointer(path), err)
+	}
+	return &f, nil
+}
+
+func compileJSONSchemaInt(v interface{}, path string) (*int, error) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return nil, fmt.Errorf("%s: must be a non-negative integer", pointer(path))
+	}
+
+	i, err := strconv.Atoi(n.String())
+	if err != nil || i < 0 {
+		return nil, fmt.Errorf("%s: must be a non-negative integer", pointer(path))
+	}
+	return &i, nil
+}
+
+func (n *jsonSchemaNode) validate(v interface{}, path string) error {
+	if n.valid != nil {
+		if !*n.valid {
+			return jsonSchemaError(path, "no value is allowed")
+		}
+		return nil
+	}
+
+	if len(n.types) > 0 && !n.hasType(v) {
+		return jsonSchemaError(path, "expected %s, got %s", strings.Join(n.types, " or "), jsonValueType(v))
+	}
+
+	if n.hasConst && !jsonEqual(v, n.constant) {
+		return jsonSchemaError(path, "value is not equal to the constant")
+	}
+
+	if len(n.enum) > 0 {
+		var found bool
+		for _, e := range n.enum {
+			if jsonEqual(v, e) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return jsonSchemaError(path, "value is not one of the enum values")
+		}
+	}
+
+	var err error
+	switch v := v.(type) {
+	case map[string]interface{}:
+		err = n.validateObject(v, path)
+	case []interface{}:
+		err = n.validateArray(v, path)
+	case json.Number:
+		err = n.validateNumber(v, path)
+	case string:
+		err = n.validateString(v, path)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, s := range n.allOf {
+		if err := s.validate(v, path); err != nil {
+			return err
+		}
+	}
+
+	if len(n.anyOf) > 0 {
+		var matched bool
+		for _, s := range n.anyOf {
+			if s.validate(v, path) == nil {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return jsonSchemaError(path, "value does not match any of the schemas in anyOf")
+		}
+	}
+
+	if len(n.oneOf) > 0 {
+		var matches int
+		for _, s := range n.oneOf {
+			if s.validate(v, path) == nil {
+				matches++
+			}
+		}
+
+		if matches != 1 {
+			return jsonSchemaError(path, "value matches %d of the schemas in oneOf instead of exactly one", matches)
+		}
+	}
+
+	if n.not != nil && n.not.validate(v, path) == nil {
+		return jsonSchemaError(path, "value matches the schema in not")
+	}
+
+	return nil
+}
+
+func (n *jsonSchemaNode) hasType(v interface{}) bool {
+	typ := jsonValueType(v)
+	for _, t := range n.types {
+		if t == typ || (t == "number" && typ == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *jsonSchemaNode) validateObject(obj map[string]interface{}, path string) error {
+	for _, name := range n.required {
+		if _, ok := obj[name]; !ok {
+			return jsonSchemaError(path, "missing required property %q", name)
+		}
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	// properties are validated in order so the error is always the same
+	sort.Strings(names)
+
+	for _, name := range names {
+		ppath := path + "/" + name
+		if prop, ok := n.properties[name]; ok {
+			if err := prop.validate(obj[name], ppath); err != nil {
+				return err
+			}
+		} else if n.additionalProperties != nil {
+			if n.additionalProperties.valid != nil && !*n.additionalProperties.valid {
+				return jsonSchemaError(path, "additional property %q is not allowed", name)
+			}
+
+			if err := n.additionalProperties.validate(obj[name], ppath); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (n *jsonSchemaNode) validateArray(arr []interface{}, path string) error {
+	if n.minItems != nil && len(arr) < *n.minItems {
+		return jsonSchemaError(path, "array has %d items, minimum is %d", len(arr), *n.minItems)
+	}
+
+	if n.maxItems != nil && len(arr) > *n.maxItems {
+		return jsonSchemaError(path, "array has %d items, maximum is %d", len(arr), *n.maxItems)
+	}
+
+	if n.uniqueItems {
+		for i := range arr {
+			for j := i + 1; j < len(arr); j++ {
+				if jsonEqual(arr[i], arr[j]) {
+					return jsonSchemaError(path, "items %d and %d are equal", i, j)
+				}
+			}
+		}
+	}
+
+	if n.items != nil {
+		for i, item := range arr {
+			if err := n.items.validate(item, fmt.Sprintf("%s/%d", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (n *jsonSchemaNode) validateNumber(num json.Number, path string) error {
+	f, err := num.Float64()
+	if err != nil {
+		return jsonSchemaError(path, "invalid number %s", num)
+	}
+
+	if n.minimum != nil && f < *n.minimum {
+		return jsonSchemaError(path, "%s is less than the minimum %g", num, *n.minimum)
+	}
+
+	if n.maximum != nil && f > *n.maximum {
+		return jsonSchemaError(path, "%s is greater than the maximum %g", num, *n.maximum)
+	}
+
+	if n.exclusiveMinimum != nil && f <= *n.exclusiveMinimum {
+		return jsonSchemaError(path, "%s is not greater than the exclusive minimum %g", num, *n.exclusiveMinimum)
+	}
+
+	if n.exclusiveMaximum != nil && f >= *n.exclusiveMaximum {
+		return jsonSchemaError(path, "%s is not less than the exclusive maximum %g", num, *n.exclusiveMaximum)
+	}
+
+	if n.multipleOf != nil {
+		q := f / *n.multipleOf
+		if math.Abs(q-math.Round(q)) > 1e-9 {
+			return jsonSchemaError(path, "%s is not a multiple of %g", num, *n.multipleOf)
+		}
+	}
+
+	return nil
+}
+
+func (n *jsonSchemaNode) validateString(s string, path string) error {
+	length := utf8.RuneCountInString(s)
+	if n.minLength != nil && length < *n.minLength {
+		return jsonSchemaError(path, "string has %d characters, minimum is %d", length, *n.minLength)
+	}
+
+	if n.maxLength != nil && length > *n.maxLength {
+		return jsonSchemaError(path, "string has %d characters, maximum is %d", length, *n.maxLength)
+	}
+
+	if n.pattern != nil && !n.pattern.MatchString(s) {
+		return jsonSchemaError(path, "string does not match pattern %q", n.pattern)
+	}
+
+	return nil
+}
+
+func jsonSchemaError(path string, msg string, args ...interface{}) error {
+	return fmt.Errorf("kallax: value does not match JSON schema at %s: %s", pointer(path), fmt.Sprintf(msg, args...))
+}
+
+// pointer returns the JSON pointer of the given path, which is "/" for the
+// root of the document.
+func pointer(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+// jsonValueType returns the JSON Schema type of a decoded JSON value.
+func jsonValueType(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case json.Number:
+		if f, err := v.Float64(); err == nil && f == math.Trunc(f) {
+			return "integer"
+		}
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// jsonEqual reports whether two decoded JSON values are equal. Numbers are
+// equal if they have the same value, regardless of their representation.
+func jsonEqual(a, b interface{}) bool {
+	switch a := a.(type) {
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+
+		fa, erra := a.Float64()
+		fb, errb := b.Float64()
+		return erra == nil && errb == nil && fa == fb
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+
+		for k, v := range a {
+			bv, ok := b[k]
+			if !ok || !jsonEqual(v, bv) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+
+		for i := range a {
+			if !jsonEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
@@ -0,0 +1,130 @@
+package types
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const settingsSchema = `{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"title": "settings",
+	"type": "object",
+	"required": ["theme"],
+	"additionalProperties": false,
+	"properties": {
+		"theme": {"enum": ["light", "dark"]},
+		"fontSize": {"type": "integer", "minimum": 8, "exclusiveMaximum": 72},
+		"email": {"type": "string", "pattern": "^[^@]+@[^@]+$", "maxLength": 20},
+		"tags": {"type": "array", "items": {"type": "string", "minLength": 1}, "maxItems": 3, "uniqueItems": true},
+		"ratio": {"type": ["number", "null"], "multipleOf": 0.25},
+		"contact": {
+			"oneOf": [
+				{"type": "string"},
+				{"type": "object", "required": ["phone"]}
+			]
+		},
+		"version": {"const": 1},
+		"mode": {"anyOf": [{"type": "boolean"}, {"type": "string"}], "not": {"const": "off"}}
+	}
+}`
+
+func TestJSONSchema_Validate(t *testing.T) {
+	s, err := CompileJSONSchema([]byte(settingsSchema))
+	require.NoError(t, err)
+
+	valid := []string{
+		`{"theme": "dark"}`,
+		`{"theme": "light", "fontSize": 8, "email": "foo@bar.com"}`,
+		`{"theme": "light", "tags": ["a", "b"], "ratio": 0.75}`,
+		`{"theme": "light", "ratio": null, "contact": "foo", "version": 1.0}`,
+		`{"theme": "light", "contact": {"phone": "555"}, "mode": true}`,
+	}
+
+	for _, c := range valid {
+		require.NoError(t, s.ValidateJSON([]byte(c)), c)
+	}
+
+	invalid := map[string]string{
+		`[]`:                                 "at /: expected object, got array",
+		`{}`:                                 `at /: missing required property "theme"`,
+		`{"theme": "blue"}`:                  "at /theme: value is not one of the enum values",
+		`{"theme": "dark", "other": 1}`:      `at /: additional property "other" is not allowed`,
+		`{"theme": "dark", "fontSize": 8.5}`: "at /fontSize: expected integer, got number",
+		`{"theme": "dark", "fontSize": 7}`:   "at /fontSize: 7 is less than the minimum 8",
+		`{"theme": "dark", "fontSize": 72}`:  "at /fontSize: 72 is not less than the exclusive maximum 72",
+		`{"theme": "dark", "email": "foo"}`:  "at /email: string does not match pattern",
+		`{"theme": "dark", "email": "foooooo@barrrrrrr.com"}`: "at /email: string has 21 characters, maximum is 20",
+		`{"theme": "dark", "tags": ["a", ""]}`:                "at /tags/1: string has 0 characters, minimum is 1",
+		`{"theme": "dark", "tags": ["a", "b", "c", "d"]}`:     "at /tags: array has 4 items, maximum is 3",
+		`{"theme": "dark", "tags": ["a", "a"]}`:               "at /tags: items 0 and 1 are equal",
+		`{"theme": "dark", "ratio": 0.3}`:                     "at /ratio: 0.3 is not a multiple of 0.25",
+		`{"theme": "dark", "contact": {}}`:                    "at /contact: value matches 0 of the schemas in oneOf",
+		`{"theme": "dark", "version": 2}`:                     "at /version: value is not equal to the constant",
+		`{"theme": "dark", "mode": 1}`:                        "at /mode: value does not match any of the schemas in anyOf",
+		`{"theme": "dark", "mode": "off"}`:                    "at /mode: value matches the schema in not",
+		`{"theme": "dark"`:                                    "invalid JSON",
+	}
+
+	for c, msg := range invalid {
+		err := s.ValidateJSON([]byte(c))
+		require.Error(t, err, c)
+		require.Contains(t, err.Error(), msg, c)
+	}
+}
+
+func TestJSONSchema_ValidateValues(t *testing.T) {
+	r := require.New(t)
+	s := MustCompileJSONSchema(`{"type": "object", "required": ["Name"]}`)
+
+	type settings struct {
+		Name string `json:",omitempty"`
+	}
+
+	r.NoError(s.Validate(settings{"foo"}))
+	r.Error(s.Validate(settings{}))
+	r.NoError(s.Validate(JSON(&settings{"foo"})))
+	r.Error(s.Validate(JSON(&settings{})))
+	r.NoError(s.Validate(JSONWithCodec("json", &settings{"foo"})))
+	r.NoError(s.Validate(nil))
+	r.NoError(s.Validate(nullValuer{}))
+}
+
+type nullValuer struct{}
+
+func (nullValuer) Value() (driver.Value, error) {
+	return nil, nil
+}
+
+func TestCompileJSONSchema_Invalid(t *testing.T) {
+	cases := []string{
+		``,
+		`"object"`,
+		`{"type": "foo"}`,
+		`{"type": 1}`,
+		`{"required": "foo"}`,
+		`{"properties": {"foo": 1}}`,
+		`{"minLength": -1}`,
+		`{"maximum": "1"}`,
+		`{"multipleOf": 0}`,
+		`{"pattern": "("}`,
+		`{"anyOf": []}`,
+		`{"items": {"$ref": "#/definitions/foo"}}`,
+	}
+
+	for _, c := range cases {
+		_, err := CompileJSONSchema([]byte(c))
+		require.Error(t, err, c)
+	}
+
+	require.Panics(t, func() {
+		MustCompileJSONSchema(`{"type": "foo"}`)
+	})
+}
+
+func TestJSONSchema_BooleanSchemas(t *testing.T) {
+	s := MustCompileJSONSchema(`{"properties": {"foo": true, "bar": false}}`)
+	require.NoError(t, s.ValidateJSON([]byte(`{"foo": 1}`)))
+	require.Error(t, s.ValidateJSON([]byte(`{"bar": 1}`)))
+}
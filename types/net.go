@@ -0,0 +1,108 @@
+package types
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+)
+
+// IP is a wrapper of net.IP that implements SQLType interface. It is meant to
+// be stored in a Postgres inet column.
+type IP net.IP
+
+func (ip *IP) Scan(v interface{}) error {
+	switch t := v.(type) {
+	case []byte:
+		return ip.Scan(string(t))
+	case string:
+		// inet columns may contain a netmask, which is discarded here
+		if idx := strings.Index(t, "/"); idx >= 0 {
+			t = t[:idx]
+		}
+
+		parsed := net.ParseIP(t)
+		if parsed == nil {
+			return fmt.Errorf("kallax: error scanning ip: invalid ip %q", t)
+		}
+
+		*ip = IP(parsed)
+		return nil
+	case nil:
+		*ip = nil
+		return nil
+	}
+	return fmt.Errorf("kallax: cannot scan type %s into IP type", reflect.TypeOf(v))
+}
+
+func (ip IP) Value() (driver.Value, error) {
+	if ip == nil {
+		return nil, nil
+	}
+	return net.IP(ip).String(), nil
+}
+
+// IPNet is a wrapper of net.IPNet that implements SQLType interface. It is
+// meant to be stored in a Postgres cidr or inet column.
+type IPNet net.IPNet
+
+func (n *IPNet) Scan(v interface{}) error {
+	switch t := v.(type) {
+	case []byte:
+		return n.Scan(string(t))
+	case string:
+		// inet columns omit the netmask of single hosts
+		if !strings.Contains(t, "/") {
+			if strings.Contains(t, ":") {
+				t += "/128"
+			} else {
+				t += "/32"
+			}
+		}
+
+		_, network, err := net.ParseCIDR(t)
+		if err != nil {
+			return fmt.Errorf("kallax: error scanning ip network: %s", err)
+		}
+
+		*n = IPNet(*network)
+		return nil
+	}
+	return fmt.Errorf("kallax: cannot scan type %s into IPNet type", reflect.TypeOf(v))
+}
+
+func (n IPNet) Value() (driver.Value, error) {
+	network := net.IPNet(n)
+	return (&network).String(), nil
+}
+
+// HardwareAddr is a wrapper of net.HardwareAddr that implements SQLType
+// interface. It is meant to be stored in a Postgres macaddr column.
+type HardwareAddr net.HardwareAddr
+
+func (a *HardwareAddr) Scan(v interface{}) error {
+	switch t := v.(type) {
+	case []byte:
+		return a.Scan(string(t))
+	case string:
+		addr, err := net.ParseMAC(t)
+		if err != nil {
+			return fmt.Errorf("kallax: error scanning hardware address: %s", err)
+		}
+
+		*a = HardwareAddr(addr)
+		return nil
+	case nil:
+		*a = nil
+		return nil
+	}
+	return fmt.Errorf("kallax: cannot scan type %s into HardwareAddr type", reflect.TypeOf(v))
+}
+
+func (a HardwareAddr) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	return net.HardwareAddr(a).String(), nil
+}
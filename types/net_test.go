@@ -0,0 +1,72 @@
+package types
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIP(t *testing.T) {
+	require := require.New(t)
+
+	var ip IP
+	require.NoError(ip.Scan([]byte("192.168.1.5")))
+	require.Equal("192.168.1.5", net.IP(ip).String())
+
+	require.NoError(ip.Scan("10.0.0.1/8"))
+	require.Equal("10.0.0.1", net.IP(ip).String())
+
+	require.NoError(ip.Scan("::1"))
+	require.Equal("::1", net.IP(ip).String())
+
+	require.Error(ip.Scan("foo"))
+	require.Error(ip.Scan(1))
+
+	val, err := IP(net.ParseIP("127.0.0.1")).Value()
+	require.NoError(err)
+	require.Equal("127.0.0.1", val)
+
+	val, err = IP(nil).Value()
+	require.NoError(err)
+	require.Nil(val)
+}
+
+func TestIPNet(t *testing.T) {
+	require := require.New(t)
+
+	var n IPNet
+	require.NoError(n.Scan([]byte("192.168.0.0/16")))
+	network := net.IPNet(n)
+	require.Equal("192.168.0.0/16", network.String())
+
+	require.NoError(n.Scan("192.168.1.5"))
+	network = net.IPNet(n)
+	require.Equal("192.168.1.5/32", network.String())
+
+	require.NoError(n.Scan("2001:db8::/32"))
+	network = net.IPNet(n)
+	require.Equal("2001:db8::/32", network.String())
+
+	require.Error(n.Scan("foo/8"))
+	require.Error(n.Scan(nil))
+
+	val, err := n.Value()
+	require.NoError(err)
+	require.Equal("2001:db8::/32", val)
+}
+
+func TestHardwareAddr(t *testing.T) {
+	require := require.New(t)
+
+	var a HardwareAddr
+	require.NoError(a.Scan([]byte("08:00:2b:01:02:03")))
+	require.Equal("08:00:2b:01:02:03", net.HardwareAddr(a).String())
+
+	require.Error(a.Scan("foo"))
+	require.Error(a.Scan(1))
+
+	val, err := a.Value()
+	require.NoError(err)
+	require.Equal("08:00:2b:01:02:03", val)
+}
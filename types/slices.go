@@ -2,9 +2,11 @@ package types
 
 import (
 	"bytes"
+	"database/sql"
 	"database/sql/driver"
 	"fmt"
 	"net/url"
+	"reflect"
 	"strconv"
 	"strings"
 
@@ -21,6 +23,8 @@ type slice struct {
 //  - slices of *url.URL and url.URL
 //  - slices of types that implement sql.Scanner and driver.Valuer (take into
 //    account that these make use of reflection for scan/value)
+//  - slices of named types whose underlying type is a Go basic type, such as
+//    enums (these make use of reflection for scan too)
 //
 // NOTE: Keep in mind to always use the following types in the database schema
 // to keep it in sync with the values allowed in Go.
@@ -84,6 +88,10 @@ func Slice(v interface{}) SQLType {
 }
 
 func (a *slice) Scan(v interface{}) error {
+	if rv, dims := sliceDims(a.val); dims > 1 && rv.CanSet() {
+		return scanMultiDimSlice(rv, dims, v)
+	}
+
 	switch o := a.val.(type) {
 	case *[]url.URL:
 		var s []string
@@ -116,10 +124,80 @@ func (a *slice) Scan(v interface{}) error {
 		*o = res
 		return nil
 	}
+
+	if ok, err := scanNamedBasicSlice(a.val, v); ok {
+		return err
+	}
 	return pq.Array(a.val).Scan(v)
 }
 
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// basicTypes are the basic types for each kind that can be the underlying
+// type of the elements of a slice.
+var basicTypes = map[reflect.Kind]reflect.Type{
+	reflect.String:  reflect.TypeOf(""),
+	reflect.Bool:    reflect.TypeOf(false),
+	reflect.Int:     reflect.TypeOf(int(0)),
+	reflect.Int8:    reflect.TypeOf(int8(0)),
+	reflect.Int16:   reflect.TypeOf(int16(0)),
+	reflect.Int32:   reflect.TypeOf(int32(0)),
+	reflect.Int64:   reflect.TypeOf(int64(0)),
+	reflect.Uint:    reflect.TypeOf(uint(0)),
+	reflect.Uint8:   reflect.TypeOf(uint8(0)),
+	reflect.Uint16:  reflect.TypeOf(uint16(0)),
+	reflect.Uint32:  reflect.TypeOf(uint32(0)),
+	reflect.Uint64:  reflect.TypeOf(uint64(0)),
+	reflect.Float32: reflect.TypeOf(float32(0)),
+	reflect.Float64: reflect.TypeOf(float64(0)),
+}
+
+// scanNamedBasicSlice scans the given value into dst if it is a pointer to a
+// slice of a named type with a basic underlying type that does not implement
+// sql.Scanner, e.g. `[]Status` being `type Status string`. The array is
+// scanned as a slice of the underlying type and then converted. It reports
+// whether dst was such a slice.
+func scanNamedBasicSlice(dst, v interface{}) (bool, error) {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		return false, nil
+	}
+
+	typ := rv.Elem().Type()
+	elem := typ.Elem()
+	if elem.PkgPath() == "" || reflect.PtrTo(elem).Implements(scannerType) {
+		return false, nil
+	}
+
+	basic, ok := basicTypes[elem.Kind()]
+	if !ok {
+		return false, nil
+	}
+
+	tmp := reflect.New(reflect.SliceOf(basic))
+	if err := Slice(tmp.Interface()).Scan(v); err != nil {
+		return true, err
+	}
+
+	src := tmp.Elem()
+	if src.IsNil() {
+		rv.Elem().Set(reflect.Zero(typ))
+		return true, nil
+	}
+
+	res := reflect.MakeSlice(typ, src.Len(), src.Len())
+	for i := 0; i < src.Len(); i++ {
+		res.Index(i).Set(src.Index(i).Convert(elem))
+	}
+	rv.Elem().Set(res)
+	return true, nil
+}
+
 func (a slice) Value() (driver.Value, error) {
+	if rv, dims := sliceDims(a.val); dims > 1 {
+		return multiDimSliceValue(rv, dims)
+	}
+
 	switch v := a.val.(type) {
 	case *[]url.URL:
 		var s = make([]string, len(*v))
@@ -146,6 +224,209 @@ func (a slice) Value() (driver.Value, error) {
 	}
 }
 
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+// sliceDims returns the slice value of the given slice or pointer to slice
+// and its number of dimensions. Slices of bytes and slices implementing
+// driver.Valuer are considered elements and not dimensions.
+func sliceDims(v interface{}) (reflect.Value, int) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+
+	if !rv.IsValid() {
+		return rv, 0
+	}
+
+	var dims int
+	for t := rv.Type(); isSliceDim(t); t = t.Elem() {
+		dims++
+	}
+	return rv, dims
+}
+
+func isSliceDim(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice &&
+		t.Elem().Kind() != reflect.Uint8 &&
+		!t.Implements(valuerType) &&
+		!reflect.PtrTo(t).Implements(scannerType)
+}
+
+func sliceElemType(t reflect.Type, dims int) reflect.Type {
+	for i := 0; i < dims; i++ {
+		t = t.Elem()
+	}
+	return t
+}
+
+// scanMultiDimSlice scans a multidimensional Postgres array into the given
+// slice with the given number of dimensions. The elements are scanned as a
+// slice of one dimension, so the same restrictions of the Slice function are
+// applied to them.
+func scanMultiDimSlice(dst reflect.Value, dims int, v interface{}) error {
+	var src []byte
+	switch t := v.(type) {
+	case []byte:
+		src = t
+	case string:
+		src = []byte(t)
+	case nil:
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	default:
+		return fmt.Errorf("kallax: cannot convert %T to %s", v, dst.Type())
+	}
+
+	arrDims, elems, err := parseArray(src, []byte{','})
+	if err != nil {
+		return err
+	}
+
+	if len(arrDims) == 0 {
+		dst.Set(reflect.MakeSlice(dst.Type(), 0, 0))
+		return nil
+	}
+
+	if len(arrDims) != dims {
+		return fmt.Errorf(
+			"kallax: cannot scan array of %d dimensions into %s",
+			len(arrDims),
+			dst.Type(),
+		)
+	}
+
+	flat := reflect.New(reflect.SliceOf(sliceElemType(dst.Type(), dims)))
+	if err := Slice(flat.Interface()).Scan(formatArray(elems, []int{len(elems)})); err != nil {
+		return err
+	}
+
+	dst.Set(nestSlice(dst.Type(), flat.Elem(), arrDims))
+	return nil
+}
+
+func nestSlice(typ reflect.Type, flat reflect.Value, dims []int) reflect.Value {
+	res := reflect.MakeSlice(typ, dims[0], dims[0])
+	if len(dims) == 1 {
+		reflect.Copy(res, flat)
+		return res
+	}
+
+	size := flat.Len() / dims[0]
+	for i := 0; i < dims[0]; i++ {
+		res.Index(i).Set(nestSlice(typ.Elem(), flat.Slice(i*size, (i+1)*size), dims[1:]))
+	}
+	return res
+}
+
+// multiDimSliceValue returns the Postgres array representation of the given
+// multidimensional slice. All the slices of the same dimension must have the
+// same length.
+func multiDimSliceValue(rv reflect.Value, dims int) (driver.Value, error) {
+	if rv.IsNil() {
+		return nil, nil
+	}
+
+	shape := make([]int, dims)
+	v := rv
+	for i := range shape {
+		shape[i] = v.Len()
+		if shape[i] == 0 {
+			return "{}", nil
+		}
+		v = v.Index(0)
+	}
+
+	flat := reflect.MakeSlice(reflect.SliceOf(sliceElemType(rv.Type(), dims)), 0, 0)
+	flat, err := flattenSlice(rv, shape, flat)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := Slice(flat.Interface()).Value()
+	if err != nil {
+		return nil, err
+	}
+
+	var src []byte
+	switch val := val.(type) {
+	case string:
+		src = []byte(val)
+	case []byte:
+		src = val
+	default:
+		return nil, fmt.Errorf("kallax: unexpected array value of type %T", val)
+	}
+
+	_, elems, err := parseArray(src, []byte{','})
+	if err != nil {
+		return nil, err
+	}
+
+	return formatArray(elems, shape), nil
+}
+
+func flattenSlice(rv reflect.Value, shape []int, flat reflect.Value) (reflect.Value, error) {
+	if rv.Len() != shape[0] {
+		return flat, fmt.Errorf("kallax: multidimensional arrays must have elements with matching dimensions")
+	}
+
+	if len(shape) == 1 {
+		return reflect.AppendSlice(flat, rv), nil
+	}
+
+	var err error
+	for i := 0; i < rv.Len(); i++ {
+		flat, err = flattenSlice(rv.Index(i), shape[1:], flat)
+		if err != nil {
+			return flat, err
+		}
+	}
+	return flat, nil
+}
+
+// formatArray returns the Postgres array representation of the given raw
+// elements with the given dimensions.
+func formatArray(elems [][]byte, shape []int) string {
+	var buf bytes.Buffer
+	formatArrayDim(&buf, elems, shape)
+	return buf.String()
+}
+
+func formatArrayDim(buf *bytes.Buffer, elems [][]byte, shape []int) {
+	buf.WriteRune('{')
+	if len(shape) == 1 {
+		for i, e := range elems {
+			if i > 0 {
+				buf.WriteRune(',')
+			}
+
+			if e == nil {
+				buf.WriteString("NULL")
+				continue
+			}
+
+			buf.WriteRune('"')
+			for _, c := range e {
+				if c == '"' || c == '\\' {
+					buf.WriteByte('\\')
+				}
+				buf.WriteByte(c)
+			}
+			buf.WriteRune('"')
+		}
+	} else {
+		size := len(elems) / shape[0]
+		for i := 0; i < shape[0]; i++ {
+			if i > 0 {
+				buf.WriteRune(',')
+			}
+			formatArrayDim(buf, elems[i*size:(i+1)*size], shape[1:])
+		}
+	}
+	buf.WriteRune('}')
+}
+
 // Uint64Array represents a one-dimensional array of the PostgreSQL unsigned bigint type.
 type Uint64Array []uint64
 
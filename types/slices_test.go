@@ -2,6 +2,7 @@ package types
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"math"
 	"net/url"
@@ -110,6 +111,95 @@ func TestSlice(t *testing.T) {
 	})
 }
 
+type status string
+
+type priority int16
+
+type valuer struct {
+	v string
+}
+
+func (v *valuer) Scan(src interface{}) error {
+	v.v = "scanned:" + string(src.([]byte))
+	return nil
+}
+
+func (v valuer) Value() (driver.Value, error) {
+	return v.v, nil
+}
+
+func TestSlice_NamedElems(t *testing.T) {
+	require := require.New(t)
+
+	val, err := Slice([]status{"active", "deleted"}).Value()
+	require.NoError(err)
+	require.Equal(`{"active","deleted"}`, val)
+
+	var statuses []status
+	require.NoError(Slice(&statuses).Scan(val))
+	require.Equal([]status{"active", "deleted"}, statuses)
+
+	val, err = Slice([]priority{1, 2}).Value()
+	require.NoError(err)
+	require.Equal(`{1,2}`, val)
+
+	var priorities []priority
+	require.NoError(Slice(&priorities).Scan([]byte(`{1,2}`)))
+	require.Equal([]priority{1, 2}, priorities)
+
+	require.NoError(Slice(&priorities).Scan(nil))
+	require.Nil(priorities)
+
+	require.Error(Slice(&priorities).Scan([]byte(`{a}`)))
+
+	val, err = Slice([]valuer{{"a"}, {"b"}}).Value()
+	require.NoError(err)
+	require.Equal(`{"a","b"}`, val)
+
+	var valuers []valuer
+	require.NoError(Slice(&valuers).Scan([]byte(`{a,b}`)))
+	require.Equal([]valuer{{"scanned:a"}, {"scanned:b"}}, valuers)
+}
+
+func TestSlice_MultiDim(t *testing.T) {
+	require := require.New(t)
+
+	val, err := Slice([][]float64{{1, 2.5}, {3, 4}}).Value()
+	require.NoError(err)
+	require.Equal(`{{"1","2.5"},{"3","4"}}`, val)
+
+	var matrix [][]float64
+	require.NoError(Slice(&matrix).Scan(val))
+	require.Equal([][]float64{{1, 2.5}, {3, 4}}, matrix)
+
+	require.NoError(Slice(&matrix).Scan([]byte(`{{1,2},{3,4},{5,6}}`)))
+	require.Equal([][]float64{{1, 2}, {3, 4}, {5, 6}}, matrix)
+
+	require.NoError(Slice(&matrix).Scan([]byte(`{}`)))
+	require.Equal([][]float64{}, matrix)
+
+	require.NoError(Slice(&matrix).Scan(nil))
+	require.Nil(matrix)
+
+	require.Error(Slice(&matrix).Scan([]byte(`{1,2}`)))
+	require.Error(Slice(&matrix).Scan([]byte(`{{1,2},{3}}`)))
+
+	_, err = Slice([][]float64{{1, 2}, {3}}).Value()
+	require.Error(err)
+
+	val, err = Slice([][][]string{{{"a", "b"}}, {{"c", `d"e`}}}).Value()
+	require.NoError(err)
+	require.Equal(`{{{"a","b"}},{{"c","d\"e"}}}`, val)
+
+	var cube [][][]string
+	require.NoError(Slice(&cube).Scan(val))
+	require.Equal([][][]string{{{"a", "b"}}, {{"c", `d"e`}}}, cube)
+
+	val, err = Slice([][]float64{}).Value()
+	require.NoError(err)
+	require.Equal("{}", val)
+}
+
 func TestSlice_Integration(t *testing.T) {
 	cases := []struct {
 		name  string
@@ -171,6 +261,12 @@ func TestSlice_Integration(t *testing.T) {
 			[]float32{.3, .6},
 			&([]float32{.3, .6}),
 		},
+		{
+			"multidimensional float64",
+			"double precision[]",
+			[][]float64{{.5, 1}, {-2, math.MaxFloat64}},
+			&([][]float64{}),
+		},
 	}
 
 	db, err := openTestDB()
@@ -24,7 +24,8 @@ type SQLType interface {
 // a nullable type. For that, it must be either a pointer of a basic Go type or
 // a type that implements sql.Scanner itself.
 // time.Time and time.Duration are also supported, even though they are none of
-// the above.
+// the above, as well as pointers of named types whose underlying type is a
+// basic Go type, e.g. `type Status string`.
 // If the given types does not fall into any of the above categories, it will
 // actually return a valid sql.Scanner that will fail only when the Scan is
 // performed.
@@ -98,9 +99,48 @@ func Nullable(typ interface{}) sql.Scanner {
 		return &nullPtrDuration{typ}
 	}
 
+	if n, ok := nullableNamedBasic(typ); ok {
+		return n
+	}
+
 	return &nullableErr{typ}
 }
 
+// nullNamedBasic is a nullable pointer of a named type with a basic
+// underlying type, which is scanned as the underlying type and converted.
+type nullNamedBasic struct {
+	v     reflect.Value
+	basic reflect.Type
+}
+
+func nullableNamedBasic(typ interface{}) (*nullNamedBasic, bool) {
+	rv := reflect.ValueOf(typ)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return nil, false
+	}
+
+	basic, ok := basicTypes[rv.Elem().Kind()]
+	if !ok || rv.Elem().Type() == basic {
+		return nil, false
+	}
+
+	return &nullNamedBasic{rv.Elem(), basic}, true
+}
+
+func (n *nullNamedBasic) Scan(v interface{}) error {
+	if v == nil {
+		return nil
+	}
+
+	tmp := reflect.New(n.basic)
+	if err := Nullable(tmp.Interface()).Scan(v); err != nil {
+		return err
+	}
+
+	n.v.Set(tmp.Elem().Convert(n.v.Type()))
+	return nil
+}
+
 type nullableErr struct {
 	v interface{}
 }
@@ -100,6 +100,21 @@ func TestArray(t *testing.T) {
 	require.Equal(input, v)
 }
 
+type namedString string
+
+func TestNullable_NamedBasic(t *testing.T) {
+	r := require.New(t)
+	var s namedString
+	r.NoError(Nullable(&s).Scan([]byte("foo")))
+	r.Equal(namedString("foo"), s)
+
+	r.NoError(Nullable(&s).Scan(nil))
+	r.Equal(namedString("foo"), s)
+
+	var n struct{}
+	r.Error(Nullable(&n).Scan("foo"))
+}
+
 func TestNullable(t *testing.T) {
 	var (
 		Str         string
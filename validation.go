@@ -0,0 +1,268 @@
+package kallax
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidationRule is a rule the value of a field must satisfy, which can be
+// given in the struct tag `validate` of the fields of the models, such as
+// `validate:"required,max=255,email"`.
+type ValidationRule interface {
+	// String returns the rule as it is written in the struct tag.
+	String() string
+	// Valid reports whether the given value satisfies the rule. Pointers are
+	// dereferenced and nil pointers are never given.
+	Valid(value interface{}) bool
+}
+
+// RequiredRule returns a rule satisfied by the values that are not empty, that
+// is, that are not nil, the zero value of their type or empty collections.
+// If a value does not satisfy it, the rest of rules are not checked.
+func RequiredRule() ValidationRule {
+	return &rule{name: "required", valid: func(v reflect.Value) bool {
+		return !isEmptyValue(v)
+	}}
+}
+
+// OmitEmptyRule returns a rule that makes the rest of rules be checked only if
+// the value is not empty, see RequiredRule. Nil pointers are never checked.
+func OmitEmptyRule() ValidationRule {
+	return &rule{name: "omitempty", valid: func(reflect.Value) bool { return true }}
+}
+
+// MinRule returns a rule satisfied by the numbers that are not less than the
+// given number, and by the strings, slices, arrays and maps whose length is
+// not less than it. The length of the strings is their number of runes.
+func MinRule(n float64) ValidationRule {
+	return &rule{name: "min", param: formatParam(n), valid: func(v reflect.Value) bool {
+		x, ok := measure(v)
+		return ok && x >= n
+	}}
+}
+
+// MaxRule returns a rule satisfied by the numbers that are not greater than the
+// given number, and by the strings, slices, arrays and maps whose length is
+// not greater than it. The length of the strings is their number of runes.
+func MaxRule(n float64) ValidationRule {
+	return &rule{name: "max", param: formatParam(n), valid: func(v reflect.Value) bool {
+		x, ok := measure(v)
+		return ok && x <= n
+	}}
+}
+
+// LenRule returns a rule satisfied by the strings, slices, arrays and maps with
+// the given length. The length of the strings is their number of runes.
+func LenRule(n int) ValidationRule {
+	return &rule{name: "len", param: strconv.Itoa(n), valid: func(v reflect.Value) bool {
+		if x, ok := length(v); ok {
+			return x == n
+		}
+		return false
+	}}
+}
+
+// EmailRule returns a rule satisfied by the strings that are an email address,
+// without a display name.
+func EmailRule() ValidationRule {
+	return &rule{name: "email", valid: func(v reflect.Value) bool {
+		if v.Kind() != reflect.String {
+			return false
+		}
+		addr, err := mail.ParseAddress(v.String())
+		return err == nil && addr.Name == "" && addr.Address == v.String()
+	}}
+}
+
+// URLRule returns a rule satisfied by the strings that are an absolute URL, with
+// a scheme and a host.
+func URLRule() ValidationRule {
+	return &rule{name: "url", valid: func(v reflect.Value) bool {
+		if v.Kind() != reflect.String {
+			return false
+		}
+		u, err := url.ParseRequestURI(v.String())
+		return err == nil && u.Scheme != "" && u.Host != ""
+	}}
+}
+
+// OneOfRule returns a rule satisfied by the strings and integers that are one of
+// the given values, which are separated by spaces in the struct tag.
+func OneOfRule(values ...string) ValidationRule {
+	return &rule{name: "oneof", param: strings.Join(values, " "), valid: func(v reflect.Value) bool {
+		var s string
+		switch v.Kind() {
+		case reflect.String:
+			s = v.String()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			s = strconv.FormatInt(v.Int(), 10)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			s = strconv.FormatUint(v.Uint(), 10)
+		default:
+			return false
+		}
+
+		for _, value := range values {
+			if s == value {
+				return true
+			}
+		}
+		return false
+	}}
+}
+
+type rule struct {
+	name  string
+	param string
+	valid func(reflect.Value) bool
+}
+
+func (r *rule) String() string {
+	if r.param == "" {
+		return r.name
+	}
+	return r.name + "=" + r.param
+}
+
+func (r *rule) Valid(value interface{}) bool {
+	return r.valid(reflect.ValueOf(value))
+}
+
+func formatParam(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// measure returns the number a value is compared with by Min and Max, which
+// is the value itself if it's a number or its length otherwise.
+func measure(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+
+	n, ok := length(v)
+	return float64(n), ok
+}
+
+func length(v reflect.Value) (int, bool) {
+	switch v.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(v.String()), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return v.Len(), true
+	}
+	return 0, false
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+
+	if z, ok := v.Interface().(interface{ IsZero() bool }); ok {
+		return z.IsZero()
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.String:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+	return v.IsZero()
+}
+
+// FieldError is a rule not satisfied by the value of a field of a record.
+type FieldError struct {
+	// Field is the name of the field.
+	Field string
+	// Rule is the rule as it is written in the struct tag, such as "max=255".
+	Rule string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("field %s does not satisfy %s", e.Field, e.Rule)
+}
+
+// ValidationError is the error returned by the generated Validate methods of
+// the models with fields with the struct tag `validate`, which lists all the
+// rules not satisfied by the fields of a record.
+type ValidationError struct {
+	// Model is the name of the model of the record.
+	Model string
+	// Fields are the rules not satisfied by the fields, in the order of the
+	// fields and their rules.
+	Fields []*FieldError
+}
+
+// NewValidationError returns a new ValidationError of the given model with
+// no errors, whose fields are validated with Check.
+func NewValidationError(model string) *ValidationError {
+	return &ValidationError{Model: model}
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Error()
+	}
+	return fmt.Sprintf("kallax: invalid %s: %s", e.Model, strings.Join(msgs, ", "))
+}
+
+// Check adds an error for each of the given rules not satisfied by the given
+// value of the given field. No rule is checked after a RequiredRule not
+// satisfied or an OmitEmptyRule if the value is empty, and only RequiredRule is
+// checked if the value is a nil pointer.
+func (e *ValidationError) Check(field string, value interface{}, rules ...ValidationRule) {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	isNil := !v.IsValid() || v.Kind() == reflect.Ptr
+
+	for _, r := range rules {
+		var name string
+		if r, ok := r.(*rule); ok {
+			name = r.name
+		}
+
+		switch name {
+		case "required":
+			if isNil || isEmptyValue(v) {
+				e.Fields = append(e.Fields, &FieldError{Field: field, Rule: r.String()})
+				return
+			}
+			continue
+		case "omitempty":
+			if isNil || isEmptyValue(v) {
+				return
+			}
+			continue
+		}
+
+		if isNil {
+			return
+		}
+
+		if !r.Valid(v.Interface()) {
+			e.Fields = append(e.Fields, &FieldError{Field: field, Rule: r.String()})
+		}
+	}
+}
+
+// Err returns the error if any rule is not satisfied, or nil otherwise.
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
@@ -0,0 +1,67 @@
+package kallax
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationRules(t *testing.T) {
+	cases := []struct {
+		rule  ValidationRule
+		value interface{}
+		valid bool
+	}{
+		{MinRule(2), 2, true},
+		{MinRule(2), uint8(1), false},
+		{MinRule(0.5), 0.25, false},
+		{MinRule(2), "ñu", true},
+		{MinRule(2), []int{1}, false},
+		{MaxRule(2), "foo", false},
+		{MaxRule(2), map[string]int{"a": 1}, true},
+		{MaxRule(2), time.Now(), false},
+		{LenRule(3), [3]int{}, true},
+		{LenRule(3), "fo", false},
+		{LenRule(3), 3, false},
+		{EmailRule(), "foo@bar.baz", true},
+		{EmailRule(), "Foo <foo@bar.baz>", false},
+		{EmailRule(), "foo", false},
+		{URLRule(), "https://example.com/foo", true},
+		{URLRule(), "example.com", false},
+		{URLRule(), "/foo", false},
+		{OneOfRule("1", "2"), int64(2), true},
+		{OneOfRule("foo", "bar"), "baz", false},
+		{RequiredRule(), time.Time{}, false},
+	}
+
+	for _, c := range cases {
+		require.Equal(t, c.valid, c.rule.Valid(c.value), "%s %v", c.rule, c.value)
+	}
+
+	require.Equal(t, "max=0.5", MaxRule(0.5).String())
+	require.Equal(t, "oneof=foo bar", OneOfRule("foo", "bar").String())
+}
+
+func TestValidationError_Check(t *testing.T) {
+	r := require.New(t)
+	str := func(s string) *string { return &s }
+
+	errs := NewValidationError("User")
+	errs.Check("Name", "", RequiredRule(), MinRule(3))
+	errs.Check("Nick", "fo", MinRule(3), MaxRule(1))
+	errs.Check("Email", (*string)(nil), EmailRule())
+	errs.Check("Website", str(""), OmitEmptyRule(), URLRule())
+	errs.Check("Bio", (*string)(nil), RequiredRule())
+	errs.Check("Role", str("admin"), RequiredRule(), OneOfRule("admin"))
+
+	r.Equal([]*FieldError{
+		{Field: "Name", Rule: "required"},
+		{Field: "Nick", Rule: "min=3"},
+		{Field: "Nick", Rule: "max=1"},
+		{Field: "Bio", Rule: "required"},
+	}, errs.Fields)
+	r.EqualError(errs.Err(), "kallax: invalid User: field Name does not satisfy required, field Nick does not satisfy min=3, field Nick does not satisfy max=1, field Bio does not satisfy required")
+
+	r.NoError(NewValidationError("User").Err())
+}
@@ -0,0 +1,107 @@
+package kallax
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Vector is an embedding stored in a column of the vector type of the
+// pgvector extension. The number of dimensions of the column can be set with
+// the struct tag `dims`, and an index for nearest neighbor searches with the
+// struct tag `index`, e.g. `dims:"1536" index:"hnsw,cosine"`.
+type Vector []float32
+
+// String returns the vector in the format used by pgvector, e.g. `[1,2,3]`.
+func (v Vector) String() string {
+	var buf bytes.Buffer
+	buf.WriteRune('[')
+	for i, f := range v {
+		if i > 0 {
+			buf.WriteRune(',')
+		}
+		buf.WriteString(strconv.FormatFloat(float64(f), 'g', -1, 32))
+	}
+	buf.WriteRune(']')
+	return buf.String()
+}
+
+// Scan implements the sql.Scanner interface.
+func (v *Vector) Scan(src interface{}) error {
+	switch t := src.(type) {
+	case []byte:
+		return v.Scan(string(t))
+	case string:
+		t = strings.TrimSpace(t)
+		if !strings.HasPrefix(t, "[") || !strings.HasSuffix(t, "]") {
+			return fmt.Errorf("kallax: invalid vector %q", t)
+		}
+
+		t = strings.TrimSpace(t[1 : len(t)-1])
+		if t == "" {
+			*v = Vector{}
+			return nil
+		}
+
+		parts := strings.Split(t, ",")
+		vec := make(Vector, len(parts))
+		for i, p := range parts {
+			f, err := strconv.ParseFloat(strings.TrimSpace(p), 32)
+			if err != nil {
+				return fmt.Errorf("kallax: invalid vector element %q: %s", p, err)
+			}
+			vec[i] = float32(f)
+		}
+
+		*v = vec
+		return nil
+	case nil:
+		*v = nil
+		return nil
+	}
+	return fmt.Errorf("kallax: cannot scan type %s into Vector type", reflect.TypeOf(src))
+}
+
+// Value implements the driver.Valuer interface.
+func (v Vector) Value() (driver.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return v.String(), nil
+}
+
+type vectorOrder struct {
+	op  string
+	col SchemaField
+	vec Vector
+}
+
+// ToSql returns the SQL representation of the column with its order.
+func (o *vectorOrder) ToSql(schema Schema) string {
+	return fmt.Sprintf("%s %s '%s'::vector ASC", o.col.QualifiedName(schema), o.op, o.vec)
+}
+
+func (vectorOrder) isColumnOrder() {}
+
+// NearestTo returns a column order that sorts the results by the euclidean
+// (L2) distance between the vector in `col` and the given vector, nearest
+// first.
+func NearestTo(col SchemaField, v Vector) ColumnOrder {
+	return &vectorOrder{"<->", col, v}
+}
+
+// NearestToCosine returns a column order that sorts the results by the cosine
+// distance between the vector in `col` and the given vector, nearest first.
+func NearestToCosine(col SchemaField, v Vector) ColumnOrder {
+	return &vectorOrder{"<=>", col, v}
+}
+
+// NearestToInnerProduct returns a column order that sorts the results by the
+// negative inner product between the vector in `col` and the given vector,
+// nearest first.
+func NearestToInnerProduct(col SchemaField, v Vector) ColumnOrder {
+	return &vectorOrder{"<#>", col, v}
+}
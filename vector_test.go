@@ -0,0 +1,62 @@
+package kallax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVector_Scan(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected Vector
+	}{
+		{"[1,2,3]", Vector{1, 2, 3}},
+		{"[0.5, -1.25]", Vector{.5, -1.25}},
+		{"[]", Vector{}},
+	}
+
+	for _, c := range cases {
+		var v Vector
+		require.NoError(t, v.Scan([]byte(c.input)), c.input)
+		require.Equal(t, c.expected, v, c.input)
+	}
+
+	var v = Vector{1}
+	require.NoError(t, v.Scan(nil))
+	require.Nil(t, v)
+}
+
+func TestVector_ScanInvalid(t *testing.T) {
+	cases := []interface{}{
+		"1,2,3",
+		"{1,2,3}",
+		"[1,a]",
+		int64(1),
+	}
+
+	for _, c := range cases {
+		var v Vector
+		require.Error(t, v.Scan(c), "%v", c)
+	}
+}
+
+func TestVector_Value(t *testing.T) {
+	v, err := Vector{1, .5, -2}.Value()
+	require.NoError(t, err)
+	require.Equal(t, "[1,0.5,-2]", v)
+
+	v, err = Vector(nil).Value()
+	require.NoError(t, err)
+	require.Nil(t, v)
+}
+
+func TestVectorOrders(t *testing.T) {
+	col := f("embedding")
+	schema := NewBaseSchema("docs", "__docs", f("id"), nil, nil, false, f("id"), col)
+	vec := Vector{1, .5}
+
+	require.Equal(t, "__docs.embedding <-> '[1,0.5]'::vector ASC", NearestTo(col, vec).ToSql(schema))
+	require.Equal(t, "__docs.embedding <=> '[1,0.5]'::vector ASC", NearestToCosine(col, vec).ToSql(schema))
+	require.Equal(t, "__docs.embedding <#> '[1,0.5]'::vector ASC", NearestToInnerProduct(col, vec).ToSql(schema))
+}
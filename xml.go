@@ -0,0 +1,76 @@
+package kallax
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// XML is an XML document or content fragment stored in a xml column. XML
+// values are checked to be well-formed when they are scanned and converted to
+// driver values, so malformed XML is never stored. An empty XML is valid.
+type XML string
+
+// NewXML returns the XML encoding of the given value using encoding/xml.
+func NewXML(v interface{}) (XML, error) {
+	bytes, err := xml.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return XML(bytes), nil
+}
+
+// Unmarshal decodes the XML into the given value using encoding/xml.
+func (x XML) Unmarshal(v interface{}) error {
+	return xml.Unmarshal([]byte(x), v)
+}
+
+// Validate returns an error if the XML is not well-formed.
+func (x XML) Validate() error {
+	d := xml.NewDecoder(bytes.NewBufferString(string(x)))
+	d.Strict = true
+	for {
+		_, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("kallax: invalid XML: %s", err)
+		}
+	}
+}
+
+// Scan implements the sql.Scanner interface.
+func (x *XML) Scan(src interface{}) error {
+	var v XML
+	switch t := src.(type) {
+	case []byte:
+		v = XML(t)
+	case string:
+		v = XML(t)
+	case nil:
+		*x = ""
+		return nil
+	default:
+		return fmt.Errorf("kallax: cannot scan type %s into XML type", reflect.TypeOf(src))
+	}
+
+	if err := v.Validate(); err != nil {
+		return err
+	}
+
+	*x = v
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (x XML) Value() (driver.Value, error) {
+	if err := x.Validate(); err != nil {
+		return nil, err
+	}
+	return string(x), nil
+}
@@ -0,0 +1,73 @@
+package kallax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type xmlNote struct {
+	To   string `xml:"to"`
+	Body string `xml:"body"`
+}
+
+func TestXML(t *testing.T) {
+	r := require.New(t)
+	x, err := NewXML(xmlNote{"Tove", "Hi!"})
+	r.NoError(err)
+	r.Equal(XML("<xmlNote><to>Tove</to><body>Hi!</body></xmlNote>"), x)
+
+	var note xmlNote
+	r.NoError(x.Unmarshal(&note))
+	r.Equal(xmlNote{"Tove", "Hi!"}, note)
+}
+
+func TestXML_Validate(t *testing.T) {
+	r := require.New(t)
+	for _, valid := range []XML{"", "<a/>", "<a><b>foo</b></a>", "<a/><b/>", `<?xml version="1.0"?><a x="1"/>`} {
+		r.NoError(valid.Validate(), string(valid))
+	}
+
+	for _, invalid := range []XML{"<a>", "<a></b>", "<a x=1/>", "</a>"} {
+		r.Error(invalid.Validate(), string(invalid))
+	}
+}
+
+func TestXML_Scan(t *testing.T) {
+	r := require.New(t)
+	var x XML
+	r.NoError(x.Scan([]byte("<a/>")))
+	r.Equal(XML("<a/>"), x)
+
+	r.NoError(x.Scan("<b/>"))
+	r.Equal(XML("<b/>"), x)
+
+	r.Error(x.Scan("<a>"))
+	r.Equal(XML("<b/>"), x)
+
+	r.NoError(x.Scan(nil))
+	r.Equal(XML(""), x)
+
+	r.Error(x.Scan(int64(1)))
+}
+
+func TestXML_Value(t *testing.T) {
+	r := require.New(t)
+	v, err := XML("<a/>").Value()
+	r.NoError(err)
+	r.Equal("<a/>", v)
+
+	_, err = XML("<a>").Value()
+	r.Error(err)
+}
+
+func TestXPathExists(t *testing.T) {
+	r := require.New(t)
+	col := f("payload")
+	schema := NewBaseSchema("messages", "__messages", f("id"), nil, nil, false, f("id"), col)
+
+	sql, args, err := XPathExists(col, "/order/item[@sku]")(schema).ToSql()
+	r.NoError(err)
+	r.Equal("xpath_exists(?, __messages.payload)", sql)
+	r.Equal([]interface{}{"/order/item[@sku]"}, args)
+}